@@ -0,0 +1,226 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessgrant
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// ManagedLabel marks role bindings created and reaped by Dashboard.
+	ManagedLabel = "dashboard.kubernetes.io/access-grant"
+	// ExpiresAtAnnotation holds the RFC 3339 time after which the role binding is deleted.
+	ExpiresAtAnnotation = "dashboard.kubernetes.io/access-grant-expires-at"
+	// GrantedByAnnotation holds the identity of the user that granted the access.
+	GrantedByAnnotation = "dashboard.kubernetes.io/access-grant-granted-by"
+
+	// DefaultRole is the cluster role granted when none is given.
+	DefaultRole = "view"
+)
+
+// AccessGrantSpec is a request to grant the subject access to namespaces for limited time.
+type AccessGrantSpec struct {
+	// Subject that gets the access. Kind is User, Group or ServiceAccount.
+	Subject rbac.Subject `json:"subject"`
+	// Namespaces the subject gets access to.
+	Namespaces []string `json:"namespaces"`
+	// Role is the name of the cluster role granted in each namespace. Defaults to 'view'.
+	Role string `json:"role"`
+	// Duration of the access in seconds.
+	Duration int64 `json:"duration"`
+}
+
+// AccessGrant is a single managed role binding.
+type AccessGrant struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+	Subject    rbac.Subject   `json:"subject"`
+	Role       string         `json:"role"`
+	GrantedBy  string         `json:"grantedBy"`
+	ExpiresAt  metaV1.Time    `json:"expiresAt"`
+	Expired    bool           `json:"expired"`
+}
+
+// AccessGrantList contains a list of access grants.
+type AccessGrantList struct {
+	ListMeta api.ListMeta  `json:"listMeta"`
+	Items    []AccessGrant `json:"items"`
+}
+
+// CreateAccessGrants creates a managed role binding in each namespace of the spec. Role bindings are created with
+// credentials of the granting user, so the apiserver only lets them grant roles they are allowed to bind. Either all
+// role bindings are created or none.
+func CreateAccessGrants(client kubernetes.Interface, spec *AccessGrantSpec, grantedBy string,
+	now time.Time) (*AccessGrantList, error) {
+	if err := validate(spec); err != nil {
+		return nil, err
+	}
+
+	role := spec.Role
+	if len(role) == 0 {
+		role = DefaultRole
+	}
+
+	subject := spec.Subject
+	if subject.Kind != rbac.ServiceAccountKind {
+		subject.APIGroup = rbac.GroupName
+	}
+
+	expiresAt := now.Add(time.Duration(spec.Duration) * time.Second).UTC()
+	created := make([]rbac.RoleBinding, 0, len(spec.Namespaces))
+	for _, namespace := range spec.Namespaces {
+		binding := &rbac.RoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{
+				GenerateName: "dashboard-access-grant-",
+				Namespace:    namespace,
+				Labels:       map[string]string{ManagedLabel: "true"},
+				Annotations: map[string]string{
+					ExpiresAtAnnotation: expiresAt.Format(time.RFC3339),
+					GrantedByAnnotation: grantedBy,
+				},
+			},
+			Subjects: []rbac.Subject{subject},
+			RoleRef:  rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: role},
+		}
+
+		result, err := client.RbacV1().RoleBindings(namespace).Create(context.TODO(), binding, metaV1.CreateOptions{})
+		if err != nil {
+			for _, binding := range created {
+				_ = client.RbacV1().RoleBindings(binding.Namespace).Delete(context.TODO(), binding.Name,
+					metaV1.DeleteOptions{})
+			}
+			return nil, err
+		}
+		created = append(created, *result)
+	}
+
+	return toAccessGrantList(created, now), nil
+}
+
+// GetAccessGrantList returns managed role bindings in given namespace, or in all namespaces if it is empty.
+func GetAccessGrantList(client kubernetes.Interface, namespace string, now time.Time) (*AccessGrantList, error) {
+	bindings, err := listManaged(client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAccessGrantList(bindings, now), nil
+}
+
+// DeleteAccessGrant revokes access grant of given name before it expires. Only managed role bindings are deleted.
+func DeleteAccessGrant(client kubernetes.Interface, namespace, name string) error {
+	binding, err := client.RbacV1().RoleBindings(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if binding.Labels[ManagedLabel] != "true" {
+		return errors.NewInvalid(fmt.Sprintf("role binding %s/%s is not an access grant", namespace, name))
+	}
+
+	return client.RbacV1().RoleBindings(namespace).Delete(context.TODO(), name, metaV1.DeleteOptions{})
+}
+
+func validate(spec *AccessGrantSpec) error {
+	switch spec.Subject.Kind {
+	case rbac.UserKind, rbac.GroupKind:
+	case rbac.ServiceAccountKind:
+		if len(spec.Subject.Namespace) == 0 {
+			return errors.NewBadRequest("namespace of the service account is required")
+		}
+	default:
+		return errors.NewBadRequest(fmt.Sprintf("subject kind must be one of %s, %s, %s", rbac.UserKind,
+			rbac.GroupKind, rbac.ServiceAccountKind))
+	}
+
+	if len(strings.TrimSpace(spec.Subject.Name)) == 0 {
+		return errors.NewBadRequest("subject name is required")
+	}
+
+	if len(spec.Namespaces) == 0 {
+		return errors.NewBadRequest("at least one namespace is required")
+	}
+
+	if spec.Duration <= 0 {
+		return errors.NewBadRequest("duration must be greater than 0")
+	}
+
+	return nil
+}
+
+func listManaged(client kubernetes.Interface, namespace string) ([]rbac.RoleBinding, error) {
+	selector := labels.SelectorFromSet(labels.Set{ManagedLabel: "true"}).String()
+	list, err := client.RbacV1().RoleBindings(namespace).List(context.TODO(), metaV1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// getExpiresAt returns the expiry of managed role binding. Role bindings with missing or malformed annotation
+// expire immediately, so that access can not be kept by editing it.
+func getExpiresAt(binding rbac.RoleBinding) time.Time {
+	expiresAt, err := time.Parse(time.RFC3339, binding.Annotations[ExpiresAtAnnotation])
+	if err != nil {
+		return time.Time{}
+	}
+
+	return expiresAt
+}
+
+func toAccessGrantList(bindings []rbac.RoleBinding, now time.Time) *AccessGrantList {
+	result := &AccessGrantList{
+		ListMeta: api.ListMeta{TotalItems: len(bindings)},
+		Items:    make([]AccessGrant, 0, len(bindings)),
+	}
+
+	for _, binding := range bindings {
+		result.Items = append(result.Items, toAccessGrant(binding, now))
+	}
+
+	return result
+}
+
+func toAccessGrant(binding rbac.RoleBinding, now time.Time) AccessGrant {
+	grant := AccessGrant{
+		ObjectMeta: api.NewObjectMeta(binding.ObjectMeta),
+		TypeMeta:   api.NewTypeMeta(api.ResourceKindRoleBinding),
+		Role:       binding.RoleRef.Name,
+		GrantedBy:  binding.Annotations[GrantedByAnnotation],
+	}
+
+	if len(binding.Subjects) > 0 {
+		grant.Subject = binding.Subjects[0]
+	}
+
+	expiresAt := getExpiresAt(binding)
+	grant.ExpiresAt = metaV1.NewTime(expiresAt)
+	grant.Expired = !now.Before(expiresAt)
+	return grant
+}
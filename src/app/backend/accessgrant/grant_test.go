@@ -0,0 +1,168 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessgrant
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newManagedRoleBinding(namespace, name, expiresAt string) *rbac.RoleBinding {
+	return &rbac.RoleBinding{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      map[string]string{ManagedLabel: "true"},
+			Annotations: map[string]string{ExpiresAtAnnotation: expiresAt},
+		},
+	}
+}
+
+func TestCreateAccessGrants(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := fake.NewSimpleClientset()
+	spec := &AccessGrantSpec{
+		Subject:    rbac.Subject{Kind: rbac.UserKind, Name: "jane"},
+		Namespaces: []string{"dev", "test"},
+		Duration:   3600,
+	}
+
+	result, err := CreateAccessGrants(client, spec, "admin", now)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v.", err)
+	}
+
+	if result.ListMeta.TotalItems != 2 {
+		t.Fatalf("Expected 2 access grants, but got %d.", result.ListMeta.TotalItems)
+	}
+
+	for _, namespace := range spec.Namespaces {
+		list, err := client.RbacV1().RoleBindings(namespace).List(context.TODO(), metaV1.ListOptions{})
+		if err != nil || len(list.Items) != 1 {
+			t.Fatalf("Expected a single role binding in %s, but got %v (%v).", namespace, list, err)
+		}
+
+		binding := list.Items[0]
+		expectedSubjects := []rbac.Subject{{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "jane"}}
+		if !reflect.DeepEqual(binding.Subjects, expectedSubjects) {
+			t.Errorf("Expected subjects %v, but got %v.", expectedSubjects, binding.Subjects)
+		}
+
+		if binding.RoleRef.Kind != "ClusterRole" || binding.RoleRef.Name != DefaultRole {
+			t.Errorf("Expected binding to cluster role %s, but got %v.", DefaultRole, binding.RoleRef)
+		}
+
+		expectedAnnotations := map[string]string{
+			ExpiresAtAnnotation: "2020-01-01T13:00:00Z",
+			GrantedByAnnotation: "admin",
+		}
+		if binding.Labels[ManagedLabel] != "true" || !reflect.DeepEqual(binding.Annotations, expectedAnnotations) {
+			t.Errorf("Expected managed role binding with annotations %v, but got %v.", expectedAnnotations,
+				binding.ObjectMeta)
+		}
+	}
+}
+
+func TestCreateAccessGrantsValidation(t *testing.T) {
+	cases := []struct {
+		info string
+		spec *AccessGrantSpec
+	}{
+		{
+			"unknown subject kind",
+			&AccessGrantSpec{Subject: rbac.Subject{Kind: "Robot", Name: "r2"}, Namespaces: []string{"dev"},
+				Duration: 60},
+		},
+		{
+			"service account without namespace",
+			&AccessGrantSpec{Subject: rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "ci"},
+				Namespaces: []string{"dev"}, Duration: 60},
+		},
+		{
+			"no namespaces",
+			&AccessGrantSpec{Subject: rbac.Subject{Kind: rbac.GroupKind, Name: "devs"}, Duration: 60},
+		},
+		{
+			"no duration",
+			&AccessGrantSpec{Subject: rbac.Subject{Kind: rbac.UserKind, Name: "jane"}, Namespaces: []string{"dev"}},
+		},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset()
+		if _, err := CreateAccessGrants(client, c.spec, "admin", time.Now()); err == nil {
+			t.Errorf("Test Case: %s. Expected error, but got none.", c.info)
+		}
+
+		if len(client.Actions()) > 0 {
+			t.Errorf("Test Case: %s. Expected no calls to the apiserver, but got %v.", c.info, client.Actions())
+		}
+	}
+}
+
+func TestReapExpiredAccessGrants(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	unmanaged := &rbac.RoleBinding{ObjectMeta: metaV1.ObjectMeta{Name: "unmanaged", Namespace: "dev"}}
+	client := fake.NewSimpleClientset(
+		newManagedRoleBinding("dev", "expired", "2020-01-01T11:00:00Z"),
+		newManagedRoleBinding("test", "malformed", "tomorrow"),
+		newManagedRoleBinding("dev", "active", "2020-01-01T13:00:00Z"),
+		unmanaged,
+	)
+
+	reaped, err := ReapExpiredAccessGrants(client, now)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v.", err)
+	}
+
+	if reaped != 2 {
+		t.Errorf("Expected 2 reaped access grants, but got %d.", reaped)
+	}
+
+	list, _ := client.RbacV1().RoleBindings(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	names := make([]string, 0)
+	for _, binding := range list.Items {
+		names = append(names, binding.Name)
+	}
+
+	expected := []string{"active", "unmanaged"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected remaining role bindings %v, but got %v.", expected, names)
+	}
+}
+
+func TestDeleteAccessGrant(t *testing.T) {
+	unmanaged := &rbac.RoleBinding{ObjectMeta: metaV1.ObjectMeta{Name: "unmanaged", Namespace: "dev"}}
+	client := fake.NewSimpleClientset(newManagedRoleBinding("dev", "grant", "2020-01-01T11:00:00Z"), unmanaged)
+
+	if err := DeleteAccessGrant(client, "dev", "unmanaged"); err == nil {
+		t.Error("Expected error when deleting unmanaged role binding, but got none.")
+	}
+
+	if err := DeleteAccessGrant(client, "dev", "grant"); err != nil {
+		t.Errorf("Expected no error, but got %v.", err)
+	}
+
+	list, _ := client.RbacV1().RoleBindings("dev").List(context.TODO(), metaV1.ListOptions{})
+	if len(list.Items) != 1 || list.Items[0].Name != "unmanaged" {
+		t.Errorf("Expected only unmanaged role binding to remain, but got %v.", list.Items)
+	}
+}
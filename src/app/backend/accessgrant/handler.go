@@ -0,0 +1,151 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessgrant
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbac "k8s.io/api/rbac/v1"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Handler manages endpoints related to delegated access grants.
+type Handler struct {
+	cManager clientapi.ClientManager
+}
+
+// Install creates new endpoints for access grants.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/accessgrant").
+			To(h.handleGetAccessGrantList).
+			Writes(AccessGrantList{}))
+	ws.Route(
+		ws.GET("/accessgrant/{namespace}").
+			To(h.handleGetAccessGrantList).
+			Writes(AccessGrantList{}))
+	ws.Route(
+		ws.POST("/accessgrant").
+			To(h.handleCreateAccessGrants).
+			Reads(AccessGrantSpec{}).
+			Writes(AccessGrantList{}))
+	ws.Route(
+		ws.DELETE("/accessgrant/{namespace}/{name}").
+			To(h.handleDeleteAccessGrant))
+}
+
+// NewAccessGrantHandler creates accessgrant.Handler.
+func NewAccessGrantHandler(cManager clientapi.ClientManager) *Handler {
+	return &Handler{cManager: cManager}
+}
+
+func (h *Handler) handleGetAccessGrantList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := GetAccessGrantList(k8sClient, request.PathParameter("namespace"), time.Now())
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// handleCreateAccessGrants grants access to all namespaces of the spec. Only authenticated users that can create
+// role bindings in every namespace can grant access.
+func (h *Handler) handleCreateAccessGrants(request *restful.Request, response *restful.Response) {
+	spec := new(AccessGrantSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	for _, namespace := range spec.Namespaces {
+		if !h.cManager.CanI(request, ToRoleBindingAccessReview(namespace, "create")) {
+			errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+				fmt.Sprintf("only users that can create role bindings in namespace %s can grant access to it",
+					namespace)))
+			return
+		}
+	}
+
+	cfg, err := h.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := CreateAccessGrants(k8sClient, spec, clientapi.GetIdentity(cfg), time.Now())
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+// handleDeleteAccessGrant revokes access grant before it expires.
+func (h *Handler) handleDeleteAccessGrant(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	if !h.cManager.CanI(request, ToRoleBindingAccessReview(namespace, "delete")) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			fmt.Sprintf("only users that can delete role bindings in namespace %s can revoke access to it", namespace)))
+		return
+	}
+
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if err := DeleteAccessGrant(k8sClient, namespace, request.PathParameter("name")); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+// ToRoleBindingAccessReview creates kubernetes API object checking whether the user can use given verb on role
+// bindings in given namespace.
+func ToRoleBindingAccessReview(namespace, verb string) *authorizationv1.SelfSubjectAccessReview {
+	return &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Group:     rbac.GroupName,
+				Resource:  "rolebindings",
+				Verb:      verb,
+			},
+		},
+	}
+}
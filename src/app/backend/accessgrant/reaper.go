@@ -0,0 +1,64 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessgrant
+
+import (
+	"context"
+	"log"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ReapExpiredAccessGrants deletes managed role bindings in all namespaces that expired before given time and
+// returns how many were deleted.
+func ReapExpiredAccessGrants(client kubernetes.Interface, now time.Time) (int, error) {
+	bindings, err := listManaged(client, metaV1.NamespaceAll)
+	if err != nil {
+		return 0, err
+	}
+
+	reaped := 0
+	for _, binding := range bindings {
+		if now.Before(getExpiresAt(binding)) {
+			continue
+		}
+
+		err := client.RbacV1().RoleBindings(binding.Namespace).Delete(context.TODO(), binding.Name,
+			metaV1.DeleteOptions{})
+		if err != nil {
+			log.Printf("Cannot delete expired access grant %s/%s: %s", binding.Namespace, binding.Name, err.Error())
+			continue
+		}
+
+		log.Printf("Deleted expired access grant %s/%s", binding.Namespace, binding.Name)
+		reaped++
+	}
+
+	return reaped, nil
+}
+
+// StartReaper periodically deletes expired access grants with given client. It must be allowed to list and delete
+// role bindings in all namespaces.
+func StartReaper(client kubernetes.Interface, period time.Duration) {
+	go func() {
+		for range time.Tick(period) {
+			if _, err := ReapExpiredAccessGrants(client, time.Now()); err != nil {
+				log.Printf("Cannot reap expired access grants: %s", err.Error())
+			}
+		}
+	}()
+}
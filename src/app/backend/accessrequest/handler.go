@@ -0,0 +1,308 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessrequest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/kubernetes/dashboard/src/app/backend/accessgrant"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/stream"
+)
+
+// heartbeatInterval is the interval of comments sent to keep the watch open through proxies.
+const heartbeatInterval = 25 * time.Second
+
+// Handler manages endpoints of the access request workflow. Access requests are stored and decisions are recorded
+// with privileges of Dashboard, so that users without any access can request it. Dashboard service account has to
+// be able to manage access requests and create events and token reviews. It should be the only one allowed to
+// create access requests, since approvers trust the user recorded in them.
+type Handler struct {
+	cManager clientapi.ClientManager
+}
+
+// Install creates new endpoints for access requests.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/accessrequest").
+			To(h.handleGetAccessRequestList).
+			Writes(AccessRequestList{}))
+	ws.Route(
+		ws.GET("/accessrequest/watch").
+			To(h.handleWatchAccessRequests).
+			Writes(AccessRequest{}).
+			Metadata(stream.RouteMetadata, true))
+	ws.Route(
+		ws.POST("/accessrequest/{namespace}").
+			To(h.handleCreateAccessRequest).
+			Reads(AccessRequestSpec{}).
+			Writes(AccessRequest{}))
+	ws.Route(
+		ws.POST("/accessrequest/{namespace}/{name}/approve").
+			To(h.handleApproveAccessRequest).
+			Writes(AccessRequest{}))
+	ws.Route(
+		ws.POST("/accessrequest/{namespace}/{name}/deny").
+			To(h.handleDenyAccessRequest).
+			Reads(DecisionSpec{}).
+			Writes(AccessRequest{}))
+}
+
+// NewAccessRequestHandler creates accessrequest.Handler.
+func NewAccessRequestHandler(cManager clientapi.ClientManager) *Handler {
+	return &Handler{cManager: cManager}
+}
+
+// handleGetAccessRequestList returns requests of the user and requests the user can decide about.
+func (h *Handler) handleGetAccessRequestList(request *restful.Request, response *restful.Response) {
+	user, err := h.getUser(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	requests, err := GetAccessRequestList(h.cManager.InsecureDynamicClient(), metaV1.NamespaceAll)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	canApprove := h.newApprovalChecker(request)
+	result := &AccessRequestList{Items: make([]AccessRequest, 0)}
+	for _, item := range requests {
+		if item.Spec.User == user || canApprove(item.Namespace) {
+			result.Items = append(result.Items, item)
+		}
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// handleWatchAccessRequests streams server-sent 'accessrequest' events with pending requests the user can decide
+// about, and with decisions about requests of the user.
+func (h *Handler) handleWatchAccessRequests(request *restful.Request, response *restful.Response) {
+	user, err := h.getUser(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	ctx := request.Request.Context()
+	watcher, err := h.cManager.InsecureDynamicClient().Resource(Resource).Namespace(metaV1.NamespaceAll).
+		Watch(ctx, metaV1.ListOptions{})
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	defer watcher.Stop()
+
+	stream.StartEvents(response)
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	canApprove := h.newApprovalChecker(request)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			stream.WriteHeartbeat(response)
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+
+			if event.Type == watch.Error {
+				stream.WriteEvent(response, "error", k8serrors.FromObject(event.Object).Error())
+				return
+			}
+
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok || (event.Type != watch.Added && event.Type != watch.Modified) {
+				continue
+			}
+
+			item, err := fromUnstructured(obj)
+			if err != nil {
+				log.Printf("Could not read access request %s/%s: %s", obj.GetNamespace(), obj.GetName(), err)
+				continue
+			}
+
+			pending := item.Status.Phase == PhasePending
+			if (pending && item.Spec.User != user && canApprove(item.Namespace)) ||
+				(!pending && item.Spec.User == user) {
+				stream.WriteEvent(response, "accessrequest", item)
+			}
+		}
+	}
+}
+
+func (h *Handler) handleCreateAccessRequest(request *restful.Request, response *restful.Response) {
+	user, err := h.getUser(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	spec := new(AccessRequestSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	result, err := CreateAccessRequest(h.cManager.InsecureDynamicClient(), request.PathParameter("namespace"),
+		user, spec)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	h.recordEvent(result, "Requested", fmt.Sprintf("%s requested %s access for %ds: %s", user, result.Spec.Role,
+		result.Spec.Duration, result.Spec.Reason))
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+func (h *Handler) handleApproveAccessRequest(request *restful.Request, response *restful.Response) {
+	approver, namespace, ok := h.getApprover(request, response)
+	if !ok {
+		return
+	}
+
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := ApproveAccessRequest(h.cManager.InsecureDynamicClient(), k8sClient, namespace,
+		request.PathParameter("name"), approver, time.Now())
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	h.recordEvent(result, "Approved", fmt.Sprintf("%s approved the request, access granted by %s until %s",
+		approver, result.Status.Grant, result.Status.ExpiresAt.UTC().Format(time.RFC3339)))
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (h *Handler) handleDenyAccessRequest(request *restful.Request, response *restful.Response) {
+	approver, namespace, ok := h.getApprover(request, response)
+	if !ok {
+		return
+	}
+
+	spec := new(DecisionSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	result, err := DenyAccessRequest(h.cManager.InsecureDynamicClient(), namespace, request.PathParameter("name"),
+		approver, spec.Message, time.Now())
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	h.recordEvent(result, "Denied", fmt.Sprintf("%s denied the request: %s", approver, spec.Message))
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// getApprover returns the user if it can decide about requests in the namespace of the request, otherwise it
+// writes an error.
+func (h *Handler) getApprover(request *restful.Request, response *restful.Response) (string, string, bool) {
+	approver, err := h.getUser(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return "", "", false
+	}
+
+	namespace := request.PathParameter("namespace")
+	if !h.cManager.CanI(request, accessgrant.ToRoleBindingAccessReview(namespace, "create")) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			fmt.Sprintf("only users that can create role bindings in namespace %s can decide about access "+
+				"requests to it", namespace)))
+		return "", "", false
+	}
+
+	return approver, namespace, true
+}
+
+// newApprovalChecker returns a function checking whether the user can decide about requests in a namespace.
+// Results are remembered, so that every namespace is reviewed only once.
+func (h *Handler) newApprovalChecker(request *restful.Request) func(namespace string) bool {
+	allowed := make(map[string]bool)
+	return func(namespace string) bool {
+		if result, ok := allowed[namespace]; ok {
+			return result
+		}
+
+		allowed[namespace] = h.cManager.CanI(request, accessgrant.ToRoleBindingAccessReview(namespace, "create"))
+		return allowed[namespace]
+	}
+}
+
+// getUser returns the name of the logged in user. Users that are not logged in can not use access requests, since
+// access would be granted to anyone. Tokens are resolved with a token review.
+func (h *Handler) getUser(request *restful.Request) (string, error) {
+	if _, err := h.cManager.ClientCmdConfig(request); err != nil {
+		return "", err
+	}
+
+	cfg, err := h.cManager.Config(request)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case len(cfg.Impersonate.UserName) > 0:
+		return cfg.Impersonate.UserName, nil
+	case len(cfg.Username) > 0:
+		return cfg.Username, nil
+	case len(cfg.BearerToken) > 0:
+		review, err := h.cManager.InsecureClient().AuthenticationV1().TokenReviews().Create(context.TODO(),
+			&authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: cfg.BearerToken}},
+			metaV1.CreateOptions{})
+		if err != nil {
+			return "", err
+		}
+
+		if review.Status.Authenticated && len(review.Status.User.Username) > 0 {
+			return review.Status.User.Username, nil
+		}
+	}
+
+	return "", errors.NewUnauthorized("access requests can only be used by logged in users")
+}
+
+func (h *Handler) recordEvent(request *AccessRequest, reason, message string) {
+	if err := RecordEvent(h.cManager.InsecureClient(), request, reason, message); err != nil {
+		log.Printf("Could not record %s event of access request %s/%s: %s", reason, request.Namespace,
+			request.Name, err)
+	}
+}
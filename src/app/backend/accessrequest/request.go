@@ -0,0 +1,300 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessrequest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/accessgrant"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Phase of an access request.
+type Phase string
+
+const (
+	// PhasePending means that the request waits for a decision of an approver.
+	PhasePending Phase = "Pending"
+	// PhaseApproved means that the access has been granted.
+	PhaseApproved Phase = "Approved"
+	// PhaseDenied means that an approver refused the request.
+	PhaseDenied Phase = "Denied"
+)
+
+// Kind of the access request custom resource.
+const Kind = "AccessRequest"
+
+// Resource is the access request custom resource, see aio/test-resources/accessrequest-crd.yml.
+var Resource = schema.GroupVersionResource{Group: "dashboard.k8s.io", Version: "v1alpha1", Resource: "accessrequests"}
+
+// AccessRequestSpec is what the user asks for. Access is requested to the namespace of the access request.
+type AccessRequestSpec struct {
+	// User that gets the access. It is always the user that created the request.
+	User string `json:"user"`
+	// Role is the name of the cluster role granted in the namespace. Defaults to 'view'.
+	Role string `json:"role"`
+	// Duration of the access in seconds.
+	Duration int64 `json:"duration"`
+	// Reason shown to approvers.
+	Reason string `json:"reason"`
+}
+
+// AccessRequestStatus is the decision about the request.
+type AccessRequestStatus struct {
+	Phase Phase `json:"phase"`
+	// Identity of the approver that decided about the request.
+	DecidedBy    string       `json:"decidedBy,omitempty"`
+	DecisionTime *metaV1.Time `json:"decisionTime,omitempty"`
+	Message      string       `json:"message,omitempty"`
+	// Name of the access grant created on approval.
+	Grant string `json:"grant,omitempty"`
+	// Time when the granted access expires.
+	ExpiresAt *metaV1.Time `json:"expiresAt,omitempty"`
+}
+
+// AccessRequest is a request of a user for time-limited access to a namespace.
+type AccessRequest struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata"`
+
+	Spec   AccessRequestSpec   `json:"spec"`
+	Status AccessRequestStatus `json:"status"`
+}
+
+// AccessRequestList contains a list of access requests.
+type AccessRequestList struct {
+	Items []AccessRequest `json:"items"`
+}
+
+// DecisionSpec is sent by approvers when denying a request.
+type DecisionSpec struct {
+	Message string `json:"message"`
+}
+
+// CreateAccessRequest creates a pending access request of given user in given namespace.
+func CreateAccessRequest(client dynamic.Interface, namespace, user string,
+	spec *AccessRequestSpec) (*AccessRequest, error) {
+	if len(strings.TrimSpace(spec.Reason)) == 0 {
+		return nil, errors.NewBadRequest("reason of the request is required")
+	}
+
+	if spec.Duration <= 0 {
+		return nil, errors.NewBadRequest("duration must be greater than 0")
+	}
+
+	request := &AccessRequest{
+		TypeMeta:   metaV1.TypeMeta{APIVersion: Resource.GroupVersion().String(), Kind: Kind},
+		ObjectMeta: metaV1.ObjectMeta{GenerateName: "access-request-", Namespace: namespace},
+		Spec:       *spec,
+		Status:     AccessRequestStatus{Phase: PhasePending},
+	}
+	request.Spec.User = user
+	if len(request.Spec.Role) == 0 {
+		request.Spec.Role = accessgrant.DefaultRole
+	}
+
+	obj, err := toUnstructured(request)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err = client.Resource(Resource).Namespace(namespace).Create(context.TODO(), obj, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromUnstructured(obj)
+}
+
+// GetAccessRequestList returns access requests in given namespace, or in all namespaces if it is empty.
+func GetAccessRequestList(client dynamic.Interface, namespace string) ([]AccessRequest, error) {
+	list, err := client.Resource(Resource).Namespace(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]AccessRequest, 0, len(list.Items))
+	for i := range list.Items {
+		request, err := fromUnstructured(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *request)
+	}
+
+	return result, nil
+}
+
+// GetAccessRequest returns a single access request.
+func GetAccessRequest(client dynamic.Interface, namespace, name string) (*AccessRequest, error) {
+	obj, err := client.Resource(Resource).Namespace(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromUnstructured(obj)
+}
+
+// ApproveAccessRequest grants the requested access with the client of the approver, so the apiserver only lets
+// approvers grant roles they are allowed to bind. Users can not approve their own requests.
+func ApproveAccessRequest(client dynamic.Interface, approverClient kubernetes.Interface, namespace, name,
+	approver string, now time.Time) (*AccessRequest, error) {
+	request, err := getPendingAccessRequest(client, namespace, name, approver)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := accessgrant.CreateAccessGrants(approverClient, &accessgrant.AccessGrantSpec{
+		Subject:    rbac.Subject{Kind: rbac.UserKind, Name: request.Spec.User},
+		Namespaces: []string{namespace},
+		Role:       request.Spec.Role,
+		Duration:   request.Spec.Duration,
+	}, approver, now)
+	if err != nil {
+		return nil, err
+	}
+
+	grant := grants.Items[0]
+	request.Status = AccessRequestStatus{
+		Phase:        PhaseApproved,
+		DecidedBy:    approver,
+		DecisionTime: &metaV1.Time{Time: now},
+		Grant:        grant.ObjectMeta.Name,
+		ExpiresAt:    &grant.ExpiresAt,
+	}
+
+	result, err := update(client, request)
+	if err != nil {
+		// Request has been decided concurrently or can not be recorded, so the access is not granted.
+		_ = accessgrant.DeleteAccessGrant(approverClient, namespace, grant.ObjectMeta.Name)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DenyAccessRequest refuses pending access request.
+func DenyAccessRequest(client dynamic.Interface, namespace, name, approver, message string,
+	now time.Time) (*AccessRequest, error) {
+	request, err := getPendingAccessRequest(client, namespace, name, approver)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Status = AccessRequestStatus{
+		Phase:        PhaseDenied,
+		DecidedBy:    approver,
+		DecisionTime: &metaV1.Time{Time: now},
+		Message:      message,
+	}
+
+	return update(client, request)
+}
+
+// RecordEvent creates an event about the access request, so that its history can be audited together with
+// other events of the namespace.
+func RecordEvent(client kubernetes.Interface, request *AccessRequest, reason, message string) error {
+	now := metaV1.Now()
+	event := &v1.Event{
+		ObjectMeta: metaV1.ObjectMeta{GenerateName: request.Name + ".", Namespace: request.Namespace},
+		InvolvedObject: v1.ObjectReference{
+			APIVersion:      Resource.GroupVersion().String(),
+			Kind:            Kind,
+			Namespace:       request.Namespace,
+			Name:            request.Name,
+			UID:             request.UID,
+			ResourceVersion: request.ResourceVersion,
+		},
+		Reason:         reason,
+		Message:        message,
+		Source:         v1.EventSource{Component: "kubernetes-dashboard"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           v1.EventTypeNormal,
+	}
+
+	_, err := client.CoreV1().Events(request.Namespace).Create(context.TODO(), event, metaV1.CreateOptions{})
+	return err
+}
+
+func getPendingAccessRequest(client dynamic.Interface, namespace, name, approver string) (*AccessRequest, error) {
+	request, err := GetAccessRequest(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status.Phase != PhasePending {
+		return nil, errors.NewInvalid(fmt.Sprintf("access request %s/%s has already been %s", namespace, name,
+			strings.ToLower(string(request.Status.Phase))))
+	}
+
+	if request.Spec.User == approver {
+		return nil, errors.NewGenericResponse(http.StatusForbidden, "users can not decide about their own access requests")
+	}
+
+	return request, nil
+}
+
+// update stores the request. Resource version of the request is kept, so concurrent decisions conflict.
+func update(client dynamic.Interface, request *AccessRequest) (*AccessRequest, error) {
+	obj, err := toUnstructured(request)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err = client.Resource(Resource).Namespace(request.Namespace).Update(context.TODO(), obj,
+		metaV1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromUnstructured(obj)
+}
+
+func toUnstructured(request *AccessRequest) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+func fromUnstructured(obj *unstructured.Unstructured) (*AccessRequest, error) {
+	request := new(AccessRequest)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, request); err != nil {
+		return nil, err
+	}
+
+	if len(request.Status.Phase) == 0 {
+		request.Status.Phase = PhasePending
+	}
+
+	return request, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accessrequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/accessgrant"
+)
+
+func newDynamicClient(t *testing.T, requests ...*AccessRequest) *dynamicfake.FakeDynamicClient {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(schema.GroupVersionKind{Group: Resource.Group, Version: Resource.Version,
+		Kind: Kind + "List"}, &unstructured.UnstructuredList{})
+
+	objects := make([]runtime.Object, 0)
+	for _, request := range requests {
+		obj, err := toUnstructured(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, obj)
+	}
+
+	return dynamicfake.NewSimpleDynamicClient(scheme, objects...)
+}
+
+func newPendingRequest(user string) *AccessRequest {
+	return &AccessRequest{
+		TypeMeta:   metaV1.TypeMeta{APIVersion: Resource.GroupVersion().String(), Kind: Kind},
+		ObjectMeta: metaV1.ObjectMeta{Name: "request", Namespace: "dev"},
+		Spec:       AccessRequestSpec{User: user, Role: "edit", Duration: 3600, Reason: "incident"},
+		Status:     AccessRequestStatus{Phase: PhasePending},
+	}
+}
+
+func TestCreateAccessRequest(t *testing.T) {
+	cases := []struct {
+		info        string
+		spec        *AccessRequestSpec
+		expectError bool
+	}{
+		{"no reason", &AccessRequestSpec{Duration: 60}, true},
+		{"no duration", &AccessRequestSpec{Reason: "debugging"}, true},
+		{"valid", &AccessRequestSpec{User: "admin", Duration: 60, Reason: "debugging"}, false},
+	}
+
+	for _, c := range cases {
+		result, err := CreateAccessRequest(newDynamicClient(t), "dev", "jane", c.spec)
+		if c.expectError {
+			if err == nil {
+				t.Errorf("%s: expected error, but got none", c.info)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: expected no error, but got %v", c.info, err)
+		}
+
+		if result.Namespace != "dev" || result.Spec.User != "jane" || result.Spec.Role != accessgrant.DefaultRole ||
+			result.Status.Phase != PhasePending {
+			t.Errorf("%s: expected pending request of jane for %s role in dev, but got %+v", c.info,
+				accessgrant.DefaultRole, result)
+		}
+	}
+}
+
+func TestApproveAccessRequest(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := newDynamicClient(t, newPendingRequest("jane"))
+	approverClient := fake.NewSimpleClientset()
+
+	if _, err := ApproveAccessRequest(client, approverClient, "dev", "request", "jane", now); err == nil {
+		t.Error("expected error when approving own request, but got none")
+	}
+
+	result, err := ApproveAccessRequest(client, approverClient, "dev", "request", "admin", now)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	if result.Status.Phase != PhaseApproved || result.Status.DecidedBy != "admin" ||
+		!result.Status.ExpiresAt.Time.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected request approved by admin until %s, but got %+v", now.Add(time.Hour), result.Status)
+	}
+
+	bindings, _ := approverClient.RbacV1().RoleBindings("dev").List(context.TODO(), metaV1.ListOptions{})
+	if len(bindings.Items) != 1 || bindings.Items[0].RoleRef.Name != "edit" ||
+		bindings.Items[0].Subjects[0].Kind != rbac.UserKind || bindings.Items[0].Subjects[0].Name != "jane" {
+		t.Errorf("expected jane to be granted edit role, but got %+v", bindings.Items)
+	}
+
+	stored, _ := GetAccessRequest(client, "dev", "request")
+	if stored.Status.Phase != PhaseApproved {
+		t.Errorf("expected approval to be stored, but got %+v", stored.Status)
+	}
+
+	if _, err := DenyAccessRequest(client, "dev", "request", "admin", "", now); err == nil {
+		t.Error("expected error when deciding about approved request again, but got none")
+	}
+}
+
+func TestDenyAccessRequest(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := newDynamicClient(t, newPendingRequest("jane"))
+
+	result, err := DenyAccessRequest(client, "dev", "request", "admin", "not on call", now)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	if result.Status.Phase != PhaseDenied || result.Status.Message != "not on call" ||
+		len(result.Status.Grant) > 0 {
+		t.Errorf("expected request to be denied without grant, but got %+v", result.Status)
+	}
+
+	list, err := GetAccessRequestList(client, metaV1.NamespaceAll)
+	if err != nil || len(list) != 1 || list[0].Status.Phase != PhaseDenied {
+		t.Errorf("expected single denied request in the list, but got %+v, %v", list, err)
+	}
+}
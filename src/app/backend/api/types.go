@@ -125,6 +125,7 @@ const (
 	ResourceKindCronJob                  = "cronjob"
 	ResourceKindLimitRange               = "limitrange"
 	ResourceKindNamespace                = "namespace"
+	ResourceKindNetworkPolicy            = "networkpolicy"
 	ResourceKindNode                     = "node"
 	ResourceKindPersistentVolumeClaim    = "persistentvolumeclaim"
 	ResourceKindPersistentVolume         = "persistentvolume"
@@ -141,6 +142,7 @@ const (
 	ResourceKindClusterRoleBinding       = "clusterrolebinding"
 	ResourceKindRole                     = "role"
 	ResourceKindRoleBinding              = "rolebinding"
+	ResourceKindRoute                    = "route"
 	ResourceKindPlugin                   = "plugin"
 	ResourceKindEndpoint                 = "endpoint"
 )
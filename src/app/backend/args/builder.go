@@ -42,12 +42,366 @@ func (self *holderBuilder) SetTokenTTL(ttl int) *holderBuilder {
 	return self
 }
 
+// SetTokenMaxLifetime 'token-max-lifetime' argument of Dashboard binary.
+func (self *holderBuilder) SetTokenMaxLifetime(maxLifetime int) *holderBuilder {
+	self.holder.tokenMaxLifetime = maxLifetime
+	return self
+}
+
+// SetLoginMaxAttempts 'login-max-attempts' argument of Dashboard binary.
+func (self *holderBuilder) SetLoginMaxAttempts(attempts int) *holderBuilder {
+	self.holder.loginMaxAttempts = attempts
+	return self
+}
+
+// SetLoginLockoutDuration 'login-lockout-duration' argument of Dashboard binary.
+func (self *holderBuilder) SetLoginLockoutDuration(seconds int) *holderBuilder {
+	self.holder.loginLockoutDuration = seconds
+	return self
+}
+
+// SetLoginMaxLockoutDuration 'login-max-lockout-duration' argument of Dashboard binary.
+func (self *holderBuilder) SetLoginMaxLockoutDuration(seconds int) *holderBuilder {
+	self.holder.loginMaxLockoutDuration = seconds
+	return self
+}
+
+// SetMaxElevationDuration 'max-elevation-duration' argument of Dashboard binary.
+func (self *holderBuilder) SetMaxElevationDuration(seconds int) *holderBuilder {
+	self.holder.maxElevationDuration = seconds
+	return self
+}
+
+// SetAccessGrantReapPeriod 'access-grant-reap-period' argument of Dashboard binary.
+func (self *holderBuilder) SetAccessGrantReapPeriod(seconds int) *holderBuilder {
+	self.holder.accessGrantReapPeriod = seconds
+	return self
+}
+
 // SetMetricClientCheckPeriod 'metric-client-check-period' argument of Dashboard binary.
 func (self *holderBuilder) SetMetricClientCheckPeriod(period int) *holderBuilder {
 	self.holder.metricClientCheckPeriod = period
 	return self
 }
 
+// SetListEncoderWorkers 'list-encoder-workers' argument of Dashboard binary.
+func (self *holderBuilder) SetListEncoderWorkers(workers int) *holderBuilder {
+	self.holder.listEncoderWorkers = workers
+	return self
+}
+
+// SetMaxListItems 'max-list-items' argument of Dashboard binary.
+func (self *holderBuilder) SetMaxListItems(items int) *holderBuilder {
+	self.holder.maxListItems = items
+	return self
+}
+
+// SetListMemoryBudget 'list-memory-budget' argument of Dashboard binary.
+func (self *holderBuilder) SetListMemoryBudget(megabytes int) *holderBuilder {
+	self.holder.listMemoryBudget = megabytes
+	return self
+}
+
+// SetSlowRequestThreshold 'slow-request-threshold' argument of Dashboard binary.
+func (self *holderBuilder) SetSlowRequestThreshold(seconds int) *holderBuilder {
+	self.holder.slowRequestThreshold = seconds
+	return self
+}
+
+// SetRequestTimeout 'request-timeout' argument of Dashboard binary.
+func (self *holderBuilder) SetRequestTimeout(seconds int) *holderBuilder {
+	self.holder.requestTimeout = seconds
+	return self
+}
+
+// SetTerminalHeartbeatInterval 'terminal-heartbeat-interval' argument of Dashboard binary.
+func (self *holderBuilder) SetTerminalHeartbeatInterval(seconds int) *holderBuilder {
+	self.holder.terminalHeartbeatInterval = seconds
+	return self
+}
+
+// SetTerminalIdleTimeout 'terminal-idle-timeout' argument of Dashboard binary.
+func (self *holderBuilder) SetTerminalIdleTimeout(seconds int) *holderBuilder {
+	self.holder.terminalIdleTimeout = seconds
+	return self
+}
+
+// SetMaxRequestsInFlight 'max-requests-in-flight' argument of Dashboard binary.
+func (self *holderBuilder) SetMaxRequestsInFlight(requests int) *holderBuilder {
+	self.holder.maxRequestsInFlight = requests
+	return self
+}
+
+// SetMaxRequestsPerIdentity 'max-requests-per-identity' argument of Dashboard binary.
+func (self *holderBuilder) SetMaxRequestsPerIdentity(requests int) *holderBuilder {
+	self.holder.maxRequestsPerIdentity = requests
+	return self
+}
+
+// SetStaleCacheTTL 'stale-cache-ttl' argument of Dashboard binary.
+func (self *holderBuilder) SetStaleCacheTTL(seconds int) *holderBuilder {
+	self.holder.staleCacheTTL = seconds
+	return self
+}
+
+// SetIdempotencyKeyTTL 'idempotency-key-ttl' argument of Dashboard binary.
+func (self *holderBuilder) SetIdempotencyKeyTTL(seconds int) *holderBuilder {
+	self.holder.idempotencyKeyTTL = seconds
+	return self
+}
+
+// SetDiscoveryCacheTTL 'discovery-cache-ttl' argument of Dashboard binary.
+func (self *holderBuilder) SetDiscoveryCacheTTL(seconds int) *holderBuilder {
+	self.holder.discoveryCacheTTL = seconds
+	return self
+}
+
+// SetTokenManager 'token-manager' argument of Dashboard binary.
+func (self *holderBuilder) SetTokenManager(tokenManager string) *holderBuilder {
+	self.holder.tokenManager = tokenManager
+	return self
+}
+
+// SetFieldManager 'field-manager' argument of Dashboard binary.
+func (self *holderBuilder) SetFieldManager(fieldManager string) *holderBuilder {
+	self.holder.fieldManager = fieldManager
+	return self
+}
+
+// SetAuthAuditSink 'auth-audit-sink' argument of Dashboard binary.
+func (self *holderBuilder) SetAuthAuditSink(sink string) *holderBuilder {
+	self.holder.authAuditSink = sink
+	return self
+}
+
+// SetAuthAuditFile 'auth-audit-file' argument of Dashboard binary.
+func (self *holderBuilder) SetAuthAuditFile(path string) *holderBuilder {
+	self.holder.authAuditFile = path
+	return self
+}
+
+// SetAuthAuditEventObject 'auth-audit-event-object' argument of Dashboard binary.
+func (self *holderBuilder) SetAuthAuditEventObject(object string) *holderBuilder {
+	self.holder.authAuditEventObject = object
+	return self
+}
+
+// SetLDAPURL 'ldap-url' argument of Dashboard binary.
+func (self *holderBuilder) SetLDAPURL(url string) *holderBuilder {
+	self.holder.ldapURL = url
+	return self
+}
+
+// SetLDAPStartTLS 'ldap-start-tls' argument of Dashboard binary.
+func (self *holderBuilder) SetLDAPStartTLS(startTLS bool) *holderBuilder {
+	self.holder.ldapStartTLS = startTLS
+	return self
+}
+
+// SetLDAPInsecureSkipVerify 'ldap-insecure-skip-verify' argument of Dashboard binary.
+func (self *holderBuilder) SetLDAPInsecureSkipVerify(insecureSkipVerify bool) *holderBuilder {
+	self.holder.ldapInsecureSkipVerify = insecureSkipVerify
+	return self
+}
+
+// SetLDAPBindDN 'ldap-bind-dn' argument of Dashboard binary.
+func (self *holderBuilder) SetLDAPBindDN(bindDN string) *holderBuilder {
+	self.holder.ldapBindDN = bindDN
+	return self
+}
+
+// SetLDAPBindPassword 'ldap-bind-password' argument of Dashboard binary.
+func (self *holderBuilder) SetLDAPBindPassword(bindPassword string) *holderBuilder {
+	self.holder.ldapBindPassword = bindPassword
+	return self
+}
+
+// SetLDAPBaseDN 'ldap-base-dn' argument of Dashboard binary.
+func (self *holderBuilder) SetLDAPBaseDN(baseDN string) *holderBuilder {
+	self.holder.ldapBaseDN = baseDN
+	return self
+}
+
+// SetLDAPUserFilter 'ldap-user-filter' argument of Dashboard binary.
+func (self *holderBuilder) SetLDAPUserFilter(filter string) *holderBuilder {
+	self.holder.ldapUserFilter = filter
+	return self
+}
+
+// SetLDAPGroupFilter 'ldap-group-filter' argument of Dashboard binary.
+func (self *holderBuilder) SetLDAPGroupFilter(filter string) *holderBuilder {
+	self.holder.ldapGroupFilter = filter
+	return self
+}
+
+// SetLDAPGroupAttribute 'ldap-group-attribute' argument of Dashboard binary.
+func (self *holderBuilder) SetLDAPGroupAttribute(attribute string) *holderBuilder {
+	self.holder.ldapGroupAttribute = attribute
+	return self
+}
+
+// SetEnableWebAuthn 'enable-webauthn' argument of Dashboard binary.
+func (self *holderBuilder) SetEnableWebAuthn(enableWebAuthn bool) *holderBuilder {
+	self.holder.enableWebAuthn = enableWebAuthn
+	return self
+}
+
+// SetWebAuthnRPID 'webauthn-rp-id' argument of Dashboard binary.
+func (self *holderBuilder) SetWebAuthnRPID(rpID string) *holderBuilder {
+	self.holder.webAuthnRPID = rpID
+	return self
+}
+
+// SetWebAuthnOrigins 'webauthn-origins' argument of Dashboard binary.
+func (self *holderBuilder) SetWebAuthnOrigins(origins []string) *holderBuilder {
+	self.holder.webAuthnOrigins = origins
+	return self
+}
+
+// SetEnableTokenReview 'enable-token-review' argument of Dashboard binary.
+func (self *holderBuilder) SetEnableTokenReview(enableTokenReview bool) *holderBuilder {
+	self.holder.enableTokenReview = enableTokenReview
+	return self
+}
+
+// SetTokenReviewAudiences 'token-review-audiences' argument of Dashboard binary.
+func (self *holderBuilder) SetTokenReviewAudiences(audiences []string) *holderBuilder {
+	self.holder.tokenReviewAudiences = audiences
+	return self
+}
+
+// SetTokenReviewIssuers 'token-review-issuers' argument of Dashboard binary.
+func (self *holderBuilder) SetTokenReviewIssuers(issuers []string) *holderBuilder {
+	self.holder.tokenReviewIssuers = issuers
+	return self
+}
+
+// SetAuthProxyUserHeader 'auth-proxy-user-header' argument of Dashboard binary.
+func (self *holderBuilder) SetAuthProxyUserHeader(header string) *holderBuilder {
+	self.holder.authProxyUserHeader = header
+	return self
+}
+
+// SetAuthProxyGroupHeader 'auth-proxy-group-header' argument of Dashboard binary.
+func (self *holderBuilder) SetAuthProxyGroupHeader(header string) *holderBuilder {
+	self.holder.authProxyGroupHeader = header
+	return self
+}
+
+// SetAuthProxyTrustedCIDRs 'auth-proxy-trusted-cidrs' argument of Dashboard binary.
+func (self *holderBuilder) SetAuthProxyTrustedCIDRs(cidrs []string) *holderBuilder {
+	self.holder.authProxyTrustedCIDRs = cidrs
+	return self
+}
+
+// SetAuthProxyClientCAFile 'auth-proxy-client-ca-file' argument of Dashboard binary.
+func (self *holderBuilder) SetAuthProxyClientCAFile(file string) *holderBuilder {
+	self.holder.authProxyClientCAFile = file
+	return self
+}
+
+// SetAuthProxyAllowedNames 'auth-proxy-allowed-names' argument of Dashboard binary.
+func (self *holderBuilder) SetAuthProxyAllowedNames(names []string) *holderBuilder {
+	self.holder.authProxyAllowedNames = names
+	return self
+}
+
+// SetEncryptionKeyRotationPeriod 'encryption-key-rotation-period' argument of Dashboard binary.
+func (self *holderBuilder) SetEncryptionKeyRotationPeriod(period int) *holderBuilder {
+	self.holder.encryptionKeyRotationPeriod = period
+	return self
+}
+
+// SetEncryptionKeyHistory 'encryption-key-history' argument of Dashboard binary.
+func (self *holderBuilder) SetEncryptionKeyHistory(history int) *holderBuilder {
+	self.holder.encryptionKeyHistory = history
+	return self
+}
+
+// SetKeyStore 'key-store' argument of Dashboard binary.
+func (self *holderBuilder) SetKeyStore(keyStore string) *holderBuilder {
+	self.holder.keyStore = keyStore
+	return self
+}
+
+// SetVaultAddress 'vault-address' argument of Dashboard binary.
+func (self *holderBuilder) SetVaultAddress(vaultAddress string) *holderBuilder {
+	self.holder.vaultAddress = vaultAddress
+	return self
+}
+
+// SetVaultEngine 'vault-engine' argument of Dashboard binary.
+func (self *holderBuilder) SetVaultEngine(vaultEngine string) *holderBuilder {
+	self.holder.vaultEngine = vaultEngine
+	return self
+}
+
+// SetVaultMount 'vault-mount' argument of Dashboard binary.
+func (self *holderBuilder) SetVaultMount(vaultMount string) *holderBuilder {
+	self.holder.vaultMount = vaultMount
+	return self
+}
+
+// SetVaultKey 'vault-key' argument of Dashboard binary.
+func (self *holderBuilder) SetVaultKey(vaultKey string) *holderBuilder {
+	self.holder.vaultKey = vaultKey
+	return self
+}
+
+// SetVaultTokenFile 'vault-token-file' argument of Dashboard binary.
+func (self *holderBuilder) SetVaultTokenFile(vaultTokenFile string) *holderBuilder {
+	self.holder.vaultTokenFile = vaultTokenFile
+	return self
+}
+
+// SetVaultRole 'vault-role' argument of Dashboard binary.
+func (self *holderBuilder) SetVaultRole(vaultRole string) *holderBuilder {
+	self.holder.vaultRole = vaultRole
+	return self
+}
+
+// SetKMSProvider 'kms-provider' argument of Dashboard binary.
+func (self *holderBuilder) SetKMSProvider(kmsProvider string) *holderBuilder {
+	self.holder.kmsProvider = kmsProvider
+	return self
+}
+
+// SetKMSKey 'kms-key' argument of Dashboard binary.
+func (self *holderBuilder) SetKMSKey(kmsKey string) *holderBuilder {
+	self.holder.kmsKey = kmsKey
+	return self
+}
+
+// SetSnapshotFile 'snapshot-file' argument of Dashboard binary.
+func (self *holderBuilder) SetSnapshotFile(path string) *holderBuilder {
+	self.holder.snapshotFile = path
+	return self
+}
+
+// SetFalcoWebhookToken 'falco-webhook-token' argument of Dashboard binary.
+func (self *holderBuilder) SetFalcoWebhookToken(token string) *holderBuilder {
+	self.holder.falcoWebhookToken = token
+	return self
+}
+
+// SetImageUpdateRegistries 'image-update-registries' argument of Dashboard binary.
+func (self *holderBuilder) SetImageUpdateRegistries(registries []string) *holderBuilder {
+	self.holder.imageUpdateRegistries = registries
+	return self
+}
+
+// SetImageUpdateCacheTTL 'image-update-cache-ttl' argument of Dashboard binary.
+func (self *holderBuilder) SetImageUpdateCacheTTL(ttl int) *holderBuilder {
+	self.holder.imageUpdateCacheTTL = ttl
+	return self
+}
+
+// SetRouteTimeouts 'route-timeouts' argument of Dashboard binary.
+func (self *holderBuilder) SetRouteTimeouts(timeouts map[string]int) *holderBuilder {
+	self.holder.routeTimeouts = timeouts
+	return self
+}
+
 // SetInsecureBindAddress 'insecure-bind-address' argument of Dashboard binary.
 func (self *holderBuilder) SetInsecureBindAddress(ip net.IP) *holderBuilder {
 	self.holder.insecureBindAddress = ip
@@ -110,18 +464,36 @@ func (self *holderBuilder) SetKubeConfigFile(kubeConfigFile string) *holderBuild
 
 // SetSystemBanner 'system-banner' argument of Dashboard binary.
 func (self *holderBuilder) SetSystemBanner(systemBanner string) *holderBuilder {
+	self.holder.reloadableMux.Lock()
+	defer self.holder.reloadableMux.Unlock()
 	self.holder.systemBanner = systemBanner
 	return self
 }
 
 // SetSystemBannerSeverity 'system-banner-severity' argument of Dashboard binary.
 func (self *holderBuilder) SetSystemBannerSeverity(systemBannerSeverity string) *holderBuilder {
+	self.holder.reloadableMux.Lock()
+	defer self.holder.reloadableMux.Unlock()
 	self.holder.systemBannerSeverity = systemBannerSeverity
 	return self
 }
 
+// SetLoginNotice 'login-notice' argument of Dashboard binary.
+func (self *holderBuilder) SetLoginNotice(loginNotice string) *holderBuilder {
+	self.holder.loginNotice = loginNotice
+	return self
+}
+
+// SetLoginNoticeAcknowledgment 'login-notice-acknowledgment' argument of Dashboard binary.
+func (self *holderBuilder) SetLoginNoticeAcknowledgment(acknowledgment bool) *holderBuilder {
+	self.holder.loginNoticeAcknowledgment = acknowledgment
+	return self
+}
+
 // SetLogLevel 'api-log-level' argument of Dashboard binary.
 func (self *holderBuilder) SetAPILogLevel(apiLogLevel string) *holderBuilder {
+	self.holder.reloadableMux.Lock()
+	defer self.holder.reloadableMux.Unlock()
 	self.holder.apiLogLevel = apiLogLevel
 	return self
 }
@@ -132,6 +504,30 @@ func (self *holderBuilder) SetAuthenticationMode(authMode []string) *holderBuild
 	return self
 }
 
+// SetPublicStatusNamespaces 'public-status-namespaces' argument of Dashboard binary.
+func (self *holderBuilder) SetPublicStatusNamespaces(namespaces []string) *holderBuilder {
+	self.holder.publicStatusNamespaces = namespaces
+	return self
+}
+
+// SetProtectedResources 'protected-resources' argument of Dashboard binary.
+func (self *holderBuilder) SetProtectedResources(resources []string) *holderBuilder {
+	self.holder.protectedResources = resources
+	return self
+}
+
+// SetReadOnlyNamespaces 'read-only-namespaces' argument of Dashboard binary.
+func (self *holderBuilder) SetReadOnlyNamespaces(namespaces []string) *holderBuilder {
+	self.holder.readOnlyNamespaces = namespaces
+	return self
+}
+
+// SetKubeConfigExecPlugins 'kubeconfig-exec-plugins' argument of Dashboard binary.
+func (self *holderBuilder) SetKubeConfigExecPlugins(commands []string) *holderBuilder {
+	self.holder.kubeConfigExecPlugins = commands
+	return self
+}
+
 // SetAutoGenerateCertificates 'auto-generate-certificates' argument of Dashboard binary.
 func (self *holderBuilder) SetAutoGenerateCertificates(autoGenerateCertificates bool) *holderBuilder {
 	self.holder.autoGenerateCertificates = autoGenerateCertificates
@@ -150,12 +546,36 @@ func (self *holderBuilder) SetDisableSettingsAuthorizer(disableSettingsAuthorize
 	return self
 }
 
+// SetEnableSettingsWebhook 'enable-settings-webhook' argument of Dashboard binary.
+func (self *holderBuilder) SetEnableSettingsWebhook(enableSettingsWebhook bool) *holderBuilder {
+	self.holder.enableSettingsWebhook = enableSettingsWebhook
+	return self
+}
+
+// SetEnableTenancy 'enable-tenancy' argument of Dashboard binary.
+func (self *holderBuilder) SetEnableTenancy(enableTenancy bool) *holderBuilder {
+	self.holder.enableTenancy = enableTenancy
+	return self
+}
+
+// SetReadOnly 'read-only' argument of Dashboard binary.
+func (self *holderBuilder) SetReadOnly(readOnly bool) *holderBuilder {
+	self.holder.readOnly = readOnly
+	return self
+}
+
 // SetEnableSkipLogin 'enable-skip-login' argument of Dashboard binary.
 func (self *holderBuilder) SetEnableSkipLogin(enableSkipLogin bool) *holderBuilder {
 	self.holder.enableSkipLogin = enableSkipLogin
 	return self
 }
 
+// SetSkipAuthServiceAccount 'skip-auth-service-account' argument of Dashboard binary.
+func (self *holderBuilder) SetSkipAuthServiceAccount(name string) *holderBuilder {
+	self.holder.skipAuthServiceAccount = name
+	return self
+}
+
 // SetNamespace 'namespace' argument of Dashboard binary.
 func (self *holderBuilder) SetNamespace(namespace string) *holderBuilder {
 	self.holder.namespace = namespace
@@ -168,6 +588,20 @@ func (self *holderBuilder) SetLocaleConfig(localeConfig string) *holderBuilder {
 	return self
 }
 
+// SetFeatureGates 'feature-gates' argument of Dashboard binary.
+func (self *holderBuilder) SetFeatureGates(featureGates string) *holderBuilder {
+	self.holder.reloadableMux.Lock()
+	defer self.holder.reloadableMux.Unlock()
+	self.holder.featureGates = featureGates
+	return self
+}
+
+// SetValidateConfig 'validate-config' argument of Dashboard binary.
+func (self *holderBuilder) SetValidateConfig(validateConfig bool) *holderBuilder {
+	self.holder.validateConfig = validateConfig
+	return self
+}
+
 // GetHolderBuilder returns singleton instance of argument holder builder.
 func GetHolderBuilder() *holderBuilder {
 	return builder
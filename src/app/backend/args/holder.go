@@ -16,6 +16,7 @@ package args
 
 import (
 	"net"
+	"sync"
 
 	"github.com/kubernetes/dashboard/src/app/backend/cert/api"
 )
@@ -25,10 +26,41 @@ var Holder = &holder{}
 // Argument holder structure. It is private to make sure that only 1 instance can be created. It holds all
 // arguments values passed to Dashboard binary.
 type holder struct {
-	insecurePort            int
-	port                    int
-	tokenTTL                int
-	metricClientCheckPeriod int
+	insecurePort              int
+	port                      int
+	tokenTTL                  int
+	tokenMaxLifetime          int
+	loginMaxAttempts          int
+	loginLockoutDuration      int
+	loginMaxLockoutDuration   int
+	maxElevationDuration      int
+	accessGrantReapPeriod     int
+	metricClientCheckPeriod   int
+	listEncoderWorkers        int
+	maxListItems              int
+	listMemoryBudget          int
+	slowRequestThreshold      int
+	requestTimeout            int
+	terminalHeartbeatInterval int
+	terminalIdleTimeout       int
+	maxRequestsInFlight       int
+	maxRequestsPerIdentity    int
+	staleCacheTTL             int
+	idempotencyKeyTTL         int
+	discoveryCacheTTL         int
+
+	encryptionKeyRotationPeriod int
+	encryptionKeyHistory        int
+
+	keyStore       string
+	vaultAddress   string
+	vaultEngine    string
+	vaultMount     string
+	vaultKey       string
+	vaultTokenFile string
+	vaultRole      string
+	kmsProvider    string
+	kmsKey         string
 
 	insecureBindAddress net.IP
 	bindAddress         net.IP
@@ -45,16 +77,67 @@ type holder struct {
 	systemBannerSeverity string
 	apiLogLevel          string
 	namespace            string
-
-	authenticationMode []string
+	snapshotFile         string
+	falcoWebhookToken    string
+	tokenManager         string
+	fieldManager         string
+	authAuditSink        string
+	authAuditFile        string
+	authAuditEventObject string
+
+	loginNotice               string
+	loginNoticeAcknowledgment bool
+
+	imageUpdateRegistries []string
+	imageUpdateCacheTTL   int
+
+	ldapURL                string
+	ldapStartTLS           bool
+	ldapInsecureSkipVerify bool
+	ldapBindDN             string
+	ldapBindPassword       string
+	ldapBaseDN             string
+	ldapUserFilter         string
+	ldapGroupFilter        string
+	ldapGroupAttribute     string
+	enableWebAuthn         bool
+	webAuthnRPID           string
+	webAuthnOrigins        []string
+
+	enableTokenReview    bool
+	tokenReviewAudiences []string
+	tokenReviewIssuers   []string
+
+	authProxyUserHeader   string
+	authProxyGroupHeader  string
+	authProxyTrustedCIDRs []string
+	authProxyClientCAFile string
+	authProxyAllowedNames []string
+
+	authenticationMode     []string
+	publicStatusNamespaces []string
+	kubeConfigExecPlugins  []string
+	protectedResources     []string
+	readOnlyNamespaces     []string
+
+	routeTimeouts map[string]int
 
 	autoGenerateCertificates  bool
 	enableInsecureLogin       bool
 	disableSettingsAuthorizer bool
+	enableSettingsWebhook     bool
+	enableTenancy             bool
+	readOnly                  bool
+	validateConfig            bool
 
-	enableSkipLogin bool
+	enableSkipLogin        bool
+	skipAuthServiceAccount string
 
 	localeConfig string
+	featureGates string
+
+	// Guards arguments that can be reloaded from the config file while Dashboard is running.
+	reloadableMux sync.RWMutex
 }
 
 // GetInsecurePort 'insecure-port' argument of Dashboard binary.
@@ -72,11 +155,306 @@ func (self *holder) GetTokenTTL() int {
 	return self.tokenTTL
 }
 
+// GetTokenMaxLifetime 'token-max-lifetime' argument of Dashboard binary.
+func (self *holder) GetTokenMaxLifetime() int {
+	return self.tokenMaxLifetime
+}
+
+// GetLoginMaxAttempts 'login-max-attempts' argument of Dashboard binary.
+func (self *holder) GetLoginMaxAttempts() int {
+	return self.loginMaxAttempts
+}
+
+// GetLoginLockoutDuration 'login-lockout-duration' argument of Dashboard binary.
+func (self *holder) GetLoginLockoutDuration() int {
+	return self.loginLockoutDuration
+}
+
+// GetLoginMaxLockoutDuration 'login-max-lockout-duration' argument of Dashboard binary.
+func (self *holder) GetLoginMaxLockoutDuration() int {
+	return self.loginMaxLockoutDuration
+}
+
+// GetMaxElevationDuration 'max-elevation-duration' argument of Dashboard binary.
+func (self *holder) GetMaxElevationDuration() int {
+	return self.maxElevationDuration
+}
+
+// GetAccessGrantReapPeriod 'access-grant-reap-period' argument of Dashboard binary.
+func (self *holder) GetAccessGrantReapPeriod() int {
+	return self.accessGrantReapPeriod
+}
+
 // GetMetricClientCheckPeriod 'metric-client-check-period' argument of Dashboard binary.
 func (self *holder) GetMetricClientCheckPeriod() int {
 	return self.metricClientCheckPeriod
 }
 
+// GetListEncoderWorkers 'list-encoder-workers' argument of Dashboard binary.
+func (self *holder) GetListEncoderWorkers() int {
+	return self.listEncoderWorkers
+}
+
+// GetMaxListItems 'max-list-items' argument of Dashboard binary.
+func (self *holder) GetMaxListItems() int {
+	return self.maxListItems
+}
+
+// GetListMemoryBudget 'list-memory-budget' argument of Dashboard binary.
+func (self *holder) GetListMemoryBudget() int {
+	return self.listMemoryBudget
+}
+
+// GetSlowRequestThreshold 'slow-request-threshold' argument of Dashboard binary.
+func (self *holder) GetSlowRequestThreshold() int {
+	return self.slowRequestThreshold
+}
+
+// GetRequestTimeout 'request-timeout' argument of Dashboard binary.
+func (self *holder) GetRequestTimeout() int {
+	return self.requestTimeout
+}
+
+// GetTerminalHeartbeatInterval 'terminal-heartbeat-interval' argument of Dashboard binary.
+func (self *holder) GetTerminalHeartbeatInterval() int {
+	return self.terminalHeartbeatInterval
+}
+
+// GetTerminalIdleTimeout 'terminal-idle-timeout' argument of Dashboard binary.
+func (self *holder) GetTerminalIdleTimeout() int {
+	return self.terminalIdleTimeout
+}
+
+// GetMaxRequestsInFlight 'max-requests-in-flight' argument of Dashboard binary.
+func (self *holder) GetMaxRequestsInFlight() int {
+	return self.maxRequestsInFlight
+}
+
+// GetMaxRequestsPerIdentity 'max-requests-per-identity' argument of Dashboard binary.
+func (self *holder) GetMaxRequestsPerIdentity() int {
+	return self.maxRequestsPerIdentity
+}
+
+// GetStaleCacheTTL 'stale-cache-ttl' argument of Dashboard binary.
+func (self *holder) GetStaleCacheTTL() int {
+	return self.staleCacheTTL
+}
+
+// GetIdempotencyKeyTTL 'idempotency-key-ttl' argument of Dashboard binary.
+func (self *holder) GetIdempotencyKeyTTL() int {
+	return self.idempotencyKeyTTL
+}
+
+// GetDiscoveryCacheTTL 'discovery-cache-ttl' argument of Dashboard binary.
+func (self *holder) GetDiscoveryCacheTTL() int {
+	return self.discoveryCacheTTL
+}
+
+// GetTokenManager 'token-manager' argument of Dashboard binary.
+func (self *holder) GetTokenManager() string {
+	return self.tokenManager
+}
+
+// GetFieldManager 'field-manager' argument of Dashboard binary.
+func (self *holder) GetFieldManager() string {
+	return self.fieldManager
+}
+
+// GetAuthAuditSink 'auth-audit-sink' argument of Dashboard binary.
+func (self *holder) GetAuthAuditSink() string {
+	return self.authAuditSink
+}
+
+// GetAuthAuditFile 'auth-audit-file' argument of Dashboard binary.
+func (self *holder) GetAuthAuditFile() string {
+	return self.authAuditFile
+}
+
+// GetAuthAuditEventObject 'auth-audit-event-object' argument of Dashboard binary.
+func (self *holder) GetAuthAuditEventObject() string {
+	return self.authAuditEventObject
+}
+
+// GetLDAPURL 'ldap-url' argument of Dashboard binary.
+func (self *holder) GetLDAPURL() string {
+	return self.ldapURL
+}
+
+// GetLDAPStartTLS 'ldap-start-tls' argument of Dashboard binary.
+func (self *holder) GetLDAPStartTLS() bool {
+	return self.ldapStartTLS
+}
+
+// GetLDAPInsecureSkipVerify 'ldap-insecure-skip-verify' argument of Dashboard binary.
+func (self *holder) GetLDAPInsecureSkipVerify() bool {
+	return self.ldapInsecureSkipVerify
+}
+
+// GetLDAPBindDN 'ldap-bind-dn' argument of Dashboard binary.
+func (self *holder) GetLDAPBindDN() string {
+	return self.ldapBindDN
+}
+
+// GetLDAPBindPassword 'ldap-bind-password' argument of Dashboard binary.
+func (self *holder) GetLDAPBindPassword() string {
+	return self.ldapBindPassword
+}
+
+// GetLDAPBaseDN 'ldap-base-dn' argument of Dashboard binary.
+func (self *holder) GetLDAPBaseDN() string {
+	return self.ldapBaseDN
+}
+
+// GetLDAPUserFilter 'ldap-user-filter' argument of Dashboard binary.
+func (self *holder) GetLDAPUserFilter() string {
+	return self.ldapUserFilter
+}
+
+// GetLDAPGroupFilter 'ldap-group-filter' argument of Dashboard binary.
+func (self *holder) GetLDAPGroupFilter() string {
+	return self.ldapGroupFilter
+}
+
+// GetLDAPGroupAttribute 'ldap-group-attribute' argument of Dashboard binary.
+func (self *holder) GetLDAPGroupAttribute() string {
+	return self.ldapGroupAttribute
+}
+
+// GetEnableWebAuthn 'enable-webauthn' argument of Dashboard binary.
+func (self *holder) GetEnableWebAuthn() bool {
+	return self.enableWebAuthn
+}
+
+// GetWebAuthnRPID 'webauthn-rp-id' argument of Dashboard binary.
+func (self *holder) GetWebAuthnRPID() string {
+	return self.webAuthnRPID
+}
+
+// GetWebAuthnOrigins 'webauthn-origins' argument of Dashboard binary.
+func (self *holder) GetWebAuthnOrigins() []string {
+	return self.webAuthnOrigins
+}
+
+// GetEnableTokenReview 'enable-token-review' argument of Dashboard binary.
+func (self *holder) GetEnableTokenReview() bool {
+	return self.enableTokenReview
+}
+
+// GetTokenReviewAudiences 'token-review-audiences' argument of Dashboard binary.
+func (self *holder) GetTokenReviewAudiences() []string {
+	return self.tokenReviewAudiences
+}
+
+// GetTokenReviewIssuers 'token-review-issuers' argument of Dashboard binary.
+func (self *holder) GetTokenReviewIssuers() []string {
+	return self.tokenReviewIssuers
+}
+
+// GetAuthProxyUserHeader 'auth-proxy-user-header' argument of Dashboard binary.
+func (self *holder) GetAuthProxyUserHeader() string {
+	return self.authProxyUserHeader
+}
+
+// GetAuthProxyGroupHeader 'auth-proxy-group-header' argument of Dashboard binary.
+func (self *holder) GetAuthProxyGroupHeader() string {
+	return self.authProxyGroupHeader
+}
+
+// GetAuthProxyTrustedCIDRs 'auth-proxy-trusted-cidrs' argument of Dashboard binary.
+func (self *holder) GetAuthProxyTrustedCIDRs() []string {
+	return self.authProxyTrustedCIDRs
+}
+
+// GetAuthProxyClientCAFile 'auth-proxy-client-ca-file' argument of Dashboard binary.
+func (self *holder) GetAuthProxyClientCAFile() string {
+	return self.authProxyClientCAFile
+}
+
+// GetAuthProxyAllowedNames 'auth-proxy-allowed-names' argument of Dashboard binary.
+func (self *holder) GetAuthProxyAllowedNames() []string {
+	return self.authProxyAllowedNames
+}
+
+// GetEncryptionKeyRotationPeriod 'encryption-key-rotation-period' argument of Dashboard binary.
+func (self *holder) GetEncryptionKeyRotationPeriod() int {
+	return self.encryptionKeyRotationPeriod
+}
+
+// GetEncryptionKeyHistory 'encryption-key-history' argument of Dashboard binary.
+func (self *holder) GetEncryptionKeyHistory() int {
+	return self.encryptionKeyHistory
+}
+
+// GetKeyStore 'key-store' argument of Dashboard binary.
+func (self *holder) GetKeyStore() string {
+	return self.keyStore
+}
+
+// GetVaultAddress 'vault-address' argument of Dashboard binary.
+func (self *holder) GetVaultAddress() string {
+	return self.vaultAddress
+}
+
+// GetVaultEngine 'vault-engine' argument of Dashboard binary.
+func (self *holder) GetVaultEngine() string {
+	return self.vaultEngine
+}
+
+// GetVaultMount 'vault-mount' argument of Dashboard binary.
+func (self *holder) GetVaultMount() string {
+	return self.vaultMount
+}
+
+// GetVaultKey 'vault-key' argument of Dashboard binary.
+func (self *holder) GetVaultKey() string {
+	return self.vaultKey
+}
+
+// GetVaultTokenFile 'vault-token-file' argument of Dashboard binary.
+func (self *holder) GetVaultTokenFile() string {
+	return self.vaultTokenFile
+}
+
+// GetVaultRole 'vault-role' argument of Dashboard binary.
+func (self *holder) GetVaultRole() string {
+	return self.vaultRole
+}
+
+// GetKMSProvider 'kms-provider' argument of Dashboard binary.
+func (self *holder) GetKMSProvider() string {
+	return self.kmsProvider
+}
+
+// GetKMSKey 'kms-key' argument of Dashboard binary.
+func (self *holder) GetKMSKey() string {
+	return self.kmsKey
+}
+
+// GetSnapshotFile 'snapshot-file' argument of Dashboard binary.
+func (self *holder) GetSnapshotFile() string {
+	return self.snapshotFile
+}
+
+// GetFalcoWebhookToken 'falco-webhook-token' argument of Dashboard binary.
+func (self *holder) GetFalcoWebhookToken() string {
+	return self.falcoWebhookToken
+}
+
+// GetImageUpdateRegistries 'image-update-registries' argument of Dashboard binary.
+func (self *holder) GetImageUpdateRegistries() []string {
+	return self.imageUpdateRegistries
+}
+
+// GetImageUpdateCacheTTL 'image-update-cache-ttl' argument of Dashboard binary.
+func (self *holder) GetImageUpdateCacheTTL() int {
+	return self.imageUpdateCacheTTL
+}
+
+// GetRouteTimeouts 'route-timeouts' argument of Dashboard binary.
+func (self *holder) GetRouteTimeouts() map[string]int {
+	return self.routeTimeouts
+}
+
 // GetInsecureBindAddress 'insecure-bind-address' argument of Dashboard binary.
 func (self *holder) GetInsecureBindAddress() net.IP {
 	return self.insecureBindAddress
@@ -137,16 +515,32 @@ func (self *holder) GetKubeConfigFile() string {
 
 // GetSystemBanner 'system-banner' argument of Dashboard binary.
 func (self *holder) GetSystemBanner() string {
+	self.reloadableMux.RLock()
+	defer self.reloadableMux.RUnlock()
 	return self.systemBanner
 }
 
 // GetSystemBannerSeverity 'system-banner-severity' argument of Dashboard binary.
 func (self *holder) GetSystemBannerSeverity() string {
+	self.reloadableMux.RLock()
+	defer self.reloadableMux.RUnlock()
 	return self.systemBannerSeverity
 }
 
+// GetLoginNotice 'login-notice' argument of Dashboard binary.
+func (self *holder) GetLoginNotice() string {
+	return self.loginNotice
+}
+
+// GetLoginNoticeAcknowledgment 'login-notice-acknowledgment' argument of Dashboard binary.
+func (self *holder) GetLoginNoticeAcknowledgment() bool {
+	return self.loginNoticeAcknowledgment
+}
+
 // LogLevel 'api-log-level' argument of Dashboard binary.
 func (self *holder) GetAPILogLevel() string {
+	self.reloadableMux.RLock()
+	defer self.reloadableMux.RUnlock()
 	return self.apiLogLevel
 }
 
@@ -155,6 +549,26 @@ func (self *holder) GetAuthenticationMode() []string {
 	return self.authenticationMode
 }
 
+// GetPublicStatusNamespaces 'public-status-namespaces' argument of Dashboard binary.
+func (self *holder) GetPublicStatusNamespaces() []string {
+	return self.publicStatusNamespaces
+}
+
+// GetProtectedResources 'protected-resources' argument of Dashboard binary.
+func (self *holder) GetProtectedResources() []string {
+	return self.protectedResources
+}
+
+// GetReadOnlyNamespaces 'read-only-namespaces' argument of Dashboard binary.
+func (self *holder) GetReadOnlyNamespaces() []string {
+	return self.readOnlyNamespaces
+}
+
+// GetKubeConfigExecPlugins 'kubeconfig-exec-plugins' argument of Dashboard binary.
+func (self *holder) GetKubeConfigExecPlugins() []string {
+	return self.kubeConfigExecPlugins
+}
+
 // GetAutoGenerateCertificates 'auto-generate-certificates' argument of Dashboard binary.
 func (self *holder) GetAutoGenerateCertificates() bool {
 	return self.autoGenerateCertificates
@@ -170,11 +584,31 @@ func (self *holder) GetDisableSettingsAuthorizer() bool {
 	return self.disableSettingsAuthorizer
 }
 
+// GetEnableSettingsWebhook 'enable-settings-webhook' argument of Dashboard binary.
+func (self *holder) GetEnableSettingsWebhook() bool {
+	return self.enableSettingsWebhook
+}
+
+// GetEnableTenancy 'enable-tenancy' argument of Dashboard binary.
+func (self *holder) GetEnableTenancy() bool {
+	return self.enableTenancy
+}
+
+// GetReadOnly 'read-only' argument of Dashboard binary.
+func (self *holder) GetReadOnly() bool {
+	return self.readOnly
+}
+
 // GetEnableSkipLogin 'enable-skip-login' argument of Dashboard binary.
 func (self *holder) GetEnableSkipLogin() bool {
 	return self.enableSkipLogin
 }
 
+// GetSkipAuthServiceAccount 'skip-auth-service-account' argument of Dashboard binary.
+func (self *holder) GetSkipAuthServiceAccount() string {
+	return self.skipAuthServiceAccount
+}
+
 // GetNamespace 'namespace' argument of Dashboard binary.
 func (self *holder) GetNamespace() string {
 	return self.namespace
@@ -184,3 +618,15 @@ func (self *holder) GetNamespace() string {
 func (self *holder) GetLocaleConfig() string {
 	return self.localeConfig
 }
+
+// GetFeatureGates 'feature-gates' argument of Dashboard binary.
+func (self *holder) GetFeatureGates() string {
+	self.reloadableMux.RLock()
+	defer self.reloadableMux.RUnlock()
+	return self.featureGates
+}
+
+// GetValidateConfig 'validate-config' argument of Dashboard binary.
+func (self *holder) GetValidateConfig() bool {
+	return self.validateConfig
+}
@@ -15,7 +15,13 @@
 package api
 
 import (
+	"net"
+	"net/http"
+	"path"
 	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/kubernetes/dashboard/src/app/backend/args"
 )
@@ -25,7 +31,7 @@ func ToAuthenticationModes(modes []string) AuthenticationModes {
 	result := AuthenticationModes{}
 	modesMap := map[string]bool{}
 
-	for _, mode := range []AuthenticationMode{Token, Basic} {
+	for _, mode := range []AuthenticationMode{Token, Basic, LDAP, Header} {
 		modesMap[mode.String()] = true
 	}
 
@@ -40,8 +46,13 @@ func ToAuthenticationModes(modes []string) AuthenticationModes {
 
 // List of protected resources that should be filtered out from dashboard UI.
 var protectedResources = []ProtectedResource{
-	{EncryptionKeyHolderName, args.Holder.GetNamespace()},
-	{CertificateHolderSecretName, args.Holder.GetNamespace()},
+	{ResourceName: EncryptionKeyHolderName, ResourceNamespace: args.Holder.GetNamespace()},
+	{ResourceName: CertificateHolderSecretName, ResourceNamespace: args.Holder.GetNamespace()},
+	{ResourceName: RevokedTokensHolderName, ResourceNamespace: args.Holder.GetNamespace()},
+	{ResourceName: SessionsHolderName, ResourceNamespace: args.Holder.GetNamespace()},
+	{ResourceName: ClientStateHolderName, ResourceNamespace: args.Holder.GetNamespace()},
+	{ResourceName: WebAuthnCredentialsHolderName, ResourceNamespace: args.Holder.GetNamespace()},
+	{ResourceName: OperationsHolderName, ResourceNamespace: args.Holder.GetNamespace()},
 }
 
 // ShouldRejectRequest returns true if url contains name and namespace of resource that should be filtered out from
@@ -55,3 +66,100 @@ func ShouldRejectRequest(url string) bool {
 
 	return false
 }
+
+// NewAuditRecord creates AuditRecord of given event caused by given request.
+func NewAuditRecord(event AuditEvent, subject string, request *http.Request) *AuditRecord {
+	return &AuditRecord{
+		Timestamp: time.Now(),
+		Event:     event,
+		Subject:   subject,
+		SourceIP:  GetSourceIP(request),
+		UserAgent: request.UserAgent(),
+	}
+}
+
+// GetSourceIP returns IP address the request came from. It is taken from the connection, because forward headers
+// are set by clients unless a proxy overwrites them.
+func GetSourceIP(request *http.Request) string {
+	ip, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+
+	return ip
+}
+
+// embedSubresources are read-only subresources that can be served with embed tokens, i.e.
+// /api/v1/pod/<namespace>/<name>/event. Others, like shell or secret data, are never allowed.
+var embedSubresources = map[string]bool{
+	"event":                 true,
+	"pod":                   true,
+	"container":             true,
+	"service":               true,
+	"job":                   true,
+	"persistentvolume":      true,
+	"persistentvolumeclaim": true,
+	"newreplicaset":         true,
+	"oldreplicaset":         true,
+}
+
+// Allows returns true if request with given method and url path can be served with embed token restricted to the
+// scope. Only GET requests of lists and details, /api/v1/<kind>[/<namespace>][/<name>], and of their read-only
+// subresources listed in embedSubresources are allowed.
+func (self EmbedScope) Allows(method, urlPath string) bool {
+	const apiPrefix = "/api/v1/"
+	if method != http.MethodGet || len(self.Kind) == 0 {
+		return false
+	}
+
+	cleaned := path.Clean(urlPath)
+	if !strings.HasPrefix(cleaned, apiPrefix) {
+		return false
+	}
+
+	expected := []string{self.Kind}
+	if len(self.Namespace) > 0 {
+		expected = append(expected, self.Namespace)
+	}
+	if len(self.Name) > 0 {
+		expected = append(expected, self.Name)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(cleaned, apiPrefix), "/")
+	if len(segments) < len(expected) {
+		return false
+	}
+
+	for i, segment := range expected {
+		if segments[i] != segment {
+			return false
+		}
+	}
+
+	// Paths have at most a kind, a namespace and a name, followed by a single subresource.
+	last := segments[len(segments)-1]
+	if len(segments) > len(expected) && embedSubresources[last] {
+		return len(segments) <= 4
+	}
+
+	return len(segments) <= 3 && !embedSubresources[last]
+}
+
+// IsElevated returns true if the session is elevated at given time.
+func (self *ElevatedAuthInfo) IsElevated(now time.Time) bool {
+	return self.Elevated != nil && now.Before(self.ElevatedUntil)
+}
+
+// IsElevationExpired returns true if the session has been elevated, but the elevation has expired at given time.
+func (self *ElevatedAuthInfo) IsElevationExpired(now time.Time) bool {
+	return self.Elevated != nil && !now.Before(self.ElevatedUntil)
+}
+
+// Current returns AuthInfo that should be used at given time.
+func (self *ElevatedAuthInfo) Current(now time.Time) *api.AuthInfo {
+	if self.IsElevated(now) {
+		return self.Elevated.DeepCopy()
+	}
+
+	return self.AuthInfo.DeepCopy()
+}
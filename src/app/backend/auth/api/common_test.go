@@ -60,3 +60,41 @@ func TestShouldRejectRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestEmbedScopeAllows(t *testing.T) {
+	cases := []struct {
+		scope    EmbedScope
+		method   string
+		path     string
+		expected bool
+	}{
+		{EmbedScope{Kind: "pod", Namespace: "default", Name: "foo"}, "GET", "/api/v1/pod/default/foo", true},
+		{EmbedScope{Kind: "pod", Namespace: "default", Name: "foo"}, "GET", "/api/v1/pod/default/foo/event", true},
+		{EmbedScope{Kind: "pod", Namespace: "default", Name: "foo"}, "PUT", "/api/v1/pod/default/foo", false},
+		{EmbedScope{Kind: "pod", Namespace: "default", Name: "foo"}, "GET", "/api/v1/pod/default/bar", false},
+		{EmbedScope{Kind: "pod", Namespace: "default", Name: "foo"}, "GET", "/api/v1/pod/default", false},
+		{EmbedScope{Kind: "pod", Namespace: "default", Name: "foo"}, "GET", "/api/v1/pod/default/foo/../bar", false},
+		{EmbedScope{Kind: "pod", Namespace: "default"}, "GET", "/api/v1/pod/default", true},
+		{EmbedScope{Kind: "pod", Namespace: "default"}, "GET", "/api/v1/pod/kube-system", false},
+		{EmbedScope{Kind: "pod", Namespace: "default"}, "GET", "/api/v1/secret/default", false},
+		{EmbedScope{Kind: "node", Name: "node-1"}, "GET", "/api/v1/node/node-1/pod", true},
+		{EmbedScope{Kind: "node", Name: "node-1"}, "GET", "/pod/node-1", false},
+		{EmbedScope{}, "GET", "/api/v1/pod", false},
+		{EmbedScope{Kind: "pod"}, "GET", "/api/v1/pod/default/foo", true},
+		{EmbedScope{Kind: "pod"}, "GET", "/api/v1/pod/default/foo/event", true},
+		{EmbedScope{Kind: "pod", Namespace: "default", Name: "foo"}, "GET", "/api/v1/pod/default/foo/shell/bar", false},
+		{EmbedScope{Kind: "pod", Namespace: "default"}, "GET", "/api/v1/pod/default/foo/shell/bar", false},
+		{EmbedScope{Kind: "pod", Namespace: "default", Name: "foo"}, "GET", "/api/v1/pod/default/foo/imagepull", false},
+		{EmbedScope{Kind: "secret", Namespace: "default", Name: "foo"}, "GET", "/api/v1/secret/default/foo/data/key",
+			false},
+		{EmbedScope{Kind: "pod", Namespace: "default", Name: "foo"}, "GET", "/api/v1/pod/default/foo/event/x", false},
+	}
+
+	for _, c := range cases {
+		got := c.scope.Allows(c.method, c.path)
+		if got != c.expected {
+			t.Fatalf("Allows(): scope %+v, %s %s expected %v, but got %v", c.scope, c.method, c.path, c.expected,
+				got)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// MsgResourceProtected is returned for requests to resources protected by the configuration.
+const MsgResourceProtected = "%s %s is protected by Dashboard configuration"
+
+// ParseProtectedResource parses protected resource in the format [<kind>:][<namespace>/]<name>[@<label selector>],
+// i.e. "secret:kube-system/*" or "*/*@sensitive=true". Namespace and name are glob patterns. Resource without
+// namespace matches only non-namespaced resources.
+func ParseProtectedResource(value string) (ProtectedResource, error) {
+	result := ProtectedResource{}
+	rest := strings.TrimSpace(value)
+
+	if i := strings.Index(rest, "@"); i >= 0 {
+		selector, err := labels.Parse(rest[i+1:])
+		if err != nil {
+			return result, fmt.Errorf("invalid label selector of protected resource %s: %s", value, err.Error())
+		}
+		result.Selector = selector
+		rest = rest[:i]
+	}
+
+	if i := strings.Index(rest, ":"); i >= 0 {
+		result.ResourceKind = strings.ToLower(rest[:i])
+		rest = rest[i+1:]
+		if len(result.ResourceKind) == 0 {
+			return result, fmt.Errorf("empty kind of protected resource %s", value)
+		}
+	}
+
+	if i := strings.Index(rest, "/"); i >= 0 {
+		result.ResourceNamespace = rest[:i]
+		rest = rest[i+1:]
+	}
+	result.ResourceName = rest
+
+	if len(result.ResourceName) == 0 {
+		return result, fmt.Errorf("empty name of protected resource %s", value)
+	}
+	for _, pattern := range []string{result.ResourceNamespace, result.ResourceName} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return result, fmt.Errorf("invalid pattern %s of protected resource %s", pattern, value)
+		}
+	}
+
+	return result, nil
+}
+
+// MatchesName returns true if resource of given kind, namespace and name is protected, when its labels match the
+// selector.
+func (self ProtectedResource) MatchesName(kind, namespace, name string) bool {
+	if len(self.ResourceKind) > 0 && self.ResourceKind != kind {
+		return false
+	}
+
+	namespaceMatches, _ := path.Match(self.ResourceNamespace, namespace)
+	nameMatches, _ := path.Match(self.ResourceName, name)
+	return namespaceMatches && nameMatches
+}
+
+// Matches returns true if resource of given kind, namespace, name and labels is protected.
+func (self ProtectedResource) Matches(kind, namespace, name string, objectLabels map[string]string) bool {
+	if !self.MatchesName(kind, namespace, name) {
+		return false
+	}
+
+	return self.Selector == nil || self.Selector.Matches(labels.Set(objectLabels))
+}
+
+var (
+	configuredProtectedResources []ProtectedResource
+	protectedResourcesMux        sync.RWMutex
+)
+
+// SetProtectedResources parses protected resources configured by admins. Previously configured resources are kept if
+// any of them is invalid.
+func SetProtectedResources(values []string) error {
+	resources := make([]ProtectedResource, 0, len(values))
+	for _, value := range values {
+		resource, err := ParseProtectedResource(value)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, resource)
+	}
+
+	protectedResourcesMux.Lock()
+	defer protectedResourcesMux.Unlock()
+	configuredProtectedResources = resources
+	return nil
+}
+
+// GetProtectedResources returns protected resources configured by admins.
+func GetProtectedResources() []ProtectedResource {
+	protectedResourcesMux.RLock()
+	defer protectedResourcesMux.RUnlock()
+	return configuredProtectedResources
+}
+
+// IsProtected returns true if resource of given kind, namespace, name and labels is protected by the configuration
+// and should be hidden from users.
+func IsProtected(kind, namespace, name string, objectLabels map[string]string) bool {
+	for _, resource := range GetProtectedResources() {
+		if resource.Matches(kind, namespace, name, objectLabels) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+)
+
+func TestParseProtectedResource(t *testing.T) {
+	cases := []struct {
+		value     string
+		kind      string
+		namespace string
+		name      string
+		selector  string
+		valid     bool
+	}{
+		{"secret:kube-system/*", "secret", "kube-system", "*", "", true},
+		{"Secret:default/db-password", "secret", "default", "db-password", "", true},
+		{"*/*@sensitive=true", "", "*", "*", "sensitive=true", true},
+		{"configmap:*/*@team in (a,b),tier", "configmap", "*", "*", "team in (a,b),tier", true},
+		{"node:gpu-*", "node", "", "gpu-*", "", true},
+		{"default/", "", "default", "", "", false},
+		{":default/db", "", "default", "db", "", false},
+		{"default/[", "", "default", "[", "", false},
+		{"*/*@a=(b", "", "*", "*", "", false},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseProtectedResource(c.value)
+		if (err == nil) != c.valid {
+			t.Errorf("ParseProtectedResource(%s) returned error %v, expected valid: %t", c.value, err, c.valid)
+			continue
+		}
+		if !c.valid {
+			continue
+		}
+
+		selector := ""
+		if actual.Selector != nil {
+			selector = actual.Selector.String()
+		}
+		if actual.ResourceKind != c.kind || actual.ResourceNamespace != c.namespace || actual.ResourceName != c.name ||
+			selector != c.selector {
+			t.Errorf("ParseProtectedResource(%s) == %+v, expected %s:%s/%s@%s", c.value, actual, c.kind,
+				c.namespace, c.name, c.selector)
+		}
+	}
+}
+
+func TestIsProtected(t *testing.T) {
+	err := SetProtectedResources([]string{"secret:kube-system/*", "*/db-*", "*/*@sensitive=true", "node:gpu-*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetProtectedResources(nil)
+
+	cases := []struct {
+		kind      string
+		namespace string
+		name      string
+		labels    map[string]string
+		expected  bool
+	}{
+		{"secret", "kube-system", "token", nil, true},
+		{"configmap", "kube-system", "token", nil, false},
+		{"deployment", "default", "db-main", nil, true},
+		{"deployment", "default", "web", map[string]string{"sensitive": "true"}, true},
+		{"deployment", "default", "web", map[string]string{"sensitive": "false"}, false},
+		{"node", "", "gpu-1", nil, true},
+		{"node", "", "cpu-1", nil, false},
+		{"pod", "default", "gpu-1", nil, false},
+	}
+
+	for _, c := range cases {
+		if actual := IsProtected(c.kind, c.namespace, c.name, c.labels); actual != c.expected {
+			t.Errorf("IsProtected(%s, %s, %s, %v) == %t, expected %t", c.kind, c.namespace, c.name, c.labels,
+				actual, c.expected)
+		}
+	}
+
+	if err := SetProtectedResources([]string{"default/["}); err == nil {
+		t.Error("SetProtectedResources() returned no error for invalid pattern")
+	}
+	if len(GetProtectedResources()) != 4 {
+		t.Error("SetProtectedResources() replaced protected resources with invalid ones")
+	}
+}
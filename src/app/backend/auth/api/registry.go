@@ -0,0 +1,79 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultTokenManager is the name of the token manager used unless the 'token-manager' argument selects another one.
+const DefaultTokenManager = "jwe"
+
+// TokenManagerFactory creates a TokenManager. The client uses privileges of Dashboard and can be used to keep state
+// shared by all Dashboard replicas, i.e. encryption keys or server-side sessions.
+type TokenManagerFactory func(client kubernetes.Interface) (TokenManager, error)
+
+var tokenManagers = struct {
+	sync.RWMutex
+	factories map[string]TokenManagerFactory
+}{factories: make(map[string]TokenManagerFactory)}
+
+// RegisterTokenManager makes a token manager available under the given name. It is meant to be called from init
+// functions of packages implementing TokenManager. Registering the same name twice panics.
+func RegisterTokenManager(name string, factory TokenManagerFactory) {
+	tokenManagers.Lock()
+	defer tokenManagers.Unlock()
+
+	if factory == nil {
+		panic("token manager factory " + name + " is nil")
+	}
+
+	if _, exists := tokenManagers.factories[name]; exists {
+		panic("token manager " + name + " is already registered")
+	}
+
+	tokenManagers.factories[name] = factory
+}
+
+// TokenManagerNames returns sorted names of registered token managers.
+func TokenManagerNames() []string {
+	tokenManagers.RLock()
+	defer tokenManagers.RUnlock()
+
+	names := make([]string, 0, len(tokenManagers.factories))
+	for name := range tokenManagers.factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// NewTokenManager creates the token manager registered under the given name.
+func NewTokenManager(name string, client kubernetes.Interface) (TokenManager, error) {
+	tokenManagers.RLock()
+	factory, exists := tokenManagers.factories[name]
+	tokenManagers.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unknown token manager %q, registered token managers: %v", name, TokenManagerNames())
+	}
+
+	return factory(client)
+}
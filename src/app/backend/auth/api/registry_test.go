@@ -0,0 +1,59 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+type fakeTokenManager struct{}
+
+func (fakeTokenManager) Generate(api.AuthInfo) (string, error) { return "", nil }
+func (fakeTokenManager) Decrypt(string) (*api.AuthInfo, error) { return nil, nil }
+func (fakeTokenManager) Refresh(string) (string, error)        { return "", nil }
+func (fakeTokenManager) SetTokenTTL(time.Duration)             {}
+func (fakeTokenManager) SetTokenMaxLifetime(time.Duration)     {}
+func (fakeTokenManager) Revoke(string) error                   { return nil }
+
+func TestTokenManagerRegistry(t *testing.T) {
+	RegisterTokenManager("fake", func(kubernetes.Interface) (TokenManager, error) {
+		return fakeTokenManager{}, nil
+	})
+
+	manager, err := NewTokenManager("fake", nil)
+	if err != nil || !reflect.DeepEqual(manager, fakeTokenManager{}) {
+		t.Fatalf("NewTokenManager(): expected fake token manager, but got %v, %v", manager, err)
+	}
+
+	if _, err := NewTokenManager("unknown", nil); err == nil {
+		t.Fatal("NewTokenManager(): expected error for unknown token manager")
+	}
+
+	if names := TokenManagerNames(); !reflect.DeepEqual(names, []string{"fake"}) {
+		t.Fatalf("TokenManagerNames(): expected [fake], but got %v", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("RegisterTokenManager(): expected panic when registering the same name twice")
+		}
+	}()
+	RegisterTokenManager("fake", func(kubernetes.Interface) (TokenManager, error) { return nil, nil })
+}
@@ -17,18 +17,69 @@ package api
 import (
 	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
 )
 
 const (
 	// Resource information that are used as encryption key storage. Can be accessible by multiple dashboard replicas.
 	EncryptionKeyHolderName = "kubernetes-dashboard-key-holder"
 
+	// Resource information that are used as storage of tokens revoked before they expire. Can be accessible by multiple
+	// dashboard replicas.
+	RevokedTokensHolderName = "kubernetes-dashboard-revoked-tokens"
+
+	// Resource information that are used as storage of active sessions. Can be accessible by multiple dashboard
+	// replicas.
+	SessionsHolderName = "kubernetes-dashboard-sessions"
+
+	// Resource information that are used as storage of encrypted client state of users. Can be accessible by
+	// multiple dashboard replicas.
+	ClientStateHolderName = "kubernetes-dashboard-client-state"
+
+	// Resource information that are used as storage of WebAuthn credentials registered by users as second factor. Can
+	// be accessible by multiple dashboard replicas.
+	WebAuthnCredentialsHolderName = "kubernetes-dashboard-webauthn"
+
+	// Resource information that are used as storage of long running operations, so that any dashboard replica can
+	// report their progress.
+	OperationsHolderName = "kubernetes-dashboard-operations"
+
 	// Resource information that are used as certificate storage for custom certificates used by the user.
 	CertificateHolderSecretName = "kubernetes-dashboard-certs"
 
 	// Expiration time (in seconds) of tokens generated by dashboard. Default: 15 min.
 	DefaultTokenTTL = 900
+
+	// Expiration time (in seconds) of embed tokens if not provided in the request. Default: 1 hour.
+	DefaultEmbedTokenTTL = 3600
+
+	// Name of the query parameter used to pass embed tokens, as embedded views can not set request headers.
+	EmbedTokenParameter = "embedToken"
+
+	// Message of the error returned for mutating requests made after elevation of the session has expired.
+	MsgElevationExpired = "Elevated access has expired. Elevate the session again to make changes."
+
+	// Message of the error returned for logins that have not acknowledged the current login notice.
+	MsgLoginNoticeNotAcknowledged = "Login notice has to be acknowledged to log in."
+)
+
+// Stores of the encryption key selectable with key-store argument.
+const (
+	// Key is held by a secret in Dashboard namespace.
+	KeyStoreSecret = "secret"
+	// Key is held by Vault.
+	KeyStoreVault = "vault"
+)
+
+// Vault secret engines selectable with vault-engine argument.
+const (
+	// Key is stored in KV version 2 secret engine.
+	VaultEngineKV = "kv"
+	// Key is stored in a secret in Dashboard namespace encrypted with transit secret engine.
+	VaultEngineTransit = "transit"
 )
 
 // AuthenticationModes represents auth modes supported by dashboard.
@@ -36,10 +87,15 @@ type AuthenticationModes map[AuthenticationMode]bool
 
 // ProtectedResource represents basic information about resource that should be filtered out from Dashboard UI.
 type ProtectedResource struct {
-	// ResourceName is a name of the protected resource.
+	// ResourceName is a name of the protected resource. Configured resources can use glob patterns, i.e. "db-*".
 	ResourceName string
 	// ResourceNamespace is a namespace of the protected resource. Should be empty if resource is non-namespaced.
+	// Configured resources can use glob patterns, i.e. "*" matches all namespaces.
 	ResourceNamespace string
+	// ResourceKind limits protection to resources of the kind, i.e. "secret". Empty matches all kinds.
+	ResourceKind string
+	// Selector limits protection to resources with matching labels. Nil matches all resources.
+	Selector labels.Selector
 }
 
 // IsEnabled returns true if given auth mode is supported, false otherwise.
@@ -73,8 +129,10 @@ func (self AuthenticationMode) String() string {
 
 // Authentication modes supported by dashboard should be defined below.
 const (
-	Token AuthenticationMode = "token"
-	Basic AuthenticationMode = "basic"
+	Token  AuthenticationMode = "token"
+	Basic  AuthenticationMode = "basic"
+	LDAP   AuthenticationMode = "ldap"
+	Header AuthenticationMode = "header"
 )
 
 // AuthManager is used for user authentication management.
@@ -89,6 +147,45 @@ type AuthManager interface {
 	AuthenticationModes() []AuthenticationMode
 	// AuthenticationSkippable tells if the Skip button should be enabled or not
 	AuthenticationSkippable() bool
+	// LoginNotice returns notice shown before login.
+	LoginNotice() LoginNotice
+	// Logout revokes provided token, so it can not be used anymore even if it hasn't expired yet.
+	Logout(string) error
+	// EmbedToken takes valid token and returns a read-only token restricted to the scope from EmbedTokenSpec.
+	EmbedToken(string, *EmbedTokenSpec) (*EmbedTokenResponse, error)
+	// Elevate takes valid token and returns a token of the same session temporarily elevated with credentials
+	// from ElevationSpec.
+	Elevate(string, *ElevationSpec) (*AuthResponse, error)
+	// Sessions returns active sessions of all users.
+	Sessions() ([]Session, error)
+	// ExpireSession revokes all tokens of the session with given ID, so the user has to log in again.
+	ExpireSession(string) error
+	// OwnSessions returns active sessions of the user the provided token belongs to. Session of the token is
+	// marked as current.
+	OwnSessions(string) ([]Session, error)
+	// ExpireOwnSession expires session with given ID if it belongs to the same user as provided token.
+	ExpireOwnSession(string, string) error
+	// RecordSessionClient records client that provided token has been issued to in its session.
+	RecordSessionClient(string, SessionClient) error
+	// SessionID returns ID of the session provided token belongs to. Empty ID is returned if token manager does not
+	// keep track of sessions.
+	SessionID(string) (string, error)
+	// Groups returns groups of the user the provided token belongs to.
+	Groups(string) ([]string, error)
+	// EncryptState encrypts client state of given owner with keys of the token manager.
+	EncryptState(string, []byte) (string, error)
+	// DecryptState decrypts client state of given owner encrypted by EncryptState.
+	DecryptState(string, string) ([]byte, error)
+	// BeginWebAuthnRegistration takes valid token and returns challenge registering new second factor of its user.
+	BeginWebAuthnRegistration(string) (*WebAuthnRegistration, error)
+	// FinishWebAuthnRegistration takes valid token and registers credential from the attestation answering the
+	// challenge returned by BeginWebAuthnRegistration.
+	FinishWebAuthnRegistration(string, *WebAuthnAttestation) (*WebAuthnCredential, error)
+	// WebAuthnCredentials returns second factors registered by the user the provided token belongs to.
+	WebAuthnCredentials(string) ([]WebAuthnCredential, error)
+	// DeleteWebAuthnCredential removes second factor with given ID registered by the user the provided token
+	// belongs to.
+	DeleteWebAuthnCredential(string, string) error
 }
 
 // TokenManager is responsible for generating and decrypting tokens used for authorization. Authorization is handled
@@ -103,11 +200,76 @@ type TokenManager interface {
 	Refresh(string) (string, error)
 	// SetTokenTTL sets expiration time (in seconds) of generated tokens.
 	SetTokenTTL(time.Duration)
+	// SetTokenMaxLifetime sets maximum time (in seconds) since login for which tokens can be refreshed. Refreshed
+	// tokens never expire later than that.
+	SetTokenMaxLifetime(time.Duration)
+	// Revoke invalidates provided token before it expires. Revoked tokens can not be decrypted or refreshed.
+	Revoke(string) error
+}
+
+// EmbedTokenManager is implemented by token managers that can generate embed tokens. Embed tokens can only be
+// decrypted with DecryptEmbed, so they can not be used as regular tokens.
+type EmbedTokenManager interface {
+	// GenerateEmbed generates token based on AuthInfo structure that expires after given time and is restricted to
+	// given scope.
+	GenerateEmbed(api.AuthInfo, EmbedScope, time.Duration) (string, error)
+	// DecryptEmbed decrypts embed token and returns AuthInfo structure and scope saved in a token.
+	DecryptEmbed(string) (*api.AuthInfo, *EmbedScope, error)
+}
+
+// ElevationTokenManager is implemented by token managers that can temporarily elevate a session with stronger
+// credentials, i.e. an admin token used by a viewer for a few minutes.
+type ElevationTokenManager interface {
+	// Elevate generates a token of the same session as provided token, which uses given AuthInfo until given time.
+	// Session lifetime is not extended by elevation.
+	Elevate(string, api.AuthInfo, time.Time) (string, error)
+	// DecryptElevated decrypts token and returns AuthInfo of the session together with its elevation.
+	DecryptElevated(string) (*ElevatedAuthInfo, error)
+}
+
+// SessionTokenManager is implemented by token managers that keep track of active sessions, so they can be listed
+// and expired before their tokens expire.
+type SessionTokenManager interface {
+	// Sessions returns sessions that have tokens which have not expired yet.
+	Sessions() ([]Session, error)
+	// ExpireSession revokes all tokens generated within the session with given ID and forgets the session.
+	ExpireSession(string) error
+	// SessionOf returns active session provided token belongs to.
+	SessionOf(string) (*Session, error)
+	// SetSessionClient sets client that the latest token of the session with given ID has been issued to.
+	SetSessionClient(string, SessionClient) error
+}
+
+// GroupTokenManager is implemented by token managers that can carry groups of the user inside the token, so they
+// are known to handlers without asking the identity provider again.
+type GroupTokenManager interface {
+	// GenerateWithGroups generates token based on AuthInfo structure with given groups of the user in its payload.
+	GenerateWithGroups(api.AuthInfo, []string) (string, error)
+	// Groups decrypts token and returns groups of the user saved in it.
+	Groups(string) ([]string, error)
+}
+
+// StateTokenManager is implemented by token managers that can encrypt small blobs of client state with their keys,
+// so the state can be stored in the cluster without exposing its content. State is bound to its owner and can not
+// be decrypted for anyone else.
+type StateTokenManager interface {
+	// EncryptState encrypts state of given owner.
+	EncryptState(string, []byte) (string, error)
+	// DecryptState decrypts state encrypted for given owner.
+	DecryptState(string, string) ([]byte, error)
+}
+
+// AuditLogger records authentication events, so they can be reviewed during security audits.
+type AuditLogger interface {
+	// Log records given event. Records that can not be written are only logged, authentication does not fail
+	// because of them.
+	Log(*AuditRecord)
 }
 
 // Authenticator represents authentication methods supported by Dashboard. Currently supported types are:
 //    - Token based - Any bearer token accepted by apiserver
 //	  - Basic - Username and password based authentication. Requires that apiserver has basic auth enabled also
+//    - LDAP - Username and password verified by LDAP server. Dashboard impersonates the user and its LDAP groups
 //    - Kubeconfig based - Authenticates user based on kubeconfig file. Only token/basic modes are supported within
 // 		the kubeconfig file.
 type Authenticator interface {
@@ -127,6 +289,23 @@ type LoginSpec struct {
 	// KubeConfig is the content of users' kubeconfig file. It will be parsed and auth data will be extracted.
 	// Kubeconfig can not contain any paths. All data has to be provided within the file.
 	KubeConfig string `json:"kubeconfig,omitempty"`
+	// Mode selects the authentication mode of username and password, either "basic" or "ldap". It is required only
+	// when both modes are enabled, LDAP is used when it is empty.
+	Mode AuthenticationMode `json:"mode,omitempty"`
+	// WebAuthn is the assertion of the second factor answering the challenge returned by previous login request.
+	WebAuthn *WebAuthnAssertion `json:"webauthn,omitempty"`
+	// NoticeAcknowledgment is the ID of the login notice acknowledged by the user.
+	NoticeAcknowledgment string `json:"noticeAcknowledgment,omitempty"`
+}
+
+// LoginNotice is shown before login, i.e. terms of use of the cluster. It is identified by a hash of its text, so a
+// changed notice has to be acknowledged again.
+type LoginNotice struct {
+	ID string `json:"id"`
+	// Text in markdown format. Notice is not shown if it is empty.
+	Text string `json:"text"`
+	// AcknowledgmentRequired is true when logins not carrying ID of the notice are rejected.
+	AcknowledgmentRequired bool `json:"acknowledgmentRequired"`
 }
 
 // AuthResponse is returned from our backend as a response for login/refresh requests. It contains generated JWEToken
@@ -135,7 +314,12 @@ type AuthResponse struct {
 	// JWEToken is a token generated during login request that contains AuthInfo data in the payload.
 	JWEToken string `json:"jweToken"`
 	// Errors are a list of non-critical errors that happened during login request.
-	Errors []error `json:"errors"`
+	Errors []*errors.APIError `json:"errors"`
+	// Groups of the logged in user known at login, i.e. from OIDC token claims, LDAP or organizations of the client
+	// certificate.
+	Groups []string `json:"groups,omitempty"`
+	// WebAuthnChallenge is returned instead of the token if the user has to assert registered second factor.
+	WebAuthnChallenge *WebAuthnChallenge `json:"webauthnChallenge,omitempty"`
 }
 
 // TokenRefreshSpec contains token that is required by token refresh operation.
@@ -150,7 +334,128 @@ type LoginModesResponse struct {
 }
 
 // LoginSkippableResponse contains a flag that tells the UI not to display the Skip button.
-// Note that this only hides the button, it doesn't disable unauthenticated access.
+// Note that this only hides the button, it doesn't disable unauthenticated access. Identity tells which identity is
+// used by users who skip the login.
 type LoginSkippableResponse struct {
-	Skippable bool `json:"skippable"`
+	Skippable bool   `json:"skippable"`
+	Identity  string `json:"identity,omitempty"`
+}
+
+// EmbedScope restricts embed token to read requests of a single resource kind, optionally in a single namespace and
+// for a single resource name.
+type EmbedScope struct {
+	// Kind is a resource kind as used in dashboard API paths, i.e. pod.
+	Kind string `json:"kind"`
+	// Namespace is a namespace of the resources. Should be empty for non-namespaced resource kinds.
+	Namespace string `json:"namespace,omitempty"`
+	// Name is a name of the resource. All resources of given kind are allowed if empty.
+	Name string `json:"name,omitempty"`
+}
+
+// EmbedTokenSpec contains information required to generate embed token.
+type EmbedTokenSpec struct {
+	// Scope restricts resources that can be read with the token.
+	Scope EmbedScope `json:"scope"`
+	// TTL is an expiration time (in seconds) of the token. DefaultEmbedTokenTTL is used if not set.
+	TTL int64 `json:"ttl,omitempty"`
+}
+
+// ElevationSpec contains stronger credentials and duration of the session elevation.
+type ElevationSpec struct {
+	LoginSpec `json:",inline"`
+	// Duration (in seconds) of the elevation. Maximum elevation duration is used if not set.
+	Duration int64 `json:"duration,omitempty"`
+}
+
+// ElevatedAuthInfo is AuthInfo of a session, which may be temporarily elevated.
+type ElevatedAuthInfo struct {
+	// AuthInfo of the session used when it is not elevated.
+	AuthInfo api.AuthInfo
+	// Elevated is AuthInfo used until ElevatedUntil. It is nil if the session has not been elevated.
+	Elevated      *api.AuthInfo
+	ElevatedUntil time.Time
+	// Groups of the user the session belongs to. They are kept during the whole session.
+	Groups []string
+}
+
+// EmbedTokenResponse is returned from our backend as a response for embed token requests.
+type EmbedTokenResponse struct {
+	// EmbedToken is a read-only token that should be passed in EmbedTokenParameter query parameter.
+	EmbedToken string `json:"embedToken"`
+}
+
+// Session represents a single login to Dashboard. Tokens refreshed or elevated after the login belong to the same
+// session.
+type Session struct {
+	// ID of the session. It is random and does not reveal any tokens.
+	ID string `json:"id"`
+	// Subject is a loggable description of the credentials used to log in. Bearer tokens are only identified by
+	// their short fingerprint.
+	Subject string `json:"subject"`
+	// LoginTime is a time of the login.
+	LoginTime time.Time `json:"loginTime"`
+	// LastRefresh is a time when the last token of the session has been generated.
+	LastRefresh time.Time `json:"lastRefresh"`
+	// ExpiresAt is an expiration time of the latest token of the session. It is not set if tokens never expire.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Client that the latest token of the session has been issued to. It is not known for tokens issued before
+	// the client is recorded.
+	SessionClient `json:",inline"`
+	// Current is true for the session of the token used to list sessions.
+	Current bool `json:"current,omitempty"`
+}
+
+// SessionClient describes client that a token has been issued to.
+type SessionClient struct {
+	// SourceIP is an IP address the request for the token came from.
+	SourceIP string `json:"sourceIP,omitempty"`
+	// UserAgent is a value of the User-Agent header of the request for the token.
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+// SessionList contains active sessions of all users.
+type SessionList struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// AuditEvent represents type of the authentication event recorded by AuditLogger.
+type AuditEvent string
+
+// Authentication events recorded by AuditLogger should be defined below.
+const (
+	AuditLoginSucceeded     AuditEvent = "LoginSucceeded"
+	AuditLoginFailed        AuditEvent = "LoginFailed"
+	AuditTokenRefreshed     AuditEvent = "TokenRefreshed"
+	AuditTokenRefreshFailed AuditEvent = "TokenRefreshFailed"
+	AuditSessionElevated    AuditEvent = "SessionElevated"
+	AuditElevationFailed    AuditEvent = "ElevationFailed"
+	AuditLogout             AuditEvent = "Logout"
+	// AuditLoginSkipped is recorded when privileges of Dashboard are used by a user who has skipped the login.
+	AuditLoginSkipped AuditEvent = "LoginSkipped"
+	// AuditSecondFactorRegistered and AuditSecondFactorRemoved are recorded when users change their WebAuthn
+	// credentials.
+	AuditSecondFactorRegistered AuditEvent = "SecondFactorRegistered"
+	AuditSecondFactorRemoved    AuditEvent = "SecondFactorRemoved"
+	// AuditSecretRevealed and AuditSecretRevealDenied are recorded when users try to read values of secrets redacted
+	// by the secret redaction policy.
+	AuditSecretRevealed     AuditEvent = "SecretRevealed"
+	AuditSecretRevealDenied AuditEvent = "SecretRevealDenied"
+)
+
+// AuditRecord is a structured record of a single authentication event.
+type AuditRecord struct {
+	// Timestamp is a time of the event.
+	Timestamp time.Time `json:"timestamp"`
+	// Event is a type of the event.
+	Event AuditEvent `json:"event"`
+	// Subject is a loggable description of the credentials. Bearer tokens are only identified by their short
+	// fingerprint.
+	Subject string `json:"subject"`
+	// SourceIP is an IP address the request came from.
+	SourceIP string `json:"sourceIP"`
+	// UserAgent is a value of the User-Agent header of the request.
+	UserAgent string `json:"userAgent"`
+	// Message describes why the event failed. It is empty for successful events, except for secret reveals, which
+	// record the revealed secret.
+	Message string `json:"message,omitempty"`
 }
@@ -0,0 +1,115 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import "time"
+
+// WebAuthnCredential is a security key or platform authenticator registered by a user as the second factor of
+// basic and LDAP login.
+type WebAuthnCredential struct {
+	// ID is the base64url encoded credential ID assigned by the authenticator.
+	ID string `json:"id"`
+	// Name is given by the user to recognize the credential.
+	Name     string    `json:"name"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// WebAuthnCredentialList contains credentials registered by a user.
+type WebAuthnCredentialList struct {
+	Credentials []WebAuthnCredential `json:"credentials"`
+}
+
+// WebAuthnCredentialDescriptor identifies a credential in WebAuthn options.
+type WebAuthnCredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// WebAuthnRelyingParty identifies Dashboard as the relying party credentials are scoped to.
+type WebAuthnRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebAuthnUser identifies the user credentials are registered for.
+type WebAuthnUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// WebAuthnCredentialParameter is a public key algorithm accepted by Dashboard.
+type WebAuthnCredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// WebAuthnRequestOptions are options of navigator.credentials.get() call asserting the second factor. Binary values
+// are base64url encoded.
+type WebAuthnRequestOptions struct {
+	Challenge        string                         `json:"challenge"`
+	RPID             string                         `json:"rpId"`
+	Timeout          int64                          `json:"timeout"`
+	AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+// WebAuthnCreationOptions are options of navigator.credentials.create() call registering new credential. Binary
+// values are base64url encoded.
+type WebAuthnCreationOptions struct {
+	Challenge          string                         `json:"challenge"`
+	RP                 WebAuthnRelyingParty           `json:"rp"`
+	User               WebAuthnUser                   `json:"user"`
+	PubKeyCredParams   []WebAuthnCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout            int64                          `json:"timeout"`
+	ExcludeCredentials []WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+	Attestation        string                         `json:"attestation"`
+}
+
+// WebAuthnChallenge is returned by login instead of the token when the user has to assert the second factor. Login
+// has to be repeated with the same credentials and the assertion.
+type WebAuthnChallenge struct {
+	// ChallengeToken has to be sent back with the assertion. It is encrypted, so any replica can verify it.
+	ChallengeToken string                 `json:"challengeToken"`
+	PublicKey      WebAuthnRequestOptions `json:"publicKey"`
+}
+
+// WebAuthnRegistration starts registration of new credential of the logged in user.
+type WebAuthnRegistration struct {
+	// ChallengeToken has to be sent back with the attestation. It is encrypted, so any replica can verify it.
+	ChallengeToken string                  `json:"challengeToken"`
+	PublicKey      WebAuthnCreationOptions `json:"publicKey"`
+}
+
+// WebAuthnAssertion is the response of the authenticator to the challenge of login. Binary values are base64url
+// encoded.
+type WebAuthnAssertion struct {
+	ChallengeToken    string `json:"challengeToken"`
+	ID                string `json:"id"`
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+}
+
+// WebAuthnAttestation is the response of the authenticator to the registration challenge. Binary values are
+// base64url encoded.
+type WebAuthnAttestation struct {
+	ChallengeToken    string `json:"challengeToken"`
+	Name              string `json:"name"`
+	ID                string `json:"id"`
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AttestationObject string `json:"attestationObject"`
+}
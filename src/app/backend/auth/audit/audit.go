@@ -0,0 +1,134 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+// Sinks supported by 'auth-audit-sink' argument.
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkEvent  = "event"
+)
+
+const (
+	// Skipped login is recorded at most once per this period for the same source IP and user agent, as privileges
+	// of Dashboard are used by every request of such users.
+	skippedLoginPeriod = time.Hour
+	// Maximum number of remembered users who have skipped the login. Older entries are forgotten when it is reached.
+	maxSkippedLoginEntries = 10000
+)
+
+// Sink writes audit records to their destination.
+type Sink interface {
+	// Write writes single audit record.
+	Write(*authApi.AuditRecord) error
+}
+
+// Implements AuditLogger interface.
+type auditLogger struct {
+	sink Sink
+	// Times when skipped login has been recorded for source IP and user agent.
+	skipped map[string]time.Time
+	mux     sync.Mutex
+}
+
+// Log implements audit logger interface. See AuditLogger for more information.
+func (self *auditLogger) Log(record *authApi.AuditRecord) {
+	if self.sink == nil {
+		return
+	}
+
+	if record.Event == authApi.AuditLoginSkipped && !self.shouldLogSkippedLogin(record) {
+		return
+	}
+
+	if err := self.sink.Write(record); err != nil {
+		log.Printf("Could not write audit record of %s event: %s", record.Event, err)
+	}
+}
+
+// Returns true if skipped login has not been recorded for the source IP and user agent of the record recently.
+func (self *auditLogger) shouldLogSkippedLogin(record *authApi.AuditRecord) bool {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	key := record.SourceIP + "/" + record.UserAgent
+	if last, exists := self.skipped[key]; exists && record.Timestamp.Sub(last) < skippedLoginPeriod {
+		return false
+	}
+
+	if len(self.skipped) >= maxSkippedLoginEntries {
+		for entry, last := range self.skipped {
+			if record.Timestamp.Sub(last) >= skippedLoginPeriod {
+				delete(self.skipped, entry)
+			}
+		}
+	}
+
+	if len(self.skipped) >= maxSkippedLoginEntries {
+		self.skipped = make(map[string]time.Time)
+	}
+
+	self.skipped[key] = record.Timestamp
+	return true
+}
+
+// NewAuditLogger creates audit logger writing records to given sink. Nothing is recorded if sink is nil.
+func NewAuditLogger(sink Sink) authApi.AuditLogger {
+	return &auditLogger{sink: sink, skipped: make(map[string]time.Time)}
+}
+
+// NewSinkFromArgs creates sink selected by 'auth-audit-sink' argument. Nil is returned if auditing is disabled.
+// Events are created with given client.
+func NewSinkFromArgs(client kubernetes.Interface) (Sink, error) {
+	switch sink := args.Holder.GetAuthAuditSink(); sink {
+	case "":
+		return nil, nil
+	case SinkStdout:
+		return NewWriterSink(os.Stdout), nil
+	case SinkFile:
+		if len(args.Holder.GetAuthAuditFile()) == 0 {
+			return nil, fmt.Errorf("--auth-audit-file has to be set for %s audit sink", SinkFile)
+		}
+
+		file, err := os.OpenFile(args.Holder.GetAuthAuditFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewWriterSink(file), nil
+	case SinkEvent:
+		object, err := ParseEventObject(args.Holder.GetAuthAuditEventObject())
+		if err != nil {
+			return nil, err
+		}
+
+		return NewEventSink(client, object), nil
+	default:
+		return nil, fmt.Errorf("unknown audit sink %s", sink)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+func TestAuditLogger_Log(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewAuditLogger(NewWriterSink(buffer))
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	record := func(event authApi.AuditEvent, sourceIP string, timestamp time.Time) *authApi.AuditRecord {
+		return &authApi.AuditRecord{Timestamp: timestamp, Event: event, Subject: "user", SourceIP: sourceIP,
+			UserAgent: "browser"}
+	}
+
+	logger.Log(record(authApi.AuditLoginSucceeded, "10.0.0.1", now))
+	logger.Log(record(authApi.AuditLoginSucceeded, "10.0.0.1", now))
+	logger.Log(record(authApi.AuditLoginSkipped, "10.0.0.1", now))
+	logger.Log(record(authApi.AuditLoginSkipped, "10.0.0.1", now.Add(time.Minute)))
+	logger.Log(record(authApi.AuditLoginSkipped, "10.0.0.2", now.Add(time.Minute)))
+	logger.Log(record(authApi.AuditLoginSkipped, "10.0.0.1", now.Add(skippedLoginPeriod)))
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	expected := []authApi.AuditEvent{authApi.AuditLoginSucceeded, authApi.AuditLoginSucceeded,
+		authApi.AuditLoginSkipped, authApi.AuditLoginSkipped, authApi.AuditLoginSkipped}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d records with repeated skipped login omitted, but got %v.", len(expected), lines)
+	}
+
+	for i, line := range lines {
+		written := new(authApi.AuditRecord)
+		if err := json.Unmarshal([]byte(line), written); err != nil {
+			t.Fatalf("Expected JSON record, but got %s: %v.", line, err)
+		}
+
+		if written.Event != expected[i] || written.Subject != "user" || written.UserAgent != "browser" {
+			t.Errorf("Expected %s record of user, but got %+v.", expected[i], written)
+		}
+	}
+
+	// Nothing is recorded without sink.
+	NewAuditLogger(nil).Log(record(authApi.AuditLogout, "10.0.0.1", now))
+}
+
+func TestEventSink_Write(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	// Fake client does not generate names.
+	client.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		event := action.(k8stesting.CreateAction).GetObject().(*v1.Event)
+		event.Name = event.GenerateName + string(event.Reason)
+		return false, nil, nil
+	})
+	object, err := ParseEventObject("Service/kubernetes-dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sink := NewEventSink(client, object)
+	records := []*authApi.AuditRecord{
+		{Timestamp: time.Now(), Event: authApi.AuditLoginSucceeded, Subject: "user"},
+		{Timestamp: time.Now(), Event: authApi.AuditLoginFailed, Subject: "user", Message: "MSG_LOGIN_UNAUTHORIZED"},
+	}
+	for _, record := range records {
+		if err := sink.Write(record); err != nil {
+			t.Fatalf("Expected no error when writing record, but got %v.", err)
+		}
+	}
+
+	events, _ := client.CoreV1().Events(object.Namespace).List(context.TODO(), metaV1.ListOptions{})
+	if len(events.Items) != 2 {
+		t.Fatalf("Expected 2 events, but got %d.", len(events.Items))
+	}
+
+	types := map[authApi.AuditEvent]string{}
+	for _, event := range events.Items {
+		written := new(authApi.AuditRecord)
+		if err := json.Unmarshal([]byte(event.Message), written); err != nil || string(written.Event) != event.Reason {
+			t.Errorf("Expected event message to be JSON record of %s, but got %s.", event.Reason, event.Message)
+		}
+
+		if event.InvolvedObject.Kind != "Service" || event.InvolvedObject.Name != "kubernetes-dashboard" ||
+			event.Reason != string(written.Event) {
+			t.Errorf("Expected %s event of the dashboard service, but got %+v.", event.Reason, event)
+		}
+		types[written.Event] = event.Type
+	}
+
+	if types[authApi.AuditLoginSucceeded] != v1.EventTypeNormal || types[authApi.AuditLoginFailed] != v1.EventTypeWarning {
+		t.Errorf("Expected only failed events to be warnings, but got %v.", types)
+	}
+}
+
+func TestParseEventObject(t *testing.T) {
+	for _, object := range []string{"", "kubernetes-dashboard", "Service/", "/kubernetes-dashboard", "a/b/c"} {
+		if _, err := ParseEventObject(object); err == nil {
+			t.Errorf("Expected error when parsing %s, but got none.", object)
+		}
+	}
+}
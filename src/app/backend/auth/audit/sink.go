@@ -0,0 +1,106 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+// Writes records as JSON lines, i.e. to stdout or a file.
+type writerSink struct {
+	writer io.Writer
+	mux    sync.Mutex
+}
+
+// Write implements Sink interface. See Sink for more information.
+func (self *writerSink) Write(record *authApi.AuditRecord) error {
+	marshalled, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	// Records are written at once, so lines of concurrent requests are not mixed.
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	_, err = self.writer.Write(append(marshalled, '\n'))
+	return err
+}
+
+// NewWriterSink creates sink writing records to given writer as JSON lines.
+func NewWriterSink(writer io.Writer) Sink {
+	return &writerSink{writer: writer}
+}
+
+// Creates Kubernetes events with JSON records as messages, attached to the configured object.
+type eventSink struct {
+	client kubernetes.Interface
+	object v1.ObjectReference
+}
+
+// Write implements Sink interface. See Sink for more information.
+func (self *eventSink) Write(record *authApi.AuditRecord) error {
+	marshalled, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	eventType := v1.EventTypeNormal
+	if len(record.Message) > 0 {
+		eventType = v1.EventTypeWarning
+	}
+
+	timestamp := metaV1.NewTime(record.Timestamp)
+	event := &v1.Event{
+		ObjectMeta:     metaV1.ObjectMeta{GenerateName: self.object.Name + ".", Namespace: self.object.Namespace},
+		InvolvedObject: self.object,
+		Reason:         string(record.Event),
+		Message:        string(marshalled),
+		Source:         v1.EventSource{Component: "kubernetes-dashboard"},
+		FirstTimestamp: timestamp,
+		LastTimestamp:  timestamp,
+		Count:          1,
+		Type:           eventType,
+	}
+
+	_, err = self.client.CoreV1().Events(self.object.Namespace).Create(context.TODO(), event, metaV1.CreateOptions{})
+	return err
+}
+
+// NewEventSink creates sink creating events attached to given object with given client.
+func NewEventSink(client kubernetes.Interface, object v1.ObjectReference) Sink {
+	return &eventSink{client: client, object: object}
+}
+
+// ParseEventObject parses object in 'kind/name' format. Object is expected in Dashboard namespace.
+func ParseEventObject(object string) (v1.ObjectReference, error) {
+	parts := strings.Split(object, "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return v1.ObjectReference{}, fmt.Errorf("audit event object %s is not in 'kind/name' format", object)
+	}
+
+	return v1.ObjectReference{Kind: parts[0], Name: parts[1], Namespace: args.Holder.GetNamespace()}, nil
+}
@@ -0,0 +1,309 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/pkg/apis/clientauthentication/v1alpha1"
+	"k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// Time after which a running exec credential plugin is killed.
+	execPluginTimeout = 30 * time.Second
+	// Cached tokens are only reused if they are valid for at least this long.
+	execCredentialMinTTL = time.Minute
+	// Maximum number of cached tokens.
+	execCredentialCacheSize = 1000
+	// Maximum length of plugin output shown in errors.
+	execPluginMaxErrorLength = 256
+)
+
+// Environment variables that can not be set by the kubeconfig file, as they change what is executed or let plugins
+// read files and credentials of Dashboard.
+var (
+	execEnvDenyNames    = []string{"PATH", "HOME", "KUBECONFIG", "BASH_ENV", "ENV", "KUBERNETES_EXEC_INFO", "NO_GCE_CHECK"}
+	execEnvDenyPrefixes = []string{"LD_", "DYLD_", "AWS_", "GOOGLE_", "CLOUDSDK_", "GCE_"}
+)
+
+// Environment variables set for every plugin after those from the kubeconfig file. Plugins must not fall back to
+// the cloud identity of the Dashboard pod, that is served by metadata servers.
+var execEnvPinned = []string{
+	"AWS_EC2_METADATA_DISABLED=true",
+	"CLOUDSDK_CORE_CHECK_GCE_METADATA=false",
+	"NO_GCE_CHECK=True",
+}
+
+// Leading arguments required by well-known plugins, so that they can only be used to get a token.
+var execPluginSubcommands = map[string][]string{
+	"aws":                   {"eks", "get-token"},
+	"aws-iam-authenticator": {"token"},
+	"gcloud":                {"config", "config-helper"},
+	"kubelogin":             {"get-token"},
+}
+
+type execEnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// execConfig is the 'exec' entry of kubeconfig user.
+type execConfig struct {
+	APIVersion string       `yaml:"apiVersion"`
+	Command    string       `yaml:"command"`
+	Args       []string     `yaml:"args"`
+	Env        []execEnvVar `yaml:"env"`
+}
+
+// execCredential is the output of exec credential plugin, it is the same in all supported API versions.
+type execCredential struct {
+	APIVersion string                        `json:"apiVersion"`
+	Kind       string                        `json:"kind"`
+	Status     *v1beta1.ExecCredentialStatus `json:"status,omitempty"`
+}
+
+type execCredentialEntry struct {
+	token     string
+	expiresAt time.Time
+}
+
+// execCredentialCache keeps tokens returned by exec credential plugins until they expire. Plugins return the same
+// credentials for the same command, arguments and environment, so they are cached by them.
+type execCredentialCache struct {
+	mux     sync.Mutex
+	entries map[string]execCredentialEntry
+}
+
+var execCredentials = &execCredentialCache{entries: make(map[string]execCredentialEntry)}
+
+func (self *execCredentialCache) get(key string, now time.Time) (string, bool) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	entry, ok := self.entries[key]
+	if !ok || entry.expiresAt.Before(now.Add(execCredentialMinTTL)) {
+		return "", false
+	}
+
+	return entry.token, true
+}
+
+func (self *execCredentialCache) put(key, token string, expiresAt, now time.Time) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	for k, entry := range self.entries {
+		if entry.expiresAt.Before(now) {
+			delete(self.entries, k)
+		}
+	}
+
+	if len(self.entries) < execCredentialCacheSize {
+		self.entries[key] = execCredentialEntry{token: token, expiresAt: expiresAt}
+	}
+}
+
+// getExecToken runs exec credential plugin and returns the token it provides. Only plugins with commands in
+// given list are run. Tokens with an expiry are cached until they expire.
+func getExecToken(config *execConfig, allowed []string) (string, error) {
+	if len(allowed) == 0 {
+		return "", errors.NewInvalid("Exec credential plugins are disabled. Check --kubeconfig-exec-plugins " +
+			"argument for more information.")
+	}
+
+	if !isExecPluginAllowed(config.Command, allowed) {
+		return "", errors.NewInvalid(fmt.Sprintf("Exec credential plugin %s is not allowed.", config.Command))
+	}
+
+	if config.APIVersion != v1alpha1.SchemeGroupVersion.String() &&
+		config.APIVersion != v1beta1.SchemeGroupVersion.String() {
+		return "", errors.NewInvalid(fmt.Sprintf("Exec credential plugin API version %s is not supported.",
+			config.APIVersion))
+	}
+
+	for _, env := range config.Env {
+		if isExecEnvDenied(env.Name) {
+			return "", errors.NewInvalid(fmt.Sprintf("Environment variable %s can not be set for exec "+
+				"credential plugins.", env.Name))
+		}
+	}
+
+	if err := validateExecArgs(config.Command, config.Args); err != nil {
+		return "", err
+	}
+
+	key := getExecConfigKey(config)
+	if token, ok := execCredentials.get(key, time.Now()); ok {
+		return token, nil
+	}
+
+	credential, err := runExecPlugin(config)
+	if err != nil {
+		return "", err
+	}
+
+	if credential.Status.ExpirationTimestamp != nil {
+		execCredentials.put(key, credential.Status.Token, credential.Status.ExpirationTimestamp.Time, time.Now())
+	}
+
+	return credential.Status.Token, nil
+}
+
+func isExecPluginAllowed(command string, allowed []string) bool {
+	for _, allowedCommand := range allowed {
+		if command == allowedCommand {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isExecEnvDenied(name string) bool {
+	for _, denied := range execEnvDenyNames {
+		if name == denied {
+			return true
+		}
+	}
+
+	for _, prefix := range execEnvDenyPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateExecArgs checks that well-known plugins are run with their token subcommand and that no argument
+// refers to local files, i.e. to credentials of Dashboard.
+func validateExecArgs(command string, args []string) error {
+	if subcommand, ok := execPluginSubcommands[filepath.Base(command)]; ok {
+		if len(args) < len(subcommand) || strings.Join(args[:len(subcommand)], " ") != strings.Join(subcommand, " ") {
+			return errors.NewInvalid(fmt.Sprintf("Exec credential plugin %s can only be run as '%s %s'.", command,
+				command, strings.Join(subcommand, " ")))
+		}
+	}
+
+	for _, arg := range args {
+		value := arg
+		if i := strings.Index(arg, "="); i >= 0 {
+			value = arg[i+1:]
+		}
+
+		if strings.HasPrefix(value, "/") || strings.HasPrefix(value, "~") || strings.HasPrefix(value, "file:") ||
+			strings.Contains(value, "..") {
+			return errors.NewInvalid(fmt.Sprintf("Argument %s of exec credential plugin %s refers to a local file.",
+				arg, command))
+		}
+	}
+
+	return nil
+}
+
+// runExecPlugin runs the plugin non-interactively and parses its output. The plugin does not inherit environment
+// of Dashboard, it gets only PATH, an empty home directory and variables from the kubeconfig file.
+func runExecPlugin(config *execConfig) (*execCredential, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), execPluginTimeout)
+	defer cancel()
+
+	info, err := json.Marshal(execCredential{APIVersion: config.APIVersion, Kind: "ExecCredential"})
+	if err != nil {
+		return nil, err
+	}
+
+	home, err := ioutil.TempDir("", "exec-plugin")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(home)
+
+	cmd := exec.CommandContext(ctx, config.Command, config.Args...)
+	cmd.Dir = home
+	cmd.Env = getExecEnv(config, home, string(info))
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.NewUnauthorized(fmt.Sprintf("Exec credential plugin %s failed: %s %s", config.Command,
+			err.Error(), truncate(stderr.String(), execPluginMaxErrorLength)))
+	}
+
+	credential := new(execCredential)
+	if err := json.Unmarshal(stdout.Bytes(), credential); err != nil {
+		return nil, errors.NewUnauthorized(fmt.Sprintf("Cannot parse output of exec credential plugin %s: %s",
+			config.Command, err.Error()))
+	}
+
+	if credential.APIVersion != config.APIVersion || credential.Kind != "ExecCredential" {
+		return nil, errors.NewUnauthorized(fmt.Sprintf("Exec credential plugin %s returned %s %s, expected "+
+			"ExecCredential %s.", config.Command, credential.APIVersion, credential.Kind, config.APIVersion))
+	}
+
+	if credential.Status == nil || len(credential.Status.Token) == 0 {
+		return nil, errors.NewUnauthorized(fmt.Sprintf("Exec credential plugin %s did not return a token.",
+			config.Command))
+	}
+
+	return credential, nil
+}
+
+// getExecEnv returns the whole environment of the plugin.
+func getExecEnv(config *execConfig, home, info string) []string {
+	env := []string{"PATH=" + os.Getenv("PATH"), "HOME=" + home, "KUBERNETES_EXEC_INFO=" + info}
+	for _, variable := range config.Env {
+		env = append(env, variable.Name+"="+variable.Value)
+	}
+
+	return append(env, execEnvPinned...)
+}
+
+// getExecConfigKey returns a SHA-256 hash of everything that is passed to the plugin.
+func getExecConfigKey(config *execConfig) string {
+	hash := sha256.New()
+	for _, value := range append([]string{config.APIVersion, config.Command}, config.Args...) {
+		fmt.Fprintf(hash, "%d:%s", len(value), value)
+	}
+
+	for _, env := range config.Env {
+		fmt.Fprintf(hash, "%d:%s=%d:%s", len(env.Name), env.Name, len(env.Value), env.Value)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+func truncate(s string, length int) string {
+	s = strings.TrimSpace(s)
+	if len(s) > length {
+		return s[:length] + "..."
+	}
+
+	return s
+}
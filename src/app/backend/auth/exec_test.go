@@ -0,0 +1,186 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+const execKubeconfig = `
+apiVersion: v1
+kind: Config
+contexts:
+- context:
+    user: foo
+  name: foo
+current-context: foo
+users:
+- name: foo
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: %s
+      args: ["%s"]
+      env:
+      - name: %s
+        value: bar
+`
+
+// writeExecPlugin writes a plugin that returns token given as first argument and records its runs in given file.
+func writeExecPlugin(t *testing.T, dir, runs string, expiresAt time.Time) string {
+	plugin := filepath.Join(dir, "plugin")
+	script := fmt.Sprintf(`#!/bin/sh
+echo run >> %s
+echo '{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential",'\
+'"status":{"token":"'$1'","expirationTimestamp":"%s"}}'
+`, runs, expiresAt.UTC().Format(time.RFC3339))
+	if err := ioutil.WriteFile(plugin, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	return plugin
+}
+
+func TestKubeConfigAuthenticatorExec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exec-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	runs := filepath.Join(dir, "runs")
+	plugin := writeExecPlugin(t, dir, runs, time.Now().Add(time.Hour))
+	authModes := authApi.AuthenticationModes{authApi.Token: true}
+
+	cases := []struct {
+		info          string
+		command       string
+		token         string
+		env           string
+		allowed       []string
+		expectedToken string
+		expectedErr   string
+		expectedRuns  int
+	}{
+		{"disabled", plugin, "first-token", "FOO", nil, "", "Exec credential plugins are disabled", 0},
+		{"not allowed", "/bin/sh", "first-token", "FOO", []string{plugin}, "", "is not allowed", 0},
+		{"denied env", plugin, "first-token", "LD_PRELOAD", []string{plugin}, "", "can not be set", 0},
+		{"denied cloud env", plugin, "first-token", "AWS_WEB_IDENTITY_TOKEN_FILE", []string{plugin}, "",
+			"can not be set", 0},
+		{"denied home", plugin, "first-token", "HOME", []string{plugin}, "", "can not be set", 0},
+		{"file argument", plugin, "--token-file=/var/run/secrets/token", "FOO", []string{plugin}, "",
+			"refers to a local file", 0},
+		{"run", plugin, "first-token", "FOO", []string{plugin}, "first-token", "", 1},
+		{"cached", plugin, "first-token", "FOO", []string{plugin}, "first-token", "", 1},
+		{"other args", plugin, "second-token", "FOO", []string{plugin}, "second-token", "", 2},
+	}
+
+	for _, c := range cases {
+		spec := &authApi.LoginSpec{KubeConfig: fmt.Sprintf(execKubeconfig, c.command, c.token, c.env)}
+		authenticator := NewKubeConfigAuthenticator(spec, authModes).(*kubeConfigAuthenticator)
+		authenticator.execPlugins = c.allowed
+
+		info, err := authenticator.GetAuthInfo()
+		if len(c.expectedErr) > 0 {
+			if err == nil || !strings.Contains(err.Error(), c.expectedErr) {
+				t.Errorf("%s: expected error containing %q, but got %v", c.info, c.expectedErr, err)
+			}
+		} else if err != nil || info.Token != c.expectedToken {
+			t.Errorf("%s: expected token %s, but got %s, %v", c.info, c.expectedToken, info.Token, err)
+		}
+
+		output, _ := ioutil.ReadFile(runs)
+		if actual := strings.Count(string(output), "run"); actual != c.expectedRuns {
+			t.Errorf("%s: expected plugin to run %d times, but it ran %d times", c.info, c.expectedRuns, actual)
+		}
+	}
+}
+
+func TestExecPluginEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exec-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.Setenv("DASHBOARD_SECRET", "secret")
+	defer os.Unsetenv("DASHBOARD_SECRET")
+
+	output := filepath.Join(dir, "env")
+	plugin := filepath.Join(dir, "plugin")
+	script := fmt.Sprintf(`#!/bin/sh
+env > %s
+echo '{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"token":"token"}}'
+`, output)
+	if err := ioutil.WriteFile(plugin, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &execConfig{APIVersion: "client.authentication.k8s.io/v1beta1", Command: plugin,
+		Env: []execEnvVar{{Name: "FOO", Value: "bar"}}}
+	if _, err := runExecPlugin(config); err != nil {
+		t.Fatalf("runExecPlugin(): unexpected error: %v", err)
+	}
+
+	env, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(env), "DASHBOARD_SECRET") {
+		t.Error("plugin should not inherit environment of Dashboard")
+	}
+
+	if home := os.Getenv("HOME"); len(home) > 0 && strings.Contains(string(env), "HOME="+home+"\n") {
+		t.Error("plugin should get an empty home directory")
+	}
+
+	for _, expected := range append([]string{"FOO=bar", "KUBERNETES_EXEC_INFO="}, execEnvPinned...) {
+		if !strings.Contains(string(env), expected) {
+			t.Errorf("plugin should get %s", expected)
+		}
+	}
+}
+
+func TestValidateExecArgs(t *testing.T) {
+	cases := []struct {
+		command string
+		args    []string
+		valid   bool
+	}{
+		{"aws", []string{"eks", "get-token", "--cluster-name", "foo"}, true},
+		{"/usr/local/bin/aws", []string{"eks", "get-token"}, true},
+		{"aws", []string{"s3", "ls"}, false},
+		{"aws", []string{"eks"}, false},
+		{"kubelogin", []string{"get-token", "--server-id=foo"}, true},
+		{"kubelogin", []string{"get-token", "--token-cache-dir=../cache"}, false},
+		{"plugin", []string{"--config", "~/.config"}, false},
+		{"plugin", []string{"token"}, true},
+	}
+
+	for _, c := range cases {
+		if err := validateExecArgs(c.command, c.args); (err == nil) != c.valid {
+			t.Errorf("validateExecArgs(%s, %v): expected valid %t, got %v", c.command, c.args, c.valid, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Claim of OIDC ID tokens that contains groups of the user.
+const groupsClaim = "groups"
+
+// GetGroups returns groups of the user known from provided AuthInfo, i.e. groups impersonated after LDAP login,
+// groups claim of OIDC ID token or organizations of the client certificate. It has to be called only after
+// AuthInfo has been verified by API server, as signatures of tokens and certificates are not verified here.
+func GetGroups(authInfo api.AuthInfo) []string {
+	switch {
+	case len(authInfo.ImpersonateGroups) > 0:
+		return authInfo.ImpersonateGroups
+	case len(authInfo.Token) > 0:
+		return getTokenGroups(authInfo.Token)
+	case len(authInfo.ClientCertificateData) > 0:
+		return getCertificateGroups(authInfo.ClientCertificateData)
+	}
+
+	return nil
+}
+
+// Returns groups claim of the token if it is a JWT. Groups claim can be either a list or a single group.
+func getTokenGroups(token string) []string {
+	return getStringsClaim(getTokenClaims(token), groupsClaim)
+}
+
+// Returns claims of the token if it is a JWT. Signature of the token is not verified.
+func getTokenClaims(token string) map[string]json.RawMessage {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil
+	}
+
+	claims := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(decoded, &claims); err != nil {
+		return nil
+	}
+
+	return claims
+}
+
+// Returns claim that can be either a list of strings or a single string.
+func getStringsClaim(claims map[string]json.RawMessage, name string) []string {
+	var values []string
+	if err := json.Unmarshal(claims[name], &values); err == nil {
+		return values
+	}
+
+	var value string
+	if err := json.Unmarshal(claims[name], &value); err == nil && len(value) > 0 {
+		return []string{value}
+	}
+
+	return nil
+}
+
+// Returns organizations of the PEM encoded client certificate. API server uses them as groups of the user.
+func getCertificateGroups(data []byte) []string {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+
+	return cert.Subject.Organization
+}
@@ -0,0 +1,80 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func getTestJWT(claims string) string {
+	return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".c2lnbmF0dXJl"
+}
+
+func getTestCertificate(t *testing.T, organizations ...string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "user", Organization: organizations},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestGetGroups(t *testing.T) {
+	cases := []struct {
+		info     string
+		authInfo api.AuthInfo
+		expected []string
+	}{
+		{"LDAP groups", api.AuthInfo{Impersonate: "user", ImpersonateGroups: []string{"dev"}}, []string{"dev"}},
+		{"OIDC groups", api.AuthInfo{Token: getTestJWT(`{"sub":"user","groups":["dev","ops"]}`)},
+			[]string{"dev", "ops"}},
+		{"OIDC single group", api.AuthInfo{Token: getTestJWT(`{"sub":"user","groups":"dev"}`)}, []string{"dev"}},
+		{"JWT without groups", api.AuthInfo{Token: getTestJWT(`{"sub":"user"}`)}, nil},
+		{"opaque token", api.AuthInfo{Token: "abcdef.0123456789abcdef"}, nil},
+		{"client certificate", api.AuthInfo{ClientCertificateData: getTestCertificate(t, "system:masters")},
+			[]string{"system:masters"}},
+		{"invalid certificate", api.AuthInfo{ClientCertificateData: []byte("invalid")}, nil},
+	}
+
+	for _, c := range cases {
+		if groups := GetGroups(c.authInfo); !reflect.DeepEqual(groups, c.expected) {
+			t.Errorf("%s: Expected groups %v, but got %v.", c.info, c.expected, groups)
+		}
+	}
+}
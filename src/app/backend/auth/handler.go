@@ -15,18 +15,31 @@
 package auth
 
 import (
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/emicklei/go-restful"
+	"k8s.io/client-go/rest"
 
+	"github.com/kubernetes/dashboard/src/app/backend/args"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
 	"github.com/kubernetes/dashboard/src/app/backend/validation"
 )
 
 // AuthHandler manages all endpoints related to dashboard auth, such as login.
 type AuthHandler struct {
-	manager authApi.AuthManager
+	manager     authApi.AuthManager
+	cManager    clientapi.ClientManager
+	fManager    featuresApi.FeatureGateManager
+	limiter     *LoginLimiter
+	auditLogger authApi.AuditLogger
 }
 
 // Install creates new endpoints for dashboard auth, such as login. It allows user to log in to dashboard using
@@ -46,68 +59,400 @@ func (self AuthHandler) Install(ws *restful.WebService) {
 			Reads(authApi.TokenRefreshSpec{}).
 			To(self.handleJWETokenRefresh).
 			Writes(authApi.AuthResponse{}))
+	ws.Route(
+		ws.POST("/logout").
+			To(self.handleLogout))
+	ws.Route(
+		ws.POST("/login/elevate").
+			Reads(authApi.ElevationSpec{}).
+			To(self.handleElevate).
+			Writes(authApi.AuthResponse{}))
+	ws.Route(
+		ws.POST("/token/embed").
+			Reads(authApi.EmbedTokenSpec{}).
+			To(self.handleEmbedToken).
+			Writes(authApi.EmbedTokenResponse{}))
 	ws.Route(
 		ws.GET("/login/modes").
 			To(self.handleLoginModes).
 			Writes(authApi.LoginModesResponse{}))
+	ws.Route(
+		ws.GET("/login/notice").
+			To(self.handleLoginNotice).
+			Writes(authApi.LoginNotice{}))
 	ws.Route(
 		ws.GET("/login/skippable").
 			To(self.handleLoginSkippable).
 			Writes(authApi.LoginSkippableResponse{}))
+	ws.Route(
+		ws.GET("/sessions").
+			To(self.handleGetSessions).
+			Writes(authApi.SessionList{}))
+	ws.Route(
+		ws.DELETE("/sessions/{id}").
+			To(self.handleExpireSession))
+	ws.Route(
+		ws.GET("/sessions/mine").
+			To(self.handleGetOwnSessions).
+			Writes(authApi.SessionList{}))
+	ws.Route(
+		ws.DELETE("/sessions/mine/{id}").
+			To(self.handleExpireOwnSession))
+	ws.Route(
+		ws.POST("/webauthn/registration").
+			To(self.handleBeginWebAuthnRegistration).
+			Writes(authApi.WebAuthnRegistration{}))
+	ws.Route(
+		ws.GET("/webauthn/credentials").
+			To(self.handleGetWebAuthnCredentials).
+			Writes(authApi.WebAuthnCredentialList{}))
+	ws.Route(
+		ws.POST("/webauthn/credentials").
+			Reads(authApi.WebAuthnAttestation{}).
+			To(self.handleFinishWebAuthnRegistration).
+			Writes(authApi.WebAuthnCredential{}))
+	ws.Route(
+		ws.DELETE("/webauthn/credentials/{id}").
+			To(self.handleDeleteWebAuthnCredential))
 }
 
 func (self AuthHandler) handleLogin(request *restful.Request, response *restful.Response) {
 	loginSpec := new(authApi.LoginSpec)
 	if err := request.ReadEntity(loginSpec); err != nil {
-		response.AddHeader("Content-Type", "text/plain")
-		response.WriteErrorString(errors.HandleHTTPError(err), err.Error()+"\n")
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	self.limitLogin(request, response, loginSpec, authApi.AuditLoginSucceeded, authApi.AuditLoginFailed,
+		func() (*authApi.AuthResponse, error) {
+			return self.manager.Login(loginSpec)
+		})
+}
+
+// Calls login function unless source IP address or username of the request is locked out after failed login
+// attempts. Result of the login is recorded by the login limiter and audit logger as given events.
+func (self AuthHandler) limitLogin(request *restful.Request, response *restful.Response, loginSpec *authApi.LoginSpec,
+	succeeded, failed authApi.AuditEvent, login func() (*authApi.AuthResponse, error)) {
+	subject := getLoginSubject(loginSpec)
+	limiterKeys := getLoginLimiterKeys(request.Request, loginSpec)
+	if retryAfter := self.limiter.Check(limiterKeys...); retryAfter > 0 {
+		self.audit(request, failed, subject, "locked out after too many failed login attempts")
+		response.AddHeader("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		errors.HandleInternalError(response, errors.NewTooManyRequests("Too many failed login attempts. Try again later.",
+			int(math.Ceil(retryAfter.Seconds()))))
 		return
 	}
 
-	loginResponse, err := self.manager.Login(loginSpec)
+	loginResponse, err := login()
 	if err != nil {
-		response.AddHeader("Content-Type", "text/plain")
-		response.WriteErrorString(errors.HandleHTTPError(err), err.Error()+"\n")
+		if errors.HandleHTTPError(err) == http.StatusUnauthorized {
+			self.limiter.Fail(limiterKeys...)
+		}
+
+		self.audit(request, failed, subject, err.Error())
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	// Credentials accepted by the apiserver still have to be confirmed by the second factor. Challenge is neither
+	// a failure nor a success, the result is recorded once the login is repeated with the assertion.
+	if loginResponse.WebAuthnChallenge != nil {
+		response.WriteHeaderAndEntity(http.StatusOK, loginResponse)
 		return
 	}
 
+	// Credentials rejected by the apiserver are reported as non-critical errors without token.
+	if len(loginResponse.JWEToken) == 0 {
+		self.limiter.Fail(limiterKeys...)
+		self.audit(request, failed, subject, "credentials rejected by the apiserver")
+	} else {
+		if len(loginSpec.Username) > 0 {
+			self.limiter.Succeed(getUsernameLimiterKey(loginSpec.Username))
+		}
+		self.audit(request, succeeded, subject, "")
+		self.recordSessionClient(request, loginResponse.JWEToken)
+	}
+
 	response.WriteHeaderAndEntity(http.StatusOK, loginResponse)
 }
 
 func (self *AuthHandler) handleLoginStatus(request *restful.Request, response *restful.Response) {
-	response.WriteHeaderAndEntity(http.StatusOK, validation.ValidateLoginStatus(request))
+	response.WriteHeaderAndEntity(http.StatusOK, GetLoginStatus(self.cManager, request))
+}
+
+// GetLoginStatus returns login status of given request. Users authenticated by a trusted proxy are logged in without
+// the login page.
+func GetLoginStatus(cManager clientapi.ClientManager, request *restful.Request) *validation.LoginStatus {
+	status := validation.ValidateLoginStatus(request)
+	if authInfo := cManager.HeaderAuthInfo(request); authInfo != nil {
+		status.HeaderPresent = true
+		status.ImpersonationPresent = true
+		status.ImpersonatedUser = authInfo.Impersonate
+	}
+
+	return status
 }
 
 func (self *AuthHandler) handleJWETokenRefresh(request *restful.Request, response *restful.Response) {
 	tokenRefreshSpec := new(authApi.TokenRefreshSpec)
 	if err := request.ReadEntity(tokenRefreshSpec); err != nil {
-		response.AddHeader("Content-Type", "text/plain")
-		response.WriteErrorString(errors.HandleHTTPError(err), err.Error()+"\n")
+		errors.HandleInternalError(response, err)
 		return
 	}
 
+	subject := self.getTokenSubject(request)
 	refreshedJWEToken, err := self.manager.Refresh(tokenRefreshSpec.JWEToken)
 	if err != nil {
-		response.AddHeader("Content-Type", "text/plain")
-		response.WriteErrorString(errors.HandleHTTPError(err), err.Error()+"\n")
+		self.audit(request, authApi.AuditTokenRefreshFailed, subject, err.Error())
+		errors.HandleInternalError(response, err)
 		return
 	}
 
+	self.audit(request, authApi.AuditTokenRefreshed, subject, "")
+	self.recordSessionClient(request, refreshedJWEToken)
+	// Groups are only informational, so token managers that do not keep them do not fail the refresh.
+	groups, _ := self.manager.Groups(refreshedJWEToken)
 	response.WriteHeaderAndEntity(http.StatusOK, &authApi.AuthResponse{
 		JWEToken: refreshedJWEToken,
-		Errors:   make([]error, 0),
+		Errors:   make([]*errors.APIError, 0),
+		Groups:   groups,
 	})
 }
 
+func (self *AuthHandler) handleLogout(request *restful.Request, response *restful.Response) {
+	// Subject has to be read before the token is revoked.
+	subject := self.getTokenSubject(request)
+	if err := self.manager.Logout(request.HeaderParameter(client.JWETokenHeader)); err != nil {
+		self.audit(request, authApi.AuditLogout, subject, err.Error())
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	self.audit(request, authApi.AuditLogout, subject, "")
+	response.WriteHeader(http.StatusOK)
+}
+
+func (self *AuthHandler) handleElevate(request *restful.Request, response *restful.Response) {
+	elevationSpec := new(authApi.ElevationSpec)
+	if err := request.ReadEntity(elevationSpec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	self.limitLogin(request, response, &elevationSpec.LoginSpec, authApi.AuditSessionElevated,
+		authApi.AuditElevationFailed, func() (*authApi.AuthResponse, error) {
+			return self.manager.Elevate(request.HeaderParameter(client.JWETokenHeader), elevationSpec)
+		})
+}
+
+func (self *AuthHandler) handleEmbedToken(request *restful.Request, response *restful.Response) {
+	embedTokenSpec := new(authApi.EmbedTokenSpec)
+	if err := request.ReadEntity(embedTokenSpec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	embedTokenResponse, err := self.manager.EmbedToken(request.HeaderParameter(client.JWETokenHeader), embedTokenSpec)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, embedTokenResponse)
+}
+
 func (self *AuthHandler) handleLoginModes(request *restful.Request, response *restful.Response) {
 	response.WriteHeaderAndEntity(http.StatusOK, authApi.LoginModesResponse{Modes: self.manager.AuthenticationModes()})
 }
 
+func (self *AuthHandler) handleLoginNotice(request *restful.Request, response *restful.Response) {
+	response.WriteHeaderAndEntity(http.StatusOK, self.manager.LoginNotice())
+}
+
 func (self *AuthHandler) handleLoginSkippable(request *restful.Request, response *restful.Response) {
-	response.WriteHeaderAndEntity(http.StatusOK, authApi.LoginSkippableResponse{Skippable: self.manager.AuthenticationSkippable()})
+	response.WriteHeaderAndEntity(http.StatusOK, GetLoginSkippable(self.manager, self.cManager, self.fManager))
+}
+
+// GetLoginSkippable tells whether the login can be skipped and which identity is used by users who skip it.
+func GetLoginSkippable(manager authApi.AuthManager, cManager clientapi.ClientManager,
+	fManager featuresApi.FeatureGateManager) authApi.LoginSkippableResponse {
+	result := authApi.LoginSkippableResponse{
+		Skippable: manager.AuthenticationSkippable() && fManager.Enabled(featuresApi.SkipLogin),
+	}
+	if result.Skippable {
+		result.Identity = cManager.SkipLoginIdentity()
+	}
+
+	return result
+}
+
+func (self *AuthHandler) handleGetSessions(request *restful.Request, response *restful.Response) {
+	if !self.checkSessionAdmin(request, response) {
+		return
+	}
+
+	sessions, err := self.manager.Sessions()
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, authApi.SessionList{Sessions: sessions})
+}
+
+func (self *AuthHandler) handleExpireSession(request *restful.Request, response *restful.Response) {
+	if !self.checkSessionAdmin(request, response) {
+		return
+	}
+
+	if err := self.manager.ExpireSession(request.PathParameter("id")); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (self *AuthHandler) handleGetOwnSessions(request *restful.Request, response *restful.Response) {
+	sessions, err := self.manager.OwnSessions(request.HeaderParameter(client.JWETokenHeader))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, authApi.SessionList{Sessions: sessions})
+}
+
+func (self *AuthHandler) handleExpireOwnSession(request *restful.Request, response *restful.Response) {
+	err := self.manager.ExpireOwnSession(request.HeaderParameter(client.JWETokenHeader), request.PathParameter("id"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (self *AuthHandler) handleBeginWebAuthnRegistration(request *restful.Request, response *restful.Response) {
+	registration, err := self.manager.BeginWebAuthnRegistration(request.HeaderParameter(client.JWETokenHeader))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, registration)
+}
+
+func (self *AuthHandler) handleFinishWebAuthnRegistration(request *restful.Request, response *restful.Response) {
+	attestation := new(authApi.WebAuthnAttestation)
+	if err := request.ReadEntity(attestation); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	credential, err := self.manager.FinishWebAuthnRegistration(request.HeaderParameter(client.JWETokenHeader),
+		attestation)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	self.audit(request, authApi.AuditSecondFactorRegistered, self.getTokenSubject(request), "")
+	response.WriteHeaderAndEntity(http.StatusOK, credential)
+}
+
+func (self *AuthHandler) handleGetWebAuthnCredentials(request *restful.Request, response *restful.Response) {
+	credentials, err := self.manager.WebAuthnCredentials(request.HeaderParameter(client.JWETokenHeader))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, authApi.WebAuthnCredentialList{Credentials: credentials})
+}
+
+func (self *AuthHandler) handleDeleteWebAuthnCredential(request *restful.Request, response *restful.Response) {
+	err := self.manager.DeleteWebAuthnCredential(request.HeaderParameter(client.JWETokenHeader),
+		request.PathParameter("id"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	self.audit(request, authApi.AuditSecondFactorRemoved, self.getTokenSubject(request), "")
+	response.WriteHeader(http.StatusOK)
+}
+
+// Records source IP and user agent of the request in the session of the token issued for it, so users can recognize
+// their sessions. Tokens are valid regardless, so failures are only logged.
+func (self *AuthHandler) recordSessionClient(request *restful.Request, jweToken string) {
+	client := authApi.SessionClient{SourceIP: authApi.GetSourceIP(request.Request), UserAgent: request.Request.UserAgent()}
+	if err := self.manager.RecordSessionClient(jweToken, client); err != nil {
+		log.Printf("Could not record client of the session: %s", err)
+	}
+}
+
+// Sessions of all users are administered with Dashboard privileges, so only authenticated cluster administrators
+// can do it. Returns false if the error response has been written.
+func (self *AuthHandler) checkSessionAdmin(request *restful.Request, response *restful.Response) bool {
+	if _, err := self.cManager.ClientCmdConfig(request); err != nil {
+		errors.HandleInternalError(response, err)
+		return false
+	}
+
+	if !self.cManager.CanI(request, clientapi.ToClusterAdminAccessReview()) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			"only cluster administrators can administer Dashboard sessions"))
+		return false
+	}
+
+	return true
+}
+
+// Records authentication event caused by the request. Message is empty for successful events.
+func (self *AuthHandler) audit(request *restful.Request, event authApi.AuditEvent, subject, message string) {
+	if self.auditLogger == nil {
+		return
+	}
+
+	record := authApi.NewAuditRecord(event, subject, request.Request)
+	record.Message = message
+	self.auditLogger.Log(record)
+}
+
+// Returns subject of the token from the request header. Unknown is returned if the token can not be decrypted.
+func (self *AuthHandler) getTokenSubject(request *restful.Request) string {
+	clientConfig, err := self.cManager.ClientCmdConfig(request)
+	if err != nil {
+		return "unknown"
+	}
+
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return "unknown"
+	}
+
+	return clientapi.GetIdentity(cfg)
+}
+
+// Returns subject of the credentials from the login request. Kubeconfig files are not parsed before login.
+func getLoginSubject(spec *authApi.LoginSpec) string {
+	if len(spec.Username) == 0 && len(spec.Token) == 0 && len(spec.KubeConfig) > 0 {
+		return "kubeconfig"
+	}
+
+	return clientapi.GetIdentity(&rest.Config{Username: spec.Username, BearerToken: spec.Token})
 }
 
-// NewAuthHandler created AuthHandler instance.
-func NewAuthHandler(manager authApi.AuthManager) AuthHandler {
-	return AuthHandler{manager: manager}
+// NewAuthHandler created AuthHandler instance. Login can be skipped only if both auth manager and
+// SkipLogin feature gate allow it. Failed logins are limited according to the login limiter arguments. Client
+// manager is used to check that sessions are administered by cluster administrators. Authentication events are
+// recorded by given audit logger.
+func NewAuthHandler(manager authApi.AuthManager, cManager clientapi.ClientManager,
+	fManager featuresApi.FeatureGateManager, auditLogger authApi.AuditLogger) AuthHandler {
+	limiter := NewLoginLimiter(args.Holder.GetLoginMaxAttempts(),
+		time.Duration(args.Holder.GetLoginLockoutDuration())*time.Second,
+		time.Duration(args.Holder.GetLoginMaxLockoutDuration())*time.Second)
+	return AuthHandler{manager: manager, cManager: cManager, fManager: fManager, limiter: limiter,
+		auditLogger: auditLogger}
 }
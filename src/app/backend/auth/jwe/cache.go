@@ -0,0 +1,115 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwe
+
+import (
+	"container/list"
+	"crypto/rsa"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DefaultTokenCacheSize is the maximum number of decrypted tokens kept in memory.
+const DefaultTokenCacheSize = 1024
+
+// tokenCacheKey is a SHA-256 hash of the token. Raw tokens are never kept in the cache.
+type tokenCacheKey [sha256.Size]byte
+
+// tokenCacheEntry holds decrypted token together with the key used to decrypt it, so the entry stops
+// being valid as soon as the encryption key changes.
+type tokenCacheEntry struct {
+	hash     tokenCacheKey
+	authInfo *api.AuthInfo
+	groups   []string
+	key      *rsa.PrivateKey
+	// Zero value means that the token never expires.
+	expiry time.Time
+}
+
+// tokenCache is a bounded LRU cache of decrypted tokens. It is safe for concurrent use.
+type tokenCache struct {
+	size    int
+	entries map[tokenCacheKey]*list.Element
+	order   *list.List
+	mux     sync.Mutex
+}
+
+// get returns cached auth info of the token. Entry is dropped if it was decrypted with a key that is not among
+// given keys anymore.
+func (self *tokenCache) get(token string, keys ...*rsa.PrivateKey) (*tokenCacheEntry, bool) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	element, ok := self.entries[hashToken(token)]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*tokenCacheEntry)
+	for _, key := range keys {
+		if entry.key == key {
+			self.order.MoveToFront(element)
+			return entry, true
+		}
+	}
+
+	self.removeElement(element)
+	return nil, false
+}
+
+// add stores auth info and groups of the token, evicting the least recently used entry if the cache is full.
+func (self *tokenCache) add(token string, authInfo *api.AuthInfo, groups []string, key *rsa.PrivateKey,
+	expiry time.Time) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	hash := hashToken(token)
+	if element, ok := self.entries[hash]; ok {
+		self.removeElement(element)
+	}
+
+	entry := &tokenCacheEntry{hash: hash, authInfo: authInfo.DeepCopy(), groups: groups, key: key, expiry: expiry}
+	self.entries[hash] = self.order.PushFront(entry)
+	for self.order.Len() > self.size {
+		self.removeElement(self.order.Back())
+	}
+}
+
+// remove drops the token from the cache, i.e. after it was refreshed. It does not revoke the token, it is
+// decrypted again when it is used before it expires.
+func (self *tokenCache) remove(token string) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if element, ok := self.entries[hashToken(token)]; ok {
+		self.removeElement(element)
+	}
+}
+
+func (self *tokenCache) removeElement(element *list.Element) {
+	self.order.Remove(element)
+	delete(self.entries, element.Value.(*tokenCacheEntry).hash)
+}
+
+func hashToken(token string) tokenCacheKey {
+	return sha256.Sum256([]byte(token))
+}
+
+func newTokenCache(size int) *tokenCache {
+	return &tokenCache{size: size, entries: make(map[tokenCacheKey]*list.Element), order: list.New()}
+}
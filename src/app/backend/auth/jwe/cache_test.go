@@ -0,0 +1,88 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwe
+
+import (
+	"crypto/rsa"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestTokenCache(t *testing.T) {
+	key, otherKey := &rsa.PrivateKey{}, &rsa.PrivateKey{}
+	cache := newTokenCache(2)
+
+	cache.add("a", &api.AuthInfo{Token: "a"}, nil, key, time.Time{})
+	cache.add("b", &api.AuthInfo{Token: "b"}, nil, key, time.Time{})
+	// Use "a", so "b" becomes the least recently used entry.
+	if _, ok := cache.get("a", key); !ok {
+		t.Fatal("expected token a to be cached")
+	}
+	cache.add("c", &api.AuthInfo{Token: "c"}, nil, key, time.Time{})
+
+	if _, ok := cache.get("b", key); ok {
+		t.Error("expected least recently used token b to be evicted")
+	}
+
+	entry, ok := cache.get("c", key)
+	if !ok || !reflect.DeepEqual(entry.authInfo, &api.AuthInfo{Token: "c"}) {
+		t.Errorf("expected token c to be cached, got %v", entry)
+	}
+
+	if _, ok := cache.get("a", otherKey); ok {
+		t.Error("expected token a not to be returned after the key has changed")
+	}
+	if _, ok := cache.get("a", key); ok {
+		t.Error("expected token a to be dropped after the key has changed")
+	}
+
+	cache.remove("c")
+	if _, ok := cache.get("c", key); ok || cache.order.Len() != 0 || len(cache.entries) != 0 {
+		t.Error("expected cache to be empty")
+	}
+}
+
+func TestJweTokenManager_DecryptCached(t *testing.T) {
+	tokenManager := getTokenManager().(*jweTokenManager)
+	authInfo := api.AuthInfo{Token: "test-token"}
+	token, err := tokenManager.Generate(authInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		decrypted, err := tokenManager.Decrypt(token)
+		if err != nil || !reflect.DeepEqual(*decrypted, authInfo) {
+			t.Fatalf("expected %v to be decrypted, got %v, %v", authInfo, decrypted, err)
+		}
+		// Modifying the result must not affect the cached entry.
+		decrypted.Token = "changed"
+	}
+
+	if _, ok := tokenManager.cache.get(token, tokenManager.keyHolder.Key()); !ok {
+		t.Error("expected decrypted token to be cached")
+	}
+
+	if _, err := tokenManager.Refresh(token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := tokenManager.cache.get(token, tokenManager.keyHolder.Key()); ok {
+		t.Error("expected refreshed token to be removed from the cache")
+	}
+}
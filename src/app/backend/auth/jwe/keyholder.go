@@ -17,8 +17,10 @@ package jwe
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	jose "gopkg.in/square/go-jose.v2"
 	v1 "k8s.io/api/core/v1"
@@ -32,12 +34,20 @@ import (
 	syncApi "github.com/kubernetes/dashboard/src/app/backend/sync/api"
 )
 
-// Entries held by resource used to synchronize encryption key data.
+// Entries held by resource used to synchronize encryption key data. Previous keys are stored under the same
+// entries with their index appended, i.e. 'priv.1' and 'pub.1' hold the key used before the last rotation.
 const (
 	holderMapKeyEntry  = "priv"
 	holderMapCertEntry = "pub"
 )
 
+const (
+	// Annotation of the synchronized secret holding the time of the last key rotation.
+	keyRotatedAnnotation = "dashboard.kubernetes.io/key-rotated-at"
+	// Interval in which every replica checks whether the key is due for rotation.
+	keyRotationCheckInterval = time.Minute
+)
+
 // KeyHolder is responsible for generating, storing and synchronizing encryption key used for token
 // generation/decryption.
 type KeyHolder interface {
@@ -45,6 +55,9 @@ type KeyHolder interface {
 	Encrypter() jose.Encrypter
 	// Returns encryption key that can be used to decrypt data.
 	Key() *rsa.PrivateKey
+	// Returns current encryption key followed by previous keys, which can still decrypt tokens generated before
+	// the key was rotated.
+	Keys() []*rsa.PrivateKey
 	// Forces refresh of encryption key synchronized with kubernetes resource (secret).
 	Refresh()
 }
@@ -52,7 +65,9 @@ type KeyHolder interface {
 // Implements KeyHolder interface
 type rsaKeyHolder struct {
 	// 256-byte random RSA key pair. Synced with a key saved in a secret.
-	key          *rsa.PrivateKey
+	key *rsa.PrivateKey
+	// Keys used before the last rotations, newest first.
+	previousKeys []*rsa.PrivateKey
 	synchronizer syncApi.Synchronizer
 	mux          sync.Mutex
 }
@@ -78,6 +93,13 @@ func (self *rsaKeyHolder) Key() *rsa.PrivateKey {
 	return self.key
 }
 
+// Keys implements key holder interface. See KeyHolder for more information.
+func (self *rsaKeyHolder) Keys() []*rsa.PrivateKey {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	return append([]*rsa.PrivateKey{self.key}, self.previousKeys...)
+}
+
 // Refresh implements key holder interface. See KeyHolder for more information.
 func (self *rsaKeyHolder) Refresh() {
 	self.synchronizer.Refresh()
@@ -88,7 +110,7 @@ func (self *rsaKeyHolder) Refresh() {
 // is created or updated.
 func (self *rsaKeyHolder) update(obj runtime.Object) {
 	secret := obj.(*v1.Secret)
-	priv, err := ParseRSAKey(string(secret.Data[holderMapKeyEntry]), string(secret.Data[holderMapCertEntry]))
+	priv, previous, err := parseKeys(secret)
 	if err != nil {
 		// Secret was probably tampered with. Update it based on local key.
 		err := self.synchronizer.Update(self.getEncryptionKeyHolder())
@@ -102,6 +124,7 @@ func (self *rsaKeyHolder) update(obj runtime.Object) {
 	self.mux.Lock()
 	defer self.mux.Unlock()
 	self.key = priv
+	self.previousKeys = previous
 }
 
 // Handler function executed by synchronizer used to store encryption key. It is called whenever watched object
@@ -136,21 +159,138 @@ func (self *rsaKeyHolder) init() {
 	}
 }
 
+// Periodically checks whether the key is due for rotation until the process exits.
+func (self *rsaKeyHolder) rotateEvery(period time.Duration, history int) {
+	interval := keyRotationCheckInterval
+	if period < interval {
+		interval = period
+	}
+
+	for range time.Tick(interval) {
+		if err := self.rotateIfDue(period, history, time.Now()); err != nil {
+			log.Printf("Failed to rotate JWE encryption key: %s", err)
+		}
+	}
+}
+
+// Generates a new key if the current one is older than given period and keeps up to history previous keys.
+// Replicas coordinate through resourceVersion of the secret. Update made by a replica that did not see the latest
+// rotation fails with a conflict and the replica takes the key generated by the other one instead.
+func (self *rsaKeyHolder) rotateIfDue(period time.Duration, history int, now time.Time) error {
+	self.synchronizer.Refresh()
+	obj := self.synchronizer.Get()
+	if obj == nil {
+		// Secret is recreated from the local key once its deletion is observed.
+		return nil
+	}
+
+	secret := obj.(*v1.Secret).DeepCopy()
+	if now.Sub(getRotationTime(secret)) < period {
+		return nil
+	}
+
+	current, previous, err := parseKeys(secret)
+	if err != nil {
+		return err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+
+	previous = append([]*rsa.PrivateKey{current}, previous...)
+	if len(previous) > history {
+		previous = previous[:history]
+	}
+
+	secret.Data = getKeyEntries(key, previous)
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[keyRotatedAnnotation] = now.UTC().Format(time.RFC3339)
+
+	if err := self.synchronizer.Update(secret); err != nil {
+		if !errors.IsConflict(err) {
+			return err
+		}
+
+		log.Print("JWE encryption key has been rotated by another replica")
+		self.Refresh()
+		return nil
+	}
+
+	log.Print("JWE encryption key has been rotated")
+	self.update(secret)
+	return nil
+}
+
 func (self *rsaKeyHolder) getEncryptionKeyHolder() runtime.Object {
-	priv, pub := ExportRSAKeyOrDie(self.Key())
+	keys := self.Keys()
 	return &v1.Secret{
 		ObjectMeta: metaV1.ObjectMeta{
 			Namespace: args.Holder.GetNamespace(),
 			Name:      authApi.EncryptionKeyHolderName,
 		},
 
-		Data: map[string][]byte{
-			holderMapKeyEntry:  []byte(priv),
-			holderMapCertEntry: []byte(pub),
-		},
+		Data: getKeyEntries(keys[0], keys[1:]),
 	}
 }
 
+// Returns secret entries holding the current key and the previous keys.
+func getKeyEntries(current *rsa.PrivateKey, previous []*rsa.PrivateKey) map[string][]byte {
+	result := make(map[string][]byte)
+	for i, key := range append([]*rsa.PrivateKey{current}, previous...) {
+		priv, pub := ExportRSAKeyOrDie(key)
+		result[getKeyEntryName(holderMapKeyEntry, i)] = []byte(priv)
+		result[getKeyEntryName(holderMapCertEntry, i)] = []byte(pub)
+	}
+
+	return result
+}
+
+// Parses the current key and the previous keys from the secret. Previous keys that can not be parsed are skipped,
+// tokens encrypted with them can not be decrypted anymore.
+func parseKeys(secret *v1.Secret) (*rsa.PrivateKey, []*rsa.PrivateKey, error) {
+	key, err := ParseRSAKey(string(secret.Data[holderMapKeyEntry]), string(secret.Data[holderMapCertEntry]))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previous := make([]*rsa.PrivateKey, 0)
+	for i := 1; ; i++ {
+		priv, exists := secret.Data[getKeyEntryName(holderMapKeyEntry, i)]
+		if !exists {
+			break
+		}
+
+		previousKey, err := ParseRSAKey(string(priv), string(secret.Data[getKeyEntryName(holderMapCertEntry, i)]))
+		if err != nil {
+			log.Printf("Skipping invalid previous JWE encryption key %d: %s", i, err)
+			continue
+		}
+
+		previous = append(previous, previousKey)
+	}
+
+	return key, previous, nil
+}
+
+func getKeyEntryName(entry string, index int) string {
+	if index == 0 {
+		return entry
+	}
+	return fmt.Sprintf("%s.%d", entry, index)
+}
+
+// Returns time of the last key rotation, or creation of the secret if the key has never been rotated.
+func getRotationTime(secret *v1.Secret) time.Time {
+	if rotated, err := time.Parse(time.RFC3339, secret.Annotations[keyRotatedAnnotation]); err == nil {
+		return rotated
+	}
+	return secret.CreationTimestamp.Time
+}
+
 // Generates encryption key used to encrypt token payload.
 func (self *rsaKeyHolder) initEncryptionKey() {
 	log.Print("Generating JWE encryption key")
@@ -172,5 +312,9 @@ func NewRSAKeyHolder(synchronizer syncApi.Synchronizer) KeyHolder {
 	}
 
 	holder.init()
+	if period := args.Holder.GetEncryptionKeyRotationPeriod(); period > 0 {
+		go holder.rotateEvery(time.Duration(period)*time.Second, args.Holder.GetEncryptionKeyHistory())
+	}
+
 	return holder
 }
@@ -15,10 +15,15 @@
 package jwe
 
 import (
+	"crypto/rsa"
+	"reflect"
 	"testing"
+	"time"
 
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	"github.com/kubernetes/dashboard/src/app/backend/sync"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 func getKeyHolder() KeyHolder {
@@ -47,3 +52,55 @@ func TestRsaKeyHolder_Key(t *testing.T) {
 		t.Fatalf("Key(): Expected key not to be nil")
 	}
 }
+
+func isSameKey(a, b *rsa.PrivateKey) bool {
+	return a.N.Cmp(b.N) == 0
+}
+
+func TestRsaKeyHolder_RotateIfDue(t *testing.T) {
+	// Rotation reads the stored secret, so the synchronizer has to watch the secret the key is stored in.
+	syncManager := sync.NewSynchronizerManager(fake.NewSimpleClientset())
+	holder := NewRSAKeyHolder(syncManager.Secret("", authApi.EncryptionKeyHolderName)).(*rsaKeyHolder)
+	manager := NewJWETokenManager(holder, getRevocationList(), getSessionList())
+	authInfo := api.AuthInfo{Token: "test-token"}
+	token, err := manager.Generate(authInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	original := holder.Key()
+	if err := holder.rotateIfDue(time.Hour, 1, now); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := holder.Key()
+	if keys := holder.Keys(); isSameKey(rotated, original) || len(keys) != 2 || !isSameKey(keys[1], original) {
+		t.Fatalf("rotateIfDue(): Expected key to be rotated and the original key to be kept")
+	}
+
+	if decrypted, err := manager.Decrypt(token); err != nil || !reflect.DeepEqual(*decrypted, authInfo) {
+		t.Fatalf("Decrypt(): Expected token generated before rotation to be decrypted, but got %v, %v",
+			decrypted, err)
+	}
+
+	if err := holder.rotateIfDue(time.Hour, 1, now.Add(30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !isSameKey(holder.Key(), rotated) {
+		t.Fatalf("rotateIfDue(): Expected key not to be rotated before the rotation period passes")
+	}
+
+	if err := holder.rotateIfDue(time.Hour, 1, now.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := holder.Keys(); len(keys) != 2 || !isSameKey(keys[1], rotated) {
+		t.Fatalf("rotateIfDue(): Expected only the last previous key to be kept, but got %d keys", len(keys))
+	}
+
+	if _, err := manager.Decrypt(token); err == nil {
+		t.Fatalf("Decrypt(): Expected token encrypted with a dropped key not to be decrypted")
+	}
+}
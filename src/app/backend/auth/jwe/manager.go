@@ -15,20 +15,42 @@
 package jwe
 
 import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"log"
 	"time"
 
+	"golang.org/x/crypto/hkdf"
 	jose "gopkg.in/square/go-jose.v2"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd/api"
 
+	"github.com/kubernetes/dashboard/src/app/backend/args"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/sync"
+	syncApi "github.com/kubernetes/dashboard/src/app/backend/sync/api"
+	"github.com/kubernetes/dashboard/src/app/backend/sync/kms"
+	"github.com/kubernetes/dashboard/src/app/backend/sync/vault"
 )
 
+func init() {
+	authApi.RegisterTokenManager(authApi.DefaultTokenManager, newTokenManagerWithSecretKeyHolder)
+}
+
 // Implements TokenManager interface
 type jweTokenManager struct {
-	keyHolder KeyHolder
-	tokenTTL  time.Duration
+	keyHolder   KeyHolder
+	revocations RevocationList
+	sessions    SessionList
+	tokenTTL    time.Duration
+	maxLifetime time.Duration
+	// Decrypting a token is expensive and it happens on every request, so decrypted tokens are cached.
+	cache *tokenCache
 }
 
 // AdditionalAuthData contains information required to validate token. It is integrity protected.
@@ -46,45 +68,173 @@ const (
 	IAT Claim = "iat"
 	// EXP claim is part of token AAD header. It represents token expiration time.
 	EXP Claim = "exp"
+	// AUTH_TIME claim is part of token AAD header. It represents time of the login and is kept when token is refreshed.
+	AUTH_TIME Claim = "auth_time"
+	// SCOPE claim is part of embed token AAD header. It contains JSON encoded EmbedScope the token is restricted to.
+	SCOPE Claim = "scope"
+	// ELEVATED_UNTIL claim is part of elevated token AAD header. It represents expiration time of the elevation.
+	ELEVATED_UNTIL Claim = "elevated_until"
+	// SID claim is part of token AAD header. It represents ID of the session and is kept when token is refreshed.
+	SID Claim = "sid"
+	// PAYLOAD claim is part of token AAD header. It is set to 'session' for tokens whose payload contains AuthInfo
+	// together with other data of the session, i.e. groups of the user.
+	PAYLOAD Claim = "payload"
 )
 
+// Value of the PAYLOAD claim of tokens with sessionPayload.
+const sessionPayloadType = "session"
+
+// Payload of elevated tokens and tokens carrying groups of the user. It contains AuthInfo of the session, the
+// stronger one used until the elevation expires and groups of the user.
+type sessionPayload struct {
+	AuthInfo api.AuthInfo  `json:"authInfo"`
+	Elevated *api.AuthInfo `json:"elevated,omitempty"`
+	Groups   []string      `json:"groups,omitempty"`
+}
+
 // Generate and encrypt JWE token based on provided AuthInfo structure. AuthInfo will be embedded in a token payload and
 // encrypted with autogenerated signing key.
 func (self *jweTokenManager) Generate(authInfo api.AuthInfo) (string, error) {
-	marshalledAuthInfo, err := json.Marshal(authInfo)
+	return self.generate(&authApi.ElevatedAuthInfo{AuthInfo: authInfo}, time.Now(), newSessionID())
+}
+
+// GenerateWithGroups implements group token manager interface. Groups are encrypted together with AuthInfo and kept
+// when token is refreshed or elevated. See GroupTokenManager for more information.
+func (self *jweTokenManager) GenerateWithGroups(authInfo api.AuthInfo, groups []string) (string, error) {
+	return self.generate(&authApi.ElevatedAuthInfo{AuthInfo: authInfo, Groups: groups}, time.Now(), newSessionID())
+}
+
+// Groups implements group token manager interface. See GroupTokenManager for more information.
+func (self *jweTokenManager) Groups(jweToken string) ([]string, error) {
+	info, err := self.DecryptElevated(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.Groups, nil
+}
+
+// Generates token for a session started at authTime. Elevated sessions get elevated token. Generated token is
+// recorded in the session list.
+func (self *jweTokenManager) generate(info *authApi.ElevatedAuthInfo, authTime time.Time, sessionID string) (string,
+	error) {
+	var payload interface{} = info.AuthInfo
+	wrapped := info.Elevated != nil || len(info.Groups) > 0
+	if wrapped {
+		payload = sessionPayload{AuthInfo: info.AuthInfo, Elevated: info.Elevated, Groups: info.Groups}
+	}
+
+	marshalledPayload, err := json.Marshal(payload)
 	if err != nil {
 		return "", err
 	}
 
-	jweObject, err := self.getEncrypter().EncryptWithAuthData(marshalledAuthInfo, self.generateAAD())
+	now := time.Now()
+	aad, expiry := self.generateAAD(now, authTime, info.ElevatedUntil, sessionID, wrapped)
+	jweObject, err := self.getEncrypter().EncryptWithAuthData(marshalledPayload, aad)
 	if err != nil {
 		return "", err
 	}
 
-	return jweObject.FullSerialize(), nil
+	token := jweObject.FullSerialize()
+	session := authApi.Session{
+		ID:          sessionID,
+		Subject:     getSessionSubject(info.AuthInfo),
+		LoginTime:   authTime,
+		LastRefresh: now,
+	}
+
+	// Tokens are valid regardless of the session list, so failing to record them must not prevent the login.
+	if err = self.sessions.Record(session, getTokenID(token), expiry); err != nil {
+		log.Printf("Could not record token of session %s: %s", sessionID, err)
+	}
+
+	return token, nil
 }
 
-// Decrypt provides token and returns AuthInfo structure saved in a token payload.
+// Decrypt provides token and returns AuthInfo structure saved in a token payload. Elevated AuthInfo is returned
+// for elevated tokens until the elevation expires.
 func (self *jweTokenManager) Decrypt(jweToken string) (*api.AuthInfo, error) {
+	info, err := self.DecryptElevated(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.Current(time.Now()), nil
+}
+
+// DecryptElevated implements elevation token manager interface. See ElevationTokenManager for more information.
+func (self *jweTokenManager) DecryptElevated(jweToken string) (*authApi.ElevatedAuthInfo, error) {
+	if self.revocations.IsRevoked(getTokenID(jweToken)) {
+		self.cache.remove(jweToken)
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	if entry, ok := self.cache.get(jweToken, self.keyHolder.Keys()...); ok {
+		if !entry.expiry.IsZero() && time.Now().After(entry.expiry) {
+			self.cache.remove(jweToken)
+			return nil, errors.NewTokenExpired(errors.MsgTokenExpiredError)
+		}
+
+		return &authApi.ElevatedAuthInfo{AuthInfo: *entry.authInfo.DeepCopy(), Groups: entry.groups}, nil
+	}
+
 	jweTokenObject, err := self.validate(jweToken)
 	if err != nil {
 		return nil, err
 	}
 
-	decrypted, err := jweTokenObject.Decrypt(self.keyHolder.Key())
-	if err == jose.ErrCryptoFailure {
-		// Force key refresh and try to decrypt again
-		self.keyHolder.Refresh()
-		decrypted, err = jweTokenObject.Decrypt(self.keyHolder.Key())
+	if isEmbedToken(jweTokenObject) {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
 	}
 
+	decrypted, key, err := self.decrypt(jweTokenObject)
 	if err != nil {
 		return nil, err
 	}
 
-	authInfo := new(api.AuthInfo)
-	err = json.Unmarshal(decrypted, authInfo)
-	return authInfo, err
+	info, err := unmarshalPayload(jweTokenObject, decrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache holds a single AuthInfo per token, so elevated tokens, which change it once the elevation expires, are
+	// not cached. They are short-lived anyway.
+	if expiry, ok := self.getExpiry(jweTokenObject); ok && info.Elevated == nil {
+		self.cache.add(jweToken, &info.AuthInfo, info.Groups, key, expiry)
+	}
+
+	return info, nil
+}
+
+// Elevate implements elevation token manager interface. See ElevationTokenManager for more information.
+func (self *jweTokenManager) Elevate(jweToken string, elevated api.AuthInfo, until time.Time) (string, error) {
+	if self.revocations.IsRevoked(getTokenID(jweToken)) {
+		return "", errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	jweTokenObject, err := self.validate(jweToken)
+	if err != nil {
+		return "", err
+	}
+
+	if isEmbedToken(jweTokenObject) {
+		return "", errors.NewInvalid("Can not elevate token. Embed tokens can not be elevated.")
+	}
+
+	decrypted, _, err := self.decrypt(jweTokenObject)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := unmarshalPayload(jweTokenObject, decrypted)
+	if err != nil {
+		return "", err
+	}
+
+	info.Elevated = &elevated
+	info.ElevatedUntil = until
+	return self.generate(info, getAuthTime(jweTokenObject), getSessionID(jweTokenObject))
 }
 
 // Refresh implements token manager interface. See TokenManager for more information.
@@ -93,23 +243,183 @@ func (self *jweTokenManager) Refresh(jweToken string) (string, error) {
 		return "", errors.NewInvalid("Can not refresh token. No token provided.")
 	}
 
+	if self.revocations.IsRevoked(getTokenID(jweToken)) {
+		return "", errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
 	jweTokenObject, err := self.validate(jweToken)
 	if err != nil {
 		return "", err
 	}
 
-	decrypted, err := jweTokenObject.Decrypt(self.keyHolder.Key())
+	if isEmbedToken(jweTokenObject) {
+		return "", errors.NewInvalid("Can not refresh token. Embed tokens can not be refreshed.")
+	}
+
+	decrypted, _, err := self.decrypt(jweTokenObject)
 	if err != nil {
 		return "", err
 	}
 
-	authInfo := new(api.AuthInfo)
-	err = json.Unmarshal(decrypted, authInfo)
+	info, err := unmarshalPayload(jweTokenObject, decrypted)
 	if err != nil {
 		return "", errors.NewInvalid("Token refresh error. Could not unmarshal token payload.")
 	}
 
-	return self.Generate(*authInfo)
+	authTime := getAuthTime(jweTokenObject)
+	if self.maxLifetime > 0 && time.Now().Sub(authTime) >= self.maxLifetime {
+		return "", errors.NewTokenExpired(errors.MsgTokenExpiredError)
+	}
+
+	// Refreshed token keeps the elevation only until it expires.
+	if !info.IsElevated(time.Now()) {
+		info = &authApi.ElevatedAuthInfo{AuthInfo: info.AuthInfo, Groups: info.Groups}
+	}
+
+	// Tokens are stateless, so the old token is only dropped from the cache and stays valid until it expires.
+	self.cache.remove(jweToken)
+	return self.generate(info, authTime, getSessionID(jweTokenObject))
+}
+
+// GenerateEmbed implements embed token manager interface. Scope is saved in the token AAD header, so it is integrity
+// protected. Embed tokens always expire, regardless of the token TTL.
+func (self *jweTokenManager) GenerateEmbed(authInfo api.AuthInfo, scope authApi.EmbedScope,
+	ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", errors.NewInvalid("Embed token expiration time has to be greater than 0.")
+	}
+
+	marshalledAuthInfo, err := json.Marshal(authInfo)
+	if err != nil {
+		return "", err
+	}
+
+	marshalledScope, err := json.Marshal(scope)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	rawAAD, _ := json.Marshal(AdditionalAuthData{
+		IAT:   now.Format(timeFormat),
+		EXP:   now.Add(ttl).Format(timeFormat),
+		SCOPE: string(marshalledScope),
+	})
+
+	jweObject, err := self.getEncrypter().EncryptWithAuthData(marshalledAuthInfo, rawAAD)
+	if err != nil {
+		return "", err
+	}
+
+	return jweObject.FullSerialize(), nil
+}
+
+// DecryptEmbed implements embed token manager interface. See EmbedTokenManager for more information.
+func (self *jweTokenManager) DecryptEmbed(jweToken string) (*api.AuthInfo, *authApi.EmbedScope, error) {
+	if self.revocations.IsRevoked(getTokenID(jweToken)) {
+		return nil, nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	jweTokenObject, err := jose.ParseEncrypted(jweToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aad := AdditionalAuthData{}
+	if err = json.Unmarshal(jweTokenObject.GetAuthData(), &aad); err != nil {
+		return nil, nil, errors.NewInvalid("Token validation error. Could not unmarshal AAD.")
+	}
+
+	if len(aad[SCOPE]) == 0 {
+		return nil, nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	if self.isExpired(aad[IAT], aad[EXP]) {
+		return nil, nil, errors.NewTokenExpired(errors.MsgTokenExpiredError)
+	}
+
+	// Decryption verifies integrity of the AAD header, so the scope can be trusted only after it succeeds.
+	decrypted, _, err := self.decrypt(jweTokenObject)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scope := new(authApi.EmbedScope)
+	if err = json.Unmarshal([]byte(aad[SCOPE]), scope); err != nil {
+		return nil, nil, errors.NewInvalid("Token validation error. Could not unmarshal token scope.")
+	}
+
+	authInfo := new(api.AuthInfo)
+	if err = json.Unmarshal(decrypted, authInfo); err != nil {
+		return nil, nil, err
+	}
+
+	return authInfo, scope, nil
+}
+
+// Revoke implements token manager interface. See TokenManager for more information. Embed tokens can be revoked
+// as well.
+func (self *jweTokenManager) Revoke(jweToken string) error {
+	if len(jweToken) == 0 {
+		return errors.NewInvalid("Can not revoke token. No token provided.")
+	}
+
+	jweTokenObject, err := jose.ParseEncrypted(jweToken)
+	if err != nil {
+		return err
+	}
+
+	// Only tokens generated by Dashboard can be revoked, so the revocation list can not be filled with garbage.
+	if _, _, err = self.decrypt(jweTokenObject); err != nil {
+		return err
+	}
+
+	// Expiration time is zero for tokens that never expire.
+	aad := AdditionalAuthData{}
+	_ = json.Unmarshal(jweTokenObject.GetAuthData(), &aad)
+	expiry, _ := time.Parse(timeFormat, aad[EXP])
+	if !expiry.IsZero() && time.Now().After(expiry) {
+		return nil
+	}
+
+	self.cache.remove(jweToken)
+	if err = self.revocations.Revoke(getTokenID(jweToken), expiry); err != nil {
+		return err
+	}
+
+	// Logout ends the session, so tokens refreshed before the revoked one can not be used anymore either.
+	if sessionID := aad[SID]; len(sessionID) > 0 && self.sessions.Tokens(sessionID) != nil {
+		return self.ExpireSession(sessionID)
+	}
+
+	return nil
+}
+
+// Sessions implements session token manager interface. See SessionTokenManager for more information.
+func (self *jweTokenManager) Sessions() ([]authApi.Session, error) {
+	return self.sessions.List(time.Now()), nil
+}
+
+// ExpireSession implements session token manager interface. See SessionTokenManager for more information.
+func (self *jweTokenManager) ExpireSession(id string) error {
+	tokens := self.sessions.Tokens(id)
+	if tokens == nil {
+		return errors.NewNotFound("Session not found.")
+	}
+
+	// Session is forgotten only after all of its tokens are revoked, so failed expiration can be retried.
+	now := time.Now()
+	for tokenID, expiry := range tokens {
+		if (!expiry.IsZero() && !expiry.After(now)) || self.revocations.IsRevoked(tokenID) {
+			continue
+		}
+
+		if err := self.revocations.Revoke(tokenID, expiry); err != nil {
+			return err
+		}
+	}
+
+	return self.sessions.Remove(id)
 }
 
 // SetTokenTTL implements token manager interface. See TokenManager for more information.
@@ -121,6 +431,146 @@ func (self *jweTokenManager) SetTokenTTL(ttl time.Duration) {
 	self.tokenTTL = ttl * time.Second
 }
 
+// SetTokenMaxLifetime implements token manager interface. See TokenManager for more information.
+func (self *jweTokenManager) SetTokenMaxLifetime(maxLifetime time.Duration) {
+	if maxLifetime < 0 {
+		maxLifetime = 0
+	}
+
+	self.maxLifetime = maxLifetime * time.Second
+}
+
+// SessionOf implements session token manager interface. See SessionTokenManager for more information.
+func (self *jweTokenManager) SessionOf(jweToken string) (*authApi.Session, error) {
+	// Token is decrypted first, so its AAD header, which contains ID of the session, can be trusted.
+	if _, err := self.DecryptElevated(jweToken); err != nil {
+		return nil, err
+	}
+
+	jweTokenObject, err := jose.ParseEncrypted(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	aad := AdditionalAuthData{}
+	_ = json.Unmarshal(jweTokenObject.GetAuthData(), &aad)
+	for _, session := range self.sessions.List(time.Now()) {
+		if len(aad[SID]) > 0 && session.ID == aad[SID] {
+			return &session, nil
+		}
+	}
+
+	return nil, errors.NewNotFound("Session not found.")
+}
+
+// SetSessionClient implements session token manager interface. See SessionTokenManager for more information.
+func (self *jweTokenManager) SetSessionClient(id string, client authApi.SessionClient) error {
+	return self.sessions.SetClient(id, client)
+}
+
+// EncryptState implements state token manager interface. Owner is saved in the AAD header, so state can not be
+// moved to another owner without breaking its integrity. State is encrypted with a key derived from the token
+// encryption key, so it can never be decrypted as a token and the other way around. See StateTokenManager for more
+// information.
+func (self *jweTokenManager) EncryptState(owner string, state []byte) (string, error) {
+	key, err := deriveStateKey(self.keyHolder.Key())
+	if err != nil {
+		return "", err
+	}
+
+	encrypter, err := jose.NewEncrypter(jose.A256GCM, jose.Recipient{Algorithm: jose.DIRECT, Key: key}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	jweObject, err := encrypter.EncryptWithAuthData(state, []byte(owner))
+	if err != nil {
+		return "", err
+	}
+
+	return jweObject.FullSerialize(), nil
+}
+
+// DecryptState implements state token manager interface. See StateTokenManager for more information.
+func (self *jweTokenManager) DecryptState(owner, encrypted string) ([]byte, error) {
+	jweObject, err := jose.ParseEncrypted(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(jweObject.GetAuthData()) != owner {
+		return nil, errors.NewInvalid("State belongs to another owner.")
+	}
+
+	// Decryption verifies integrity of the AAD header, so the owner can be trusted only after it succeeds.
+	state, err := decryptStateWithKeys(jweObject, self.keyHolder.Keys())
+	if err == jose.ErrCryptoFailure {
+		// Force key refresh and try to decrypt again
+		self.keyHolder.Refresh()
+		state, err = decryptStateWithKeys(jweObject, self.keyHolder.Keys())
+	}
+
+	return state, err
+}
+
+func decryptStateWithKeys(jwe *jose.JSONWebEncryption, keys []*rsa.PrivateKey) ([]byte, error) {
+	if jwe.Header.Algorithm != string(jose.DIRECT) {
+		return nil, errors.NewInvalid("State validation error. Unexpected algorithm.")
+	}
+
+	err := jose.ErrCryptoFailure
+	for _, rsaKey := range keys {
+		key, keyErr := deriveStateKey(rsaKey)
+		if keyErr != nil {
+			return nil, keyErr
+		}
+
+		var decrypted []byte
+		if decrypted, err = jwe.Decrypt(key); err != jose.ErrCryptoFailure {
+			return decrypted, err
+		}
+	}
+
+	return nil, err
+}
+
+// Derives the 256-bit key used to encrypt state from given token encryption key, so that both share rotation and
+// synchronization between replicas, but never encrypt data of each other.
+func deriveStateKey(key *rsa.PrivateKey) ([]byte, error) {
+	stateKey := make([]byte, 32)
+	reader := hkdf.New(sha256.New, x509.MarshalPKCS1PrivateKey(key), nil, []byte("state"))
+	if _, err := io.ReadFull(reader, stateKey); err != nil {
+		return nil, err
+	}
+
+	return stateKey, nil
+}
+
+// Decrypts the token with the current key or one of the previous keys and returns the key that decrypted it. Keys
+// are refreshed once if none of them can decrypt the token, i.e. because another replica has rotated the key.
+func (self *jweTokenManager) decrypt(jwe *jose.JSONWebEncryption) ([]byte, *rsa.PrivateKey, error) {
+	decrypted, key, err := decryptWithKeys(jwe, self.keyHolder.Keys())
+	if err == jose.ErrCryptoFailure {
+		// Force key refresh and try to decrypt again
+		self.keyHolder.Refresh()
+		decrypted, key, err = decryptWithKeys(jwe, self.keyHolder.Keys())
+	}
+
+	return decrypted, key, err
+}
+
+func decryptWithKeys(jwe *jose.JSONWebEncryption, keys []*rsa.PrivateKey) ([]byte, *rsa.PrivateKey, error) {
+	err := jose.ErrCryptoFailure
+	for _, key := range keys {
+		var decrypted []byte
+		if decrypted, err = jwe.Decrypt(key); err != jose.ErrCryptoFailure {
+			return decrypted, key, err
+		}
+	}
+
+	return nil, nil, err
+}
+
 func (self *jweTokenManager) getEncrypter() jose.Encrypter {
 	return self.keyHolder.Encrypter()
 }
@@ -132,7 +582,7 @@ func (self *jweTokenManager) validate(jweToken string) (*jose.JSONWebEncryption,
 		return nil, err
 	}
 
-	if self.tokenTTL > 0 {
+	if self.tokenTTL > 0 || self.maxLifetime > 0 {
 		aad := AdditionalAuthData{}
 		err = json.Unmarshal(jwe.GetAuthData(), &aad)
 		if err != nil {
@@ -164,22 +614,204 @@ func (self *jweTokenManager) isExpired(iatStr, expStr string) bool {
 	return iat.Add(age).After(exp)
 }
 
-func (self *jweTokenManager) generateAAD() []byte {
-	now := time.Now()
+// Returns expiration time of the token or zero time if tokens do not expire. False is returned if expiration
+// time could not be read, such tokens should not be cached.
+func (self *jweTokenManager) getExpiry(jwe *jose.JSONWebEncryption) (time.Time, bool) {
+	if self.tokenTTL == 0 && self.maxLifetime == 0 {
+		return time.Time{}, true
+	}
+
+	aad := AdditionalAuthData{}
+	if err := json.Unmarshal(jwe.GetAuthData(), &aad); err != nil {
+		return time.Time{}, false
+	}
+
+	exp, err := time.Parse(timeFormat, aad[EXP])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return exp, true
+}
+
+// Returns true if token contains scope claim. Such tokens can only be decrypted with DecryptEmbed.
+func isEmbedToken(jwe *jose.JSONWebEncryption) bool {
+	aad := AdditionalAuthData{}
+	if err := json.Unmarshal(jwe.GetAuthData(), &aad); err != nil {
+		return false
+	}
+
+	_, ok := aad[SCOPE]
+	return ok
+}
+
+// Returns time of the login from the token. Issue time is used for tokens generated without AUTH_TIME claim.
+func getAuthTime(jwe *jose.JSONWebEncryption) time.Time {
+	aad := AdditionalAuthData{}
+	if err := json.Unmarshal(jwe.GetAuthData(), &aad); err != nil {
+		return time.Time{}
+	}
+
+	for _, claim := range []Claim{AUTH_TIME, IAT} {
+		if authTime, err := time.Parse(timeFormat, aad[claim]); err == nil {
+			return authTime
+		}
+	}
+
+	return time.Time{}
+}
+
+// Returns ID of the session from the token. New session ID is returned for tokens generated without SID claim, so
+// they are tracked from now on.
+func getSessionID(jwe *jose.JSONWebEncryption) string {
+	aad := AdditionalAuthData{}
+	if err := json.Unmarshal(jwe.GetAuthData(), &aad); err != nil || len(aad[SID]) == 0 {
+		return newSessionID()
+	}
+
+	return aad[SID]
+}
+
+// Unmarshals token payload. Payload of elevated tokens and tokens carrying groups contains AuthInfo of the session
+// together with the elevated one and groups of the user.
+func unmarshalPayload(jwe *jose.JSONWebEncryption, decrypted []byte) (*authApi.ElevatedAuthInfo, error) {
+	aad := AdditionalAuthData{}
+	_ = json.Unmarshal(jwe.GetAuthData(), &aad)
+	if len(aad[ELEVATED_UNTIL]) == 0 && aad[PAYLOAD] != sessionPayloadType {
+		authInfo := new(api.AuthInfo)
+		if err := json.Unmarshal(decrypted, authInfo); err != nil {
+			return nil, err
+		}
+
+		return &authApi.ElevatedAuthInfo{AuthInfo: *authInfo}, nil
+	}
+
+	payload := new(sessionPayload)
+	if err := json.Unmarshal(decrypted, payload); err != nil {
+		return nil, err
+	}
+
+	info := &authApi.ElevatedAuthInfo{AuthInfo: payload.AuthInfo, Groups: payload.Groups}
+	if len(aad[ELEVATED_UNTIL]) == 0 || payload.Elevated == nil {
+		return info, nil
+	}
+
+	until, err := time.Parse(timeFormat, aad[ELEVATED_UNTIL])
+	if err != nil {
+		return nil, errors.NewInvalid("Token validation error. Could not parse elevation expiration time.")
+	}
+
+	info.Elevated = payload.Elevated
+	info.ElevatedUntil = until
+	return info, nil
+}
+
+// Returns AAD header of the token issued at given time together with expiration time of the token. Zero time is
+// returned for tokens that never expire.
+func (self *jweTokenManager) generateAAD(now, authTime, elevatedUntil time.Time, sessionID string,
+	wrapped bool) ([]byte, time.Time) {
 	aad := AdditionalAuthData{
-		IAT: now.Format(timeFormat),
+		IAT:       now.Format(timeFormat),
+		AUTH_TIME: authTime.Format(timeFormat),
+		SID:       sessionID,
+	}
+
+	if wrapped {
+		aad[PAYLOAD] = sessionPayloadType
 	}
 
+	if !elevatedUntil.IsZero() {
+		aad[ELEVATED_UNTIL] = elevatedUntil.Format(timeFormat)
+	}
+
+	// Tokens expire after token TTL, but never later than max lifetime since login.
+	var exp time.Time
 	if self.tokenTTL > 0 {
-		aad[EXP] = now.Add(self.tokenTTL).Format(timeFormat)
+		exp = now.Add(self.tokenTTL)
+	}
+
+	if limit := authTime.Add(self.maxLifetime); self.maxLifetime > 0 && (exp.IsZero() || limit.Before(exp)) {
+		exp = limit
+	}
+
+	if !exp.IsZero() {
+		aad[EXP] = exp.Format(timeFormat)
 	}
 
 	rawAAD, _ := json.Marshal(aad)
-	return rawAAD
+	return rawAAD, exp
 }
 
 // Creates and returns default JWE token manager instance.
-func NewJWETokenManager(holder KeyHolder) authApi.TokenManager {
-	manager := &jweTokenManager{keyHolder: holder, tokenTTL: authApi.DefaultTokenTTL * time.Second}
+func NewJWETokenManager(holder KeyHolder, revocations RevocationList, sessions SessionList) authApi.TokenManager {
+	manager := &jweTokenManager{
+		keyHolder:   holder,
+		revocations: revocations,
+		sessions:    sessions,
+		tokenTTL:    authApi.DefaultTokenTTL * time.Second,
+		cache:       newTokenCache(DefaultTokenCacheSize),
+	}
 	return manager
 }
+
+// Creates JWE token manager with encryption key, revoked tokens and sessions synchronized through secrets in
+// Dashboard namespace, so all Dashboard replicas can decrypt the tokens, reject revoked ones and list sessions.
+func newTokenManagerWithSecretKeyHolder(client kubernetes.Interface) (authApi.TokenManager, error) {
+	synchronizerManager := sync.NewSynchronizerManager(client)
+	keySynchronizer, err := newKeySynchronizer(synchronizerManager)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := args.Holder.GetKMSProvider(); len(name) > 0 {
+		provider, err := kms.NewProvider(name, args.Holder.GetKMSKey())
+		if err != nil {
+			return nil, errors.NewInvalid(err.Error())
+		}
+		keySynchronizer = kms.NewEnvelopeSynchronizer(provider, keySynchronizer)
+	}
+	revocationSynchronizer := synchronizerManager.Secret(args.Holder.GetNamespace(), authApi.RevokedTokensHolderName)
+	sessionSynchronizer := synchronizerManager.Secret(args.Holder.GetNamespace(), authApi.SessionsHolderName)
+
+	// Register synchronizers. Overwatch will be responsible for restarting them in case of error.
+	sync.Overwatch.RegisterSynchronizer(keySynchronizer, sync.AlwaysRestart)
+	sync.Overwatch.RegisterSynchronizer(revocationSynchronizer, sync.AlwaysRestart)
+	sync.Overwatch.RegisterSynchronizer(sessionSynchronizer, sync.AlwaysRestart)
+
+	return NewJWETokenManager(NewRSAKeyHolder(keySynchronizer), NewSecretRevocationList(revocationSynchronizer),
+		NewSecretSessionList(sessionSynchronizer)), nil
+}
+
+// Creates synchronizer of the encryption key based on selected key store. Key is held either by a secret in
+// Dashboard namespace or by Vault, in which case the key is stored in KV secret engine or kept in the secret
+// encrypted with transit secret engine.
+func newKeySynchronizer(manager syncApi.SynchronizerManager) (syncApi.Synchronizer, error) {
+	secretSynchronizer := func() syncApi.Synchronizer {
+		return manager.Secret(args.Holder.GetNamespace(), authApi.EncryptionKeyHolderName)
+	}
+
+	switch args.Holder.GetKeyStore() {
+	case "", authApi.KeyStoreSecret:
+		return secretSynchronizer(), nil
+	case authApi.KeyStoreVault:
+	default:
+		return nil, errors.NewInvalid(fmt.Sprintf("Unsupported key store: %s", args.Holder.GetKeyStore()))
+	}
+
+	client := vault.NewClient(vault.Config{
+		Address:   args.Holder.GetVaultAddress(),
+		TokenFile: args.Holder.GetVaultTokenFile(),
+		Role:      args.Holder.GetVaultRole(),
+	})
+
+	switch args.Holder.GetVaultEngine() {
+	case authApi.VaultEngineKV:
+		return vault.NewKVSynchronizer(client, args.Holder.GetVaultMount(), args.Holder.GetVaultKey(),
+			args.Holder.GetNamespace(), authApi.EncryptionKeyHolderName), nil
+	case authApi.VaultEngineTransit:
+		return vault.NewTransitSynchronizer(client, args.Holder.GetVaultMount(), args.Holder.GetVaultKey(),
+			secretSynchronizer()), nil
+	}
+
+	return nil, errors.NewInvalid(fmt.Sprintf("Unsupported Vault secret engine: %s", args.Holder.GetVaultEngine()))
+}
@@ -19,9 +19,11 @@ import (
 	"testing"
 	"time"
 
+	jose "gopkg.in/square/go-jose.v2"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/clientcmd/api"
 
+	"github.com/kubernetes/dashboard/src/app/backend/args"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
 	"github.com/kubernetes/dashboard/src/app/backend/sync"
@@ -31,7 +33,17 @@ func getTokenManager() authApi.TokenManager {
 	c := fake.NewSimpleClientset()
 	syncManager := sync.NewSynchronizerManager(c)
 	holder := NewRSAKeyHolder(syncManager.Secret("", ""))
-	return NewJWETokenManager(holder)
+	return NewJWETokenManager(holder, getRevocationList(), getSessionList())
+}
+
+func getRevocationList() RevocationList {
+	syncManager := sync.NewSynchronizerManager(fake.NewSimpleClientset())
+	return NewSecretRevocationList(syncManager.Secret(args.Holder.GetNamespace(), authApi.RevokedTokensHolderName))
+}
+
+func getSessionList() SessionList {
+	syncManager := sync.NewSynchronizerManager(fake.NewSimpleClientset())
+	return NewSecretSessionList(syncManager.Secret(args.Holder.GetNamespace(), authApi.SessionsHolderName))
 }
 
 func areErrorsEqual(err1, err2 error) bool {
@@ -154,3 +166,280 @@ func TestJweTokenManager_Refresh(t *testing.T) {
 		}
 	}
 }
+
+func TestJweTokenManager_RefreshMaxLifetime(t *testing.T) {
+	tokenManager := getTokenManager()
+	tokenManager.SetTokenTTL(10)
+	tokenManager.SetTokenMaxLifetime(2)
+	token, _ := tokenManager.Generate(api.AuthInfo{Token: "test-token"})
+
+	refreshedToken, err := tokenManager.Refresh(token)
+	if err != nil {
+		t.Fatalf("Expected token to be refreshed, but got %v.", err)
+	}
+
+	time.Sleep(2 * time.Second)
+
+	expectedErr := errors.NewTokenExpired(errors.MsgTokenExpiredError)
+	if _, err := tokenManager.Refresh(refreshedToken); !areErrorsEqual(err, expectedErr) {
+		t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+}
+
+func TestJweTokenManager_Elevate(t *testing.T) {
+	tokenManager := getTokenManager()
+	elevationTokenManager := tokenManager.(authApi.ElevationTokenManager)
+	token, _ := tokenManager.Generate(api.AuthInfo{Token: "viewer-token"})
+
+	elevatedToken, err := elevationTokenManager.Elevate(token, api.AuthInfo{Token: "admin-token"},
+		time.Now().Add(2*time.Second))
+	if err != nil {
+		t.Fatalf("Expected token to be elevated, but got %v.", err)
+	}
+
+	info, err := elevationTokenManager.DecryptElevated(elevatedToken)
+	if err != nil || info.AuthInfo.Token != "viewer-token" || info.Elevated == nil ||
+		info.Elevated.Token != "admin-token" {
+		t.Fatalf("Expected elevated token to contain both auth infos, but got %#v, %v.", info, err)
+	}
+
+	if authInfo, _ := tokenManager.Decrypt(elevatedToken); authInfo == nil || authInfo.Token != "admin-token" {
+		t.Errorf("Expected elevated auth info before elevation expires, but got %#v.", authInfo)
+	}
+
+	refreshedToken, _ := tokenManager.Refresh(elevatedToken)
+	if info, _ := elevationTokenManager.DecryptElevated(refreshedToken); info == nil || info.Elevated == nil {
+		t.Errorf("Expected refreshed token to keep the elevation, but got %#v.", info)
+	}
+
+	time.Sleep(3 * time.Second)
+
+	if authInfo, _ := tokenManager.Decrypt(elevatedToken); authInfo == nil || authInfo.Token != "viewer-token" {
+		t.Errorf("Expected session auth info after elevation expires, but got %#v.", authInfo)
+	}
+
+	refreshedToken, _ = tokenManager.Refresh(elevatedToken)
+	if info, _ := elevationTokenManager.DecryptElevated(refreshedToken); info == nil || info.Elevated != nil {
+		t.Errorf("Expected refreshed token to drop the expired elevation, but got %#v.", info)
+	}
+}
+
+func TestJweTokenManager_DecryptEmbed(t *testing.T) {
+	tokenManager := getTokenManager()
+	embedManager := tokenManager.(authApi.EmbedTokenManager)
+	authInfo := api.AuthInfo{Token: "test-token"}
+	scope := authApi.EmbedScope{Kind: "pod", Namespace: "default", Name: "test-pod"}
+
+	token, err := embedManager.GenerateEmbed(authInfo, scope, time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error when generating embed token, but got %v.", err)
+	}
+
+	decrypted, decryptedScope, err := embedManager.DecryptEmbed(token)
+	if err != nil {
+		t.Fatalf("Expected no error when decrypting embed token, but got %v.", err)
+	}
+
+	if !reflect.DeepEqual(decrypted, &authInfo) || !reflect.DeepEqual(decryptedScope, &scope) {
+		t.Errorf("Expected: %v and %v, but got %v and %v.", authInfo, scope, decrypted, decryptedScope)
+	}
+
+	if _, err = tokenManager.Decrypt(token); err == nil {
+		t.Error("Expected embed token not to be accepted as a regular token.")
+	}
+
+	if _, err = tokenManager.Refresh(token); err == nil {
+		t.Error("Expected embed token not to be refreshed.")
+	}
+
+	regular, _ := tokenManager.Generate(authInfo)
+	if _, _, err = embedManager.DecryptEmbed(regular); err == nil {
+		t.Error("Expected regular token not to be accepted as an embed token.")
+	}
+
+	if _, err = embedManager.GenerateEmbed(authInfo, scope, 0); err == nil {
+		t.Error("Expected error when generating embed token that never expires.")
+	}
+}
+
+func TestJweTokenManager_Revoke(t *testing.T) {
+	tokenManager := getTokenManager()
+	token, _ := tokenManager.Generate(api.AuthInfo{Token: "test-token"})
+	other, _ := tokenManager.Generate(api.AuthInfo{Token: "other-token"})
+
+	// Decrypt the token first, so it is cached.
+	if _, err := tokenManager.Decrypt(token); err != nil {
+		t.Fatalf("Expected no error when decrypting token, but got %v.", err)
+	}
+
+	if err := tokenManager.Revoke(token); err != nil {
+		t.Fatalf("Expected no error when revoking token, but got %v.", err)
+	}
+
+	expectedErr := errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	if _, err := tokenManager.Decrypt(token); !areErrorsEqual(err, expectedErr) {
+		t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+
+	if _, err := tokenManager.Refresh(token); !areErrorsEqual(err, expectedErr) {
+		t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+
+	if _, err := tokenManager.Decrypt(other); err != nil {
+		t.Errorf("Expected other token to stay valid, but got %v.", err)
+	}
+
+	expectedErr = errors.NewInvalid("Can not revoke token. No token provided.")
+	if err := tokenManager.Revoke(""); !areErrorsEqual(err, expectedErr) {
+		t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+}
+
+func TestJweTokenManager_ExpireSession(t *testing.T) {
+	tokenManager := getTokenManager()
+	sessionTokenManager := tokenManager.(authApi.SessionTokenManager)
+	token, _ := tokenManager.Generate(api.AuthInfo{Token: "test-token"})
+	refreshed, err := tokenManager.Refresh(token)
+	if err != nil {
+		t.Fatalf("Expected no error when refreshing token, but got %v.", err)
+	}
+	other, _ := tokenManager.Generate(api.AuthInfo{Username: "other"})
+
+	sessions, _ := sessionTokenManager.Sessions()
+	if len(sessions) != 2 {
+		t.Fatalf("Expected refreshed token to belong to the same session, but got sessions %+v.", sessions)
+	}
+
+	if sessions[0].Subject != "other" || sessions[1].Subject != "token:4c5dc9b7" || sessions[1].ExpiresAt == nil {
+		t.Errorf("Expected sessions of other and token:4c5dc9b7 with expiration time, but got %+v.", sessions)
+	}
+
+	if err = sessionTokenManager.ExpireSession(sessions[1].ID); err != nil {
+		t.Fatalf("Expected no error when expiring session, but got %v.", err)
+	}
+
+	expectedErr := errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	for _, expired := range []string{token, refreshed} {
+		if _, err := tokenManager.Decrypt(expired); !areErrorsEqual(err, expectedErr) {
+			t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+		}
+	}
+
+	if _, err := tokenManager.Decrypt(other); err != nil {
+		t.Errorf("Expected token of other session to stay valid, but got %v.", err)
+	}
+
+	expectedErr = errors.NewNotFound("Session not found.")
+	if err := sessionTokenManager.ExpireSession(sessions[1].ID); !areErrorsEqual(err, expectedErr) {
+		t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+
+	// Logout ends the whole session.
+	if err := tokenManager.Revoke(other); err != nil {
+		t.Fatalf("Expected no error when revoking token, but got %v.", err)
+	}
+
+	if sessions, _ = sessionTokenManager.Sessions(); len(sessions) != 0 {
+		t.Errorf("Expected no sessions after logout, but got %+v.", sessions)
+	}
+}
+
+func TestJweTokenManager_SessionOf(t *testing.T) {
+	tokenManager := getTokenManager()
+	sessionTokenManager := tokenManager.(authApi.SessionTokenManager)
+	token, _ := tokenManager.Generate(api.AuthInfo{Token: "test-token"})
+
+	session, err := sessionTokenManager.SessionOf(token)
+	if err != nil {
+		t.Fatalf("Expected no error when getting session of token, but got %v.", err)
+	}
+
+	client := authApi.SessionClient{SourceIP: "10.0.0.1", UserAgent: "browser"}
+	if err = sessionTokenManager.SetSessionClient(session.ID, client); err != nil {
+		t.Fatalf("Expected no error when setting session client, but got %v.", err)
+	}
+
+	refreshed, _ := tokenManager.Refresh(token)
+	if session, _ = sessionTokenManager.SessionOf(refreshed); session == nil || session.SessionClient != client {
+		t.Errorf("Expected refreshed token to belong to the session of %+v, but got %+v.", client, session)
+	}
+
+	_ = tokenManager.Revoke(refreshed)
+	expectedErr := errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	if _, err = sessionTokenManager.SessionOf(refreshed); !areErrorsEqual(err, expectedErr) {
+		t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+}
+
+func TestJweTokenManager_State(t *testing.T) {
+	stateTokenManager := getTokenManager().(authApi.StateTokenManager)
+	encrypted, err := stateTokenManager.EncryptState("alice", []byte(`{"recent":["pod"]}`))
+	if err != nil {
+		t.Fatalf("Expected no error when encrypting state, but got %v.", err)
+	}
+
+	state, err := stateTokenManager.DecryptState("alice", encrypted)
+	if err != nil || string(state) != `{"recent":["pod"]}` {
+		t.Errorf("Expected state to be decrypted, but got %s (error: %v).", state, err)
+	}
+
+	expectedErr := errors.NewInvalid("State belongs to another owner.")
+	if _, err = stateTokenManager.DecryptState("bob", encrypted); !areErrorsEqual(err, expectedErr) {
+		t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+}
+
+func TestJweTokenManager_StateKeySeparation(t *testing.T) {
+	tokenManager := getTokenManager()
+	stateTokenManager := tokenManager.(authApi.StateTokenManager)
+	encrypted, err := stateTokenManager.EncryptState("alice", []byte(`{"token":"test-token"}`))
+	if err != nil {
+		t.Fatalf("Expected no error when encrypting state, but got %v.", err)
+	}
+
+	if info, err := tokenManager.Decrypt(encrypted); err == nil {
+		t.Errorf("Expected state not to be decrypted as a token, but got %+v.", info)
+	}
+
+	token, err := tokenManager.Generate(api.AuthInfo{Token: "test-token"})
+	if err != nil {
+		t.Fatalf("Expected no error when generating token, but got %v.", err)
+	}
+
+	jweObject, _ := jose.ParseEncrypted(token)
+	if state, err := stateTokenManager.DecryptState(string(jweObject.GetAuthData()), token); err == nil {
+		t.Errorf("Expected token not to be decrypted as state, but got %s.", state)
+	}
+}
+
+func TestJweTokenManager_Groups(t *testing.T) {
+	tokenManager := getTokenManager()
+	groupTokenManager := tokenManager.(authApi.GroupTokenManager)
+	groups := []string{"dev", "ops"}
+	token, err := groupTokenManager.GenerateWithGroups(api.AuthInfo{Token: "test-token"}, groups)
+	if err != nil {
+		t.Fatalf("Expected no error when generating token, but got %v.", err)
+	}
+
+	// Second call is served from the cache.
+	for i := 0; i < 2; i++ {
+		if result, err := groupTokenManager.Groups(token); err != nil || !reflect.DeepEqual(result, groups) {
+			t.Errorf("Expected groups %v, but got %v (error: %v).", groups, result, err)
+		}
+	}
+
+	if authInfo, err := tokenManager.Decrypt(token); err != nil || authInfo.Token != "test-token" {
+		t.Errorf("Expected token with groups to be decrypted, but got %v (error: %v).", authInfo, err)
+	}
+
+	refreshed, _ := tokenManager.Refresh(token)
+	if result, err := groupTokenManager.Groups(refreshed); err != nil || !reflect.DeepEqual(result, groups) {
+		t.Errorf("Expected refreshed token to keep groups %v, but got %v (error: %v).", groups, result, err)
+	}
+
+	elevated, _ := tokenManager.(authApi.ElevationTokenManager).Elevate(refreshed, api.AuthInfo{Token: "admin-token"},
+		time.Now().Add(time.Minute))
+	if result, err := groupTokenManager.Groups(elevated); err != nil || !reflect.DeepEqual(result, groups) {
+		t.Errorf("Expected elevated token to keep groups %v, but got %v (error: %v).", groups, result, err)
+	}
+}
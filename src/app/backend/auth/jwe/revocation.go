@@ -0,0 +1,174 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	syncApi "github.com/kubernetes/dashboard/src/app/backend/sync/api"
+)
+
+// Number of times revocation is retried when the secret has been modified by another replica in the meantime.
+const revocationRetries = 3
+
+// RevocationList is responsible for storing and synchronizing IDs of tokens revoked before they expire.
+type RevocationList interface {
+	// Revoke adds token ID to the list. Entry can be removed from the list after given expiration time of the
+	// token. Zero time means that token never expires.
+	Revoke(id string, expiry time.Time) error
+	// IsRevoked returns true if token with given ID has been revoked.
+	IsRevoked(id string) bool
+}
+
+// Implements RevocationList interface. Entries are stored in a secret, so they are shared between replicas. Keys of
+// the secret are token IDs and values are expiration times of the tokens.
+type secretRevocationList struct {
+	revoked      map[string]time.Time
+	synchronizer syncApi.Synchronizer
+	mux          sync.Mutex
+}
+
+// Revoke implements revocation list interface. See RevocationList for more information.
+func (self *secretRevocationList) Revoke(id string, expiry time.Time) error {
+	var err error
+	for i := 0; i < revocationRetries; i++ {
+		if err = self.tryRevoke(id, expiry, time.Now()); !errors.IsConflict(err) && !errors.IsAlreadyExists(err) {
+			return err
+		}
+
+		self.synchronizer.Refresh()
+	}
+
+	return err
+}
+
+// IsRevoked implements revocation list interface. See RevocationList for more information.
+func (self *secretRevocationList) IsRevoked(id string) bool {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	_, revoked := self.revoked[id]
+	return revoked
+}
+
+// Adds entry to the synchronized secret, creating it if it does not exist yet. Entries of tokens that have already
+// expired are removed, as such tokens are rejected anyway.
+func (self *secretRevocationList) tryRevoke(id string, expiry, now time.Time) error {
+	obj := self.synchronizer.Get()
+	if obj == nil {
+		secret := &v1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{
+				Namespace: args.Holder.GetNamespace(),
+				Name:      authApi.RevokedTokensHolderName,
+			},
+			Data: map[string][]byte{id: formatExpiry(expiry)},
+		}
+
+		if err := self.synchronizer.Create(secret); err != nil {
+			return err
+		}
+
+		self.update(secret)
+		return nil
+	}
+
+	secret := obj.(*v1.Secret).DeepCopy()
+	data := make(map[string][]byte)
+	for entryID, entryExpiry := range parseRevocations(secret) {
+		if entryExpiry.IsZero() || entryExpiry.After(now) {
+			data[entryID] = formatExpiry(entryExpiry)
+		}
+	}
+
+	data[id] = formatExpiry(expiry)
+	secret.Data = data
+	if err := self.synchronizer.Update(secret); err != nil {
+		return err
+	}
+
+	self.update(secret)
+	return nil
+}
+
+// Handler function executed by synchronizer used to store revoked tokens. It is called whenever watched object
+// is created or updated.
+func (self *secretRevocationList) update(obj runtime.Object) {
+	revoked := parseRevocations(obj.(*v1.Secret))
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.revoked = revoked
+}
+
+// Handler function executed by synchronizer used to store revoked tokens. It is called whenever watched object
+// gets deleted. Secret is recreated with the next revocation.
+func (self *secretRevocationList) clear(obj runtime.Object) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.revoked = make(map[string]time.Time)
+}
+
+func (self *secretRevocationList) init() {
+	self.synchronizer.RegisterActionHandler(self.update, watch.Added, watch.Modified)
+	self.synchronizer.RegisterActionHandler(self.clear, watch.Deleted)
+
+	if obj := self.synchronizer.Get(); obj != nil {
+		self.update(obj)
+	}
+}
+
+// Returns revoked token IDs with expiration times of the tokens. Entries with invalid time never expire.
+func parseRevocations(secret *v1.Secret) map[string]time.Time {
+	result := make(map[string]time.Time)
+	for id, expiry := range secret.Data {
+		result[id], _ = time.Parse(timeFormat, string(expiry))
+	}
+
+	return result
+}
+
+func formatExpiry(expiry time.Time) []byte {
+	if expiry.IsZero() {
+		return []byte{}
+	}
+	return []byte(expiry.UTC().Format(timeFormat))
+}
+
+// Returns ID of the token used in revocation list. Tokens are not stored, so they can not be read from the list.
+func getTokenID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewSecretRevocationList creates new RevocationList instance synchronized through given secret synchronizer.
+func NewSecretRevocationList(synchronizer syncApi.Synchronizer) RevocationList {
+	list := &secretRevocationList{
+		revoked:      make(map[string]time.Time),
+		synchronizer: synchronizer,
+	}
+
+	list.init()
+	return list
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/sync"
+)
+
+func TestSecretRevocationList_Revoke(t *testing.T) {
+	now := time.Now()
+	secret := &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: args.Holder.GetNamespace(), Name: authApi.RevokedTokensHolderName},
+		Data: map[string][]byte{
+			"expired":  formatExpiry(now.Add(-time.Minute)),
+			"valid":    formatExpiry(now.Add(time.Hour)),
+			"eternal":  formatExpiry(time.Time{}),
+			"tampered": []byte("not-a-time"),
+		},
+	}
+	client := fake.NewSimpleClientset(secret)
+	syncManager := sync.NewSynchronizerManager(client)
+	list := NewSecretRevocationList(syncManager.Secret(args.Holder.GetNamespace(), authApi.RevokedTokensHolderName))
+
+	if !list.IsRevoked("valid") || list.IsRevoked("new") {
+		t.Fatal("Expected revocation list to be initialized from the secret.")
+	}
+
+	if err := list.Revoke("new", now.Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error when revoking token, but got %v.", err)
+	}
+
+	if !list.IsRevoked("new") {
+		t.Error("Expected token to be revoked.")
+	}
+
+	stored, err := client.CoreV1().Secrets(args.Holder.GetNamespace()).Get(context.TODO(), authApi.RevokedTokensHolderName,
+		metaV1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"new", "valid", "eternal", "tampered"} {
+		if _, exists := stored.Data[id]; !exists {
+			t.Errorf("Expected entry %s to be stored in the secret.", id)
+		}
+	}
+
+	if _, exists := stored.Data["expired"]; exists {
+		t.Error("Expected entry of expired token to be removed from the secret.")
+	}
+}
+
+func TestSecretRevocationList_RevokeCreatesSecret(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	syncManager := sync.NewSynchronizerManager(client)
+	list := NewSecretRevocationList(syncManager.Secret(args.Holder.GetNamespace(), authApi.RevokedTokensHolderName))
+
+	if err := list.Revoke("token", time.Time{}); err != nil {
+		t.Fatalf("Expected no error when revoking token, but got %v.", err)
+	}
+
+	if !list.IsRevoked("token") {
+		t.Error("Expected token to be revoked.")
+	}
+
+	if _, err := client.CoreV1().Secrets(args.Holder.GetNamespace()).Get(context.TODO(), authApi.RevokedTokensHolderName,
+		metaV1.GetOptions{}); err != nil {
+		t.Errorf("Expected secret to be created, but got %v.", err)
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwe
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	syncApi "github.com/kubernetes/dashboard/src/app/backend/sync/api"
+)
+
+// Number of times session update is retried when the secret has been modified by another replica in the meantime.
+const sessionRetries = 3
+
+// SessionList is responsible for storing and synchronizing active sessions together with IDs of their tokens.
+type SessionList interface {
+	// Record adds token with given ID and expiration time to the session, creating the session if it does not exist
+	// yet. Zero time means that token never expires.
+	Record(session authApi.Session, tokenID string, expiry time.Time) error
+	// List returns sessions with at least one token that has not expired at given time.
+	List(now time.Time) []authApi.Session
+	// Tokens returns IDs of tokens of the session together with their expiration times.
+	Tokens(id string) map[string]time.Time
+	// Remove forgets the session. Its tokens are not revoked.
+	Remove(id string) error
+	// SetClient sets client that the latest token of the session has been issued to. Nothing is written if the
+	// client has not changed.
+	SetClient(id string, client authApi.SessionClient) error
+}
+
+// Session as stored in the secret. Tokens are only stored as IDs, so they can not be read from the secret.
+type sessionEntry struct {
+	Subject     string            `json:"subject"`
+	LoginTime   time.Time         `json:"loginTime"`
+	LastRefresh time.Time         `json:"lastRefresh"`
+	Tokens      map[string]string `json:"tokens"`
+	SourceIP    string            `json:"sourceIP,omitempty"`
+	UserAgent   string            `json:"userAgent,omitempty"`
+}
+
+// Implements SessionList interface. Sessions are stored in a secret, so they are shared between replicas. Keys of
+// the secret are session IDs and values are JSON encoded session entries.
+type secretSessionList struct {
+	sessions     map[string]sessionEntry
+	synchronizer syncApi.Synchronizer
+	mux          sync.Mutex
+}
+
+// Record implements session list interface. See SessionList for more information.
+func (self *secretSessionList) Record(session authApi.Session, tokenID string, expiry time.Time) error {
+	return self.retry(func(now time.Time, entries map[string]sessionEntry) {
+		entry, exists := entries[session.ID]
+		if !exists {
+			entry = sessionEntry{Subject: session.Subject, LoginTime: session.LoginTime, Tokens: map[string]string{}}
+		}
+
+		entry.LastRefresh = session.LastRefresh
+		entry.Tokens[tokenID] = string(formatExpiry(expiry))
+		entries[session.ID] = entry
+	})
+}
+
+// List implements session list interface. See SessionList for more information.
+func (self *secretSessionList) List(now time.Time) []authApi.Session {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	result := make([]authApi.Session, 0)
+	for id, entry := range self.sessions {
+		expiry, active := getSessionExpiry(entry, now)
+		if !active {
+			continue
+		}
+
+		session := authApi.Session{
+			ID:            id,
+			Subject:       entry.Subject,
+			LoginTime:     entry.LoginTime,
+			LastRefresh:   entry.LastRefresh,
+			SessionClient: authApi.SessionClient{SourceIP: entry.SourceIP, UserAgent: entry.UserAgent},
+		}
+
+		if !expiry.IsZero() {
+			session.ExpiresAt = &expiry
+		}
+
+		result = append(result, session)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Subject != result[j].Subject {
+			return result[i].Subject < result[j].Subject
+		}
+		return result[i].LoginTime.Before(result[j].LoginTime)
+	})
+
+	return result
+}
+
+// Tokens implements session list interface. See SessionList for more information.
+func (self *secretSessionList) Tokens(id string) map[string]time.Time {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	entry, exists := self.sessions[id]
+	if !exists {
+		return nil
+	}
+
+	return parseTokenExpiries(entry)
+}
+
+// Remove implements session list interface. See SessionList for more information.
+func (self *secretSessionList) Remove(id string) error {
+	return self.retry(func(now time.Time, entries map[string]sessionEntry) {
+		delete(entries, id)
+	})
+}
+
+// SetClient implements session list interface. See SessionList for more information.
+func (self *secretSessionList) SetClient(id string, client authApi.SessionClient) error {
+	self.mux.Lock()
+	entry, exists := self.sessions[id]
+	self.mux.Unlock()
+	if !exists {
+		return errors.NewNotFound("Session not found.")
+	}
+
+	if entry.SourceIP == client.SourceIP && entry.UserAgent == client.UserAgent {
+		return nil
+	}
+
+	return self.retry(func(now time.Time, entries map[string]sessionEntry) {
+		if entry, exists := entries[id]; exists {
+			entry.SourceIP = client.SourceIP
+			entry.UserAgent = client.UserAgent
+			entries[id] = entry
+		}
+	})
+}
+
+// Applies given change to the sessions stored in the synchronized secret and retries it when the secret has been
+// modified by another replica in the meantime. Tokens are generated by all replicas on every login and refresh, so
+// the watched secret is often outdated and it is read before every attempt.
+func (self *secretSessionList) retry(change func(time.Time, map[string]sessionEntry)) error {
+	var err error
+	for i := 0; i < sessionRetries; i++ {
+		self.synchronizer.Refresh()
+		if err = self.tryUpdate(change, time.Now()); !errors.IsConflict(err) && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// Applies given change to the synchronized secret, creating it if it does not exist yet. Sessions whose tokens have
+// all expired are removed, as they can not be used anymore.
+func (self *secretSessionList) tryUpdate(change func(time.Time, map[string]sessionEntry), now time.Time) error {
+	obj := self.synchronizer.Get()
+	entries := make(map[string]sessionEntry)
+	if obj != nil {
+		entries = parseSessions(obj.(*v1.Secret))
+	}
+
+	change(now, entries)
+	data := make(map[string][]byte)
+	for id, entry := range entries {
+		for tokenID, expiry := range parseTokenExpiries(entry) {
+			if !expiry.IsZero() && !expiry.After(now) {
+				delete(entry.Tokens, tokenID)
+			}
+		}
+
+		if len(entry.Tokens) == 0 {
+			continue
+		}
+
+		marshalled, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		data[id] = marshalled
+	}
+
+	var secret *v1.Secret
+	if obj == nil {
+		secret = &v1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{
+				Namespace: args.Holder.GetNamespace(),
+				Name:      authApi.SessionsHolderName,
+			},
+			Data: data,
+		}
+
+		if err := self.synchronizer.Create(secret); err != nil {
+			return err
+		}
+	} else {
+		secret = obj.(*v1.Secret).DeepCopy()
+		secret.Data = data
+		if err := self.synchronizer.Update(secret); err != nil {
+			return err
+		}
+	}
+
+	self.update(secret)
+	return nil
+}
+
+// Handler function executed by synchronizer used to store sessions. It is called whenever watched object is created
+// or updated.
+func (self *secretSessionList) update(obj runtime.Object) {
+	sessions := parseSessions(obj.(*v1.Secret))
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.sessions = sessions
+}
+
+// Handler function executed by synchronizer used to store sessions. It is called whenever watched object gets
+// deleted. Secret is recreated with the next login.
+func (self *secretSessionList) clear(obj runtime.Object) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.sessions = make(map[string]sessionEntry)
+}
+
+func (self *secretSessionList) init() {
+	self.synchronizer.RegisterActionHandler(self.update, watch.Added, watch.Modified)
+	self.synchronizer.RegisterActionHandler(self.clear, watch.Deleted)
+
+	if obj := self.synchronizer.Get(); obj != nil {
+		self.update(obj)
+	}
+}
+
+// Returns sessions stored in the secret. Entries that can not be unmarshalled are skipped.
+func parseSessions(secret *v1.Secret) map[string]sessionEntry {
+	result := make(map[string]sessionEntry)
+	for id, data := range secret.Data {
+		entry := sessionEntry{}
+		if err := json.Unmarshal(data, &entry); err != nil || len(entry.Tokens) == 0 {
+			continue
+		}
+
+		result[id] = entry
+	}
+
+	return result
+}
+
+// Returns token IDs of the session with expiration times of the tokens. Tokens with invalid time never expire.
+func parseTokenExpiries(entry sessionEntry) map[string]time.Time {
+	result := make(map[string]time.Time)
+	for tokenID, expiry := range entry.Tokens {
+		result[tokenID], _ = time.Parse(timeFormat, expiry)
+	}
+
+	return result
+}
+
+// Returns expiration time of the latest token of the session and false if all tokens have expired at given time.
+// Zero time is returned if any of the tokens never expires.
+func getSessionExpiry(entry sessionEntry, now time.Time) (time.Time, bool) {
+	var latest time.Time
+	active := false
+	for _, expiry := range parseTokenExpiries(entry) {
+		if expiry.IsZero() {
+			return time.Time{}, true
+		}
+
+		if expiry.After(now) {
+			active = true
+		}
+
+		if expiry.After(latest) {
+			latest = expiry
+		}
+	}
+
+	return latest, active
+}
+
+// Returns a loggable description of credentials in given AuthInfo used as a subject of the session.
+func getSessionSubject(authInfo api.AuthInfo) string {
+	return clientapi.GetIdentity(&rest.Config{
+		Username:        authInfo.Username,
+		BearerToken:     authInfo.Token,
+		TLSClientConfig: rest.TLSClientConfig{CertData: authInfo.ClientCertificateData},
+		Impersonate:     rest.ImpersonationConfig{UserName: authInfo.Impersonate},
+	})
+}
+
+// Returns new random session ID.
+func newSessionID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// NewSecretSessionList creates new SessionList instance synchronized through given secret synchronizer.
+func NewSecretSessionList(synchronizer syncApi.Synchronizer) SessionList {
+	list := &secretSessionList{
+		sessions:     make(map[string]sessionEntry),
+		synchronizer: synchronizer,
+	}
+
+	list.init()
+	return list
+}
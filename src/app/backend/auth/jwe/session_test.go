@@ -0,0 +1,104 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwe
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/sync"
+)
+
+func TestSecretSessionList_Record(t *testing.T) {
+	now := time.Now()
+	expired, _ := json.Marshal(sessionEntry{Subject: "expired",
+		Tokens: map[string]string{"old": string(formatExpiry(now.Add(-time.Minute)))}})
+	secret := &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: args.Holder.GetNamespace(), Name: authApi.SessionsHolderName},
+		Data:       map[string][]byte{"expired": expired, "tampered": []byte("not-a-session")},
+	}
+	client := fake.NewSimpleClientset(secret)
+	syncManager := sync.NewSynchronizerManager(client)
+	list := NewSecretSessionList(syncManager.Secret(args.Holder.GetNamespace(), authApi.SessionsHolderName))
+
+	if sessions := list.List(now); len(sessions) != 0 {
+		t.Fatalf("Expected sessions with expired tokens not to be listed, but got %+v.", sessions)
+	}
+
+	session := authApi.Session{ID: "session", Subject: "user", LoginTime: now, LastRefresh: now}
+	if err := list.Record(session, "first", now.Add(time.Minute)); err != nil {
+		t.Fatalf("Expected no error when recording token, but got %v.", err)
+	}
+
+	session.LastRefresh = now.Add(time.Second)
+	if err := list.Record(session, "second", now.Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error when recording token, but got %v.", err)
+	}
+
+	sessions := list.List(now)
+	if len(sessions) != 1 || sessions[0].Subject != "user" || !sessions[0].LastRefresh.Equal(session.LastRefresh) ||
+		sessions[0].ExpiresAt == nil || sessions[0].ExpiresAt.Unix() != now.Add(time.Hour).Unix() {
+		t.Fatalf("Expected single session of user expiring with the latest token, but got %+v.", sessions)
+	}
+
+	if tokens := list.Tokens("session"); len(tokens) != 2 {
+		t.Errorf("Expected both tokens to be recorded, but got %v.", tokens)
+	}
+
+	stored, err := client.CoreV1().Secrets(args.Holder.GetNamespace()).Get(context.TODO(), authApi.SessionsHolderName,
+		metaV1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stored.Data) != 1 {
+		t.Errorf("Expected expired and invalid sessions to be removed from the secret, but got %v.", stored.Data)
+	}
+
+	if err = list.Remove("session"); err != nil {
+		t.Fatalf("Expected no error when removing session, but got %v.", err)
+	}
+
+	if sessions = list.List(now); len(sessions) != 0 || list.Tokens("session") != nil {
+		t.Errorf("Expected session to be removed, but got %+v.", sessions)
+	}
+}
+
+func TestSecretSessionList_RecordCreatesSecret(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	syncManager := sync.NewSynchronizerManager(client)
+	list := NewSecretSessionList(syncManager.Secret(args.Holder.GetNamespace(), authApi.SessionsHolderName))
+
+	if err := list.Record(authApi.Session{ID: "session"}, "token", time.Time{}); err != nil {
+		t.Fatalf("Expected no error when recording token, but got %v.", err)
+	}
+
+	if sessions := list.List(time.Now()); len(sessions) != 1 || sessions[0].ExpiresAt != nil {
+		t.Errorf("Expected single session that never expires, but got %+v.", sessions)
+	}
+
+	if _, err := client.CoreV1().Secrets(args.Holder.GetNamespace()).Get(context.TODO(), authApi.SessionsHolderName,
+		metaV1.GetOptions{}); err != nil {
+		t.Errorf("Expected secret to be created, but got %v.", err)
+	}
+}
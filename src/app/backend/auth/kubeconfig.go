@@ -15,6 +15,7 @@
 package auth
 
 import (
+	"github.com/kubernetes/dashboard/src/app/backend/args"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
 
@@ -24,6 +25,7 @@ import (
 
 // Below structures represent structure of kubeconfig file. They only contain fields required to gather data needed
 // to log in user. It should support same auth options as defined in auth/api/types.go file. Currently: basic, token.
+// Tokens can also be provided by exec credential plugins allowed by --kubeconfig-exec-plugins argument.
 
 type contextInfo struct {
 	User string `yaml:"user"`
@@ -49,6 +51,7 @@ type authProviderInfo struct {
 
 type userInfo struct {
 	AuthProvider authProviderInfo `yaml:"auth-provider"`
+	Exec         *execConfig      `yaml:"exec"`
 	Token        string           `yaml:"token"`
 	Username     string           `yaml:"username"`
 	Password     string           `yaml:"password"`
@@ -64,6 +67,7 @@ type kubeConfig struct {
 type kubeConfigAuthenticator struct {
 	fileContent []byte
 	authModes   authApi.AuthenticationModes
+	execPlugins []string
 }
 
 // GetAuthInfo implements Authenticator interface. See Authenticator for more information.
@@ -120,6 +124,15 @@ func (self *kubeConfigAuthenticator) getAuthInfo(info userInfo) (api.AuthInfo, e
 		info.Token = info.AuthProvider.Config.AccessToken
 	}
 
+	// Otherwise run the exec credential plugin, if there is one.
+	if len(info.Token) == 0 && info.Exec != nil && self.authModes.IsEnabled(authApi.Token) {
+		token, err := getExecToken(info.Exec, self.execPlugins)
+		if err != nil {
+			return api.AuthInfo{}, err
+		}
+		info.Token = token
+	}
+
 	if len(info.Token) == 0 && (len(info.Password) == 0 || len(info.Username) == 0) {
 		return api.AuthInfo{}, errors.NewInvalid("Not enough data to create auth info structure.")
 	}
@@ -142,5 +155,6 @@ func NewKubeConfigAuthenticator(spec *authApi.LoginSpec, authModes authApi.Authe
 	return &kubeConfigAuthenticator{
 		fileContent: []byte(spec.KubeConfig),
 		authModes:   authModes,
+		execPlugins: args.Holder.GetKubeConfigExecPlugins(),
 	}
 }
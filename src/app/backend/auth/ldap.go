@@ -0,0 +1,194 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+
+	"github.com/go-ldap/ldap/v3"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// LDAPConfig contains settings of the LDAP or Active Directory server used by the ldap authentication mode.
+type LDAPConfig struct {
+	// URL of the server, i.e. 'ldaps://ldap.example.com:636'.
+	URL string
+	// StartTLS upgrades plain 'ldap://' connections to TLS before any credentials are sent.
+	StartTLS bool
+	// InsecureSkipVerify disables verification of the server certificate.
+	InsecureSkipVerify bool
+	// BindDN and BindPassword are used to look up users and their groups. Anonymous search is used if empty.
+	BindDN       string
+	BindPassword string
+	// BaseDN is the subtree in which users and groups are searched.
+	BaseDN string
+	// UserFilter finds the entry of the user logging in. '%s' is replaced with the escaped username.
+	UserFilter string
+	// GroupFilter finds groups of the user. '%s' is replaced with the escaped DN of the user entry.
+	GroupFilter string
+	// GroupAttribute of group entries is used as the Kubernetes group name.
+	GroupAttribute string
+}
+
+// GetLDAPConfig returns LDAP settings passed to Dashboard binary.
+func GetLDAPConfig() LDAPConfig {
+	return LDAPConfig{
+		URL:                args.Holder.GetLDAPURL(),
+		StartTLS:           args.Holder.GetLDAPStartTLS(),
+		InsecureSkipVerify: args.Holder.GetLDAPInsecureSkipVerify(),
+		BindDN:             args.Holder.GetLDAPBindDN(),
+		BindPassword:       args.Holder.GetLDAPBindPassword(),
+		BaseDN:             args.Holder.GetLDAPBaseDN(),
+		UserFilter:         args.Holder.GetLDAPUserFilter(),
+		GroupFilter:        args.Holder.GetLDAPGroupFilter(),
+		GroupAttribute:     args.Holder.GetLDAPGroupAttribute(),
+	}
+}
+
+// ldapConn is the part of LDAP connection used by the authenticator.
+type ldapConn interface {
+	StartTLS(config *tls.Config) error
+	Bind(username, password string) error
+	Search(request *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close()
+}
+
+// dialLDAP opens connection to the LDAP server. Replaced in tests.
+var dialLDAP = func(config LDAPConfig) (ldapConn, error) {
+	return ldap.DialURL(config.URL, ldap.DialWithTLSConfig(config.tlsConfig()))
+}
+
+func (self LDAPConfig) tlsConfig() *tls.Config {
+	return &tls.Config{InsecureSkipVerify: self.InsecureSkipVerify}
+}
+
+// Implements Authenticator interface
+type ldapAuthenticator struct {
+	username string
+	password string
+	config   LDAPConfig
+}
+
+// GetAuthInfo implements Authenticator interface. See Authenticator for more information. Credentials are verified
+// by binding to the LDAP server as the user. Returned AuthInfo impersonates the user and its LDAP groups, the
+// requests are then made with privileges of Dashboard, which has to be allowed to impersonate them.
+func (self *ldapAuthenticator) GetAuthInfo() (api.AuthInfo, error) {
+	// Bind with an empty password is an unauthenticated bind that succeeds for any existing DN.
+	if len(self.username) == 0 || len(self.password) == 0 {
+		return api.AuthInfo{}, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	conn, err := dialLDAP(self.config)
+	if err != nil {
+		return api.AuthInfo{}, err
+	}
+	defer conn.Close()
+
+	if self.config.StartTLS {
+		if err = conn.StartTLS(self.config.tlsConfig()); err != nil {
+			return api.AuthInfo{}, err
+		}
+	}
+
+	if err = self.bindSearchUser(conn); err != nil {
+		return api.AuthInfo{}, err
+	}
+
+	userDN, err := self.findUser(conn)
+	if err != nil {
+		return api.AuthInfo{}, err
+	}
+
+	if err = conn.Bind(userDN, self.password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return api.AuthInfo{}, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+		}
+		return api.AuthInfo{}, err
+	}
+
+	// Groups are looked up as the search user again, or as the user itself when no search user is set.
+	if err = self.bindSearchUser(conn); err != nil {
+		return api.AuthInfo{}, err
+	}
+
+	groups, err := self.findGroups(conn, userDN)
+	if err != nil {
+		return api.AuthInfo{}, err
+	}
+
+	return api.AuthInfo{
+		Impersonate:       self.username,
+		ImpersonateGroups: groups,
+	}, nil
+}
+
+func (self *ldapAuthenticator) bindSearchUser(conn ldapConn) error {
+	if len(self.config.BindDN) == 0 {
+		return nil
+	}
+
+	return conn.Bind(self.config.BindDN, self.config.BindPassword)
+}
+
+func (self *ldapAuthenticator) findUser(conn ldapConn) (string, error) {
+	filter := fmt.Sprintf(self.config.UserFilter, ldap.EscapeFilter(self.username))
+	result, err := conn.Search(ldap.NewSearchRequest(self.config.BaseDN, ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases, 2, 0, false, filter, []string{"dn"}, nil))
+	if err != nil {
+		return "", err
+	}
+
+	if len(result.Entries) != 1 {
+		log.Printf("LDAP login of %s failed: found %d matching entries", self.username, len(result.Entries))
+		return "", errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return result.Entries[0].DN, nil
+}
+
+func (self *ldapAuthenticator) findGroups(conn ldapConn, userDN string) ([]string, error) {
+	if len(self.config.GroupFilter) == 0 {
+		return nil, nil
+	}
+
+	filter := fmt.Sprintf(self.config.GroupFilter, ldap.EscapeFilter(userDN))
+	result, err := conn.Search(ldap.NewSearchRequest(self.config.BaseDN, ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases, 0, 0, false, filter, []string{self.config.GroupAttribute}, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		groups = append(groups, entry.GetAttributeValues(self.config.GroupAttribute)...)
+	}
+
+	return groups, nil
+}
+
+// NewLDAPAuthenticator returns Authenticator based on LoginSpec that verifies credentials against LDAP server.
+func NewLDAPAuthenticator(spec *authApi.LoginSpec, config LDAPConfig) authApi.Authenticator {
+	return &ldapAuthenticator{
+		username: spec.Username,
+		password: spec.Password,
+		config:   config,
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+type fakeLDAPConn struct {
+	passwords map[string]string
+	users     map[string]string
+	groups    map[string][]string
+	bound     string
+	filters   []string
+}
+
+func (self *fakeLDAPConn) StartTLS(config *tls.Config) error {
+	return nil
+}
+
+func (self *fakeLDAPConn) Bind(username, password string) error {
+	if expected, ok := self.passwords[username]; !ok || expected != password {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.NewUnauthorized("invalid credentials"))
+	}
+
+	self.bound = username
+	return nil
+}
+
+func (self *fakeLDAPConn) Search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	self.filters = append(self.filters, request.Filter)
+	result := &ldap.SearchResult{}
+	if dn, ok := self.users[request.Filter]; ok {
+		result.Entries = append(result.Entries, ldap.NewEntry(dn, nil))
+	}
+
+	for _, group := range self.groups[request.Filter] {
+		result.Entries = append(result.Entries, ldap.NewEntry("cn="+group+",ou=groups,dc=example,dc=com",
+			map[string][]string{"cn": {group}}))
+	}
+
+	return result, nil
+}
+
+func (self *fakeLDAPConn) Close() {}
+
+func TestLDAPAuthenticator(t *testing.T) {
+	config := LDAPConfig{
+		BindDN:         "cn=dashboard,dc=example,dc=com",
+		BindPassword:   "secret",
+		BaseDN:         "dc=example,dc=com",
+		UserFilter:     "(uid=%s)",
+		GroupFilter:    "(member=%s)",
+		GroupAttribute: "cn",
+	}
+	userDN := "uid=jane,ou=people,dc=example,dc=com"
+
+	cases := []struct {
+		info        string
+		spec        *authApi.LoginSpec
+		expected    api.AuthInfo
+		expectedErr error
+	}{
+		{
+			"should impersonate user and its groups",
+			&authApi.LoginSpec{Username: "jane", Password: "pass"},
+			api.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{"admins", "developers"}},
+			nil,
+		},
+		{
+			"should reject wrong password",
+			&authApi.LoginSpec{Username: "jane", Password: "wrong"},
+			api.AuthInfo{},
+			errors.NewUnauthorized(errors.MsgLoginUnauthorizedError),
+		},
+		{
+			"should reject unknown user",
+			&authApi.LoginSpec{Username: "john", Password: "pass"},
+			api.AuthInfo{},
+			errors.NewUnauthorized(errors.MsgLoginUnauthorizedError),
+		},
+		{
+			"should reject empty password",
+			&authApi.LoginSpec{Username: "jane"},
+			api.AuthInfo{},
+			errors.NewUnauthorized(errors.MsgLoginUnauthorizedError),
+		},
+	}
+
+	for _, c := range cases {
+		conn := &fakeLDAPConn{
+			passwords: map[string]string{config.BindDN: config.BindPassword, userDN: "pass"},
+			users:     map[string]string{"(uid=jane)": userDN},
+			groups:    map[string][]string{"(member=" + userDN + ")": {"admins", "developers"}},
+		}
+		dialLDAP = func(LDAPConfig) (ldapConn, error) { return conn, nil }
+
+		actual, err := NewLDAPAuthenticator(c.spec, config).GetAuthInfo()
+		if !areErrorsEqual(err, c.expectedErr) {
+			t.Errorf("Test Case: %s. Expected error to be: %v, but got %v.", c.info, c.expectedErr, err)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Expected auth info to be: %#v, but got %#v.", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestLDAPAuthenticatorEscapesUsername(t *testing.T) {
+	conn := &fakeLDAPConn{passwords: map[string]string{}}
+	dialLDAP = func(LDAPConfig) (ldapConn, error) { return conn, nil }
+
+	config := LDAPConfig{UserFilter: "(uid=%s)"}
+	_, err := NewLDAPAuthenticator(&authApi.LoginSpec{Username: "*)(uid=*", Password: "pass"}, config).GetAuthInfo()
+	if !areErrorsEqual(err, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)) {
+		t.Fatalf("Expected unauthorized error, but got %v", err)
+	}
+
+	expected := []string{`(uid=\2a\29\28uid=\2a)`}
+	if !reflect.DeepEqual(conn.filters, expected) {
+		t.Fatalf("Expected filters %v, but got %v", expected, conn.filters)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+// Lockouts double with every failure, this caps the exponent to avoid overflows.
+const maxLockoutExponent = 30
+
+var (
+	loginFailures = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "dashboard_login_failures_total",
+			Help: "Counter of failed login attempts.",
+		},
+	)
+	loginLockouts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "dashboard_login_lockouts_total",
+			Help: "Counter of lockouts of source IP addresses or usernames after too many failed login attempts.",
+		},
+	)
+	loginRejected = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "dashboard_login_rejected_total",
+			Help: "Counter of login attempts rejected because of a lockout.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(loginFailures)
+	prometheus.MustRegister(loginLockouts)
+	prometheus.MustRegister(loginRejected)
+}
+
+// LoginLimiter protects login against brute-force attacks. It tracks failed login attempts per key, i.e. source IP
+// address or username. After too many failures further attempts with the key are locked out for a time, which doubles
+// with every next failure.
+type LoginLimiter struct {
+	mu          sync.Mutex
+	maxAttempts int
+	lockout     time.Duration
+	maxLockout  time.Duration
+	attempts    map[string]*loginAttempts
+	now         func() time.Time
+}
+
+type loginAttempts struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// Check returns remaining time of the longest lockout of given keys. Zero means that login attempt is allowed.
+func (self *LoginLimiter) Check(keys ...string) time.Duration {
+	if self.maxAttempts <= 0 {
+		return 0
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := self.now()
+	var result time.Duration
+	for _, key := range keys {
+		if attempts, ok := self.attempts[key]; ok && attempts.lockedUntil.Sub(now) > result {
+			result = attempts.lockedUntil.Sub(now)
+		}
+	}
+
+	if result > 0 {
+		loginRejected.Inc()
+	}
+
+	return result
+}
+
+// Fail records failed login attempt for all given keys.
+func (self *LoginLimiter) Fail(keys ...string) {
+	if self.maxAttempts <= 0 {
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := self.now()
+	self.prune(now)
+	loginFailures.Inc()
+	for _, key := range keys {
+		attempts, ok := self.attempts[key]
+		if !ok {
+			attempts = &loginAttempts{}
+			self.attempts[key] = attempts
+		}
+
+		attempts.failures++
+		attempts.lastFailure = now
+		if exponent := attempts.failures - self.maxAttempts; exponent >= 0 && self.lockout > 0 {
+			if exponent > maxLockoutExponent {
+				exponent = maxLockoutExponent
+			}
+
+			lockout := self.lockout << uint(exponent)
+			if lockout > self.maxLockout || lockout <= 0 {
+				lockout = self.maxLockout
+			}
+
+			attempts.lockedUntil = now.Add(lockout)
+			loginLockouts.Inc()
+		}
+	}
+}
+
+// Succeed forgets failed login attempts of given keys.
+func (self *LoginLimiter) Succeed(keys ...string) {
+	if self.maxAttempts <= 0 {
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, key := range keys {
+		delete(self.attempts, key)
+	}
+}
+
+// Forgets keys, that are not locked out and did not fail for the max lockout duration.
+func (self *LoginLimiter) prune(now time.Time) {
+	for key, attempts := range self.attempts {
+		if now.After(attempts.lockedUntil) && now.Sub(attempts.lastFailure) > self.maxLockout {
+			delete(self.attempts, key)
+		}
+	}
+}
+
+// Returns limiter keys of the login request.
+func getLoginLimiterKeys(request *http.Request, spec *authApi.LoginSpec) []string {
+	keys := []string{"ip/" + authApi.GetSourceIP(request)}
+	if len(spec.Username) > 0 {
+		keys = append(keys, getUsernameLimiterKey(spec.Username))
+	}
+
+	return keys
+}
+
+func getUsernameLimiterKey(username string) string {
+	return "user/" + username
+}
+
+// NewLoginLimiter creates login limiter locking keys out after maxAttempts failed login attempts. Lockouts start at
+// lockout duration and are capped at maxLockout. Limiter with maxAttempts lower than 1 allows all attempts.
+func NewLoginLimiter(maxAttempts int, lockout, maxLockout time.Duration) *LoginLimiter {
+	if maxLockout < lockout {
+		maxLockout = lockout
+	}
+
+	return &LoginLimiter{
+		maxAttempts: maxAttempts,
+		lockout:     lockout,
+		maxLockout:  maxLockout,
+		attempts:    make(map[string]*loginAttempts),
+		now:         time.Now,
+	}
+}
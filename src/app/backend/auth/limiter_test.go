@@ -0,0 +1,99 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+func TestLoginLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := NewLoginLimiter(2, time.Second, 4*time.Second)
+	limiter.now = func() time.Time { return now }
+
+	steps := []struct {
+		info     string
+		fail     bool
+		advance  time.Duration
+		expected time.Duration
+	}{
+		{"first failure is allowed", true, 0, 0},
+		{"second failure locks out", true, 0, time.Second},
+		{"lockout passes", false, time.Second, 0},
+		{"third failure doubles lockout", true, 0, 2 * time.Second},
+		{"fourth failure doubles lockout", true, 0, 4 * time.Second},
+		{"lockout is capped", true, 0, 4 * time.Second},
+	}
+
+	for _, s := range steps {
+		now = now.Add(s.advance)
+		if s.fail {
+			limiter.Fail("ip/10.0.0.1", "user/admin")
+		}
+
+		if actual := limiter.Check("user/admin"); actual != s.expected {
+			t.Errorf("%s: expected lockout to be %v, but got %v", s.info, s.expected, actual)
+		}
+	}
+
+	limiter.Succeed("user/admin")
+	if actual := limiter.Check("user/admin"); actual != 0 {
+		t.Errorf("Expected username to be forgotten after successful login, but got lockout %v", actual)
+	}
+
+	if actual := limiter.Check("ip/10.0.0.1", "user/admin"); actual != 4*time.Second {
+		t.Errorf("Expected IP address to stay locked out, but got lockout %v", actual)
+	}
+
+	now = now.Add(10 * time.Second)
+	limiter.Fail("ip/10.0.0.2")
+	if len(limiter.attempts) != 1 {
+		t.Errorf("Expected old failures to be pruned, but got %d keys", len(limiter.attempts))
+	}
+}
+
+func TestLoginLimiterDisabled(t *testing.T) {
+	limiter := NewLoginLimiter(0, time.Second, time.Second)
+	for i := 0; i < 10; i++ {
+		limiter.Fail("ip/10.0.0.1")
+	}
+
+	if actual := limiter.Check("ip/10.0.0.1"); actual != 0 {
+		t.Errorf("Expected disabled limiter to allow login, but got lockout %v", actual)
+	}
+}
+
+func TestGetLoginLimiterKeys(t *testing.T) {
+	cases := []struct {
+		remoteAddr string
+		spec       *authApi.LoginSpec
+		expected   []string
+	}{
+		{"10.0.0.1:53412", &authApi.LoginSpec{Token: "token"}, []string{"ip/10.0.0.1"}},
+		{"[::1]:53412", &authApi.LoginSpec{Username: "admin"}, []string{"ip/::1", "user/admin"}},
+	}
+
+	for _, c := range cases {
+		actual := getLoginLimiterKeys(&http.Request{RemoteAddr: c.remoteAddr}, c.spec)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getLoginLimiterKeys(%s, %#v) == %v, expected %v", c.remoteAddr, c.spec, actual, c.expected)
+		}
+	}
+}
@@ -15,9 +15,13 @@
 package auth
 
 import (
+	"time"
+
 	"k8s.io/client-go/tools/clientcmd/api"
 
+	"github.com/kubernetes/dashboard/src/app/backend/args"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/auth/webauthn"
 	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
 )
@@ -28,10 +32,18 @@ type authManager struct {
 	clientManager           clientapi.ClientManager
 	authenticationModes     authApi.AuthenticationModes
 	authenticationSkippable bool
+	// webAuthn is nil unless second factor is enabled.
+	webAuthn *webauthn.Manager
+	// loginNotice has to be acknowledged by every login if it is required.
+	loginNotice authApi.LoginNotice
 }
 
 // Login implements auth manager. See AuthManager interface for more information.
 func (self authManager) Login(spec *authApi.LoginSpec) (*authApi.AuthResponse, error) {
+	if self.loginNotice.AcknowledgmentRequired && spec.NoticeAcknowledgment != self.loginNotice.ID {
+		return nil, errors.NewBadRequest(authApi.MsgLoginNoticeNotAcknowledged)
+	}
+
 	authenticator, err := self.getAuthenticator(spec)
 	if err != nil {
 		return nil, err
@@ -45,15 +57,21 @@ func (self authManager) Login(spec *authApi.LoginSpec) (*authApi.AuthResponse, e
 	err = self.healthCheck(authInfo)
 	nonCriticalErrors, criticalError := errors.HandleError(err)
 	if criticalError != nil || len(nonCriticalErrors) > 0 {
-		return &authApi.AuthResponse{Errors: nonCriticalErrors}, criticalError
+		return &authApi.AuthResponse{Errors: errors.NewAPIErrors(nonCriticalErrors)}, criticalError
 	}
 
-	token, err := self.tokenManager.Generate(authInfo)
+	challenge, err := self.verifySecondFactor(authInfo, spec)
+	if err != nil || challenge != nil {
+		return &authApi.AuthResponse{Errors: errors.NewAPIErrors(nonCriticalErrors), WebAuthnChallenge: challenge}, err
+	}
+
+	groups := GetGroups(authInfo)
+	token, err := self.generate(authInfo, groups)
 	if err != nil {
 		return nil, err
 	}
 
-	return &authApi.AuthResponse{JWEToken: token, Errors: nonCriticalErrors}, nil
+	return &authApi.AuthResponse{JWEToken: token, Errors: errors.NewAPIErrors(nonCriticalErrors), Groups: groups}, nil
 }
 
 // Refresh implements auth manager. See AuthManager interface for more information.
@@ -61,14 +79,386 @@ func (self authManager) Refresh(jweToken string) (string, error) {
 	return self.tokenManager.Refresh(jweToken)
 }
 
+// Logout implements auth manager. See AuthManager interface for more information.
+func (self authManager) Logout(jweToken string) error {
+	return self.tokenManager.Revoke(jweToken)
+}
+
+// EmbedToken implements auth manager. See AuthManager interface for more information.
+func (self authManager) EmbedToken(jweToken string, spec *authApi.EmbedTokenSpec) (*authApi.EmbedTokenResponse,
+	error) {
+	embedTokenManager, ok := self.tokenManager.(authApi.EmbedTokenManager)
+	if !ok {
+		return nil, errors.NewInvalid("Can not generate embed token. Token manager does not support embed tokens.")
+	}
+
+	if len(spec.Scope.Kind) == 0 {
+		return nil, errors.NewInvalid("Can not generate embed token. No resource kind provided.")
+	}
+
+	if len(jweToken) == 0 {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	// Embed tokens outlive elevation of the session, so they are generated with its own credentials.
+	authInfo, err := self.getSessionAuthInfo(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := spec.TTL
+	if ttl <= 0 {
+		ttl = authApi.DefaultEmbedTokenTTL
+	}
+
+	token, err := embedTokenManager.GenerateEmbed(*authInfo, spec.Scope, time.Duration(ttl)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authApi.EmbedTokenResponse{EmbedToken: token}, nil
+}
+
+// Elevate implements auth manager. See AuthManager interface for more information.
+func (self authManager) Elevate(jweToken string, spec *authApi.ElevationSpec) (*authApi.AuthResponse, error) {
+	elevationTokenManager, ok := self.tokenManager.(authApi.ElevationTokenManager)
+	maxDuration := int64(args.Holder.GetMaxElevationDuration())
+	if !ok || maxDuration <= 0 {
+		return nil, errors.NewInvalid("Can not elevate token. Elevation is disabled.")
+	}
+
+	if len(jweToken) == 0 {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	authenticator, err := self.getAuthenticator(&spec.LoginSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	authInfo, err := authenticator.GetAuthInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	err = self.healthCheck(authInfo)
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil || len(nonCriticalErrors) > 0 {
+		return &authApi.AuthResponse{Errors: errors.NewAPIErrors(nonCriticalErrors)}, criticalError
+	}
+
+	challenge, err := self.verifySecondFactor(authInfo, &spec.LoginSpec)
+	if err != nil || challenge != nil {
+		return &authApi.AuthResponse{Errors: errors.NewAPIErrors(nonCriticalErrors), WebAuthnChallenge: challenge}, err
+	}
+
+	duration := spec.Duration
+	if duration <= 0 || duration > maxDuration {
+		duration = maxDuration
+	}
+
+	token, err := elevationTokenManager.Elevate(jweToken, authInfo, time.Now().Add(time.Duration(duration)*time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	return &authApi.AuthResponse{JWEToken: token, Errors: errors.NewAPIErrors(nonCriticalErrors)}, nil
+}
+
+// Sessions implements auth manager. See AuthManager interface for more information.
+func (self authManager) Sessions() ([]authApi.Session, error) {
+	sessionTokenManager, ok := self.tokenManager.(authApi.SessionTokenManager)
+	if !ok {
+		return nil, errors.NewInvalid("Can not list sessions. Token manager does not keep track of sessions.")
+	}
+
+	return sessionTokenManager.Sessions()
+}
+
+// ExpireSession implements auth manager. See AuthManager interface for more information.
+func (self authManager) ExpireSession(id string) error {
+	sessionTokenManager, ok := self.tokenManager.(authApi.SessionTokenManager)
+	if !ok {
+		return errors.NewInvalid("Can not expire session. Token manager does not keep track of sessions.")
+	}
+
+	if len(id) == 0 {
+		return errors.NewInvalid("Can not expire session. No session ID provided.")
+	}
+
+	return sessionTokenManager.ExpireSession(id)
+}
+
+// OwnSessions implements auth manager. See AuthManager interface for more information.
+func (self authManager) OwnSessions(jweToken string) ([]authApi.Session, error) {
+	sessionTokenManager, current, err := self.getCurrentSession(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := sessionTokenManager.Sessions()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]authApi.Session, 0)
+	for _, session := range sessions {
+		if session.Subject == current.Subject {
+			session.Current = session.ID == current.ID
+			result = append(result, session)
+		}
+	}
+
+	return result, nil
+}
+
+// ExpireOwnSession implements auth manager. See AuthManager interface for more information.
+func (self authManager) ExpireOwnSession(jweToken, id string) error {
+	sessionTokenManager, current, err := self.getCurrentSession(jweToken)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := sessionTokenManager.Sessions()
+	if err != nil {
+		return err
+	}
+
+	// Sessions of other users are reported as not found, so their IDs can not be probed.
+	for _, session := range sessions {
+		if session.ID == id && session.Subject == current.Subject {
+			return sessionTokenManager.ExpireSession(id)
+		}
+	}
+
+	return errors.NewNotFound("Session not found.")
+}
+
+// RecordSessionClient implements auth manager. See AuthManager interface for more information.
+func (self authManager) RecordSessionClient(jweToken string, client authApi.SessionClient) error {
+	// There is nothing to record if token manager does not keep track of sessions.
+	if _, ok := self.tokenManager.(authApi.SessionTokenManager); !ok {
+		return nil
+	}
+
+	sessionTokenManager, current, err := self.getCurrentSession(jweToken)
+	if err != nil {
+		return err
+	}
+
+	return sessionTokenManager.SetSessionClient(current.ID, client)
+}
+
+// SessionID implements auth manager. See AuthManager interface for more information.
+func (self authManager) SessionID(jweToken string) (string, error) {
+	if _, ok := self.tokenManager.(authApi.SessionTokenManager); !ok {
+		return "", nil
+	}
+
+	_, current, err := self.getCurrentSession(jweToken)
+	if err != nil {
+		return "", err
+	}
+
+	return current.ID, nil
+}
+
+// Groups implements auth manager. See AuthManager interface for more information.
+func (self authManager) Groups(jweToken string) ([]string, error) {
+	groupTokenManager, ok := self.tokenManager.(authApi.GroupTokenManager)
+	if !ok {
+		return nil, errors.NewInvalid("Token manager does not keep groups of the user.")
+	}
+
+	if len(jweToken) == 0 {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return groupTokenManager.Groups(jweToken)
+}
+
+// EncryptState implements auth manager. See AuthManager interface for more information.
+func (self authManager) EncryptState(owner string, state []byte) (string, error) {
+	stateTokenManager, ok := self.tokenManager.(authApi.StateTokenManager)
+	if !ok {
+		return "", errors.NewInvalid("Token manager does not support encryption of client state.")
+	}
+
+	return stateTokenManager.EncryptState(owner, state)
+}
+
+// DecryptState implements auth manager. See AuthManager interface for more information.
+func (self authManager) DecryptState(owner, encrypted string) ([]byte, error) {
+	stateTokenManager, ok := self.tokenManager.(authApi.StateTokenManager)
+	if !ok {
+		return nil, errors.NewInvalid("Token manager does not support encryption of client state.")
+	}
+
+	return stateTokenManager.DecryptState(owner, encrypted)
+}
+
+// BeginWebAuthnRegistration implements auth manager. See AuthManager interface for more information.
+func (self authManager) BeginWebAuthnRegistration(jweToken string) (*authApi.WebAuthnRegistration, error) {
+	subject, err := self.getWebAuthnSubject(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.webAuthn.BeginRegistration(subject)
+}
+
+// FinishWebAuthnRegistration implements auth manager. See AuthManager interface for more information.
+func (self authManager) FinishWebAuthnRegistration(jweToken string,
+	attestation *authApi.WebAuthnAttestation) (*authApi.WebAuthnCredential, error) {
+	subject, err := self.getWebAuthnSubject(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := self.webAuthn.FinishRegistration(subject, attestation)
+	if err != nil {
+		return nil, err
+	}
+
+	info := credential.Info()
+	return &info, nil
+}
+
+// WebAuthnCredentials implements auth manager. See AuthManager interface for more information.
+func (self authManager) WebAuthnCredentials(jweToken string) ([]authApi.WebAuthnCredential, error) {
+	subject, err := self.getWebAuthnSubject(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := self.webAuthn.Credentials(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]authApi.WebAuthnCredential, 0, len(credentials))
+	for _, credential := range credentials {
+		result = append(result, credential.Info())
+	}
+
+	return result, nil
+}
+
+// DeleteWebAuthnCredential implements auth manager. See AuthManager interface for more information.
+func (self authManager) DeleteWebAuthnCredential(jweToken, id string) error {
+	subject, err := self.getWebAuthnSubject(jweToken)
+	if err != nil {
+		return err
+	}
+
+	return self.webAuthn.DeleteCredential(subject, id)
+}
+
+// AuthenticationModes returns modes reflected on the login screen. Users of the header mode are logged in by the
+// authenticating proxy, so it is not one of them.
 func (self authManager) AuthenticationModes() []authApi.AuthenticationMode {
-	return self.authenticationModes.Array()
+	modes := []authApi.AuthenticationMode{}
+	for _, mode := range self.authenticationModes.Array() {
+		if mode != authApi.Header {
+			modes = append(modes, mode)
+		}
+	}
+
+	return modes
 }
 
 func (self authManager) AuthenticationSkippable() bool {
 	return self.authenticationSkippable
 }
 
+// LoginNotice implements auth manager. See AuthManager interface for more information.
+func (self authManager) LoginNotice() authApi.LoginNotice {
+	return self.loginNotice
+}
+
+// Generates token with groups of the user if token manager can keep them.
+func (self authManager) generate(authInfo api.AuthInfo, groups []string) (string, error) {
+	if groupTokenManager, ok := self.tokenManager.(authApi.GroupTokenManager); ok && len(groups) > 0 {
+		return groupTokenManager.GenerateWithGroups(authInfo, groups)
+	}
+
+	return self.tokenManager.Generate(authInfo)
+}
+
+// Verifies the second factor of the user if it has registered one. Returns the challenge the user has to answer if
+// login spec does not contain the assertion yet.
+func (self authManager) verifySecondFactor(authInfo api.AuthInfo, spec *authApi.LoginSpec) (
+	*authApi.WebAuthnChallenge, error) {
+	subject := webauthn.Subject(authInfo)
+	if self.webAuthn == nil || len(subject) == 0 {
+		return nil, nil
+	}
+
+	if spec.WebAuthn == nil {
+		return self.webAuthn.Challenge(subject)
+	}
+
+	return nil, self.webAuthn.VerifyAssertion(subject, spec.WebAuthn)
+}
+
+// Returns subject second factor of the session is registered under. Second factor is registered for own credentials
+// of the session, not for the elevated ones.
+func (self authManager) getWebAuthnSubject(jweToken string) (string, error) {
+	if self.webAuthn == nil {
+		return "", errors.NewInvalid("Second factor is disabled. Check --enable-webauthn argument for more information.")
+	}
+
+	if len(jweToken) == 0 {
+		return "", errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	authInfo, err := self.getSessionAuthInfo(jweToken)
+	if err != nil {
+		return "", err
+	}
+
+	subject := webauthn.Subject(*authInfo)
+	if len(subject) == 0 {
+		return "", errors.NewInvalid("Second factor can be registered only by users of basic and LDAP login.")
+	}
+
+	return subject, nil
+}
+
+// Returns own AuthInfo of the session, ignoring its elevation.
+func (self authManager) getSessionAuthInfo(jweToken string) (*api.AuthInfo, error) {
+	elevationTokenManager, ok := self.tokenManager.(authApi.ElevationTokenManager)
+	if !ok {
+		return self.tokenManager.Decrypt(jweToken)
+	}
+
+	info, err := elevationTokenManager.DecryptElevated(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info.AuthInfo, nil
+}
+
+// Returns session token manager together with the session of provided token.
+func (self authManager) getCurrentSession(jweToken string) (authApi.SessionTokenManager, *authApi.Session, error) {
+	sessionTokenManager, ok := self.tokenManager.(authApi.SessionTokenManager)
+	if !ok {
+		return nil, nil, errors.NewInvalid("Token manager does not keep track of sessions.")
+	}
+
+	if len(jweToken) == 0 {
+		return nil, nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	current, err := sessionTokenManager.SessionOf(jweToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sessionTokenManager, current, nil
+}
+
 // Returns authenticator based on provided LoginSpec.
 func (self authManager) getAuthenticator(spec *authApi.LoginSpec) (authApi.Authenticator, error) {
 	if len(self.authenticationModes) == 0 {
@@ -77,8 +467,15 @@ func (self authManager) getAuthenticator(spec *authApi.LoginSpec) (authApi.Authe
 
 	switch {
 	case len(spec.Token) > 0 && self.authenticationModes.IsEnabled(authApi.Token):
+		if config := GetTokenReviewConfig(); config.Enabled {
+			return NewTokenReviewAuthenticator(spec, self.clientManager.InsecureClient(), config), nil
+		}
 		return NewTokenAuthenticator(spec), nil
-	case len(spec.Username) > 0 && len(spec.Password) > 0 && self.authenticationModes.IsEnabled(authApi.Basic):
+	case len(spec.Username) > 0 && len(spec.Password) > 0 && spec.Mode != authApi.Basic &&
+		self.authenticationModes.IsEnabled(authApi.LDAP):
+		return NewLDAPAuthenticator(spec, GetLDAPConfig()), nil
+	case len(spec.Username) > 0 && len(spec.Password) > 0 && spec.Mode != authApi.LDAP &&
+		self.authenticationModes.IsEnabled(authApi.Basic):
 		return NewBasicAuthenticator(spec), nil
 	case len(spec.KubeConfig) > 0:
 		return NewKubeConfigAuthenticator(spec, self.authenticationModes), nil
@@ -96,10 +493,18 @@ func (self authManager) healthCheck(authInfo api.AuthInfo) error {
 // NewAuthManager creates auth manager.
 func NewAuthManager(clientManager clientapi.ClientManager, tokenManager authApi.TokenManager,
 	authenticationModes authApi.AuthenticationModes, authenticationSkippable bool) authApi.AuthManager {
-	return &authManager{
+	manager := &authManager{
 		tokenManager:            tokenManager,
 		clientManager:           clientManager,
 		authenticationModes:     authenticationModes,
 		authenticationSkippable: authenticationSkippable,
+		loginNotice:             GetLoginNotice(),
 	}
+
+	// Challenges are encrypted with keys of the token manager, so any replica can verify them.
+	if args.Holder.GetEnableWebAuthn() {
+		manager.webAuthn = webauthn.NewManager(webauthn.GetConfig(), clientManager.InsecureClient(), manager)
+	}
+
+	return manager
 }
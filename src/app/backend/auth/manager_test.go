@@ -21,14 +21,18 @@ import (
 
 	restful "github.com/emicklei/go-restful"
 
+	"github.com/kubernetes/dashboard/src/app/backend/args"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	"github.com/kubernetes/dashboard/src/app/backend/client"
 	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
 
 	pluginclientset "github.com/kubernetes/dashboard/src/app/backend/plugin/client/clientset/versioned"
 	v1 "k8s.io/api/authorization/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -68,8 +72,24 @@ func (self *fakeClientManager) InsecurePluginClient() pluginclientset.Interface
 	return nil
 }
 
+func (self *fakeClientManager) InsecureDynamicClient() dynamic.Interface {
+	return nil
+}
+
 func (self *fakeClientManager) SetTokenManager(manager authApi.TokenManager) {}
 
+func (self *fakeClientManager) SetFeatureGateManager(manager featuresApi.FeatureGateManager) {}
+
+func (self *fakeClientManager) SetAuditLogger(logger authApi.AuditLogger) {}
+
+func (self *fakeClientManager) SkipLoginIdentity() string {
+	return "anonymous"
+}
+
+func (self *fakeClientManager) HeaderAuthInfo(req *restful.Request) *api.AuthInfo {
+	return nil
+}
+
 func (self *fakeClientManager) Config(req *restful.Request) (*rest.Config, error) {
 	return nil, nil
 }
@@ -105,6 +125,12 @@ func (self *fakeTokenManager) Refresh(string) (string, error) {
 
 func (self *fakeTokenManager) SetTokenTTL(time.Duration) {}
 
+func (self *fakeTokenManager) SetTokenMaxLifetime(time.Duration) {}
+
+func (self *fakeTokenManager) Revoke(string) error {
+	return self.Error
+}
+
 func (self *fakeTokenManager) Generate(authInfo api.AuthInfo) (string, error) {
 	return self.GeneratedToken, self.Error
 }
@@ -113,6 +139,27 @@ func (self *fakeTokenManager) Decrypt(jweToken string) (*api.AuthInfo, error) {
 	return nil, nil
 }
 
+type fakeEmbedTokenManager struct {
+	fakeTokenManager
+	Scope authApi.EmbedScope
+	TTL   time.Duration
+}
+
+func (self *fakeEmbedTokenManager) Decrypt(jweToken string) (*api.AuthInfo, error) {
+	return &api.AuthInfo{Token: jweToken}, nil
+}
+
+func (self *fakeEmbedTokenManager) GenerateEmbed(authInfo api.AuthInfo, scope authApi.EmbedScope,
+	ttl time.Duration) (string, error) {
+	self.Scope = scope
+	self.TTL = ttl
+	return "embed-" + authInfo.Token, nil
+}
+
+func (self *fakeEmbedTokenManager) DecryptEmbed(string) (*api.AuthInfo, *authApi.EmbedScope, error) {
+	return nil, nil, nil
+}
+
 func TestAuthManager_Login(t *testing.T) {
 	unauthorizedErr := errors.NewUnauthorized("Unauthorized")
 
@@ -136,21 +183,21 @@ func TestAuthManager_Login(t *testing.T) {
 			&authApi.LoginSpec{Token: "not-existing-token"},
 			&fakeClientManager{HasAccessError: unauthorizedErr},
 			&fakeTokenManager{},
-			&authApi.AuthResponse{Errors: []error{unauthorizedErr}},
+			&authApi.AuthResponse{Errors: []*errors.APIError{errors.NewAPIError(unauthorizedErr)}},
 			nil,
 		}, {
 			"Recognized token should allow login and return JWE token",
 			&authApi.LoginSpec{Token: "existing-token"},
 			&fakeClientManager{HasAccessError: nil},
 			&fakeTokenManager{GeneratedToken: "generated-token"},
-			&authApi.AuthResponse{JWEToken: "generated-token", Errors: make([]error, 0)},
+			&authApi.AuthResponse{JWEToken: "generated-token", Errors: make([]*errors.APIError, 0)},
 			nil,
 		}, {
 			"Should propagate error on unexpected error",
 			&authApi.LoginSpec{Token: "test-token"},
 			&fakeClientManager{HasAccessError: errors.NewInvalid("Unexpected error")},
 			&fakeTokenManager{},
-			&authApi.AuthResponse{Errors: make([]error, 0)},
+			&authApi.AuthResponse{Errors: make([]*errors.APIError, 0)},
 			errors.NewInvalid("Unexpected error"),
 		},
 	}
@@ -171,6 +218,39 @@ func TestAuthManager_Login(t *testing.T) {
 	}
 }
 
+func TestAuthManager_LoginNotice(t *testing.T) {
+	args.GetHolderBuilder().SetLoginNotice("Authorized use only.").SetLoginNoticeAcknowledgment(true)
+	defer func() { args.GetHolderBuilder().SetLoginNotice("").SetLoginNoticeAcknowledgment(false) }()
+
+	authManager := NewAuthManager(&fakeClientManager{}, &fakeTokenManager{GeneratedToken: "generated-token"},
+		authApi.AuthenticationModes{authApi.Token: true}, true)
+	notice := authManager.LoginNotice()
+	if notice.Text != "Authorized use only." || !notice.AcknowledgmentRequired || len(notice.ID) == 0 {
+		t.Fatalf("Unexpected login notice %#v.", notice)
+	}
+
+	cases := []struct {
+		info           string
+		acknowledgment string
+		expectedErr    bool
+	}{
+		{"missing acknowledgment", "", true},
+		{"acknowledgment of other notice", NewLoginNotice("Other notice.", true).ID, true},
+		{"acknowledgment of the notice", notice.ID, false},
+	}
+
+	for _, c := range cases {
+		response, err := authManager.Login(&authApi.LoginSpec{Token: "existing-token",
+			NoticeAcknowledgment: c.acknowledgment})
+		if c.expectedErr != k8serrors.IsBadRequest(err) {
+			t.Errorf("Test Case: %s. Unexpected error: %v", c.info, err)
+		}
+		if !c.expectedErr && (response == nil || response.JWEToken != "generated-token") {
+			t.Errorf("Test Case: %s. Expected login to succeed, but got %#v.", c.info, response)
+		}
+	}
+}
+
 func TestAuthManager_AuthenticationModes(t *testing.T) {
 	cManager := &fakeClientManager{}
 	tManager := &fakeTokenManager{}
@@ -180,6 +260,7 @@ func TestAuthManager_AuthenticationModes(t *testing.T) {
 	}{
 		{authApi.AuthenticationModes{}, []authApi.AuthenticationMode{}},
 		{authApi.AuthenticationModes{authApi.Token: true}, []authApi.AuthenticationMode{authApi.Token}},
+		{authApi.AuthenticationModes{authApi.Header: true}, []authApi.AuthenticationMode{}},
 	}
 
 	for _, c := range cases {
@@ -192,6 +273,50 @@ func TestAuthManager_AuthenticationModes(t *testing.T) {
 	}
 }
 
+func TestAuthManager_UsernamePasswordModes(t *testing.T) {
+	cManager := &fakeClientManager{}
+	tManager := &fakeTokenManager{}
+	bothModes := authApi.AuthenticationModes{authApi.Basic: true, authApi.LDAP: true}
+	cases := []struct {
+		info     string
+		modes    authApi.AuthenticationModes
+		mode     authApi.AuthenticationMode
+		expected reflect.Type
+	}{
+		{"LDAP is default when both modes are enabled", bothModes, "",
+			reflect.TypeOf(&ldapAuthenticator{})},
+		{"Basic selected when both modes are enabled", bothModes, authApi.Basic,
+			reflect.TypeOf(&basicAuthenticator{})},
+		{"LDAP selected when both modes are enabled", bothModes, authApi.LDAP,
+			reflect.TypeOf(&ldapAuthenticator{})},
+		{"Basic selected when only LDAP is enabled", authApi.AuthenticationModes{authApi.LDAP: true}, authApi.Basic,
+			nil},
+		{"LDAP selected when only Basic is enabled", authApi.AuthenticationModes{authApi.Basic: true}, authApi.LDAP,
+			nil},
+	}
+
+	for _, c := range cases {
+		manager := NewAuthManager(cManager, tManager, c.modes, true).(*authManager)
+		spec := &authApi.LoginSpec{Username: "user", Password: "pass", Mode: c.mode}
+		authenticator, err := manager.getAuthenticator(spec)
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("Test Case: %s. Expected error, but got %T.", c.info, authenticator)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Test Case: %s. Unexpected error: %v.", c.info, err)
+			continue
+		}
+
+		if got := reflect.TypeOf(authenticator); got != c.expected {
+			t.Errorf("Test Case: %s. Expected %v, but got %v.", c.info, c.expected, got)
+		}
+	}
+}
+
 func TestAuthManager_AuthenticationSkippable(t *testing.T) {
 	cManager := &fakeClientManager{}
 	tManager := &fakeTokenManager{}
@@ -205,3 +330,231 @@ func TestAuthManager_AuthenticationSkippable(t *testing.T) {
 		}
 	}
 }
+
+func TestAuthManager_EmbedToken(t *testing.T) {
+	scope := authApi.EmbedScope{Kind: "pod", Namespace: "default"}
+	cases := []struct {
+		info        string
+		jweToken    string
+		spec        *authApi.EmbedTokenSpec
+		tManager    authApi.TokenManager
+		expected    *authApi.EmbedTokenResponse
+		expectedTTL time.Duration
+		expectedErr error
+	}{
+		{
+			"Should return error if token manager does not support embed tokens",
+			"token", &authApi.EmbedTokenSpec{Scope: scope}, &fakeTokenManager{}, nil, 0,
+			errors.NewInvalid("Can not generate embed token. Token manager does not support embed tokens."),
+		}, {
+			"Should return error if no resource kind provided",
+			"token", &authApi.EmbedTokenSpec{}, &fakeEmbedTokenManager{}, nil, 0,
+			errors.NewInvalid("Can not generate embed token. No resource kind provided."),
+		}, {
+			"Should return error if no token provided",
+			"", &authApi.EmbedTokenSpec{Scope: scope}, &fakeEmbedTokenManager{}, nil, 0,
+			errors.NewUnauthorized(errors.MsgLoginUnauthorizedError),
+		}, {
+			"Should generate embed token with default TTL",
+			"token", &authApi.EmbedTokenSpec{Scope: scope}, &fakeEmbedTokenManager{},
+			&authApi.EmbedTokenResponse{EmbedToken: "embed-token"}, authApi.DefaultEmbedTokenTTL * time.Second, nil,
+		}, {
+			"Should generate embed token with provided TTL",
+			"token", &authApi.EmbedTokenSpec{Scope: scope, TTL: 60}, &fakeEmbedTokenManager{},
+			&authApi.EmbedTokenResponse{EmbedToken: "embed-token"}, time.Minute, nil,
+		},
+	}
+
+	for _, c := range cases {
+		authManager := NewAuthManager(&fakeClientManager{}, c.tManager, authApi.AuthenticationModes{}, true)
+		response, err := authManager.EmbedToken(c.jweToken, c.spec)
+
+		if !areErrorsEqual(err, c.expectedErr) {
+			t.Errorf("Test Case: %s. Expected error to be: %v, but got %v.", c.info, c.expectedErr, err)
+		}
+
+		if !reflect.DeepEqual(response, c.expected) {
+			t.Errorf("Test Case: %s. Expected response to be: %v, but got %v.", c.info, c.expected, response)
+		}
+
+		if embedManager, ok := c.tManager.(*fakeEmbedTokenManager); ok && c.expected != nil {
+			if embedManager.TTL != c.expectedTTL || embedManager.Scope != c.spec.Scope {
+				t.Errorf("Test Case: %s. Expected token with TTL %v and scope %v, but got %v and %v.", c.info,
+					c.expectedTTL, c.spec.Scope, embedManager.TTL, embedManager.Scope)
+			}
+		}
+	}
+}
+
+func TestAuthManager_Logout(t *testing.T) {
+	revokeErr := errors.NewInvalid("Can not revoke token. No token provided.")
+	for _, expected := range []error{nil, revokeErr} {
+		authManager := NewAuthManager(&fakeClientManager{}, &fakeTokenManager{Error: expected},
+			authApi.AuthenticationModes{}, true)
+		if err := authManager.Logout("token"); !areErrorsEqual(err, expected) {
+			t.Errorf("Expected error to be: %v, but got %v.", expected, err)
+		}
+	}
+}
+
+type fakeElevationTokenManager struct {
+	fakeTokenManager
+	Elevated api.AuthInfo
+	Until    time.Time
+}
+
+func (self *fakeElevationTokenManager) Elevate(jweToken string, elevated api.AuthInfo, until time.Time) (string,
+	error) {
+	self.Elevated = elevated
+	self.Until = until
+	return "elevated-" + jweToken, nil
+}
+
+func (self *fakeElevationTokenManager) DecryptElevated(string) (*authApi.ElevatedAuthInfo, error) {
+	return nil, nil
+}
+
+func TestAuthManager_Elevate(t *testing.T) {
+	args.GetHolderBuilder().SetMaxElevationDuration(600)
+	defer args.GetHolderBuilder().SetMaxElevationDuration(0)
+
+	cases := []struct {
+		info             string
+		jweToken         string
+		spec             *authApi.ElevationSpec
+		tManager         authApi.TokenManager
+		expected         *authApi.AuthResponse
+		expectedDuration time.Duration
+		expectedErr      error
+	}{
+		{
+			"Should return error if token manager does not support elevation",
+			"token", &authApi.ElevationSpec{LoginSpec: authApi.LoginSpec{Token: "admin-token"}},
+			&fakeTokenManager{}, nil, 0,
+			errors.NewInvalid("Can not elevate token. Elevation is disabled."),
+		}, {
+			"Should return error if no token provided",
+			"", &authApi.ElevationSpec{LoginSpec: authApi.LoginSpec{Token: "admin-token"}},
+			&fakeElevationTokenManager{}, nil, 0,
+			errors.NewUnauthorized(errors.MsgLoginUnauthorizedError),
+		}, {
+			"Should elevate token for maximum duration",
+			"token", &authApi.ElevationSpec{LoginSpec: authApi.LoginSpec{Token: "admin-token"}, Duration: 3600},
+			&fakeElevationTokenManager{},
+			&authApi.AuthResponse{JWEToken: "elevated-token", Errors: make([]*errors.APIError, 0)}, 10 * time.Minute, nil,
+		}, {
+			"Should elevate token for provided duration",
+			"token", &authApi.ElevationSpec{LoginSpec: authApi.LoginSpec{Token: "admin-token"}, Duration: 60},
+			&fakeElevationTokenManager{},
+			&authApi.AuthResponse{JWEToken: "elevated-token", Errors: make([]*errors.APIError, 0)}, time.Minute, nil,
+		},
+	}
+
+	for _, c := range cases {
+		authManager := NewAuthManager(&fakeClientManager{}, c.tManager, authApi.AuthenticationModes{authApi.Token: true},
+			true)
+		start := time.Now()
+		response, err := authManager.Elevate(c.jweToken, c.spec)
+
+		if !areErrorsEqual(err, c.expectedErr) {
+			t.Errorf("Test Case: %s. Expected error to be: %v, but got %v.", c.info, c.expectedErr, err)
+		}
+
+		if !reflect.DeepEqual(response, c.expected) {
+			t.Errorf("Test Case: %s. Expected response to be: %v, but got %v.", c.info, c.expected, response)
+		}
+
+		if elevationManager, ok := c.tManager.(*fakeElevationTokenManager); ok && c.expected != nil {
+			until := elevationManager.Until
+			if elevationManager.Elevated.Token != "admin-token" || until.Before(start.Add(c.expectedDuration)) ||
+				until.After(time.Now().Add(c.expectedDuration)) {
+				t.Errorf("Test Case: %s. Expected elevation with admin token for %v, but got %v until %v.", c.info,
+					c.expectedDuration, elevationManager.Elevated, elevationManager.Until)
+			}
+		}
+	}
+}
+
+type fakeSessionTokenManager struct {
+	fakeTokenManager
+	sessions []authApi.Session
+	expired  []string
+}
+
+func (self *fakeSessionTokenManager) Sessions() ([]authApi.Session, error) {
+	return self.sessions, nil
+}
+
+func (self *fakeSessionTokenManager) ExpireSession(id string) error {
+	self.expired = append(self.expired, id)
+	return nil
+}
+
+func (self *fakeSessionTokenManager) SessionOf(jweToken string) (*authApi.Session, error) {
+	for _, session := range self.sessions {
+		if session.ID == jweToken {
+			return &session, nil
+		}
+	}
+
+	return nil, errors.NewNotFound("Session not found.")
+}
+
+func (self *fakeSessionTokenManager) SetSessionClient(id string, client authApi.SessionClient) error {
+	return nil
+}
+
+func TestAuthManager_SessionID(t *testing.T) {
+	tokenManager := &fakeSessionTokenManager{sessions: []authApi.Session{{ID: "first", Subject: "user"}}}
+	authManager := NewAuthManager(&fakeClientManager{}, tokenManager, authApi.AuthenticationModes{}, true)
+
+	if id, err := authManager.SessionID("first"); err != nil || id != "first" {
+		t.Errorf("Expected ID of the first session, but got %q, %v.", id, err)
+	}
+
+	if _, err := authManager.SessionID("expired"); err == nil {
+		t.Error("Expected error for token of a session that does not exist.")
+	}
+
+	authManager = NewAuthManager(&fakeClientManager{}, &fakeTokenManager{}, authApi.AuthenticationModes{}, true)
+	if id, err := authManager.SessionID("token"); err != nil || len(id) > 0 {
+		t.Errorf("Expected empty ID when sessions are not tracked, but got %q, %v.", id, err)
+	}
+}
+
+func TestAuthManager_OwnSessions(t *testing.T) {
+	// Fake token manager uses session IDs as tokens.
+	tokenManager := &fakeSessionTokenManager{sessions: []authApi.Session{
+		{ID: "first", Subject: "user"},
+		{ID: "second", Subject: "user"},
+		{ID: "other", Subject: "other"},
+	}}
+	authManager := NewAuthManager(&fakeClientManager{}, tokenManager, authApi.AuthenticationModes{}, true)
+
+	sessions, err := authManager.OwnSessions("first")
+	if err != nil {
+		t.Fatalf("Expected no error when listing sessions, but got %v.", err)
+	}
+
+	if len(sessions) != 2 || !sessions[0].Current || sessions[1].Current {
+		t.Errorf("Expected both sessions of user with the first one marked as current, but got %+v.", sessions)
+	}
+
+	expectedErr := errors.NewNotFound("Session not found.")
+	if err = authManager.ExpireOwnSession("first", "other"); !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+
+	if err = authManager.ExpireOwnSession("first", "second"); err != nil {
+		t.Fatalf("Expected no error when expiring own session, but got %v.", err)
+	}
+
+	if !reflect.DeepEqual(tokenManager.expired, []string{"second"}) {
+		t.Errorf("Expected only the second session to be expired, but got %v.", tokenManager.expired)
+	}
+
+	expectedErr = errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	if _, err = authManager.OwnSessions(""); !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error to be: %v, but got %v.", expectedErr, err)
+	}
+}
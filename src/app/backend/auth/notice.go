@@ -0,0 +1,41 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+// Length of the hex encoded hash of the notice text used as its ID.
+const loginNoticeIDLength = 16
+
+// GetLoginNotice returns login notice passed to Dashboard binary.
+func GetLoginNotice() authApi.LoginNotice {
+	return NewLoginNotice(args.Holder.GetLoginNotice(), args.Holder.GetLoginNoticeAcknowledgment())
+}
+
+// NewLoginNotice creates login notice with given text identified by its hash.
+func NewLoginNotice(text string, acknowledgmentRequired bool) authApi.LoginNotice {
+	sum := sha256.Sum256([]byte(text))
+	return authApi.LoginNotice{
+		ID:                     hex.EncodeToString(sum[:])[:loginNoticeIDLength],
+		Text:                   text,
+		AcknowledgmentRequired: acknowledgmentRequired,
+	}
+}
@@ -15,25 +15,151 @@
 package auth
 
 import (
-	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
 )
 
+// TokenReviewConfig contains settings of token validation done before a token is accepted by the token
+// authentication mode.
+type TokenReviewConfig struct {
+	// Enabled makes tokens to be reviewed with the TokenReview API of the cluster.
+	Enabled bool
+	// Audiences of which the token has to be valid for at least one. Any audience is accepted if empty.
+	Audiences []string
+	// Issuers of which one has to issue the token. Any issuer is accepted if empty.
+	Issuers []string
+}
+
+// GetTokenReviewConfig returns token review settings passed to Dashboard binary.
+func GetTokenReviewConfig() TokenReviewConfig {
+	return TokenReviewConfig{
+		Enabled:   args.Holder.GetEnableTokenReview(),
+		Audiences: args.Holder.GetTokenReviewAudiences(),
+		Issuers:   args.Holder.GetTokenReviewIssuers(),
+	}
+}
+
 // Implements Authenticator interface
 type tokenAuthenticator struct {
 	token string
+	// Used to review the token if it is enabled. Reviews are made with privileges of Dashboard.
+	client kubernetes.Interface
+	review TokenReviewConfig
 }
 
-// GetAuthInfo implements Authenticator interface. See Authenticator for more information.
+// GetAuthInfo implements Authenticator interface. See Authenticator for more information. If token review is
+// enabled, the token is rejected with a clear reason when it is expired or not valid for the required audiences,
+// otherwise it would only fail on the first request made with it.
 func (self tokenAuthenticator) GetAuthInfo() (api.AuthInfo, error) {
+	if self.review.Enabled {
+		if err := self.checkClaims(time.Now()); err != nil {
+			return api.AuthInfo{}, err
+		}
+
+		if err := self.reviewToken(); err != nil {
+			return api.AuthInfo{}, err
+		}
+	}
+
 	return api.AuthInfo{
 		Token: self.token,
 	}, nil
 }
 
+// Checks claims of JWT tokens, i.e. service account tokens, to give a clear reason of their rejection. Signature
+// is not verified here, tokens are always reviewed by the API server afterwards.
+func (self tokenAuthenticator) checkClaims(now time.Time) error {
+	claims := getTokenClaims(self.token)
+	var expiration int64
+	if err := json.Unmarshal(claims["exp"], &expiration); err == nil && now.Unix() >= expiration {
+		return errors.NewUnauthorized(fmt.Sprintf("token expired at %s",
+			time.Unix(expiration, 0).UTC().Format(time.RFC3339)))
+	}
+
+	if audiences := getStringsClaim(claims, "aud"); claims != nil && len(self.review.Audiences) > 0 &&
+		!intersects(audiences, self.review.Audiences) {
+		return errors.NewUnauthorized(fmt.Sprintf("token audiences [%s] do not include any of [%s]",
+			strings.Join(audiences, ", "), strings.Join(self.review.Audiences, ", ")))
+	}
+
+	if len(self.review.Issuers) == 0 {
+		return nil
+	}
+
+	var issuer string
+	if err := json.Unmarshal(claims["iss"], &issuer); err != nil || !intersects([]string{issuer}, self.review.Issuers) {
+		return errors.NewUnauthorized(fmt.Sprintf("token issuer %q is not one of [%s]", issuer,
+			strings.Join(self.review.Issuers, ", ")))
+	}
+
+	return nil
+}
+
+// Reviews the token with the TokenReview API, so tokens that can not be checked locally are validated as well.
+func (self tokenAuthenticator) reviewToken() error {
+	review, err := self.client.AuthenticationV1().TokenReviews().Create(context.TODO(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: self.token, Audiences: self.review.Audiences},
+	}, metaV1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	if !review.Status.Authenticated {
+		reason := review.Status.Error
+		if len(reason) == 0 {
+			reason = "token is not valid"
+		}
+
+		return errors.NewUnauthorized(fmt.Sprintf("token rejected by token review: %s", reason))
+	}
+
+	if len(self.review.Audiences) > 0 && !intersects(review.Status.Audiences, self.review.Audiences) {
+		return errors.NewUnauthorized(fmt.Sprintf("token is not valid for any of audiences [%s]",
+			strings.Join(self.review.Audiences, ", ")))
+	}
+
+	return nil
+}
+
+// Returns true if any value is in both slices.
+func intersects(values, others []string) bool {
+	for _, value := range values {
+		for _, other := range others {
+			if value == other {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // NewTokenAuthenticator returns Authenticator based on LoginSpec.
 func NewTokenAuthenticator(spec *authApi.LoginSpec) authApi.Authenticator {
 	return &tokenAuthenticator{
 		token: spec.Token,
 	}
 }
+
+// NewTokenReviewAuthenticator returns Authenticator based on LoginSpec, that validates the token with given client
+// before it is accepted.
+func NewTokenReviewAuthenticator(spec *authApi.LoginSpec, client kubernetes.Interface,
+	config TokenReviewConfig) authApi.Authenticator {
+	return &tokenAuthenticator{
+		token:  spec.Token,
+		client: client,
+		review: config,
+	}
+}
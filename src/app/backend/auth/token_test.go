@@ -0,0 +1,96 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+// Returns client answering token reviews with given status. Requested audiences are recorded.
+func getTokenReviewClient(status authenticationv1.TokenReviewStatus, audiences *[]string) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+			*audiences = review.Spec.Audiences
+			return true, &authenticationv1.TokenReview{Spec: review.Spec, Status: status}, nil
+		})
+	return client
+}
+
+func TestTokenAuthenticator_GetAuthInfo(t *testing.T) {
+	now := time.Now().Unix()
+	valid := getTestJWT(fmt.Sprintf(`{"iss":"https://kubernetes.default.svc","aud":["dashboard"],"exp":%d}`,
+		now+3600))
+	authenticated := authenticationv1.TokenReviewStatus{Authenticated: true, Audiences: []string{"dashboard"}}
+
+	cases := []struct {
+		info      string
+		token     string
+		config    TokenReviewConfig
+		status    authenticationv1.TokenReviewStatus
+		expected  string
+		audiences []string
+	}{
+		{"review disabled", "static-token", TokenReviewConfig{}, authenticationv1.TokenReviewStatus{}, "", nil},
+		{"valid token", valid,
+			TokenReviewConfig{Enabled: true, Audiences: []string{"dashboard"},
+				Issuers: []string{"https://kubernetes.default.svc"}},
+			authenticated, "", []string{"dashboard"}},
+		{"static token", "static-token", TokenReviewConfig{Enabled: true},
+			authenticationv1.TokenReviewStatus{Authenticated: true}, "", nil},
+		{"expired token", getTestJWT(fmt.Sprintf(`{"exp":%d}`, now-60)), TokenReviewConfig{Enabled: true},
+			authenticated, "token expired at", nil},
+		{"wrong audience", valid, TokenReviewConfig{Enabled: true, Audiences: []string{"other"}},
+			authenticated, "token audiences [dashboard] do not include any of [other]", nil},
+		{"wrong issuer", valid, TokenReviewConfig{Enabled: true, Issuers: []string{"https://issuer.example.com"}},
+			authenticated, `token issuer "https://kubernetes.default.svc" is not one of`, nil},
+		{"rejected by review", "static-token", TokenReviewConfig{Enabled: true},
+			authenticationv1.TokenReviewStatus{Error: "invalid bearer token"},
+			"token rejected by token review: invalid bearer token", nil},
+		{"reviewed for other audience", "static-token", TokenReviewConfig{Enabled: true, Audiences: []string{"dashboard"}},
+			authenticationv1.TokenReviewStatus{Authenticated: true, Audiences: []string{"other"}},
+			"token is not valid for any of audiences [dashboard]", []string{"dashboard"}},
+	}
+
+	for _, c := range cases {
+		var audiences []string
+		authenticator := NewTokenReviewAuthenticator(&authApi.LoginSpec{Token: c.token},
+			getTokenReviewClient(c.status, &audiences), c.config)
+		authInfo, err := authenticator.GetAuthInfo()
+		if len(c.expected) == 0 {
+			if err != nil || authInfo.Token != c.token {
+				t.Errorf("Test Case: %s. Expected token to be accepted, but got %v.", c.info, err)
+			}
+		} else if !k8serrors.IsUnauthorized(err) || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Test Case: %s. Expected unauthorized error %q, but got %v.", c.info, c.expected, err)
+		}
+
+		if strings.Join(audiences, ",") != strings.Join(c.audiences, ",") {
+			t.Errorf("Test Case: %s. Expected review for audiences %v, but got %v.", c.info, c.audiences, audiences)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Maximum nesting of CBOR arrays and maps. WebAuthn structures are at most a few levels deep.
+const maxCBORDepth = 8
+
+var errCBORTruncated = errors.New("CBOR data is truncated")
+
+// Decodes single CBOR data item and returns it together with the data following it. Only the subset of CBOR used by
+// WebAuthn is supported: integers, byte and text strings, arrays, maps and simple values. Integers are decoded as
+// int64, byte strings as []byte, text strings as string, arrays as []interface{} and maps as
+// map[interface{}]interface{}. See: https://tools.ietf.org/html/rfc7049
+func decodeCBOR(data []byte) (interface{}, []byte, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > maxCBORDepth {
+		return nil, nil, errors.New("CBOR data is nested too deep")
+	}
+
+	if len(data) == 0 {
+		return nil, nil, errCBORTruncated
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, data[1:], nil
+		case 21:
+			return true, data[1:], nil
+		case 22, 23:
+			return nil, data[1:], nil
+		}
+		return nil, nil, fmt.Errorf("unsupported CBOR simple value %d", info)
+	}
+
+	argument, data, err := readCBORArgument(info, data[1:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0, 1:
+		if argument > math.MaxInt64 {
+			return nil, nil, errors.New("CBOR integer overflows int64")
+		}
+		if major == 1 {
+			return -1 - int64(argument), data, nil
+		}
+		return int64(argument), data, nil
+	case 2, 3:
+		if argument > uint64(len(data)) {
+			return nil, nil, errCBORTruncated
+		}
+		if major == 3 {
+			return string(data[:argument]), data[argument:], nil
+		}
+		return append([]byte{}, data[:argument]...), data[argument:], nil
+	case 4:
+		// Every item takes at least one byte, which limits allocation for corrupted lengths.
+		if argument > uint64(len(data)) {
+			return nil, nil, errCBORTruncated
+		}
+		items := make([]interface{}, argument)
+		for i := range items {
+			if items[i], data, err = decodeCBORItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+		}
+		return items, data, nil
+	case 5:
+		if argument > uint64(len(data))/2 {
+			return nil, nil, errCBORTruncated
+		}
+		items := make(map[interface{}]interface{}, argument)
+		for i := uint64(0); i < argument; i++ {
+			var key, value interface{}
+			if key, data, err = decodeCBORItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, errors.New("CBOR map key has to be an integer or a text string")
+			}
+			if value, data, err = decodeCBORItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			items[key] = value
+		}
+		return items, data, nil
+	case 6:
+		// Tags only annotate the item following them.
+		return decodeCBORItem(data, depth+1)
+	}
+
+	return nil, nil, fmt.Errorf("unsupported CBOR major type %d", major)
+}
+
+// Reads argument of data item encoded in additional information and the bytes following the initial byte.
+func readCBORArgument(info byte, data []byte) (uint64, []byte, error) {
+	size := 0
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, nil, errors.New("indefinite length CBOR items are not supported")
+	}
+
+	if len(data) < size {
+		return 0, nil, errCBORTruncated
+	}
+
+	buf := make([]byte, 8)
+	copy(buf[8-size:], data[:size])
+	return binary.BigEndian.Uint64(buf), data[size:], nil
+}
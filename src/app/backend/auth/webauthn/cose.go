@@ -0,0 +1,131 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithms of credential public keys accepted by Dashboard.
+// See: https://www.iana.org/assignments/cose/cose.xhtml#algorithms
+const (
+	algES256 int64 = -7
+	algEdDSA int64 = -8
+	algRS256 int64 = -257
+)
+
+// Algorithms offered to authenticators during registration, most preferred first.
+var supportedAlgorithms = []int64{algES256, algEdDSA, algRS256}
+
+// COSE key parameters. See: https://tools.ietf.org/html/rfc8152#section-13
+const (
+	coseKeyType  int64 = 1
+	coseKeyAlg   int64 = 3
+	coseKeyCurve int64 = -1
+	coseKeyX     int64 = -2
+	coseKeyY     int64 = -3
+	// RSA keys use the same labels for modulus and exponent as other keys for curve and x coordinate.
+	coseKeyN = coseKeyCurve
+	coseKeyE = coseKeyX
+
+	coseKeyTypeOKP int64 = 1
+	coseKeyTypeEC2 int64 = 2
+	coseKeyTypeRSA int64 = 3
+
+	coseCurveP256    int64 = 1
+	coseCurveEd25519 int64 = 6
+)
+
+// Parses COSE encoded credential public key. Only keys of supported algorithms are accepted.
+func parsePublicKey(coseKey []byte) (crypto.PublicKey, error) {
+	decoded, rest, err := decodeCBOR(coseKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := decoded.(map[interface{}]interface{})
+	if !ok || len(rest) > 0 {
+		return nil, errors.New("credential public key is not a COSE key")
+	}
+
+	alg, _ := key[coseKeyAlg].(int64)
+	keyType, _ := key[coseKeyType].(int64)
+	switch {
+	case alg == algES256 && keyType == coseKeyTypeEC2:
+		x, xOk := key[coseKeyX].([]byte)
+		y, yOk := key[coseKeyY].([]byte)
+		if curve, _ := key[coseKeyCurve].(int64); curve != coseCurveP256 || !xOk || !yOk {
+			return nil, errors.New("ES256 credential public key has to be a P-256 point")
+		}
+
+		publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, errors.New("ES256 credential public key is not on the curve")
+		}
+		return publicKey, nil
+	case alg == algEdDSA && keyType == coseKeyTypeOKP:
+		x, ok := key[coseKeyX].([]byte)
+		if curve, _ := key[coseKeyCurve].(int64); curve != coseCurveEd25519 || !ok || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("EdDSA credential public key has to be an Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	case alg == algRS256 && keyType == coseKeyTypeRSA:
+		n, nOk := key[coseKeyN].([]byte)
+		e, eOk := key[coseKeyE].([]byte)
+		if !nOk || !eOk || len(e) > 4 {
+			return nil, errors.New("RS256 credential public key is invalid")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported credential public key algorithm %d", alg)
+}
+
+// Verifies signature of given data made by the private key of COSE encoded credential public key.
+func verifySignature(coseKey, data, signature []byte) error {
+	publicKey, err := parsePublicKey(coseKey)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(data)
+	valid := false
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		// ECDSA signatures are ASN.1 DER encoded.
+		parsed := struct{ R, S *big.Int }{}
+		if rest, err := asn1.Unmarshal(signature, &parsed); err == nil && len(rest) == 0 {
+			valid = ecdsa.Verify(key, hash[:], parsed.R, parsed.S)
+		}
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, data, signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) == nil
+	}
+
+	if !valid {
+		return errors.New("invalid signature of the authenticator")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webauthn
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Number of attempts to update the secret when it is changed concurrently by another replica.
+const credentialRetries = 3
+
+// Credential is a registered credential together with its public key.
+type Credential struct {
+	ID        []byte    `json:"id"`
+	Name      string    `json:"name"`
+	PublicKey []byte    `json:"publicKey"`
+	SignCount uint32    `json:"signCount"`
+	Created   time.Time `json:"created"`
+	LastUsed  time.Time `json:"lastUsed"`
+}
+
+// Info returns the credential without its public key.
+func (self Credential) Info() authApi.WebAuthnCredential {
+	return authApi.WebAuthnCredential{
+		ID:       encodeBase64URL(self.ID),
+		Name:     self.Name,
+		Created:  self.Created,
+		LastUsed: self.LastUsed,
+	}
+}
+
+// Returns credentials registered by given subject.
+func getCredentials(client kubernetes.Interface, subject string) ([]Credential, error) {
+	secret, err := client.CoreV1().Secrets(args.Holder.GetNamespace()).Get(context.TODO(),
+		authApi.WebAuthnCredentialsHolderName, metaV1.GetOptions{})
+	if errors.IsNotFoundError(err) {
+		return []Credential{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make([]Credential, 0)
+	if data, ok := secret.Data[getCredentialsKey(subject)]; ok {
+		if err := json.Unmarshal(data, &credentials); err != nil {
+			return nil, err
+		}
+	}
+
+	return credentials, nil
+}
+
+// Applies given change to credentials of given subject, creating the secret if it does not exist yet. Change is
+// retried if the secret has been modified or created concurrently. Errors returned by the change are not retried.
+func updateCredentials(client kubernetes.Interface, subject string,
+	change func([]Credential) ([]Credential, error)) error {
+	secrets := client.CoreV1().Secrets(args.Holder.GetNamespace())
+	key := getCredentialsKey(subject)
+	var err error
+	for i := 0; i < credentialRetries; i++ {
+		secret, getErr := secrets.Get(context.TODO(), authApi.WebAuthnCredentialsHolderName, metaV1.GetOptions{})
+		exists := getErr == nil
+		if !exists && !errors.IsNotFoundError(getErr) {
+			return getErr
+		}
+
+		if !exists {
+			secret = &v1.Secret{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: args.Holder.GetNamespace(),
+					Name:      authApi.WebAuthnCredentialsHolderName,
+				},
+			}
+		}
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+
+		credentials := make([]Credential, 0)
+		if data, ok := secret.Data[key]; ok {
+			if err := json.Unmarshal(data, &credentials); err != nil {
+				return err
+			}
+		}
+
+		if credentials, err = change(credentials); err != nil {
+			return err
+		}
+
+		if len(credentials) == 0 {
+			delete(secret.Data, key)
+		} else if secret.Data[key], err = json.Marshal(credentials); err != nil {
+			return err
+		}
+
+		if exists {
+			_, err = secrets.Update(context.TODO(), secret, metaV1.UpdateOptions{})
+		} else {
+			_, err = secrets.Create(context.TODO(), secret, metaV1.CreateOptions{})
+		}
+
+		if !errors.IsConflict(err) && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// Returns the name of the secret entry credentials of given subject are saved under. Subject is hashed, so the
+// secret does not reveal who has registered second factor.
+func getCredentialsKey(subject string) string {
+	hash := sha256.Sum256([]byte(subject))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,512 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// Number of random bytes in a challenge.
+	challengeSize = 32
+	// How long a challenge can be answered.
+	challengeTTL = 2 * time.Minute
+	// Timeout of the ceremony in the browser in milliseconds.
+	ceremonyTimeout = int64(60000)
+	// Maximum number of credentials a user can register.
+	maxCredentials = 10
+	// Maximum length of a credential name.
+	maxNameLength = 64
+
+	credentialType = "public-key"
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+
+	// Authenticator data flags. See: https://www.w3.org/TR/webauthn/#sec-authenticator-data
+	flagUserPresent            = 0x01
+	flagAttestedCredentialData = 0x40
+
+	// Length of the fixed part of authenticator data: rpIdHash, flags and signCount.
+	authDataLength = 37
+)
+
+// Config of the relying party.
+type Config struct {
+	// RPID is the domain credentials are scoped to.
+	RPID string
+	// RPName is shown to users by their authenticators.
+	RPName string
+	// Origins of Dashboard accepted in client data.
+	Origins []string
+}
+
+// GetConfig returns relying party configuration based on Dashboard arguments. Dashboard is expected at the relying
+// party domain unless origins are set.
+func GetConfig() Config {
+	config := Config{
+		RPID:    args.Holder.GetWebAuthnRPID(),
+		RPName:  "Kubernetes Dashboard",
+		Origins: args.Holder.GetWebAuthnOrigins(),
+	}
+
+	if len(config.Origins) == 0 {
+		config.Origins = []string{"https://" + config.RPID}
+	}
+
+	return config
+}
+
+// Subject returns the name second factor of given auth info is registered under. Auth info without basic
+// credentials has no subject, as tokens and certificates are already bound to their owners.
+func Subject(authInfo api.AuthInfo) string {
+	if len(authInfo.Token) > 0 || len(authInfo.ClientCertificate) > 0 || len(authInfo.ClientCertificateData) > 0 {
+		return ""
+	}
+
+	if len(authInfo.Impersonate) > 0 {
+		return authInfo.Impersonate
+	}
+
+	return authInfo.Username
+}
+
+// Manager registers WebAuthn credentials and verifies assertions made with them. Challenges are not kept in memory,
+// they are encrypted and sent to the client instead, so any replica can verify the answer.
+type Manager struct {
+	config    Config
+	client    kubernetes.Interface
+	encrypter authApi.StateTokenManager
+}
+
+// State of a challenge, encrypted for its subject and ceremony.
+type challengeState struct {
+	Challenge []byte    `json:"challenge"`
+	Expires   time.Time `json:"expires"`
+}
+
+// Client data collected by the browser. See: https://www.w3.org/TR/webauthn/#dictdef-collectedclientdata
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// Challenge returns challenge the subject has to answer with one of registered credentials or nil if the subject
+// has not registered any.
+func (self *Manager) Challenge(subject string) (*authApi.WebAuthnChallenge, error) {
+	credentials, err := getCredentials(self.client, subject)
+	if err != nil || len(credentials) == 0 {
+		return nil, err
+	}
+
+	challenge, token, err := self.newChallenge(ceremonyGet, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authApi.WebAuthnChallenge{
+		ChallengeToken: token,
+		PublicKey: authApi.WebAuthnRequestOptions{
+			Challenge:        encodeBase64URL(challenge),
+			RPID:             self.config.RPID,
+			Timeout:          ceremonyTimeout,
+			AllowCredentials: descriptors(credentials),
+			UserVerification: "discouraged",
+		},
+	}, nil
+}
+
+// VerifyAssertion verifies that the assertion answers challenge of the subject and is signed by one of its
+// credentials. Sign count and last use of the credential are updated.
+func (self *Manager) VerifyAssertion(subject string, assertion *authApi.WebAuthnAssertion) error {
+	if assertion == nil {
+		return errors.NewUnauthorized("Second factor assertion is missing.")
+	}
+
+	id, err := decodeBase64URL(assertion.ID)
+	if err != nil {
+		return errors.NewUnauthorized("Invalid credential ID.")
+	}
+
+	rawClientData, err := self.verifyClientData(ceremonyGet, subject, assertion.ChallengeToken,
+		assertion.ClientDataJSON)
+	if err != nil {
+		return err
+	}
+
+	authData, err := decodeBase64URL(assertion.AuthenticatorData)
+	if err != nil {
+		return errors.NewUnauthorized("Invalid authenticator data.")
+	}
+
+	signCount, err := self.verifyAuthData(authData)
+	if err != nil {
+		return err
+	}
+
+	signature, err := decodeBase64URL(assertion.Signature)
+	if err != nil {
+		return errors.NewUnauthorized("Invalid signature.")
+	}
+
+	clientDataHash := sha256.Sum256(rawClientData)
+	signed := append(append([]byte{}, authData...), clientDataHash[:]...)
+
+	return updateCredentials(self.client, subject, func(credentials []Credential) ([]Credential, error) {
+		i := findCredential(credentials, id)
+		if i < 0 {
+			return nil, errors.NewUnauthorized("Unknown credential.")
+		}
+
+		if err := verifySignature(credentials[i].PublicKey, signed, signature); err != nil {
+			return nil, errors.NewUnauthorized("Invalid signature.")
+		}
+
+		// Authenticators that do not count signatures always report zero. Any other value has to increase, otherwise
+		// the credential may have been cloned.
+		if (signCount != 0 || credentials[i].SignCount != 0) && signCount <= credentials[i].SignCount {
+			return nil, errors.NewUnauthorized("Credential sign count has not increased.")
+		}
+
+		credentials[i].SignCount = signCount
+		credentials[i].LastUsed = time.Now()
+		return credentials, nil
+	})
+}
+
+// BeginRegistration returns challenge the subject has to answer with new credential.
+func (self *Manager) BeginRegistration(subject string) (*authApi.WebAuthnRegistration, error) {
+	credentials, err := getCredentials(self.client, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(credentials) >= maxCredentials {
+		return nil, errors.NewBadRequest(fmt.Sprintf("At most %d credentials can be registered.", maxCredentials))
+	}
+
+	challenge, token, err := self.newChallenge(ceremonyCreate, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := sha256.Sum256([]byte(subject))
+	params := make([]authApi.WebAuthnCredentialParameter, 0, len(supportedAlgorithms))
+	for _, alg := range supportedAlgorithms {
+		params = append(params, authApi.WebAuthnCredentialParameter{Type: credentialType, Alg: alg})
+	}
+
+	return &authApi.WebAuthnRegistration{
+		ChallengeToken: token,
+		PublicKey: authApi.WebAuthnCreationOptions{
+			Challenge: encodeBase64URL(challenge),
+			RP: authApi.WebAuthnRelyingParty{
+				ID:   self.config.RPID,
+				Name: self.config.RPName,
+			},
+			User: authApi.WebAuthnUser{
+				ID:          encodeBase64URL(userID[:]),
+				Name:        subject,
+				DisplayName: subject,
+			},
+			PubKeyCredParams:   params,
+			Timeout:            ceremonyTimeout,
+			ExcludeCredentials: descriptors(credentials),
+			Attestation:        "none",
+		},
+	}, nil
+}
+
+// FinishRegistration verifies that the attestation answers registration challenge of the subject and saves the new
+// credential. Attestation statement is not verified, Dashboard does not restrict which authenticators can be used.
+func (self *Manager) FinishRegistration(subject string, attestation *authApi.WebAuthnAttestation) (*Credential,
+	error) {
+	name := strings.TrimSpace(attestation.Name)
+	if len(name) == 0 || len(name) > maxNameLength {
+		return nil, errors.NewBadRequest(fmt.Sprintf("Credential name has to have 1 to %d characters.",
+			maxNameLength))
+	}
+
+	if _, err := self.verifyClientData(ceremonyCreate, subject, attestation.ChallengeToken,
+		attestation.ClientDataJSON); err != nil {
+		return nil, err
+	}
+
+	rawObject, err := decodeBase64URL(attestation.AttestationObject)
+	if err != nil {
+		return nil, errors.NewBadRequest("Invalid attestation object.")
+	}
+
+	authData, err := parseAttestationObject(rawObject)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	signCount, err := self.verifyAuthData(authData)
+	if err != nil {
+		return nil, err
+	}
+
+	id, publicKey, err := parseAttestedCredential(authData)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	if claimed, err := decodeBase64URL(attestation.ID); err != nil || !bytes.Equal(claimed, id) {
+		return nil, errors.NewBadRequest("Credential ID does not match attested credential data.")
+	}
+
+	if _, err := parsePublicKey(publicKey); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	credential := Credential{
+		ID:        id,
+		Name:      name,
+		PublicKey: publicKey,
+		SignCount: signCount,
+		Created:   time.Now(),
+	}
+
+	err = updateCredentials(self.client, subject, func(credentials []Credential) ([]Credential, error) {
+		if findCredential(credentials, id) >= 0 {
+			return nil, errors.NewBadRequest("Credential is already registered.")
+		}
+
+		if len(credentials) >= maxCredentials {
+			return nil, errors.NewBadRequest(fmt.Sprintf("At most %d credentials can be registered.",
+				maxCredentials))
+		}
+
+		return append(credentials, credential), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &credential, nil
+}
+
+// Credentials returns credentials registered by the subject.
+func (self *Manager) Credentials(subject string) ([]Credential, error) {
+	return getCredentials(self.client, subject)
+}
+
+// DeleteCredential removes credential with given base64url encoded ID registered by the subject.
+func (self *Manager) DeleteCredential(subject, credentialID string) error {
+	id, err := decodeBase64URL(credentialID)
+	if err != nil {
+		return errors.NewBadRequest("Invalid credential ID.")
+	}
+
+	return updateCredentials(self.client, subject, func(credentials []Credential) ([]Credential, error) {
+		i := findCredential(credentials, id)
+		if i < 0 {
+			return nil, errors.NewNotFound("Credential not found.")
+		}
+
+		return append(credentials[:i], credentials[i+1:]...), nil
+	})
+}
+
+// Generates random challenge and encrypts it for given ceremony of the subject.
+func (self *Manager) newChallenge(ceremony, subject string) ([]byte, string, error) {
+	challenge := make([]byte, challengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, "", err
+	}
+
+	state, err := json.Marshal(challengeState{Challenge: challenge, Expires: time.Now().Add(challengeTTL)})
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := self.encrypter.EncryptState(challengeOwner(ceremony, subject), state)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return challenge, token, nil
+}
+
+// Verifies that client data belongs to given ceremony, answers the challenge encrypted in the token and comes from
+// one of Dashboard origins. Returns decoded client data, as signatures are made over its hash.
+func (self *Manager) verifyClientData(ceremony, subject, token, encoded string) ([]byte, error) {
+	decrypted, err := self.encrypter.DecryptState(challengeOwner(ceremony, subject), token)
+	if err != nil {
+		return nil, errors.NewUnauthorized("Invalid challenge token.")
+	}
+
+	state := challengeState{}
+	if err := json.Unmarshal(decrypted, &state); err != nil || time.Now().After(state.Expires) {
+		return nil, errors.NewUnauthorized("Challenge has expired.")
+	}
+
+	raw, err := decodeBase64URL(encoded)
+	if err != nil {
+		return nil, errors.NewUnauthorized("Invalid client data.")
+	}
+
+	data := clientData{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, errors.NewUnauthorized("Invalid client data.")
+	}
+
+	if data.Type != ceremony {
+		return nil, errors.NewUnauthorized(fmt.Sprintf("Unexpected client data type %q.", data.Type))
+	}
+
+	if challenge, err := decodeBase64URL(data.Challenge); err != nil || !bytes.Equal(challenge, state.Challenge) {
+		return nil, errors.NewUnauthorized("Client data does not answer the challenge.")
+	}
+
+	if !self.isAllowedOrigin(data.Origin) {
+		return nil, errors.NewUnauthorized(fmt.Sprintf("Origin %q is not allowed.", data.Origin))
+	}
+
+	return raw, nil
+}
+
+// Verifies that authenticator data is scoped to the relying party and the user was present. Returns sign count.
+func (self *Manager) verifyAuthData(authData []byte) (uint32, error) {
+	if len(authData) < authDataLength {
+		return 0, errors.NewUnauthorized("Authenticator data is too short.")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(self.config.RPID))
+	if !bytes.Equal(authData[:32], rpIDHash[:]) {
+		return 0, errors.NewUnauthorized("Authenticator data belongs to another relying party.")
+	}
+
+	if authData[32]&flagUserPresent == 0 {
+		return 0, errors.NewUnauthorized("User presence has not been confirmed.")
+	}
+
+	return binary.BigEndian.Uint32(authData[33:authDataLength]), nil
+}
+
+func (self *Manager) isAllowedOrigin(origin string) bool {
+	for _, allowed := range self.config.Origins {
+		if origin == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns authenticator data of CBOR encoded attestation object.
+func parseAttestationObject(data []byte) ([]byte, error) {
+	object, _, err := decodeCBOR(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attestation object: %s", err)
+	}
+
+	fields, ok := object.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("attestation object is not a map")
+	}
+
+	authData, ok := fields["authData"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("attestation object has no authenticator data")
+	}
+
+	return authData, nil
+}
+
+// Returns ID and COSE encoded public key of the credential attested in authenticator data.
+// See: https://www.w3.org/TR/webauthn/#sec-attested-credential-data
+func parseAttestedCredential(authData []byte) ([]byte, []byte, error) {
+	if authData[32]&flagAttestedCredentialData == 0 {
+		return nil, nil, fmt.Errorf("authenticator data has no attested credential")
+	}
+
+	// AAGUID of the authenticator precedes credential ID length.
+	data := authData[authDataLength:]
+	if len(data) < 18 {
+		return nil, nil, fmt.Errorf("attested credential data is too short")
+	}
+
+	idLength := int(binary.BigEndian.Uint16(data[16:18]))
+	data = data[18:]
+	if len(data) < idLength {
+		return nil, nil, fmt.Errorf("attested credential data is too short")
+	}
+
+	id := data[:idLength]
+	// Public key is followed by extensions, so only the key itself is kept.
+	_, rest, err := decodeCBOR(data[idLength:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid credential public key: %s", err)
+	}
+
+	return id, data[idLength : len(data)-len(rest)], nil
+}
+
+func findCredential(credentials []Credential, id []byte) int {
+	for i, credential := range credentials {
+		if bytes.Equal(credential.ID, id) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func descriptors(credentials []Credential) []authApi.WebAuthnCredentialDescriptor {
+	result := make([]authApi.WebAuthnCredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		result = append(result, authApi.WebAuthnCredentialDescriptor{
+			Type: credentialType,
+			ID:   encodeBase64URL(credential.ID),
+		})
+	}
+
+	return result
+}
+
+func challengeOwner(ceremony, subject string) string {
+	return "webauthn:" + ceremony + ":" + subject
+}
+
+func encodeBase64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decodes base64url value. Padding is optional, browsers do not add it but some client libraries do.
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// NewManager creates WebAuthn manager. Challenges are encrypted by given encrypter.
+func NewManager(config Config, client kubernetes.Interface, encrypter authApi.StateTokenManager) *Manager {
+	return &Manager{config: config, client: client, encrypter: encrypter}
+}
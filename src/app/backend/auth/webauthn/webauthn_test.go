@@ -0,0 +1,398 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	testRPID   = "dashboard.example.com"
+	testOrigin = "https://dashboard.example.com"
+)
+
+// Fake encrypter prefixes state with its owner, so wrong owner can be detected without real keys.
+type fakeEncrypter struct{}
+
+func (fakeEncrypter) EncryptState(owner string, state []byte) (string, error) {
+	return owner + "|" + string(state), nil
+}
+
+func (fakeEncrypter) DecryptState(owner, encrypted string) ([]byte, error) {
+	if !strings.HasPrefix(encrypted, owner+"|") {
+		return nil, errors.NewInvalid("State belongs to another owner.")
+	}
+
+	return []byte(strings.TrimPrefix(encrypted, owner+"|")), nil
+}
+
+// Minimal CBOR encoder of the values authenticators send. Map entries are given as key, value pairs to keep order.
+type cborMap []interface{}
+
+func encodeCBOR(value interface{}) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 256:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			result := []byte{major<<5 | 25, 0, 0}
+			binary.BigEndian.PutUint16(result[1:], uint16(n))
+			return result
+		}
+	}
+
+	switch v := value.(type) {
+	case int64:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case cborMap:
+		result := head(5, uint64(len(v)/2))
+		for _, item := range v {
+			result = append(result, encodeCBOR(item)...)
+		}
+		return result
+	}
+
+	panic("unsupported CBOR value")
+}
+
+// Fake authenticator holding a single P-256 key.
+type fakeAuthenticator struct {
+	id        []byte
+	key       *ecdsa.PrivateKey
+	signCount uint32
+}
+
+func newFakeAuthenticator(t *testing.T) *fakeAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &fakeAuthenticator{id: []byte("credential-" + t.Name()), key: key}
+}
+
+func (self *fakeAuthenticator) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	flags := byte(flagUserPresent)
+	if attested {
+		flags |= flagAttestedCredentialData
+	}
+
+	data := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[33:], self.signCount)
+	if !attested {
+		return data
+	}
+
+	data = append(data, make([]byte, 16)...)
+	data = append(data, byte(len(self.id)>>8), byte(len(self.id)))
+	data = append(data, self.id...)
+	return append(data, encodeCBOR(cborMap{
+		coseKeyType, coseKeyTypeEC2,
+		coseKeyAlg, algES256,
+		coseKeyCurve, coseCurveP256,
+		coseKeyX, pad(self.key.X.Bytes()),
+		coseKeyY, pad(self.key.Y.Bytes()),
+	})...)
+}
+
+func (self *fakeAuthenticator) clientData(ceremony, challenge, origin string) []byte {
+	data, _ := json.Marshal(clientData{Type: ceremony, Challenge: challenge, Origin: origin})
+	return data
+}
+
+func (self *fakeAuthenticator) create(registration *authApi.WebAuthnRegistration) *authApi.WebAuthnAttestation {
+	object := encodeCBOR(cborMap{"fmt", "none", "attStmt", cborMap{}, "authData", self.authData(true)})
+	return &authApi.WebAuthnAttestation{
+		ChallengeToken: registration.ChallengeToken,
+		Name:           "YubiKey",
+		ID:             encodeBase64URL(self.id),
+		ClientDataJSON: encodeBase64URL(self.clientData(ceremonyCreate, registration.PublicKey.Challenge,
+			testOrigin)),
+		AttestationObject: encodeBase64URL(object),
+	}
+}
+
+func (self *fakeAuthenticator) get(challenge *authApi.WebAuthnChallenge, origin string) *authApi.WebAuthnAssertion {
+	self.signCount++
+	authData := self.authData(false)
+	clientData := self.clientData(ceremonyGet, challenge.PublicKey.Challenge, origin)
+	hash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), hash[:]...))
+	r, s, _ := ecdsa.Sign(rand.Reader, self.key, digest[:])
+	signature, _ := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+
+	return &authApi.WebAuthnAssertion{
+		ChallengeToken:    challenge.ChallengeToken,
+		ID:                encodeBase64URL(self.id),
+		ClientDataJSON:    encodeBase64URL(clientData),
+		AuthenticatorData: encodeBase64URL(authData),
+		Signature:         encodeBase64URL(signature),
+	}
+}
+
+func newTestManager() *Manager {
+	return NewManager(Config{RPID: testRPID, RPName: "Dashboard", Origins: []string{testOrigin}},
+		fake.NewSimpleClientset(), fakeEncrypter{})
+}
+
+func register(t *testing.T, manager *Manager, subject string, authenticator *fakeAuthenticator) {
+	registration, err := manager.BeginRegistration(subject)
+	if err != nil {
+		t.Fatalf("Expected no error when beginning registration, but got %v.", err)
+	}
+
+	if _, err = manager.FinishRegistration(subject, authenticator.create(registration)); err != nil {
+		t.Fatalf("Expected no error when finishing registration, but got %v.", err)
+	}
+}
+
+func TestSubject(t *testing.T) {
+	cases := []struct {
+		info     string
+		authInfo api.AuthInfo
+		expected string
+	}{
+		{"basic", api.AuthInfo{Username: "alice", Password: "secret"}, "alice"},
+		{"ldap", api.AuthInfo{Impersonate: "bob", ImpersonateGroups: []string{"admins"}}, "bob"},
+		{"token", api.AuthInfo{Token: "token"}, ""},
+		{"impersonating token", api.AuthInfo{Token: "token", Impersonate: "bob"}, ""},
+		{"certificate", api.AuthInfo{ClientCertificateData: []byte("cert")}, ""},
+	}
+
+	for _, c := range cases {
+		if subject := Subject(c.authInfo); subject != c.expected {
+			t.Errorf("%s: expected subject %q, but got %q.", c.info, c.expected, subject)
+		}
+	}
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	manager := newTestManager()
+	authenticator := newFakeAuthenticator(t)
+
+	challenge, err := manager.Challenge("alice")
+	if err != nil || challenge != nil {
+		t.Fatalf("Expected no challenge before registration, but got %v (error: %v).", challenge, err)
+	}
+
+	register(t, manager, "alice", authenticator)
+
+	challenge, err = manager.Challenge("alice")
+	if err != nil || challenge == nil {
+		t.Fatalf("Expected challenge after registration, but got %v (error: %v).", challenge, err)
+	}
+
+	expected := []authApi.WebAuthnCredentialDescriptor{{Type: credentialType, ID: encodeBase64URL(authenticator.id)}}
+	if !reflect.DeepEqual(challenge.PublicKey.AllowCredentials, expected) {
+		t.Errorf("Expected allowed credentials %v, but got %v.", expected, challenge.PublicKey.AllowCredentials)
+	}
+
+	if err = manager.VerifyAssertion("alice", authenticator.get(challenge, testOrigin)); err != nil {
+		t.Fatalf("Expected valid assertion, but got %v.", err)
+	}
+
+	credentials, _ := manager.Credentials("alice")
+	if len(credentials) != 1 || credentials[0].SignCount != 1 || credentials[0].LastUsed.IsZero() {
+		t.Errorf("Expected sign count and last use of the credential to be updated, but got %v.", credentials)
+	}
+
+	if challenge, _ = manager.Challenge("bob"); challenge != nil {
+		t.Errorf("Expected no challenge of another user, but got %v.", challenge)
+	}
+
+	if err = manager.DeleteCredential("alice", encodeBase64URL(authenticator.id)); err != nil {
+		t.Fatalf("Expected no error when deleting credential, but got %v.", err)
+	}
+
+	if challenge, _ = manager.Challenge("alice"); challenge != nil {
+		t.Errorf("Expected no challenge after the credential is deleted, but got %v.", challenge)
+	}
+}
+
+func TestVerifyAssertionRejected(t *testing.T) {
+	manager := newTestManager()
+	authenticator := newFakeAuthenticator(t)
+	register(t, manager, "alice", authenticator)
+	register(t, manager, "bob", newFakeAuthenticator(t))
+
+	cases := []struct {
+		info    string
+		subject string
+		modify  func(*authApi.WebAuthnAssertion)
+	}{
+		{"missing assertion", "alice", nil},
+		{"another origin", "alice", func(assertion *authApi.WebAuthnAssertion) {
+			*assertion = *authenticator.get(getChallenge(t, manager, "alice"), "https://evil.example.com")
+		}},
+		{"challenge of another user", "bob", func(assertion *authApi.WebAuthnAssertion) {}},
+		{"replayed sign count", "alice", func(assertion *authApi.WebAuthnAssertion) {
+			if err := manager.VerifyAssertion("alice", assertion); err != nil {
+				t.Fatalf("Expected valid assertion, but got %v.", err)
+			}
+
+			authenticator.signCount--
+			*assertion = *authenticator.get(getChallenge(t, manager, "alice"), testOrigin)
+		}},
+		{"invalid signature", "alice", func(assertion *authApi.WebAuthnAssertion) {
+			assertion.Signature = encodeBase64URL([]byte("signature"))
+		}},
+		{"unknown credential", "alice", func(assertion *authApi.WebAuthnAssertion) {
+			assertion.ID = encodeBase64URL([]byte("unknown"))
+		}},
+		{"registration ceremony", "alice", func(assertion *authApi.WebAuthnAssertion) {
+			challenge := getChallenge(t, manager, "alice")
+			assertion.ClientDataJSON = encodeBase64URL(authenticator.clientData(ceremonyCreate,
+				challenge.PublicKey.Challenge, testOrigin))
+		}},
+	}
+
+	for _, c := range cases {
+		var assertion *authApi.WebAuthnAssertion
+		if c.modify != nil {
+			assertion = authenticator.get(getChallenge(t, manager, "alice"), testOrigin)
+			c.modify(assertion)
+		}
+
+		if err := manager.VerifyAssertion(c.subject, assertion); !k8serrors.IsUnauthorized(err) {
+			t.Errorf("%s: expected unauthorized error, but got %v.", c.info, err)
+		}
+	}
+}
+
+func TestFinishRegistrationRejected(t *testing.T) {
+	manager := newTestManager()
+	authenticator := newFakeAuthenticator(t)
+	register(t, manager, "alice", authenticator)
+
+	cases := []struct {
+		info   string
+		modify func(*authApi.WebAuthnAttestation)
+	}{
+		{"already registered", func(attestation *authApi.WebAuthnAttestation) {}},
+		{"empty name", func(attestation *authApi.WebAuthnAttestation) { attestation.Name = " " }},
+		{"mismatched ID", func(attestation *authApi.WebAuthnAttestation) {
+			attestation.ID = encodeBase64URL([]byte("other"))
+		}},
+		{"invalid attestation object", func(attestation *authApi.WebAuthnAttestation) {
+			attestation.AttestationObject = encodeBase64URL([]byte{0xff})
+		}},
+	}
+
+	for _, c := range cases {
+		registration, err := manager.BeginRegistration("alice")
+		if err != nil {
+			t.Fatalf("%s: expected no error when beginning registration, but got %v.", c.info, err)
+		}
+
+		attestation := authenticator.create(registration)
+		c.modify(attestation)
+		if _, err = manager.FinishRegistration("alice", attestation); !k8serrors.IsBadRequest(err) {
+			t.Errorf("%s: expected bad request error, but got %v.", c.info, err)
+		}
+	}
+
+	registration, _ := manager.BeginRegistration("alice")
+	attestation := newFakeAuthenticator(t).create(registration)
+	if _, err := manager.FinishRegistration("bob", attestation); !k8serrors.IsUnauthorized(err) {
+		t.Errorf("Expected challenge of another user to be rejected, but got %v.", err)
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	cases := []struct {
+		info     string
+		data     []byte
+		expected interface{}
+	}{
+		{"negative integer", []byte{0x38, 0x18}, int64(-25)},
+		{"text", []byte{0x63, 'f', 'm', 't'}, "fmt"},
+		{"array", []byte{0x82, 0x01, 0xf5}, []interface{}{int64(1), true}},
+		{"map", encodeCBOR(cborMap{int64(1), []byte{2}}), map[interface{}]interface{}{int64(1): []byte{2}}},
+	}
+
+	for _, c := range cases {
+		value, rest, err := decodeCBOR(c.data)
+		if err != nil || len(rest) > 0 || !reflect.DeepEqual(value, c.expected) {
+			t.Errorf("%s: expected %v, but got %v (rest: %v, error: %v).", c.info, c.expected, value, rest, err)
+		}
+	}
+
+	for _, data := range [][]byte{{}, {0x62, 'a'}, {0x81}, {0xa1, 0x01}} {
+		if _, _, err := decodeCBOR(data); err == nil {
+			t.Errorf("Expected error when decoding truncated data %v.", data)
+		}
+	}
+}
+
+func TestVerifyEdDSASignature(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	coseKey := encodeCBOR(cborMap{
+		coseKeyType, coseKeyTypeOKP,
+		coseKeyAlg, algEdDSA,
+		coseKeyCurve, coseCurveEd25519,
+		coseKeyX, []byte(publicKey),
+	})
+
+	data := []byte("signed data")
+	if err := verifySignature(coseKey, data, ed25519.Sign(privateKey, data)); err != nil {
+		t.Errorf("Expected valid signature, but got %v.", err)
+	}
+
+	if err := verifySignature(coseKey, []byte("other data"), ed25519.Sign(privateKey, data)); err == nil {
+		t.Error("Expected signature of other data to be rejected.")
+	}
+}
+
+// Pads P-256 coordinate to its fixed length.
+func pad(coordinate []byte) []byte {
+	return append(make([]byte, 32-len(coordinate)), coordinate...)
+}
+
+func getChallenge(t *testing.T, manager *Manager, subject string) *authApi.WebAuthnChallenge {
+	challenge, err := manager.Challenge(subject)
+	if err != nil || challenge == nil {
+		t.Fatalf("Expected challenge of %s, but got %v (error: %v).", subject, challenge, err)
+	}
+
+	return challenge
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cani
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Handler manages endpoints checking which actions the user is allowed to perform.
+type Handler struct {
+	cManager clientapi.ClientManager
+}
+
+// Install creates new endpoints for batch access checks. Queries are answered with the credentials of the
+// request, so the frontend can hide actions the user cannot perform.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.POST("/can-i").
+			To(h.handleCanI).
+			Reads(QueryList{}).
+			Writes(ResultList{}))
+}
+
+// NewCanIHandler creates cani.Handler.
+func NewCanIHandler(cManager clientapi.ClientManager) *Handler {
+	return &Handler{cManager: cManager}
+}
+
+func (h *Handler) handleCanI(request *restful.Request, response *restful.Response) {
+	queries := new(QueryList)
+	if err := request.ReadEntity(queries); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	client, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := Review(client, *queries)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cani
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/authorization/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// MaxQueries is the maximum number of queries answered in a single request.
+const MaxQueries = 100
+
+// Query describes an action the user wants to perform. Verb and resource are required, resource has to be in
+// the plural lower-case form used by the API server, e.g. "deployments". Empty group means the core group.
+type Query struct {
+	Verb        string `json:"verb"`
+	Group       string `json:"group"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+}
+
+// QueryList is a batch of queries answered together.
+type QueryList struct {
+	Items []Query `json:"items"`
+}
+
+// Result tells whether the action described by the query is allowed.
+type Result struct {
+	Query
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// ResultList contains results in the same order as queries of the list.
+type ResultList struct {
+	Items []Result `json:"items"`
+}
+
+// Validate checks that the query list can be reviewed.
+func (self QueryList) Validate() error {
+	if len(self.Items) > MaxQueries {
+		return errors.NewBadRequest(fmt.Sprintf("at most %d queries can be reviewed at once", MaxQueries))
+	}
+
+	for i, query := range self.Items {
+		if len(query.Verb) == 0 || len(query.Resource) == 0 {
+			return errors.NewBadRequest(fmt.Sprintf("query %d: verb and resource are required", i))
+		}
+	}
+
+	return nil
+}
+
+// ToSelfSubjectAccessReview creates kubernetes API object based on the query.
+func (self Query) ToSelfSubjectAccessReview() *v1.SelfSubjectAccessReview {
+	return &v1.SelfSubjectAccessReview{
+		Spec: v1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &v1.ResourceAttributes{
+				Namespace:   self.Namespace,
+				Name:        self.Name,
+				Group:       self.Group,
+				Resource:    strings.ToLower(self.Resource),
+				Subresource: strings.ToLower(self.Subresource),
+				Verb:        strings.ToLower(self.Verb),
+			},
+		},
+	}
+}
+
+// Review answers all queries with self subject access reviews created by the given client, so results reflect
+// permissions of the client's user. Duplicate queries are reviewed only once.
+func Review(client kubernetes.Interface, queries QueryList) (*ResultList, error) {
+	if err := queries.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &ResultList{Items: make([]Result, 0, len(queries.Items))}
+	reviewed := make(map[Query]Result)
+	for _, query := range queries.Items {
+		if cached, ok := reviewed[query]; ok {
+			result.Items = append(result.Items, cached)
+			continue
+		}
+
+		review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(),
+			query.ToSelfSubjectAccessReview(), metaV1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		item := Result{Query: query, Allowed: review.Status.Allowed, Reason: review.Status.Reason}
+		reviewed[query] = item
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
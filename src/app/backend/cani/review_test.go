@@ -0,0 +1,132 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cani
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+func newFakeClient(allowed func(attributes *v1.ResourceAttributes) bool) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			review := action.(clienttesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			review.Status.Allowed = allowed(review.Spec.ResourceAttributes)
+			return true, review, nil
+		})
+	return client
+}
+
+func TestReview(t *testing.T) {
+	client := newFakeClient(func(attributes *v1.ResourceAttributes) bool {
+		return attributes.Verb == "get" || attributes.Namespace == "dev"
+	})
+	queries := QueryList{Items: []Query{
+		{Verb: "DELETE", Group: "apps", Resource: "Deployments", Namespace: "prod", Name: "web"},
+		{Verb: "get", Resource: "pods", Namespace: "prod"},
+		{Verb: "delete", Group: "apps", Resource: "deployments", Namespace: "dev", Name: "web"},
+		{Verb: "get", Resource: "pods", Namespace: "prod"},
+	}}
+
+	result, err := Review(client, queries)
+	if err != nil {
+		t.Fatalf("Review() returned unexpected error: %v", err)
+	}
+
+	expected := []bool{false, true, true, true}
+	if len(result.Items) != len(expected) {
+		t.Fatalf("Review() returned %d results, expected %d", len(result.Items), len(expected))
+	}
+	for i, item := range result.Items {
+		if item.Allowed != expected[i] || item.Query != queries.Items[i] {
+			t.Errorf("Review() result %d: got %+v, expected allowed %v for %+v", i, item, expected[i],
+				queries.Items[i])
+		}
+	}
+
+	// Duplicate query is answered from the first review.
+	if actions := len(client.Actions()); actions != 3 {
+		t.Errorf("Review() created %d access reviews, expected 3", actions)
+	}
+}
+
+func TestReviewAttributes(t *testing.T) {
+	var attributes *v1.ResourceAttributes
+	client := newFakeClient(func(a *v1.ResourceAttributes) bool {
+		attributes = a
+		return true
+	})
+
+	query := Query{Verb: "UPDATE", Group: "apps", Resource: "Deployments", Subresource: "Scale",
+		Namespace: "dev", Name: "web"}
+	if _, err := Review(client, QueryList{Items: []Query{query}}); err != nil {
+		t.Fatalf("Review() returned unexpected error: %v", err)
+	}
+
+	expected := v1.ResourceAttributes{Verb: "update", Group: "apps", Resource: "deployments",
+		Subresource: "scale", Namespace: "dev", Name: "web"}
+	if attributes == nil || *attributes != expected {
+		t.Errorf("Review() sent attributes %+v, expected %+v", attributes, expected)
+	}
+}
+
+func TestReviewInvalid(t *testing.T) {
+	tooMany := make([]Query, MaxQueries+1)
+	for i := range tooMany {
+		tooMany[i] = Query{Verb: "get", Resource: "pods", Name: fmt.Sprint(i)}
+	}
+
+	cases := []struct {
+		name    string
+		queries []Query
+	}{
+		{"missing verb", []Query{{Resource: "pods"}}},
+		{"missing resource", []Query{{Verb: "get"}}},
+		{"too many queries", tooMany},
+	}
+
+	for _, c := range cases {
+		client := newFakeClient(func(*v1.ResourceAttributes) bool { return true })
+		_, err := Review(client, QueryList{Items: c.queries})
+		if !apierrors.IsBadRequest(err) {
+			t.Errorf("%s: Review() returned %v, expected bad request", c.name, err)
+		}
+		if len(client.Actions()) != 0 {
+			t.Errorf("%s: Review() created access reviews for invalid queries", c.name)
+		}
+	}
+}
+
+func TestReviewError(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.NewUnauthorized("token expired")
+		})
+
+	_, err := Review(client, QueryList{Items: []Query{{Verb: "get", Resource: "pods"}}})
+	if !errors.IsUnauthorized(err) {
+		t.Errorf("Review() returned %v, expected unauthorized error", err)
+	}
+}
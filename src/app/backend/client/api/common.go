@@ -16,10 +16,14 @@ package api
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
 
 	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/rest"
 )
 
 // ToSelfSubjectAccessReview creates kubernetes API object based on provided data.
@@ -38,6 +42,72 @@ func ToSelfSubjectAccessReview(namespace, name, resourceKind, verb string) *v1.S
 	}
 }
 
+// ToClusterAdminAccessReview creates kubernetes API object that is allowed only for cluster administrators,
+// i.e. users that can perform any action on any resource.
+func ToClusterAdminAccessReview() *v1.SelfSubjectAccessReview {
+	return &v1.SelfSubjectAccessReview{
+		Spec: v1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &v1.ResourceAttributes{
+				Group:    "*",
+				Resource: "*",
+				Verb:     "*",
+			},
+		},
+	}
+}
+
+// GetIdentity returns a loggable description of credentials in given config. Bearer tokens are never
+// returned, only their short fingerprint. Impersonated user is appended to the credentials that impersonate.
+func GetIdentity(cfg *rest.Config) string {
+	identity := "anonymous"
+	switch {
+	case len(cfg.Username) > 0:
+		identity = cfg.Username
+	case len(cfg.BearerToken) > 0:
+		identity = fmt.Sprintf("token:%x", sha256.Sum256([]byte(cfg.BearerToken)))[:14]
+	case len(cfg.CertData) > 0:
+		identity = fmt.Sprintf("cert:%x", sha256.Sum256(cfg.CertData))[:13]
+	}
+
+	if len(cfg.Impersonate.UserName) > 0 {
+		identity = fmt.Sprintf("%s as %s", identity, cfg.Impersonate.UserName)
+	}
+	return identity
+}
+
+// GetCredentialsKey returns a SHA-256 hash of all credentials in given config, including impersonated user,
+// groups and extra fields. Configs with different credentials never get the same key, so it can be used to
+// keep data that must not be shared between users apart.
+func GetCredentialsKey(cfg *rest.Config) string {
+	hash := sha256.New()
+	write := func(values ...string) {
+		for _, value := range values {
+			// Length prefix keeps concatenated values unambiguous.
+			fmt.Fprintf(hash, "%d:%s", len(value), value)
+		}
+	}
+
+	write(cfg.Username, cfg.Password, cfg.BearerToken, cfg.BearerTokenFile, cfg.CertFile, cfg.KeyFile,
+		string(cfg.CertData), string(cfg.KeyData), cfg.Impersonate.UserName)
+
+	groups := append([]string(nil), cfg.Impersonate.Groups...)
+	sort.Strings(groups)
+	write(fmt.Sprint(len(groups)))
+	write(groups...)
+
+	keys := make([]string, 0, len(cfg.Impersonate.Extra))
+	for key := range cfg.Impersonate.Extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		write(key, fmt.Sprint(len(cfg.Impersonate.Extra[key])))
+		write(cfg.Impersonate.Extra[key]...)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 // GenerateCSRFKey generates random csrf key
 func GenerateCSRFKey() string {
 	bytes := make([]byte, 256)
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/rest"
 
 	"github.com/kubernetes/dashboard/src/app/backend/client/api"
 )
@@ -44,3 +45,56 @@ func TestToSelfSubjectAccessReview(t *testing.T) {
 		t.Fatalf("Expected to get %+v but got %+v", expected, got)
 	}
 }
+
+func TestGetIdentity(t *testing.T) {
+	cases := []struct {
+		cfg      *rest.Config
+		expected string
+	}{
+		{&rest.Config{}, "anonymous"},
+		{&rest.Config{Username: "admin", Password: "secret"}, "admin"},
+		{&rest.Config{BearerToken: "token"}, "token:3c469e9d"},
+		{&rest.Config{BearerToken: "token", Impersonate: rest.ImpersonationConfig{UserName: "bob"}},
+			"token:3c469e9d as bob"},
+	}
+
+	for _, c := range cases {
+		if actual := api.GetIdentity(c.cfg); actual != c.expected {
+			t.Errorf("Expected identity %s, but got %s", c.expected, actual)
+		}
+	}
+}
+
+func TestGetCredentialsKey(t *testing.T) {
+	configs := []*rest.Config{
+		{},
+		{Username: "admin", Password: "secret"},
+		{Username: "admin", Password: "other"},
+		{BearerToken: "token"},
+		{BearerToken: "other"},
+		{BearerToken: "token", Impersonate: rest.ImpersonationConfig{UserName: "bob"}},
+		{BearerToken: "token", Impersonate: rest.ImpersonationConfig{UserName: "bob", Groups: []string{"admins"}}},
+		{BearerToken: "token", Impersonate: rest.ImpersonationConfig{UserName: "bob",
+			Extra: map[string][]string{"scopes": {"view"}}}},
+		{TLSClientConfig: rest.TLSClientConfig{CertData: []byte("cert"), KeyData: []byte("key")}},
+	}
+
+	keys := make(map[string]int)
+	for i, cfg := range configs {
+		key := api.GetCredentialsKey(cfg)
+		if len(key) != 64 {
+			t.Errorf("Expected key of config %d to be a full SHA-256 hash, but got %s", i, key)
+		}
+
+		if j, exists := keys[key]; exists {
+			t.Errorf("Expected configs %d and %d to have different keys", j, i)
+		}
+		keys[key] = i
+	}
+
+	same := &rest.Config{BearerToken: "token", Impersonate: rest.ImpersonationConfig{Groups: []string{"b", "a"}}}
+	reordered := &rest.Config{BearerToken: "token", Impersonate: rest.ImpersonationConfig{Groups: []string{"a", "b"}}}
+	if api.GetCredentialsKey(same) != api.GetCredentialsKey(reordered) {
+		t.Error("Expected order of impersonated groups not to change the key")
+	}
+}
@@ -19,12 +19,15 @@ import (
 	v1 "k8s.io/api/authorization/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
 	pluginclientset "github.com/kubernetes/dashboard/src/app/backend/plugin/client/clientset/versioned"
 )
 
@@ -45,6 +48,7 @@ type ClientManager interface {
 	PluginClient(req *restful.Request) (pluginclientset.Interface, error)
 	InsecureAPIExtensionsClient() apiextensionsclientset.Interface
 	InsecurePluginClient() pluginclientset.Interface
+	InsecureDynamicClient() dynamic.Interface
 	CanI(req *restful.Request, ssar *v1.SelfSubjectAccessReview) bool
 	Config(req *restful.Request) (*rest.Config, error)
 	ClientCmdConfig(req *restful.Request) (clientcmd.ClientConfig, error)
@@ -52,6 +56,10 @@ type ClientManager interface {
 	HasAccess(authInfo api.AuthInfo) error
 	VerberClient(req *restful.Request, config *rest.Config) (ResourceVerber, error)
 	SetTokenManager(manager authApi.TokenManager)
+	SetFeatureGateManager(manager featuresApi.FeatureGateManager)
+	SetAuditLogger(logger authApi.AuditLogger)
+	SkipLoginIdentity() string
+	HeaderAuthInfo(req *restful.Request) *api.AuthInfo
 }
 
 // ResourceVerber is responsible for performing generic CRUD operations on all supported resources.
@@ -60,6 +68,10 @@ type ResourceVerber interface {
 		object *runtime.Unknown) error
 	Get(kind string, namespaceSet bool, namespace string, name string) (runtime.Object, error)
 	Delete(kind string, namespaceSet bool, namespace string, name string) error
+	Patch(kind string, namespaceSet bool, namespace string, name string, patchType types.PatchType,
+		data []byte) error
+	Apply(kind string, namespaceSet bool, namespace string, name string, data []byte, fieldManager string,
+		force bool) error
 }
 
 // CanIResponse is used to as response to check whether or not user is allowed to access given endpoint.
@@ -0,0 +1,129 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sync/atomic"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// memoryBudget is the amount of response data that all API groups of a single client may hold in
+// memory. Every client returned for an API request gets its own budget, so a pathological query, i.e.
+// listing all pods of a big cluster, fails with a 'result truncated' error instead of exhausting the
+// memory of the Dashboard pod.
+type memoryBudget struct {
+	limit     int64
+	remaining int64
+}
+
+// budgetRoundTripper charges responses to the memory budget. Streamed responses such as logs and
+// watches are not counted.
+type budgetRoundTripper struct {
+	delegate http.RoundTripper
+	budget   *memoryBudget
+}
+
+// RoundTrip implements http.RoundTripper. Budgeted responses are read up front so that their size is
+// known before they are decoded. Once the budget is used up, the apiserver response is replaced by
+// a status that the client decodes into a ResultTruncated error.
+func (self *budgetRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := self.delegate.RoundTrip(req)
+	if err != nil || !isBudgeted(resp) {
+		return resp, err
+	}
+	defer resp.Body.Close()
+
+	remaining := atomic.LoadInt64(&self.budget.remaining)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, remaining+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if atomic.AddInt64(&self.budget.remaining, -int64(len(body))) < 0 {
+		return self.truncatedResponse(req)
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+func (self *budgetRoundTripper) truncatedResponse(req *http.Request) (*http.Response, error) {
+	status := errors.NewResultTruncated(fmt.Sprintf("%dMB", self.budget.limit>>20)).ErrStatus
+	body, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status.Code, http.StatusText(int(status.Code))),
+		StatusCode:    int(status.Code),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// isBudgeted returns true for successful responses that are decoded as a whole, i.e. objects and lists.
+func isBudgeted(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK || resp.Request == nil || resp.Request.Method != http.MethodGet {
+		return false
+	}
+
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	if _, streamed := params["stream"]; streamed {
+		return false
+	}
+
+	return mediaType == "application/json" || mediaType == DefaultContentType
+}
+
+// withMemoryBudget returns a copy of given config whose clients share a budget of given size in
+// megabytes. Config is returned unchanged if the budget is not positive.
+func withMemoryBudget(cfg *rest.Config, megabytes int) *rest.Config {
+	if megabytes <= 0 {
+		return cfg
+	}
+
+	limit := int64(megabytes) << 20
+	budget := &memoryBudget{limit: limit, remaining: limit}
+	budgeted := rest.CopyConfig(cfg)
+	budgeted.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &budgetRoundTripper{delegate: rt, budget: budget}
+	})
+	return budgeted
+}
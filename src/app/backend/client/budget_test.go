@@ -0,0 +1,102 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+func newPodList(annotationSize int) *v1.PodList {
+	return &v1.PodList{
+		TypeMeta: metaV1.TypeMeta{Kind: "PodList", APIVersion: "v1"},
+		Items: []v1.Pod{{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:        "pod",
+				Namespace:   "default",
+				Annotations: map[string]string{"data": strings.Repeat("x", annotationSize)},
+			},
+		}},
+	}
+}
+
+func TestWithMemoryBudget(t *testing.T) {
+	cases := []struct {
+		info           string
+		annotationSize int
+		budget         int
+		truncated      bool
+	}{
+		{"should return list smaller than budget", 1024, 1, false},
+		{"should truncate list larger than budget", 2 << 20, 1, true},
+		{"should not limit lists if budget is disabled", 2 << 20, 0, false},
+	}
+
+	for _, c := range cases {
+		list := newPodList(c.annotationSize)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/log") {
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte(strings.Repeat("x", 2<<20)))
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(list)
+		}))
+
+		cfg := withMemoryBudget(&rest.Config{Host: server.URL,
+			ContentConfig: rest.ContentConfig{ContentType: "application/json"}}, c.budget)
+		client, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.info, err)
+		}
+
+		// Streamed responses must not use up the budget.
+		if _, err := client.CoreV1().Pods("default").GetLogs("pod", &v1.PodLogOptions{}).DoRaw(context.TODO()); err != nil {
+			t.Fatalf("%s: unexpected error while reading logs: %v", c.info, err)
+		}
+
+		result, err := client.CoreV1().Pods("default").List(context.TODO(), metaV1.ListOptions{})
+		server.Close()
+
+		if c.truncated {
+			if !errors.IsResultTruncated(err) {
+				t.Errorf("%s: expected result truncated error, got %v", c.info, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.info, err)
+			continue
+		}
+
+		if len(result.Items) != 1 || len(result.Items[0].Annotations["data"]) != c.annotationSize {
+			t.Errorf("%s: expected list to be returned unchanged", c.info)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/client-go/rest"
+)
+
+// contextRoundTripper ties apiserver calls to the context of the API request they are made for. Once that
+// request is done, i.e. because its timeout passed or the user went away, calls that are still in flight
+// are cancelled, so that handlers do not keep loading data nobody waits for.
+type contextRoundTripper struct {
+	delegate http.RoundTripper
+	ctx      context.Context
+}
+
+// RoundTrip implements http.RoundTripper.
+func (self *contextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	go func() {
+		select {
+		case <-self.ctx.Done():
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	return self.delegate.RoundTrip(req.WithContext(ctx))
+}
+
+// withRequestContext returns a copy of given config whose clients cancel their calls when given API
+// request is done. Config is returned unchanged if there is no request.
+func withRequestContext(cfg *rest.Config, req *http.Request) *rest.Config {
+	if req == nil || req.Context().Done() == nil {
+		return cfg
+	}
+
+	ctx := req.Context()
+	bound := rest.CopyConfig(cfg)
+	bound.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &contextRoundTripper{delegate: rt, ctx: ctx}
+	})
+	return bound
+}
@@ -0,0 +1,70 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+type contextRecordingRoundTripper struct {
+	ctx context.Context
+}
+
+func (self *contextRecordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	self.ctx = req.Context()
+	return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+}
+
+func TestContextRoundTripper(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	delegate := &contextRecordingRoundTripper{}
+	rt := &contextRoundTripper{delegate: delegate, ctx: ctx}
+
+	if _, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)); err != nil {
+		t.Fatalf("RoundTrip() returned unexpected error: %s", err.Error())
+	}
+
+	if delegate.ctx.Err() != nil {
+		t.Fatalf("apiserver call was cancelled before the API request was done")
+	}
+
+	cancel()
+	select {
+	case <-delegate.ctx.Done():
+	case <-time.After(time.Second):
+		t.Errorf("apiserver call was not cancelled when the API request was done")
+	}
+}
+
+func TestWithRequestContext(t *testing.T) {
+	cfg := &rest.Config{Host: "http://localhost:8080"}
+
+	if withRequestContext(cfg, nil) != cfg {
+		t.Errorf("withRequestContext() without request should return the config unchanged")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bound := withRequestContext(cfg, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	if bound == cfg || bound.WrapTransport == nil {
+		t.Errorf("withRequestContext() should return a copy of the config with wrapped transport")
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/xsrftoken"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// CookieName is the name of the cookie with a random nonce, which tokens of the browser are bound to.
+	CookieName = "kdCsrfNonce"
+	// HeaderName is the name of the header carrying the token.
+	HeaderName = "X-CSRF-TOKEN"
+	// DefaultRotationPeriod is the period after which tokens are signed with a new key. Tokens signed with the
+	// previous key are still valid, so every token is valid for at least one period.
+	DefaultRotationPeriod = 12 * time.Hour
+
+	// MsgCSRFValidationFailed is returned for requests without a valid token.
+	MsgCSRFValidationFailed = "CSRF validation failed"
+
+	jweTokenHeader      = "jweToken"
+	authorizationHeader = "Authorization"
+	nonceLength         = 32
+)
+
+// SessionResolver returns ID of the session given JWE token belongs to. It returns an empty ID if sessions are not
+// tracked and an error if the session does not exist anymore.
+type SessionResolver func(jweToken string) (string, error)
+
+// SessionTokenManager issues CSRF tokens bound to the session of the user and to the browser, and validates them.
+// Tokens use double-submit cookies: the token sent in the header is only valid together with the SameSite cookie
+// set when it was issued, so it can be neither used from another site nor replayed by another browser or session.
+// Signing keys are derived from the CSRF key and rotate every rotation period, so all replicas sharing the key
+// accept the same tokens.
+type SessionTokenManager struct {
+	key            string
+	sessions       SessionResolver
+	rotationPeriod time.Duration
+	now            func() time.Time
+}
+
+// Generate returns a token for given action, bound to the session and the browser of the request. The nonce
+// cookie is set on the response if the request does not have one yet.
+func (self *SessionTokenManager) Generate(request *http.Request, response http.ResponseWriter, action string) (
+	string, error) {
+	nonce := getNonce(request)
+	if len(nonce) == 0 {
+		var err error
+		if nonce, err = newNonce(); err != nil {
+			return "", err
+		}
+
+		http.SetCookie(response, &http.Cookie{
+			Name:     CookieName,
+			Value:    nonce,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   request.TLS != nil,
+			SameSite: http.SameSiteStrictMode,
+		})
+	}
+
+	binding, err := self.getBinding(request)
+	if err != nil {
+		return "", err
+	}
+
+	return xsrftoken.Generate(self.getKey(self.now()), binding+nonce, action), nil
+}
+
+// Validate checks that the request carries a valid token for given action in the header together with the
+// nonce cookie the token has been issued with, and that the token belongs to the session of the request.
+func (self *SessionTokenManager) Validate(request *http.Request, action string) error {
+	token := request.Header.Get(HeaderName)
+	nonce := getNonce(request)
+	if len(token) == 0 || len(nonce) == 0 {
+		return errors.NewInvalid(MsgCSRFValidationFailed)
+	}
+
+	binding, err := self.getBinding(request)
+	if err != nil {
+		return errors.NewInvalid(MsgCSRFValidationFailed)
+	}
+
+	now := self.now()
+	for _, at := range []time.Time{now, now.Add(-self.rotationPeriod)} {
+		if xsrftoken.Valid(token, self.getKey(at), binding+nonce, action) {
+			return nil
+		}
+	}
+
+	return errors.NewInvalid(MsgCSRFValidationFailed)
+}
+
+// getKey returns the signing key of the rotation period given time falls into.
+func (self *SessionTokenManager) getKey(at time.Time) string {
+	epoch := at.UnixNano() / int64(self.rotationPeriod)
+	mac := hmac.New(sha256.New, []byte(self.key))
+	mac.Write([]byte(strconv.FormatInt(epoch, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// getBinding returns the value tokens of the request are bound to. It is the ID of the session of the JWE token
+// if sessions are tracked, a hash of the credentials for other authenticated requests and a constant value for
+// anonymous requests.
+func (self *SessionTokenManager) getBinding(request *http.Request) (string, error) {
+	jweToken := request.Header.Get(jweTokenHeader)
+	if len(jweToken) > 0 && self.sessions != nil {
+		id, err := self.sessions(jweToken)
+		if err != nil {
+			return "", err
+		}
+
+		if len(id) > 0 {
+			return "session:" + id + ":", nil
+		}
+	}
+
+	credentials := jweToken + request.Header.Get(authorizationHeader)
+	if len(credentials) == 0 {
+		return "anonymous:", nil
+	}
+
+	hash := sha256.Sum256([]byte(credentials))
+	return "credentials:" + hex.EncodeToString(hash[:]) + ":", nil
+}
+
+func getNonce(request *http.Request) string {
+	cookie, err := request.Cookie(CookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+func newNonce() (string, error) {
+	nonce := make([]byte, nonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(nonce), nil
+}
+
+// NewSessionTokenManager creates CSRF token manager signing tokens with keys derived from given CSRF key. Tokens
+// are bound to sessions returned by given resolver, it may be nil if sessions are not tracked.
+func NewSessionTokenManager(key string, sessions SessionResolver) *SessionTokenManager {
+	return &SessionTokenManager{
+		key:            key,
+		sessions:       sessions,
+		rotationPeriod: DefaultRotationPeriod,
+		now:            time.Now,
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csrf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+func fakeSessions(jweToken string) (string, error) {
+	switch jweToken {
+	case "token-a", "token-a-refreshed":
+		return "session-a", nil
+	case "token-b":
+		return "session-b", nil
+	default:
+		return "", errors.NewNotFound("Session not found.")
+	}
+}
+
+func newRequest(jweToken string, cookie *http.Cookie, token string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/pod", nil)
+	if len(jweToken) > 0 {
+		request.Header.Set(jweTokenHeader, jweToken)
+	}
+
+	if cookie != nil {
+		request.AddCookie(cookie)
+	}
+
+	if len(token) > 0 {
+		request.Header.Set(HeaderName, token)
+	}
+	return request
+}
+
+// issue returns a token for given action together with the nonce cookie set by the manager.
+func issue(t *testing.T, manager *SessionTokenManager, jweToken, action string) (string, *http.Cookie) {
+	recorder := httptest.NewRecorder()
+	token, err := manager.Generate(newRequest(jweToken, nil, ""), recorder, action)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != CookieName {
+		t.Fatalf("Expected %s cookie to be set, got %v", CookieName, cookies)
+	}
+	return token, cookies[0]
+}
+
+func TestSessionTokenManager_Generate(t *testing.T) {
+	manager := NewSessionTokenManager("key", fakeSessions)
+	_, cookie := issue(t, manager, "token-a", "pod")
+
+	if !cookie.HttpOnly || cookie.SameSite != http.SameSiteStrictMode || cookie.Path != "/" {
+		t.Errorf("Expected http only cookie with strict SameSite, got %#v", cookie)
+	}
+
+	recorder := httptest.NewRecorder()
+	if _, err := manager.Generate(newRequest("token-a", cookie, ""), recorder, "pod"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.Result().Cookies()) > 0 {
+		t.Error("Expected nonce cookie not to be replaced when request has one")
+	}
+
+	if _, err := manager.Generate(newRequest("expired", nil, ""), httptest.NewRecorder(), "pod"); err == nil {
+		t.Error("Expected token not to be issued for a session that does not exist")
+	}
+}
+
+func TestSessionTokenManager_Validate(t *testing.T) {
+	manager := NewSessionTokenManager("key", fakeSessions)
+	token, cookie := issue(t, manager, "token-a", "pod")
+	_, otherCookie := issue(t, manager, "token-a", "pod")
+	otherToken, _ := issue(t, manager, "token-b", "pod")
+
+	cases := []struct {
+		info    string
+		request *http.Request
+		action  string
+		valid   bool
+	}{
+		{"token of the session and browser", newRequest("token-a", cookie, token), "pod", true},
+		{"token after refresh of the session", newRequest("token-a-refreshed", cookie, token), "pod", true},
+		{"token of another action", newRequest("token-a", cookie, token), "secret", false},
+		{"missing token", newRequest("token-a", cookie, ""), "pod", false},
+		{"missing cookie", newRequest("token-a", nil, token), "pod", false},
+		{"token replayed by another browser", newRequest("token-a", otherCookie, token), "pod", false},
+		{"token reused in another session", newRequest("token-b", cookie, token), "pod", false},
+		{"token of another session", newRequest("token-a", cookie, otherToken), "pod", false},
+		{"token of expired session", newRequest("expired", cookie, token), "pod", false},
+		{"anonymous request", newRequest("", cookie, token), "pod", false},
+	}
+
+	for _, c := range cases {
+		if err := manager.Validate(c.request, c.action); (err == nil) != c.valid {
+			t.Errorf("%s: expected valid to be %t, got error %v", c.info, c.valid, err)
+		}
+	}
+}
+
+func TestSessionTokenManager_ValidateAnonymous(t *testing.T) {
+	manager := NewSessionTokenManager("key", nil)
+	token, cookie := issue(t, manager, "", "login")
+
+	if err := manager.Validate(newRequest("", cookie, token), "login"); err != nil {
+		t.Errorf("Expected anonymous token to be valid, got %v", err)
+	}
+
+	if err := manager.Validate(newRequest("token-a", cookie, token), "login"); err == nil {
+		t.Error("Expected anonymous token to be rejected for authenticated requests")
+	}
+
+	authenticated, cookie := issue(t, manager, "token-a", "login")
+	if err := manager.Validate(newRequest("token-a", cookie, authenticated), "login"); err != nil {
+		t.Errorf("Expected token bound to credentials to be valid, got %v", err)
+	}
+
+	if err := manager.Validate(newRequest("token-b", cookie, authenticated), "login"); err == nil {
+		t.Error("Expected token bound to credentials to be rejected for other credentials")
+	}
+}
+
+func TestSessionTokenManager_Rotation(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	manager := NewSessionTokenManager("key", fakeSessions)
+	manager.now = func() time.Time { return now }
+	token, cookie := issue(t, manager, "token-a", "pod")
+
+	now = now.Add(DefaultRotationPeriod)
+	if err := manager.Validate(newRequest("token-a", cookie, token), "pod"); err != nil {
+		t.Errorf("Expected token signed with the previous key to be valid, got %v", err)
+	}
+
+	now = now.Add(DefaultRotationPeriod)
+	if err := manager.Validate(newRequest("token-a", cookie, token), "pod"); err == nil {
+		t.Error("Expected token signed with a rotated out key to be rejected")
+	}
+
+	if other := NewSessionTokenManager("other", fakeSessions); other.Validate(
+		newRequest("token-a", cookie, token), "pod") == nil {
+		t.Error("Expected token to be rejected by a manager with another key")
+	}
+}
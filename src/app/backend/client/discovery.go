@@ -0,0 +1,188 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+)
+
+// discoveryCaches contains discovery results per apiserver host. Discovery documents are the same for all users,
+// so a single cache is shared by all clients of a host.
+var discoveryCaches = &discoveryCacheRegistry{caches: make(map[string]*discoveryCache)}
+
+type discoveryCacheRegistry struct {
+	mux    sync.Mutex
+	caches map[string]*discoveryCache
+}
+
+func (self *discoveryCacheRegistry) get(host string) *discoveryCache {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	cache, exists := self.caches[host]
+	if !exists {
+		cache = &discoveryCache{resources: make(map[string]*cachedResources)}
+		self.caches[host] = cache
+	}
+	return cache
+}
+
+type cachedGroups struct {
+	list    *metaV1.APIGroupList
+	fetched time.Time
+}
+
+type cachedResources struct {
+	list    *metaV1.APIResourceList
+	fetched time.Time
+}
+
+// discoveryCache holds the last successful discovery results. Results are kept after 'discovery-cache-ttl'
+// passes, so they can be served when the apiserver fails to refresh them, i.e. while an aggregated APIService
+// is down.
+type discoveryCache struct {
+	mux       sync.RWMutex
+	groups    *cachedGroups
+	resources map[string]*cachedResources
+}
+
+func isFresh(fetched time.Time) bool {
+	return time.Since(fetched) < time.Duration(args.Holder.GetDiscoveryCacheTTL())*time.Second
+}
+
+// cachedDiscoveryClient serves discovery results from the cache and falls back to stale results when the
+// apiserver fails to refresh them. Other calls are passed to the delegate.
+type cachedDiscoveryClient struct {
+	discovery.DiscoveryInterface
+	cache *discoveryCache
+}
+
+// ServerGroups implements discovery.ServerGroupsInterface.
+func (self *cachedDiscoveryClient) ServerGroups() (*metaV1.APIGroupList, error) {
+	self.cache.mux.RLock()
+	cached := self.cache.groups
+	self.cache.mux.RUnlock()
+	if cached != nil && isFresh(cached.fetched) {
+		return cached.list, nil
+	}
+
+	list, err := self.DiscoveryInterface.ServerGroups()
+	if err != nil {
+		if cached != nil {
+			log.Printf("Serving cached API groups, because discovery failed: %s", err.Error())
+			return cached.list, nil
+		}
+		return nil, err
+	}
+
+	self.cache.mux.Lock()
+	self.cache.groups = &cachedGroups{list: list, fetched: time.Now()}
+	self.cache.mux.Unlock()
+	return list, nil
+}
+
+// ServerResourcesForGroupVersion implements discovery.ServerResourcesInterface.
+func (self *cachedDiscoveryClient) ServerResourcesForGroupVersion(groupVersion string) (
+	*metaV1.APIResourceList, error) {
+	self.cache.mux.RLock()
+	cached := self.cache.resources[groupVersion]
+	self.cache.mux.RUnlock()
+	if cached != nil && isFresh(cached.fetched) {
+		return cached.list, nil
+	}
+
+	list, err := self.DiscoveryInterface.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		if cached != nil {
+			log.Printf("Serving cached resources of %s, because discovery failed: %s", groupVersion, err.Error())
+			return cached.list, nil
+		}
+		return nil, err
+	}
+
+	self.cache.mux.Lock()
+	self.cache.resources[groupVersion] = &cachedResources{list: list, fetched: time.Now()}
+	self.cache.mux.Unlock()
+	return list, nil
+}
+
+// ServerResources implements discovery.ServerResourcesInterface.
+func (self *cachedDiscoveryClient) ServerResources() ([]*metaV1.APIResourceList, error) {
+	_, resources, err := self.ServerGroupsAndResources()
+	return resources, err
+}
+
+// ServerGroupsAndResources implements discovery.ServerResourcesInterface. Group versions that cannot be
+// discovered and were never cached are reported in discovery.ErrGroupDiscoveryFailed together with the
+// resources of all other groups.
+func (self *cachedDiscoveryClient) ServerGroupsAndResources() ([]*metaV1.APIGroup, []*metaV1.APIResourceList,
+	error) {
+	return discovery.ServerGroupsAndResources(self)
+}
+
+// ServerPreferredResources implements discovery.ServerResourcesInterface.
+func (self *cachedDiscoveryClient) ServerPreferredResources() ([]*metaV1.APIResourceList, error) {
+	return discovery.ServerPreferredResources(self)
+}
+
+// ServerPreferredNamespacedResources implements discovery.ServerResourcesInterface.
+func (self *cachedDiscoveryClient) ServerPreferredNamespacedResources() ([]*metaV1.APIResourceList, error) {
+	return discovery.ServerPreferredNamespacedResources(self)
+}
+
+// Fresh implements discovery.CachedDiscoveryInterface. The cache refreshes expired results on its own, so
+// callers never need to retry.
+func (self *cachedDiscoveryClient) Fresh() bool {
+	return true
+}
+
+// Invalidate implements discovery.CachedDiscoveryInterface. Cached results are expired, but kept as a fallback.
+func (self *cachedDiscoveryClient) Invalidate() {
+	self.cache.mux.Lock()
+	defer self.cache.mux.Unlock()
+
+	if self.cache.groups != nil {
+		self.cache.groups = &cachedGroups{list: self.cache.groups.list}
+	}
+	for groupVersion, resources := range self.cache.resources {
+		self.cache.resources[groupVersion] = &cachedResources{list: resources.list}
+	}
+}
+
+// NewCachedDiscoveryClient returns a discovery client for given config, that shares cached discovery results
+// with all other clients of the same apiserver.
+func NewCachedDiscoveryClient(cfg *rest.Config) (discovery.CachedDiscoveryInterface, error) {
+	delegate, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCachedDiscoveryClient(delegate, cfg.Host), nil
+}
+
+func newCachedDiscoveryClient(delegate discovery.DiscoveryInterface, host string) *cachedDiscoveryClient {
+	return &cachedDiscoveryClient{
+		DiscoveryInterface: delegate,
+		cache:              discoveryCaches.get(host),
+	}
+}
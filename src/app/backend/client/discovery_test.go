@@ -0,0 +1,98 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakeclient "k8s.io/client-go/testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+)
+
+// failingDiscovery counts discovery calls and fails them when down is set, like an apiserver with an
+// unavailable aggregated API.
+type failingDiscovery struct {
+	discovery.DiscoveryInterface
+	down  bool
+	calls int
+}
+
+func (self *failingDiscovery) ServerGroups() (*metaV1.APIGroupList, error) {
+	self.calls++
+	if self.down {
+		return nil, errors.New("service unavailable")
+	}
+	return &metaV1.APIGroupList{Groups: []metaV1.APIGroup{{
+		Name:             "metrics.k8s.io",
+		Versions:         []metaV1.GroupVersionForDiscovery{{GroupVersion: "metrics.k8s.io/v1beta1", Version: "v1beta1"}},
+		PreferredVersion: metaV1.GroupVersionForDiscovery{GroupVersion: "metrics.k8s.io/v1beta1", Version: "v1beta1"},
+	}}}, nil
+}
+
+func (self *failingDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metaV1.APIResourceList, error) {
+	self.calls++
+	if self.down {
+		return nil, errors.New("service unavailable")
+	}
+	return &metaV1.APIResourceList{GroupVersion: groupVersion,
+		APIResources: []metaV1.APIResource{{Name: "pods", Kind: "PodMetrics", Namespaced: true}}}, nil
+}
+
+func TestCachedDiscoveryClient(t *testing.T) {
+	args.GetHolderBuilder().SetDiscoveryCacheTTL(300)
+	defer args.GetHolderBuilder().SetDiscoveryCacheTTL(0)
+
+	delegate := &failingDiscovery{DiscoveryInterface: &fakediscovery.FakeDiscovery{Fake: &fakeclient.Fake{}}}
+	client := newCachedDiscoveryClient(delegate, "https://cached-discovery-test")
+
+	if _, err := client.ServerResourcesForGroupVersion("metrics.k8s.io/v1beta1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.ServerResourcesForGroupVersion("metrics.k8s.io/v1beta1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if delegate.calls != 1 {
+		t.Errorf("Expected fresh results to be served from cache, got %d calls", delegate.calls)
+	}
+
+	delegate.down = true
+	client.Invalidate()
+	list, err := client.ServerResourcesForGroupVersion("metrics.k8s.io/v1beta1")
+	if err != nil || len(list.APIResources) != 1 {
+		t.Errorf("Expected stale results while discovery fails, got %v, %v", list, err)
+	}
+	if delegate.calls != 2 {
+		t.Errorf("Expected expired results to be refreshed, got %d calls", delegate.calls)
+	}
+
+	if _, err := client.ServerResourcesForGroupVersion("custom.metrics.k8s.io/v1beta1"); err == nil {
+		t.Error("Expected error for group version that was never discovered")
+	}
+
+	if _, _, err := client.ServerGroupsAndResources(); err == nil {
+		t.Error("Expected error for groups that were never discovered")
+	}
+
+	delegate.down = false
+	groups, resources, err := client.ServerGroupsAndResources()
+	if err != nil || len(groups) != 1 || len(resources) != 1 {
+		t.Errorf("Unexpected groups and resources %v, %v, %v", groups, resources, err)
+	}
+}
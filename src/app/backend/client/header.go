@@ -0,0 +1,141 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+// headerAuthenticator trusts users and groups set in request headers by an authenticating proxy. Anyone can set the
+// headers, so they are only trusted when the request comes from one of the proxy networks or presents a client
+// certificate of the proxy.
+type headerAuthenticator struct {
+	userHeader   string
+	groupHeader  string
+	trustedNets  []*net.IPNet
+	clientCAs    *x509.CertPool
+	allowedNames []string
+}
+
+// Returns auth info impersonating the user and groups from the request headers or nil if the request does not come
+// from a trusted proxy or does not name any user.
+func (self *headerAuthenticator) authInfo(req *http.Request) *api.AuthInfo {
+	user := strings.TrimSpace(req.Header.Get(self.userHeader))
+	if len(user) == 0 || !self.isTrusted(req) {
+		return nil
+	}
+
+	authInfo := &api.AuthInfo{Impersonate: user}
+	for _, value := range req.Header[http.CanonicalHeaderKey(self.groupHeader)] {
+		for _, group := range strings.Split(value, ",") {
+			if group = strings.TrimSpace(group); len(group) > 0 {
+				authInfo.ImpersonateGroups = append(authInfo.ImpersonateGroups, group)
+			}
+		}
+	}
+
+	return authInfo
+}
+
+func (self *headerAuthenticator) isTrusted(req *http.Request) bool {
+	// Forward headers are set by clients unless a proxy overwrites them, so only the connection is checked.
+	if ip := net.ParseIP(authApi.GetSourceIP(req)); ip != nil {
+		for _, trustedNet := range self.trustedNets {
+			if trustedNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	return self.hasTrustedCertificate(req.TLS)
+}
+
+// Client certificates are only requested, not verified by the server, so they are verified here.
+func (self *headerAuthenticator) hasTrustedCertificate(state *tls.ConnectionState) bool {
+	if self.clientCAs == nil || state == nil || len(state.PeerCertificates) == 0 {
+		return false
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	cert := state.PeerCertificates[0]
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         self.clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return false
+	}
+
+	if len(self.allowedNames) == 0 {
+		return true
+	}
+
+	for _, name := range self.allowedNames {
+		if cert.Subject.CommonName == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newHeaderAuthenticator creates authenticator trusting proxies from given networks or with client certificates
+// signed by CAs from given file.
+func newHeaderAuthenticator(userHeader, groupHeader string, trustedCIDRs []string, clientCAFile string,
+	allowedNames []string) (*headerAuthenticator, error) {
+	result := &headerAuthenticator{
+		userHeader:   userHeader,
+		groupHeader:  groupHeader,
+		allowedNames: allowedNames,
+	}
+
+	for _, cidr := range trustedCIDRs {
+		_, trustedNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+
+		result.trustedNets = append(result.trustedNets, trustedNet)
+	}
+
+	if len(clientCAFile) > 0 {
+		pem, err := ioutil.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		result.clientCAs = x509.NewCertPool()
+		if !result.clientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", clientCAFile)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Creates certificate signed by given parent or self-signed CA certificate if parent is nil.
+func newTestCertificate(t *testing.T, commonName string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (
+	*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+func TestHeaderAuthenticator(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "proxy-ca", nil, nil)
+	otherCA, otherCAKey := newTestCertificate(t, "other-ca", nil, nil)
+	proxyCert, _ := newTestCertificate(t, "proxy", ca, caKey)
+	otherProxyCert, _ := newTestCertificate(t, "other-proxy", ca, caKey)
+	foreignCert, _ := newTestCertificate(t, "proxy", otherCA, otherCAKey)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+	authenticator, err := newHeaderAuthenticator("X-Remote-User", "X-Remote-Group", []string{"10.0.0.0/8"}, "",
+		[]string{"proxy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.clientCAs = clientCAs
+
+	withCert := func(cert *x509.Certificate) *tls.ConnectionState {
+		return &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	}
+
+	cases := []struct {
+		info       string
+		remoteAddr string
+		tls        *tls.ConnectionState
+		header     http.Header
+		expected   *api.AuthInfo
+	}{
+		{
+			"trusted network", "10.1.2.3:43210", nil,
+			http.Header{"X-Remote-User": {"alice"}, "X-Remote-Group": {"dev, ops", "admins"}},
+			&api.AuthInfo{Impersonate: "alice", ImpersonateGroups: []string{"dev", "ops", "admins"}},
+		},
+		{
+			"trusted network without user", "10.1.2.3:43210", nil,
+			http.Header{"X-Remote-Group": {"admins"}},
+			nil,
+		},
+		{
+			"untrusted network", "192.168.1.1:43210", nil,
+			http.Header{"X-Remote-User": {"alice"}, "X-Forwarded-For": {"10.1.2.3"}},
+			nil,
+		},
+		{
+			"trusted certificate", "192.168.1.1:43210", withCert(proxyCert),
+			http.Header{"X-Remote-User": {"alice"}},
+			&api.AuthInfo{Impersonate: "alice"},
+		},
+		{
+			"certificate with name not allowed", "192.168.1.1:43210", withCert(otherProxyCert),
+			http.Header{"X-Remote-User": {"alice"}},
+			nil,
+		},
+		{
+			"certificate signed by other ca", "192.168.1.1:43210", withCert(foreignCert),
+			http.Header{"X-Remote-User": {"alice"}},
+			nil,
+		},
+		{
+			"ca certificate", "192.168.1.1:43210", withCert(ca),
+			http.Header{"X-Remote-User": {"alice"}},
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		req := &http.Request{RemoteAddr: c.remoteAddr, TLS: c.tls, Header: c.header}
+		if actual := authenticator.authInfo(req); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Expected %#v, but got %#v.", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestNewHeaderAuthenticator(t *testing.T) {
+	if _, err := newHeaderAuthenticator("X-Remote-User", "X-Remote-Group", []string{"10.0.0.1"}, "", nil); err == nil {
+		t.Error("Expected error for invalid network, but got nil.")
+	}
+
+	if _, err := newHeaderAuthenticator("X-Remote-User", "X-Remote-Group", nil, "missing-ca.crt", nil); err == nil {
+		t.Error("Expected error for missing client CA file, but got nil.")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	v1 "k8s.io/api/authorization/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Prefix of user names of service accounts. Impersonating them is authorized as access to serviceaccounts resource.
+const serviceAccountUserPrefix = "system:serviceaccount:"
+
+// Returns auth info holding only impersonation settings from the request headers or nil if there are none.
+func getImpersonation(req *restful.Request) *api.AuthInfo {
+	user := req.HeaderParameter(ImpersonateUserHeader)
+	if len(user) == 0 {
+		return nil
+	}
+
+	authInfo := &api.AuthInfo{Impersonate: user}
+	if groups := req.Request.Header[ImpersonateGroupHeader]; len(groups) > 0 {
+		authInfo.ImpersonateGroups = groups
+	}
+
+	for headerName, headerValues := range req.Request.Header {
+		if strings.HasPrefix(headerName, ImpersonateUserExtraHeader) {
+			extraName := headerName[len(ImpersonateUserExtraHeader):]
+			if authInfo.ImpersonateUserExtra == nil {
+				authInfo.ImpersonateUserExtra = make(map[string][]string)
+			}
+			authInfo.ImpersonateUserExtra[extraName] = headerValues
+		}
+	}
+
+	return authInfo
+}
+
+// Applies impersonation headers of the request to auth info of a user logged in to Dashboard. Auth info without own
+// credentials is used with credentials of Dashboard, so the user always has to be allowed to impersonate first.
+func (self *clientManager) withImpersonation(req *restful.Request, authInfo *api.AuthInfo) (*api.AuthInfo, error) {
+	impersonation := getImpersonation(req)
+	if impersonation == nil {
+		return authInfo, nil
+	}
+
+	for _, attributes := range toImpersonationAttributes(impersonation) {
+		allowed, err := self.canImpersonate(authInfo, attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		if !allowed {
+			return nil, errors.NewGenericResponse(http.StatusForbidden,
+				"user is not allowed to impersonate "+attributes.Resource+" "+attributes.Name)
+		}
+	}
+
+	result := authInfo.DeepCopy()
+	result.Impersonate = impersonation.Impersonate
+	result.ImpersonateGroups = impersonation.ImpersonateGroups
+	result.ImpersonateUserExtra = impersonation.ImpersonateUserExtra
+	return result, nil
+}
+
+// Checks access with self subject access review made with credentials of the user. Users, that are impersonated
+// by Dashboard, are checked with subject access review made by Dashboard.
+func (self *clientManager) canImpersonate(authInfo *api.AuthInfo, attributes *v1.ResourceAttributes) (bool, error) {
+	if !hasCredentials(authInfo) {
+		extra := make(map[string]v1.ExtraValue)
+		for key, values := range authInfo.ImpersonateUserExtra {
+			extra[key] = values
+		}
+
+		review, err := self.InsecureClient().AuthorizationV1().SubjectAccessReviews().Create(context.TODO(),
+			&v1.SubjectAccessReview{
+				Spec: v1.SubjectAccessReviewSpec{
+					ResourceAttributes: attributes,
+					User:               authInfo.Impersonate,
+					Groups:             authInfo.ImpersonateGroups,
+					Extra:              extra,
+				},
+			}, metaV1.CreateOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return review.Status.Allowed, nil
+	}
+
+	cfg, err := self.buildConfigFromFlags(self.apiserverHost, self.kubeConfigPath)
+	if err != nil {
+		return false, err
+	}
+
+	clientConfig, err := self.buildCmdConfig(authInfo, cfg).ClientConfig()
+	if err != nil {
+		return false, err
+	}
+
+	client, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return false, err
+	}
+
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(),
+		&v1.SelfSubjectAccessReview{
+			Spec: v1.SelfSubjectAccessReviewSpec{ResourceAttributes: attributes},
+		}, metaV1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	return review.Status.Allowed, nil
+}
+
+// Returns resource attributes, that API server authorizes when impersonating given user, groups and extras.
+func toImpersonationAttributes(impersonation *api.AuthInfo) []*v1.ResourceAttributes {
+	result := []*v1.ResourceAttributes{toUserImpersonationAttributes(impersonation.Impersonate)}
+	for _, group := range impersonation.ImpersonateGroups {
+		result = append(result, &v1.ResourceAttributes{Verb: "impersonate", Resource: "groups", Name: group})
+	}
+
+	for key, values := range impersonation.ImpersonateUserExtra {
+		for _, value := range values {
+			result = append(result, &v1.ResourceAttributes{
+				Verb:        "impersonate",
+				Group:       "authentication.k8s.io",
+				Resource:    "userextras",
+				Subresource: key,
+				Name:        value,
+			})
+		}
+	}
+
+	return result
+}
+
+func toUserImpersonationAttributes(user string) *v1.ResourceAttributes {
+	if strings.HasPrefix(user, serviceAccountUserPrefix) {
+		if parts := strings.Split(strings.TrimPrefix(user, serviceAccountUserPrefix), ":"); len(parts) == 2 {
+			return &v1.ResourceAttributes{
+				Verb:      "impersonate",
+				Resource:  "serviceaccounts",
+				Namespace: parts[0],
+				Name:      parts[1],
+			}
+		}
+	}
+
+	return &v1.ResourceAttributes{Verb: "impersonate", Resource: "users", Name: user}
+}
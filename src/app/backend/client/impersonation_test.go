@@ -0,0 +1,90 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	restful "github.com/emicklei/go-restful"
+	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestGetImpersonation(t *testing.T) {
+	cases := []struct {
+		header   http.Header
+		expected *api.AuthInfo
+	}{
+		{http.Header{}, nil},
+		{
+			http.Header{"Impersonate-Group": {"group1"}},
+			nil,
+		},
+		{
+			http.Header{
+				"Impersonate-User":         {"user1"},
+				"Impersonate-Group":        {"group1", "group2"},
+				"Impersonate-Extra-Scopes": {"view"},
+			},
+			&api.AuthInfo{
+				Impersonate:          "user1",
+				ImpersonateGroups:    []string{"group1", "group2"},
+				ImpersonateUserExtra: map[string][]string{"Scopes": {"view"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := getImpersonation(restful.NewRequest(&http.Request{Header: c.header}))
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getImpersonation(%v) == %#v, expected %#v", c.header, actual, c.expected)
+		}
+	}
+}
+
+func TestToImpersonationAttributes(t *testing.T) {
+	cases := []struct {
+		impersonation *api.AuthInfo
+		expected      []*v1.ResourceAttributes
+	}{
+		{
+			&api.AuthInfo{Impersonate: "user1", ImpersonateGroups: []string{"group1"}},
+			[]*v1.ResourceAttributes{
+				{Verb: "impersonate", Resource: "users", Name: "user1"},
+				{Verb: "impersonate", Resource: "groups", Name: "group1"},
+			},
+		},
+		{
+			&api.AuthInfo{
+				Impersonate:          "system:serviceaccount:ns-1:sa-1",
+				ImpersonateUserExtra: map[string][]string{"scopes": {"view"}},
+			},
+			[]*v1.ResourceAttributes{
+				{Verb: "impersonate", Resource: "serviceaccounts", Namespace: "ns-1", Name: "sa-1"},
+				{Verb: "impersonate", Group: "authentication.k8s.io", Resource: "userextras", Subresource: "scopes",
+					Name: "view"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toImpersonationAttributes(c.impersonation)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toImpersonationAttributes(%#v) == %#v, expected %#v", c.impersonation, actual, c.expected)
+		}
+	}
+}
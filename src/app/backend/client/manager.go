@@ -17,18 +17,22 @@ package client
 import (
 	"context"
 	"log"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/emicklei/go-restful"
 	v1 "k8s.io/api/authorization/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	pluginclientset "github.com/kubernetes/dashboard/src/app/backend/plugin/client/clientset/versioned"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/compat"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/customresourcedefinition"
 
 	"github.com/kubernetes/dashboard/src/app/backend/args"
@@ -36,6 +40,7 @@ import (
 	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
 	"github.com/kubernetes/dashboard/src/app/backend/client/csrf"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
 )
 
 // Dashboard UI default values for client configs.
@@ -56,6 +61,10 @@ const (
 	DefaultUserAgent = "dashboard"
 	//Impersonation Extra header
 	ImpersonateUserExtraHeader = "Impersonate-Extra-"
+	// Impersonation user header
+	ImpersonateUserHeader = "Impersonate-User"
+	// Impersonation group header
+	ImpersonateGroupHeader = "Impersonate-Group"
 )
 
 // VERSION of this binary
@@ -75,12 +84,19 @@ type clientManager struct {
 	inClusterConfig *rest.Config
 	// Responsible for decrypting tokens coming in request header. Used for authentication.
 	tokenManager authApi.TokenManager
+	// Used to check whether SkipLogin feature gate allows to use privileges of Dashboard SA.
+	fManager featuresApi.FeatureGateManager
+	// Used to record usage of privileges of Dashboard SA by users who have skipped the login.
+	auditLogger authApi.AuditLogger
 	// API Extensions client created without providing auth info. It uses permissions granted to
 	// service account used by dashboard or kubeconfig file if it was passed during dashboard init.
 	insecureAPIExtensionsClient apiextensionsclientset.Interface
 	// Plugin client created without providing auth info. It uses permissions granted to
 	// service account used by dashboard or kubeconfig file if it was passed during dashboard init.
 	insecurePluginClient pluginclientset.Interface
+	// Dynamic client created without providing auth info. It uses permissions granted to service
+	// account used by dashboard or kubeconfig file if it was passed during dashboard init.
+	insecureDynamicClient dynamic.Interface
 	// Kubernetes client created without providing auth info. It uses permissions granted to
 	// service account used by dashboard or kubeconfig file if it was passed during dashboard init.
 	insecureClient kubernetes.Interface
@@ -88,11 +104,19 @@ type clientManager struct {
 	// to service account used by dashboard or kubeconfig file if it was passed during dashboard
 	// init.
 	insecureConfig *rest.Config
+	// Clients and config used by users who have skipped the login. They impersonate service account set by
+	// 'skip-auth-service-account' argument or are the same as insecure ones if it is not set.
+	skipLoginAPIExtensionsClient apiextensionsclientset.Interface
+	skipLoginPluginClient        pluginclientset.Interface
+	skipLoginConfig              *rest.Config
+	// Trusts users set in request headers by an authenticating proxy. Nil if 'header' authentication mode is disabled.
+	headerAuthenticator *headerAuthenticator
 }
 
 // Client returns a kubernetes client. In case dashboard login is enabled and option to skip
 // login page is disabled only secure client will be returned, otherwise insecure client will be
-// used.
+// used. If 'list-memory-budget' is set, every returned client has its own memory budget. Calls made by
+// the client are cancelled once the request is done.
 func (self *clientManager) Client(req *restful.Request) (kubernetes.Interface, error) {
 	if req == nil {
 		return nil, errors.NewBadRequest("request can not be nil")
@@ -102,7 +126,9 @@ func (self *clientManager) Client(req *restful.Request) (kubernetes.Interface, e
 		return self.secureClient(req)
 	}
 
-	return self.InsecureClient(), nil
+	self.auditSkippedLogin(req)
+	cfg := withRequestContext(self.skipLoginConfig, req.Request)
+	return kubernetes.NewForConfig(withMemoryBudget(cfg, args.Holder.GetListMemoryBudget()))
 }
 
 // APIExtensionsClient returns an API Extensions client. In case dashboard login is enabled and
@@ -117,7 +143,8 @@ func (self *clientManager) APIExtensionsClient(req *restful.Request) (apiextensi
 		return self.secureAPIExtensionsClient(req)
 	}
 
-	return self.InsecureAPIExtensionsClient(), nil
+	self.auditSkippedLogin(req)
+	return self.skipLoginAPIExtensionsClient, nil
 }
 
 // PluginClient returns a plugin client. In case dashboard login is enabled and
@@ -132,12 +159,13 @@ func (self *clientManager) PluginClient(req *restful.Request) (pluginclientset.I
 		return self.securePluginClient(req)
 	}
 
-	return self.InsecurePluginClient(), nil
+	self.auditSkippedLogin(req)
+	return self.skipLoginPluginClient, nil
 }
 
 // Config returns a rest config. In case dashboard login is enabled and option to skip
 // login page is disabled only secure config will be returned, otherwise insecure config will be
-// used.
+// used. Clients created from the config cancel their calls once the request is done.
 func (self *clientManager) Config(req *restful.Request) (*rest.Config, error) {
 	if req == nil {
 		return nil, errors.NewBadRequest("request can not be nil")
@@ -147,7 +175,8 @@ func (self *clientManager) Config(req *restful.Request) (*rest.Config, error) {
 		return self.secureConfig(req)
 	}
 
-	return self.InsecureConfig(), nil
+	self.auditSkippedLogin(req)
+	return withRequestContext(self.skipLoginConfig, req.Request), nil
 }
 
 // InsecureClient returns kubernetes client that was created without providing auth info. It uses
@@ -171,12 +200,35 @@ func (self *clientManager) InsecurePluginClient() pluginclientset.Interface {
 	return self.insecurePluginClient
 }
 
+// InsecureDynamicClient returns dynamic client that was created without providing auth info. It uses
+// permissions granted to service account used by dashboard or kubeconfig file if it was passed during
+// dashboard init.
+func (self *clientManager) InsecureDynamicClient() dynamic.Interface {
+	return self.insecureDynamicClient
+}
+
 // InsecureConfig returns kubernetes client config that used privileges of dashboard service account
 // or kubeconfig file if it was passed during dashboard init.
 func (self *clientManager) InsecureConfig() *rest.Config {
 	return self.insecureConfig
 }
 
+// SkipLoginIdentity returns identity used by users who have skipped the login. It is either the impersonated service
+// account or identity of Dashboard itself.
+func (self *clientManager) SkipLoginIdentity() string {
+	return clientapi.GetIdentity(self.skipLoginConfig)
+}
+
+// HeaderAuthInfo returns auth info of the user set in request headers by a trusted authenticating proxy or nil if the
+// request does not come from one.
+func (self *clientManager) HeaderAuthInfo(req *restful.Request) *api.AuthInfo {
+	if self.headerAuthenticator == nil || req == nil {
+		return nil
+	}
+
+	return self.headerAuthenticator.authInfo(req.Request)
+}
+
 // CanI returns true when user is allowed to access data provided within SelfSubjectAccessReview, false otherwise.
 func (self *clientManager) CanI(req *restful.Request, ssar *v1.SelfSubjectAccessReview) bool {
 	// In case user is not authenticated (uses skip option) do not allow access.
@@ -268,10 +320,10 @@ func (self *clientManager) VerberClient(req *restful.Request, config *rest.Confi
 
 	return NewResourceVerber(
 		k8sClient.CoreV1().RESTClient(),
-		k8sClient.ExtensionsV1beta1().RESTClient(),
+		compat.IngressRESTClient(k8sClient),
 		k8sClient.AppsV1().RESTClient(),
 		k8sClient.BatchV1().RESTClient(),
-		k8sClient.BatchV1beta1().RESTClient(),
+		compat.CronJobRESTClient(k8sClient),
 		k8sClient.AutoscalingV1().RESTClient(),
 		k8sClient.StorageV1().RESTClient(),
 		k8sClient.RbacV1().RESTClient(),
@@ -285,6 +337,25 @@ func (self *clientManager) SetTokenManager(manager authApi.TokenManager) {
 	self.tokenManager = manager
 }
 
+// SetFeatureGateManager sets the feature gate manager that will be used to check whether login can be skipped.
+func (self *clientManager) SetFeatureGateManager(manager featuresApi.FeatureGateManager) {
+	self.fManager = manager
+}
+
+// SetAuditLogger sets the audit logger that will be used to record usage of privileges of Dashboard SA by users who
+// have skipped the login.
+func (self *clientManager) SetAuditLogger(logger authApi.AuditLogger) {
+	self.auditLogger = logger
+}
+
+// Records that privileges of Dashboard SA are used by a user who has skipped the login. Audit logger limits how
+// often it is recorded for the same user.
+func (self *clientManager) auditSkippedLogin(req *restful.Request) {
+	if self.auditLogger != nil && self.isLoginEnabled(req) && self.isSkipLoginEnabled() && !self.containsAuthInfo(req) {
+		self.auditLogger.Log(authApi.NewAuditRecord(authApi.AuditLoginSkipped, "anonymous", req.Request))
+	}
+}
+
 // Initializes config with default values
 func (self *clientManager) initConfig(cfg *rest.Config) {
 	cfg.QPS = DefaultQPS
@@ -319,7 +390,7 @@ func (self *clientManager) buildCmdConfig(authInfo *api.AuthInfo, cfg *rest.Conf
 		CertificateAuthorityData: cfg.TLSClientConfig.CAData,
 		InsecureSkipTLSVerify:    cfg.TLSClientConfig.Insecure,
 	}
-	cmdCfg.AuthInfos[DefaultCmdConfigName] = authInfo
+	cmdCfg.AuthInfos[DefaultCmdConfigName] = withOwnCredentials(authInfo, cfg)
 	cmdCfg.Contexts[DefaultCmdConfigName] = &api.Context{
 		Cluster:  DefaultCmdConfigName,
 		AuthInfo: DefaultCmdConfigName,
@@ -332,55 +403,118 @@ func (self *clientManager) buildCmdConfig(authInfo *api.AuthInfo, cfg *rest.Conf
 	)
 }
 
+// Auth info that only impersonates, i.e. created by the ldap authentication mode, carries no credentials. Requests
+// made with it use credentials of Dashboard, which has to be allowed to impersonate users and groups.
+func withOwnCredentials(authInfo *api.AuthInfo, cfg *rest.Config) *api.AuthInfo {
+	if len(authInfo.Impersonate) == 0 || hasCredentials(authInfo) {
+		return authInfo
+	}
+
+	result := authInfo.DeepCopy()
+	result.Token = cfg.BearerToken
+	result.TokenFile = cfg.BearerTokenFile
+	result.ClientCertificate = cfg.TLSClientConfig.CertFile
+	result.ClientCertificateData = cfg.TLSClientConfig.CertData
+	result.ClientKey = cfg.TLSClientConfig.KeyFile
+	result.ClientKeyData = cfg.TLSClientConfig.KeyData
+	result.Username = cfg.Username
+	result.Password = cfg.Password
+	return result
+}
+
+func hasCredentials(authInfo *api.AuthInfo) bool {
+	return len(authInfo.Token) > 0 || len(authInfo.TokenFile) > 0 || len(authInfo.Username) > 0 ||
+		len(authInfo.ClientCertificate) > 0 || len(authInfo.ClientCertificateData) > 0 ||
+		authInfo.AuthProvider != nil || authInfo.Exec != nil
+}
+
 // Extracts authorization information from the request header
 func (self *clientManager) extractAuthInfo(req *restful.Request) (*api.AuthInfo, error) {
+	// Users authenticated by the proxy are impersonated with credentials of Dashboard, other headers are ignored.
+	if authInfo := self.HeaderAuthInfo(req); authInfo != nil {
+		return authInfo, nil
+	}
+
 	authHeader := req.HeaderParameter("Authorization")
-	impersonationHeader := req.HeaderParameter("Impersonate-User")
 	jweToken := req.HeaderParameter(JWETokenHeader)
 
 	// Authorization header will be more important than our token
 	token := self.extractTokenFromHeader(authHeader)
 	if len(token) > 0 {
-
-		authInfo := &api.AuthInfo{Token: token}
-
-		if len(impersonationHeader) > 0 {
-			//there's an impersonation header, lets make sure to add it
-			authInfo.Impersonate = impersonationHeader
-
-			//Check for impersonated groups
-			if groupsImpersonationHeader := req.Request.Header["Impersonate-Group"]; len(groupsImpersonationHeader) > 0 {
-				authInfo.ImpersonateGroups = groupsImpersonationHeader
-			}
-
-			//check for extra fields
-			for headerName, headerValues := range req.Request.Header {
-				if strings.HasPrefix(headerName, ImpersonateUserExtraHeader) {
-					extraName := headerName[len(ImpersonateUserExtraHeader):]
-					if authInfo.ImpersonateUserExtra == nil {
-						authInfo.ImpersonateUserExtra = make(map[string][]string)
-					}
-					authInfo.ImpersonateUserExtra[extraName] = headerValues
-				}
-			}
+		// Requests are sent with the token from the header, so API server itself checks whether its owner can
+		// impersonate.
+		authInfo := getImpersonation(req)
+		if authInfo == nil {
+			authInfo = &api.AuthInfo{}
 		}
 
+		authInfo.Token = token
 		return authInfo, nil
 	}
 
 	if self.tokenManager != nil && len(jweToken) > 0 {
-		return self.tokenManager.Decrypt(jweToken)
+		authInfo, err := self.decryptJWEToken(req, jweToken)
+		if err != nil {
+			return nil, err
+		}
+
+		return self.withImpersonation(req, authInfo)
+	}
+
+	if embedToken := req.QueryParameter(authApi.EmbedTokenParameter); self.tokenManager != nil && len(embedToken) > 0 {
+		return self.extractEmbedAuthInfo(req, embedToken)
 	}
 
 	return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
 }
 
+// Decrypts JWE token. Elevated sessions fall back to their own credentials once the elevation expires, but mutating
+// requests are rejected then, so changes are never made with other credentials than the user expects.
+func (self *clientManager) decryptJWEToken(req *restful.Request, jweToken string) (*api.AuthInfo, error) {
+	elevationTokenManager, ok := self.tokenManager.(authApi.ElevationTokenManager)
+	if !ok {
+		return self.tokenManager.Decrypt(jweToken)
+	}
+
+	info, err := elevationTokenManager.DecryptElevated(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if info.IsElevationExpired(now) && req.Request.Method != http.MethodGet {
+		return nil, errors.NewGenericResponse(http.StatusForbidden, authApi.MsgElevationExpired)
+	}
+
+	return info.Current(now), nil
+}
+
+// Decrypts embed token and returns auth information saved in it only if the request is allowed by the token scope.
+func (self *clientManager) extractEmbedAuthInfo(req *restful.Request, embedToken string) (*api.AuthInfo, error) {
+	embedTokenManager, ok := self.tokenManager.(authApi.EmbedTokenManager)
+	if !ok {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	authInfo, scope, err := embedTokenManager.DecryptEmbed(embedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !scope.Allows(req.Request.Method, req.Request.URL.Path) {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return authInfo, nil
+}
+
 // Checks if request headers contain any auth information without parsing.
 func (self *clientManager) containsAuthInfo(req *restful.Request) bool {
 	authHeader := req.HeaderParameter("Authorization")
 	jweToken := req.HeaderParameter(JWETokenHeader)
+	embedToken := req.QueryParameter(authApi.EmbedTokenParameter)
 
-	return len(authHeader) > 0 || len(jweToken) > 0
+	return len(authHeader) > 0 || len(jweToken) > 0 || len(embedToken) > 0 || self.HeaderAuthInfo(req) != nil
 }
 
 func (self *clientManager) extractTokenFromHeader(authHeader string) string {
@@ -398,11 +532,20 @@ func (self *clientManager) isLoginEnabled(req *restful.Request) bool {
 // Secure mode means that every request to Dashboard has to be authenticated and privileges
 // of Dashboard SA can not be used.
 func (self *clientManager) isSecureModeEnabled(req *restful.Request) bool {
-	if self.isLoginEnabled(req) && !args.Holder.GetEnableSkipLogin() {
+	if self.isLoginEnabled(req) && !self.isSkipLoginEnabled() {
 		return true
 	}
 
-	return self.isLoginEnabled(req) && args.Holder.GetEnableSkipLogin() && self.containsAuthInfo(req)
+	return self.isLoginEnabled(req) && self.isSkipLoginEnabled() && self.containsAuthInfo(req)
+}
+
+// Login can be skipped only when it is enabled by the argument and not disabled by the SkipLogin feature gate.
+func (self *clientManager) isSkipLoginEnabled() bool {
+	if !args.Holder.GetEnableSkipLogin() {
+		return false
+	}
+
+	return self.fManager == nil || self.fManager.Enabled(featuresApi.SkipLogin)
 }
 
 func (self *clientManager) secureClient(req *restful.Request) (kubernetes.Interface, error) {
@@ -411,7 +554,7 @@ func (self *clientManager) secureClient(req *restful.Request) (kubernetes.Interf
 		return nil, err
 	}
 
-	client, err := kubernetes.NewForConfig(cfg)
+	client, err := kubernetes.NewForConfig(withMemoryBudget(cfg, args.Holder.GetListMemoryBudget()))
 	if err != nil {
 		return nil, err
 	}
@@ -459,16 +602,35 @@ func (self *clientManager) secureConfig(req *restful.Request) (*rest.Config, err
 	}
 
 	self.initConfig(cfg)
-	return cfg, nil
+	return withRequestContext(cfg, req.Request), nil
 }
 
 // Initializes client manager
 func (self *clientManager) init() {
 	self.initInClusterConfig()
 	self.initInsecureClients()
+	self.initSkipLoginClients()
+	self.initHeaderAuthenticator()
 	self.initCSRFKey()
 }
 
+// Initializes authenticator of users set in request headers if 'header' authentication mode is enabled.
+func (self *clientManager) initHeaderAuthenticator() {
+	if !authApi.ToAuthenticationModes(args.Holder.GetAuthenticationMode()).IsEnabled(authApi.Header) {
+		return
+	}
+
+	authenticator, err := newHeaderAuthenticator(args.Holder.GetAuthProxyUserHeader(),
+		args.Holder.GetAuthProxyGroupHeader(), args.Holder.GetAuthProxyTrustedCIDRs(),
+		args.Holder.GetAuthProxyClientCAFile(), args.Holder.GetAuthProxyAllowedNames())
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("Trusting users set in %s header by authenticating proxies", args.Holder.GetAuthProxyUserHeader())
+	self.headerAuthenticator = authenticator
+}
+
 // Initializes in-cluster config if apiserverHost and kubeConfigPath were not provided.
 func (self *clientManager) initInClusterConfig() {
 	if len(self.apiserverHost) > 0 || len(self.kubeConfigPath) > 0 {
@@ -519,9 +681,15 @@ func (self *clientManager) initInsecureClients() {
 		panic(err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(self.insecureConfig)
+	if err != nil {
+		panic(err)
+	}
+
 	self.insecureClient = k8sClient
 	self.insecureAPIExtensionsClient = apiextensionsclient
 	self.insecurePluginClient = pluginclient
+	self.insecureDynamicClient = dynamicClient
 }
 
 func (self *clientManager) initInsecureConfig() {
@@ -534,6 +702,40 @@ func (self *clientManager) initInsecureConfig() {
 	self.insecureConfig = cfg
 }
 
+// Initializes clients used by users who have skipped the login. If 'skip-auth-service-account' argument is set, they
+// impersonate given service account from Dashboard namespace instead of using privileges of Dashboard SA.
+func (self *clientManager) initSkipLoginClients() {
+	self.skipLoginAPIExtensionsClient = self.insecureAPIExtensionsClient
+	self.skipLoginPluginClient = self.insecurePluginClient
+	self.skipLoginConfig = self.insecureConfig
+
+	name := args.Holder.GetSkipAuthServiceAccount()
+	if len(name) == 0 {
+		return
+	}
+
+	cfg := rest.CopyConfig(self.insecureConfig)
+	// API server adds service account groups itself, so only impersonating the service account has to be allowed.
+	cfg.Impersonate = rest.ImpersonationConfig{
+		UserName: serviceAccountUserPrefix + args.Holder.GetNamespace() + ":" + name,
+	}
+
+	apiextensionsclient, err := apiextensionsclientset.NewForConfig(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	pluginclient, err := pluginclientset.NewForConfig(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("Users who skip the login will impersonate %s", cfg.Impersonate.UserName)
+	self.skipLoginAPIExtensionsClient = apiextensionsclient
+	self.skipLoginPluginClient = pluginclient
+	self.skipLoginConfig = cfg
+}
+
 // Returns true if in-cluster config is used
 func (self *clientManager) isRunningInCluster() bool {
 	return self.inClusterConfig != nil
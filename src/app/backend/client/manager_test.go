@@ -17,12 +17,17 @@ package client
 import (
 	"crypto/tls"
 	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
+	"time"
 
 	restful "github.com/emicklei/go-restful"
 	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 func TestNewClientManager(t *testing.T) {
@@ -310,6 +315,34 @@ func TestClientManager_InsecureAPIExtensionsClient(t *testing.T) {
 	}
 }
 
+func TestClientManager_SkipLoginServiceAccount(t *testing.T) {
+	args.GetHolderBuilder().SetEnableSkipLogin(true)
+	args.GetHolderBuilder().SetNamespace("kubernetes-dashboard")
+	args.GetHolderBuilder().SetSkipAuthServiceAccount("dashboard-viewer")
+	defer args.GetHolderBuilder().SetSkipAuthServiceAccount("")
+
+	manager := NewClientManager("", "http://localhost:8080")
+	req := &restful.Request{Request: &http.Request{Header: http.Header{}, TLS: &tls.ConnectionState{}}}
+	cfg, err := manager.Config(req)
+	if err != nil {
+		t.Fatalf("Config(): Expected config to be created but error was thrown: %s", err.Error())
+	}
+
+	expected := "system:serviceaccount:kubernetes-dashboard:dashboard-viewer"
+	if cfg.Impersonate.UserName != expected {
+		t.Errorf("Config(): Expected skipped login to impersonate %s, but got %s", expected,
+			cfg.Impersonate.UserName)
+	}
+
+	if identity := manager.SkipLoginIdentity(); identity != "anonymous as "+expected {
+		t.Errorf("SkipLoginIdentity(): Expected identity of %s, but got %s", expected, identity)
+	}
+
+	if insecureConfig := manager.(*clientManager).insecureConfig; len(insecureConfig.Impersonate.UserName) > 0 {
+		t.Errorf("Expected Dashboard itself not to impersonate anyone, but got %s", insecureConfig.Impersonate.UserName)
+	}
+}
+
 func TestImpersonationUserClient(t *testing.T) {
 	args.GetHolderBuilder().SetEnableSkipLogin(true)
 	cases := []struct {
@@ -591,3 +624,123 @@ func TestImpersonationExtrasClient(t *testing.T) {
 		}
 	}
 }
+
+func TestWithOwnCredentials(t *testing.T) {
+	cfg := &rest.Config{BearerToken: "dashboard-token", BearerTokenFile: "/var/run/token"}
+	cases := []struct {
+		info     *api.AuthInfo
+		expected *api.AuthInfo
+	}{
+		{
+			&api.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{"admins"}},
+			&api.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{"admins"}, Token: "dashboard-token",
+				TokenFile: "/var/run/token"},
+		},
+		{
+			&api.AuthInfo{Impersonate: "jane", Token: "user-token"},
+			&api.AuthInfo{Impersonate: "jane", Token: "user-token"},
+		},
+		{
+			&api.AuthInfo{},
+			&api.AuthInfo{},
+		},
+	}
+
+	for _, c := range cases {
+		actual := withOwnCredentials(c.info, cfg)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("withOwnCredentials(%#v) == %#v, expected %#v", c.info, actual, c.expected)
+		}
+	}
+}
+
+type fakeEmbedTokenManager struct{}
+
+func (fakeEmbedTokenManager) Generate(api.AuthInfo) (string, error) { return "", nil }
+func (fakeEmbedTokenManager) Decrypt(string) (*api.AuthInfo, error) { return nil, nil }
+func (fakeEmbedTokenManager) Refresh(string) (string, error)        { return "", nil }
+func (fakeEmbedTokenManager) SetTokenTTL(time.Duration)             {}
+func (fakeEmbedTokenManager) SetTokenMaxLifetime(time.Duration)     {}
+func (fakeEmbedTokenManager) Revoke(string) error                   { return nil }
+func (fakeEmbedTokenManager) GenerateEmbed(api.AuthInfo, authApi.EmbedScope, time.Duration) (string, error) {
+	return "", nil
+}
+
+func (fakeEmbedTokenManager) DecryptEmbed(token string) (*api.AuthInfo, *authApi.EmbedScope, error) {
+	if token != "embed-token" {
+		return nil, nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return &api.AuthInfo{Token: "user-token"}, &authApi.EmbedScope{Kind: "pod", Namespace: "default"}, nil
+}
+
+func TestEmbedTokenAuthInfo(t *testing.T) {
+	cases := []struct {
+		method   string
+		url      string
+		expected *api.AuthInfo
+	}{
+		{"GET", "/api/v1/pod/default/test-pod?embedToken=embed-token", &api.AuthInfo{Token: "user-token"}},
+		{"DELETE", "/api/v1/pod/default/test-pod?embedToken=embed-token", nil},
+		{"GET", "/api/v1/pod/kube-system/test-pod?embedToken=embed-token", nil},
+		{"GET", "/api/v1/pod/default/test-pod?embedToken=other-token", nil},
+	}
+
+	manager := &clientManager{tokenManager: fakeEmbedTokenManager{}}
+	for _, c := range cases {
+		u, _ := url.Parse(c.url)
+		req := restful.NewRequest(&http.Request{Method: c.method, URL: u, Header: http.Header{}})
+
+		if !manager.containsAuthInfo(req) {
+			t.Errorf("containsAuthInfo(%s %s) == false, expected true", c.method, c.url)
+		}
+
+		actual, err := manager.extractAuthInfo(req)
+		if !reflect.DeepEqual(actual, c.expected) || (c.expected == nil && err == nil) {
+			t.Errorf("extractAuthInfo(%s %s) == %#v, %v, expected %#v", c.method, c.url, actual, err, c.expected)
+		}
+	}
+}
+
+type fakeElevationTokenManager struct {
+	fakeEmbedTokenManager
+	elevatedUntil time.Time
+}
+
+func (fakeElevationTokenManager) Elevate(string, api.AuthInfo, time.Time) (string, error) {
+	return "", nil
+}
+
+func (self fakeElevationTokenManager) DecryptElevated(string) (*authApi.ElevatedAuthInfo, error) {
+	return &authApi.ElevatedAuthInfo{
+		AuthInfo:      api.AuthInfo{Token: "viewer-token"},
+		Elevated:      &api.AuthInfo{Token: "admin-token"},
+		ElevatedUntil: self.elevatedUntil,
+	}, nil
+}
+
+func TestElevatedAuthInfo(t *testing.T) {
+	cases := []struct {
+		info          string
+		method        string
+		elevatedUntil time.Time
+		expected      *api.AuthInfo
+	}{
+		{"elevated read", "GET", time.Now().Add(time.Minute), &api.AuthInfo{Token: "admin-token"}},
+		{"elevated change", "DELETE", time.Now().Add(time.Minute), &api.AuthInfo{Token: "admin-token"}},
+		{"read after elevation", "GET", time.Now().Add(-time.Minute), &api.AuthInfo{Token: "viewer-token"}},
+		{"change after elevation", "DELETE", time.Now().Add(-time.Minute), nil},
+	}
+
+	for _, c := range cases {
+		manager := &clientManager{tokenManager: fakeElevationTokenManager{elevatedUntil: c.elevatedUntil}}
+		req := restful.NewRequest(&http.Request{Method: c.method, URL: &url.URL{Path: "/api/v1/pod/default/test-pod"},
+			Header: http.Header{}})
+		req.Request.Header.Set(JWETokenHeader, "jwe-token")
+
+		actual, err := manager.extractAuthInfo(req)
+		if !reflect.DeepEqual(actual, c.expected) || (c.expected == nil && err == nil) {
+			t.Errorf("%s: expected auth info %#v, but got %#v, %v", c.info, c.expected, actual, err)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	restclient "k8s.io/client-go/rest"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
@@ -161,6 +162,7 @@ type RESTClient interface {
 	Delete() *restclient.Request
 	Put() *restclient.Request
 	Get() *restclient.Request
+	Patch(pt types.PatchType) *restclient.Request
 }
 
 // NewResourceVerber creates a new resource verber that uses the given client for performing operations.
@@ -213,6 +215,54 @@ func (verber *resourceVerber) Put(kind string, namespaceSet bool, namespace stri
 	return req.Do(context.TODO()).Error()
 }
 
+// Patch applies given patch to the resource of the given kind in the given namespace with the given name.
+func (verber *resourceVerber) Patch(kind string, namespaceSet bool, namespace string, name string,
+	patchType types.PatchType, data []byte) error {
+
+	client, resourceSpec, err := verber.getResourceSpecFromKind(kind, namespaceSet)
+	if err != nil {
+		return err
+	}
+
+	req := client.Patch(patchType).
+		Resource(resourceSpec.Resource).
+		Name(name).
+		Body(data)
+
+	if resourceSpec.Namespaced {
+		req.Namespace(namespace)
+	}
+
+	return req.Do(context.TODO()).Error()
+}
+
+// Apply applies given configuration to the resource of the given kind in the given namespace with the given name
+// using server-side apply. Conflicts with fields owned by other managers are overridden only if force is set.
+func (verber *resourceVerber) Apply(kind string, namespaceSet bool, namespace string, name string, data []byte,
+	fieldManager string, force bool) error {
+
+	client, resourceSpec, err := verber.getResourceSpecFromKind(kind, namespaceSet)
+	if err != nil {
+		return err
+	}
+
+	req := client.Patch(types.ApplyPatchType).
+		Resource(resourceSpec.Resource).
+		Name(name).
+		Param("fieldManager", fieldManager).
+		Body(data)
+
+	if force {
+		req.Param("force", "true")
+	}
+
+	if resourceSpec.Namespaced {
+		req.Namespace(namespace)
+	}
+
+	return req.Do(context.TODO()).Error()
+}
+
 // Get gets the resource of the given kind in the given namespace with the given name.
 func (verber *resourceVerber) Get(kind string, namespaceSet bool, namespace string, name string) (runtime.Object, error) {
 	client, resourceSpec, err := verber.getResourceSpecFromKind(kind, namespaceSet)
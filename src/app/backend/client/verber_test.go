@@ -24,6 +24,7 @@ import (
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/rest/fake"
@@ -72,6 +73,10 @@ func (c *FakeRESTClient) Get() *restclient.Request {
 	return restclient.NewRequestWithClient(&url.URL{Path: "/api/v1/"}, "", restclient.ClientContentConfig{}, fake.CreateHTTPClient(NewFakeClientFunc(c))).Verb("GET")
 }
 
+func (c *FakeRESTClient) Patch(pt types.PatchType) *restclient.Request {
+	return restclient.NewRequestWithClient(&url.URL{Path: "/api/v1/"}, "", restclient.ClientContentConfig{}, fake.CreateHTTPClient(NewFakeClientFunc(c))).Verb("PATCH")
+}
+
 // Removes all quote signs that might have been added to the message.
 // Might depend on dependencies version how they are constructed.
 func normalize(msg string) string {
@@ -104,6 +109,27 @@ func TestDeleteShouldPropagateErrorsAndChooseClient(t *testing.T) {
 	}
 }
 
+func TestApplyShouldPropagateErrorsAndSetParams(t *testing.T) {
+	verber := resourceVerber{
+		client:     &FakeRESTClient{err: errors.NewInvalid("err")},
+		appsClient: &FakeRESTClient{err: errors.NewInvalid("err from apps")},
+	}
+
+	err := verber.Apply("deployment", true, "bar", "baz", nil, "dashboard", false)
+
+	expected := "Patch /api/v1/namespaces/bar/deployments/baz?fieldManager=dashboard: err from apps"
+	if !reflect.DeepEqual(normalize(err.Error()), expected) {
+		t.Fatalf("Expected error on verber apply but got %#v", err.Error())
+	}
+
+	err = verber.Apply("service", true, "bar", "baz", nil, "dashboard", true)
+
+	expected = "Patch /api/v1/namespaces/bar/services/baz?fieldManager=dashboard&force=true: err"
+	if !reflect.DeepEqual(normalize(err.Error()), expected) {
+		t.Fatalf("Expected error on verber apply but got %#v", err.Error())
+	}
+}
+
 func TestGetShouldPropagateErrorsAndChoseClient(t *testing.T) {
 	verber := resourceVerber{
 		client:           &FakeRESTClient{err: errors.NewInvalid("err")},
@@ -0,0 +1,120 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientstate
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Handler manages endpoints related to encrypted client state.
+type Handler struct {
+	cManager  clientapi.ClientManager
+	encrypter authApi.StateTokenManager
+}
+
+// Install creates new endpoints for client state. State is tied to the identity of the user, so it roams across
+// browsers, and it is encrypted with keys of the token manager, so it is not readable from the cluster.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/clientstate").
+			To(h.handleGetClientState).
+			Writes(ClientState{}))
+	ws.Route(
+		ws.PUT("/clientstate").
+			To(h.handleSaveClientState).
+			Reads(ClientState{}))
+	ws.Route(
+		ws.DELETE("/clientstate").
+			To(h.handleDeleteClientState))
+}
+
+// NewClientStateHandler creates clientstate.Handler.
+func NewClientStateHandler(cManager clientapi.ClientManager, encrypter authApi.StateTokenManager) *Handler {
+	return &Handler{cManager: cManager, encrypter: encrypter}
+}
+
+func (h *Handler) handleGetClientState(request *restful.Request, response *restful.Response) {
+	owner, err := GetOwner(h.cManager, request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := GetClientState(h.cManager.InsecureClient(), h.encrypter, owner)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (h *Handler) handleSaveClientState(request *restful.Request, response *restful.Response) {
+	owner, err := GetOwner(h.cManager, request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	state := new(ClientState)
+	if err := request.ReadEntity(state); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if err := SaveClientState(h.cManager.InsecureClient(), h.encrypter, owner, state); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleDeleteClientState(request *restful.Request, response *restful.Response) {
+	owner, err := GetOwner(h.cManager, request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if err := DeleteClientState(h.cManager.InsecureClient(), owner); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
+
+// GetOwner returns identity of the logged in user, that owns state saved by given request. Users who have skipped
+// the login share the same identity, so they can not have their own state.
+func GetOwner(cManager clientapi.ClientManager, request *restful.Request) (string, error) {
+	cfg, err := cManager.Config(request)
+	if err != nil {
+		return "", err
+	}
+
+	owner := clientapi.GetIdentity(cfg)
+	if owner == cManager.SkipLoginIdentity() {
+		return "", errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return owner, nil
+}
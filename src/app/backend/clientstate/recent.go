@@ -0,0 +1,107 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// MaxRecentNamespaces is the number of recently used namespaces remembered for every user.
+const MaxRecentNamespaces = 10
+
+// GetRecentNamespaces returns namespaces recently used by given owner, the most recent first. Unlike client state,
+// they are interpreted by the backend, so that users can land in the last used namespace after login.
+func GetRecentNamespaces(client kubernetes.Interface, encrypter authApi.StateTokenManager,
+	owner string) ([]string, error) {
+	data, err := readEntry(client, encrypter, owner, getRecentNamespacesKey(owner))
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalRecentNamespaces(data), nil
+}
+
+// AddRecentNamespace moves given namespace to the front of namespaces recently used by given owner and forgets
+// the oldest ones above MaxRecentNamespaces.
+func AddRecentNamespace(client kubernetes.Interface, encrypter authApi.StateTokenManager, owner,
+	namespace string) ([]string, error) {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid namespace %s: %s", namespace, strings.Join(errs, ", ")))
+	}
+
+	var recent []string
+	var encryptErr error
+	err := updateStates(client, func(data map[string][]byte) {
+		key := getRecentNamespacesKey(owner)
+		var previous []string
+		if encrypted, ok := data[key]; ok {
+			if decrypted, err := encrypter.DecryptState(owner, string(encrypted)); err == nil {
+				previous = unmarshalRecentNamespaces(decrypted)
+			}
+		}
+
+		recent = addRecentNamespace(previous, namespace)
+		encoded, _ := json.Marshal(recent)
+		encrypted, err := encrypter.EncryptState(owner, encoded)
+		if err != nil {
+			encryptErr = err
+			return
+		}
+		data[key] = []byte(encrypted)
+	})
+	if encryptErr != nil {
+		return nil, encryptErr
+	}
+
+	return recent, err
+}
+
+func addRecentNamespace(recent []string, namespace string) []string {
+	result := []string{namespace}
+	for _, n := range recent {
+		if n != namespace && len(result) < MaxRecentNamespaces {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// Unmarshals recently used namespaces. Entries that can not be read are reset.
+func unmarshalRecentNamespaces(data []byte) []string {
+	recent := make([]string, 0)
+	if len(data) == 0 {
+		return recent
+	}
+
+	if err := json.Unmarshal(data, &recent); err != nil {
+		log.Printf("Could not read recent namespaces, they will be reset: %s", err)
+		return make([]string, 0)
+	}
+	return recent
+}
+
+// Returns the name of the secret entry recently used namespaces of given owner are saved under.
+func getRecentNamespacesKey(owner string) string {
+	return getStateKey(owner) + ".namespaces"
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientstate
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRecentNamespaces(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	recent, err := GetRecentNamespaces(client, fakeEncrypter{}, "alice")
+	if err != nil || len(recent) > 0 {
+		t.Fatalf("Expected no recent namespaces before any is used, but got %v (error: %v).", recent, err)
+	}
+
+	for _, namespace := range []string{"default", "team-a", "default"} {
+		if _, err = AddRecentNamespace(client, fakeEncrypter{}, "alice", namespace); err != nil {
+			t.Fatalf("Expected no error when adding %s, but got %v.", namespace, err)
+		}
+	}
+
+	recent, _ = GetRecentNamespaces(client, fakeEncrypter{}, "alice")
+	if expected := []string{"default", "team-a"}; !reflect.DeepEqual(recent, expected) {
+		t.Errorf("Expected recent namespaces %v, but got %v.", expected, recent)
+	}
+
+	recent, _ = GetRecentNamespaces(client, fakeEncrypter{}, "bob")
+	if len(recent) > 0 {
+		t.Errorf("Expected no recent namespaces of bob, but got %v.", recent)
+	}
+
+	if err = DeleteClientState(client, "alice"); err != nil {
+		t.Fatalf("Expected no error when deleting state, but got %v.", err)
+	}
+
+	recent, _ = GetRecentNamespaces(client, fakeEncrypter{}, "alice")
+	if len(recent) > 0 {
+		t.Errorf("Expected recent namespaces of alice to be deleted, but got %v.", recent)
+	}
+}
+
+func TestAddRecentNamespaceLimit(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	var recent []string
+	for i := 0; i < MaxRecentNamespaces+5; i++ {
+		recent, _ = AddRecentNamespace(client, fakeEncrypter{}, "alice", fmt.Sprintf("ns-%d", i))
+	}
+
+	if len(recent) != MaxRecentNamespaces || recent[0] != fmt.Sprintf("ns-%d", MaxRecentNamespaces+4) {
+		t.Errorf("Expected %d most recent namespaces, but got %v.", MaxRecentNamespaces, recent)
+	}
+}
+
+func TestAddRecentNamespaceValidation(t *testing.T) {
+	for _, namespace := range []string{"", "Team-A", "a/b"} {
+		_, err := AddRecentNamespace(fake.NewSimpleClientset(), fakeEncrypter{}, "alice", namespace)
+		if !k8serrors.IsBadRequest(err) {
+			t.Errorf("Expected bad request for namespace %q, but got %v.", namespace, err)
+		}
+	}
+}
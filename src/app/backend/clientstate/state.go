@@ -0,0 +1,155 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientstate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// MaxStateSize is the maximum size of client state of a single user in bytes. All states are kept in a single
+	// secret, so they have to stay small.
+	MaxStateSize = 8 * 1024
+
+	// Number of attempts to update the secret when it is changed concurrently by another replica.
+	stateRetries = 3
+)
+
+// ClientState is a blob of frontend state, i.e. recently viewed resources or layout, which roams with the user
+// across browsers. Backend does not interpret its content.
+type ClientState struct {
+	State json.RawMessage `json:"state,omitempty"`
+}
+
+// GetClientState returns client state of given owner. Empty state is returned if owner has not saved any state yet
+// or it can not be decrypted anymore, i.e. because encryption keys have been rotated since.
+func GetClientState(client kubernetes.Interface, encrypter authApi.StateTokenManager,
+	owner string) (*ClientState, error) {
+	state, err := readEntry(client, encrypter, owner, getStateKey(owner))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientState{State: state}, nil
+}
+
+// SaveClientState encrypts and saves client state of given owner, replacing the previous one.
+func SaveClientState(client kubernetes.Interface, encrypter authApi.StateTokenManager, owner string,
+	state *ClientState) error {
+	if len(state.State) > MaxStateSize {
+		return errors.NewBadRequest(fmt.Sprintf("client state can not be larger than %d bytes", MaxStateSize))
+	}
+
+	if len(state.State) > 0 && !json.Valid(state.State) {
+		return errors.NewBadRequest("client state has to be valid JSON")
+	}
+
+	encrypted, err := encrypter.EncryptState(owner, state.State)
+	if err != nil {
+		return err
+	}
+
+	return updateStates(client, func(data map[string][]byte) {
+		data[getStateKey(owner)] = []byte(encrypted)
+	})
+}
+
+// DeleteClientState removes saved client state and recently used namespaces of given owner.
+func DeleteClientState(client kubernetes.Interface, owner string) error {
+	return updateStates(client, func(data map[string][]byte) {
+		delete(data, getStateKey(owner))
+		delete(data, getRecentNamespacesKey(owner))
+	})
+}
+
+// Returns decrypted entry of given owner saved under given key. Nil is returned if there is no such entry or it can
+// not be decrypted anymore.
+func readEntry(client kubernetes.Interface, encrypter authApi.StateTokenManager, owner, key string) ([]byte, error) {
+	secret, err := client.CoreV1().Secrets(args.Holder.GetNamespace()).Get(context.TODO(),
+		authApi.ClientStateHolderName, metaV1.GetOptions{})
+	if errors.IsNotFoundError(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, ok := secret.Data[key]
+	if !ok {
+		return nil, nil
+	}
+
+	state, err := encrypter.DecryptState(owner, string(encrypted))
+	if err != nil {
+		log.Printf("Could not decrypt client state, it will be reset: %s", err)
+		return nil, nil
+	}
+
+	return state, nil
+}
+
+// Applies given change to states saved in the secret, creating it if it does not exist yet. Change is retried if
+// the secret has been modified or created concurrently.
+func updateStates(client kubernetes.Interface, change func(map[string][]byte)) error {
+	secrets := client.CoreV1().Secrets(args.Holder.GetNamespace())
+	var err error
+	for i := 0; i < stateRetries; i++ {
+		var secret *v1.Secret
+		secret, err = secrets.Get(context.TODO(), authApi.ClientStateHolderName, metaV1.GetOptions{})
+		switch {
+		case errors.IsNotFoundError(err):
+			secret = &v1.Secret{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: args.Holder.GetNamespace(),
+					Name:      authApi.ClientStateHolderName,
+				},
+				Data: make(map[string][]byte),
+			}
+			change(secret.Data)
+			_, err = secrets.Create(context.TODO(), secret, metaV1.CreateOptions{})
+		case err == nil:
+			if secret.Data == nil {
+				secret.Data = make(map[string][]byte)
+			}
+			change(secret.Data)
+			_, err = secrets.Update(context.TODO(), secret, metaV1.UpdateOptions{})
+		}
+
+		if !errors.IsConflict(err) && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// Returns the name of the secret entry state of given owner is saved under. Owner is hashed, so the secret does not
+// reveal who has saved their state.
+func getStateKey(owner string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(owner)))
+}
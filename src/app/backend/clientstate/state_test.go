@@ -0,0 +1,92 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientstate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Fake encrypter prefixes state with its owner, so wrong owner can be detected without real keys.
+type fakeEncrypter struct{}
+
+func (fakeEncrypter) EncryptState(owner string, state []byte) (string, error) {
+	return owner + "|" + string(state), nil
+}
+
+func (fakeEncrypter) DecryptState(owner, encrypted string) ([]byte, error) {
+	if !strings.HasPrefix(encrypted, owner+"|") {
+		return nil, errors.NewInvalid("State belongs to another owner.")
+	}
+
+	return []byte(strings.TrimPrefix(encrypted, owner+"|")), nil
+}
+
+func TestClientState(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	state, err := GetClientState(client, fakeEncrypter{}, "alice")
+	if err != nil || len(state.State) > 0 {
+		t.Fatalf("Expected empty state before anything is saved, but got %s (error: %v).", state.State, err)
+	}
+
+	for owner, saved := range map[string]string{"alice": `{"recent":["pod"]}`, "bob": `{"layout":"compact"}`} {
+		if err = SaveClientState(client, fakeEncrypter{}, owner, &ClientState{State: []byte(saved)}); err != nil {
+			t.Fatalf("Expected no error when saving state of %s, but got %v.", owner, err)
+		}
+	}
+
+	state, err = GetClientState(client, fakeEncrypter{}, "alice")
+	if err != nil || string(state.State) != `{"recent":["pod"]}` {
+		t.Errorf("Expected state of alice to be restored, but got %s (error: %v).", state.State, err)
+	}
+
+	if err = DeleteClientState(client, "alice"); err != nil {
+		t.Fatalf("Expected no error when deleting state, but got %v.", err)
+	}
+
+	state, _ = GetClientState(client, fakeEncrypter{}, "alice")
+	if len(state.State) > 0 {
+		t.Errorf("Expected state of alice to be deleted, but got %s.", state.State)
+	}
+
+	state, _ = GetClientState(client, fakeEncrypter{}, "bob")
+	if string(state.State) != `{"layout":"compact"}` {
+		t.Errorf("Expected state of bob to be kept, but got %s.", state.State)
+	}
+}
+
+func TestSaveClientStateValidation(t *testing.T) {
+	cases := []struct {
+		info  string
+		state string
+	}{
+		{"invalid JSON", `{"recent":`},
+		{"too large", fmt.Sprintf(`{"recent":"%s"}`, strings.Repeat("a", MaxStateSize))},
+	}
+
+	for _, c := range cases {
+		err := SaveClientState(fake.NewSimpleClientset(), fakeEncrypter{}, "alice", &ClientState{State: []byte(c.state)})
+		if !k8serrors.IsBadRequest(err) {
+			t.Errorf("%s: Expected bad request error, but got %v.", c.info, err)
+		}
+	}
+}
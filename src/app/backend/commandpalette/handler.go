@@ -0,0 +1,106 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commandpalette
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+	"github.com/kubernetes/dashboard/src/app/backend/tenancy"
+)
+
+// Handler manages the command palette endpoint.
+type Handler struct {
+	cManager clientapi.ClientManager
+	sManager settingsApi.SettingsManager
+	index    *objectIndex
+}
+
+// Install creates new endpoint for the command palette. Given a partial 'query' parameter it returns ranked views,
+// quick actions and objects the user is allowed to see. Objects can be limited to a 'namespace' and the number of
+// commands to 'limit'.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/command-palette").
+			To(h.handleGetCommandList).
+			Writes(CommandList{}))
+}
+
+// NewCommandPaletteHandler creates commandpalette.Handler.
+func NewCommandPaletteHandler(cManager clientapi.ClientManager, sManager settingsApi.SettingsManager) *Handler {
+	return &Handler{cManager: cManager, sManager: sManager, index: newObjectIndex(objectIndexTTL, objectIndexSize)}
+}
+
+func (h *Handler) handleGetCommandList(request *restful.Request, response *restful.Response) {
+	limit := DefaultLimit
+	if value := request.QueryParameter("limit"); len(value) > 0 {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			errors.HandleInternalError(response, errors.NewBadRequest("limit has to be a number"))
+			return
+		}
+		limit = parsed
+	}
+
+	cfg, err := h.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	query := request.QueryParameter("query")
+	var objects []Command
+	if len(query) > 0 {
+		objects = h.index.get(clientapi.GetCredentialsKey(cfg), k8sClient, request.QueryParameter("namespace"),
+			time.Now())
+	}
+
+	if scope := tenancy.GetScope(request); scope != nil {
+		objects = filterVisibleObjects(objects, scope)
+	}
+
+	actions := h.sManager.GetQuickActions(h.cManager.InsecureClient())
+	response.WriteHeaderAndEntity(http.StatusOK, GetCommandList(query, objects, actions, limit))
+}
+
+// filterVisibleObjects returns objects from namespaces visible in given scope. Indexed objects are shared by users
+// with the same credentials, so they can not be limited while indexing.
+func filterVisibleObjects(objects []Command, scope *tenancy.Scope) []Command {
+	result := make([]Command, 0, len(objects))
+	for _, object := range objects {
+		namespace := object.Namespace
+		if object.Kind == api.ResourceKindNamespace {
+			namespace = object.Name
+		}
+
+		if len(namespace) == 0 || scope.Allows(namespace) {
+			result = append(result, object)
+		}
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commandpalette
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/compat"
+)
+
+const (
+	// Objects of a single user are listed again after this time.
+	objectIndexTTL = 30 * time.Second
+	// Maximum number of users whose objects are kept in memory.
+	objectIndexSize = 64
+)
+
+// objectLister lists objects of a single kind the user is allowed to list.
+type objectLister struct {
+	kind string
+	list func(client kubernetes.Interface, namespace string) (runtime.Object, error)
+}
+
+// listers of kinds that can be opened from the command palette.
+var listers = []objectLister{
+	{api.ResourceKindNamespace, func(client kubernetes.Interface, _ string) (runtime.Object, error) {
+		return client.CoreV1().Namespaces().List(context.TODO(), api.ListEverything)
+	}},
+	{api.ResourceKindNode, func(client kubernetes.Interface, _ string) (runtime.Object, error) {
+		return client.CoreV1().Nodes().List(context.TODO(), api.ListEverything)
+	}},
+	{api.ResourceKindDeployment, func(client kubernetes.Interface, namespace string) (runtime.Object, error) {
+		return client.AppsV1().Deployments(namespace).List(context.TODO(), api.ListEverything)
+	}},
+	{api.ResourceKindStatefulSet, func(client kubernetes.Interface, namespace string) (runtime.Object, error) {
+		return client.AppsV1().StatefulSets(namespace).List(context.TODO(), api.ListEverything)
+	}},
+	{api.ResourceKindDaemonSet, func(client kubernetes.Interface, namespace string) (runtime.Object, error) {
+		return client.AppsV1().DaemonSets(namespace).List(context.TODO(), api.ListEverything)
+	}},
+	{api.ResourceKindCronJob, func(client kubernetes.Interface, namespace string) (runtime.Object, error) {
+		return compat.ListCronJobs(client, namespace, api.ListEverything)
+	}},
+	{api.ResourceKindPod, func(client kubernetes.Interface, namespace string) (runtime.Object, error) {
+		return client.CoreV1().Pods(namespace).List(context.TODO(), api.ListEverything)
+	}},
+	{api.ResourceKindService, func(client kubernetes.Interface, namespace string) (runtime.Object, error) {
+		return client.CoreV1().Services(namespace).List(context.TODO(), api.ListEverything)
+	}},
+	{api.ResourceKindConfigMap, func(client kubernetes.Interface, namespace string) (runtime.Object, error) {
+		return client.CoreV1().ConfigMaps(namespace).List(context.TODO(), api.ListEverything)
+	}},
+}
+
+type objectIndexEntry struct {
+	key     string
+	objects []Command
+	listed  time.Time
+}
+
+// objectIndex keeps objects listed with credentials of each user for a short time, so typing into the command
+// palette does not list all objects on every key stroke. It is a bounded LRU cache and is safe for concurrent use.
+type objectIndex struct {
+	ttl     time.Duration
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	mux     sync.Mutex
+}
+
+// get returns objects indexed under given credentials key, listing them with given client if they are missing or
+// outdated. Kinds the user is not allowed to list are skipped.
+func (self *objectIndex) get(key string, client kubernetes.Interface, namespace string, now time.Time) []Command {
+	key = key + "/" + namespace
+	if objects, ok := self.lookup(key, now); ok {
+		return objects
+	}
+
+	objects := ListObjects(client, namespace)
+	self.add(key, objects, now)
+	return objects
+}
+
+func (self *objectIndex) lookup(key string, now time.Time) ([]Command, bool) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	element, ok := self.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*objectIndexEntry)
+	if now.Sub(entry.listed) > self.ttl {
+		self.order.Remove(element)
+		delete(self.entries, key)
+		return nil, false
+	}
+
+	self.order.MoveToFront(element)
+	return entry.objects, true
+}
+
+func (self *objectIndex) add(key string, objects []Command, now time.Time) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if element, ok := self.entries[key]; ok {
+		self.order.Remove(element)
+	}
+
+	self.entries[key] = self.order.PushFront(&objectIndexEntry{key: key, objects: objects, listed: now})
+	for self.order.Len() > self.size {
+		oldest := self.order.Back()
+		self.order.Remove(oldest)
+		delete(self.entries, oldest.Value.(*objectIndexEntry).key)
+	}
+}
+
+func newObjectIndex(ttl time.Duration, size int) *objectIndex {
+	return &objectIndex{ttl: ttl, size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// ListObjects returns object commands of all kinds that can be opened from the command palette. Kinds that can not
+// be listed, i.e. because the user is not allowed to, are skipped.
+func ListObjects(client kubernetes.Interface, namespace string) []Command {
+	result := make([]Command, 0)
+	for _, lister := range listers {
+		objects, err := lister.list(client, namespace)
+		if err != nil {
+			continue
+		}
+
+		kind := lister.kind
+		_ = meta.EachListItem(objects, func(object runtime.Object) error {
+			accessor, err := meta.Accessor(object)
+			if err != nil {
+				return err
+			}
+
+			title := accessor.GetName()
+			if len(accessor.GetNamespace()) > 0 {
+				title = accessor.GetNamespace() + "/" + accessor.GetName()
+			}
+
+			result = append(result, Command{Type: CommandObject, Title: title, Kind: kind,
+				Namespace: accessor.GetNamespace(), Name: accessor.GetName()})
+			return nil
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,198 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commandpalette
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+const (
+	// DefaultLimit is the number of commands returned when limit is not provided.
+	DefaultLimit = 20
+	// MaxLimit is the maximum number of commands returned for a single query.
+	MaxLimit = 100
+)
+
+// CommandType tells the UI what to do when the command is selected.
+type CommandType string
+
+const (
+	// CommandNavigate opens a view of the UI, i.e. list of pods.
+	CommandNavigate CommandType = "navigate"
+	// CommandObject opens details of a resource.
+	CommandObject CommandType = "object"
+	// CommandQuickAction starts an admin-defined quick action, the UI asks for its target.
+	CommandQuickAction CommandType = "quickaction"
+)
+
+// Command is a single entry of the command palette.
+type Command struct {
+	Type  CommandType `json:"type"`
+	Title string      `json:"title"`
+	// View is the UI view opened by navigate commands.
+	View string `json:"view,omitempty"`
+	// Kind, Namespace and Name identify the resource opened by object commands.
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// Action is the name of the quick action started by quickaction commands.
+	Action string `json:"action,omitempty"`
+	// Kinds are resource kinds quick action can be executed on.
+	Kinds []string `json:"kinds,omitempty"`
+	Score int      `json:"score"`
+}
+
+// CommandList contains commands matching the query, best matches first.
+type CommandList struct {
+	Query    string    `json:"query"`
+	Commands []Command `json:"commands"`
+}
+
+// views is the registry of navigate commands. Titles are matched against the query.
+var views = []Command{
+	{Type: CommandNavigate, Title: "Overview", View: "overview"},
+	{Type: CommandNavigate, Title: "Workloads", View: "workloads"},
+	{Type: CommandNavigate, Title: "Cron Jobs", View: "cronjob"},
+	{Type: CommandNavigate, Title: "Daemon Sets", View: "daemonset"},
+	{Type: CommandNavigate, Title: "Deployments", View: "deployment"},
+	{Type: CommandNavigate, Title: "Jobs", View: "job"},
+	{Type: CommandNavigate, Title: "Pods", View: "pod"},
+	{Type: CommandNavigate, Title: "Replica Sets", View: "replicaset"},
+	{Type: CommandNavigate, Title: "Stateful Sets", View: "statefulset"},
+	{Type: CommandNavigate, Title: "Services", View: "service"},
+	{Type: CommandNavigate, Title: "Ingresses", View: "ingress"},
+	{Type: CommandNavigate, Title: "Config Maps", View: "configmap"},
+	{Type: CommandNavigate, Title: "Secrets", View: "secret"},
+	{Type: CommandNavigate, Title: "Persistent Volume Claims", View: "persistentvolumeclaim"},
+	{Type: CommandNavigate, Title: "Namespaces", View: "namespace"},
+	{Type: CommandNavigate, Title: "Nodes", View: "node"},
+	{Type: CommandNavigate, Title: "Persistent Volumes", View: "persistentvolume"},
+	{Type: CommandNavigate, Title: "Storage Classes", View: "storageclass"},
+	{Type: CommandNavigate, Title: "Custom Resource Definitions", View: "customresourcedefinition"},
+	{Type: CommandNavigate, Title: "Events", View: "event"},
+	{Type: CommandNavigate, Title: "Create New Resource", View: "create"},
+	{Type: CommandNavigate, Title: "Settings", View: "settings"},
+	{Type: CommandNavigate, Title: "About", View: "about"},
+}
+
+// GetCommandList returns up to limit commands matching the query. Views, quick actions and objects are ranked
+// together by their score. Empty query matches only views and quick actions, as there are too many objects to
+// list them all.
+func GetCommandList(query string, objects []Command, actions []settingsApi.QuickAction, limit int) *CommandList {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	candidates := make([]Command, 0, len(views)+len(actions)+len(objects))
+	candidates = append(candidates, views...)
+	for _, action := range actions {
+		title := action.DisplayName
+		if len(title) == 0 {
+			title = action.Name
+		}
+
+		candidates = append(candidates, Command{Type: CommandQuickAction, Title: title, Action: action.Name,
+			Kinds: action.Kinds})
+	}
+
+	if len(strings.TrimSpace(query)) > 0 {
+		candidates = append(candidates, objects...)
+	}
+
+	result := &CommandList{Query: query, Commands: make([]Command, 0)}
+	for _, candidate := range candidates {
+		if score, ok := Score(query, candidate.Title); ok {
+			candidate.Score = score
+			result.Commands = append(result.Commands, candidate)
+		}
+	}
+
+	sort.SliceStable(result.Commands, func(i, j int) bool {
+		a, b := result.Commands[i], result.Commands[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+
+		if len(a.Title) != len(b.Title) {
+			return len(a.Title) < len(b.Title)
+		}
+
+		return a.Title < b.Title
+	})
+
+	if len(result.Commands) > limit {
+		result.Commands = result.Commands[:limit]
+	}
+
+	return result
+}
+
+// Score returns how well the candidate matches the query. Query matches if all its characters appear in the
+// candidate in the same order, ignoring case and spaces. Exact matches score the highest, followed by prefix and
+// substring matches. Subsequence matches are scored by how many characters are consecutive or start a word.
+func Score(query, candidate string) (int, bool) {
+	query = strings.ToLower(strings.Join(strings.Fields(query), ""))
+	lower := strings.ToLower(candidate)
+	if len(query) == 0 {
+		return 0, true
+	}
+
+	switch compact := strings.Replace(lower, " ", "", -1); {
+	case lower == query || compact == query:
+		return 1000, true
+	case strings.HasPrefix(lower, query) || strings.HasPrefix(compact, query):
+		return 800 - len(candidate), true
+	case strings.Contains(lower, query):
+		return 600 - strings.Index(lower, query) - len(candidate), true
+	}
+
+	score, queryIndex, previousEnd := 0, 0, -1
+	for i, r := range lower {
+		if queryIndex >= len(query) {
+			break
+		}
+
+		expected, size := utf8.DecodeRuneInString(query[queryIndex:])
+		if r != expected {
+			continue
+		}
+
+		score++
+		if previousEnd == i {
+			score += 5
+		}
+
+		if i == 0 || strings.ContainsRune(" -./_", rune(lower[i-1])) {
+			score += 10
+		}
+
+		previousEnd = i + utf8.RuneLen(r)
+		queryIndex += size
+	}
+
+	if queryIndex < len(query) {
+		return 0, false
+	}
+
+	return score, true
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commandpalette
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		query, candidate string
+		matches          bool
+	}{
+		{"pods", "Pods", true},
+		{"dep", "Deployments", true},
+		{"cm", "Config Maps", true},
+		{"pvc", "Persistent Volume Claims", true},
+		{"web", "default/frontend-web", true},
+		{"xyz", "Pods", false},
+		{"spod", "Pods", false},
+	}
+
+	for _, c := range cases {
+		if _, ok := Score(c.query, c.candidate); ok != c.matches {
+			t.Errorf("Score(%q, %q): Expected match to be %v, but got %v.", c.query, c.candidate, c.matches, ok)
+		}
+	}
+
+	exact, _ := Score("pods", "Pods")
+	prefix, _ := Score("pod", "Pods")
+	substring, _ := Score("ods", "Pods")
+	subsequence, _ := Score("pds", "Pods")
+	if !(exact > prefix && prefix > substring && substring > subsequence) {
+		t.Errorf("Expected exact > prefix > substring > subsequence match, but got %d, %d, %d, %d.", exact, prefix,
+			substring, subsequence)
+	}
+
+	words, _ := Score("cm", "Config Maps")
+	middle, _ := Score("cm", "Custom Resource Definitions")
+	if words <= middle {
+		t.Errorf("Expected match of word starts to score higher, but got %d and %d.", words, middle)
+	}
+}
+
+func TestGetCommandList(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "frontend-7d9f", Namespace: "default"}},
+		&apps.Deployment{ObjectMeta: metaV1.ObjectMeta{Name: "frontend", Namespace: "default"}},
+		&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "kube-system"}},
+	)
+	actions := []settingsApi.QuickAction{{Name: "restart", DisplayName: "Restart", Kinds: []string{"deployment"}}}
+
+	index := newObjectIndex(objectIndexTTL, objectIndexSize)
+	objects := index.get("user", client, "", time.Now())
+	if len(objects) != 3 {
+		t.Fatalf("Expected 3 indexed objects, but got %v.", objects)
+	}
+
+	result := GetCommandList("frontend", objects, actions, 0)
+	if len(result.Commands) != 2 || result.Commands[0].Kind != "deployment" || result.Commands[1].Kind != "pod" {
+		t.Errorf("Expected deployment to be ranked before its pod, but got %v.", result.Commands)
+	}
+
+	result = GetCommandList("", objects, actions, 0)
+	for _, command := range result.Commands {
+		if command.Type == CommandObject {
+			t.Errorf("Expected empty query not to return objects, but got %v.", command)
+		}
+	}
+
+	result = GetCommandList("rest", objects, actions, 1)
+	if len(result.Commands) != 1 || result.Commands[0].Action != "restart" {
+		t.Errorf("Expected only restart quick action, but got %v.", result.Commands)
+	}
+}
+
+func TestObjectIndex(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "default"}})
+	index := newObjectIndex(time.Minute, 1)
+	now := time.Now()
+	index.get("alice", client, "", now)
+
+	_ = client.CoreV1().Namespaces().Delete(context.TODO(), "default", metaV1.DeleteOptions{})
+	if objects := index.get("alice", client, "", now.Add(30*time.Second)); len(objects) != 1 {
+		t.Errorf("Expected objects to be served from the index, but got %v.", objects)
+	}
+
+	if objects := index.get("alice", client, "", now.Add(2*time.Minute)); len(objects) != 0 {
+		t.Errorf("Expected outdated objects to be listed again, but got %v.", objects)
+	}
+
+	index.get("bob", client, "", now)
+	if _, ok := index.lookup("alice/", now); ok {
+		t.Error("Expected the least recently used user to be evicted.")
+	}
+}
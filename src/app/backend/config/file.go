@@ -0,0 +1,231 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ConfigFileFlagName is the name of the argument pointing to the config file. It can not be set in the file.
+const ConfigFileFlagName = "config-file"
+
+// ReloadCheckPeriod defines how often config file is checked for modifications.
+const ReloadCheckPeriod = 10 * time.Second
+
+// ReloadableOptions lists options that are applied without restarting Dashboard when the config file changes.
+// Changes of other options, including authentication, TLS and integrations, are logged and require a restart.
+var ReloadableOptions = []string{
+	"api-log-level",
+	"feature-gates",
+	"system-banner",
+	"system-banner-severity",
+}
+
+// FileConfig loads Dashboard options from a YAML file. Keys of the file are the names of command line
+// arguments, e.g. 'auto-generate-certificates: true'. Lists are accepted wherever the argument takes comma
+// separated values and maps wherever it takes key=value pairs. Options passed on the command line take
+// precedence over the file.
+type FileConfig struct {
+	path    string
+	flags   *pflag.FlagSet
+	cmdLine map[string]bool
+	modTime time.Time
+}
+
+// NewFileConfig creates FileConfig and applies all options found in the file to the given flag set.
+func NewFileConfig(path string, flags *pflag.FlagSet) (*FileConfig, error) {
+	config := &FileConfig{path: path, flags: flags, cmdLine: make(map[string]bool)}
+	flags.Visit(func(flag *pflag.Flag) { config.cmdLine[flag.Name] = true })
+
+	if _, err := config.apply(nil); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Reload reads the file again and applies reloadable options. Names of the changed options are returned.
+func (self *FileConfig) Reload() ([]string, error) {
+	return self.apply(ReloadableOptions)
+}
+
+// Watch reloads the config file on SIGHUP and whenever its modification time changes. Function onReload
+// is called with the names of changed options after every reload that changed anything.
+func (self *FileConfig) Watch(onReload func(changed []string)) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	ticker := time.NewTicker(ReloadCheckPeriod)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				log.Printf("Received SIGHUP, reloading config file %s", self.path)
+			case <-ticker.C:
+				info, err := os.Stat(self.path)
+				if err != nil || info.ModTime().Equal(self.modTime) {
+					continue
+				}
+				log.Printf("Config file %s changed, reloading", self.path)
+			}
+
+			changed, err := self.Reload()
+			if err != nil {
+				log.Printf("Cannot reload config file %s: %s", self.path, err.Error())
+				continue
+			}
+
+			if len(changed) > 0 {
+				log.Printf("Reloaded options: %s", strings.Join(changed, ", "))
+				onReload(changed)
+			}
+		}
+	}()
+}
+
+// apply reads the file and sets flags that were not set on the command line. If only is not nil, other
+// options are skipped and reloadable options missing in the file are reset to their defaults.
+func (self *FileConfig) apply(only []string) ([]string, error) {
+	info, err := os.Stat(self.path)
+	if err != nil {
+		return nil, err
+	}
+	self.modTime = info.ModTime()
+
+	data, err := ioutil.ReadFile(self.path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("cannot parse config file %s: %s", self.path, err.Error())
+	}
+
+	if only != nil {
+		for _, name := range only {
+			if _, ok := values[name]; !ok {
+				if flag := self.flags.Lookup(name); flag != nil {
+					values[name] = normalize(flag, flag.DefValue)
+				}
+			}
+		}
+	}
+
+	changed := make([]string, 0)
+	for name, value := range values {
+		flag := self.flags.Lookup(name)
+		if flag == nil || name == ConfigFileFlagName {
+			return nil, fmt.Errorf("unknown option %s in config file %s", name, self.path)
+		}
+
+		if self.cmdLine[name] {
+			continue
+		}
+
+		s := toString(value)
+		if only != nil {
+			current := normalize(flag, flag.Value.String())
+			if !contains(only, name) {
+				if s != current {
+					log.Printf("Option %s changed in config file, restart is required to apply it", name)
+				}
+				continue
+			}
+
+			if s == current {
+				continue
+			}
+		}
+
+		if err := self.flags.Set(name, s); err != nil {
+			return nil, fmt.Errorf("invalid value of option %s in config file %s: %s", name, self.path, err.Error())
+		}
+		changed = append(changed, name)
+	}
+
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// toString converts YAML value to the format accepted by flags. Lists are joined with commas and maps are
+// converted to comma separated key=value pairs ordered by key. List items with commas are quoted like CSV fields.
+func toString(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, 0, len(typed))
+		for _, item := range typed {
+			items = append(items, quote(fmt.Sprint(item)))
+		}
+		return strings.Join(items, ",")
+	case map[interface{}]interface{}:
+		pairs := make([]string, 0, len(typed))
+		for key, item := range typed {
+			pairs = append(pairs, fmt.Sprintf("%v=%v", key, item))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	}
+
+	return fmt.Sprint(value)
+}
+
+// quote quotes value containing commas or quotes, so that list flags read it as a single item.
+func quote(value string) string {
+	if !strings.ContainsAny(value, ",\"") {
+		return value
+	}
+	return `"` + strings.Replace(value, `"`, `""`, -1) + `"`
+}
+
+// normalize converts value of list and map flags, which are printed in brackets and maps in random order,
+// to the format returned by toString, so that they can be compared.
+func normalize(flag *pflag.Flag, value string) string {
+	kind := flag.Value.Type()
+	if !strings.HasSuffix(kind, "Slice") && !strings.HasSuffix(kind, "Array") && !strings.HasPrefix(kind, "stringTo") {
+		return value
+	}
+
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	if strings.HasPrefix(kind, "stringTo") {
+		pairs := strings.Split(value, ",")
+		sort.Strings(pairs)
+		value = strings.Join(pairs, ",")
+	}
+	return value
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,156 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func newFlagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("system-banner", "", "")
+	flags.String("api-log-level", "INFO", "")
+	flags.String("feature-gates", "", "")
+	flags.String("system-banner-severity", "INFO", "")
+	flags.Int("port", 8443, "")
+	flags.StringSlice("authentication-mode", []string{"token"}, "")
+	flags.StringToInt("route-timeouts", map[string]int{}, "")
+	return flags
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewFileConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	writeConfig(t, path, "port: 9443\nauthentication-mode: [token, basic]\nsystem-banner: from file\n"+
+		"route-timeouts:\n  /api/v1/pod: 30\n  /api/v1/node: 60\n")
+
+	flags := newFlagSet()
+	if err := flags.Parse([]string{"--system-banner=from command line"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFileConfig(path, flags); err != nil {
+		t.Fatalf("NewFileConfig() returned unexpected error: %s", err.Error())
+	}
+
+	port, _ := flags.GetInt("port")
+	modes, _ := flags.GetStringSlice("authentication-mode")
+	banner, _ := flags.GetString("system-banner")
+	if port != 9443 || !reflect.DeepEqual(modes, []string{"token", "basic"}) || banner != "from command line" {
+		t.Errorf("unexpected options after loading config file: port %d, modes %v, banner %s", port, modes, banner)
+	}
+
+	timeouts, _ := flags.GetStringToInt("route-timeouts")
+	if expected := map[string]int{"/api/v1/pod": 30, "/api/v1/node": 60}; !reflect.DeepEqual(timeouts, expected) {
+		t.Errorf("unexpected route timeouts after loading config file: %v, expected %v", timeouts, expected)
+	}
+
+	writeConfig(t, path, "unknown: true\n")
+	if _, err := NewFileConfig(path, newFlagSet()); err == nil {
+		t.Error("NewFileConfig() should fail on unknown options")
+	}
+}
+
+func TestFileConfig_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	writeConfig(t, path, "port: 9443\napi-log-level: DEBUG\nsystem-banner: hello\n")
+
+	flags := newFlagSet()
+	config, err := NewFileConfig(path, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeConfig(t, path, "port: 10443\napi-log-level: DEBUG\nfeature-gates: Exec=false\n")
+	changed, err := config.Reload()
+	if err != nil {
+		t.Fatalf("Reload() returned unexpected error: %s", err.Error())
+	}
+
+	if expected := []string{"feature-gates", "system-banner"}; !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Reload() changed %v, expected %v", changed, expected)
+	}
+
+	port, _ := flags.GetInt("port")
+	banner, _ := flags.GetString("system-banner")
+	if port != 9443 || banner != "" {
+		t.Errorf("unexpected options after reload: port %d, banner %s", port, banner)
+	}
+}
+
+func TestToString(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, ""},
+		{true, "true"},
+		{[]interface{}{"token", "basic"}, "token,basic"},
+		{[]interface{}{"*/*@team in (a,b)", "secret:*"}, `"*/*@team in (a,b)",secret:*`},
+		{map[interface{}]interface{}{"b": 2, "a": 1}, "a=1,b=2"},
+	}
+
+	for _, c := range cases {
+		if actual := toString(c.value); actual != c.expected {
+			t.Errorf("toString(%v) == %s, expected %s", c.value, actual, c.expected)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	flags := newFlagSet()
+	if err := flags.Set("route-timeouts", "b=2,a=1"); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{"port", "8443"},
+		{"authentication-mode", "token"},
+		{"route-timeouts", "a=1,b=2"},
+	}
+
+	for _, c := range cases {
+		flag := flags.Lookup(c.name)
+		if actual := normalize(flag, flag.Value.String()); actual != c.expected {
+			t.Errorf("normalize(%s) == %s, expected %s", c.name, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/auth/audit"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/features"
+	"github.com/kubernetes/dashboard/src/app/backend/integration"
+	integrationapi "github.com/kubernetes/dashboard/src/app/backend/integration/api"
+	"github.com/kubernetes/dashboard/src/app/backend/sync/kms"
+)
+
+// Severity tells whether dashboard can start with a problem.
+type Severity string
+
+const (
+	// SeverityError is used for problems that prevent dashboard from working correctly.
+	SeverityError Severity = "ERROR"
+	// SeverityWarning is used for problems that disable or degrade some of the features.
+	SeverityWarning Severity = "WARNING"
+)
+
+// Problem describes a single configuration issue together with a hint how to fix it.
+type Problem struct {
+	Check    string
+	Severity Severity
+	Message  string
+	Hint     string
+}
+
+// Report contains all problems found during configuration validation.
+type Report struct {
+	Problems []Problem
+}
+
+// HasErrors returns true if at least one of the problems prevents dashboard from working.
+func (self Report) HasErrors() bool {
+	for _, problem := range self.Problems {
+		if problem.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Print writes human readable report to the given writer.
+func (self Report) Print(w io.Writer) {
+	if len(self.Problems) == 0 {
+		fmt.Fprintln(w, "Configuration is valid.")
+		return
+	}
+
+	for _, problem := range self.Problems {
+		fmt.Fprintf(w, "[%s] %s: %s\n", problem.Severity, problem.Check, problem.Message)
+		if len(problem.Hint) > 0 {
+			fmt.Fprintf(w, "    Hint: %s\n", problem.Hint)
+		}
+	}
+}
+
+// Log writes all problems to the log.
+func (self Report) Log() {
+	for _, problem := range self.Problems {
+		log.Printf("Configuration %s in %s: %s. %s", strings.ToLower(string(problem.Severity)), problem.Check,
+			problem.Message, problem.Hint)
+	}
+}
+
+// Validate runs all configuration checks. Arguments are checked first, then the checks that require
+// connection to the apiserver and integrated applications.
+func Validate(client kubernetes.Interface, iManager integration.IntegrationManager) Report {
+	problems := CheckArguments()
+	problems = append(problems, CheckKeyHolderNamespace(client)...)
+	problems = append(problems, CheckMetricsProvider(iManager)...)
+	return Report{Problems: problems}
+}
+
+// CheckArguments verifies combinations of dashboard arguments that do not require any connection.
+func CheckArguments() []Problem {
+	problems := make([]Problem, 0)
+	add := func(check string, severity Severity, message, hint string) {
+		problems = append(problems, Problem{Check: check, Severity: severity, Message: message, Hint: hint})
+	}
+
+	certFile, keyFile := args.Holder.GetCertFile(), args.Holder.GetKeyFile()
+	if (len(certFile) > 0) != (len(keyFile) > 0) {
+		add("tls", SeverityError, "only one of --tls-cert-file and --tls-key-file is set",
+			"Set both of them or neither.")
+	} else if len(certFile) > 0 && !args.Holder.GetAutoGenerateCertificates() {
+		for _, file := range []string{certFile, keyFile} {
+			path := filepath.Join(args.Holder.GetDefaultCertDir(), file)
+			if _, err := os.Stat(path); err != nil {
+				add("tls", SeverityError, fmt.Sprintf("cannot read %s: %s", path, err.Error()),
+					"Mount the certificate files into --default-cert-dir or use --auto-generate-certificates.")
+			}
+		}
+	}
+
+	servedOverHTTPS := args.Holder.GetAutoGenerateCertificates() || (len(certFile) > 0 && len(keyFile) > 0)
+	if !servedOverHTTPS && !args.Holder.GetEnableInsecureLogin() {
+		add("authentication", SeverityWarning, "dashboard is served over HTTP, so the login page is disabled",
+			"Use --auto-generate-certificates or TLS files, or set --enable-insecure-login.")
+	}
+
+	for _, mode := range args.Holder.GetAuthenticationMode() {
+		if !authApi.ToAuthenticationModes([]string{mode}).IsEnabled(authApi.AuthenticationMode(mode)) {
+			add("authentication", SeverityError, fmt.Sprintf("unknown authentication mode %s", mode),
+				"Supported values of --authentication-mode are: token, basic, ldap, header.")
+		} else if authApi.AuthenticationMode(mode) == authApi.Basic {
+			add("authentication", SeverityWarning, "basic authentication mode is enabled",
+				"Make sure that apiserver has '--authorization-mode=ABAC' and '--basic-auth-file' flags set.")
+		}
+	}
+
+	if args.Holder.GetLoginNoticeAcknowledgment() && len(args.Holder.GetLoginNotice()) == 0 {
+		add("authentication", SeverityError, "login notice acknowledgment is required but there is no notice",
+			"Set --login-notice or disable --login-notice-acknowledgment.")
+	}
+
+	if args.Holder.GetTokenTTL() < 0 {
+		add("authentication", SeverityError, "--token-ttl cannot be negative", "Use 0 to disable token expiration.")
+	}
+
+	if len(args.Holder.GetSkipAuthServiceAccount()) > 0 && !args.Holder.GetEnableSkipLogin() {
+		add("authentication", SeverityWarning, "--skip-auth-service-account is set but login cannot be skipped",
+			"Set --enable-skip-login or remove --skip-auth-service-account.")
+	}
+
+	if !args.Holder.GetEnableTokenReview() &&
+		(len(args.Holder.GetTokenReviewAudiences()) > 0 || len(args.Holder.GetTokenReviewIssuers()) > 0) {
+		add("authentication", SeverityWarning, "token review audiences or issuers are set but tokens are not reviewed",
+			"Set --enable-token-review or remove --token-review-audiences and --token-review-issuers.")
+	}
+
+	if args.Holder.GetEnableWebAuthn() {
+		if len(args.Holder.GetWebAuthnRPID()) == 0 {
+			add("authentication", SeverityError, "--webauthn-rp-id is not set for WebAuthn second factor",
+				"Set the domain Dashboard is served at.")
+		}
+
+		modes := authApi.ToAuthenticationModes(args.Holder.GetAuthenticationMode())
+		if !modes.IsEnabled(authApi.Basic) && !modes.IsEnabled(authApi.LDAP) {
+			add("authentication", SeverityWarning, "WebAuthn is enabled but no login uses the second factor",
+				"WebAuthn is the second factor of basic and ldap authentication modes. Enable one of them.")
+		}
+	}
+
+	if authApi.ToAuthenticationModes(args.Holder.GetAuthenticationMode()).IsEnabled(authApi.Header) {
+		cidrs, caFile := args.Holder.GetAuthProxyTrustedCIDRs(), args.Holder.GetAuthProxyClientCAFile()
+		if len(cidrs) == 0 && len(caFile) == 0 {
+			add("authentication", SeverityError, "header authentication mode does not trust any proxy",
+				"Set --auth-proxy-trusted-cidrs or --auth-proxy-client-ca-file.")
+		}
+
+		for _, cidr := range cidrs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				add("authentication", SeverityError, fmt.Sprintf("invalid trusted proxy network %s", cidr),
+					"Use the CIDR notation, i.e. '10.0.0.0/8'.")
+			}
+		}
+
+		if len(caFile) > 0 {
+			if _, err := os.Stat(caFile); err != nil {
+				add("authentication", SeverityError, fmt.Sprintf("cannot read %s: %s", caFile, err.Error()),
+					"Mount the CA certificates of authenticating proxies.")
+			} else if !servedOverHTTPS {
+				add("authentication", SeverityWarning, "proxy client certificates are only checked over HTTPS",
+					"Configure certificates or use --auth-proxy-trusted-cidrs.")
+			}
+		}
+	}
+
+	if args.Holder.GetEnableSettingsWebhook() && !servedOverHTTPS {
+		add("settings", SeverityError, "settings webhook is enabled but dashboard is served over HTTP",
+			"Admission webhooks have to be served over HTTPS. Configure certificates or disable the webhook.")
+	}
+
+	if args.Holder.GetEnableTenancy() && args.Holder.GetEnableSkipLogin() {
+		add("tenancy", SeverityWarning, "users who skip the login see no namespaces",
+			"Users who skip the login are anonymous. Add system:anonymous user to a tenant to let them see its namespaces.")
+	}
+
+	for _, resource := range args.Holder.GetProtectedResources() {
+		if _, err := authApi.ParseProtectedResource(resource); err != nil {
+			add("protected resources", SeverityError, err.Error(),
+				"Use the format '[<kind>:][<namespace>/]<name>[@<label selector>]', i.e. 'secret:kube-system/*'.")
+		}
+	}
+
+	for _, namespace := range args.Holder.GetReadOnlyNamespaces() {
+		if _, err := path.Match(namespace, ""); err != nil {
+			add("read-only", SeverityError, fmt.Sprintf("invalid read-only namespace pattern %s", namespace),
+				"Namespaces are glob patterns, i.e. 'kube-*'.")
+		}
+	}
+
+	if _, err := features.ParseFeatureGates(args.Holder.GetFeatureGates()); err != nil {
+		add("features", SeverityError, err.Error(), "Check the value of --feature-gates.")
+	}
+
+	switch provider := args.Holder.GetMetricsProvider(); provider {
+	case "sidecar", "heapster", "none":
+	default:
+		add("metrics", SeverityWarning, fmt.Sprintf("unknown metrics provider %s, sidecar will be used", provider),
+			"Supported values of --metrics-provider are: sidecar, heapster, none.")
+	}
+
+	if severity := args.Holder.GetSystemBannerSeverity(); !isOneOf(severity, "INFO", "WARNING", "ERROR") {
+		add("system banner", SeverityWarning, fmt.Sprintf("unknown severity %s, INFO will be used", severity),
+			"Supported values of --system-banner-severity are: INFO, WARNING, ERROR.")
+	}
+
+	if level := args.Holder.GetAPILogLevel(); !isOneOf(level, "INFO", "NONE", "DEBUG") {
+		add("logging", SeverityWarning, fmt.Sprintf("unknown API log level %s, INFO will be used", level),
+			"Supported values of --api-log-level are: INFO, NONE, DEBUG.")
+	}
+
+	switch sink := args.Holder.GetAuthAuditSink(); sink {
+	case "", audit.SinkStdout, audit.SinkEvent:
+	case audit.SinkFile:
+		if len(args.Holder.GetAuthAuditFile()) == 0 {
+			add("audit", SeverityError, "--auth-audit-file is not set for file audit sink",
+				"Set path of the file audit records are appended to.")
+		}
+	default:
+		add("audit", SeverityError, fmt.Sprintf("unknown audit sink %s", sink),
+			"Supported values of --auth-audit-sink are: stdout, file, event.")
+	}
+
+	if args.Holder.GetAuthAuditSink() == audit.SinkEvent {
+		if _, err := audit.ParseEventObject(args.Holder.GetAuthAuditEventObject()); err != nil {
+			add("audit", SeverityError, err.Error(), "Set --auth-audit-event-object to i.e. Service/kubernetes-dashboard.")
+		}
+	}
+
+	if args.Holder.GetImageUpdateCacheTTL() < 0 {
+		add("image updates", SeverityError, "--image-update-cache-ttl cannot be negative",
+			"Use 0 to read tags from registries on every request.")
+	}
+
+	switch store := args.Holder.GetKeyStore(); store {
+	case authApi.KeyStoreSecret:
+	case authApi.KeyStoreVault:
+		if len(args.Holder.GetVaultAddress()) == 0 {
+			add("encryption key", SeverityError, "--vault-address is not set for vault key store",
+				"Set address of Vault or the VAULT_ADDR environment variable.")
+		}
+
+		if engine := args.Holder.GetVaultEngine(); !isOneOf(engine, authApi.VaultEngineKV, authApi.VaultEngineTransit) {
+			add("encryption key", SeverityError, fmt.Sprintf("unknown Vault secret engine %s", engine),
+				"Supported values of --vault-engine are: kv, transit.")
+		}
+
+		if len(args.Holder.GetVaultTokenFile()) == 0 && len(args.Holder.GetVaultRole()) == 0 {
+			add("encryption key", SeverityError, "neither --vault-token-file nor --vault-role is set",
+				"Dashboard needs a token or a role of Vault Kubernetes auth method to log in to Vault.")
+		}
+	default:
+		add("encryption key", SeverityError, fmt.Sprintf("unknown key store %s", store),
+			"Supported values of --key-store are: secret, vault.")
+	}
+
+	if provider := args.Holder.GetKMSProvider(); len(provider) > 0 {
+		if !isOneOf(provider, kms.ProviderAWS, kms.ProviderGCP, kms.ProviderAzure) {
+			add("encryption key", SeverityError, fmt.Sprintf("unknown KMS provider %s", provider),
+				"Supported values of --kms-provider are: aws, gcp, azure.")
+		} else if len(args.Holder.GetKMSKey()) == 0 {
+			add("encryption key", SeverityError, "--kms-key is not set for KMS provider "+provider,
+				"Set the key the encryption key is wrapped with.")
+		}
+	}
+
+	return problems
+}
+
+// CheckKeyHolderNamespace verifies that dashboard can read and write the secret used to store the encryption key.
+func CheckKeyHolderNamespace(client kubernetes.Interface) []Problem {
+	problems := make([]Problem, 0)
+	namespace := args.Holder.GetNamespace()
+	for _, verb := range []string{"get", "update"} {
+		ssar := clientapi.ToSelfSubjectAccessReview(namespace, authApi.EncryptionKeyHolderName, "secret", verb)
+		response, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(), ssar,
+			metav1.CreateOptions{})
+		if err != nil {
+			problems = append(problems, Problem{Check: "encryption key", Severity: SeverityWarning,
+				Message: fmt.Sprintf("cannot check access to secrets in %s namespace: %s", namespace, err.Error())})
+			return problems
+		}
+
+		if !response.Status.Allowed {
+			problems = append(problems, Problem{
+				Check:    "encryption key",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("dashboard is not allowed to %s secrets in %s namespace", verb, namespace),
+				Hint: fmt.Sprintf("Grant dashboard service account access to %s secret or point --namespace "+
+					"to the namespace dashboard is deployed in.", authApi.EncryptionKeyHolderName),
+			})
+		}
+	}
+
+	return problems
+}
+
+// CheckMetricsProvider verifies that the configured metrics provider is reachable.
+func CheckMetricsProvider(iManager integration.IntegrationManager) []Problem {
+	var id integrationapi.IntegrationID
+	switch args.Holder.GetMetricsProvider() {
+	case "none":
+		return []Problem{}
+	case "heapster":
+		id = integrationapi.HeapsterIntegrationID
+	default:
+		id = integrationapi.SidecarIntegrationID
+	}
+
+	state, err := iManager.GetState(id)
+	if err == nil && state.Error != nil {
+		err = state.Error
+	}
+	if err != nil {
+		return []Problem{{
+			Check:    "metrics",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s is not reachable: %s", id, err.Error()),
+			Hint: "Metrics and graphs will be unavailable until it becomes reachable. Check --sidecar-host " +
+				"or --heapster-host, or use --metrics-provider=none.",
+		}}
+	}
+
+	return []Problem{}
+}
+
+func isOneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+
+	return false
+}
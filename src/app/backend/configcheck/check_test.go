@@ -0,0 +1,175 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configcheck
+
+import (
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+)
+
+func setValidArguments() {
+	args.GetHolderBuilder().
+		SetCertFile("").
+		SetKeyFile("").
+		SetAutoGenerateCertificates(true).
+		SetAuthenticationMode([]string{"token"}).
+		SetTokenTTL(900).
+		SetEnableSkipLogin(false).
+		SetSkipAuthServiceAccount("").
+		SetFeatureGates("").
+		SetEnableSettingsWebhook(false).
+		SetMetricsProvider("sidecar").
+		SetSystemBannerSeverity("INFO").
+		SetAPILogLevel("INFO").
+		SetAuthAuditSink("").
+		SetAuthAuditFile("").
+		SetAuthAuditEventObject("Service/kubernetes-dashboard").
+		SetKeyStore("secret").
+		SetVaultAddress("").
+		SetVaultEngine("kv").
+		SetVaultTokenFile("").
+		SetVaultRole("kubernetes-dashboard").
+		SetKMSProvider("").
+		SetKMSKey("").
+		SetEnableWebAuthn(false).
+		SetWebAuthnRPID("").
+		SetLoginNotice("").
+		SetLoginNoticeAcknowledgment(false).
+		SetEnableTokenReview(false).
+		SetTokenReviewAudiences([]string{}).
+		SetTokenReviewIssuers([]string{}).
+		SetAuthProxyTrustedCIDRs([]string{}).
+		SetAuthProxyClientCAFile("").
+		SetImageUpdateCacheTTL(3600).
+		SetEnableTenancy(false).
+		SetProtectedResources([]string{}).
+		SetReadOnly(false).
+		SetReadOnlyNamespaces([]string{})
+}
+
+func TestCheckArguments(t *testing.T) {
+	cases := []struct {
+		info     string
+		modify   func()
+		errors   int
+		warnings int
+	}{
+		{"valid arguments", func() {}, 0, 0},
+		{"cert without key", func() {
+			args.GetHolderBuilder().SetAutoGenerateCertificates(false).SetCertFile("tls.crt")
+		}, 1, 1},
+		{"http without insecure login", func() { args.GetHolderBuilder().SetAutoGenerateCertificates(false) }, 0, 1},
+		{"unknown auth mode", func() { args.GetHolderBuilder().SetAuthenticationMode([]string{"oidc"}) }, 1, 0},
+		{"skip auth service account without skip login", func() {
+			args.GetHolderBuilder().SetSkipAuthServiceAccount("dashboard-viewer")
+		}, 0, 1},
+		{"webauthn without rp id", func() {
+			args.GetHolderBuilder().SetAuthenticationMode([]string{"ldap"}).SetEnableWebAuthn(true)
+		}, 1, 0},
+		{"webauthn without basic login", func() {
+			args.GetHolderBuilder().SetEnableWebAuthn(true).SetWebAuthnRPID("dashboard.example.com")
+		}, 0, 1},
+		{"invalid feature gates", func() { args.GetHolderBuilder().SetFeatureGates("Exec") }, 1, 0},
+		{"webhook over http", func() {
+			args.GetHolderBuilder().SetAutoGenerateCertificates(false).SetEnableSettingsWebhook(true)
+		}, 1, 1},
+		{"unknown metrics provider", func() { args.GetHolderBuilder().SetMetricsProvider("prometheus") }, 0, 1},
+		{"unknown audit sink", func() { args.GetHolderBuilder().SetAuthAuditSink("syslog") }, 1, 0},
+		{"file audit sink without file", func() { args.GetHolderBuilder().SetAuthAuditSink("file") }, 1, 0},
+		{"invalid audit event object", func() {
+			args.GetHolderBuilder().SetAuthAuditSink("event").SetAuthAuditEventObject("kubernetes-dashboard")
+		}, 1, 0},
+		{"login notice acknowledgment without notice", func() {
+			args.GetHolderBuilder().SetLoginNoticeAcknowledgment(true)
+		}, 1, 0},
+		{"token review audiences without review", func() {
+			args.GetHolderBuilder().SetTokenReviewAudiences([]string{"dashboard"})
+		}, 0, 1},
+		{"header mode without trusted proxy", func() {
+			args.GetHolderBuilder().SetAuthenticationMode([]string{"header"})
+		}, 1, 0},
+		{"header mode with invalid network", func() {
+			args.GetHolderBuilder().SetAuthenticationMode([]string{"header"}).
+				SetAuthProxyTrustedCIDRs([]string{"10.0.0.0/8", "10.0.0.1"})
+		}, 1, 0},
+		{"header mode with missing client ca", func() {
+			args.GetHolderBuilder().SetAuthenticationMode([]string{"header"}).SetAuthProxyClientCAFile("missing-ca.crt")
+		}, 1, 0},
+		{"tenancy with skip login", func() {
+			args.GetHolderBuilder().SetEnableTenancy(true).SetEnableSkipLogin(true)
+		}, 0, 1},
+		{"invalid protected resources", func() {
+			args.GetHolderBuilder().SetProtectedResources([]string{"secret:kube-system/*", "*/[", "*/*@a=(b"})
+		}, 2, 0},
+		{"invalid read-only namespaces", func() {
+			args.GetHolderBuilder().SetReadOnlyNamespaces([]string{"kube-*", "[team"})
+		}, 1, 0},
+		{"negative image update cache ttl", func() { args.GetHolderBuilder().SetImageUpdateCacheTTL(-1) }, 1, 0},
+		{"unknown key store", func() { args.GetHolderBuilder().SetKeyStore("file") }, 1, 0},
+		{"vault without address", func() { args.GetHolderBuilder().SetKeyStore("vault") }, 1, 0},
+		{"unknown vault engine", func() {
+			args.GetHolderBuilder().SetKeyStore("vault").SetVaultAddress("https://vault:8200").SetVaultEngine("pki")
+		}, 1, 0},
+		{"vault without credentials", func() {
+			args.GetHolderBuilder().SetKeyStore("vault").SetVaultAddress("https://vault:8200").SetVaultRole("")
+		}, 1, 0},
+		{"unknown kms provider", func() { args.GetHolderBuilder().SetKMSProvider("ibm").SetKMSKey("key") }, 1, 0},
+		{"kms provider without key", func() { args.GetHolderBuilder().SetKMSProvider("aws") }, 1, 0},
+	}
+
+	for _, c := range cases {
+		setValidArguments()
+		c.modify()
+
+		errors, warnings := 0, 0
+		for _, problem := range CheckArguments() {
+			if problem.Severity == SeverityError {
+				errors++
+			} else {
+				warnings++
+			}
+		}
+
+		if errors != c.errors || warnings != c.warnings {
+			t.Errorf("%s: expected %d errors and %d warnings, got %d and %d", c.info, c.errors, c.warnings,
+				errors, warnings)
+		}
+	}
+}
+
+func TestCheckKeyHolderNamespace(t *testing.T) {
+	for _, allowed := range []bool{true, false} {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "selfsubjectaccessreviews",
+			func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, &authorizationv1.SelfSubjectAccessReview{
+					Status: authorizationv1.SubjectAccessReviewStatus{Allowed: allowed},
+				}, nil
+			})
+
+		problems := CheckKeyHolderNamespace(client)
+		report := Report{Problems: problems}
+		if report.HasErrors() == allowed {
+			t.Errorf("expected errors to be reported when access is not allowed, allowed: %t, got: %v",
+				allowed, problems)
+		}
+	}
+}
@@ -23,24 +23,38 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/pflag"
 
+	"github.com/kubernetes/dashboard/src/app/backend/accessgrant"
 	"github.com/kubernetes/dashboard/src/app/backend/args"
 	"github.com/kubernetes/dashboard/src/app/backend/auth"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
-	"github.com/kubernetes/dashboard/src/app/backend/auth/jwe"
+	"github.com/kubernetes/dashboard/src/app/backend/auth/audit"
+	// Registers the default JWE token manager.
+	_ "github.com/kubernetes/dashboard/src/app/backend/auth/jwe"
 	"github.com/kubernetes/dashboard/src/app/backend/cert"
 	"github.com/kubernetes/dashboard/src/app/backend/cert/ecdsa"
 	"github.com/kubernetes/dashboard/src/app/backend/client"
 	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/config"
+	"github.com/kubernetes/dashboard/src/app/backend/configcheck"
+	"github.com/kubernetes/dashboard/src/app/backend/features"
 	"github.com/kubernetes/dashboard/src/app/backend/handler"
 	"github.com/kubernetes/dashboard/src/app/backend/integration"
 	integrationapi "github.com/kubernetes/dashboard/src/app/backend/integration/api"
+	"github.com/kubernetes/dashboard/src/app/backend/openapi"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/compat"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/openshift"
 	"github.com/kubernetes/dashboard/src/app/backend/settings"
-	"github.com/kubernetes/dashboard/src/app/backend/sync"
+	"github.com/kubernetes/dashboard/src/app/backend/snapshot"
+	"github.com/kubernetes/dashboard/src/app/backend/stream"
 	"github.com/kubernetes/dashboard/src/app/backend/systembanner"
 )
 
@@ -67,18 +81,92 @@ var (
 		"Kubernetes cluster and service proxy will be used.")
 	argKubeConfigFile     = pflag.String("kubeconfig", "", "Path to kubeconfig file with authorization and master location information.")
 	argTokenTTL           = pflag.Int("token-ttl", int(authApi.DefaultTokenTTL), "Expiration time (in seconds) of JWE tokens generated by dashboard. '0' never expires")
-	argAuthenticationMode = pflag.StringSlice("authentication-mode", []string{authApi.Token.String()}, "Enables authentication options that will be reflected on login screen. Supported values: token, basic. "+
+	argTokenMaxLifetime   = pflag.Int("token-max-lifetime", 0, "Maximum time (in seconds) since login for which tokens can be refreshed. Afterwards user has to log in again. '0' means no limit.")
+	argAuthenticationMode = pflag.StringSlice("authentication-mode", []string{authApi.Token.String()}, "Enables authentication options that will be reflected on login screen. Supported values: token, basic, ldap, header. "+
 		"Note that basic option should only be used if apiserver has '--authorization-mode=ABAC' and '--basic-auth-file' flags set.")
 	argMetricClientCheckPeriod   = pflag.Int("metric-client-check-period", 30, "Time in seconds that defines how often configured metric client health check should be run.")
 	argAutoGenerateCertificates  = pflag.Bool("auto-generate-certificates", false, "When set to true, Dashboard will automatically generate certificates used to serve HTTPS. (default false)")
 	argEnableInsecureLogin       = pflag.Bool("enable-insecure-login", false, "When enabled, Dashboard login view will also be shown when Dashboard is not served over HTTPS. (default false)")
 	argEnableSkip                = pflag.Bool("enable-skip-login", false, "When enabled, the skip button on the login page will be shown. (default false)")
+	argSkipAuthServiceAccount    = pflag.String("skip-auth-service-account", "", "Name of the service account in Dashboard namespace, which is impersonated by users who have skipped the login. Dashboard service account has to be allowed to impersonate it. When not set, privileges of Dashboard service account are used.")
 	argSystemBanner              = pflag.String("system-banner", "", "When non-empty displays message to Dashboard users. Accepts simple HTML tags.")
 	argSystemBannerSeverity      = pflag.String("system-banner-severity", "INFO", "Severity of system banner. Should be one of 'INFO|WARNING|ERROR'.")
+	argLoginNotice               = pflag.String("login-notice", "", "When non-empty, displays notice in markdown format to users before login, i.e. terms of use of the cluster.")
+	argLoginNoticeAcknowledgment = pflag.Bool("login-notice-acknowledgment", false, "When enabled, users have to acknowledge --login-notice to log in. Logins are rejected otherwise. (default false)")
 	argAPILogLevel               = pflag.String("api-log-level", "INFO", "Level of API request logging. Should be one of 'INFO|NONE|DEBUG'.")
 	argDisableSettingsAuthorizer = pflag.Bool("disable-settings-authorizer", false, "When enabled, Dashboard settings page will not require user to be logged in and authorized to access settings page. (default false)")
+	argEnableSettingsWebhook     = pflag.Bool("enable-settings-webhook", false, "When enabled, Dashboard serves a validating admission webhook for its settings config map at /api/webhook/settings. Requires HTTPS. (default false)")
+	argEnableTenancy             = pflag.Bool("enable-tenancy", false, "When enabled, users see only namespaces of their tenants defined by '_tenants' key of the settings config map, regardless of their RBAC permissions. Users that are not members of any tenant see no namespaces. (default false)")
 	argNamespace                 = pflag.String("namespace", getEnv("POD_NAMESPACE", "kube-system"), "When non-default namespace is used, create encryption key in the specified namespace.")
 	localeConfig                 = pflag.String("locale-config", "./locale_conf.json", "File containing the configuration of locales")
+	argListEncoderWorkers        = pflag.Int("list-encoder-workers", runtime.NumCPU(), "Number of workers used to encode list responses with more than "+strconv.Itoa(stream.DefaultStreamThreshold)+" items.")
+	argMaxListItems              = pflag.Int("max-list-items", 0, "Maximum number of items returned in a single list response. Larger lists have to be paginated. '0' means no limit.")
+	argListMemoryBudget          = pflag.Int("list-memory-budget", 0, "Maximum amount of data in megabytes a single request may read from the apiserver to assemble its response. Larger results are truncated and reported as an error. '0' means no limit.")
+	argSlowRequestThreshold      = pflag.Int("slow-request-threshold", 5, "Time in seconds after which a request is logged as slow, together with its route and the identity that made it. '0' disables the log.")
+	argRequestTimeout            = pflag.Int("request-timeout", 120, "Time in seconds after which an API request is aborted. Long-lived streaming routes are never aborted. '0' means no timeout.")
+	argRouteTimeouts             = pflag.StringToInt("route-timeouts", map[string]int{}, "Timeouts in seconds overriding 'request-timeout' for API routes starting with given path, e.g., '/api/v1/node=300,/api/v1/log=0'.")
+	argTerminalHeartbeatInterval = pflag.Int("terminal-heartbeat-interval", 25, "Interval in seconds of heartbeats sent to terminal sessions. Connections that stop answering are closed.")
+	argTerminalIdleTimeout       = pflag.Int("terminal-idle-timeout", 0, "Time in seconds after which terminal sessions without any user input are closed. '0' means no timeout.")
+	argMaxRequestsInFlight       = pflag.Int("max-requests-in-flight", 0, "Maximum number of API requests processed at the same time. Requests over the limit are rejected with '429 Too Many Requests'. '0' means no limit.")
+	argMaxRequestsPerIdentity    = pflag.Int("max-requests-per-identity", 0, "Maximum number of API requests processed at the same time for a single user. Requests over the limit are rejected with '429 Too Many Requests'. '0' means no limit.")
+	argStaleCacheTTL             = pflag.Int("stale-cache-ttl", 0, "Time in seconds for which last successful API responses are kept and served, marked as stale, when the apiserver fails. '0' disables the fallback.")
+	argIdempotencyKeyTTL         = pflag.Int("idempotency-key-ttl", 600, "Time in seconds for which responses of mutating requests with an 'Idempotency-Key' header are kept, so that retried requests are not executed again. '0' disables deduplication.")
+	argDiscoveryCacheTTL         = pflag.Int("discovery-cache-ttl", 300, "Time in seconds for which API discovery results are cached. Last known results are served when the apiserver fails to refresh them, i.e. while an aggregated API is down. '0' refreshes them on every request.")
+	argSnapshotFile              = pflag.String("snapshot-file", "", "Path to a cluster snapshot archive. When set, Dashboard serves the snapshot read-only instead of connecting to a cluster.")
+	argFalcoWebhookToken         = pflag.String("falco-webhook-token", "", "When non-empty, Dashboard receives Falco events at /api/webhook/falco, i.e. from the webhook output of falcosidekick. Requests have to send the token in the 'Authorization: Bearer' header.")
+	argImageUpdateRegistries     = pflag.StringSlice("image-update-registries", []string{}, "Registries, i.e. 'docker.io,ghcr.io', which Dashboard asks for newer semver tags of images used by workloads. Empty disables detection of available updates.")
+	argImageUpdateCacheTTL       = pflag.Int("image-update-cache-ttl", 3600, "Time in seconds for which tags of images read from registries are cached.")
+	argTokenManager              = pflag.String("token-manager", authApi.DefaultTokenManager, "Implementation of tokens generated by Dashboard after login. Supported values: "+strings.Join(authApi.TokenManagerNames(), ", ")+".")
+	argKeyRotationPeriod         = pflag.Int("encryption-key-rotation-period", 0, "Time in seconds after which the encryption key of tokens generated by Dashboard is replaced with a new one. '0' never rotates the key.")
+	argKeyHistory                = pflag.Int("encryption-key-history", 2, "Number of previous encryption keys kept after rotation, so tokens generated before the rotation can still be used until they expire.")
+	argKeyStore                  = pflag.String("key-store", "secret", "Storage of the encryption key of tokens generated by Dashboard. Supported values: secret, vault. 'vault' keeps the key in HashiCorp Vault for clusters where etcd secrets are not trusted for key material.")
+	argVaultAddress              = pflag.String("vault-address", getEnv("VAULT_ADDR", ""), "Address of HashiCorp Vault used when --key-store is 'vault', i.e. 'https://vault.example.com:8200'.")
+	argVaultEngine               = pflag.String("vault-engine", "kv", "Vault secrets engine used to store the encryption key. 'kv' stores it in a KV version 2 secret, 'transit' keeps it in the key holder secret encrypted with a Vault Transit key.")
+	argVaultMount                = pflag.String("vault-mount", "secret", "Mount path of the Vault secrets engine given by --vault-engine.")
+	argVaultKey                  = pflag.String("vault-key", "kubernetes-dashboard/key-holder", "Path of the KV secret or name of the Transit key used to store the encryption key.")
+	argVaultTokenFile            = pflag.String("vault-token-file", "", "Path to a file with Vault token. When empty, Dashboard logs in with Kubernetes auth method using its service account token and --vault-role.")
+	argVaultRole                 = pflag.String("vault-role", "kubernetes-dashboard", "Role of Vault Kubernetes auth method Dashboard logs in with when --vault-token-file is not set.")
+	argKMSProvider               = pflag.String("kms-provider", "", "Cloud KMS used to envelope-encrypt the encryption key of tokens, so the stored key is useless without access to KMS. Supported values: aws, gcp, azure. Disabled when empty.")
+	argKMSKey                    = pflag.String("kms-key", "", "Key of the KMS given by --kms-provider, i.e. AWS KMS key ARN, GCP KMS key resource name or Azure Key Vault key URL.")
+	argFieldManager              = pflag.String("field-manager", "kubernetes-dashboard", "Name of the field manager used for server-side apply of objects edited in Dashboard, unless the request sets its own.")
+	argAuthAuditSink             = pflag.String("auth-audit-sink", "", "Destination of JSON records of authentication events, i.e. logins, token refreshes, logouts and usage of skipped login. Supported values: stdout, file, event. Empty disables auditing.")
+	argAuthAuditFile             = pflag.String("auth-audit-file", "", "Path of the file authentication audit records are appended to when --auth-audit-sink is 'file'.")
+	argAuthAuditEventObject      = pflag.String("auth-audit-event-object", "Service/kubernetes-dashboard", "Object in Dashboard namespace in 'kind/name' format, which Kubernetes events with authentication audit records are attached to when --auth-audit-sink is 'event'. Dashboard service account has to be able to create events in its namespace.")
+	argLDAPURL                   = pflag.String("ldap-url", getEnv("LDAP_URL", ""), "URL of the LDAP or Active Directory server used by the 'ldap' authentication mode, i.e. 'ldaps://ldap.example.com:636'.")
+	argLDAPStartTLS              = pflag.Bool("ldap-start-tls", false, "When enabled, plain 'ldap://' connections are upgraded to TLS before credentials are sent. (default false)")
+	argLDAPInsecureSkipVerify    = pflag.Bool("ldap-insecure-skip-verify", false, "When enabled, the certificate of the LDAP server is not verified. (default false)")
+	argLDAPBindDN                = pflag.String("ldap-bind-dn", getEnv("LDAP_BIND_DN", ""), "DN used to search for users and their groups. Anonymous search is used if empty.")
+	argLDAPBindPassword          = pflag.String("ldap-bind-password", getEnv("LDAP_BIND_PASSWORD", ""), "Password of 'ldap-bind-dn'. Prefer the LDAP_BIND_PASSWORD environment variable.")
+	argLDAPBaseDN                = pflag.String("ldap-base-dn", getEnv("LDAP_BASE_DN", ""), "DN of the subtree in which users and groups are searched, i.e. 'dc=example,dc=com'.")
+	argLDAPUserFilter            = pflag.String("ldap-user-filter", "(uid=%s)", "Filter finding the entry of the user logging in. '%s' is replaced with the username, i.e. '(sAMAccountName=%s)' for Active Directory.")
+	argLDAPGroupFilter           = pflag.String("ldap-group-filter", "(member=%s)", "Filter finding groups of the user. '%s' is replaced with the DN of the user entry. Groups are not looked up if empty.")
+	argLDAPGroupAttribute        = pflag.String("ldap-group-attribute", "cn", "Attribute of group entries used as the name of the Kubernetes group.")
+	argEnableWebAuthn            = pflag.Bool("enable-webauthn", false, "When enabled, users of the 'basic' and 'ldap' authentication modes can register WebAuthn security keys, which they then have to use as the second factor of every login. (default false)")
+	argWebAuthnRPID              = pflag.String("webauthn-rp-id", "", "WebAuthn relying party ID, i.e. the domain Dashboard is served at, such as 'dashboard.example.com'. Registered credentials are scoped to it.")
+	argWebAuthnOrigins           = pflag.StringSlice("webauthn-origins", []string{}, "Origins Dashboard is served at, i.e. 'https://dashboard.example.com:8443'. Defaults to 'https://' followed by --webauthn-rp-id.")
+	argEnableTokenReview         = pflag.Bool("enable-token-review", false, "When enabled, tokens of the 'token' authentication mode are validated with the TokenReview API before login, so expired or otherwise invalid tokens are rejected with a clear reason. Dashboard service account has to be able to create token reviews. (default false)")
+	argTokenReviewAudiences      = pflag.StringSlice("token-review-audiences", []string{}, "Audiences of which a token has to be valid for at least one to be accepted by --enable-token-review, i.e. 'https://kubernetes.default.svc'. Any audience accepted by the API server is allowed if empty.")
+	argTokenReviewIssuers        = pflag.StringSlice("token-review-issuers", []string{}, "Issuers of which one has to issue a token to be accepted by --enable-token-review. Any issuer is allowed if empty.")
+	argAuthProxyUserHeader       = pflag.String("auth-proxy-user-header", "X-Remote-User", "Header in which an authenticating proxy passes the name of the user to the 'header' authentication mode.")
+	argAuthProxyGroupHeader      = pflag.String("auth-proxy-group-header", "X-Remote-Group", "Header in which an authenticating proxy passes groups of the user to the 'header' authentication mode. It can be repeated or hold a comma separated list.")
+	argAuthProxyTrustedCIDRs     = pflag.StringSlice("auth-proxy-trusted-cidrs", []string{}, "Networks of authenticating proxies, i.e. '10.0.0.0/8'. User headers of requests coming from them are trusted by the 'header' authentication mode.")
+	argAuthProxyClientCAFile     = pflag.String("auth-proxy-client-ca-file", "", "File containing CA certificates of authenticating proxies. User headers of HTTPS requests presenting a client certificate signed by them are trusted by the 'header' authentication mode.")
+	argAuthProxyAllowedNames     = pflag.StringSlice("auth-proxy-allowed-names", []string{}, "Common names of client certificates of authenticating proxies. Any certificate signed by --auth-proxy-client-ca-file is trusted if empty.")
+	argLoginMaxAttempts          = pflag.Int("login-max-attempts", 5, "Number of failed login attempts from a single IP address or for a single username after which further attempts are temporarily locked out. '0' means no limit.")
+	argLoginLockoutDuration      = pflag.Int("login-lockout-duration", 30, "Time in seconds of the first lockout after too many failed login attempts. Every further failure doubles it.")
+	argLoginMaxLockoutDuration   = pflag.Int("login-max-lockout-duration", 900, "Maximum time in seconds of a lockout after failed login attempts. Failures are forgotten when no attempt fails for this long.")
+	argMaxElevationDuration      = pflag.Int("max-elevation-duration", 900, "Maximum time in seconds for which a session can be elevated by logging in again with stronger credentials. Changes made afterwards require a new elevation. '0' disables elevation.")
+	argAccessGrantReapPeriod     = pflag.Int("access-grant-reap-period", 60, "Interval in seconds at which Dashboard deletes expired access grants, i.e. role bindings it manages. Dashboard service account has to be able to list and delete role bindings in all namespaces. '0' disables reaping.")
+	argKubeConfigExecPlugins     = pflag.StringSlice("kubeconfig-exec-plugins", []string{}, "Commands of exec credential plugins, e.g. 'aws-iam-authenticator', that Dashboard runs to get a token when logging in with a kubeconfig file using them. The command in the kubeconfig file has to match exactly, it is looked up in PATH of Dashboard and runs with its environment extended by the 'env' of the kubeconfig file. Empty disables exec plugins.")
+	argPublicStatusNamespaces    = pflag.StringSlice("public-status-namespaces", []string{}, "When non-empty, Dashboard serves health of the workloads in these namespaces without authentication at /api/v1/publicstatus, i.e. for public status pages. Dashboard service account has to be able to list workloads in them.")
+	argProtectedResources        = pflag.StringSlice("protected-resources", []string{}, "Resources hidden from Dashboard users in the format '[<kind>:][<namespace>/]<name>[@<label selector>]', i.e. 'secret:kube-system/*' or '*/*@sensitive=true'. Namespace and name are glob patterns, resources without namespace match only non-namespaced resources. Entries with commas in the label selector have to be quoted.")
+	argReadOnly                  = pflag.Bool("read-only", false, "When enabled, Dashboard rejects all requests that would modify the cluster, i.e. create, update, patch, delete, scale or exec, so that it can be exposed publicly as a cluster viewer. (default false)")
+	argReadOnlyNamespaces        = pflag.StringSlice("read-only-namespaces", []string{}, "Namespaces in which Dashboard rejects all requests that would modify resources, i.e. 'kube-*'. Namespaces are glob patterns.")
+	argConfigFile                = pflag.String(config.ConfigFileFlagName, "", "Path to YAML file with Dashboard options. Keys are the names of the arguments, arguments passed on the command line take precedence. "+
+		"Options "+strings.Join(config.ReloadableOptions, ", ")+" are reloaded on SIGHUP or when the file changes, other options require a restart.")
+	argValidateConfig = pflag.Bool("validate-config", false, "When enabled, Dashboard validates its configuration, prints found problems and exits. (default false)")
+	argFeatureGates   = pflag.String("feature-gates", "", "A set of key=value pairs that enable or disable optional features, e.g., 'Exec=false,SecretReveal=false'. "+
+		"Supported features: Exec, SecretReveal, SkipLogin. Gates can be overridden at runtime using settings.")
 )
 
 func main() {
@@ -89,6 +177,16 @@ func main() {
 	pflag.Parse()
 	_ = flag.CommandLine.Parse(make([]string, 0)) // Init for glog calls in kubernetes packages
 
+	// Apply options from the config file to the arguments not set on the command line
+	var fileConfig *config.FileConfig
+	if len(*argConfigFile) > 0 {
+		var err error
+		if fileConfig, err = config.NewFileConfig(*argConfigFile, pflag.CommandLine); err != nil {
+			log.Fatalf("Error while loading config file: %s", err.Error())
+		}
+		log.Printf("Using config file: %s", *argConfigFile)
+	}
+
 	// Initializes dashboard arguments holder so we can read them in other packages
 	initArgHolder()
 
@@ -102,13 +200,24 @@ func main() {
 		log.Printf("Using namespace: %s", args.Holder.GetNamespace())
 	}
 
-	clientManager := client.NewClientManager(args.Holder.GetKubeConfigFile(), args.Holder.GetApiServerHost())
+	var clientManager clientapi.ClientManager
+	if snapshotFile := args.Holder.GetSnapshotFile(); len(snapshotFile) > 0 {
+		clientManager = initSnapshotClientManager(snapshotFile)
+	} else {
+		clientManager = client.NewClientManager(args.Holder.GetKubeConfigFile(), args.Holder.GetApiServerHost())
+	}
+
 	versionInfo, err := clientManager.InsecureClient().Discovery().ServerVersion()
 	if err != nil {
 		handleFatalInitError(err)
 	}
 
 	log.Printf("Successful initial request to the apiserver, version: %s", versionInfo.String())
+	// Snapshots keep objects of the oldest API versions and no objects of OpenShift APIs.
+	if len(args.Holder.GetSnapshotFile()) == 0 {
+		compat.SetServerVersion(versionInfo)
+		openshift.SetCapabilities(openshift.Detect(clientManager.InsecureClient().Discovery()))
+	}
 
 	// Init auth manager
 	authManager := initAuthManager(clientManager)
@@ -139,12 +248,71 @@ func main() {
 			EnableWithRetry(integrationapi.SidecarIntegrationID, time.Duration(args.Holder.GetMetricClientCheckPeriod()))
 	}
 
+	integrationManager.Falco().Configure(args.Holder.GetFalcoWebhookToken())
+	integrationManager.ImageUpdate().Configure(args.Holder.GetImageUpdateRegistries(),
+		time.Duration(args.Holder.GetImageUpdateCacheTTL())*time.Second)
+
+	// Validate configuration before serving any request
+	report := configcheck.Validate(clientManager.InsecureClient(), integrationManager)
+	if args.Holder.GetValidateConfig() {
+		report.Print(os.Stdout)
+		if report.HasErrors() {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Problems may be transient, i.e. apiserver not being reachable yet, so they only fail --validate-config runs.
+	report.Log()
+	if report.HasErrors() {
+		log.Print("Configuration has errors, see the messages above. Some features may not work. Run with " +
+			"--validate-config to only check the configuration.")
+	}
+
+	// Init feature gate manager
+	featureGateManager, err := features.NewFeatureGateManager(args.Holder.GetFeatureGates(), settingsManager,
+		clientManager.InsecureClient())
+	if err != nil {
+		log.Fatalf("Invalid --feature-gates argument: %s", err.Error())
+	}
+	clientManager.SetFeatureGateManager(featureGateManager)
+
+	if err := authApi.SetProtectedResources(args.Holder.GetProtectedResources()); err != nil {
+		log.Fatalf("Invalid --protected-resources argument: %s", err.Error())
+	}
+
+	// Init audit logger selected by the 'auth-audit-sink' argument
+	auditSink, err := audit.NewSinkFromArgs(clientManager.InsecureClient())
+	if err != nil {
+		log.Fatalf("Error while initializing auth audit sink: %s", err.Error())
+	}
+	auditLogger := audit.NewAuditLogger(auditSink)
+	clientManager.SetAuditLogger(auditLogger)
+
+	// Delete expired access grants, a read-only snapshot has nothing to delete
+	if period := args.Holder.GetAccessGrantReapPeriod(); period > 0 && len(args.Holder.GetSnapshotFile()) == 0 {
+		accessgrant.StartReaper(clientManager.InsecureClient(), time.Duration(period)*time.Second)
+	}
+
+	// Apply reloadable options whenever the config file changes
+	if fileConfig != nil {
+		fileConfig.Watch(func(changed []string) {
+			reloadArgHolder()
+			systemBannerManager.Set(args.Holder.GetSystemBanner(), args.Holder.GetSystemBannerSeverity())
+			if err := featureGateManager.SetFlagGates(args.Holder.GetFeatureGates()); err != nil {
+				log.Printf("Cannot reload feature gates: %s", err.Error())
+			}
+		})
+	}
+
 	apiHandler, err := handler.CreateHTTPAPIHandler(
 		integrationManager,
 		clientManager,
 		authManager,
 		settingsManager,
-		systemBannerManager)
+		systemBannerManager,
+		featureGateManager,
+		auditLogger)
 	if err != nil {
 		handleFatalInitError(err)
 	}
@@ -172,6 +340,7 @@ func main() {
 	// Run a HTTP server that serves static public files from './public' and handles API calls.
 	http.Handle("/", handler.MakeGzipHandler(handler.CreateLocaleHandler()))
 	http.Handle("/api/", apiHandler)
+	http.Handle(openapi.DocumentPath, apiHandler)
 	http.Handle("/config", handler.AppHandler(handler.ConfigHandler))
 	http.Handle("/api/sockjs/", handler.CreateAttachHandler("/api/sockjs"))
 	http.Handle("/metrics", promhttp.Handler())
@@ -188,6 +357,11 @@ func main() {
 				MinVersion:   tls.VersionTLS12,
 			},
 		}
+		if len(args.Holder.GetAuthProxyClientCAFile()) > 0 {
+			// Certificates of authenticating proxies are verified by the 'header' authentication mode, other clients
+			// do not have to present any.
+			server.TLSConfig.ClientAuth = tls.RequestClientCert
+		}
 		go func() { log.Fatal(server.ListenAndServeTLS("", "")) }()
 	} else {
 		log.Printf("Serving insecurely on HTTP port: %d", args.Holder.GetInsecurePort())
@@ -197,24 +371,46 @@ func main() {
 	select {}
 }
 
-func initAuthManager(clientManager clientapi.ClientManager) authApi.AuthManager {
-	insecureClient := clientManager.InsecureClient()
+// initSnapshotClientManager loads the cluster snapshot that is served read-only instead of a live cluster.
+// There are no metrics in a snapshot, so metrics provider is disabled.
+func initSnapshotClientManager(path string) clientapi.ClientManager {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Error while opening cluster snapshot: %s", err.Error())
+	}
+	defer file.Close()
+
+	objects, err := snapshot.Read(file)
+	if err != nil {
+		log.Fatalf("Error while loading cluster snapshot: %s", err.Error())
+	}
 
-	// Init default encryption key synchronizer
-	synchronizerManager := sync.NewSynchronizerManager(insecureClient)
-	keySynchronizer := synchronizerManager.Secret(args.Holder.GetNamespace(), authApi.EncryptionKeyHolderName)
+	log.Printf("Serving read-only cluster snapshot %s with %d objects", path, len(objects))
+	builder := args.GetHolderBuilder()
+	builder.SetMetricsProvider("none")
+	if len(args.Holder.GetSystemBanner()) == 0 {
+		builder.SetSystemBanner("Read-only cluster snapshot loaded from " + filepath.Base(path))
+	}
 
-	// Register synchronizer. Overwatch will be responsible for restarting it in case of error.
-	sync.Overwatch.RegisterSynchronizer(keySynchronizer, sync.AlwaysRestart)
+	return snapshot.NewClientManager(objects)
+}
+
+func initAuthManager(clientManager clientapi.ClientManager) authApi.AuthManager {
+	// Init token manager selected by the 'token-manager' argument
+	tokenManager, err := authApi.NewTokenManager(args.Holder.GetTokenManager(), clientManager.InsecureClient())
+	if err != nil {
+		log.Fatalf("Error while initializing token manager: %s", err)
+	}
 
-	// Init encryption key holder and token manager
-	keyHolder := jwe.NewRSAKeyHolder(keySynchronizer)
-	tokenManager := jwe.NewJWETokenManager(keyHolder)
 	tokenTTL := time.Duration(args.Holder.GetTokenTTL())
 	if tokenTTL != authApi.DefaultTokenTTL {
 		tokenManager.SetTokenTTL(tokenTTL)
 	}
 
+	if maxLifetime := args.Holder.GetTokenMaxLifetime(); maxLifetime > 0 {
+		tokenManager.SetTokenMaxLifetime(time.Duration(maxLifetime))
+	}
+
 	// Set token manager for client manager.
 	clientManager.SetTokenManager(tokenManager)
 	authModes := authApi.ToAuthenticationModes(args.Holder.GetAuthenticationMode())
@@ -233,7 +429,66 @@ func initArgHolder() {
 	builder.SetInsecurePort(*argInsecurePort)
 	builder.SetPort(*argPort)
 	builder.SetTokenTTL(*argTokenTTL)
+	builder.SetTokenMaxLifetime(*argTokenMaxLifetime)
+	builder.SetLoginMaxAttempts(*argLoginMaxAttempts)
+	builder.SetLoginLockoutDuration(*argLoginLockoutDuration)
+	builder.SetLoginMaxLockoutDuration(*argLoginMaxLockoutDuration)
+	builder.SetMaxElevationDuration(*argMaxElevationDuration)
+	builder.SetAccessGrantReapPeriod(*argAccessGrantReapPeriod)
 	builder.SetMetricClientCheckPeriod(*argMetricClientCheckPeriod)
+	builder.SetListEncoderWorkers(*argListEncoderWorkers)
+	builder.SetMaxListItems(*argMaxListItems)
+	builder.SetListMemoryBudget(*argListMemoryBudget)
+	builder.SetSlowRequestThreshold(*argSlowRequestThreshold)
+	builder.SetRequestTimeout(*argRequestTimeout)
+	builder.SetRouteTimeouts(*argRouteTimeouts)
+	builder.SetTerminalHeartbeatInterval(*argTerminalHeartbeatInterval)
+	builder.SetTerminalIdleTimeout(*argTerminalIdleTimeout)
+	builder.SetMaxRequestsInFlight(*argMaxRequestsInFlight)
+	builder.SetMaxRequestsPerIdentity(*argMaxRequestsPerIdentity)
+	builder.SetStaleCacheTTL(*argStaleCacheTTL)
+	builder.SetIdempotencyKeyTTL(*argIdempotencyKeyTTL)
+	builder.SetDiscoveryCacheTTL(*argDiscoveryCacheTTL)
+	builder.SetSnapshotFile(*argSnapshotFile)
+	builder.SetFalcoWebhookToken(*argFalcoWebhookToken)
+	builder.SetImageUpdateRegistries(*argImageUpdateRegistries)
+	builder.SetImageUpdateCacheTTL(*argImageUpdateCacheTTL)
+	builder.SetTokenManager(*argTokenManager)
+	builder.SetEncryptionKeyRotationPeriod(*argKeyRotationPeriod)
+	builder.SetEncryptionKeyHistory(*argKeyHistory)
+	builder.SetKeyStore(*argKeyStore)
+	builder.SetVaultAddress(*argVaultAddress)
+	builder.SetVaultEngine(*argVaultEngine)
+	builder.SetVaultMount(*argVaultMount)
+	builder.SetVaultKey(*argVaultKey)
+	builder.SetVaultTokenFile(*argVaultTokenFile)
+	builder.SetVaultRole(*argVaultRole)
+	builder.SetKMSProvider(*argKMSProvider)
+	builder.SetKMSKey(*argKMSKey)
+	builder.SetFieldManager(*argFieldManager)
+	builder.SetAuthAuditSink(*argAuthAuditSink)
+	builder.SetAuthAuditFile(*argAuthAuditFile)
+	builder.SetAuthAuditEventObject(*argAuthAuditEventObject)
+	builder.SetLDAPURL(*argLDAPURL)
+	builder.SetLDAPStartTLS(*argLDAPStartTLS)
+	builder.SetLDAPInsecureSkipVerify(*argLDAPInsecureSkipVerify)
+	builder.SetLDAPBindDN(*argLDAPBindDN)
+	builder.SetLDAPBindPassword(*argLDAPBindPassword)
+	builder.SetLDAPBaseDN(*argLDAPBaseDN)
+	builder.SetLDAPUserFilter(*argLDAPUserFilter)
+	builder.SetLDAPGroupFilter(*argLDAPGroupFilter)
+	builder.SetLDAPGroupAttribute(*argLDAPGroupAttribute)
+	builder.SetEnableWebAuthn(*argEnableWebAuthn)
+	builder.SetWebAuthnRPID(*argWebAuthnRPID)
+	builder.SetWebAuthnOrigins(*argWebAuthnOrigins)
+	builder.SetEnableTokenReview(*argEnableTokenReview)
+	builder.SetTokenReviewAudiences(*argTokenReviewAudiences)
+	builder.SetTokenReviewIssuers(*argTokenReviewIssuers)
+	builder.SetAuthProxyUserHeader(*argAuthProxyUserHeader)
+	builder.SetAuthProxyGroupHeader(*argAuthProxyGroupHeader)
+	builder.SetAuthProxyTrustedCIDRs(*argAuthProxyTrustedCIDRs)
+	builder.SetAuthProxyClientCAFile(*argAuthProxyClientCAFile)
+	builder.SetAuthProxyAllowedNames(*argAuthProxyAllowedNames)
 	builder.SetInsecureBindAddress(*argInsecureBindAddress)
 	builder.SetBindAddress(*argBindAddress)
 	builder.SetDefaultCertDir(*argDefaultCertDir)
@@ -246,14 +501,35 @@ func initArgHolder() {
 	builder.SetKubeConfigFile(*argKubeConfigFile)
 	builder.SetSystemBanner(*argSystemBanner)
 	builder.SetSystemBannerSeverity(*argSystemBannerSeverity)
+	builder.SetLoginNotice(*argLoginNotice)
+	builder.SetLoginNoticeAcknowledgment(*argLoginNoticeAcknowledgment)
 	builder.SetAPILogLevel(*argAPILogLevel)
 	builder.SetAuthenticationMode(*argAuthenticationMode)
+	builder.SetPublicStatusNamespaces(*argPublicStatusNamespaces)
+	builder.SetProtectedResources(*argProtectedResources)
+	builder.SetReadOnly(*argReadOnly)
+	builder.SetReadOnlyNamespaces(*argReadOnlyNamespaces)
+	builder.SetKubeConfigExecPlugins(*argKubeConfigExecPlugins)
 	builder.SetAutoGenerateCertificates(*argAutoGenerateCertificates)
 	builder.SetEnableInsecureLogin(*argEnableInsecureLogin)
 	builder.SetDisableSettingsAuthorizer(*argDisableSettingsAuthorizer)
 	builder.SetEnableSkipLogin(*argEnableSkip)
+	builder.SetSkipAuthServiceAccount(*argSkipAuthServiceAccount)
+	builder.SetEnableSettingsWebhook(*argEnableSettingsWebhook)
+	builder.SetEnableTenancy(*argEnableTenancy)
 	builder.SetNamespace(*argNamespace)
 	builder.SetLocaleConfig(*localeConfig)
+	builder.SetFeatureGates(*argFeatureGates)
+	builder.SetValidateConfig(*argValidateConfig)
+}
+
+// reloadArgHolder updates arguments that can be changed while Dashboard is running.
+func reloadArgHolder() {
+	builder := args.GetHolderBuilder()
+	builder.SetSystemBanner(*argSystemBanner)
+	builder.SetSystemBannerSeverity(*argSystemBannerSeverity)
+	builder.SetAPILogLevel(*argAPILogLevel)
+	builder.SetFeatureGates(*argFeatureGates)
 }
 
 /**
@@ -0,0 +1,52 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// PodHealth describes state of a single pod of a cluster component.
+type PodHealth struct {
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	NodeName  string      `json:"nodeName"`
+	IP        string      `json:"ip"`
+	Phase     v1.PodPhase `json:"phase"`
+	Ready     bool        `json:"ready"`
+	Restarts  int32       `json:"restarts"`
+}
+
+func toPodHealth(pod v1.Pod) PodHealth {
+	result := PodHealth{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		NodeName:  pod.Spec.NodeName,
+		IP:        pod.Status.PodIP,
+		Phase:     pod.Status.Phase,
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			result.Ready = condition.Status == v1.ConditionTrue
+		}
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		result.Restarts += status.RestartCount
+	}
+
+	return result
+}
@@ -0,0 +1,429 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// ConnectivityProbeLabel marks probe pods and their owners with the ID of the test.
+	ConnectivityProbeLabel = "dashboard.kubernetes.io/connectivity-probe"
+	// DefaultProbeImage is used by probe pods when no image is given. The image has to provide sh, date and nc.
+	DefaultProbeImage = "busybox:1.32"
+
+	// Maximum number of sources and targets of a single test.
+	maxProbeSources = 5
+	maxProbeTargets = 10
+	// Time after which a single connection is reported as failed.
+	connectTimeoutSeconds = 2
+	// Time for which probe pods are awaited. It includes pulling of the image.
+	probeTimeout = 45 * time.Second
+	// Interval in which probe pods are checked.
+	probePollInterval = time.Second
+	// Maximum size of the output read from a probe pod.
+	maxProbeOutputBytes = 16 * 1024
+	// Prefix of lines with results printed by probe pods.
+	probeResultPrefix = "RESULT"
+)
+
+// ConnectivityStatus is the result of a single connection attempt.
+type ConnectivityStatus string
+
+const (
+	// ConnectivityPass is used when the connection was established.
+	ConnectivityPass ConnectivityStatus = "pass"
+	// ConnectivityFail is used when the connection was refused, dropped or timed out.
+	ConnectivityFail ConnectivityStatus = "fail"
+	// ConnectivityUnknown is used when the connection could not be tested, i.e. the target has no running pod.
+	ConnectivityUnknown ConnectivityStatus = "unknown"
+)
+
+// LogsFunc returns output of given finished pod.
+type LogsFunc func(client kubernetes.Interface, namespace, name string) ([]byte, error)
+
+// ConnectivityPeer is a group of pods given by their namespace and labels, i.e. pods of a workload.
+type ConnectivityPeer struct {
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
+}
+
+// ConnectivityTarget is a peer tested on a single TCP port.
+type ConnectivityTarget struct {
+	ConnectivityPeer
+	Port int32 `json:"port"`
+}
+
+// ConnectivitySpec describes a connectivity test. A probe pod with labels of the source is started in the
+// namespace of every source, so that network policies apply to it the same way as to the source pods.
+type ConnectivitySpec struct {
+	Sources []ConnectivityPeer   `json:"sources"`
+	Targets []ConnectivityTarget `json:"targets"`
+	// Image of probe pods. Defaults to DefaultProbeImage.
+	Image string `json:"image,omitempty"`
+}
+
+// ConnectivityResult is the result of connecting from a source to a target.
+type ConnectivityResult struct {
+	Status        ConnectivityStatus `json:"status"`
+	LatencyMillis int64              `json:"latencyMillis"`
+	Error         string             `json:"error,omitempty"`
+}
+
+// ConnectivityMatrix is the result of a connectivity test.
+type ConnectivityMatrix struct {
+	Sources []ConnectivityPeer   `json:"sources"`
+	Targets []ConnectivityTarget `json:"targets"`
+	// TargetPods are pods connected to for every target. They are nil for targets without a running pod.
+	TargetPods []*PodHealth `json:"targetPods"`
+	// Results has a row for every source with a result for every target.
+	Results [][]ConnectivityResult `json:"results"`
+
+	// List of non-critical errors, that occurred during the test.
+	Errors []error `json:"errors"`
+}
+
+// validate checks that the test is not too large and peers are valid.
+func (self ConnectivitySpec) validate() error {
+	if len(self.Sources) == 0 || len(self.Targets) == 0 {
+		return errors.NewBadRequest("at least one source and one target are required")
+	}
+
+	if len(self.Sources) > maxProbeSources || len(self.Targets) > maxProbeTargets {
+		return errors.NewBadRequest(fmt.Sprintf("at most %d sources and %d targets can be tested",
+			maxProbeSources, maxProbeTargets))
+	}
+
+	peers := append([]ConnectivityPeer{}, self.Sources...)
+	for _, target := range self.Targets {
+		if target.Port < 1 || target.Port > 65535 {
+			return errors.NewBadRequest(fmt.Sprintf("invalid port %d", target.Port))
+		}
+		peers = append(peers, target.ConnectivityPeer)
+	}
+
+	for _, peer := range peers {
+		if msgs := validation.IsDNS1123Label(peer.Namespace); len(msgs) > 0 {
+			return errors.NewBadRequest(fmt.Sprintf("invalid namespace %q: %s", peer.Namespace,
+				strings.Join(msgs, ", ")))
+		}
+
+		if err := validateLabels(peer.Labels); err != nil {
+			return errors.NewBadRequest(err.Error())
+		}
+	}
+
+	return nil
+}
+
+func validateLabels(values map[string]string) error {
+	for key, value := range values {
+		if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, strings.Join(msgs, ", "))
+		}
+		if msgs := validation.IsValidLabelValue(value); len(msgs) > 0 {
+			return fmt.Errorf("invalid label value %q: %s", value, strings.Join(msgs, ", "))
+		}
+	}
+	return nil
+}
+
+// probeTarget is a resolved target connected to by probe pods.
+type probeTarget struct {
+	index int
+	ip    string
+	port  int32
+}
+
+// CheckConnectivity starts a probe pod for every source, which connects to a running pod of every target, and
+// returns the results as a matrix. Probe pods are created with given client, so users can test only namespaces
+// in which they are allowed to create pods. Their output is read with given logs function.
+func CheckConnectivity(client kubernetes.Interface, spec ConnectivitySpec, logs LogsFunc) (*ConnectivityMatrix,
+	error) {
+	return checkConnectivity(client, spec, logs, probeTimeout)
+}
+
+func checkConnectivity(client kubernetes.Interface, spec ConnectivitySpec, logs LogsFunc,
+	timeout time.Duration) (*ConnectivityMatrix, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	image := spec.Image
+	if len(image) == 0 {
+		image = DefaultProbeImage
+	}
+
+	matrix := &ConnectivityMatrix{
+		Sources:    spec.Sources,
+		Targets:    spec.Targets,
+		TargetPods: make([]*PodHealth, len(spec.Targets)),
+		Results:    make([][]ConnectivityResult, len(spec.Sources)),
+		Errors:     make([]error, 0),
+	}
+
+	targets := make([]probeTarget, 0)
+	for i, target := range spec.Targets {
+		pod, err := findTargetPod(client, target.ConnectivityPeer)
+		nonCriticalErrors, criticalError := errors.AppendError(err, matrix.Errors)
+		if criticalError != nil {
+			return nil, criticalError
+		}
+		matrix.Errors = nonCriticalErrors
+
+		if pod != nil {
+			health := toPodHealth(*pod)
+			matrix.TargetPods[i] = &health
+			targets = append(targets, probeTarget{index: i, ip: pod.Status.PodIP, port: target.Port})
+		}
+	}
+
+	id := strconv.FormatInt(time.Now().UnixNano(), 36)
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+	for i, source := range spec.Sources {
+		row := make([]ConnectivityResult, len(spec.Targets))
+		for j := range row {
+			row[j] = ConnectivityResult{Status: ConnectivityUnknown, Error: "target has no running pod"}
+		}
+		matrix.Results[i] = row
+		if len(targets) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(source ConnectivityPeer, row []ConnectivityResult) {
+			defer wg.Done()
+			if err := runProbe(client, logs, id, image, source, targets, row, timeout); err != nil {
+				mux.Lock()
+				matrix.Errors = append(matrix.Errors, err)
+				mux.Unlock()
+			}
+		}(source, row)
+	}
+
+	wg.Wait()
+	return matrix, nil
+}
+
+// findTargetPod returns the first running and ready pod of the peer or nil, if there is no such pod.
+func findTargetPod(client kubernetes.Interface, peer ConnectivityPeer) (*v1.Pod, error) {
+	pods, err := client.CoreV1().Pods(peer.Namespace).List(context.TODO(),
+		metaV1.ListOptions{LabelSelector: labels.SelectorFromSet(peer.Labels).String()})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		health := toPodHealth(*pod)
+		if pod.Status.Phase == v1.PodRunning && health.Ready && net.ParseIP(pod.Status.PodIP) != nil {
+			return pod, nil
+		}
+	}
+	return nil, nil
+}
+
+// runProbe runs a probe pod of the source and fills its row of results. The pod is owned by a config map, which
+// keeps controllers of the source workload from adopting it and lets garbage collector remove it, even if
+// Dashboard stops during the test.
+func runProbe(client kubernetes.Interface, logs LogsFunc, id, image string, source ConnectivityPeer,
+	targets []probeTarget, row []ConnectivityResult, timeout time.Duration) error {
+	setAll := func(err string) {
+		for _, target := range targets {
+			row[target.index] = ConnectivityResult{Status: ConnectivityUnknown, Error: err}
+		}
+	}
+
+	owner, err := client.CoreV1().ConfigMaps(source.Namespace).Create(context.TODO(), &v1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			GenerateName: "connectivity-probe-",
+			Labels:       map[string]string{ConnectivityProbeLabel: id},
+		},
+	}, metaV1.CreateOptions{})
+	if err != nil {
+		setAll(err.Error())
+		return err
+	}
+
+	defer func() {
+		propagation := metaV1.DeletePropagationBackground
+		_ = client.CoreV1().ConfigMaps(source.Namespace).Delete(context.TODO(), owner.Name,
+			metaV1.DeleteOptions{PropagationPolicy: &propagation})
+	}()
+
+	pod, err := client.CoreV1().Pods(source.Namespace).Create(context.TODO(),
+		toProbePod(id, image, source, owner, targets, timeout), metaV1.CreateOptions{})
+	if err != nil {
+		setAll(err.Error())
+		return err
+	}
+
+	defer func() {
+		_ = client.CoreV1().Pods(source.Namespace).Delete(context.TODO(), pod.Name, metaV1.DeleteOptions{})
+	}()
+
+	if err := waitForProbe(client, pod, timeout); err != nil {
+		setAll(err.Error())
+		return nil
+	}
+
+	raw, err := logs(client, pod.Namespace, pod.Name)
+	if err != nil {
+		setAll(err.Error())
+		return err
+	}
+
+	results := parseProbeOutput(raw)
+	for _, target := range targets {
+		if result, ok := results[target.index]; ok {
+			row[target.index] = result
+		} else {
+			row[target.index] = ConnectivityResult{Status: ConnectivityUnknown, Error: "probe did not report result"}
+		}
+	}
+	return nil
+}
+
+// ReadLogs returns output of given finished pod. Output longer than maxProbeOutputBytes is cut.
+func ReadLogs(client kubernetes.Interface, namespace, name string) ([]byte, error) {
+	limit := int64(maxProbeOutputBytes)
+	return client.CoreV1().Pods(namespace).GetLogs(name, &v1.PodLogOptions{LimitBytes: &limit}).
+		DoRaw(context.TODO())
+}
+
+// waitForProbe waits until the probe pod finishes.
+func waitForProbe(client kubernetes.Interface, pod *v1.Pod, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		current, err := client.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		switch current.Status.Phase {
+		case v1.PodSucceeded:
+			return nil
+		case v1.PodFailed:
+			return fmt.Errorf("probe pod failed: %s", current.Status.Message)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("probe pod did not finish in %s", timeout)
+		}
+		time.Sleep(probePollInterval)
+	}
+}
+
+// toProbePod returns a pod with labels of the source, that connects to all targets. Its readiness probe never
+// succeeds, so services of the source do not send traffic to it.
+func toProbePod(id, image string, source ConnectivityPeer, owner *v1.ConfigMap, targets []probeTarget,
+	timeout time.Duration) *v1.Pod {
+	podLabels := map[string]string{ConnectivityProbeLabel: id}
+	for key, value := range source.Labels {
+		podLabels[key] = value
+	}
+
+	controller := true
+	automount := false
+	deadline := int64(timeout / time.Second)
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{
+			GenerateName: "connectivity-probe-",
+			Labels:       podLabels,
+			OwnerReferences: []metaV1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       owner.Name,
+				UID:        owner.UID,
+				Controller: &controller,
+			}},
+		},
+		Spec: v1.PodSpec{
+			RestartPolicy:                v1.RestartPolicyNever,
+			ActiveDeadlineSeconds:        &deadline,
+			AutomountServiceAccountToken: &automount,
+			Containers: []v1.Container{{
+				Name:    "probe",
+				Image:   image,
+				Command: []string{"sh", "-c", toProbeScript(targets)},
+				ReadinessProbe: &v1.Probe{
+					Handler: v1.Handler{Exec: &v1.ExecAction{Command: []string{"false"}}},
+				},
+			}},
+		},
+	}
+}
+
+// toProbeScript returns a shell script, that prints a line with the index, exit code of nc and start and end
+// time in nanoseconds for every target. Addresses are parsed IPs, so they are safe to use in the script.
+func toProbeScript(targets []probeTarget) string {
+	var script strings.Builder
+	for _, target := range targets {
+		fmt.Fprintf(&script, "start=$(date +%%s%%N); nc -z -w %d %s %d; code=$?; end=$(date +%%s%%N); "+
+			"echo %s %d $code $start $end\n", connectTimeoutSeconds, target.ip, target.port, probeResultPrefix,
+			target.index)
+	}
+	return script.String()
+}
+
+// parseProbeOutput returns results printed by a probe pod mapped by indexes of their targets. Latency is not
+// known if date of the probe image does not support nanoseconds.
+func parseProbeOutput(raw []byte) map[int]ConnectivityResult {
+	result := make(map[int]ConnectivityResult)
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 || fields[0] != probeResultPrefix {
+			continue
+		}
+
+		index, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+
+		status := ConnectivityResult{Status: ConnectivityPass}
+		if fields[2] != "0" {
+			status = ConnectivityResult{Status: ConnectivityFail, Error: "connection refused or timed out"}
+		}
+
+		start, startErr := strconv.ParseInt(fields[3], 10, 64)
+		end, endErr := strconv.ParseInt(fields[4], 10, 64)
+		if startErr == nil && endErr == nil && end >= start {
+			status.LatencyMillis = (end - start) / int64(time.Millisecond)
+		}
+		result[index] = status
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckConnectivity(t *testing.T) {
+	web := newNetworkPod("team-a", "web-1", "node-1", map[string]string{"app": "web"}, true, 0)
+	web.Status.PodIP = "10.0.0.10"
+	client := fake.NewSimpleClientset(web)
+
+	var probes []*v1.Pod
+	client.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		object := action.(k8stesting.CreateAction).GetObject().(metaV1.Object)
+		object.SetName(object.GetGenerateName() + "test")
+		if pod, ok := object.(*v1.Pod); ok {
+			pod.Namespace = action.GetNamespace()
+			probes = append(probes, pod)
+		}
+		return false, nil, nil
+	})
+	client.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &v1.Pod{Status: v1.PodStatus{Phase: v1.PodSucceeded}}, nil
+	})
+
+	spec := ConnectivitySpec{
+		Sources: []ConnectivityPeer{{Namespace: "team-b", Labels: map[string]string{"app": "api"}}},
+		Targets: []ConnectivityTarget{
+			{ConnectivityPeer: ConnectivityPeer{Namespace: "team-a", Labels: map[string]string{"app": "web"}}, Port: 80},
+			{ConnectivityPeer: ConnectivityPeer{Namespace: "team-a", Labels: map[string]string{"app": "db"}}, Port: 5432},
+		},
+	}
+	logs := func(client kubernetes.Interface, namespace, name string) ([]byte, error) {
+		return []byte("RESULT 0 0 1000000000 1003000000\n"), nil
+	}
+	matrix, err := checkConnectivity(client, spec, logs, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if matrix.TargetPods[0] == nil || matrix.TargetPods[0].Name != "web-1" || matrix.TargetPods[1] != nil {
+		t.Errorf("expected only web-1 target pod, got %v", matrix.TargetPods)
+	}
+
+	expected := [][]ConnectivityResult{{
+		{Status: ConnectivityPass, LatencyMillis: 3},
+		{Status: ConnectivityUnknown, Error: "target has no running pod"},
+	}}
+	if !reflect.DeepEqual(matrix.Results, expected) {
+		t.Errorf("expected results %v, got %v", expected, matrix.Results)
+	}
+
+	if len(probes) != 1 {
+		t.Fatalf("expected one probe pod, got %d", len(probes))
+	}
+
+	probe := probes[0]
+	if probe.Namespace != "team-b" || probe.Labels["app"] != "api" || len(probe.Labels[ConnectivityProbeLabel]) == 0 {
+		t.Errorf("expected probe pod with labels of the source, got %s/%s %v", probe.Namespace, probe.Name,
+			probe.Labels)
+	}
+
+	if refs := probe.OwnerReferences; len(refs) != 1 || refs[0].Kind != "ConfigMap" || !*refs[0].Controller {
+		t.Errorf("expected probe pod to be controlled by a config map, got %v", refs)
+	}
+
+	if script := probe.Spec.Containers[0].Command[2]; !strings.Contains(script, "nc -z -w 2 10.0.0.10 80") ||
+		strings.Contains(script, "5432") {
+		t.Errorf("unexpected probe script %q", script)
+	}
+
+	deleted := make(map[string]bool)
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "delete" {
+			deleted[action.GetResource().Resource] = true
+		}
+	}
+	if !deleted["pods"] || !deleted["configmaps"] {
+		t.Errorf("expected probe pod and its owner to be deleted, got %v", deleted)
+	}
+}
+
+func TestConnectivitySpec_Validate(t *testing.T) {
+	source := ConnectivityPeer{Namespace: "default", Labels: map[string]string{"app": "web"}}
+	target := ConnectivityTarget{ConnectivityPeer: source, Port: 80}
+
+	cases := []struct {
+		info  string
+		spec  ConnectivitySpec
+		valid bool
+	}{
+		{"valid", ConnectivitySpec{Sources: []ConnectivityPeer{source}, Targets: []ConnectivityTarget{target}}, true},
+		{"no targets", ConnectivitySpec{Sources: []ConnectivityPeer{source}}, false},
+		{"invalid port", ConnectivitySpec{Sources: []ConnectivityPeer{source},
+			Targets: []ConnectivityTarget{{ConnectivityPeer: source, Port: 70000}}}, false},
+		{"invalid namespace", ConnectivitySpec{Sources: []ConnectivityPeer{{Namespace: "Default"}},
+			Targets: []ConnectivityTarget{target}}, false},
+		{"invalid label", ConnectivitySpec{
+			Sources: []ConnectivityPeer{{Namespace: "default", Labels: map[string]string{"app": "a b"}}},
+			Targets: []ConnectivityTarget{target}}, false},
+		{"too many sources", ConnectivitySpec{
+			Sources: []ConnectivityPeer{source, source, source, source, source, source},
+			Targets: []ConnectivityTarget{target}}, false},
+	}
+
+	for _, c := range cases {
+		if err := c.spec.validate(); (err == nil) != c.valid {
+			t.Errorf("%s: expected valid to be %t, got error %v", c.info, c.valid, err)
+		}
+	}
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	raw := []byte("RESULT 0 0 1000000000 1012000000\n" +
+		"nc: bad address\n" +
+		"RESULT 2 1 1000000000 3000000000\n" +
+		"RESULT 3 0 %s%N %s%N\n")
+
+	expected := map[int]ConnectivityResult{
+		0: {Status: ConnectivityPass, LatencyMillis: 12},
+		2: {Status: ConnectivityFail, LatencyMillis: 2000, Error: "connection refused or timed out"},
+		3: {Status: ConnectivityPass},
+	}
+	if actual := parseProbeOutput(raw); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
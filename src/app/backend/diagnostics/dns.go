@@ -0,0 +1,336 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// DNSNamespace is the namespace of the cluster DNS.
+	DNSNamespace = "kube-system"
+	// DNSServiceName is the name of the cluster DNS service. CoreDNS keeps the name of kube-dns for compatibility.
+	DNSServiceName = "kube-dns"
+	// DNSSelector selects CoreDNS deployments and pods.
+	DNSSelector = "k8s-app=kube-dns"
+	// DefaultClusterDomain is used in cluster names resolved when no cluster domain is given.
+	DefaultClusterDomain = "cluster.local"
+	// DefaultExternalName is resolved to check that names outside of the cluster are forwarded.
+	DefaultExternalName = "kubernetes.io"
+
+	// Maximum number of names resolved in a single diagnosis besides the default ones.
+	maxNames = 5
+	// Time after which a single resolution is reported as failed.
+	resolutionTimeout = 2 * time.Second
+	// Resolutions slower than this are reported as problems.
+	slowResolution = 500 * time.Millisecond
+	// Restarts of a DNS pod from which they are reported as problems.
+	restartThreshold = 3
+)
+
+// LookupFunc resolves addresses of given name with given DNS server in 'host:port' format.
+type LookupFunc func(ctx context.Context, server, name string) ([]string, error)
+
+// DNSDeployment describes health of a CoreDNS deployment.
+type DNSDeployment struct {
+	Name      string `json:"name"`
+	Replicas  int32  `json:"replicas"`
+	Ready     int32  `json:"ready"`
+	Available int32  `json:"available"`
+}
+
+// DNSService describes the cluster DNS service and the servers behind it.
+type DNSService struct {
+	ClusterIP string `json:"clusterIP"`
+	// ReadyServers and NotReadyServers are endpoint addresses in 'host:port' format.
+	ReadyServers    []string `json:"readyServers"`
+	NotReadyServers []string `json:"notReadyServers"`
+}
+
+// Resolution is the result of resolving a single name with a single DNS server.
+type Resolution struct {
+	Name string `json:"name"`
+	// Server is the cluster DNS service address or address of one of the pods behind it.
+	Server        string   `json:"server"`
+	Addresses     []string `json:"addresses"`
+	LatencyMillis int64    `json:"latencyMillis"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// DNSReport is the result of cluster DNS diagnosis.
+type DNSReport struct {
+	// Healthy is true when no problem was found.
+	Healthy     bool            `json:"healthy"`
+	Problems    []string        `json:"problems"`
+	Deployments []DNSDeployment `json:"deployments"`
+	Pods        []PodHealth     `json:"pods"`
+	Service     *DNSService     `json:"service"`
+	Resolutions []Resolution    `json:"resolutions"`
+
+	// List of non-critical errors, that occurred during diagnosis.
+	Errors []error `json:"errors"`
+}
+
+// DNSSpec holds names resolved during the diagnosis besides the default ones.
+type DNSSpec struct {
+	// ClusterDomain is the domain of cluster names. Defaults to DefaultClusterDomain.
+	ClusterDomain string
+	Names         []string
+}
+
+// Returns absolute names resolved during the diagnosis, so search domains of the resolver are not applied.
+func (self DNSSpec) names() ([]string, error) {
+	clusterDomain := self.ClusterDomain
+	if len(clusterDomain) == 0 {
+		clusterDomain = DefaultClusterDomain
+	}
+
+	if len(self.Names) > maxNames {
+		return nil, errors.NewBadRequest(fmt.Sprintf("at most %d names can be resolved", maxNames))
+	}
+
+	result := make([]string, 0)
+	for _, name := range append([]string{"kubernetes.default.svc." + clusterDomain, DefaultExternalName},
+		self.Names...) {
+		name = strings.TrimSuffix(name, ".")
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid name %s: %s", name, strings.Join(msgs, ", ")))
+		}
+
+		result = append(result, name+".")
+	}
+
+	return result, nil
+}
+
+// DiagnoseDNS checks health of CoreDNS and resolves cluster and external names with the cluster DNS service and
+// with every server behind it, so failing replicas can be told apart. Errors reading DNS objects are non-critical,
+// only the service is required to resolve names.
+func DiagnoseDNS(client kubernetes.Interface, spec DNSSpec, lookup LookupFunc) (*DNSReport, error) {
+	names, err := spec.names()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DNSReport{
+		Problems:    make([]string, 0),
+		Deployments: make([]DNSDeployment, 0),
+		Pods:        make([]PodHealth, 0),
+		Resolutions: make([]Resolution, 0),
+		Errors:      make([]error, 0),
+	}
+
+	service, err := client.CoreV1().Services(DNSNamespace).Get(context.TODO(), DNSServiceName, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Service = &DNSService{ClusterIP: service.Spec.ClusterIP, ReadyServers: []string{},
+		NotReadyServers: []string{}}
+	endpoints, err := client.CoreV1().Endpoints(DNSNamespace).Get(context.TODO(), DNSServiceName, metaV1.GetOptions{})
+	if err != nil {
+		report.Errors = append(report.Errors, errors.LocalizeError(err))
+	} else {
+		report.Service.ReadyServers, report.Service.NotReadyServers = getDNSServers(endpoints)
+	}
+
+	if err := report.addDeployments(client); err != nil {
+		report.Errors = append(report.Errors, errors.LocalizeError(err))
+	}
+
+	if err := report.addPods(client); err != nil {
+		report.Errors = append(report.Errors, errors.LocalizeError(err))
+	}
+
+	servers := report.Service.ReadyServers
+	if ip := service.Spec.ClusterIP; len(ip) > 0 && ip != v1.ClusterIPNone {
+		servers = append([]string{net.JoinHostPort(ip, strconv.Itoa(int(getDNSPort(service.Spec.Ports))))},
+			servers...)
+	}
+
+	report.Resolutions = resolve(servers, names, lookup)
+	report.addProblems()
+	return report, nil
+}
+
+func (self *DNSReport) addDeployments(client kubernetes.Interface) error {
+	list, err := client.AppsV1().Deployments(DNSNamespace).List(context.TODO(),
+		metaV1.ListOptions{LabelSelector: DNSSelector})
+	if err != nil {
+		return err
+	}
+
+	for _, deployment := range list.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		self.Deployments = append(self.Deployments, DNSDeployment{
+			Name:      deployment.Name,
+			Replicas:  replicas,
+			Ready:     deployment.Status.ReadyReplicas,
+			Available: deployment.Status.AvailableReplicas,
+		})
+	}
+
+	return nil
+}
+
+func (self *DNSReport) addPods(client kubernetes.Interface) error {
+	list, err := client.CoreV1().Pods(DNSNamespace).List(context.TODO(), metaV1.ListOptions{LabelSelector: DNSSelector})
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range list.Items {
+		self.Pods = append(self.Pods, toPodHealth(pod))
+	}
+
+	return nil
+}
+
+// Adds human readable descriptions of everything that is wrong to the report.
+func (self *DNSReport) addProblems() {
+	for _, deployment := range self.Deployments {
+		if deployment.Available < deployment.Replicas {
+			self.Problems = append(self.Problems, fmt.Sprintf("deployment %s has %d of %d replicas available",
+				deployment.Name, deployment.Available, deployment.Replicas))
+		}
+	}
+
+	for _, pod := range self.Pods {
+		if !pod.Ready {
+			self.Problems = append(self.Problems, fmt.Sprintf("pod %s on node %s is not ready", pod.Name,
+				pod.NodeName))
+		}
+		if pod.Restarts >= restartThreshold {
+			self.Problems = append(self.Problems, fmt.Sprintf("pod %s restarted %d times", pod.Name, pod.Restarts))
+		}
+	}
+
+	if len(self.Service.ReadyServers) == 0 {
+		self.Problems = append(self.Problems, fmt.Sprintf("service %s has no ready endpoints", DNSServiceName))
+	}
+
+	for _, resolution := range self.Resolutions {
+		if len(resolution.Error) > 0 {
+			self.Problems = append(self.Problems, fmt.Sprintf("resolving %s with %s failed: %s", resolution.Name,
+				resolution.Server, resolution.Error))
+		} else if resolution.LatencyMillis >= int64(slowResolution/time.Millisecond) {
+			self.Problems = append(self.Problems, fmt.Sprintf("resolving %s with %s took %dms", resolution.Name,
+				resolution.Server, resolution.LatencyMillis))
+		}
+	}
+
+	self.Healthy = len(self.Problems) == 0
+}
+
+// Returns ready and not ready addresses of DNS servers behind the service in 'host:port' format.
+func getDNSServers(endpoints *v1.Endpoints) (ready []string, notReady []string) {
+	ready, notReady = []string{}, []string{}
+	for _, subset := range endpoints.Subsets {
+		port := strconv.Itoa(int(getDNSEndpointPort(subset.Ports)))
+		for _, address := range subset.Addresses {
+			ready = append(ready, net.JoinHostPort(address.IP, port))
+		}
+		for _, address := range subset.NotReadyAddresses {
+			notReady = append(notReady, net.JoinHostPort(address.IP, port))
+		}
+	}
+
+	sort.Strings(ready)
+	sort.Strings(notReady)
+	return ready, notReady
+}
+
+// Returns UDP port of the DNS service. Standard port is used if there is none.
+func getDNSPort(ports []v1.ServicePort) int32 {
+	for _, port := range ports {
+		if port.Protocol == v1.ProtocolUDP {
+			return port.Port
+		}
+	}
+
+	return 53
+}
+
+// Returns UDP port of the DNS servers behind the service. Standard port is used if there is none.
+func getDNSEndpointPort(ports []v1.EndpointPort) int32 {
+	for _, port := range ports {
+		if port.Protocol == v1.ProtocolUDP {
+			return port.Port
+		}
+	}
+
+	return 53
+}
+
+// Resolves all names with all servers in parallel, so a diagnosis takes at most a single resolution timeout.
+func resolve(servers, names []string, lookup LookupFunc) []Resolution {
+	result := make([]Resolution, len(servers)*len(names))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		for j, name := range names {
+			result[i*len(names)+j] = Resolution{Name: name, Server: server, Addresses: []string{}}
+			wg.Add(1)
+			go func(resolution *Resolution) {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), resolutionTimeout)
+				defer cancel()
+
+				start := time.Now()
+				addresses, err := lookup(ctx, resolution.Server, resolution.Name)
+				resolution.LatencyMillis = int64(time.Since(start) / time.Millisecond)
+				if err != nil {
+					resolution.Error = err.Error()
+					return
+				}
+
+				resolution.Addresses = addresses
+			}(&result[i*len(names)+j])
+		}
+	}
+
+	wg.Wait()
+	return result
+}
+
+// LookupHost resolves name with given DNS server only, bypassing resolver configuration of Dashboard.
+func LookupHost(ctx context.Context, server, name string) ([]string, error) {
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{}
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+
+	return resolver.LookupHost(ctx, name)
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newDNSObjects(readyReplicas int32, restarts int32) []runtime.Object {
+	replicas := int32(2)
+	labels := map[string]string{"k8s-app": "kube-dns"}
+	return []runtime.Object{
+		&v1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: DNSServiceName, Namespace: DNSNamespace},
+			Spec: v1.ServiceSpec{ClusterIP: "10.96.0.10", Ports: []v1.ServicePort{
+				{Name: "dns-tcp", Port: 53, Protocol: v1.ProtocolTCP},
+				{Name: "dns", Port: 53, Protocol: v1.ProtocolUDP},
+			}},
+		},
+		&v1.Endpoints{
+			ObjectMeta: metaV1.ObjectMeta{Name: DNSServiceName, Namespace: DNSNamespace},
+			Subsets: []v1.EndpointSubset{{
+				Addresses:         []v1.EndpointAddress{{IP: "10.244.0.3"}},
+				NotReadyAddresses: []v1.EndpointAddress{{IP: "10.244.1.4"}},
+				Ports:             []v1.EndpointPort{{Name: "dns", Port: 53, Protocol: v1.ProtocolUDP}},
+			}},
+		},
+		&appsV1.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "coredns", Namespace: DNSNamespace, Labels: labels},
+			Spec:       appsV1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsV1.DeploymentStatus{ReadyReplicas: readyReplicas, AvailableReplicas: readyReplicas},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "coredns-1", Namespace: DNSNamespace, Labels: labels},
+			Spec:       v1.PodSpec{NodeName: "node-1"},
+			Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				PodIP:             "10.244.0.3",
+				Conditions:        []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+				ContainerStatuses: []v1.ContainerStatus{{Name: "coredns", RestartCount: restarts}},
+			},
+		},
+	}
+}
+
+func TestDiagnoseDNS(t *testing.T) {
+	lookup := func(ctx context.Context, server, name string) ([]string, error) {
+		if server == "10.244.0.3:53" && name == "kubernetes.io." {
+			return nil, errors.New("i/o timeout")
+		}
+		return []string{"10.96.0.1"}, nil
+	}
+
+	client := fake.NewSimpleClientset(newDNSObjects(1, 5)...)
+	report, err := DiagnoseDNS(client, DNSSpec{Names: []string{"my-service.my-namespace.svc.cluster.local"}}, lookup)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedService := &DNSService{
+		ClusterIP:       "10.96.0.10",
+		ReadyServers:    []string{"10.244.0.3:53"},
+		NotReadyServers: []string{"10.244.1.4:53"},
+	}
+	if !reflect.DeepEqual(report.Service, expectedService) {
+		t.Errorf("Expected service %#v, but got %#v.", expectedService, report.Service)
+	}
+
+	if len(report.Resolutions) != 6 {
+		t.Fatalf("Expected 3 names resolved with 2 servers, but got %#v.", report.Resolutions)
+	}
+	if resolution := report.Resolutions[4]; resolution.Server != "10.244.0.3:53" ||
+		resolution.Name != "kubernetes.io." || resolution.Error != "i/o timeout" {
+		t.Errorf("Expected failed resolution of kubernetes.io. with the pod, but got %#v.", resolution)
+	}
+
+	expectedProblems := []string{
+		"deployment coredns has 1 of 2 replicas available",
+		"pod coredns-1 restarted 5 times",
+		"resolving kubernetes.io. with 10.244.0.3:53 failed: i/o timeout",
+	}
+	if report.Healthy || !reflect.DeepEqual(report.Problems, expectedProblems) {
+		t.Errorf("Expected problems %v, but got %v.", expectedProblems, report.Problems)
+	}
+}
+
+func TestDiagnoseDNSHealthy(t *testing.T) {
+	lookup := func(ctx context.Context, server, name string) ([]string, error) {
+		return []string{"10.96.0.1"}, nil
+	}
+
+	report, err := DiagnoseDNS(fake.NewSimpleClientset(newDNSObjects(2, 0)...), DNSSpec{}, lookup)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !report.Healthy || len(report.Problems) > 0 || len(report.Resolutions) != 4 {
+		t.Errorf("Expected healthy report with 4 resolutions, but got %#v.", report)
+	}
+}
+
+func TestDiagnoseDNSErrors(t *testing.T) {
+	lookup := func(ctx context.Context, server, name string) ([]string, error) {
+		t.Errorf("Unexpected resolution of %s with %s.", name, server)
+		return nil, nil
+	}
+
+	cases := []struct {
+		info     string
+		spec     DNSSpec
+		expected func(error) bool
+	}{
+		{"invalid name", DNSSpec{Names: []string{"in valid"}}, k8serrors.IsBadRequest},
+		{"invalid cluster domain", DNSSpec{ClusterDomain: "-cluster"}, k8serrors.IsBadRequest},
+		{"too many names", DNSSpec{Names: []string{"a", "b", "c", "d", "e", "f"}}, k8serrors.IsBadRequest},
+		{"missing service", DNSSpec{}, k8serrors.IsNotFound},
+	}
+
+	for _, c := range cases {
+		_, err := DiagnoseDNS(fake.NewSimpleClientset(), c.spec, lookup)
+		if !c.expected(err) {
+			t.Errorf("Test Case: %s. Unexpected error: %v", c.info, err)
+		}
+	}
+}
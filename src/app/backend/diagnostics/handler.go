@@ -0,0 +1,116 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Handler manages endpoints diagnosing common cluster problems.
+type Handler struct {
+	cManager clientapi.ClientManager
+	lookup   LookupFunc
+	logs     LogsFunc
+}
+
+// Install creates new endpoints for cluster diagnostics. Names resolved besides the default ones are given by
+// repeated 'name' query parameter.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/diagnostics/dns").
+			To(h.handleDiagnoseDNS).
+			Writes(DNSReport{}))
+	ws.Route(
+		ws.GET("/diagnostics/network").
+			To(h.handleDiagnoseNetwork).
+			Writes(NetworkReport{}))
+	ws.Route(
+		ws.POST("/diagnostics/connectivity").
+			To(h.handleCheckConnectivity).
+			Reads(ConnectivitySpec{}).
+			Writes(ConnectivityMatrix{}))
+}
+
+// NewDiagnosticsHandler creates diagnostics.Handler.
+func NewDiagnosticsHandler(cManager clientapi.ClientManager) *Handler {
+	return &Handler{cManager: cManager, lookup: LookupHost, logs: ReadLogs}
+}
+
+// handleDiagnoseDNS reads DNS objects with the client of the user, so only users allowed to see them can make
+// Dashboard resolve names.
+func (h *Handler) handleDiagnoseDNS(request *restful.Request, response *restful.Response) {
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	spec := DNSSpec{
+		ClusterDomain: request.QueryParameter("clusterDomain"),
+		Names:         request.QueryParameters("name"),
+	}
+	result, err := DiagnoseDNS(k8sClient, spec, h.lookup)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (h *Handler) handleDiagnoseNetwork(request *restful.Request, response *restful.Response) {
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := DiagnoseNetwork(k8sClient)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// handleCheckConnectivity creates probe pods with the client of the user, so only users allowed to create pods in
+// namespaces of the sources can test them.
+func (h *Handler) handleCheckConnectivity(request *restful.Request, response *restful.Response) {
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	spec := new(ConnectivitySpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := CheckConnectivity(k8sClient, *spec, h.logs)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
@@ -0,0 +1,336 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
+)
+
+const (
+	// KubeProxyNamespace is the namespace of kube-proxy.
+	KubeProxyNamespace = "kube-system"
+	// KubeProxyName is the name of the kube-proxy daemon set.
+	KubeProxyName = "kube-proxy"
+	// KubeProxySelector selects kube-proxy pods.
+	KubeProxySelector = "k8s-app=kube-proxy"
+
+	// Only warning events seen within this time are reported.
+	networkEventsWindow = time.Hour
+	// Maximum number of reported warning events.
+	maxNetworkEvents = 20
+)
+
+// CNIDaemonSets are names of daemon sets running agents of known CNI plugins on every node.
+var CNIDaemonSets = []string{
+	"calico-node", "cilium", "kube-flannel-ds", "weave-net", "canal", "antrea-agent", "kube-router", "aws-node",
+	"azure-cns", "ovnkube-node", "kube-ovn-cni", "kube-multus-ds",
+}
+
+// Reasons of warning events related to pod networking.
+var networkEventReasons = []string{
+	"FailedCreatePodSandBox", "FailedKillPodSandBox", "NetworkNotReady", "FailedToUpdateEndpoint",
+	"FailedToUpdateEndpointSlices", "CIDRNotAvailable", "CIDRAssignmentFailed", "SyncLoadBalancerFailed",
+}
+
+// Parts of messages of warning events related to pod networking.
+var networkEventMessages = []string{"cni", "network", "iptables", "ipvs"}
+
+// DaemonSetHealth describes rollout of a daemon set of a cluster component.
+type DaemonSetHealth struct {
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	Desired      int32  `json:"desired"`
+	Scheduled    int32  `json:"scheduled"`
+	Ready        int32  `json:"ready"`
+	Available    int32  `json:"available"`
+	Updated      int32  `json:"updated"`
+	Misscheduled int32  `json:"misscheduled"`
+}
+
+// NodeNetwork describes networking components running on a single node.
+type NodeNetwork struct {
+	Name string `json:"name"`
+	// NetworkUnavailable is true when the node reports that its network is not configured.
+	NetworkUnavailable bool        `json:"networkUnavailable"`
+	KubeProxy          *PodHealth  `json:"kubeProxy"`
+	CNI                []PodHealth `json:"cni"`
+}
+
+// NetworkReport is the result of cluster networking diagnosis.
+type NetworkReport struct {
+	// Healthy is true when no problem was found.
+	Healthy  bool     `json:"healthy"`
+	Problems []string `json:"problems"`
+	// KubeProxy is nil when kube-proxy is not deployed, i.e. because the CNI plugin replaces it.
+	KubeProxy *DaemonSetHealth `json:"kubeProxy"`
+	// CNI contains daemon sets of known CNI plugins found in the cluster.
+	CNI   []DaemonSetHealth `json:"cni"`
+	Nodes []NodeNetwork     `json:"nodes"`
+	// Events are recent networking related warning events, the newest first.
+	Events []common.Event `json:"events"`
+
+	// List of non-critical errors, that occurred during diagnosis.
+	Errors []error `json:"errors"`
+}
+
+// DiagnoseNetwork summarizes health of kube-proxy, agents of the CNI plugin on every node and recent networking
+// related warning events. Errors reading any of them are non-critical, so the rest is still reported.
+func DiagnoseNetwork(client kubernetes.Interface) (*NetworkReport, error) {
+	report := &NetworkReport{
+		Problems: make([]string, 0),
+		CNI:      make([]DaemonSetHealth, 0),
+		Nodes:    make([]NodeNetwork, 0),
+		Events:   make([]common.Event, 0),
+		Errors:   make([]error, 0),
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(context.TODO(), metaV1.ListOptions{})
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+	report.Errors = nonCriticalErrors
+
+	nodeNetworks := make(map[string]*NodeNetwork)
+	if nodes != nil {
+		for _, node := range nodes.Items {
+			nodeNetworks[node.Name] = &NodeNetwork{Name: node.Name, NetworkUnavailable: isNetworkUnavailable(node),
+				CNI: make([]PodHealth, 0)}
+		}
+	}
+
+	if err := report.addKubeProxy(client, nodeNetworks); err != nil {
+		report.Errors = append(report.Errors, errors.LocalizeError(err))
+	}
+
+	if err := report.addCNI(client, nodeNetworks); err != nil {
+		report.Errors = append(report.Errors, errors.LocalizeError(err))
+	}
+
+	if err := report.addEvents(client, time.Now()); err != nil {
+		report.Errors = append(report.Errors, errors.LocalizeError(err))
+	}
+
+	for _, nodeNetwork := range nodeNetworks {
+		report.Nodes = append(report.Nodes, *nodeNetwork)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Name < report.Nodes[j].Name })
+
+	report.addProblems()
+	return report, nil
+}
+
+func (self *NetworkReport) addKubeProxy(client kubernetes.Interface, nodeNetworks map[string]*NodeNetwork) error {
+	daemonSet, err := client.AppsV1().DaemonSets(KubeProxyNamespace).Get(context.TODO(), KubeProxyName,
+		metaV1.GetOptions{})
+	if errors.IsNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	health := toDaemonSetHealth(*daemonSet)
+	self.KubeProxy = &health
+	pods, err := client.CoreV1().Pods(KubeProxyNamespace).List(context.TODO(),
+		metaV1.ListOptions{LabelSelector: KubeProxySelector})
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		if nodeNetwork, ok := nodeNetworks[pod.Spec.NodeName]; ok {
+			podHealth := toPodHealth(pod)
+			nodeNetwork.KubeProxy = &podHealth
+		}
+	}
+
+	return nil
+}
+
+func (self *NetworkReport) addCNI(client kubernetes.Interface, nodeNetworks map[string]*NodeNetwork) error {
+	daemonSets, err := client.AppsV1().DaemonSets(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, daemonSet := range daemonSets.Items {
+		if !isCNIDaemonSet(daemonSet.Name) {
+			continue
+		}
+
+		self.CNI = append(self.CNI, toDaemonSetHealth(daemonSet))
+		selector, err := metaV1.LabelSelectorAsSelector(daemonSet.Spec.Selector)
+		if err != nil {
+			return err
+		}
+
+		pods, err := client.CoreV1().Pods(daemonSet.Namespace).List(context.TODO(),
+			metaV1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return err
+		}
+
+		for _, pod := range pods.Items {
+			if nodeNetwork, ok := nodeNetworks[pod.Spec.NodeName]; ok {
+				nodeNetwork.CNI = append(nodeNetwork.CNI, toPodHealth(pod))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (self *NetworkReport) addEvents(client kubernetes.Interface, now time.Time) error {
+	list, err := client.CoreV1().Events(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("type", v1.EventTypeWarning).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	events := make([]v1.Event, 0)
+	for _, item := range list.Items {
+		if now.Sub(getEventTime(item)) <= networkEventsWindow && isNetworkEvent(item) {
+			events = append(events, item)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return getEventTime(events[i]).After(getEventTime(events[j])) })
+	if len(events) > maxNetworkEvents {
+		events = events[:maxNetworkEvents]
+	}
+
+	for _, item := range events {
+		self.Events = append(self.Events, event.ToEvent(item))
+	}
+
+	return nil
+}
+
+// Adds human readable descriptions of everything that is wrong to the report.
+func (self *NetworkReport) addProblems() {
+	daemonSets := self.CNI
+	if self.KubeProxy != nil {
+		daemonSets = append([]DaemonSetHealth{*self.KubeProxy}, daemonSets...)
+	}
+
+	for _, daemonSet := range daemonSets {
+		if daemonSet.Available < daemonSet.Desired {
+			self.Problems = append(self.Problems, fmt.Sprintf("daemon set %s/%s has %d of %d pods available",
+				daemonSet.Namespace, daemonSet.Name, daemonSet.Available, daemonSet.Desired))
+		}
+	}
+
+	for _, node := range self.Nodes {
+		if node.NetworkUnavailable {
+			self.Problems = append(self.Problems, fmt.Sprintf("network of node %s is unavailable", node.Name))
+		}
+
+		pods := node.CNI
+		if node.KubeProxy != nil {
+			pods = append([]PodHealth{*node.KubeProxy}, pods...)
+		}
+
+		for _, pod := range pods {
+			if !pod.Ready {
+				self.Problems = append(self.Problems, fmt.Sprintf("pod %s/%s on node %s is not ready",
+					pod.Namespace, pod.Name, node.Name))
+			}
+			if pod.Restarts >= restartThreshold {
+				self.Problems = append(self.Problems, fmt.Sprintf("pod %s/%s restarted %d times", pod.Namespace,
+					pod.Name, pod.Restarts))
+			}
+		}
+	}
+
+	self.Healthy = len(self.Problems) == 0
+}
+
+func toDaemonSetHealth(daemonSet appsV1.DaemonSet) DaemonSetHealth {
+	return DaemonSetHealth{
+		Namespace:    daemonSet.Namespace,
+		Name:         daemonSet.Name,
+		Desired:      daemonSet.Status.DesiredNumberScheduled,
+		Scheduled:    daemonSet.Status.CurrentNumberScheduled,
+		Ready:        daemonSet.Status.NumberReady,
+		Available:    daemonSet.Status.NumberAvailable,
+		Updated:      daemonSet.Status.UpdatedNumberScheduled,
+		Misscheduled: daemonSet.Status.NumberMisscheduled,
+	}
+}
+
+func isCNIDaemonSet(name string) bool {
+	for _, cniName := range CNIDaemonSets {
+		if name == cniName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isNetworkUnavailable(node v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeNetworkUnavailable {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func isNetworkEvent(item v1.Event) bool {
+	for _, reason := range networkEventReasons {
+		if item.Reason == reason {
+			return true
+		}
+	}
+
+	message := strings.ToLower(item.Message)
+	for _, part := range networkEventMessages {
+		if strings.Contains(message, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns time of the last occurrence of the event. Events recorded by newer clients only have event time set.
+func getEventTime(item v1.Event) time.Time {
+	switch {
+	case !item.LastTimestamp.IsZero():
+		return item.LastTimestamp.Time
+	case !item.EventTime.IsZero():
+		return item.EventTime.Time
+	}
+
+	return item.CreationTimestamp.Time
+}
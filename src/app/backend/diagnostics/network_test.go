@@ -0,0 +1,133 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newNetworkPod(namespace, name, nodeName string, labels map[string]string, ready bool,
+	restarts int32) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+		Status: v1.PodStatus{
+			Phase:             v1.PodRunning,
+			Conditions:        []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+			ContainerStatuses: []v1.ContainerStatus{{RestartCount: restarts}},
+		},
+	}
+}
+
+func newNetworkEvent(name, reason, message string, lastSeen time.Time) *v1.Event {
+	return &v1.Event{
+		ObjectMeta:    metaV1.ObjectMeta{Name: name, Namespace: "default"},
+		Type:          v1.EventTypeWarning,
+		Reason:        reason,
+		Message:       message,
+		LastTimestamp: metaV1.NewTime(lastSeen),
+	}
+}
+
+func TestDiagnoseNetwork(t *testing.T) {
+	now := time.Now()
+	proxyLabels := map[string]string{"k8s-app": "kube-proxy"}
+	calicoLabels := map[string]string{"k8s-app": "calico-node"}
+	objects := []runtime.Object{
+		&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1"}},
+		&v1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node-2"},
+			Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+				{Type: v1.NodeNetworkUnavailable, Status: v1.ConditionTrue},
+			}},
+		},
+		&appsV1.DaemonSet{
+			ObjectMeta: metaV1.ObjectMeta{Name: KubeProxyName, Namespace: KubeProxyNamespace},
+			Status:     appsV1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 2, NumberAvailable: 2},
+		},
+		&appsV1.DaemonSet{
+			ObjectMeta: metaV1.ObjectMeta{Name: "calico-node", Namespace: "calico-system"},
+			Spec:       appsV1.DaemonSetSpec{Selector: &metaV1.LabelSelector{MatchLabels: calicoLabels}},
+			Status:     appsV1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 1, NumberAvailable: 1},
+		},
+		&appsV1.DaemonSet{ObjectMeta: metaV1.ObjectMeta{Name: "fluentd", Namespace: "logging"}},
+		newNetworkPod(KubeProxyNamespace, "kube-proxy-1", "node-1", proxyLabels, true, 0),
+		newNetworkPod(KubeProxyNamespace, "kube-proxy-2", "node-2", proxyLabels, true, 0),
+		newNetworkPod("calico-system", "calico-node-1", "node-1", calicoLabels, true, 4),
+		newNetworkPod("calico-system", "calico-node-2", "node-2", calicoLabels, false, 0),
+		newNetworkEvent("sandbox", "FailedCreatePodSandBox", "failed to set up sandbox", now.Add(-time.Minute)),
+		newNetworkEvent("cni", "Failed", "CNI plugin not initialized", now.Add(-2*time.Minute)),
+		newNetworkEvent("old", "NetworkNotReady", "network is not ready", now.Add(-2*time.Hour)),
+		newNetworkEvent("image", "Failed", "failed to pull image", now.Add(-time.Minute)),
+	}
+
+	report, err := DiagnoseNetwork(fake.NewSimpleClientset(objects...))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.KubeProxy == nil || report.KubeProxy.Available != 2 {
+		t.Errorf("Expected kube-proxy daemon set, but got %#v.", report.KubeProxy)
+	}
+	if len(report.CNI) != 1 || report.CNI[0].Name != "calico-node" {
+		t.Errorf("Expected calico-node CNI daemon set, but got %#v.", report.CNI)
+	}
+	if len(report.Nodes) != 2 || report.Nodes[1].KubeProxy == nil || len(report.Nodes[1].CNI) != 1 ||
+		!report.Nodes[1].NetworkUnavailable {
+		t.Errorf("Expected networking of both nodes, but got %#v.", report.Nodes)
+	}
+
+	events := make([]string, 0)
+	for _, event := range report.Events {
+		events = append(events, event.ObjectMeta.Name)
+	}
+	if expected := []string{"sandbox", "cni"}; !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected events %v, but got %v.", expected, events)
+	}
+
+	expectedProblems := []string{
+		"daemon set calico-system/calico-node has 1 of 2 pods available",
+		"pod calico-system/calico-node-1 restarted 4 times",
+		"network of node node-2 is unavailable",
+		"pod calico-system/calico-node-2 on node node-2 is not ready",
+	}
+	if report.Healthy || !reflect.DeepEqual(report.Problems, expectedProblems) {
+		t.Errorf("Expected problems %v, but got %v.", expectedProblems, report.Problems)
+	}
+}
+
+func TestDiagnoseNetworkWithoutKubeProxy(t *testing.T) {
+	report, err := DiagnoseNetwork(fake.NewSimpleClientset(&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1"}}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.KubeProxy != nil || !report.Healthy || len(report.Nodes) != 1 || len(report.Errors) > 0 {
+		t.Errorf("Expected healthy report without kube-proxy, but got %#v.", report)
+	}
+}
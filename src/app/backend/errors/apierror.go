@@ -0,0 +1,181 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"context"
+	goerrors "errors"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrorCode is a machine-readable code of an API error. Clients should branch on codes instead of messages, which
+// may change or be localized.
+type ErrorCode string
+
+// Codes of API errors. They are a part of the API, so existing codes must not be changed.
+const (
+	CodeTokenExpired         ErrorCode = "TOKEN_EXPIRED"
+	CodeEncryptionKeyChanged ErrorCode = "ENCRYPTION_KEY_CHANGED"
+	CodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+	CodeForbidden            ErrorCode = "FORBIDDEN"
+	CodeNotFound             ErrorCode = "NOT_FOUND"
+	CodeAlreadyExists        ErrorCode = "ALREADY_EXISTS"
+	CodeConflict             ErrorCode = "CONFLICT"
+	CodeBadRequest           ErrorCode = "BAD_REQUEST"
+	CodeInvalid              ErrorCode = "INVALID"
+	CodeResultTruncated      ErrorCode = "RESULT_TRUNCATED"
+	CodeTooManyRequests      ErrorCode = "TOO_MANY_REQUESTS"
+	CodeTimeout              ErrorCode = "TIMEOUT"
+	CodeServiceUnavailable   ErrorCode = "SERVICE_UNAVAILABLE"
+	CodeInternal             ErrorCode = "INTERNAL"
+)
+
+// APIError is the envelope of errors returned by all endpoints of the API.
+type APIError struct {
+	// Code is a machine-readable code of the error.
+	Code ErrorCode `json:"code"`
+
+	// Status is the HTTP status code of the response.
+	Status int `json:"status"`
+
+	// Message is a human-readable description of the error or a MSG_* constant localized by the frontend.
+	Message string `json:"message"`
+
+	// Details describe individual causes of the error, i.e. invalid fields.
+	Details []ErrorDetail `json:"details,omitempty"`
+
+	// Retryable is true if the same request may succeed when it is retried later.
+	Retryable bool `json:"retryable"`
+}
+
+// ErrorDetail is a single cause of an API error.
+type ErrorDetail struct {
+	Type    string `json:"type,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Error implements error interface.
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// reasonCodes maps reasons of status errors to codes of API errors.
+var reasonCodes = map[metav1.StatusReason]ErrorCode{
+	metav1.StatusReasonUnauthorized:       CodeUnauthorized,
+	metav1.StatusReasonForbidden:          CodeForbidden,
+	metav1.StatusReasonNotFound:           CodeNotFound,
+	metav1.StatusReasonAlreadyExists:      CodeAlreadyExists,
+	metav1.StatusReasonConflict:           CodeConflict,
+	metav1.StatusReasonBadRequest:         CodeBadRequest,
+	metav1.StatusReasonInvalid:            CodeInvalid,
+	StatusReasonResultTruncated:           CodeResultTruncated,
+	metav1.StatusReasonTooManyRequests:    CodeTooManyRequests,
+	metav1.StatusReasonTimeout:            CodeTimeout,
+	metav1.StatusReasonServerTimeout:      CodeTimeout,
+	metav1.StatusReasonServiceUnavailable: CodeServiceUnavailable,
+	metav1.StatusReasonInternalError:      CodeInternal,
+}
+
+// statusCodes maps HTTP status codes of errors without a known reason to codes of API errors.
+var statusCodes = map[int]ErrorCode{
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnprocessableEntity:   CodeInvalid,
+	http.StatusRequestEntityTooLarge: CodeResultTruncated,
+	http.StatusTooManyRequests:       CodeTooManyRequests,
+	http.StatusGatewayTimeout:        CodeTimeout,
+	http.StatusServiceUnavailable:    CodeServiceUnavailable,
+}
+
+// retryableCodes are codes of errors, that may not occur again when the request is retried later.
+var retryableCodes = map[ErrorCode]bool{
+	CodeConflict:           true,
+	CodeTooManyRequests:    true,
+	CodeTimeout:            true,
+	CodeServiceUnavailable: true,
+}
+
+// NewAPIError converts given error to an API error. Errors with localized messages, that require users to log in
+// again, are unauthorized regardless of their status.
+func NewAPIError(err error) *APIError {
+	if err == nil {
+		return nil
+	}
+
+	if apiError, ok := err.(*APIError); ok {
+		return apiError
+	}
+
+	result := &APIError{Code: CodeInternal, Status: http.StatusInternalServerError, Message: err.Error()}
+	if statusError, ok := err.(*errors.StatusError); ok {
+		setStatus(result, statusError.ErrStatus)
+	} else if goerrors.Is(err, context.DeadlineExceeded) {
+		// Request exceeded its timeout, see 'request-timeout' and 'route-timeouts' arguments.
+		result.Code = CodeTimeout
+		result.Status = http.StatusServiceUnavailable
+	}
+
+	switch result.Message {
+	case MsgTokenExpiredError:
+		result.Code = CodeTokenExpired
+		result.Status = http.StatusUnauthorized
+	case MsgEncryptionKeyChanged:
+		result.Code = CodeEncryptionKeyChanged
+		result.Status = http.StatusUnauthorized
+	case MsgLoginUnauthorizedError:
+		result.Code = CodeUnauthorized
+		result.Status = http.StatusUnauthorized
+	}
+
+	result.Retryable = retryableCodes[result.Code]
+	return result
+}
+
+// NewAPIErrors converts given errors to API errors.
+func NewAPIErrors(errs []error) []*APIError {
+	result := make([]*APIError, 0, len(errs))
+	for _, err := range errs {
+		result = append(result, NewAPIError(err))
+	}
+	return result
+}
+
+func setStatus(result *APIError, status metav1.Status) {
+	if status.Code > 0 {
+		result.Status = int(status.Code)
+	}
+
+	if code, ok := reasonCodes[status.Reason]; ok {
+		result.Code = code
+	} else if code, ok := statusCodes[result.Status]; ok {
+		result.Code = code
+	}
+
+	if status.Details == nil {
+		return
+	}
+
+	for _, cause := range status.Details.Causes {
+		result.Details = append(result.Details,
+			ErrorDetail{Type: string(cause.Type), Field: cause.Field, Message: cause.Message})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+func TestNewAPIError(t *testing.T) {
+	resource := schema.GroupResource{Resource: "pods"}
+	cases := []struct {
+		err       error
+		code      errors.ErrorCode
+		status    int
+		retryable bool
+	}{
+		{errors.NewInvalid(errors.MsgTokenExpiredError), errors.CodeTokenExpired, http.StatusUnauthorized, false},
+		{errors.NewTokenExpired(errors.MsgTokenExpiredError), errors.CodeTokenExpired, http.StatusUnauthorized, false},
+		{fmt.Errorf(errors.MsgEncryptionKeyChanged), errors.CodeEncryptionKeyChanged, http.StatusUnauthorized, false},
+		{errors.NewUnauthorized(errors.MsgLoginUnauthorizedError), errors.CodeUnauthorized, http.StatusUnauthorized,
+			false},
+		{k8serrors.NewForbidden(resource, "foo", fmt.Errorf("denied")), errors.CodeForbidden, http.StatusForbidden,
+			false},
+		{k8serrors.NewNotFound(resource, "foo"), errors.CodeNotFound, http.StatusNotFound, false},
+		{k8serrors.NewAlreadyExists(resource, "foo"), errors.CodeAlreadyExists, http.StatusConflict, false},
+		{k8serrors.NewConflict(resource, "foo", fmt.Errorf("modified")), errors.CodeConflict, http.StatusConflict,
+			true},
+		{errors.NewBadRequest("invalid"), errors.CodeBadRequest, http.StatusBadRequest, false},
+		{errors.NewResultTruncated("10MiB"), errors.CodeResultTruncated, http.StatusRequestEntityTooLarge, false},
+		{errors.NewTooManyRequests("later", 10), errors.CodeTooManyRequests, http.StatusTooManyRequests, true},
+		{errors.NewGenericResponse(http.StatusServiceUnavailable, ""), errors.CodeServiceUnavailable,
+			http.StatusServiceUnavailable, true},
+		{context.DeadlineExceeded, errors.CodeTimeout, http.StatusServiceUnavailable, true},
+		{fmt.Errorf("unknown"), errors.CodeInternal, http.StatusInternalServerError, false},
+	}
+
+	for _, c := range cases {
+		actual := errors.NewAPIError(c.err)
+		if actual.Code != c.code || actual.Status != c.status || actual.Retryable != c.retryable ||
+			actual.Message != c.err.Error() {
+			t.Errorf("NewAPIError(%v) == %+v, expected code %s, status %d and retryable %t", c.err, actual, c.code,
+				c.status, c.retryable)
+		}
+	}
+}
+
+func TestNewAPIErrorDetails(t *testing.T) {
+	err := k8serrors.NewInvalid(schema.GroupKind{Kind: "Pod"}, "foo",
+		field.ErrorList{field.Required(field.NewPath("spec", "containers"), "")})
+	actual := errors.NewAPIError(err)
+
+	if actual.Code != errors.CodeInvalid || len(actual.Details) != 1 ||
+		actual.Details[0].Field != "spec.containers" || actual.Details[0].Type != string(field.ErrorTypeRequired) {
+		t.Errorf("NewAPIError(%v) == %+v, expected invalid error with required field", err, actual)
+	}
+}
+
+func TestHandleInternalErrorBody(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	errors.HandleInternalError(restful.NewResponse(recorder), errors.NewInvalid(errors.MsgTokenExpiredError))
+
+	actual := new(errors.APIError)
+	if err := json.Unmarshal(recorder.Body.Bytes(), actual); err != nil {
+		t.Fatalf("HandleInternalError should write JSON, got %q: %s", recorder.Body, err)
+	}
+
+	expected := errors.APIError{Code: errors.CodeTokenExpired, Status: http.StatusUnauthorized,
+		Message: errors.MsgTokenExpiredError}
+	if recorder.Code != http.StatusUnauthorized || !reflect.DeepEqual(*actual, expected) {
+		t.Errorf("HandleInternalError wrote %d %+v, expected %+v", recorder.Code, actual, expected)
+	}
+}
@@ -25,6 +25,10 @@ import (
 
 var _ error = &errors.StatusError{}
 
+// StatusReasonResultTruncated means that the result of a request was larger than the configured limits
+// and has been dropped. The user should narrow down the query, i.e. by using a namespace or a filter.
+const StatusReasonResultTruncated metav1.StatusReason = "ResultTruncated"
+
 // NewUnauthorized returns an error indicating the client is not authorized to perform the requested
 // action.
 func NewUnauthorized(reason string) *errors.StatusError {
@@ -50,6 +54,12 @@ func NewBadRequest(reason string) *errors.StatusError {
 	return errors.NewBadRequest(reason)
 }
 
+// NewTooManyRequests creates an error that indicates that the client must try again later because too many
+// requests have been made.
+func NewTooManyRequests(reason string, retryAfterSeconds int) *errors.StatusError {
+	return errors.NewTooManyRequests(reason, retryAfterSeconds)
+}
+
 // NewInvalid return a statusError
 // which is an error intended for consumption by a REST API server; it can also be
 // reconstructed by clients from a REST response. Public to allow easy type switches.
@@ -107,6 +117,21 @@ func NewUnexpectedObject(obj runtime.Object) *errors.StatusError {
 	}
 }
 
+// NewResultTruncated return a statusError
+// which is an error intended for consumption by a REST API server; it can also be
+// reconstructed by clients from a REST response. Public to allow easy type switches.
+func NewResultTruncated(limit string) *errors.StatusError {
+	return &errors.StatusError{
+		ErrStatus: metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Code:     http.StatusRequestEntityTooLarge,
+			Reason:   StatusReasonResultTruncated,
+			Message:  fmt.Sprintf("result truncated after %s, refine your filter", limit),
+		},
+	}
+}
+
 // NewGenericResponse return a statusError
 // which is an error intended for consumption by a REST API server; it can also be
 // reconstructed by clients from a REST response. Public to allow easy type switches
@@ -184,8 +209,18 @@ func IsAlreadyExists(err error) bool {
 	return errors.IsAlreadyExists(err)
 }
 
+// IsConflict determines if the err is an error which indicates that the object was modified since it was read.
+func IsConflict(err error) bool {
+	return errors.IsConflict(err)
+}
+
 // IsUnauthorized determines if err is an error which indicates that the request is unauthorized and
 // requires authentication by the user.
 func IsUnauthorized(err error) bool {
 	return errors.IsUnauthorized(err)
 }
+
+// IsResultTruncated determines if err is an error which indicates that the result exceeded configured limits.
+func IsResultTruncated(err error) bool {
+	return errors.ReasonForError(err) == StatusReasonResultTruncated
+}
@@ -24,7 +24,7 @@ import (
 
 // NonCriticalErrors is an array of error statuses, that are non-critical. That means, that this error can be
 // silenced and displayed to the user as a warning on the frontend side.
-var NonCriticalErrors = []int32{http.StatusForbidden, http.StatusUnauthorized}
+var NonCriticalErrors = []int32{http.StatusForbidden, http.StatusUnauthorized, http.StatusRequestEntityTooLarge}
 
 // HandleError handles single error, that occurred during API GET call. If it is not critical, then it will be
 // returned as a part of error array. Otherwise, it will be returned as a second value. Usage of this functions
@@ -121,15 +121,14 @@ func IsTokenExpiredError(err error) bool {
 	return err.Error() == MsgTokenExpiredError
 }
 
-// HandleInternalError writes the given error to the response and sets appropriate HTTP status headers.
+// HandleInternalError writes the given error to the response as an APIError and sets appropriate HTTP status headers.
+// Errors caused by an exceeded request timeout are reported as 503 Service Unavailable.
 func HandleInternalError(response *restful.Response, err error) {
-	statusCode := http.StatusInternalServerError
-	statusError, ok := err.(*errors.StatusError)
-	if ok && statusError.Status().Code > 0 {
-		statusCode = int(statusError.Status().Code)
+	apiError := NewAPIError(err)
+	if apiError == nil {
+		apiError = NewAPIError(NewInternal("unknown error"))
 	}
-	response.AddHeader("Content-Type", "text/plain")
-	response.WriteErrorString(statusCode, err.Error()+"\n")
+	response.WriteHeaderAndJson(apiError.Status, apiError, restful.MIME_JSON)
 }
 
 // HandleHTTPError is used to handle HTTP Errors more accurately based on the localized consts
@@ -15,9 +15,15 @@
 package errors_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"testing"
 
+	"github.com/emicklei/go-restful"
+
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
 )
 
@@ -58,3 +64,23 @@ func TestHandleHTTPError(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleInternalError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected int
+	}{
+		{errors.NewBadRequest("invalid"), http.StatusBadRequest},
+		{&url.Error{Op: "Get", URL: "https://apiserver", Err: context.DeadlineExceeded}, http.StatusServiceUnavailable},
+		{context.Canceled, http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		errors.HandleInternalError(restful.NewResponse(recorder), c.err)
+
+		if recorder.Code != c.expected {
+			t.Errorf("HandleInternalError(%v): expected status %d, got %d", c.err, c.expected, recorder.Code)
+		}
+	}
+}
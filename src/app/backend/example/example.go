@@ -0,0 +1,234 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package example
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// CatalogVersion is increased whenever examples in the catalog change, so the UI can tell which version of an
+// example was used to create an object.
+const CatalogVersion = "1"
+
+// DefaultNamespace is used when examples are rendered without a namespace.
+const DefaultNamespace = "default"
+
+// Example is a curated manifest that can be used as a starting point when creating objects.
+type Example struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Kind        string `json:"kind"`
+	// Content is the manifest rendered for the requested namespace. It is only set for a single example.
+	Content string `json:"content,omitempty"`
+	// Namespace the manifest has been rendered for.
+	Namespace string `json:"namespace,omitempty"`
+
+	template string
+}
+
+// ExampleList contains all examples of the catalog without their content.
+type ExampleList struct {
+	Version  string    `json:"version"`
+	Examples []Example `json:"examples"`
+}
+
+// Parameters of example templates.
+type parameters struct {
+	Namespace string
+}
+
+// catalog is the list of examples shipped with Dashboard. Templates are rendered with text/template, so they can
+// reference parameters like {{ .Namespace }}.
+var catalog = []Example{
+	{
+		Name:        "deployment-with-probes",
+		Title:       "Deployment with probes",
+		Description: "Web server deployment with resource requests, readiness and liveness probes and a service.",
+		Kind:        "Deployment",
+		template: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: {{ .Namespace }}
+  labels:
+    app: web
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.19
+        ports:
+        - containerPort: 80
+        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            memory: 128Mi
+        readinessProbe:
+          httpGet:
+            path: /
+            port: 80
+          periodSeconds: 5
+        livenessProbe:
+          httpGet:
+            path: /
+            port: 80
+          initialDelaySeconds: 10
+          periodSeconds: 10
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: {{ .Namespace }}
+spec:
+  selector:
+    app: web
+  ports:
+  - port: 80
+    targetPort: 80
+`,
+	},
+	{
+		Name:        "cronjob",
+		Title:       "CronJob",
+		Description: "Job running every night that does not start a new run while the previous one is still running.",
+		Kind:        "CronJob",
+		template: `apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: nightly
+  namespace: {{ .Namespace }}
+spec:
+  schedule: "0 2 * * *"
+  concurrencyPolicy: Forbid
+  successfulJobsHistoryLimit: 3
+  failedJobsHistoryLimit: 1
+  jobTemplate:
+    spec:
+      backoffLimit: 2
+      template:
+        spec:
+          restartPolicy: OnFailure
+          containers:
+          - name: nightly
+            image: busybox:1.32
+            command: ["sh", "-c", "date; echo Running nightly job"]
+`,
+	},
+	{
+		Name:        "networkpolicy-default-deny",
+		Title:       "NetworkPolicy default deny",
+		Description: "Denies all ingress and egress traffic of pods in the namespace unless another policy allows it.",
+		Kind:        "NetworkPolicy",
+		template: `apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: default-deny
+  namespace: {{ .Namespace }}
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress
+  - Egress
+`,
+	},
+	{
+		Name:        "ingress-with-tls",
+		Title:       "Ingress with TLS",
+		Description: "Ingress terminating TLS with a certificate from a secret and routing to the web service.",
+		Kind:        "Ingress",
+		template: `apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+  namespace: {{ .Namespace }}
+spec:
+  tls:
+  - hosts:
+    - web.example.com
+    secretName: web-tls
+  rules:
+  - host: web.example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: web
+          servicePort: 80
+`,
+	},
+}
+
+// GetExampleList returns all examples of the catalog.
+func GetExampleList() *ExampleList {
+	result := &ExampleList{Version: CatalogVersion, Examples: make([]Example, 0, len(catalog))}
+	for _, example := range catalog {
+		example.template = ""
+		result.Examples = append(result.Examples, example)
+	}
+
+	return result
+}
+
+// GetExample returns example with given name rendered for given namespace.
+func GetExample(name, namespace string) (*Example, error) {
+	if len(namespace) == 0 {
+		namespace = DefaultNamespace
+	}
+
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid namespace %s: %s", namespace, errs[0]))
+	}
+
+	for _, example := range catalog {
+		if example.Name != name {
+			continue
+		}
+
+		tmpl, err := template.New(example.Name).Parse(example.template)
+		if err != nil {
+			return nil, err
+		}
+
+		content := new(bytes.Buffer)
+		if err = tmpl.Execute(content, parameters{Namespace: namespace}); err != nil {
+			return nil, err
+		}
+
+		example.template = ""
+		example.Content = content.String()
+		example.Namespace = namespace
+		return &example, nil
+	}
+
+	return nil, errors.NewNotFound(fmt.Sprintf("example %s not found", name))
+}
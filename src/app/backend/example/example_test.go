@@ -0,0 +1,73 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package example
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+func TestGetExample(t *testing.T) {
+	for _, item := range GetExampleList().Examples {
+		example, err := GetExample(item.Name, "team-a")
+		if err != nil {
+			t.Fatalf("%s: Expected example to be rendered, but got %v.", item.Name, err)
+		}
+
+		decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewBufferString(example.Content), 4096)
+		kinds := make([]string, 0)
+		for {
+			object := &unstructured.Unstructured{}
+			if err = decoder.Decode(object); err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				t.Fatalf("%s: Expected valid manifest, but got %v.", item.Name, err)
+			}
+
+			if object.GetNamespace() != "team-a" {
+				t.Errorf("%s: Expected %s to be in namespace team-a, but got %s.", item.Name, object.GetKind(),
+					object.GetNamespace())
+			}
+			kinds = append(kinds, object.GetKind())
+		}
+
+		if len(kinds) == 0 || kinds[0] != item.Kind {
+			t.Errorf("%s: Expected first object to be %s, but got %v.", item.Name, item.Kind, kinds)
+		}
+	}
+}
+
+func TestGetExampleErrors(t *testing.T) {
+	if example, _ := GetExample("cronjob", ""); !strings.Contains(example.Content, "namespace: default") {
+		t.Errorf("Expected example to be rendered for default namespace, but got %s.", example.Content)
+	}
+
+	if _, err := GetExample("unknown", "default"); !errors.IsNotFoundError(err) {
+		t.Errorf("Expected not found error, but got %v.", err)
+	}
+
+	if _, err := GetExample("cronjob", "default\nkind: Secret"); err == nil {
+		t.Error("Expected invalid namespace to be rejected.")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package example
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Handler manages endpoints related to the catalog of example manifests.
+type Handler struct{}
+
+// Install creates new endpoints for the example catalog. Examples are shipped with Dashboard, so creating objects
+// from examples works without access to external sites.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/example").
+			To(h.handleGetExampleList).
+			Writes(ExampleList{}))
+	ws.Route(
+		ws.GET("/example/{name}").
+			To(h.handleGetExample).
+			Writes(Example{}))
+}
+
+// NewExampleHandler creates example.Handler.
+func NewExampleHandler() *Handler {
+	return &Handler{}
+}
+
+func (h *Handler) handleGetExampleList(request *restful.Request, response *restful.Response) {
+	response.WriteHeaderAndEntity(http.StatusOK, GetExampleList())
+}
+
+// handleGetExample returns example rendered for the namespace given by 'namespace' parameter.
+func (h *Handler) handleGetExample(request *restful.Request, response *restful.Response) {
+	result, err := GetExample(request.PathParameter("name"), request.QueryParameter("namespace"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
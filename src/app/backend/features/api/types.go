@@ -0,0 +1,68 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+// Feature is a name of an optional or risky capability that can be turned on or off.
+type Feature string
+
+const (
+	// Exec allows users to open a shell in running containers.
+	Exec Feature = "Exec"
+	// SecretReveal allows users to read values of secrets.
+	SecretReveal Feature = "SecretReveal"
+	// SkipLogin allows users to skip the login page. It additionally requires '--enable-skip-login' to be set.
+	SkipLogin Feature = "SkipLogin"
+)
+
+// DefaultFeatureGates contains all known features together with their default state.
+var DefaultFeatureGates = map[Feature]bool{
+	Exec:         true,
+	SecretReveal: true,
+	SkipLogin:    true,
+}
+
+// FeatureGateSource tells where the effective state of a feature gate comes from.
+type FeatureGateSource string
+
+const (
+	// FeatureGateSourceDefault means that the gate was not configured.
+	FeatureGateSourceDefault FeatureGateSource = "default"
+	// FeatureGateSourceFlag means that the gate was set using '--feature-gates' argument.
+	FeatureGateSourceFlag FeatureGateSource = "flag"
+	// FeatureGateSourceSettings means that the gate was overridden in settings config map.
+	FeatureGateSourceSettings FeatureGateSource = "settings"
+)
+
+// FeatureGateManager is used to check which features are enabled.
+type FeatureGateManager interface {
+	// Enabled returns true if given feature is currently enabled.
+	Enabled(feature Feature) bool
+	// List returns effective state of all known feature gates.
+	List() FeatureGateList
+	// SetFlagGates replaces gates set using arguments, i.e. when configuration is reloaded.
+	SetFlagGates(gates string) error
+}
+
+// FeatureGate represents effective state of a single feature gate.
+type FeatureGate struct {
+	Name    Feature           `json:"name"`
+	Enabled bool              `json:"enabled"`
+	Source  FeatureGateSource `json:"source"`
+}
+
+// FeatureGateList contains effective state of all known feature gates.
+type FeatureGateList struct {
+	Gates []FeatureGate `json:"gates"`
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package features
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/features/api"
+)
+
+// FeatureGateHandler manages all endpoints related to feature gates.
+type FeatureGateHandler struct {
+	manager api.FeatureGateManager
+}
+
+// Install creates new endpoints for feature gates. They are public, because the frontend needs them
+// before the user logs in.
+func (self *FeatureGateHandler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/featuregates").
+			To(self.handleGet).
+			Writes(api.FeatureGateList{}))
+}
+
+func (self *FeatureGateHandler) handleGet(request *restful.Request, response *restful.Response) {
+	response.WriteHeaderAndEntity(http.StatusOK, self.manager.List())
+}
+
+// NewFeatureGateHandler creates FeatureGateHandler.
+func NewFeatureGateHandler(manager api.FeatureGateManager) FeatureGateHandler {
+	return FeatureGateHandler{manager: manager}
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package features
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/features/api"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// FeatureGateManager is a structure containing all feature gate manager members. Gates set using arguments
+// override the defaults and gates stored in settings config map override both of them.
+type FeatureGateManager struct {
+	flagGates map[api.Feature]bool
+	sManager  settingsApi.SettingsManager
+	client    kubernetes.Interface
+	mux       sync.RWMutex
+}
+
+// NewFeatureGateManager creates new feature gate manager. Gates are given in the same format as
+// '--feature-gates' argument, i.e. 'Exec=false,SecretReveal=false'.
+func NewFeatureGateManager(gates string, sManager settingsApi.SettingsManager,
+	client kubernetes.Interface) (api.FeatureGateManager, error) {
+	flagGates, err := ParseFeatureGates(gates)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FeatureGateManager{flagGates: flagGates, sManager: sManager, client: client}, nil
+}
+
+// Enabled implements FeatureGateManager interface. Check it for more information.
+func (self *FeatureGateManager) Enabled(feature api.Feature) bool {
+	for _, gate := range self.List().Gates {
+		if gate.Name == feature {
+			return gate.Enabled
+		}
+	}
+
+	return false
+}
+
+// List implements FeatureGateManager interface. Check it for more information.
+func (self *FeatureGateManager) List() api.FeatureGateList {
+	self.mux.RLock()
+	defer self.mux.RUnlock()
+
+	overrides := self.sManager.GetFeatureGates(self.client)
+	list := api.FeatureGateList{Gates: make([]api.FeatureGate, 0, len(api.DefaultFeatureGates))}
+	for feature, enabled := range api.DefaultFeatureGates {
+		gate := api.FeatureGate{Name: feature, Enabled: enabled, Source: api.FeatureGateSourceDefault}
+		if value, ok := self.flagGates[feature]; ok {
+			gate.Enabled = value
+			gate.Source = api.FeatureGateSourceFlag
+		}
+		if value, ok := overrides[string(feature)]; ok {
+			gate.Enabled = value
+			gate.Source = api.FeatureGateSourceSettings
+		}
+
+		list.Gates = append(list.Gates, gate)
+	}
+
+	for name := range overrides {
+		if _, ok := api.DefaultFeatureGates[api.Feature(name)]; !ok {
+			log.Printf("Ignoring unknown feature gate %s found in settings", name)
+		}
+	}
+
+	sort.Slice(list.Gates, func(i, j int) bool { return list.Gates[i].Name < list.Gates[j].Name })
+	return list
+}
+
+// SetFlagGates implements FeatureGateManager interface. Check it for more information.
+func (self *FeatureGateManager) SetFlagGates(gates string) error {
+	flagGates, err := ParseFeatureGates(gates)
+	if err != nil {
+		return err
+	}
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.flagGates = flagGates
+	return nil
+}
+
+// ParseFeatureGates parses comma separated list of key=value pairs, i.e. 'Exec=false,SecretReveal=false'.
+// Only known features are accepted.
+func ParseFeatureGates(value string) (map[api.Feature]bool, error) {
+	gates := make(map[api.Feature]bool)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("missing value for feature gate %s", pair)
+		}
+
+		feature := api.Feature(strings.TrimSpace(parts[0]))
+		if _, ok := api.DefaultFeatureGates[feature]; !ok {
+			return nil, fmt.Errorf("unknown feature gate %s", feature)
+		}
+
+		enabled, err := strconv.ParseBool(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of feature gate %s: %s", feature, parts[1])
+		}
+
+		gates[feature] = enabled
+	}
+
+	return gates, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package features
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/features/api"
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+func TestParseFeatureGates(t *testing.T) {
+	cases := []struct {
+		value       string
+		expected    map[api.Feature]bool
+		expectedErr bool
+	}{
+		{"", map[api.Feature]bool{}, false},
+		{"Exec=false, SkipLogin=false", map[api.Feature]bool{api.Exec: false, api.SkipLogin: false}, false},
+		{"NodeShell=true", nil, true},
+		{"Exec", nil, true},
+		{"Unknown=true", nil, true},
+		{"Exec=maybe", nil, true},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseFeatureGates(c.value)
+		if (err != nil) != c.expectedErr {
+			t.Errorf("ParseFeatureGates(%s) returned unexpected error: %v", c.value, err)
+			continue
+		}
+
+		if !c.expectedErr && !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ParseFeatureGates(%s) == %v, expected %v", c.value, actual, c.expected)
+		}
+	}
+}
+
+func TestFeatureGateManager_List(t *testing.T) {
+	cm := settingsApi.GetDefaultSettingsConfigMap("")
+	cm.Data[settingsApi.FeatureGatesKey] = `{"Exec":true,"SecretReveal":false}`
+	client := fake.NewSimpleClientset(cm)
+
+	manager, err := NewFeatureGateManager("Exec=false,SkipLogin=false", settings.NewSettingsManager(), client)
+	if err != nil {
+		t.Fatalf("NewFeatureGateManager() returned unexpected error: %s", err.Error())
+	}
+
+	expected := api.FeatureGateList{Gates: []api.FeatureGate{
+		{Name: api.Exec, Enabled: true, Source: api.FeatureGateSourceSettings},
+		{Name: api.SecretReveal, Enabled: false, Source: api.FeatureGateSourceSettings},
+		{Name: api.SkipLogin, Enabled: false, Source: api.FeatureGateSourceFlag},
+	}}
+
+	if actual := manager.List(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("List() == %#v, expected %#v", actual, expected)
+	}
+
+	if manager.Enabled(api.SecretReveal) {
+		t.Error("SecretReveal should be disabled by settings override")
+	}
+}
@@ -15,10 +15,16 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kubernetes/dashboard/src/app/backend/handler/parser"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/customresourcedefinition/types"
@@ -26,19 +32,46 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/plugin"
 
 	"github.com/emicklei/go-restful"
-	"golang.org/x/net/xsrftoken"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/tools/remotecommand"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 
+	"github.com/kubernetes/dashboard/src/app/backend/accessgrant"
+	"github.com/kubernetes/dashboard/src/app/backend/accessrequest"
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/args"
 	"github.com/kubernetes/dashboard/src/app/backend/auth"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/cani"
+	"github.com/kubernetes/dashboard/src/app/backend/client"
 	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/client/csrf"
+	"github.com/kubernetes/dashboard/src/app/backend/clientstate"
+	"github.com/kubernetes/dashboard/src/app/backend/commandpalette"
+	"github.com/kubernetes/dashboard/src/app/backend/diagnostics"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/example"
+	"github.com/kubernetes/dashboard/src/app/backend/features"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
 	"github.com/kubernetes/dashboard/src/app/backend/integration"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/falco"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/imageupdate"
+	"github.com/kubernetes/dashboard/src/app/backend/loglevel"
+	"github.com/kubernetes/dashboard/src/app/backend/notification"
+	"github.com/kubernetes/dashboard/src/app/backend/openapi"
+	"github.com/kubernetes/dashboard/src/app/backend/operation"
+	"github.com/kubernetes/dashboard/src/app/backend/preview"
+	"github.com/kubernetes/dashboard/src/app/backend/publicstatus"
+	"github.com/kubernetes/dashboard/src/app/backend/quickaction"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/activity"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/application"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/attention"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/clusterrole"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/clusterrolebinding"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/compliance"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/configmap"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/container"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/controller"
@@ -49,27 +82,48 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/idleworkload"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/ingress"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/job"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/lint"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
 	ns "github.com/kubernetes/dashboard/src/app/backend/resource/namespace"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/networkpolicy"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/node"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/oomreport"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/openshift"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/persistentvolume"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/persistentvolumeclaim"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/policyreport"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/probe"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/rebase"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/recommendation"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/reference"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicaset"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicationcontroller"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/restartstorm"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/role"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/rolebinding"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/route"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/scheduling"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/secret"
 	resourceService "github.com/kubernetes/dashboard/src/app/backend/resource/service"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/serviceaccount"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/statefulset"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/storageclass"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/storagereport"
 	"github.com/kubernetes/dashboard/src/app/backend/scaling"
+	"github.com/kubernetes/dashboard/src/app/backend/securityreview"
+	"github.com/kubernetes/dashboard/src/app/backend/session"
 	"github.com/kubernetes/dashboard/src/app/backend/settings"
 	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+	"github.com/kubernetes/dashboard/src/app/backend/settings/webhook"
+	"github.com/kubernetes/dashboard/src/app/backend/snapshot"
+	"github.com/kubernetes/dashboard/src/app/backend/stream"
 	"github.com/kubernetes/dashboard/src/app/backend/systembanner"
+	"github.com/kubernetes/dashboard/src/app/backend/systemstatus"
+	"github.com/kubernetes/dashboard/src/app/backend/tenancy"
 	"github.com/kubernetes/dashboard/src/app/backend/validation"
 )
 
@@ -81,11 +135,30 @@ const (
 	ResponseLogString = "[%s] Outcoming response to %s with %d status code"
 )
 
+// patchMediaTypes are the content types accepted by the generic patch endpoint.
+var patchMediaTypes = []string{
+	string(k8sTypes.JSONPatchType),
+	string(k8sTypes.MergePatchType),
+	string(k8sTypes.StrategicMergePatchType),
+	string(k8sTypes.ApplyPatchType),
+}
+
 // APIHandler is a representation of API handler. Structure contains clientapi, Heapster clientapi and clientapi configuration.
 type APIHandler struct {
 	iManager integration.IntegrationManager
 	cManager clientapi.ClientManager
 	sManager settingsApi.SettingsManager
+	fManager featuresApi.FeatureGateManager
+	// scaleScheduler does scales scheduled for later.
+	scaleScheduler *scaling.Scheduler
+	// auditLogger records reveals of redacted secrets.
+	auditLogger authApi.AuditLogger
+	// csrfManager issues CSRF tokens bound to sessions of users.
+	csrfManager *csrf.SessionTokenManager
+	// namespaceCache serves namespace lookups of users who can list all namespaces.
+	namespaceCache *ns.NamespaceCache
+	// opManager runs long running operations and keeps their progress.
+	opManager *operation.Manager
 }
 
 // TerminalResponse is sent by handleExecShell. The Id is a random session id that binds the original REST request and the SockJS connection.
@@ -97,16 +170,28 @@ type TerminalResponse struct {
 // CreateHTTPAPIHandler creates a new HTTP handler that handles all requests to the API of the backend.
 func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clientapi.ClientManager,
 	authManager authApi.AuthManager, sManager settingsApi.SettingsManager,
-	sbManager systembanner.SystemBannerManager) (
+	sbManager *systembanner.SystemBannerManager, fManager featuresApi.FeatureGateManager,
+	auditLogger authApi.AuditLogger) (
 
 	http.Handler, error) {
-	apiHandler := APIHandler{iManager: iManager, cManager: cManager, sManager: sManager}
+	csrfManager := csrf.NewSessionTokenManager(cManager.CSRFKey(), authManager.SessionID)
+	apiHandler := APIHandler{iManager: iManager, cManager: cManager, sManager: sManager, fManager: fManager,
+		scaleScheduler: scaling.NewScheduler(), auditLogger: auditLogger, csrfManager: csrfManager,
+		namespaceCache: ns.NewNamespaceCache(cManager.InsecureClient()),
+		opManager:      operation.NewManager(cManager.InsecureClient())}
+	restful.RegisterEntityAccessor(restful.MIME_JSON, stream.NewJSONEntityAccessor(args.Holder.GetListEncoderWorkers()))
 	wsContainer := restful.NewContainer()
 	wsContainer.EnableContentEncoding(true)
 
 	apiV1Ws := new(restful.WebService)
 
-	InstallFilters(apiV1Ws, cManager)
+	InstallFilters(apiV1Ws, cManager, csrfManager)
+	if args.Holder.GetEnableTenancy() {
+		apiV1Ws.Filter(tenancy.NewTenancyManager(cManager, sManager).Filter)
+	}
+	// Installed after all authorization filters, so that their rejections are never replayed.
+	apiV1Ws.Filter(idempotencyFilter(cManager))
+	apiV1Ws.Filter(apiHandler.rawObjectFilter)
 
 	apiV1Ws.Path("/api/v1").
 		Consumes(restful.MIME_JSON).
@@ -119,7 +204,7 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	pluginHandler := plugin.NewPluginHandler(cManager)
 	pluginHandler.Install(apiV1Ws)
 
-	authHandler := auth.NewAuthHandler(authManager)
+	authHandler := auth.NewAuthHandler(authManager, cManager, fManager, auditLogger)
 	authHandler.Install(apiV1Ws)
 
 	settingsHandler := settings.NewSettingsHandler(sManager, cManager)
@@ -128,6 +213,60 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	systemBannerHandler := systembanner.NewSystemBannerHandler(sbManager)
 	systemBannerHandler.Install(apiV1Ws)
 
+	quickActionHandler := quickaction.NewQuickActionHandler(cManager, sManager)
+	quickActionHandler.Install(apiV1Ws)
+
+	logLevelHandler := loglevel.NewLogLevelHandler(cManager, sManager)
+	logLevelHandler.Install(apiV1Ws)
+
+	snapshotHandler := snapshot.NewSnapshotHandler(cManager)
+	snapshotHandler.Install(apiV1Ws)
+
+	previewHandler := preview.NewPreviewHandler(cManager)
+	previewHandler.Install(apiV1Ws)
+
+	systemStatusHandler := systemstatus.NewSystemStatusHandler(cManager, iManager, &terminalSessions)
+	systemStatusHandler.Install(apiV1Ws)
+
+	publicStatusHandler := publicstatus.NewPublicStatusHandler(cManager)
+	publicStatusHandler.Install(apiV1Ws)
+
+	notificationHandler := notification.NewNotificationHandler(cManager)
+	notificationHandler.Install(apiV1Ws)
+
+	accessGrantHandler := accessgrant.NewAccessGrantHandler(cManager)
+	accessGrantHandler.Install(apiV1Ws)
+
+	accessRequestHandler := accessrequest.NewAccessRequestHandler(cManager)
+	accessRequestHandler.Install(apiV1Ws)
+
+	commandPaletteHandler := commandpalette.NewCommandPaletteHandler(cManager, sManager)
+	commandPaletteHandler.Install(apiV1Ws)
+
+	exampleHandler := example.NewExampleHandler()
+	exampleHandler.Install(apiV1Ws)
+
+	clientStateHandler := clientstate.NewClientStateHandler(cManager, authManager)
+	clientStateHandler.Install(apiV1Ws)
+
+	sessionHandler := session.NewSessionHandler(cManager, sManager, authManager, fManager, sbManager)
+	sessionHandler.Install(apiV1Ws)
+
+	operationHandler := operation.NewOperationHandler(cManager, apiHandler.opManager)
+	operationHandler.Install(apiV1Ws)
+
+	featureGateHandler := features.NewFeatureGateHandler(fManager)
+	featureGateHandler.Install(apiV1Ws)
+
+	securityReviewHandler := securityreview.NewSecurityReviewHandler(cManager, sManager)
+	securityReviewHandler.Install(apiV1Ws)
+
+	diagnosticsHandler := diagnostics.NewDiagnosticsHandler(cManager)
+	diagnosticsHandler.Install(apiV1Ws)
+
+	canIHandler := cani.NewCanIHandler(cManager)
+	canIHandler.Install(apiV1Ws)
+
 	apiV1Ws.Route(
 		apiV1Ws.GET("csrftoken/{action}").
 			To(apiHandler.handleGetCsrfToken).
@@ -153,6 +292,11 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 			To(apiHandler.handleProtocolValidity).
 			Reads(validation.ProtocolValiditySpec{}).
 			Writes(validation.ProtocolValidity{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/appdeployment/validate/ip").
+			To(apiHandler.handleIPValidity).
+			Reads(validation.IPValiditySpec{}).
+			Writes(validation.IPValidity{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/appdeployment/protocols").
 			To(apiHandler.handleGetAvailableProtocols).
@@ -238,6 +382,10 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 		apiV1Ws.GET("/pod/{namespace}/{pod}/event").
 			To(apiHandler.handleGetPodEvents).
 			Writes(common.EventList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/imagepull").
+			To(apiHandler.handleGetPodImagePullProgress).
+			Writes(pod.ImagePullProgress{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/pod/{namespace}/{pod}/shell/{container}").
 			To(apiHandler.handleExecShell).
@@ -271,6 +419,14 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 		apiV1Ws.GET("/deployment/{namespace}/{deployment}/newreplicaset").
 			To(apiHandler.handleGetDeploymentNewReplicaSet).
 			Writes(replicaset.ReplicaSet{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/deployment/{namespace}/{deployment}/dependency").
+			To(apiHandler.handleGetDeploymentDependencies).
+			Writes(deployment.DependencyRollup{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/deployment/{namespace}/{deployment}/canary").
+			To(apiHandler.handleGetDeploymentCanary).
+			Writes(deployment.CanaryComparison{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.PUT("/scale/{kind}/{namespace}/{name}/").
@@ -288,6 +444,197 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 		apiV1Ws.GET("/scale/{kind}/{name}").
 			To(apiHandler.handleGetReplicaCount).
 			Writes(scaling.ReplicaCounts{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/scale/scheduled").
+			To(apiHandler.handleGetScheduledScales).
+			Writes(scaling.ScheduledScaleList{}))
+	apiV1Ws.Route(
+		apiV1Ws.DELETE("/scale/scheduled/{id}").
+			To(apiHandler.handleCancelScheduledScale))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/podcleanup").
+			To(apiHandler.handleGetPodCleanupCandidates).
+			Writes(pod.PodCleanupResult{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/podcleanup/{namespace}").
+			To(apiHandler.handleGetPodCleanupCandidates).
+			Writes(pod.PodCleanupResult{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/podcleanup").
+			To(apiHandler.handleCleanupPods).
+			Reads(pod.PodCleanupSpec{}).
+			Writes(pod.PodCleanupResult{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/podcleanup/{namespace}").
+			To(apiHandler.handleCleanupPods).
+			Reads(pod.PodCleanupSpec{}).
+			Writes(pod.PodCleanupResult{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/networkpolicy").
+			To(apiHandler.handleGetNetworkPolicyList).
+			Writes(networkpolicy.NetworkPolicyList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/networkpolicy/{namespace}").
+			To(apiHandler.handleGetNetworkPolicyList).
+			Writes(networkpolicy.NetworkPolicyList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/networkpolicy/{namespace}/{name}").
+			To(apiHandler.handleGetNetworkPolicyDetail).
+			Writes(networkpolicy.NetworkPolicyDetail{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/referencereport").
+			To(apiHandler.handleGetReferenceReport).
+			Writes(reference.ReferenceReport{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/referencereport/{namespace}").
+			To(apiHandler.handleGetReferenceReport).
+			Writes(reference.ReferenceReport{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/storagereport").
+			To(apiHandler.handleGetStorageReport).
+			Writes(storagereport.StorageReport{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/storagereport/persistentvolume/{name}/reclaimpolicy").
+			To(apiHandler.handleSetReclaimPolicy).
+			Reads(storagereport.ReclaimPolicySpec{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/application").
+			To(apiHandler.handleGetApplicationList).
+			Writes(application.ApplicationList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/application/{namespace}").
+			To(apiHandler.handleGetApplicationList).
+			Writes(application.ApplicationList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/application/{namespace}/{name}").
+			To(apiHandler.handleGetApplicationDetail).
+			Writes(application.ApplicationDetail{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/idleworkload").
+			To(apiHandler.handleGetIdleWorkloads).
+			Writes(idleworkload.IdleWorkloadList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/idleworkload/{namespace}").
+			To(apiHandler.handleGetIdleWorkloads).
+			Writes(idleworkload.IdleWorkloadList{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/recommendation/{kind}/{namespace}/{name}").
+			To(apiHandler.handleGetRecommendation).
+			Writes(recommendation.Recommendation{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/recommendation/{kind}/{namespace}/{name}").
+			To(apiHandler.handleApplyRecommendation).
+			Reads(recommendation.ApplySpec{}).
+			Writes(recommendation.Recommendation{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/probe/{kind}/{namespace}/{name}").
+			To(apiHandler.handleGetProbeAnalytics).
+			Writes(probe.ProbeAnalytics{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/probereport").
+			To(apiHandler.handleGetFlappingReport).
+			Writes(probe.FlappingReport{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/probereport/{namespace}").
+			To(apiHandler.handleGetFlappingReport).
+			Writes(probe.FlappingReport{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/restartstorm").
+			To(apiHandler.handleGetRestartStormList).
+			Writes(restartstorm.RestartStormList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/restartstorm/{namespace}").
+			To(apiHandler.handleGetRestartStormList).
+			Writes(restartstorm.RestartStormList{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/attention").
+			To(apiHandler.handleGetAttentionList).
+			Writes(attention.AttentionList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/attention/{namespace}").
+			To(apiHandler.handleGetAttentionList).
+			Writes(attention.AttentionList{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.POST("/scheduling/simulation").
+			To(apiHandler.handleSimulateScheduling).
+			Reads(v1.Pod{}).
+			Writes(scheduling.SimulationResult{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/activity").
+			To(apiHandler.handleGetActivityHeatmap).
+			Writes(activity.ActivityHeatmap{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/activity/{namespace}").
+			To(apiHandler.handleGetActivityHeatmap).
+			Writes(activity.ActivityHeatmap{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/security/compliance").
+			To(apiHandler.handleGetComplianceSummary).
+			Writes(compliance.ComplianceSummary{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/security/compliance/node/{name}").
+			To(apiHandler.handleGetNodeCompliance).
+			Writes(compliance.NodeCompliance{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/policyreport").
+			To(apiHandler.handleGetPolicyViolationSummary).
+			Writes(policyreport.PolicyViolationSummary{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/policyreport/{namespace}").
+			To(apiHandler.handleGetWorkloadViolations).
+			Writes(policyreport.WorkloadViolationList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/policyreport/{namespace}/{kind}/{name}").
+			To(apiHandler.handleGetWorkloadViolations).
+			Writes(policyreport.WorkloadViolationList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/security/runtime").
+			To(apiHandler.handleGetRuntimeEvents).
+			Writes(falco.EventList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/security/runtime/{namespace}").
+			To(apiHandler.handleGetRuntimeEvents).
+			Writes(falco.EventList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/security/runtime/{namespace}/{pod}").
+			To(apiHandler.handleGetRuntimeEvents).
+			Writes(falco.EventList{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/imageupdate").
+			To(apiHandler.handleGetImageUpdates).
+			Writes(imageupdate.WorkloadUpdatesList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/imageupdate/{namespace}").
+			To(apiHandler.handleGetImageUpdates).
+			Writes(imageupdate.WorkloadUpdatesList{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/imageupdate/{kind}/{namespace}/{name}").
+			Reads(imageupdate.BumpSpec{}).
+			To(apiHandler.handleBumpImage).
+			Writes(imageupdate.ContainerUpdate{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/oomreport").
+			To(apiHandler.handleGetOOMReport).
+			Writes(oomreport.OOMReport{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/oomreport/{namespace}").
+			To(apiHandler.handleGetOOMReport).
+			Writes(oomreport.OOMReport{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/daemonset").
@@ -385,6 +732,10 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 		apiV1Ws.GET("/namespace").
 			To(apiHandler.handleGetNamespaces).
 			Writes(ns.NamespaceList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/namespacelookup").
+			To(apiHandler.handleLookupNamespaces).
+			Writes(ns.NamespaceLookup{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/namespace/{name}").
 			To(apiHandler.handleGetNamespaceDetail).
@@ -406,6 +757,14 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 		apiV1Ws.GET("/secret/{namespace}/{name}").
 			To(apiHandler.handleGetSecretDetail).
 			Writes(secret.SecretDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/secret/{namespace}/{name}/data/{key}").
+			To(apiHandler.handleGetSecretDataSlice).
+			Writes(common.DataValueSlice{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/secret/{namespace}/{name}/reveal").
+			To(apiHandler.handleRevealSecret).
+			Writes(secret.SecretValues{}))
 	apiV1Ws.Route(
 		apiV1Ws.POST("/secret").
 			To(apiHandler.handleCreateImagePullSecret).
@@ -424,6 +783,10 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 		apiV1Ws.GET("/configmap/{namespace}/{configmap}").
 			To(apiHandler.handleGetConfigMapDetail).
 			Writes(configmap.ConfigMapDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/configmap/{namespace}/{configmap}/data/{key}").
+			To(apiHandler.handleGetConfigMapDataSlice).
+			Writes(common.DataValueSlice{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/service").
@@ -445,6 +808,11 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 		apiV1Ws.GET("/service/{namespace}/{service}/pod").
 			To(apiHandler.handleGetServicePods).
 			Writes(pod.PodList{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/service/{namespace}/{service}/switch").
+			To(apiHandler.handleSwitchService).
+			Reads(resourceService.SelectorSwitchSpec{}).
+			Writes(resourceService.SelectorSwitchResult{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/serviceaccount").
@@ -480,6 +848,23 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 			To(apiHandler.handleGetIngressDetail).
 			Writes(ingress.IngressDetail{}))
 
+	apiV1Ws.Route(
+		apiV1Ws.GET("/openshift").
+			To(apiHandler.handleGetOpenShiftCapabilities).
+			Writes(openshift.Capabilities{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/route").
+			To(apiHandler.handleGetRouteList).
+			Writes(route.RouteList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/route/{namespace}").
+			To(apiHandler.handleGetRouteList).
+			Writes(route.RouteList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/route/{namespace}/{name}").
+			To(apiHandler.handleGetRouteDetail).
+			Writes(route.RouteDetail{}))
+
 	apiV1Ws.Route(
 		apiV1Ws.GET("/statefulset").
 			To(apiHandler.handleGetStatefulSetList).
@@ -527,6 +912,15 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	apiV1Ws.Route(
 		apiV1Ws.PUT("/_raw/{kind}/namespace/{namespace}/name/{name}").
 			To(apiHandler.handlePutResource))
+	apiV1Ws.Route(
+		apiV1Ws.PATCH("/_raw/{kind}/namespace/{namespace}/name/{name}").
+			To(apiHandler.handlePatchResource).
+			Consumes(patchMediaTypes...))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/_raw/{kind}/namespace/{namespace}/name/{name}/rebase").
+			To(apiHandler.handleRebaseResource).
+			Reads(rebase.RebaseSpec{}).
+			Writes(rebase.RebaseResult{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.DELETE("/_raw/{kind}/name/{name}").
@@ -537,6 +931,15 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	apiV1Ws.Route(
 		apiV1Ws.PUT("/_raw/{kind}/name/{name}").
 			To(apiHandler.handlePutResource))
+	apiV1Ws.Route(
+		apiV1Ws.PATCH("/_raw/{kind}/name/{name}").
+			To(apiHandler.handlePatchResource).
+			Consumes(patchMediaTypes...))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/_raw/{kind}/name/{name}/rebase").
+			To(apiHandler.handleRebaseResource).
+			Reads(rebase.RebaseSpec{}).
+			Writes(rebase.RebaseResult{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/clusterrole").
@@ -655,8 +1058,29 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	apiV1Ws.Route(
 		apiV1Ws.GET("/log/file/{namespace}/{pod}/{container}").
 			To(apiHandler.handleLogFile).
-			Writes(logs.LogDetails{}))
+			Writes(logs.LogDetails{}).
+			Metadata(stream.RouteMetadata, true))
+
+	// Webhooks are called by the apiserver or other cluster components, so they can not be protected by
+	// CSRF tokens.
+	if args.Holder.GetEnableSettingsWebhook() || len(args.Holder.GetFalcoWebhookToken()) > 0 {
+		webhookWs := new(restful.WebService)
+		webhookWs.Filter(requestAndResponseLogger)
+		webhookWs.Path("/api/webhook").
+			Consumes(restful.MIME_JSON).
+			Produces(restful.MIME_JSON)
+		wsContainer.Add(webhookWs)
+
+		if args.Holder.GetEnableSettingsWebhook() {
+			settingsWebhookHandler := webhook.NewSettingsWebhookHandler()
+			settingsWebhookHandler.Install(webhookWs)
+		}
+		if len(args.Holder.GetFalcoWebhookToken()) > 0 {
+			falco.NewFalcoWebhookHandler(iManager.Falco()).Install(webhookWs)
+		}
+	}
 
+	wsContainer.Handle(openapi.DocumentPath, openapi.NewHandler(wsContainer, client.Version))
 	return wsContainer, nil
 }
 
@@ -794,7 +1218,11 @@ func (apiHandler *APIHandler) handleGetRoleBindingDetail(request *restful.Reques
 
 func (apiHandler *APIHandler) handleGetCsrfToken(request *restful.Request, response *restful.Response) {
 	action := request.PathParameter("action")
-	token := xsrftoken.Generate(apiHandler.cManager.CSRFKey(), "none", action)
+	token, err := apiHandler.csrfManager.Generate(request.Request, response.ResponseWriter, action)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
 	response.WriteHeaderAndEntity(http.StatusOK, api.CsrfToken{Token: token})
 }
 
@@ -808,8 +1236,14 @@ func (apiHandler *APIHandler) handleGetStatefulSetList(request *restful.Request,
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parser.ParseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := statefulset.GetStatefulSetList(k8sClient, namespace, dataSelect,
-		apiHandler.iManager.Metric().Client())
+	var result interface{}
+	if label := request.QueryParameter(common.GroupByQueryParameter); len(label) > 0 {
+		result, err = statefulset.GetStatefulSetGroupList(k8sClient, namespace, dataSelect,
+			apiHandler.iManager.Metric().Client(), label)
+	} else {
+		result, err = statefulset.GetStatefulSetList(k8sClient, namespace, dataSelect,
+			apiHandler.iManager.Metric().Client())
+	}
 	if err != nil {
 		errors.HandleInternalError(response, err)
 		return
@@ -1029,6 +1463,45 @@ func (apiHandler *APIHandler) handleGetIngressList(request *restful.Request, res
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+func (apiHandler *APIHandler) handleGetOpenShiftCapabilities(request *restful.Request,
+	response *restful.Response) {
+	response.WriteHeaderAndEntity(http.StatusOK, openshift.GetCapabilities())
+}
+
+func (apiHandler *APIHandler) handleGetRouteDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := route.GetRouteDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetRouteList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	namespace := parseNamespacePathParameter(request)
+	result, err := route.GetRouteList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetServicePods(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
@@ -1048,6 +1521,40 @@ func (apiHandler *APIHandler) handleGetServicePods(request *restful.Request, res
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+func (apiHandler *APIHandler) handleSwitchService(request *restful.Request, response *restful.Response) {
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	spec := new(resourceService.SelectorSwitchSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("service")
+	result, err := resourceService.SwitchServiceSelector(k8sClient, namespace, name, spec)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if !result.DryRun {
+		log.Printf("Service %s/%s switched from %v to %v by %s", namespace, name, result.PreviousSelector,
+			result.Selector, clientapi.GetIdentity(cfg))
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetNodeList(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
@@ -1131,13 +1638,46 @@ func (apiHandler *APIHandler) handleDeploy(request *restful.Request, response *r
 		errors.HandleInternalError(response, err)
 		return
 	}
-	if err := deployment.DeployApp(appDeploymentSpec, k8sClient); err != nil {
+
+	preset := apiHandler.sManager.GetDeployPresets(apiHandler.cManager.InsecureClient()).Get(appDeploymentSpec.Namespace)
+	app, err := deployment.NewAppDeployment(appDeploymentSpec, preset)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	findings, err := lint.CheckObject("Deployment", app, apiHandler.lintPolicy())
+	if !applyLintFindings(response, findings, err) {
+		return
+	}
+
+	if err := deployment.DeployApp(appDeploymentSpec, preset, k8sClient); err != nil {
 		errors.HandleInternalError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, appDeploymentSpec)
 }
 
+// lintPolicy returns the lint policy applied to objects created or updated through the dashboard.
+func (apiHandler *APIHandler) lintPolicy() settingsApi.LintPolicy {
+	return apiHandler.sManager.GetLintPolicy(apiHandler.cManager.InsecureClient())
+}
+
+// applyLintFindings rejects the request if linting failed or found violations of error severity and returns
+// false. Otherwise warnings are added to the response and true is returned.
+func applyLintFindings(response *restful.Response, findings lint.Findings, err error) bool {
+	if err == nil {
+		err = findings.Error()
+	}
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return false
+	}
+
+	findings.SetWarnings(response.Header())
+	return true
+}
+
 func (apiHandler *APIHandler) handleScaleResource(request *restful.Request, response *restful.Response) {
 	cfg, err := apiHandler.cManager.Config(request)
 	if err != nil {
@@ -1149,30 +1689,701 @@ func (apiHandler *APIHandler) handleScaleResource(request *restful.Request, resp
 	kind := request.PathParameter("kind")
 	name := request.PathParameter("name")
 	count := request.QueryParameter("scaleBy")
+
+	// Scale with 'at' parameter is only scheduled, it is done by the backend later.
+	if at := request.QueryParameter("at"); len(at) > 0 {
+		scheduleAt, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			errors.HandleInternalError(response, errors.NewBadRequest("at has to be an RFC 3339 time"))
+			return
+		}
+
+		scheduled, err := apiHandler.scaleScheduler.Schedule(cfg, kind, namespace, name, count, scheduleAt)
+		if err != nil {
+			errors.HandleInternalError(response, err)
+			return
+		}
+		response.WriteHeaderAndEntity(http.StatusAccepted, scheduled)
+		return
+	}
+
 	replicaCountSpec, err := scaling.ScaleResource(cfg, kind, namespace, name, count)
 	if err != nil {
 		errors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, replicaCountSpec)
+	response.WriteHeaderAndEntity(http.StatusOK, replicaCountSpec)
+}
+
+func (apiHandler *APIHandler) handleGetScheduledScales(request *restful.Request, response *restful.Response) {
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, apiHandler.scaleScheduler.List(cfg))
+}
+
+func (apiHandler *APIHandler) handleCancelScheduledScale(request *restful.Request, response *restful.Response) {
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if err := apiHandler.scaleScheduler.Cancel(cfg, request.PathParameter("id")); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaCount(request *restful.Request, response *restful.Response) {
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	kind := request.PathParameter("kind")
+	name := request.PathParameter("name")
+	replicaCounts, err := scaling.GetReplicaCounts(cfg, kind, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, replicaCounts)
+}
+
+func (apiHandler *APIHandler) handleGetPodCleanupCandidates(request *restful.Request, response *restful.Response) {
+	spec := &pod.PodCleanupSpec{OlderThan: request.QueryParameter("olderThan")}
+	if states := request.QueryParameter("states"); len(states) > 0 {
+		spec.States = strings.Split(states, ",")
+	}
+
+	apiHandler.cleanupPods(request, response, spec)
+}
+
+func (apiHandler *APIHandler) handleCleanupPods(request *restful.Request, response *restful.Response) {
+	spec := new(pod.PodCleanupSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	apiHandler.cleanupPods(request, response, spec)
+}
+
+func (apiHandler *APIHandler) cleanupPods(request *restful.Request, response *restful.Response,
+	spec *pod.PodCleanupSpec) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	if request.QueryParameter("async") == "true" {
+		apiHandler.cleanupPodsAsync(request, response, k8sClient, namespace, spec)
+		return
+	}
+
+	result, err := pod.CleanupPods(k8sClient, namespace, spec)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// cleanupPodsAsync starts cleanup as an operation and returns it right away. Progress can be polled with
+// 'GET /operation/{id}'.
+func (apiHandler *APIHandler) cleanupPodsAsync(request *restful.Request, response *restful.Response,
+	k8sClient kubernetes.Interface, namespace *common.NamespaceQuery, spec *pod.PodCleanupSpec) {
+	if err := pod.ValidatePodCleanupSpec(namespace, spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	owner, err := operation.GetOwner(apiHandler.cManager, request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := apiHandler.opManager.Start(owner, operation.TypePodCleanup,
+		func(ctx context.Context, reporter *operation.Reporter) (interface{}, error) {
+			return pod.CleanupPodsWithProgress(ctx, k8sClient, namespace, spec, reporter.Report)
+		})
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusAccepted, result)
+}
+
+func (apiHandler *APIHandler) handleGetNetworkPolicyList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	result, err := networkpolicy.GetNetworkPolicyList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNetworkPolicyDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := networkpolicy.GetNetworkPolicyDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReferenceReport(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := reference.GetReferenceReport(k8sClient, namespace)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetStorageReport(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	threshold := storagereport.DefaultUnboundThreshold
+	if minutes := request.QueryParameter("unboundMinutes"); len(minutes) > 0 {
+		value, err := strconv.Atoi(minutes)
+		if err != nil {
+			errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+			return
+		}
+		threshold = time.Duration(value) * time.Minute
+	}
+
+	result, err := storagereport.GetStorageReport(k8sClient, threshold)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleSetReclaimPolicy(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	spec := new(storagereport.ReclaimPolicySpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if err := storagereport.SetReclaimPolicy(k8sClient, request.PathParameter("name"), spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleGetApplicationList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	result, err := application.GetApplicationList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetApplicationDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := application.GetApplicationDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetIdleWorkloads(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	criteria := idleworkload.IdleCriteria{CPUThreshold: idleworkload.DefaultCPUThreshold}
+	criteria.Window, err = parseDurationQueryParameter(request, "window", idleworkload.DefaultWindow)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	if threshold := request.QueryParameter("cpuThreshold"); len(threshold) > 0 {
+		criteria.CPUThreshold, err = strconv.ParseInt(threshold, 10, 64)
+		if err != nil {
+			errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+			return
+		}
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	result, err := idleworkload.GetIdleWorkloadList(k8sClient, apiHandler.iManager.Metric().Client(), namespace,
+		dataSelect, criteria)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) getRecommendation(request *restful.Request) (
+	*recommendation.Recommendation, error) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return recommendation.GetRecommendation(k8sClient, dynamicClient, apiHandler.iManager.Metric().Client(),
+		request.PathParameter("kind"), request.PathParameter("namespace"), request.PathParameter("name"))
+}
+
+func (apiHandler *APIHandler) handleGetRecommendation(request *restful.Request, response *restful.Response) {
+	result, err := apiHandler.getRecommendation(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleApplyRecommendation(request *restful.Request, response *restful.Response) {
+	spec := new(recommendation.ApplySpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := apiHandler.getRecommendation(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if err := recommendation.CheckExpected(result, spec.Containers); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	verber, err := apiHandler.cManager.VerberClient(request, cfg)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if err := recommendation.ApplyRecommendation(verber, result); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetProbeAnalytics(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	window, err := parseDurationQueryParameter(request, "window", probe.DefaultWindow)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	bucket, err := parseDurationQueryParameter(request, "bucket", probe.DefaultBucket)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := probe.GetProbeAnalytics(k8sClient, request.PathParameter("kind"),
+		request.PathParameter("namespace"), request.PathParameter("name"), window, bucket)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetFlappingReport(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	window, err := parseDurationQueryParameter(request, "window", probe.DefaultWindow)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	threshold := int64(probe.DefaultFlappingThreshold)
+	if value := request.QueryParameter("threshold"); len(value) > 0 {
+		threshold, err = strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+			return
+		}
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := probe.GetFlappingReport(k8sClient, namespace, window, int32(threshold))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetRestartStormList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	thresholds := restartstorm.DefaultThresholds
+	if value := request.QueryParameter("thresholds"); len(value) > 0 {
+		thresholds, err = restartstorm.ParseThresholds(value)
+		if err != nil {
+			errors.HandleInternalError(response, err)
+			return
+		}
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := restartstorm.GetRestartStormList(k8sClient, namespace, thresholds)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetAttentionList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	gracePeriod, err := parseDurationQueryParameter(request, "gracePeriod", attention.DefaultGracePeriod)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := attention.GetAttentionList(k8sClient, namespace, gracePeriod)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleSimulateScheduling(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	pod := new(v1.Pod)
+	if err := request.ReadEntity(pod); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := scheduling.SimulateScheduling(k8sClient, pod)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetActivityHeatmap(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	activityRange, err := activity.ParseRange(request.QueryParameter("range"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := activity.GetActivityHeatmap(k8sClient, namespace, activityRange)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetComplianceSummary(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := compliance.GetComplianceSummary(k8sClient, dynamicClient)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNodeCompliance(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := compliance.GetNodeCompliance(k8sClient, dynamicClient, request.PathParameter("name"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPolicyViolationSummary(request *restful.Request,
+	response *restful.Response) {
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := policyreport.GetPolicyViolationSummary(dynamicClient)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetWorkloadViolations(request *restful.Request, response *restful.Response) {
+	cfg, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := policyreport.GetWorkloadViolationList(dynamicClient, request.PathParameter("namespace"),
+		request.PathParameter("kind"), request.PathParameter("name"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetRuntimeEvents(request *restful.Request, response *restful.Response) {
+	if !apiHandler.iManager.Falco().Enabled() {
+		errors.HandleInternalError(response, errors.NewNotFound("falco integration is not configured"))
+		return
+	}
+
+	// Events are kept by the dashboard, so access to them is checked against access to the affected pods.
+	namespace := request.PathParameter("namespace")
+	pod := request.PathParameter("pod")
+	verb := "list"
+	if len(pod) > 0 {
+		verb = "get"
+	}
+	if !apiHandler.cManager.CanI(request, clientapi.ToSelfSubjectAccessReview(namespace, pod, "pod", verb)) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			"access to pods is required to see their runtime security events"))
+		return
+	}
+
+	result := apiHandler.iManager.Falco().List(namespace, pod, request.QueryParameter("priority"))
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Workloads are read with credentials of the user, so only accessible ones are checked.
+func (apiHandler *APIHandler) handleGetImageUpdates(request *restful.Request, response *restful.Response) {
+	if !apiHandler.iManager.ImageUpdate().Enabled() {
+		errors.HandleInternalError(response, errors.NewNotFound("image update integration is not configured"))
+		return
+	}
+
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := apiHandler.iManager.ImageUpdate().List(k8sClient, request.PathParameter("namespace"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleBumpImage(request *restful.Request, response *restful.Response) {
+	if !apiHandler.iManager.ImageUpdate().Enabled() {
+		errors.HandleInternalError(response, errors.NewNotFound("image update integration is not configured"))
+		return
+	}
+
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	spec := new(imageupdate.BumpSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := apiHandler.iManager.ImageUpdate().Bump(k8sClient, request.PathParameter("kind"),
+		request.PathParameter("namespace"), request.PathParameter("name"), spec)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaCount(request *restful.Request, response *restful.Response) {
-	cfg, err := apiHandler.cManager.Config(request)
+func (apiHandler *APIHandler) handleGetOOMReport(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
 		errors.HandleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	kind := request.PathParameter("kind")
-	name := request.PathParameter("name")
-	replicaCounts, err := scaling.GetReplicaCounts(cfg, kind, namespace, name)
+	namespace := parseNamespacePathParameter(request)
+	result, err := oomreport.GetOOMReport(k8sClient, apiHandler.iManager.Metric().Client(), namespace)
 	if err != nil {
 		errors.HandleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, replicaCounts)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
 func (apiHandler *APIHandler) handleDeployFromFile(request *restful.Request, response *restful.Response) {
@@ -1188,6 +2399,11 @@ func (apiHandler *APIHandler) handleDeployFromFile(request *restful.Request, res
 		return
 	}
 
+	findings, err := lint.CheckManifests(deploymentSpec.Content, apiHandler.lintPolicy())
+	if !applyLintFindings(response, findings, err) {
+		return
+	}
+
 	isDeployed, err := deployment.DeployAppFromFile(cfg, deploymentSpec)
 	if !isDeployed {
 		errors.HandleInternalError(response, err)
@@ -1252,6 +2468,15 @@ func (apiHandler *APIHandler) handleProtocolValidity(request *restful.Request, r
 	response.WriteHeaderAndEntity(http.StatusOK, validation.ValidateProtocol(spec))
 }
 
+func (apiHandler *APIHandler) handleIPValidity(request *restful.Request, response *restful.Response) {
+	spec := new(validation.IPValiditySpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, validation.ValidateIP(spec))
+}
+
 func (apiHandler *APIHandler) handleGetAvailableProtocols(request *restful.Request, response *restful.Response) {
 	response.WriteHeaderAndEntity(http.StatusOK, deployment.GetAvailableProtocols())
 }
@@ -1391,8 +2616,31 @@ func (apiHandler *APIHandler) handleGetPodEvents(request *restful.Request, respo
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+func (apiHandler *APIHandler) handleGetPodImagePullProgress(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("pod")
+	result, err := pod.GetImagePullProgress(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 // Handles execute shell API call
 func (apiHandler *APIHandler) handleExecShell(request *restful.Request, response *restful.Response) {
+	if !apiHandler.fManager.Enabled(featuresApi.Exec) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			"exec into containers is disabled by the Exec feature gate"))
+		return
+	}
+
 	sessionID, err := genTerminalSessionId()
 	if err != nil {
 		errors.HandleInternalError(response, err)
@@ -1411,11 +2659,7 @@ func (apiHandler *APIHandler) handleExecShell(request *restful.Request, response
 		return
 	}
 
-	terminalSessions.Set(sessionID, TerminalSession{
-		id:       sessionID,
-		bound:    make(chan error),
-		sizeChan: make(chan remotecommand.TerminalSize),
-	})
+	terminalSessions.Set(sessionID, newTerminalSession(sessionID))
 	go WaitForTerminal(k8sClient, cfg, request, sessionID)
 	response.WriteHeaderAndEntity(http.StatusOK, TerminalResponse{ID: sessionID})
 }
@@ -1430,7 +2674,13 @@ func (apiHandler *APIHandler) handleGetDeployments(request *restful.Request, res
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parser.ParseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := deployment.GetDeploymentList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	var result interface{}
+	if label := request.QueryParameter(common.GroupByQueryParameter); len(label) > 0 {
+		result, err = deployment.GetDeploymentGroupList(k8sClient, namespace, dataSelect,
+			apiHandler.iManager.Metric().Client(), label)
+	} else {
+		result, err = deployment.GetDeploymentList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	}
 	if err != nil {
 		errors.HandleInternalError(response, err)
 		return
@@ -1474,6 +2724,24 @@ func (apiHandler *APIHandler) handleGetDeploymentEvents(request *restful.Request
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+func (apiHandler *APIHandler) handleGetDeploymentDependencies(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.GetDeploymentDependencies(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
@@ -1493,6 +2761,30 @@ func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(request *restful
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+func (apiHandler *APIHandler) handleGetDeploymentCanary(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	window, err := parseDurationQueryParameter(request, "window", deployment.DefaultCanaryWindow)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.GetCanaryComparison(k8sClient, apiHandler.iManager.Metric().Client(), namespace, name,
+		window)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetDeploymentNewReplicaSet(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
@@ -1522,7 +2814,12 @@ func (apiHandler *APIHandler) handleGetPods(request *restful.Request, response *
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parser.ParseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics // download standard metrics - cpu, and memory - by default
-	result, err := pod.GetPodList(k8sClient, apiHandler.iManager.Metric().Client(), namespace, dataSelect)
+	var result interface{}
+	if label := request.QueryParameter(common.GroupByQueryParameter); len(label) > 0 {
+		result, err = pod.GetPodGroupList(k8sClient, apiHandler.iManager.Metric().Client(), namespace, dataSelect, label)
+	} else {
+		result, err = pod.GetPodList(k8sClient, apiHandler.iManager.Metric().Client(), namespace, dataSelect)
+	}
 	if err != nil {
 		errors.HandleInternalError(response, err)
 		return
@@ -1588,6 +2885,56 @@ func (apiHandler *APIHandler) handleUpdateReplicasCount(request *restful.Request
 }
 
 func (apiHandler *APIHandler) handleGetResource(request *restful.Request, response *restful.Response) {
+	kind := request.PathParameter("kind")
+	namespace, ok := request.PathParameters()["namespace"]
+	name := request.PathParameter("name")
+	result, err := apiHandler.getRawResource(request, kind, ok, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// getRawResource returns the unmodified object from the apiserver. Secrets are returned only if the SecretReveal
+// feature is enabled and they are not redacted by the secret redaction policy.
+func (apiHandler *APIHandler) getRawResource(request *restful.Request, kind string, namespaceSet bool, namespace,
+	name string) (runtime.Object, error) {
+	if kind == api.ResourceKindSecret && !apiHandler.fManager.Enabled(featuresApi.SecretReveal) {
+		return nil, errors.NewGenericResponse(http.StatusForbidden,
+			"reading raw secrets is disabled by the SecretReveal feature gate")
+	}
+
+	config, err := apiHandler.cManager.Config(request)
+	if err != nil {
+		return nil, err
+	}
+
+	verber, err := apiHandler.cManager.VerberClient(request, config)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := verber.Get(kind, namespaceSet, namespace, name)
+	if err != nil || kind != api.ResourceKindSecret {
+		return object, err
+	}
+
+	raw := &v1.Secret{}
+	if unknown, ok := object.(*runtime.Unknown); !ok || json.Unmarshal(unknown.Raw, raw) != nil {
+		return nil, errors.NewUnexpectedObject(object)
+	}
+
+	policy := apiHandler.sManager.GetSecretRedactionPolicy(apiHandler.cManager.InsecureClient())
+	if policy.Redacts(raw.Namespace, string(raw.Type), raw.Annotations) {
+		return nil, errors.NewGenericResponse(http.StatusForbidden, secret.MsgSecretRedacted)
+	}
+	return object, nil
+}
+
+func (apiHandler *APIHandler) handlePutResource(
+	request *restful.Request, response *restful.Response) {
 	config, err := apiHandler.cManager.Config(request)
 	if err != nil {
 		errors.HandleInternalError(response, err)
@@ -1603,17 +2950,29 @@ func (apiHandler *APIHandler) handleGetResource(request *restful.Request, respon
 	kind := request.PathParameter("kind")
 	namespace, ok := request.PathParameters()["namespace"]
 	name := request.PathParameter("name")
-	result, err := verber.Get(kind, ok, namespace, name)
-	if err != nil {
+	putSpec := &runtime.Unknown{}
+	if err := request.ReadEntity(putSpec); err != nil {
 		errors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	findings, err := lint.CheckManifests(string(putSpec.Raw), apiHandler.lintPolicy())
+	if !applyLintFindings(response, findings, err) {
+		return
+	}
+
+	if err := verber.Put(kind, ok, namespace, name, putSpec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusCreated)
 }
 
-func (apiHandler *APIHandler) handlePutResource(
-	request *restful.Request, response *restful.Response) {
+// handlePatchResource patches the resource with the type of patch selected by the 'Content-Type' header. Server-side
+// apply uses the field manager from the 'fieldManager' query parameter or the 'field-manager' argument and
+// overrides conflicting fields owned by other managers only if 'force' query parameter is true.
+func (apiHandler *APIHandler) handlePatchResource(request *restful.Request, response *restful.Response) {
 	config, err := apiHandler.cManager.Config(request)
 	if err != nil {
 		errors.HandleInternalError(response, err)
@@ -1626,21 +2985,75 @@ func (apiHandler *APIHandler) handlePutResource(
 		return
 	}
 
+	mediaType, _, err := mime.ParseMediaType(request.HeaderParameter("Content-Type"))
+	if err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	data, err := ioutil.ReadAll(request.Request.Body)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
 	kind := request.PathParameter("kind")
 	namespace, ok := request.PathParameters()["namespace"]
 	name := request.PathParameter("name")
-	putSpec := &runtime.Unknown{}
-	if err := request.ReadEntity(putSpec); err != nil {
+	if patchType := k8sTypes.PatchType(mediaType); patchType != k8sTypes.ApplyPatchType {
+		err = verber.Patch(kind, ok, namespace, name, patchType, data)
+	} else {
+		findings, lintErr := lint.CheckManifests(string(data), apiHandler.lintPolicy())
+		if !applyLintFindings(response, findings, lintErr) {
+			return
+		}
+
+		fieldManager := request.QueryParameter("fieldManager")
+		if len(fieldManager) == 0 {
+			fieldManager = args.Holder.GetFieldManager()
+		}
+		err = verber.Apply(kind, ok, namespace, name, data, fieldManager, request.QueryParameter("force") == "true")
+	}
+	if err != nil {
 		errors.HandleInternalError(response, err)
 		return
 	}
 
-	if err := verber.Put(kind, ok, namespace, name, putSpec); err != nil {
+	response.WriteHeader(http.StatusOK)
+}
+
+// handleRebaseResource rebases the user's edit onto the latest version of the object after its update failed
+// because of a resourceVersion conflict. The result is either the merged object ready to be saved or the list of
+// fields changed both by the user and on the server.
+func (apiHandler *APIHandler) handleRebaseResource(request *restful.Request, response *restful.Response) {
+	spec := new(rebase.RebaseSpec)
+	if err := request.ReadEntity(spec); err != nil {
 		errors.HandleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeader(http.StatusCreated)
+	kind := request.PathParameter("kind")
+	namespace, ok := request.PathParameters()["namespace"]
+	name := request.PathParameter("name")
+	latest, err := apiHandler.getRawResource(request, kind, ok, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	unknown, ok := latest.(*runtime.Unknown)
+	if !ok {
+		errors.HandleInternalError(response, errors.NewUnexpectedObject(latest))
+		return
+	}
+
+	result, err := rebase.GetRebase(spec, unknown.Raw)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
 func (apiHandler *APIHandler) handleDeleteResource(
@@ -1733,7 +3146,7 @@ func (apiHandler *APIHandler) handleGetNamespaces(request *restful.Request, resp
 	}
 
 	dataSelect := parser.ParseDataSelectPathParameter(request)
-	result, err := ns.GetNamespaceList(k8sClient, dataSelect)
+	result, err := ns.GetMatchingNamespaceList(k8sClient, parseNamespacePathParameter(request), dataSelect)
 	if err != nil {
 		errors.HandleInternalError(response, err)
 		return
@@ -1741,6 +3154,51 @@ func (apiHandler *APIHandler) handleGetNamespaces(request *restful.Request, resp
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+// handleLookupNamespaces returns a page of names of namespaces for the namespace selector. Users who can list all
+// namespaces are served from the cache, others get namespaces that they can list themselves.
+func (apiHandler *APIHandler) handleLookupNamespaces(request *restful.Request, response *restful.Response) {
+	limit, _ := strconv.Atoi(request.QueryParameter("limit"))
+	query := &ns.NamespaceLookupQuery{
+		Filter:   request.QueryParameter("filter"),
+		Limit:    limit,
+		Continue: request.QueryParameter("continue"),
+	}
+
+	names, err := apiHandler.getNamespaceNames(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if scope := tenancy.GetScope(request); scope != nil {
+		visible := make([]string, 0, len(names))
+		for _, name := range names {
+			if scope.Allows(name) {
+				visible = append(visible, name)
+			}
+		}
+		names = visible
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, ns.LookupNamespaces(names, query))
+}
+
+// getNamespaceNames returns names of namespaces visible to the user. The cache is used only when the user can list
+// namespaces, so that it never reveals namespaces to users who could not see them otherwise.
+func (apiHandler *APIHandler) getNamespaceNames(request *restful.Request) ([]string, error) {
+	review := clientapi.ToSelfSubjectAccessReview("", "", api.ResourceKindNamespace, "list")
+	if apiHandler.cManager.CanI(request, review) {
+		if names, synced := apiHandler.namespaceCache.Names(); synced {
+			return names, nil
+		}
+	}
+
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		return nil, err
+	}
+	return ns.ListNamespaceNames(k8sClient)
+}
+
 func (apiHandler *APIHandler) handleGetNamespaceDetail(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
@@ -1808,9 +3266,89 @@ func (apiHandler *APIHandler) handleGetSecretDetail(request *restful.Request, re
 		errors.HandleInternalError(response, err)
 		return
 	}
+
+	if !apiHandler.fManager.Enabled(featuresApi.SecretReveal) ||
+		result.IsRedactedBy(apiHandler.sManager.GetSecretRedactionPolicy(apiHandler.cManager.InsecureClient())) {
+		result.Redact()
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// handleGetSecretDataSlice returns a slice of the secret value, which is used to read values truncated in the
+// secret detail. Values can not be read when secret reveal feature is disabled.
+func (apiHandler *APIHandler) handleGetSecretDataSlice(request *restful.Request, response *restful.Response) {
+	if !apiHandler.fManager.Enabled(featuresApi.SecretReveal) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			"secret values can not be revealed"))
+		return
+	}
+
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	key := request.PathParameter("key")
+	offset, length := parseDataSliceQueryParameters(request)
+	policy := apiHandler.sManager.GetSecretRedactionPolicy(apiHandler.cManager.InsecureClient())
+	result, err := secret.GetSecretDataSlice(k8sClient, namespace, name, key, offset, length, policy)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// handleRevealSecret returns values of a secret, which are redacted in the secret detail. User has to be allowed to
+// get the secret, which is checked with an access review before it is read. All attempts are audited.
+func (apiHandler *APIHandler) handleRevealSecret(request *restful.Request, response *restful.Response) {
+	if !apiHandler.fManager.Enabled(featuresApi.SecretReveal) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			"secret values can not be revealed"))
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	target := fmt.Sprintf("secret %s/%s", namespace, name)
+	review := clientapi.ToSelfSubjectAccessReview(namespace, name, api.ResourceKindSecret, http.MethodGet)
+	if !apiHandler.cManager.CanI(request, review) {
+		apiHandler.audit(request, authApi.AuditSecretRevealDenied, target)
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			fmt.Sprintf("not allowed to get %s", target)))
+		return
+	}
+
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := secret.GetSecretValues(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	apiHandler.audit(request, authApi.AuditSecretRevealed, target)
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+// Records event caused by the request with the identity of its credentials.
+func (apiHandler *APIHandler) audit(request *restful.Request, event authApi.AuditEvent, message string) {
+	if apiHandler.auditLogger == nil {
+		return
+	}
+
+	record := authApi.NewAuditRecord(event, getIdentity(apiHandler.cManager, request), request.Request)
+	record.Message = message
+	apiHandler.auditLogger.Log(record)
+}
+
 func (apiHandler *APIHandler) handleGetSecretList(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
@@ -1862,6 +3400,25 @@ func (apiHandler *APIHandler) handleGetConfigMapDetail(request *restful.Request,
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+func (apiHandler *APIHandler) handleGetConfigMapDataSlice(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("configmap")
+	key := request.PathParameter("key")
+	offset, length := parseDataSliceQueryParameters(request)
+	result, err := configmap.GetConfigMapDataSlice(k8sClient, namespace, name, key, offset, length)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetPersistentVolumeList(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.cManager.Client(request)
 	if err != nil {
@@ -1992,7 +3549,13 @@ func (apiHandler *APIHandler) handleGetDaemonSetList(request *restful.Request, r
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parser.ParseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := daemonset.GetDaemonSetList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	var result interface{}
+	if label := request.QueryParameter(common.GroupByQueryParameter); len(label) > 0 {
+		result, err = daemonset.GetDaemonSetGroupList(k8sClient, namespace, dataSelect,
+			apiHandler.iManager.Metric().Client(), label)
+	} else {
+		result, err = daemonset.GetDaemonSetList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	}
 	if err != nil {
 		errors.HandleInternalError(response, err)
 		return
@@ -2560,7 +4123,8 @@ func (apiHandler *APIHandler) handleLogFile(request *restful.Request, response *
 
 // parseNamespacePathParameter parses namespace selector for list pages in path parameter.
 // The namespace selector is a comma separated list of namespaces that are trimmed.
-// No namespaces means "view all user namespaces", i.e., everything except kube-system.
+// No namespaces means "view all user namespaces", i.e., everything except kube-system. When tenancy is enabled, it
+// means all namespaces visible to the user instead.
 func parseNamespacePathParameter(request *restful.Request) *common.NamespaceQuery {
 	namespace := request.PathParameter("namespace")
 	namespaces := strings.Split(namespace, ",")
@@ -2571,5 +4135,33 @@ func parseNamespacePathParameter(request *restful.Request) *common.NamespaceQuer
 			nonEmptyNamespaces = append(nonEmptyNamespaces, n)
 		}
 	}
+
+	if scope := tenancy.GetScope(request); scope != nil && len(nonEmptyNamespaces) == 0 {
+		return common.NewRestrictedNamespaceQuery(scope.Namespaces)
+	}
 	return common.NewNamespaceQuery(nonEmptyNamespaces)
 }
+
+// parseDataSliceQueryParameters parses offset and length of config map or secret value slice. Zero is used for
+// parameters that are not set, which means slice of default length from the start of the value.
+func parseDataSliceQueryParameters(request *restful.Request) (offset int, length int) {
+	offset, _ = strconv.Atoi(request.QueryParameter("offset"))
+	length, _ = strconv.Atoi(request.QueryParameter("length"))
+	return offset, length
+}
+
+// parseDurationQueryParameter parses duration query parameter, i.e. "15m". Default value is returned when
+// the parameter is not set.
+func parseDurationQueryParameter(request *restful.Request, name string, defaultValue time.Duration) (
+	time.Duration, error) {
+	value := request.QueryParameter(name)
+	if len(value) == 0 {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.NewBadRequest(err.Error())
+	}
+	return duration, nil
+}
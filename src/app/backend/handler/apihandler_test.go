@@ -17,6 +17,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"bytes"
@@ -29,7 +30,11 @@ import (
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	"github.com/kubernetes/dashboard/src/app/backend/auth/jwe"
 	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/client/csrf"
+	"github.com/kubernetes/dashboard/src/app/backend/features"
+	"github.com/kubernetes/dashboard/src/app/backend/openapi"
 	"github.com/kubernetes/dashboard/src/app/backend/settings"
+	"github.com/kubernetes/dashboard/src/app/backend/stream"
 	"github.com/kubernetes/dashboard/src/app/backend/sync"
 	"github.com/kubernetes/dashboard/src/app/backend/systembanner"
 	"k8s.io/client-go/kubernetes/fake"
@@ -39,7 +44,9 @@ func getTokenManager() authApi.TokenManager {
 	c := fake.NewSimpleClientset()
 	syncManager := sync.NewSynchronizerManager(c)
 	holder := jwe.NewRSAKeyHolder(syncManager.Secret("", ""))
-	return jwe.NewJWETokenManager(holder)
+	revocations := jwe.NewSecretRevocationList(syncManager.Secret("", authApi.RevokedTokensHolderName))
+	sessions := jwe.NewSecretSessionList(syncManager.Secret("", authApi.SessionsHolderName))
+	return jwe.NewJWETokenManager(holder, revocations, sessions)
 }
 
 func TestCreateHTTPAPIHandler(t *testing.T) {
@@ -47,12 +54,102 @@ func TestCreateHTTPAPIHandler(t *testing.T) {
 	authManager := auth.NewAuthManager(cManager, getTokenManager(), authApi.AuthenticationModes{}, true)
 	sManager := settings.NewSettingsManager()
 	sbManager := systembanner.NewSystemBannerManager("Hello world!", "INFO")
-	_, err := CreateHTTPAPIHandler(nil, cManager, authManager, sManager, sbManager)
+	fManager, _ := features.NewFeatureGateManager("", sManager, fake.NewSimpleClientset())
+	_, err := CreateHTTPAPIHandler(nil, cManager, authManager, sManager, sbManager, fManager, nil)
 	if err != nil {
 		t.Fatal("CreateHTTPAPIHandler() cannot create HTTP API handler")
 	}
 }
 
+func TestAPIDocs(t *testing.T) {
+	cManager := client.NewClientManager("", "http://localhost:8080")
+	authManager := auth.NewAuthManager(cManager, getTokenManager(), authApi.AuthenticationModes{}, true)
+	sManager := settings.NewSettingsManager()
+	sbManager := systembanner.NewSystemBannerManager("Hello world!", "INFO")
+	fManager, _ := features.NewFeatureGateManager("", sManager, fake.NewSimpleClientset())
+	apiHandler, err := CreateHTTPAPIHandler(nil, cManager, authManager, sManager, sbManager, fManager, nil)
+	if err != nil {
+		t.Fatal("CreateHTTPAPIHandler() cannot create HTTP API handler")
+	}
+
+	recorder := httptest.NewRecorder()
+	apiHandler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, openapi.DocumentPath, nil))
+	doc := new(openapi.Document)
+	if err := json.Unmarshal(recorder.Body.Bytes(), doc); err != nil {
+		t.Fatalf("%s should serve JSON, got %d: %s", openapi.DocumentPath, recorder.Code, err)
+	}
+
+	login := doc.Paths["/api/v1/login"]["post"]
+	if login == nil || login.RequestBody == nil {
+		t.Fatal("login should be documented with a request body")
+	}
+
+	for _, name := range []string{"auth.api.LoginSpec", "auth.api.AuthResponse", "errors.APIError",
+		"resource.pod.PodList", "settings.api.Settings", "session.AppBootstrap"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("schema %s should be documented", name)
+		}
+	}
+
+	if doc.Paths["/api/v1/log/{namespace}/{pod}/{container}"] == nil {
+		t.Error("logs should be documented")
+	}
+
+	if doc.Paths["/api/v1/bootstrap"]["get"] == nil {
+		t.Error("bootstrap should be documented")
+	}
+}
+
+type writeCountingRecorder struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (self *writeCountingRecorder) Write(data []byte) (int, error) {
+	self.writes++
+	return self.ResponseRecorder.Write(data)
+}
+
+func TestInstallFiltersStreamsLargeLists(t *testing.T) {
+	args.GetHolderBuilder().SetStaleCacheTTL(60).SetRequestTimeout(60)
+	defer args.GetHolderBuilder().SetStaleCacheTTL(0).SetRequestTimeout(0)
+	restful.RegisterEntityAccessor(restful.MIME_JSON, stream.NewJSONEntityAccessor(2))
+
+	type item struct {
+		Name string `json:"name"`
+	}
+	type itemList struct {
+		Items []item `json:"items"`
+	}
+	list := itemList{Items: make([]item, stream.DefaultStreamThreshold)}
+
+	ws := new(restful.WebService)
+	manager := client.NewClientManager("", "http://localhost:8080")
+	InstallFilters(ws, manager, csrf.NewSessionTokenManager(manager.CSRFKey(), nil))
+	ws.Path("/api/v1").Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/pod").To(func(request *restful.Request, response *restful.Response) {
+		response.WriteHeaderAndEntity(http.StatusOK, list)
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	recorder := &writeCountingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/pod", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, but got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorder.writes < 2 {
+		t.Errorf("Expected large list to be streamed in chunks, but it was written at once")
+	}
+
+	actual := itemList{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &actual); err != nil || len(actual.Items) != len(list.Items) {
+		t.Errorf("Expected streamed response to contain %d items", len(list.Items))
+	}
+}
+
 func TestShouldDoCsrfValidation(t *testing.T) {
 	cases := []struct {
 		request  *restful.Request
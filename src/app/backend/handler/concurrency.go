@@ -0,0 +1,110 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Number of seconds clients are asked to wait before retrying a rejected request.
+const concurrencyRetryAfter = 1
+
+// concurrencyLimiter limits the number of requests that are processed at the same time, both overall and
+// for every identity. Without it a single browser tab that refreshes aggressively could use up the
+// client-side rate limits of the dashboard service account for everyone.
+type concurrencyLimiter struct {
+	maxInFlight    int
+	maxPerIdentity int
+
+	mux      sync.Mutex
+	inFlight int
+	perUser  map[string]int
+}
+
+// acquire reserves a slot for given identity. Returns false if one of the limits has been reached.
+func (self *concurrencyLimiter) acquire(identity string) bool {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if self.maxInFlight > 0 && self.inFlight >= self.maxInFlight {
+		return false
+	}
+
+	if self.maxPerIdentity > 0 && self.perUser[identity] >= self.maxPerIdentity {
+		return false
+	}
+
+	self.inFlight++
+	self.perUser[identity]++
+	return true
+}
+
+// release frees a slot reserved by acquire.
+func (self *concurrencyLimiter) release(identity string) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	self.inFlight--
+	if self.perUser[identity]--; self.perUser[identity] <= 0 {
+		delete(self.perUser, identity)
+	}
+}
+
+func newConcurrencyLimiter(maxInFlight, maxPerIdentity int) *concurrencyLimiter {
+	return &concurrencyLimiter{
+		maxInFlight:    maxInFlight,
+		maxPerIdentity: maxPerIdentity,
+		perUser:        make(map[string]int),
+	}
+}
+
+// concurrencyLimitFilter rejects requests over 'max-requests-in-flight' and 'max-requests-per-identity'
+// limits with 429 Too Many Requests. Anonymous requests are told apart by their remote address. Long-lived
+// streaming routes are not limited.
+func concurrencyLimitFilter(manager clientapi.ClientManager, streaming *streamingRoutes) restful.FilterFunction {
+	limiter := newConcurrencyLimiter(args.Holder.GetMaxRequestsInFlight(), args.Holder.GetMaxRequestsPerIdentity())
+
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		if (limiter.maxInFlight <= 0 && limiter.maxPerIdentity <= 0) || streaming.contains(request) {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		identity := getIdentity(manager, request)
+		if identity == "anonymous" {
+			identity = identity + "@" + getRemoteAddr(request.Request)
+		}
+
+		if !limiter.acquire(identity) {
+			log.Printf("Too many concurrent requests, rejecting %s %s by %s", request.Request.Method,
+				request.Request.URL.RequestURI(), identity)
+			response.AddHeader("Retry-After", strconv.Itoa(concurrencyRetryAfter))
+			errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusTooManyRequests, ""))
+			return
+		}
+		defer limiter.release(identity)
+
+		chain.ProcessFilter(request, response)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import "testing"
+
+func TestConcurrencyLimiter(t *testing.T) {
+	limiter := newConcurrencyLimiter(3, 2)
+
+	if !limiter.acquire("alice") || !limiter.acquire("alice") {
+		t.Fatal("acquire(alice): expected first two requests to be accepted")
+	}
+
+	if limiter.acquire("alice") {
+		t.Error("acquire(alice): expected request over identity limit to be rejected")
+	}
+
+	if !limiter.acquire("bob") {
+		t.Fatal("acquire(bob): expected request of other identity to be accepted")
+	}
+
+	if limiter.acquire("carol") {
+		t.Error("acquire(carol): expected request over overall limit to be rejected")
+	}
+
+	limiter.release("alice")
+	if !limiter.acquire("carol") {
+		t.Error("acquire(carol): expected request to be accepted after a slot was released")
+	}
+
+	limiter.release("alice")
+	limiter.release("bob")
+	limiter.release("carol")
+	if limiter.inFlight != 0 || len(limiter.perUser) != 0 {
+		t.Errorf("release(): expected all slots to be free, got %d in flight and %v", limiter.inFlight,
+			limiter.perUser)
+	}
+}
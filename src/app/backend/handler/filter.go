@@ -24,12 +24,12 @@ import (
 	"time"
 
 	"github.com/emicklei/go-restful"
-	"golang.org/x/net/xsrftoken"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 
 	"github.com/kubernetes/dashboard/src/app/backend/args"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/client/csrf"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
 )
 
@@ -40,22 +40,41 @@ const (
 )
 
 // InstallFilters installs defined filter for given web service
-func InstallFilters(ws *restful.WebService, manager clientapi.ClientManager) {
+func InstallFilters(ws *restful.WebService, manager clientapi.ClientManager, csrfManager *csrf.SessionTokenManager) {
+	streaming := newStreamingRoutes(ws)
+
 	ws.Filter(requestAndResponseLogger)
+	ws.Filter(slowRequestLogger(manager, streaming))
 	ws.Filter(metricsFilter)
-	ws.Filter(validateXSRFFilter(manager.CSRFKey()))
-	ws.Filter(restrictedResourcesFilter)
+	ws.Filter(validateXSRFFilter(csrfManager))
+	ws.Filter(embedTokenFilter)
+	ws.Filter(concurrencyLimitFilter(manager, streaming))
+	ws.Filter(requestTimeoutFilter(streaming))
+	ws.Filter(staleResponseFilter(manager, streaming))
+	ws.Filter(restrictedResourcesFilter(manager))
+
+	if policy := newReadOnlyPolicy(); policy != nil {
+		ws.Filter(policy.filter)
+	}
 }
 
-// Filter used to restrict access to dashboard exclusive resource, i.e. secret used to store dashboard encryption key.
-func restrictedResourcesFilter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
-	if !authApi.ShouldRejectRequest(request.Request.URL.String()) {
+// Filter used to restrict access to dashboard exclusive resource, i.e. secret used to store dashboard encryption key,
+// and to resources protected by the configuration.
+func restrictedResourcesFilter(manager clientapi.ClientManager) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		if authApi.ShouldRejectRequest(request.Request.URL.String()) {
+			errors.HandleInternalError(response, errors.NewUnauthorized(errors.MsgDashboardExclusiveResourceError))
+			return
+		}
+
+		if target, ok := getProtectionTarget(request); ok && isProtectedTarget(manager, request, target) {
+			errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+				fmt.Sprintf(authApi.MsgResourceProtected, target.kind, target.name)))
+			return
+		}
+
 		chain.ProcessFilter(request, response)
-		return
 	}
-
-	err := errors.NewUnauthorized(errors.MsgDashboardExclusiveResourceError)
-	response.WriteHeaderAndEntity(int(err.ErrStatus.Code), err.Error())
 }
 
 // web-service filter function used for request and response logging.
@@ -72,6 +91,42 @@ func requestAndResponseLogger(request *restful.Request, response *restful.Respon
 	}
 }
 
+// slowRequestLogger logs a warning for every request that took longer than 'slow-request-threshold'. Route
+// and identity are included, so that operators can find out who runs expensive queries.
+func slowRequestLogger(manager clientapi.ClientManager, streaming *streamingRoutes) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		start := time.Now()
+		chain.ProcessFilter(request, response)
+
+		threshold := time.Duration(args.Holder.GetSlowRequestThreshold()) * time.Second
+		if threshold <= 0 || streaming.contains(request) {
+			return
+		}
+
+		if duration := time.Since(start); duration > threshold {
+			log.Printf("Slow request: %s %s (route %s) by %s from %s took %s, status %d",
+				request.Request.Method, request.Request.URL.RequestURI(), request.SelectedRoutePath(),
+				getIdentity(manager, request), getRemoteAddr(request.Request), duration.Round(time.Millisecond),
+				response.StatusCode())
+		}
+	}
+}
+
+// getIdentity returns the name of the user that made given request.
+func getIdentity(manager clientapi.ClientManager, request *restful.Request) string {
+	cmdConfig, err := manager.ClientCmdConfig(request)
+	if err != nil {
+		return "anonymous"
+	}
+
+	cfg, err := cmdConfig.ClientConfig()
+	if err != nil {
+		return "anonymous"
+	}
+
+	return clientapi.GetIdentity(cfg)
+}
+
 // formatRequestLog formats request log string.
 func formatRequestLog(request *restful.Request) string {
 	uri := ""
@@ -139,17 +194,21 @@ func metricsFilter(req *restful.Request, resp *restful.Response,
 	}
 }
 
-func validateXSRFFilter(csrfKey string) restful.FilterFunction {
+// validateXSRFFilter rejects requests that modify the cluster without a CSRF token of the session and browser the
+// request comes from. See csrf.SessionTokenManager for more information.
+func validateXSRFFilter(csrfManager *csrf.SessionTokenManager) restful.FilterFunction {
 	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		var err error
 		resource := mapUrlToResource(req.SelectedRoutePath())
+		if resource == nil {
+			err = errors.NewInvalid(csrf.MsgCSRFValidationFailed)
+		} else if shouldDoCsrfValidation(req) {
+			err = csrfManager.Validate(req.Request, *resource)
+		}
 
-		if resource == nil || (shouldDoCsrfValidation(req) &&
-			!xsrftoken.Valid(req.HeaderParameter("X-CSRF-TOKEN"), csrfKey, "none",
-				*resource)) {
-			err := errors.NewInvalid("CSRF validation failed")
+		if err != nil {
 			log.Print(err)
-			resp.AddHeader("Content-Type", "text/plain")
-			resp.WriteErrorString(http.StatusUnauthorized, err.Error()+"\n")
+			errors.HandleInternalError(resp, errors.NewUnauthorized(err.Error()))
 			return
 		}
 
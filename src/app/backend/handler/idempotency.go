@@ -0,0 +1,250 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// IdempotencyKeyHeader is set by clients on mutating requests that may be retried.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from the idempotency cache.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// Maximum number of responses kept for deduplication.
+	idempotencyCacheSize = 1024
+	// Responses larger than this are never kept for deduplication.
+	idempotencyCacheMaxEntrySize = 1 << 20
+	// Maximum length of an idempotency key.
+	idempotencyKeyMaxLength = 255
+
+	msgIdempotencyKeyTooLong    = "Idempotency key cannot be longer than 255 characters"
+	msgIdempotencyKeyInProgress = "Request with the same idempotency key is in progress"
+	msgIdempotencyKeyReused     = "Idempotency key was already used for a different request"
+)
+
+type idempotencyCacheEntry struct {
+	key string
+	// fingerprint of the request, i.e. its method, URI and body.
+	fingerprint string
+	// done is false until the response is known.
+	done    bool
+	status  int
+	header  http.Header
+	body    []byte
+	created time.Time
+}
+
+// idempotencyCache keeps responses of mutating requests by their idempotency keys. Keys are reserved before the
+// request is processed, so that concurrent duplicates are rejected. It is a bounded LRU cache and is safe for
+// concurrent use.
+type idempotencyCache struct {
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List
+	mux     sync.Mutex
+}
+
+// reserve returns the entry for given key if it is known, otherwise it reserves the key for the request with given
+// fingerprint and returns false.
+func (self *idempotencyCache) reserve(key, fingerprint string) (idempotencyCacheEntry, bool) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if element, ok := self.entries[key]; ok {
+		entry := element.Value.(*idempotencyCacheEntry)
+		if time.Since(entry.created) <= self.ttl {
+			self.order.MoveToFront(element)
+			return *entry, true
+		}
+		self.removeElement(element)
+	}
+
+	entry := &idempotencyCacheEntry{key: key, fingerprint: fingerprint, created: time.Now()}
+	self.entries[key] = self.order.PushFront(entry)
+	for self.order.Len() > idempotencyCacheSize {
+		self.removeElement(self.order.Back())
+	}
+
+	return idempotencyCacheEntry{}, false
+}
+
+// complete stores the response of the request that reserved given key.
+func (self *idempotencyCache) complete(key string, status int, header http.Header, body []byte) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if element, ok := self.entries[key]; ok {
+		entry := element.Value.(*idempotencyCacheEntry)
+		entry.done, entry.status, entry.header, entry.body = true, status, header, body
+	}
+}
+
+// release forgets given key, so that the request can be retried.
+func (self *idempotencyCache) release(key string) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if element, ok := self.entries[key]; ok {
+		self.removeElement(element)
+	}
+}
+
+func (self *idempotencyCache) removeElement(element *list.Element) {
+	self.order.Remove(element)
+	delete(self.entries, element.Value.(*idempotencyCacheEntry).key)
+}
+
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{ttl: ttl, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// idempotencyFilter deduplicates mutating requests with an 'Idempotency-Key' header, so that requests retried
+// because of flaky networks or double clicks do not create objects or scale workloads twice. The response of the
+// first request is kept for 'idempotency-key-ttl' seconds, separately for every set of credentials, and replayed
+// for retries. Keys are optional, requests without them are never deduplicated. Responses with server errors and
+// authorization errors are not kept, so that such requests can be retried, i.e. after the permissions were granted.
+func idempotencyFilter(manager clientapi.ClientManager) restful.FilterFunction {
+	cache := newIdempotencyCache(time.Duration(args.Holder.GetIdempotencyKeyTTL()) * time.Second)
+
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		idempotencyKey := request.HeaderParameter(IdempotencyKeyHeader)
+		if cache.ttl <= 0 || idempotencyKey == "" || !isMutatingMethod(request.Request.Method) {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		if len(idempotencyKey) > idempotencyKeyMaxLength {
+			errors.HandleInternalError(response, errors.NewBadRequest(msgIdempotencyKeyTooLong))
+			return
+		}
+
+		credentials, err := getCredentialsKey(manager, request)
+		if err != nil {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		fingerprint, err := getRequestFingerprint(request)
+		if err != nil {
+			errors.HandleInternalError(response, err)
+			return
+		}
+
+		key := credentials + " " + idempotencyKey
+		if entry, ok := cache.reserve(key, fingerprint); ok {
+			replayIdempotentResponse(response, entry, fingerprint)
+			return
+		}
+
+		original := response.ResponseWriter
+		buffered := &bufferedResponseWriter{target: original, header: make(http.Header)}
+		response.ResponseWriter = buffered
+		chain.ProcessFilter(request, response)
+		response.ResponseWriter = original
+		if buffered.unbuffered {
+			cache.release(key)
+			return
+		}
+
+		if buffered.status == 0 {
+			buffered.status = http.StatusOK
+		}
+
+		if !isIdempotentResponseKept(buffered.status) || buffered.body.Len() > idempotencyCacheMaxEntrySize {
+			cache.release(key)
+		} else {
+			cache.complete(key, buffered.status, cloneHeader(buffered.header),
+				append([]byte(nil), buffered.body.Bytes()...))
+		}
+
+		buffered.flush()
+	}
+}
+
+// replayIdempotentResponse writes the response kept for the first request with the same idempotency key.
+func replayIdempotentResponse(response *restful.Response, entry idempotencyCacheEntry, fingerprint string) {
+	if entry.fingerprint != fingerprint {
+		errors.HandleInternalError(response, errors.NewBadRequest(msgIdempotencyKeyReused))
+		return
+	}
+
+	if !entry.done {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusConflict, msgIdempotencyKeyInProgress))
+		return
+	}
+
+	for key, values := range entry.header {
+		response.Header()[key] = values
+	}
+	response.Header().Set(IdempotentReplayedHeader, "true")
+	response.WriteHeader(entry.status)
+	response.Write(entry.body)
+}
+
+// getRequestFingerprint returns a hash of the method, URI and body of given request. The body is restored, so that
+// it can be read again by regular request handlers.
+func getRequestFingerprint(request *restful.Request) (string, error) {
+	body := []byte{}
+	if request.Request.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(request.Request.Body); err != nil {
+			return "", err
+		}
+		request.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(request.Request.Method + " " + request.Request.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// isIdempotentResponseKept returns false for responses that may change for the same request, i.e. server errors or
+// rejections that depend on permissions of the user.
+func isIdempotentResponseKept(status int) bool {
+	return status < http.StatusInternalServerError && status != http.StatusUnauthorized &&
+		status != http.StatusForbidden
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func cloneHeader(header http.Header) http.Header {
+	result := make(http.Header, len(header))
+	for key, values := range header {
+		result[key] = append([]string(nil), values...)
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute)
+	if _, ok := cache.reserve("a", "post"); ok {
+		t.Fatal("reserve(a): expected key to be reserved")
+	}
+
+	if entry, ok := cache.reserve("a", "post"); !ok || entry.done {
+		t.Fatal("reserve(a): expected reserved entry in progress")
+	}
+
+	cache.complete("a", http.StatusCreated, http.Header{}, []byte("{}"))
+	if entry, ok := cache.reserve("a", "post"); !ok || !entry.done || entry.status != http.StatusCreated {
+		t.Fatalf("reserve(a): expected completed entry, got %+v", entry)
+	}
+
+	cache.release("a")
+	if _, ok := cache.reserve("a", "post"); ok {
+		t.Error("reserve(a): expected released key to be reserved again")
+	}
+
+	cache.entries["a"].Value.(*idempotencyCacheEntry).created = time.Now().Add(-time.Hour)
+	if _, ok := cache.reserve("a", "post"); ok {
+		t.Error("reserve(a): expected expired entry to be dropped")
+	}
+
+	for i := 0; i <= idempotencyCacheSize; i++ {
+		cache.reserve(string(rune('b'+i)), "post")
+	}
+	if _, ok := cache.entries["a"]; ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+}
+
+func TestIdempotencyFilter(t *testing.T) {
+	args.GetHolderBuilder().SetIdempotencyKeyTTL(60)
+	defer args.GetHolderBuilder().SetIdempotencyKeyTTL(0)
+
+	calls := 0
+	status := http.StatusCreated
+	ws := new(restful.WebService).Produces(restful.MIME_JSON)
+	ws.Filter(idempotencyFilter(client.NewClientManager("", "http://localhost:8080")))
+	ws.Route(ws.POST("/scale").To(func(request *restful.Request, response *restful.Response) {
+		calls++
+		response.WriteHeaderAndEntity(status, map[string]int{"calls": calls})
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/scale", strings.NewReader(body))
+		request.Header.Set("Content-Type", restful.MIME_JSON)
+		request.Header.Set("Authorization", "Bearer token")
+		if key != "" {
+			request.Header.Set(IdempotencyKeyHeader, key)
+		}
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	first := post("a", "{}")
+	retry := post("a", "{}")
+	if calls != 1 || retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() ||
+		retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("expected retry to be replayed, got %d calls and %d %s", calls, retry.Code, retry.Body.String())
+	}
+
+	if reused := post("a", `{"replicas":2}`); reused.Code != http.StatusBadRequest || calls != 1 {
+		t.Errorf("expected key reused for a different request to be rejected, got %d", reused.Code)
+	}
+
+	post("", "{}")
+	post("", "{}")
+	if calls != 3 {
+		t.Errorf("expected requests without a key not to be deduplicated, got %d calls", calls)
+	}
+
+	status = http.StatusInternalServerError
+	post("b", "{}")
+	post("b", "{}")
+	if calls != 5 {
+		t.Errorf("expected requests that failed with a server error to be executed again, got %d calls", calls)
+	}
+
+	for _, status = range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		before := calls
+		post(http.StatusText(status), "{}")
+		if retry := post(http.StatusText(status), "{}"); calls != before+2 ||
+			retry.Header().Get(IdempotentReplayedHeader) != "" {
+			t.Errorf("expected requests rejected with %d to be executed again, got %d calls", status, calls-before)
+		}
+	}
+
+	if long := post(strings.Repeat("k", idempotencyKeyMaxLength+1), "{}"); long.Code != http.StatusBadRequest {
+		t.Errorf("expected too long key to be rejected, got %d", long.Code)
+	}
+}
@@ -47,6 +47,20 @@ var (
 		},
 		[]string{"verb", "resource"},
 	)
+	terminalSessionsActive = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "dashboard_terminal_sessions",
+			Help: "Number of open terminal sessions, including the ones waiting for the client to connect.",
+		},
+		func() float64 { return float64(terminalSessions.Count()) },
+	)
+	terminalSessionsClosed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dashboard_terminal_sessions_closed_total",
+			Help: "Counter of closed terminal sessions broken out for each reason: exited, error, idle or unbound.",
+		},
+		[]string{"reason"},
+	)
 )
 
 // Initialize all metrics in prometheus
@@ -54,6 +68,8 @@ func init() {
 	prometheus.MustRegister(requestCounter)
 	prometheus.MustRegister(requestLatencies)
 	prometheus.MustRegister(requestLatenciesSummary)
+	prometheus.MustRegister(terminalSessionsActive)
+	prometheus.MustRegister(terminalSessionsClosed)
 }
 
 // Track API call in prometheus
@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/emicklei/go-restful"
+	"github.com/kubernetes/dashboard/src/app/backend/args"
 	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 )
@@ -26,16 +27,31 @@ import (
 func parsePaginationPathParameter(request *restful.Request) *dataselect.PaginationQuery {
 	itemsPerPage, err := strconv.ParseInt(request.QueryParameter("itemsPerPage"), 10, 0)
 	if err != nil {
-		return dataselect.NoPagination
+		return limitPagination(dataselect.NoPagination)
 	}
 
 	page, err := strconv.ParseInt(request.QueryParameter("page"), 10, 0)
 	if err != nil {
-		return dataselect.NoPagination
+		return limitPagination(dataselect.NoPagination)
 	}
 
 	// Frontend pages start from 1 and backend starts from 0
-	return dataselect.NewPaginationQuery(int(itemsPerPage), int(page-1))
+	return limitPagination(dataselect.NewPaginationQuery(int(itemsPerPage), int(page-1)))
+}
+
+// limitPagination makes sure that a single response does not return more than 'max-list-items' items.
+func limitPagination(query *dataselect.PaginationQuery) *dataselect.PaginationQuery {
+	maxItems := args.Holder.GetMaxListItems()
+	if maxItems <= 0 || (query.ItemsPerPage >= 0 && query.ItemsPerPage <= maxItems) {
+		return query
+	}
+
+	page := query.Page
+	if page < 0 {
+		page = 0
+	}
+
+	return dataselect.NewPaginationQuery(maxItems, page)
 }
 
 func parseFilterPathParameter(request *restful.Request) *dataselect.FilterQuery {
@@ -0,0 +1,128 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// getProtectionTarget returns the object accessed by a route, that has to be checked against protected resources.
+// These are /api/v1/_raw routes and routes starting with /api/v1/<kind>/{namespace}/{name} or /api/v1/<kind>/{name}
+// for non-namespaced kinds. Lists are filtered by resource modules.
+func getProtectionTarget(request *restful.Request) (*rawTarget, bool) {
+	segments := strings.Split(strings.TrimPrefix(request.SelectedRoutePath(), "/api/v1/"), "/")
+	if segments[0] == "_raw" {
+		target := &rawTarget{
+			kind:      request.PathParameter("kind"),
+			namespace: request.PathParameter("namespace"),
+			name:      request.PathParameter("name"),
+		}
+		target.namespaced = len(target.namespace) > 0
+		return target, len(target.name) > 0
+	}
+
+	mapping, known := api.KindToAPIMapping[segments[0]]
+	target := &rawTarget{kind: segments[0], namespaced: !known || mapping.Namespaced}
+	nameIndex := 1
+	if target.namespaced {
+		if len(segments) < 3 || segments[1] != "{namespace}" {
+			return nil, false
+		}
+		target.namespace = request.PathParameter("namespace")
+		nameIndex = 2
+	}
+
+	if len(segments) <= nameIndex {
+		return nil, false
+	}
+	name := segments[nameIndex]
+	if !strings.HasPrefix(name, "{") || !strings.HasSuffix(name, "}") {
+		return nil, false
+	}
+	target.name = request.PathParameter(strings.Trim(name, "{}"))
+	return target, len(target.name) > 0
+}
+
+// isProtectedTarget returns true if the target is protected by the configuration. Labels of the target are read
+// only when a protected resource with label selector matches its name. They can be read only for kinds supported
+// by the resource verber, other kinds are protected only by name.
+func isProtectedTarget(manager clientapi.ClientManager, request *restful.Request, target *rawTarget) bool {
+	needsLabels := false
+	for _, resource := range authApi.GetProtectedResources() {
+		if !resource.MatchesName(target.kind, target.namespace, target.name) {
+			continue
+		}
+		if resource.Selector == nil {
+			return true
+		}
+		needsLabels = true
+	}
+
+	if _, ok := api.KindToAPIMapping[target.kind]; !needsLabels || !ok {
+		return false
+	}
+
+	meta, err := getTargetMeta(manager, request, target)
+	if err != nil {
+		// Missing objects are reported by route handlers. Objects, that can not be checked, are not shown.
+		if errors.IsNotFoundError(err) {
+			return false
+		}
+		log.Printf("Cannot check labels of %s %s/%s: %s", target.kind, target.namespace, target.name, err.Error())
+		return true
+	}
+
+	return authApi.IsProtected(target.kind, target.namespace, target.name, meta.Labels)
+}
+
+func getTargetMeta(manager clientapi.ClientManager, request *restful.Request,
+	target *rawTarget) (*metaV1.ObjectMeta, error) {
+	config, err := manager.Config(request)
+	if err != nil {
+		return nil, err
+	}
+
+	verber, err := manager.VerberClient(request, config)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := verber.Get(target.kind, target.namespaced, target.namespace, target.name)
+	if err != nil {
+		return nil, err
+	}
+
+	unknown, ok := object.(*runtime.Unknown)
+	if !ok {
+		return nil, errors.NewUnexpectedObject(object)
+	}
+
+	partial := &metaV1.PartialObjectMetadata{}
+	if err := json.Unmarshal(unknown.Raw, partial); err != nil {
+		return nil, err
+	}
+	return &partial.ObjectMeta, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+type fakeVerber struct {
+	clientapi.ResourceVerber
+	objects map[string]string
+}
+
+func (self *fakeVerber) Get(kind string, namespaceSet bool, namespace string, name string) (runtime.Object, error) {
+	raw, ok := self.objects[kind+"/"+namespace+"/"+name]
+	if !ok {
+		return nil, errors.NewNotFound(name)
+	}
+	return &runtime.Unknown{Raw: []byte(raw)}, nil
+}
+
+type fakeVerberClientManager struct {
+	clientapi.ClientManager
+	verber *fakeVerber
+}
+
+func (self *fakeVerberClientManager) Config(req *restful.Request) (*rest.Config, error) {
+	return &rest.Config{}, nil
+}
+
+func (self *fakeVerberClientManager) VerberClient(req *restful.Request,
+	config *rest.Config) (clientapi.ResourceVerber, error) {
+	return self.verber, nil
+}
+
+func TestGetProtectionTarget(t *testing.T) {
+	cases := []struct {
+		route    string
+		path     string
+		expected *rawTarget
+	}{
+		{"/secret/{namespace}/{name}", "/api/v1/secret/default/db", &rawTarget{"secret", true, "default", "db"}},
+		{"/secret/{namespace}/{name}/data/{key}", "/api/v1/secret/default/db/data/password",
+			&rawTarget{"secret", true, "default", "db"}},
+		{"/node/{name}/pod", "/api/v1/node/worker/pod", &rawTarget{"node", false, "", "worker"}},
+		{"/_raw/{kind}/namespace/{namespace}/name/{name}", "/api/v1/_raw/configmap/namespace/default/name/app",
+			&rawTarget{"configmap", true, "default", "app"}},
+		{"/_raw/{kind}/name/{name}", "/api/v1/_raw/node/name/worker", &rawTarget{"node", false, "", "worker"}},
+		{"/secret/{namespace}", "/api/v1/secret/default", nil},
+		{"/secret", "/api/v1/secret", nil},
+	}
+
+	for _, c := range cases {
+		var actual *rawTarget
+		ws := new(restful.WebService).Path("/api/v1")
+		ws.Route(ws.GET(c.route).To(func(request *restful.Request, response *restful.Response) {
+			if target, ok := getProtectionTarget(request); ok {
+				actual = target
+			}
+		}))
+		container := restful.NewContainer()
+		container.Add(ws)
+
+		container.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, c.path, nil))
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getProtectionTarget(%s) == %+v, expected %+v", c.route, actual, c.expected)
+		}
+	}
+}
+
+func TestIsProtectedTarget(t *testing.T) {
+	if err := authApi.SetProtectedResources([]string{"secret:kube-system/*", "*/*@sensitive=true"}); err != nil {
+		t.Fatal(err)
+	}
+	defer authApi.SetProtectedResources(nil)
+
+	manager := &fakeVerberClientManager{verber: &fakeVerber{objects: map[string]string{
+		"configmap/default/app": `{"metadata":{"name":"app","labels":{"sensitive":"true"}}}`,
+		"configmap/default/web": `{"metadata":{"name":"web"}}`,
+	}}}
+
+	cases := []struct {
+		target   *rawTarget
+		expected bool
+	}{
+		{&rawTarget{"secret", true, "kube-system", "token"}, true},
+		{&rawTarget{"configmap", true, "kube-system", "token"}, false},
+		{&rawTarget{"configmap", true, "default", "app"}, true},
+		{&rawTarget{"configmap", true, "default", "web"}, false},
+		{&rawTarget{"configmap", true, "default", "missing"}, false},
+	}
+
+	for _, c := range cases {
+		request := restful.NewRequest(httptest.NewRequest(http.MethodGet, "/api/v1", nil))
+		if actual := isProtectedTarget(manager, request, c.target); actual != c.expected {
+			t.Errorf("isProtectedTarget(%+v) == %t, expected %t", c.target, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// RawQueryParameter selects whether detail responses contain the unmodified object from the apiserver.
+	RawQueryParameter = "raw"
+	// RawAlongside adds the object as 'raw' field next to the view model.
+	RawAlongside = "true"
+	// RawOnly returns the object instead of the view model.
+	RawOnly = "only"
+)
+
+// rawTarget is the object shown by a detail route.
+type rawTarget struct {
+	kind       string
+	namespaced bool
+	namespace  string
+	name       string
+}
+
+// getRawTarget returns the object shown by a detail route of a supported kind, i.e. /api/v1/pod/{namespace}/{pod}
+// or /api/v1/node/{name}. False is returned for all other routes.
+func getRawTarget(request *restful.Request) (*rawTarget, bool) {
+	segments := strings.Split(strings.TrimPrefix(request.SelectedRoutePath(), "/api/v1/"), "/")
+	mapping, ok := api.KindToAPIMapping[segments[0]]
+	if !ok {
+		return nil, false
+	}
+
+	target := &rawTarget{kind: segments[0], namespaced: mapping.Namespaced}
+	if mapping.Namespaced {
+		if len(segments) != 3 || segments[1] != "{namespace}" {
+			return nil, false
+		}
+		target.namespace = request.PathParameter("namespace")
+	} else if len(segments) != 2 {
+		return nil, false
+	}
+
+	last := segments[len(segments)-1]
+	if !strings.HasPrefix(last, "{") || !strings.HasSuffix(last, "}") {
+		return nil, false
+	}
+	target.name = request.PathParameter(strings.Trim(last, "{}"))
+	return target, true
+}
+
+// rawObjectFilter returns the unmodified object from the apiserver together with or instead of the view model of
+// detail routes, when requested with the 'raw' query parameter. Parameter is ignored by other routes.
+func (apiHandler *APIHandler) rawObjectFilter(request *restful.Request, response *restful.Response,
+	chain *restful.FilterChain) {
+	mode := request.QueryParameter(RawQueryParameter)
+	target, ok := getRawTarget(request)
+	if len(mode) == 0 || request.Request.Method != http.MethodGet || !ok {
+		chain.ProcessFilter(request, response)
+		return
+	}
+
+	if mode != RawAlongside && mode != RawOnly {
+		errors.HandleInternalError(response, errors.NewBadRequest("raw parameter has to be 'true' or 'only'"))
+		return
+	}
+
+	object, err := apiHandler.getRawResource(request, target.kind, target.namespaced, target.namespace, target.name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if mode == RawOnly {
+		response.WriteHeaderAndEntity(http.StatusOK, object)
+		return
+	}
+
+	original := response.ResponseWriter
+	buffered := &bufferedResponseWriter{target: original, header: make(http.Header)}
+	response.ResponseWriter = buffered
+	chain.ProcessFilter(request, response)
+	response.ResponseWriter = original
+	if buffered.unbuffered {
+		return
+	}
+
+	if (buffered.status != 0 && buffered.status != http.StatusOK) || !isJSONObject(buffered.body.Bytes()) {
+		buffered.flush()
+		return
+	}
+
+	body, err := addJSONField(buffered.body.Bytes(), "raw", object)
+	if err != nil {
+		buffered.flush()
+		return
+	}
+
+	buffered.body.Reset()
+	buffered.body.Write(body)
+	buffered.header.Del("Content-Length")
+	buffered.flush()
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+)
+
+func TestGetRawTarget(t *testing.T) {
+	cases := []struct {
+		route    string
+		path     string
+		expected *rawTarget
+	}{
+		{"/pod/{namespace}/{pod}", "/api/v1/pod/default/web", &rawTarget{"pod", true, "default", "web"}},
+		{"/node/{name}", "/api/v1/node/worker", &rawTarget{"node", false, "", "worker"}},
+		{"/pod/{namespace}", "/api/v1/pod/default", nil},
+		{"/pod/{namespace}/{pod}/event", "/api/v1/pod/default/web/event", nil},
+		{"/log/{namespace}/{pod}", "/api/v1/log/default/web", nil},
+	}
+
+	for _, c := range cases {
+		var actual *rawTarget
+		ws := new(restful.WebService).Path("/api/v1")
+		ws.Route(ws.GET(c.route).To(func(request *restful.Request, response *restful.Response) {
+			actual, _ = getRawTarget(request)
+		}))
+		container := restful.NewContainer()
+		container.Add(ws)
+
+		container.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, c.path, nil))
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getRawTarget(%s) == %+v, expected %+v", c.route, actual, c.expected)
+		}
+	}
+}
+
+func TestAddJSONField(t *testing.T) {
+	body, err := addJSONField([]byte(`{"objectMeta":{"name":"web"}}`), "raw", map[string]string{"kind": "Pod"})
+	if err != nil {
+		t.Fatalf("addJSONField(): unexpected error: %v", err)
+	}
+
+	expected := `{"objectMeta":{"name":"web"},"raw":{"kind":"Pod"}}`
+	if string(body) != expected {
+		t.Errorf("addJSONField() == %s, expected %s", body, expected)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/snapshot"
+)
+
+const (
+	// MsgReadOnly is returned for every attempt to modify the cluster when Dashboard runs with --read-only.
+	MsgReadOnly = "Dashboard is read-only"
+	// MsgNamespaceReadOnly is returned for every attempt to modify resources in namespaces matching
+	// --read-only-namespaces.
+	MsgNamespaceReadOnly = "namespace %s is read-only"
+
+	// StatusCauseTypeReadOnly is the type of the cause of errors returned by read-only mode.
+	StatusCauseTypeReadOnly metaV1.CauseType = "ReadOnly"
+
+	// Namespace of requests modifying resources in all namespaces, i.e. deploying a file with objects of any
+	// namespace.
+	allNamespaces = "_all"
+)
+
+// Routes that accept POST requests in read-only mode. They do not modify the cluster.
+var readOnlyAllowedPaths = map[string]bool{
+	"/api/v1/login":                                                true,
+	"/api/v1/token/refresh":                                        true,
+	"/api/v1/logout":                                               true,
+	"/api/v1/login/elevate":                                        true,
+	"/api/v1/can-i":                                                true,
+	"/api/v1/appdeployment/validate/name":                          true,
+	"/api/v1/appdeployment/validate/imagereference":                true,
+	"/api/v1/appdeployment/validate/protocol":                      true,
+	"/api/v1/appdeployment/validate/ip":                            true,
+	"/api/v1/securityreview":                                       true,
+	"/api/v1/scheduling/simulation":                                true,
+	"/api/v1/secret/{namespace}/{name}/reveal":                     true,
+	"/api/v1/session/recentnamespace/{namespace}":                  true,
+	"/api/v1/_raw/{kind}/namespace/{namespace}/name/{name}/rebase": true,
+	"/api/v1/_raw/{kind}/name/{name}/rebase":                       true,
+}
+
+// Routes that modify the cluster although they accept GET requests.
+var readOnlyDeniedPaths = map[string]bool{
+	"/api/v1/pod/{namespace}/{pod}/shell/{container}": true,
+}
+
+// MsgEmbedTokenDenied is returned for requests with embed tokens to routes that are never served with them.
+const MsgEmbedTokenDenied = "embed tokens cannot be used for this request"
+
+// embedTokenFilter rejects requests with embed tokens, that are read-only, to routes denied in read-only mode,
+// i.e. the shell, regardless of the scope of the token.
+func embedTokenFilter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	if len(request.QueryParameter(authApi.EmbedTokenParameter)) > 0 &&
+		(request.Request.Method != http.MethodGet || readOnlyDeniedPaths[request.SelectedRoutePath()]) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden, MsgEmbedTokenDenied))
+		return
+	}
+
+	chain.ProcessFilter(request, response)
+}
+
+// Routes that may modify resources in any namespace, when their request does not name one.
+var anyNamespacePaths = map[string]bool{
+	"/api/v1/podcleanup":               true,
+	"/api/v1/diagnostics/connectivity": true,
+}
+
+// readOnlyPolicy decides which requests are rejected in read-only mode. All requests that could modify the
+// cluster are rejected if all is set, otherwise only those modifying resources in namespaces matching one of
+// the patterns.
+type readOnlyPolicy struct {
+	all        bool
+	namespaces []string
+	message    string
+}
+
+// newReadOnlyPolicy creates read-only policy from Dashboard arguments. It returns nil if Dashboard is not
+// read-only. Dashboard serving a cluster snapshot is always read-only.
+func newReadOnlyPolicy() *readOnlyPolicy {
+	switch {
+	case len(args.Holder.GetSnapshotFile()) > 0:
+		return &readOnlyPolicy{all: true, message: snapshot.MsgReadOnly}
+	case args.Holder.GetReadOnly():
+		return &readOnlyPolicy{all: true, message: MsgReadOnly}
+	case len(args.Holder.GetReadOnlyNamespaces()) > 0:
+		return &readOnlyPolicy{namespaces: args.Holder.GetReadOnlyNamespaces()}
+	}
+	return nil
+}
+
+// filter rejects requests that could modify read-only resources with 403 Forbidden and an error, which details
+// describe the reason.
+func (self *readOnlyPolicy) filter(request *restful.Request, response *restful.Response,
+	chain *restful.FilterChain) {
+	if err := self.check(request); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	chain.ProcessFilter(request, response)
+}
+
+// check returns an error if given request could modify read-only resources.
+func (self *readOnlyPolicy) check(request *restful.Request) *apierrors.StatusError {
+	if !isMutatingRequest(request) {
+		return nil
+	}
+
+	if self.all {
+		return newReadOnlyError(self.message)
+	}
+
+	for _, namespace := range getRequestNamespaces(request) {
+		if namespace == allNamespaces {
+			return newReadOnlyError(fmt.Sprintf(MsgNamespaceReadOnly, strings.Join(self.namespaces, ", ")))
+		}
+
+		if self.isReadOnly(namespace) {
+			return newReadOnlyError(fmt.Sprintf(MsgNamespaceReadOnly, namespace))
+		}
+	}
+	return nil
+}
+
+func (self *readOnlyPolicy) isReadOnly(namespace string) bool {
+	for _, pattern := range self.namespaces {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isMutatingRequest returns true if given request could modify the cluster, i.e. create, update, patch, delete
+// or scale resources or exec into containers.
+func isMutatingRequest(request *restful.Request) bool {
+	route := request.SelectedRoutePath()
+	switch request.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return readOnlyDeniedPaths[route]
+	case http.MethodPost:
+		return !readOnlyAllowedPaths[route]
+	default:
+		return true
+	}
+}
+
+// getRequestNamespaces returns namespaces of the resources modified by given request. They are taken from the
+// path or, for forms, from the request body. allNamespaces is returned for requests that may modify resources
+// in any namespace. Requests modifying cluster-scoped resources have no namespace.
+func getRequestNamespaces(request *restful.Request) []string {
+	if namespace := request.PathParameter("namespace"); len(namespace) > 0 {
+		return []string{namespace}
+	}
+
+	if request.PathParameter("kind") == "namespace" {
+		return []string{request.PathParameter("name")}
+	}
+
+	namespaces := getBodyNamespaces(request)
+	if len(namespaces) == 0 && anyNamespacePaths[request.SelectedRoutePath()] {
+		return []string{allNamespaces}
+	}
+	return namespaces
+}
+
+// getBodyNamespaces reads namespaces from JSON request body, i.e. of the deploy form. The body is restored, so
+// that handlers can read it again.
+func getBodyNamespaces(request *restful.Request) []string {
+	if request.Request.Body == nil {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(request.Request.Body)
+	request.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	spec := struct {
+		Namespace  string   `json:"namespace"`
+		Namespaces []string `json:"namespaces"`
+	}{}
+	if err := json.Unmarshal(body, &spec); err != nil {
+		return nil
+	}
+
+	if len(spec.Namespace) > 0 {
+		return append(spec.Namespaces, spec.Namespace)
+	}
+	return spec.Namespaces
+}
+
+// newReadOnlyError creates 403 Forbidden error with a cause telling the frontend that the request was rejected
+// because Dashboard is read-only.
+func newReadOnlyError(message string) *apierrors.StatusError {
+	return &apierrors.StatusError{ErrStatus: metaV1.Status{
+		Status:  metaV1.StatusFailure,
+		Code:    http.StatusForbidden,
+		Reason:  metaV1.StatusReasonForbidden,
+		Message: message,
+		Details: &metaV1.StatusDetails{
+			Causes: []metaV1.StatusCause{{Type: StatusCauseTypeReadOnly, Message: message}},
+		},
+	}}
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+func TestReadOnlyPolicyFilter(t *testing.T) {
+	all := &readOnlyPolicy{all: true, message: MsgReadOnly}
+	system := &readOnlyPolicy{namespaces: []string{"kube-*"}}
+
+	cases := []struct {
+		info     string
+		policy   *readOnlyPolicy
+		method   string
+		route    string
+		path     string
+		body     string
+		rejected bool
+	}{
+		{"get is allowed", all, http.MethodGet, "/pod/{namespace}", "/api/v1/pod/default", "", false},
+		{"login is allowed", all, http.MethodPost, "/login", "/api/v1/login", "{}", false},
+		{"validation is allowed", all, http.MethodPost, "/appdeployment/validate/name",
+			"/api/v1/appdeployment/validate/name", "{}", false},
+		{"deploy is rejected", all, http.MethodPost, "/appdeployment", "/api/v1/appdeployment",
+			`{"namespace":"default"}`, true},
+		{"update is rejected", all, http.MethodPut, "/_raw/{kind}/namespace/{namespace}/name/{name}",
+			"/api/v1/_raw/pod/namespace/default/name/app", "{}", true},
+		{"scale is rejected", all, http.MethodPut, "/scale/{kind}/{namespace}/{name}",
+			"/api/v1/scale/deployment/default/app", "", true},
+		{"exec is rejected", all, http.MethodGet, "/pod/{namespace}/{pod}/shell/{container}",
+			"/api/v1/pod/default/app/shell/main", "", true},
+		{"delete in other namespace is allowed", system, http.MethodDelete,
+			"/_raw/{kind}/namespace/{namespace}/name/{name}", "/api/v1/_raw/pod/namespace/default/name/app", "",
+			false},
+		{"delete in read-only namespace is rejected", system, http.MethodDelete,
+			"/_raw/{kind}/namespace/{namespace}/name/{name}", "/api/v1/_raw/pod/namespace/kube-system/name/dns", "",
+			true},
+		{"delete of read-only namespace is rejected", system, http.MethodDelete, "/_raw/{kind}/name/{name}",
+			"/api/v1/_raw/namespace/name/kube-public", "", true},
+		{"exec in read-only namespace is rejected", system, http.MethodGet,
+			"/pod/{namespace}/{pod}/shell/{container}", "/api/v1/pod/kube-system/dns/shell/main", "", true},
+		{"deploy into read-only namespace is rejected", system, http.MethodPost, "/appdeployment",
+			"/api/v1/appdeployment", `{"name":"app","namespace":"kube-system"}`, true},
+		{"deploy into other namespace is allowed", system, http.MethodPost, "/appdeployment",
+			"/api/v1/appdeployment", `{"name":"app","namespace":"default"}`, false},
+		{"deploy of file into all namespaces is rejected", system, http.MethodPost, "/appdeploymentfromfile",
+			"/api/v1/appdeploymentfromfile", `{"namespace":"_all"}`, true},
+		{"grant in read-only namespace is rejected", system, http.MethodPost, "/accessgrant",
+			"/api/v1/accessgrant", `{"namespaces":["default","kube-system"]}`, true},
+		{"cleanup of all namespaces is rejected", system, http.MethodPost, "/podcleanup", "/api/v1/podcleanup",
+			`{"states":["Failed"]}`, true},
+		{"cluster-scoped update is allowed", system, http.MethodPut, "/_raw/{kind}/name/{name}",
+			"/api/v1/_raw/node/name/worker", "{}", false},
+	}
+
+	for _, c := range cases {
+		body := ""
+		handled := false
+		ws := new(restful.WebService).Path("/api/v1").Produces(restful.MIME_JSON)
+		ws.Filter(c.policy.filter)
+		ws.Route(ws.Method(c.method).Path(c.route).To(func(request *restful.Request, response *restful.Response) {
+			handled = true
+			bytes, _ := ioutil.ReadAll(request.Request.Body)
+			body = string(bytes)
+		}))
+		container := restful.NewContainer()
+		container.Add(ws)
+
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, httptest.NewRequest(c.method, c.path, strings.NewReader(c.body)))
+		if handled == c.rejected {
+			t.Errorf("%s: expected rejected to be %t, got status %d", c.info, c.rejected, recorder.Code)
+			continue
+		}
+
+		if !c.rejected {
+			if body != c.body {
+				t.Errorf("%s: handler should read the whole body %q, got %q", c.info, c.body, body)
+			}
+			continue
+		}
+
+		apiError := errors.APIError{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &apiError); err != nil {
+			t.Fatalf("%s: %s", c.info, err)
+		}
+
+		if recorder.Code != http.StatusForbidden || apiError.Code != errors.CodeForbidden ||
+			len(apiError.Details) == 0 || apiError.Details[0].Type != string(StatusCauseTypeReadOnly) {
+			t.Errorf("%s: expected 403 with read-only cause, got %d %s", c.info, recorder.Code, recorder.Body)
+		}
+	}
+}
+
+func TestEmbedTokenFilter(t *testing.T) {
+	cases := []struct {
+		info     string
+		route    string
+		path     string
+		rejected bool
+	}{
+		{"shell with embed token", "/pod/{namespace}/{pod}/shell/{container}",
+			"/api/v1/pod/default/foo/shell/bar?embedToken=token", true},
+		{"shell without embed token", "/pod/{namespace}/{pod}/shell/{container}",
+			"/api/v1/pod/default/foo/shell/bar", false},
+		{"detail with embed token", "/pod/{namespace}/{pod}", "/api/v1/pod/default/foo?embedToken=token", false},
+	}
+
+	for _, c := range cases {
+		handled := false
+		ws := new(restful.WebService).Path("/api/v1").Produces(restful.MIME_JSON)
+		ws.Filter(embedTokenFilter)
+		ws.Route(ws.GET(c.route).To(func(request *restful.Request, response *restful.Response) {
+			handled = true
+		}))
+		container := restful.NewContainer()
+		container.Add(ws)
+
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if handled == c.rejected {
+			t.Errorf("%s: expected rejected to be %t, got status %d", c.info, c.rejected, recorder.Code)
+		}
+
+		if c.rejected && recorder.Code != http.StatusForbidden {
+			t.Errorf("%s: expected status %d, got %d", c.info, http.StatusForbidden, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+)
+
+const (
+	// Maximum number of responses kept for fallback.
+	staleCacheSize = 256
+	// Responses larger than this are never kept for fallback.
+	staleCacheMaxEntrySize = 1 << 20
+)
+
+// StaleMarker is added as 'stale' field to responses served from the fallback cache.
+type StaleMarker struct {
+	// Time when the data was fetched from the apiserver.
+	AsOf time.Time `json:"asOf"`
+	// Error that prevented fetching fresh data.
+	Error string `json:"error"`
+}
+
+type staleCacheEntry struct {
+	key     string
+	body    []byte
+	fetched time.Time
+}
+
+// staleCache keeps last successful responses of GET requests, so they can be served when the apiserver is
+// degraded. It is a bounded LRU cache and is safe for concurrent use.
+type staleCache struct {
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List
+	mux     sync.Mutex
+}
+
+// get returns the last known response for given key unless it is older than the cache TTL.
+func (self *staleCache) get(key string) (*staleCacheEntry, bool) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	element, ok := self.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*staleCacheEntry)
+	if time.Since(entry.fetched) > self.ttl {
+		self.removeElement(element)
+		return nil, false
+	}
+
+	self.order.MoveToFront(element)
+	return entry, true
+}
+
+// add stores the response, evicting the least recently used entry if the cache is full.
+func (self *staleCache) add(key string, body []byte) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if element, ok := self.entries[key]; ok {
+		self.removeElement(element)
+	}
+
+	entry := &staleCacheEntry{key: key, body: body, fetched: time.Now()}
+	self.entries[key] = self.order.PushFront(entry)
+	for self.order.Len() > staleCacheSize {
+		self.removeElement(self.order.Back())
+	}
+}
+
+func (self *staleCache) removeElement(element *list.Element) {
+	self.order.Remove(element)
+	delete(self.entries, element.Value.(*staleCacheEntry).key)
+}
+
+func newStaleCache(ttl time.Duration) *staleCache {
+	return &staleCache{ttl: ttl, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// bufferedResponseWriter holds the whole response in memory, so that it can be replaced before it is sent.
+// Streamed lists are passed through to the target writer, see stream.Unbufferer.
+type bufferedResponseWriter struct {
+	target     http.ResponseWriter
+	header     http.Header
+	status     int
+	body       bytes.Buffer
+	unbuffered bool
+}
+
+func (self *bufferedResponseWriter) Header() http.Header {
+	if self.unbuffered {
+		return self.target.Header()
+	}
+
+	return self.header
+}
+
+func (self *bufferedResponseWriter) WriteHeader(status int) {
+	if self.unbuffered {
+		self.target.WriteHeader(status)
+		return
+	}
+
+	if self.status == 0 {
+		self.status = status
+	}
+}
+
+func (self *bufferedResponseWriter) Write(data []byte) (int, error) {
+	if self.unbuffered {
+		return self.target.Write(data)
+	}
+
+	self.WriteHeader(http.StatusOK)
+	return self.body.Write(data)
+}
+
+// Unbuffer implements stream.Unbufferer. Response is not kept for fallback once it is unbuffered.
+func (self *bufferedResponseWriter) Unbuffer() {
+	if self.unbuffered {
+		return
+	}
+
+	for key, values := range self.header {
+		self.target.Header()[key] = values
+	}
+
+	if self.status != 0 {
+		self.target.WriteHeader(self.status)
+	}
+
+	if self.body.Len() > 0 {
+		self.target.Write(self.body.Bytes())
+		self.body.Reset()
+	}
+
+	self.unbuffered = true
+}
+
+// flush sends buffered response to the target writer.
+func (self *bufferedResponseWriter) flush() {
+	for key, values := range self.header {
+		self.target.Header()[key] = values
+	}
+
+	if self.status == 0 {
+		self.status = http.StatusOK
+	}
+	self.target.WriteHeader(self.status)
+	self.target.Write(self.body.Bytes())
+}
+
+// staleResponseFilter serves the last successful response of a GET request if the request fails with
+// a server error, i.e. because the apiserver times out. Served data is marked with StaleMarker and
+// a 'Warning' header. Responses are kept for 'stale-cache-ttl' seconds, separately for every set of
+// credentials. Streamed lists are neither buffered nor kept.
+func staleResponseFilter(manager clientapi.ClientManager, streaming *streamingRoutes) restful.FilterFunction {
+	cache := newStaleCache(time.Duration(args.Holder.GetStaleCacheTTL()) * time.Second)
+
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		if cache.ttl <= 0 || request.Request.Method != http.MethodGet || streaming.contains(request) {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		credentials, err := getCredentialsKey(manager, request)
+		if err != nil {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		original := response.ResponseWriter
+		buffered := &bufferedResponseWriter{target: original, header: make(http.Header)}
+		response.ResponseWriter = buffered
+		chain.ProcessFilter(request, response)
+		response.ResponseWriter = original
+		if buffered.unbuffered {
+			return
+		}
+
+		if buffered.status == 0 {
+			buffered.status = http.StatusOK
+		}
+
+		key := credentials + " " + request.Request.URL.RequestURI()
+		if buffered.status == http.StatusOK {
+			if buffered.body.Len() <= staleCacheMaxEntrySize && isJSONObject(buffered.body.Bytes()) {
+				cache.add(key, append([]byte(nil), buffered.body.Bytes()...))
+			}
+			buffered.flush()
+			return
+		}
+
+		entry, ok := cache.get(key)
+		if buffered.status < http.StatusInternalServerError || !ok {
+			buffered.flush()
+			return
+		}
+
+		cause := strings.TrimSpace(buffered.body.String())
+		body, err := markStale(entry.body, StaleMarker{AsOf: entry.fetched, Error: cause})
+		if err != nil {
+			buffered.flush()
+			return
+		}
+
+		log.Printf("Serving data of %s as of %s: %s", request.Request.URL.RequestURI(),
+			entry.fetched.Format(time.RFC3339), cause)
+		original.Header().Set("Content-Type", restful.MIME_JSON)
+		original.Header().Set("Warning", `110 - "Response is Stale"`)
+		original.WriteHeader(http.StatusOK)
+		original.Write(body)
+	}
+}
+
+// getCredentialsKey returns a hash of all credentials sent with given request.
+func getCredentialsKey(manager clientapi.ClientManager, request *restful.Request) (string, error) {
+	cmdConfig, err := manager.ClientCmdConfig(request)
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := cmdConfig.ClientConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return clientapi.GetCredentialsKey(cfg), nil
+}
+
+// markStale adds given marker as 'stale' field to the JSON object.
+func markStale(body []byte, marker StaleMarker) ([]byte, error) {
+	return addJSONField(body, "stale", marker)
+}
+
+// addJSONField adds given value as a field with given name to the JSON object.
+func addJSONField(body []byte, name string, value interface{}) ([]byte, error) {
+	envelope := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope[name] = raw
+	return json.Marshal(envelope)
+}
+
+func isJSONObject(body []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
+}
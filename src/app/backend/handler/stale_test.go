@@ -0,0 +1,90 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStaleCache(t *testing.T) {
+	cache := newStaleCache(time.Minute)
+	for i := 0; i <= staleCacheSize; i++ {
+		cache.add(string(rune('a'+i)), []byte("{}"))
+	}
+
+	if _, ok := cache.get("a"); ok {
+		t.Error("get(a): expected least recently used entry to be evicted")
+	}
+
+	if _, ok := cache.get("b"); !ok {
+		t.Error("get(b): expected entry to be cached")
+	}
+
+	cache.entries["b"].Value.(*staleCacheEntry).fetched = time.Now().Add(-time.Hour)
+	if _, ok := cache.get("b"); ok {
+		t.Error("get(b): expected expired entry to be dropped")
+	}
+}
+
+func TestMarkStale(t *testing.T) {
+	asOf := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	body, err := markStale([]byte(`{"listMeta":{"totalItems":1}}`), StaleMarker{AsOf: asOf, Error: "timeout"})
+	if err != nil {
+		t.Fatalf("markStale(): unexpected error: %v", err)
+	}
+
+	result := struct {
+		ListMeta map[string]int `json:"listMeta"`
+		Stale    *StaleMarker   `json:"stale"`
+	}{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("markStale(): returned invalid JSON: %v", err)
+	}
+
+	if result.ListMeta["totalItems"] != 1 || result.Stale == nil || !result.Stale.AsOf.Equal(asOf) ||
+		result.Stale.Error != "timeout" {
+		t.Errorf("markStale(): unexpected result %s", body)
+	}
+
+	if _, err := markStale([]byte(`[]`), StaleMarker{}); err == nil {
+		t.Error("markStale([]): expected error for response that is not an object")
+	}
+}
+
+func TestBufferedResponseWriterUnbuffer(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	buffered := &bufferedResponseWriter{target: recorder, header: make(http.Header)}
+	buffered.Header().Set("Content-Type", "application/json")
+	buffered.WriteHeader(http.StatusOK)
+	buffered.Write([]byte(`{"items":[`))
+	if recorder.Body.Len() > 0 {
+		t.Fatal("expected response to be buffered")
+	}
+
+	buffered.Unbuffer()
+	buffered.Write([]byte(`]}`))
+	if recorder.Body.String() != `{"items":[]}` || recorder.Code != http.StatusOK ||
+		recorder.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected unbuffered response %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	if buffered.body.Len() > 0 {
+		t.Error("expected unbuffered response not to be kept in memory")
+	}
+}
@@ -15,6 +15,7 @@
 package handler
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -23,18 +24,28 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	restful "github.com/emicklei/go-restful"
 	"gopkg.in/igm/sockjs-go.v2/sockjs"
 	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 )
 
 const END_OF_TRANSMISSION = "\u0004"
 
+// Time given to the client to open the SockJS connection for a newly created terminal session. Sessions
+// that are not bound in time are removed.
+const terminalBindTimeout = time.Minute
+
 // PtyHandler is what remotecommand expects from a pty
 type PtyHandler interface {
 	io.Reader
@@ -49,6 +60,20 @@ type TerminalSession struct {
 	sockJSSession sockjs.Session
 	sizeChan      chan remotecommand.TerminalSize
 	doneChan      chan struct{}
+	// Unix time in nanoseconds of the last message received from the user. Shared between copies
+	// of the session.
+	lastActivity *int64
+}
+
+// newTerminalSession creates a terminal session that waits for the client to bind to it.
+func newTerminalSession(id string) TerminalSession {
+	lastActivity := time.Now().UnixNano()
+	return TerminalSession{
+		id:           id,
+		bound:        make(chan error, 1),
+		sizeChan:     make(chan remotecommand.TerminalSize),
+		lastActivity: &lastActivity,
+	}
 }
 
 // TerminalMessage is the messaging protocol between ShellController and TerminalSession.
@@ -58,8 +83,10 @@ type TerminalSession struct {
 // bind    fe->be     SessionID      Id sent back from TerminalResponse
 // stdin   fe->be     Data           Keystrokes/paste buffer
 // resize  fe->be     Rows, Cols     New terminal size
+// ping    fe->be                    Heartbeat, answered with pong
 // stdout  be->fe     Data           Output from the process
 // toast   be->fe     Data           OOB message to be shown to the user
+// pong    be->fe                    Answer to ping
 type TerminalMessage struct {
 	Op, Data, SessionID string
 	Rows, Cols          uint16
@@ -92,10 +119,15 @@ func (t TerminalSession) Read(p []byte) (int, error) {
 
 	switch msg.Op {
 	case "stdin":
+		t.touch()
 		return copy(p, msg.Data), nil
 	case "resize":
+		t.touch()
 		t.sizeChan <- remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}
 		return 0, nil
+	case "ping":
+		// Heartbeats keep the connection alive, but do not count as user activity.
+		return 0, t.send(TerminalMessage{Op: "pong"})
 	default:
 		return copy(p, END_OF_TRANSMISSION), fmt.Errorf("unknown message type '%s'", msg.Op)
 	}
@@ -104,15 +136,7 @@ func (t TerminalSession) Read(p []byte) (int, error) {
 // Write handles process->pty stdout
 // Called from remotecommand whenever there is any output
 func (t TerminalSession) Write(p []byte) (int, error) {
-	msg, err := json.Marshal(TerminalMessage{
-		Op:   "stdout",
-		Data: string(p),
-	})
-	if err != nil {
-		return 0, err
-	}
-
-	if err = t.sockJSSession.Send(string(msg)); err != nil {
+	if err := t.send(TerminalMessage{Op: "stdout", Data: string(p)}); err != nil {
 		return 0, err
 	}
 	return len(p), nil
@@ -121,18 +145,26 @@ func (t TerminalSession) Write(p []byte) (int, error) {
 // Toast can be used to send the user any OOB messages
 // hterm puts these in the center of the terminal
 func (t TerminalSession) Toast(p string) error {
-	msg, err := json.Marshal(TerminalMessage{
-		Op:   "toast",
-		Data: p,
-	})
+	return t.send(TerminalMessage{Op: "toast", Data: p})
+}
+
+func (t TerminalSession) send(message TerminalMessage) error {
+	msg, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
-	if err = t.sockJSSession.Send(string(msg)); err != nil {
-		return err
-	}
-	return nil
+	return t.sockJSSession.Send(string(msg))
+}
+
+// touch records user activity, so that the session is not reaped as idle.
+func (t TerminalSession) touch() {
+	atomic.StoreInt64(t.lastActivity, time.Now().UnixNano())
+}
+
+// idleSince returns the time of the last user activity.
+func (t TerminalSession) idleSince() time.Time {
+	return time.Unix(0, atomic.LoadInt64(t.lastActivity))
 }
 
 // SessionMap stores a map of all TerminalSession objects and a lock to avoid concurrent conflict
@@ -155,18 +187,47 @@ func (sm *SessionMap) Set(sessionId string, session TerminalSession) {
 	sm.Sessions[sessionId] = session
 }
 
+// Count returns number of open terminal sessions.
+func (sm *SessionMap) Count() int {
+	sm.Lock.RLock()
+	defer sm.Lock.RUnlock()
+	return len(sm.Sessions)
+}
+
 // Close shuts down the SockJS connection and sends the status code and reason to the client
 // Can happen if the process exits or if there is an error starting up the process
 // For now the status code is unused and reason is shown to the user (unless "")
-func (sm *SessionMap) Close(sessionId string, status uint32, reason string) {
+// Returns false if the session has already been closed.
+func (sm *SessionMap) Close(sessionId string, status uint32, reason string) bool {
 	sm.Lock.Lock()
 	defer sm.Lock.Unlock()
-	err := sm.Sessions[sessionId].sockJSSession.Close(status, reason)
-	if err != nil {
-		log.Println(err)
+	session, ok := sm.Sessions[sessionId]
+	if !ok {
+		// Session has already been closed, i.e. reaped as idle.
+		return false
+	}
+
+	if session.sockJSSession != nil {
+		if err := session.sockJSSession.Close(status, reason); err != nil {
+			log.Println(err)
+		}
 	}
 
 	delete(sm.Sessions, sessionId)
+	return true
+}
+
+// Idle returns ids of bound sessions without any user activity since given time.
+func (sm *SessionMap) Idle(since time.Time) []string {
+	sm.Lock.RLock()
+	defer sm.Lock.RUnlock()
+	ids := make([]string, 0)
+	for id, session := range sm.Sessions {
+		if session.sockJSSession != nil && session.idleSince().Before(since) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
 var terminalSessions = SessionMap{Sessions: make(map[string]TerminalSession)}
@@ -201,13 +262,44 @@ func handleTerminalSession(session sockjs.Session) {
 	}
 
 	terminalSession.sockJSSession = session
+	terminalSession.touch()
 	terminalSessions.Set(msg.SessionID, terminalSession)
 	terminalSession.bound <- nil
 }
 
-// CreateAttachHandler is called from main for /api/sockjs
+// reapIdleTerminalSessions periodically closes terminal sessions that did not receive any input from
+// the user for longer than given timeout. Dead connections are detected by SockJS heartbeats, this
+// takes care of sessions that are alive, but abandoned.
+func reapIdleTerminalSessions(timeout time.Duration) {
+	period := timeout / 4
+	if period < time.Second {
+		period = time.Second
+	}
+
+	for range time.Tick(period) {
+		for _, id := range terminalSessions.Idle(time.Now().Add(-timeout)) {
+			log.Printf("Closing terminal session %s after %s of inactivity", id, timeout)
+			if terminalSessions.Close(id, 2, fmt.Sprintf("Session closed after %s of inactivity", timeout)) {
+				terminalSessionsClosed.WithLabelValues("idle").Inc()
+			}
+		}
+	}
+}
+
+// CreateAttachHandler is called from main for /api/sockjs. Heartbeats are sent every
+// 'terminal-heartbeat-interval' seconds and sessions are reaped after 'terminal-idle-timeout' seconds
+// without user input.
 func CreateAttachHandler(path string) http.Handler {
-	return sockjs.NewHandler(path, sockjs.DefaultOptions, handleTerminalSession)
+	options := sockjs.DefaultOptions
+	if interval := args.Holder.GetTerminalHeartbeatInterval(); interval > 0 {
+		options.HeartbeatDelay = time.Duration(interval) * time.Second
+	}
+
+	if timeout := args.Holder.GetTerminalIdleTimeout(); timeout > 0 {
+		go reapIdleTerminalSessions(time.Duration(timeout) * time.Second)
+	}
+
+	return sockjs.NewHandler(path, options, handleTerminalSession)
 }
 
 // startProcess is called by handleAttach
@@ -275,12 +367,42 @@ func isValidShell(validShells []string, shell string) bool {
 	return false
 }
 
+// getShells returns shells to try in containers of given operating system. Shells of the other operating system
+// are not tried, so that the first keyboard event is not lost on a failed attempt. All shells are tried if the
+// operating system is unknown.
+func getShells(os string) []string {
+	switch os {
+	case common.OSWindows:
+		return []string{"powershell", "cmd"}
+	case common.OSLinux:
+		return []string{"bash", "sh"}
+	default:
+		return []string{"bash", "sh", "powershell", "cmd"}
+	}
+}
+
+// getContainerOS returns operating system of the pod requested by the terminal, or an empty string if it is unknown.
+func getContainerOS(k8sClient kubernetes.Interface, request *restful.Request) string {
+	pod, err := k8sClient.CoreV1().Pods(request.PathParameter("namespace")).Get(context.TODO(),
+		request.PathParameter("pod"), metaV1.GetOptions{})
+	if err != nil {
+		log.Printf("Couldn't get operating system of %s pod: %s", request.PathParameter("pod"), err)
+		return ""
+	}
+	return common.GetPodOS(k8sClient, pod)
+}
+
 // WaitForTerminal is called from apihandler.handleAttach as a goroutine
 // Waits for the SockJS connection to be opened by the client the session to be bound in handleTerminalSession
 func WaitForTerminal(k8sClient kubernetes.Interface, cfg *rest.Config, request *restful.Request, sessionId string) {
 	shell := request.QueryParameter("shell")
 
 	select {
+	case <-time.After(terminalBindTimeout):
+		log.Printf("Terminal session %s was not bound in %s, removing it", sessionId, terminalBindTimeout)
+		if terminalSessions.Close(sessionId, 2, "Session was not bound in time") {
+			terminalSessionsClosed.WithLabelValues("unbound").Inc()
+		}
 	case <-terminalSessions.Get(sessionId).bound:
 		close(terminalSessions.Get(sessionId).bound)
 
@@ -293,7 +415,7 @@ func WaitForTerminal(k8sClient kubernetes.Interface, cfg *rest.Config, request *
 		} else {
 			// No shell given or it was not valid: try some shells until one succeeds or all fail
 			// FIXME: if the first shell fails then the first keyboard event is lost
-			for _, testShell := range validShells {
+			for _, testShell := range getShells(getContainerOS(k8sClient, request)) {
 				cmd := []string{testShell}
 				if err = startProcess(k8sClient, cfg, request, cmd, terminalSessions.Get(sessionId)); err == nil {
 					break
@@ -302,10 +424,14 @@ func WaitForTerminal(k8sClient kubernetes.Interface, cfg *rest.Config, request *
 		}
 
 		if err != nil {
-			terminalSessions.Close(sessionId, 2, err.Error())
+			if terminalSessions.Close(sessionId, 2, err.Error()) {
+				terminalSessionsClosed.WithLabelValues("error").Inc()
+			}
 			return
 		}
 
-		terminalSessions.Close(sessionId, 1, "Process exited")
+		if terminalSessions.Close(sessionId, 1, "Process exited") {
+			terminalSessionsClosed.WithLabelValues("exited").Inc()
+		}
 	}
 }
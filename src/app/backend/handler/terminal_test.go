@@ -0,0 +1,93 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/igm/sockjs-go.v2/sockjs"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+type fakeSockJSSession struct {
+	sockjs.Session
+	closed bool
+}
+
+func (self *fakeSockJSSession) Close(status uint32, reason string) error {
+	self.closed = true
+	return nil
+}
+
+func TestSessionMapIdle(t *testing.T) {
+	sessions := SessionMap{Sessions: make(map[string]TerminalSession)}
+	sockJSSession := &fakeSockJSSession{}
+
+	idle := newTerminalSession("idle")
+	idle.sockJSSession = sockJSSession
+	atomic.StoreInt64(idle.lastActivity, time.Now().Add(-time.Hour).UnixNano())
+	sessions.Set("idle", idle)
+
+	active := newTerminalSession("active")
+	active.sockJSSession = &fakeSockJSSession{}
+	sessions.Set("active", active)
+
+	// Sessions that are not bound yet are removed by WaitForTerminal.
+	unbound := newTerminalSession("unbound")
+	atomic.StoreInt64(unbound.lastActivity, time.Now().Add(-time.Hour).UnixNano())
+	sessions.Set("unbound", unbound)
+
+	ids := sessions.Idle(time.Now().Add(-time.Minute))
+	if len(ids) != 1 || ids[0] != "idle" {
+		t.Fatalf("Idle(): expected [idle], got %v", ids)
+	}
+
+	if !sessions.Close("idle", 2, "idle") || !sockJSSession.closed {
+		t.Error("Close(idle): expected session to be closed")
+	}
+
+	if sessions.Close("idle", 1, "Process exited") {
+		t.Error("Close(idle): expected already closed session to be skipped")
+	}
+
+	if !sessions.Close("unbound", 2, "Session was not bound in time") {
+		t.Error("Close(unbound): expected unbound session to be removed")
+	}
+
+	if sessions.Count() != 1 {
+		t.Errorf("Count(): expected 1 session, got %d", sessions.Count())
+	}
+}
+
+func TestGetShells(t *testing.T) {
+	cases := []struct {
+		os       string
+		expected []string
+	}{
+		{common.OSWindows, []string{"powershell", "cmd"}},
+		{common.OSLinux, []string{"bash", "sh"}},
+		{"", []string{"bash", "sh", "powershell", "cmd"}},
+	}
+
+	for _, c := range cases {
+		if actual := getShells(c.os); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getShells(%q) == %v, expected %v", c.os, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	"github.com/kubernetes/dashboard/src/app/backend/stream"
+)
+
+// streamingRoutes tells whether a request matched a route registered with stream.RouteMetadata. Routes
+// are indexed on first use, because filters are installed before routes are added to the web service.
+type streamingRoutes struct {
+	ws    *restful.WebService
+	once  sync.Once
+	index map[string]bool
+}
+
+// contains returns true if given request is served by a long-lived streaming route.
+func (self *streamingRoutes) contains(request *restful.Request) bool {
+	self.once.Do(func() {
+		self.index = make(map[string]bool)
+		for _, route := range self.ws.Routes() {
+			if stream.IsStreamingRoute(route) {
+				self.index[route.Method+" "+route.Path] = true
+			}
+		}
+	})
+
+	return self.index[request.Request.Method+" "+request.SelectedRoutePath()]
+}
+
+func newStreamingRoutes(ws *restful.WebService) *streamingRoutes {
+	return &streamingRoutes{ws: ws}
+}
+
+// requestTimeouts holds the timeout of API requests. The default timeout is set by 'request-timeout' and
+// can be overridden for given path prefixes by 'route-timeouts', in which case the longest matching
+// prefix wins.
+type requestTimeouts struct {
+	defaultTimeout time.Duration
+	routeTimeouts  map[string]time.Duration
+}
+
+func (self *requestTimeouts) timeout(path string) time.Duration {
+	timeout, matched := self.defaultTimeout, ""
+	for prefix, routeTimeout := range self.routeTimeouts {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(matched) {
+			timeout, matched = routeTimeout, prefix
+		}
+	}
+
+	return timeout
+}
+
+func newRequestTimeouts() *requestTimeouts {
+	routeTimeouts := make(map[string]time.Duration)
+	for prefix, seconds := range args.Holder.GetRouteTimeouts() {
+		routeTimeouts[prefix] = time.Duration(seconds) * time.Second
+	}
+
+	return &requestTimeouts{
+		defaultTimeout: time.Duration(args.Holder.GetRequestTimeout()) * time.Second,
+		routeTimeouts:  routeTimeouts,
+	}
+}
+
+// requestTimeoutFilter sets a deadline on the context of API requests. Clients created for the request
+// cancel their apiserver calls once the deadline passes, so the handler fails instead of running on.
+// Streaming routes have no deadline.
+func requestTimeoutFilter(streaming *streamingRoutes) restful.FilterFunction {
+	return requestTimeoutFilterWith(newRequestTimeouts(), streaming)
+}
+
+func requestTimeoutFilterWith(timeouts *requestTimeouts, streaming *streamingRoutes) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		timeout := timeouts.timeout(request.Request.URL.Path)
+		if timeout <= 0 || streaming.contains(request) {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(request.Request.Context(), timeout)
+		defer cancel()
+
+		request.Request = request.Request.WithContext(ctx)
+		chain.ProcessFilter(request, response)
+	}
+}
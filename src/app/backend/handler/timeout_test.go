@@ -0,0 +1,83 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/stream"
+)
+
+func TestRequestTimeouts(t *testing.T) {
+	timeouts := &requestTimeouts{
+		defaultTimeout: 10 * time.Millisecond,
+		routeTimeouts: map[string]time.Duration{
+			"/api/v1/node":     time.Second,
+			"/api/v1/node/big": 0,
+			"/api/v1/pod":      5 * time.Millisecond,
+		},
+	}
+
+	cases := []struct {
+		path     string
+		expected time.Duration
+	}{
+		{"/api/v1/deployment", 10 * time.Millisecond},
+		{"/api/v1/pod/default", 5 * time.Millisecond},
+		{"/api/v1/node", time.Second},
+		{"/api/v1/node/big", 0},
+	}
+
+	for _, c := range cases {
+		if actual := timeouts.timeout(c.path); actual != c.expected {
+			t.Errorf("timeout(%s): expected %s, got %s", c.path, c.expected, actual)
+		}
+	}
+}
+
+func TestRequestTimeoutFilter(t *testing.T) {
+	ws := new(restful.WebService)
+	streaming := newStreamingRoutes(ws)
+	ws.Path("/api/v1")
+
+	deadlines := make(map[string]bool)
+	recordDeadline := func(request *restful.Request, response *restful.Response) {
+		_, ok := request.Request.Context().Deadline()
+		deadlines[request.SelectedRoutePath()] = ok
+	}
+	ws.Route(ws.GET("/pod/{namespace}").To(recordDeadline))
+	ws.Route(ws.GET("/log/file/{namespace}").To(recordDeadline).Metadata(stream.RouteMetadata, true))
+
+	ws.Filter(requestTimeoutFilterWith(&requestTimeouts{defaultTimeout: time.Minute}, streaming))
+
+	container := restful.NewContainer()
+	container.Add(ws)
+	for _, path := range []string{"/api/v1/pod/default", "/api/v1/log/file/default"} {
+		container.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if !deadlines["/api/v1/pod/{namespace}"] {
+		t.Error("expected request to have a deadline")
+	}
+
+	if deadlines["/api/v1/log/file/{namespace}"] {
+		t.Error("expected streaming request not to have a deadline")
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package falco
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	integrationapi "github.com/kubernetes/dashboard/src/app/backend/integration/api"
+)
+
+// DefaultCapacity is the number of the latest events kept in memory.
+const DefaultCapacity = 1000
+
+// Priorities contains Falco event priorities ordered from the most severe one.
+var Priorities = []string{"Emergency", "Alert", "Critical", "Error", "Warning", "Notice", "Informational", "Debug"}
+
+// Output fields, that identify the Kubernetes object affected by an event.
+const (
+	fieldNamespace = "k8s.ns.name"
+	fieldPod       = "k8s.pod.name"
+	fieldContainer = "container.name"
+)
+
+// Event is a runtime security finding reported by Falco.
+type Event struct {
+	Time      metaV1.Time `json:"time"`
+	Rule      string      `json:"rule"`
+	Priority  string      `json:"priority"`
+	Output    string      `json:"output"`
+	Source    string      `json:"source,omitempty"`
+	Hostname  string      `json:"hostname,omitempty"`
+	Tags      []string    `json:"tags,omitempty"`
+	Namespace string      `json:"namespace,omitempty"`
+	Pod       string      `json:"pod,omitempty"`
+	Container string      `json:"container,omitempty"`
+}
+
+// EventList contains events matching a query, the newest first, and the number of them by priority.
+type EventList struct {
+	ListMeta       api.ListMeta   `json:"listMeta"`
+	Events         []Event        `json:"events"`
+	PriorityCounts map[string]int `json:"priorityCounts"`
+}
+
+// payload is the JSON format of events sent by the http output of Falco and the webhook output of
+// falcosidekick.
+type payload struct {
+	Time         time.Time              `json:"time"`
+	Rule         string                 `json:"rule"`
+	Priority     string                 `json:"priority"`
+	Output       string                 `json:"output"`
+	Source       string                 `json:"source"`
+	Hostname     string                 `json:"hostname"`
+	Tags         []string               `json:"tags"`
+	OutputFields map[string]interface{} `json:"output_fields"`
+}
+
+// ParseEvent parses a single event sent by Falco or falcosidekick.
+func ParseEvent(data []byte) (*Event, error) {
+	p := new(payload)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	if len(p.Rule) == 0 {
+		return nil, errors.New("event does not contain a rule")
+	}
+
+	field := func(name string) string {
+		value, _ := p.OutputFields[name].(string)
+		return value
+	}
+
+	return &Event{
+		Time:      metaV1.NewTime(p.Time),
+		Rule:      p.Rule,
+		Priority:  normalizePriority(p.Priority),
+		Output:    p.Output,
+		Source:    p.Source,
+		Hostname:  p.Hostname,
+		Tags:      p.Tags,
+		Namespace: field(fieldNamespace),
+		Pod:       field(fieldPod),
+		Container: field(fieldContainer),
+	}, nil
+}
+
+// normalizePriority returns priority in the form listed in Priorities. Unknown priorities are kept as they are.
+func normalizePriority(priority string) string {
+	for _, p := range Priorities {
+		if strings.EqualFold(p, priority) {
+			return p
+		}
+	}
+	return priority
+}
+
+// priorityRank returns position of priority in Priorities. Unknown priorities rank as the least severe.
+func priorityRank(priority string) int {
+	for i, p := range Priorities {
+		if strings.EqualFold(p, priority) {
+			return i
+		}
+	}
+	return len(Priorities)
+}
+
+// EventStore keeps the latest events received from Falco. Events are kept in memory of a single dashboard
+// replica, so Falco has to send events to all of them, i.e. through a headless service.
+type EventStore struct {
+	token    string
+	capacity int
+	events   []Event
+	next     int
+	mux      sync.RWMutex
+}
+
+// Configure enables the integration. Events are accepted only from senders presenting given token.
+func (self *EventStore) Configure(token string) *EventStore {
+	self.token = token
+	return self
+}
+
+// Token returns token expected from senders of the events.
+func (self *EventStore) Token() string {
+	return self.token
+}
+
+// Enabled returns true if the integration has been configured.
+func (self *EventStore) Enabled() bool {
+	return len(self.token) > 0
+}
+
+// ID implements integration app interface. See Integration interface for more information.
+func (self *EventStore) ID() integrationapi.IntegrationID {
+	return integrationapi.FalcoIntegrationID
+}
+
+// HealthCheck implements integration app interface. Falco pushes events to the dashboard, so the integration
+// is healthy as long as it is configured.
+func (self *EventStore) HealthCheck() error {
+	if !self.Enabled() {
+		return errors.New("falco integration is not configured")
+	}
+	return nil
+}
+
+// Add stores an event, dropping the oldest one if the store is full.
+func (self *EventStore) Add(event Event) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if len(self.events) < self.capacity {
+		self.events = append(self.events, event)
+		return
+	}
+
+	self.events[self.next] = event
+	self.next = (self.next + 1) % self.capacity
+}
+
+// List returns stored events of given namespace and pod with at least given priority. Empty values match
+// all events.
+func (self *EventStore) List(namespace, pod, minPriority string) *EventList {
+	self.mux.RLock()
+	defer self.mux.RUnlock()
+
+	maxRank := len(Priorities)
+	if len(minPriority) > 0 {
+		maxRank = priorityRank(minPriority)
+	}
+
+	result := &EventList{Events: []Event{}, PriorityCounts: map[string]int{}}
+	for i := len(self.events) - 1; i >= 0; i-- {
+		event := self.events[(self.next+i)%len(self.events)]
+		if (len(namespace) > 0 && event.Namespace != namespace) || (len(pod) > 0 && event.Pod != pod) ||
+			priorityRank(event.Priority) > maxRank {
+			continue
+		}
+
+		result.Events = append(result.Events, event)
+		result.PriorityCounts[event.Priority]++
+	}
+
+	result.ListMeta.TotalItems = len(result.Events)
+	return result
+}
+
+// NewEventStore creates event store keeping given number of the latest events.
+func NewEventStore(capacity int) *EventStore {
+	return &EventStore{capacity: capacity, events: make([]Event, 0)}
+}
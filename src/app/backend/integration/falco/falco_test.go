@@ -0,0 +1,86 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package falco
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseEvent(t *testing.T) {
+	data := `{"output":"Shell spawned in a container","priority":"warning","rule":"Terminal shell in container",
+		"time":"2020-07-01T10:00:00Z","source":"syscall","tags":["container","shell"],
+		"output_fields":{"k8s.ns.name":"default","k8s.pod.name":"web-0","container.name":"nginx","proc.pid":42}}`
+
+	expected := &Event{
+		Time:      metaV1.NewTime(time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC)),
+		Rule:      "Terminal shell in container",
+		Priority:  "Warning",
+		Output:    "Shell spawned in a container",
+		Source:    "syscall",
+		Tags:      []string{"container", "shell"},
+		Namespace: "default",
+		Pod:       "web-0",
+		Container: "nginx",
+	}
+
+	actual, err := ParseEvent([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseEvent() returned error: %s", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ParseEvent() == %#v, expected %#v", actual, expected)
+	}
+
+	if _, err := ParseEvent([]byte(`{"output":"no rule"}`)); err == nil {
+		t.Error("expected event without rule to be rejected")
+	}
+}
+
+func TestEventStoreList(t *testing.T) {
+	store := NewEventStore(3)
+	for _, e := range []Event{
+		{Rule: "a", Priority: "Critical", Namespace: "default", Pod: "web-0"},
+		{Rule: "b", Priority: "Notice", Namespace: "default", Pod: "web-0"},
+		{Rule: "c", Priority: "Warning", Namespace: "kube-system", Pod: "dns"},
+		{Rule: "d", Priority: "Error", Namespace: "default", Pod: "web-1"},
+	} {
+		store.Add(e)
+	}
+
+	cases := []struct {
+		namespace, pod, priority string
+		expected                 []string
+	}{
+		{"", "", "", []string{"d", "c", "b"}},
+		{"default", "", "", []string{"d", "b"}},
+		{"default", "web-0", "", []string{"b"}},
+		{"", "", "warning", []string{"d", "c"}},
+	}
+
+	for _, c := range cases {
+		list := store.List(c.namespace, c.pod, c.priority)
+		rules := make([]string, 0)
+		for _, e := range list.Events {
+			rules = append(rules, e.Rule)
+		}
+		if !reflect.DeepEqual(rules, c.expected) || list.ListMeta.TotalItems != len(c.expected) {
+			t.Errorf("List(%q, %q, %q) == %v, expected %v", c.namespace, c.pod, c.priority, rules, c.expected)
+		}
+	}
+}
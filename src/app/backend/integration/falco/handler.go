@@ -0,0 +1,69 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package falco
+
+import (
+	"crypto/subtle"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// maxEventSize limits the size of a single received event.
+const maxEventSize = 1024 * 1024
+
+// FalcoWebhookHandler receives events pushed by Falco or falcosidekick.
+type FalcoWebhookHandler struct {
+	store *EventStore
+}
+
+// Install creates new endpoint receiving Falco events.
+func (self *FalcoWebhookHandler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.POST("/falco").
+			To(self.handleEvent))
+}
+
+func (self *FalcoWebhookHandler) handleEvent(request *restful.Request, response *restful.Response) {
+	token := strings.TrimPrefix(request.HeaderParameter("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(self.store.Token())) != 1 {
+		errors.HandleInternalError(response, errors.NewUnauthorized("invalid falco webhook token"))
+		return
+	}
+
+	data, err := ioutil.ReadAll(http.MaxBytesReader(response, request.Request.Body, maxEventSize))
+	if err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	event, err := ParseEvent(data)
+	if err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	self.store.Add(*event)
+	response.WriteHeader(http.StatusAccepted)
+}
+
+// NewFalcoWebhookHandler creates FalcoWebhookHandler storing received events in given store.
+func NewFalcoWebhookHandler(store *EventStore) *FalcoWebhookHandler {
+	return &FalcoWebhookHandler{store: store}
+}
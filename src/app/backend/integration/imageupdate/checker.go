@@ -0,0 +1,394 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageupdate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	dashboardErrors "github.com/kubernetes/dashboard/src/app/backend/errors"
+	integrationapi "github.com/kubernetes/dashboard/src/app/backend/integration/api"
+)
+
+const (
+	// Timeout of a single request to a registry.
+	requestTimeout = 10 * time.Second
+	// Maximum number of registry lookups done at the same time.
+	maxConcurrentLookups = 4
+)
+
+// ContainerUpdate is a newer tag available for the image of a container.
+type ContainerUpdate struct {
+	Container  string `json:"container"`
+	Image      string `json:"image"`
+	CurrentTag string `json:"currentTag"`
+	LatestTag  string `json:"latestTag"`
+	// LatestImage is the image the container is bumped to.
+	LatestImage string `json:"latestImage"`
+}
+
+// WorkloadUpdates are available updates of images used by a workload.
+type WorkloadUpdates struct {
+	Kind       string            `json:"kind"`
+	Namespace  string            `json:"namespace"`
+	Name       string            `json:"name"`
+	Containers []ContainerUpdate `json:"containers"`
+}
+
+// WorkloadUpdatesList contains workloads with available updates of their images.
+type WorkloadUpdatesList struct {
+	ListMeta  api.ListMeta      `json:"listMeta"`
+	Workloads []WorkloadUpdates `json:"workloads"`
+	// List of non-critical errors, that occurred while reading tags from registries.
+	Errors []error `json:"errors"`
+}
+
+// BumpSpec is a request to change tag of the image of a workload container.
+type BumpSpec struct {
+	Container string `json:"container"`
+	Tag       string `json:"tag"`
+}
+
+// workload is a controller, which image can be bumped.
+type workload struct {
+	kind      string
+	namespace string
+	name      string
+	template  *v1.PodTemplateSpec
+	patch     func(data []byte) error
+}
+
+// Checker finds newer semver tags of images used by workloads. Only allowed registries are asked for tags, so
+// workloads can not make Dashboard send requests to arbitrary hosts.
+type Checker struct {
+	registries map[string]bool
+	cacheTTL   time.Duration
+	cache      map[string]tagCacheEntry
+	client     *http.Client
+	scheme     string
+	mux        sync.Mutex
+}
+
+// Configure enables the integration for given registries. Tags read from registries are cached for given time.
+func (self *Checker) Configure(registries []string, cacheTTL time.Duration) *Checker {
+	self.registries = make(map[string]bool)
+	for _, registry := range registries {
+		if registry = strings.TrimSpace(registry); len(registry) > 0 {
+			self.registries[registry] = true
+		}
+	}
+
+	self.cacheTTL = cacheTTL
+	return self
+}
+
+// Enabled returns true if any registry is allowed.
+func (self *Checker) Enabled() bool {
+	return len(self.registries) > 0
+}
+
+// ID implements integration app interface. See Integration interface for more information.
+func (self *Checker) ID() integrationapi.IntegrationID {
+	return integrationapi.ImageUpdateIntegrationID
+}
+
+// HealthCheck implements integration app interface. Registries are asked only when workloads are checked, so
+// the integration is healthy as long as it is configured.
+func (self *Checker) HealthCheck() error {
+	if !self.Enabled() {
+		return errors.New("image update integration is not configured")
+	}
+	return nil
+}
+
+// List returns workloads in given namespace, that use images with newer tags in allowed registries. Registries
+// that can not be read are reported as non-critical errors.
+func (self *Checker) List(client kubernetes.Interface, namespace string) (*WorkloadUpdatesList, error) {
+	workloads, err := listWorkloads(client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	references := make(map[string]reference)
+	for _, w := range workloads {
+		for _, container := range w.template.Spec.Containers {
+			if ref := parseReference(container.Image); self.isCheckable(ref) {
+				references[ref.Registry+"/"+ref.Repository] = ref
+			}
+		}
+	}
+
+	tags, errs := self.lookup(references)
+	result := &WorkloadUpdatesList{Workloads: make([]WorkloadUpdates, 0), Errors: errs}
+	for _, w := range workloads {
+		updates := WorkloadUpdates{Kind: w.kind, Namespace: w.namespace, Name: w.name}
+		for _, container := range w.template.Spec.Containers {
+			ref := parseReference(container.Image)
+			if update := getUpdate(container, ref, tags[ref.Registry+"/"+ref.Repository]); update != nil {
+				updates.Containers = append(updates.Containers, *update)
+			}
+		}
+
+		if len(updates.Containers) > 0 {
+			result.Workloads = append(result.Workloads, updates)
+		}
+	}
+
+	result.ListMeta = api.ListMeta{TotalItems: len(result.Workloads)}
+	return result, nil
+}
+
+// Bump changes tag of the image of the workload container. Only tags newer than the current one, that exist in
+// the registry, are accepted.
+func (self *Checker) Bump(client kubernetes.Interface, kind, namespace, name string,
+	spec *BumpSpec) (*ContainerUpdate, error) {
+	w, err := getWorkload(client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var container *v1.Container
+	for i := range w.template.Spec.Containers {
+		if w.template.Spec.Containers[i].Name == spec.Container {
+			container = &w.template.Spec.Containers[i]
+		}
+	}
+
+	if container == nil {
+		return nil, dashboardErrors.NewNotFound(fmt.Sprintf("container %s not found", spec.Container))
+	}
+
+	ref := parseReference(container.Image)
+	if !self.isCheckable(ref) {
+		return nil, dashboardErrors.NewInvalid(fmt.Sprintf("updates of image %s are not checked", container.Image))
+	}
+
+	current, _ := parseVersion(ref.Tag)
+	candidate, ok := parseVersion(spec.Tag)
+	if !ok || candidate.format != current.format || !candidate.newerThan(current) {
+		return nil, dashboardErrors.NewBadRequest(fmt.Sprintf("tag %s is not a newer version of %s", spec.Tag,
+			ref.Tag))
+	}
+
+	tags, err := self.tags(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if !contains(tags, spec.Tag) {
+		return nil, dashboardErrors.NewBadRequest(fmt.Sprintf("tag %s does not exist in %s", spec.Tag, ref.Registry))
+	}
+
+	update := &ContainerUpdate{
+		Container:   container.Name,
+		Image:       container.Image,
+		CurrentTag:  ref.Tag,
+		LatestTag:   spec.Tag,
+		LatestImage: ref.Name + ":" + spec.Tag,
+	}
+
+	patch := map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{
+		"spec": map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": container.Name, "image": update.LatestImage},
+		}},
+	}}}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.patch(data); err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
+// Images are checked if they come from allowed registries and use semver-like tags. Images pinned by digest are
+// skipped, as bumping their tag would not change them.
+func (self *Checker) isCheckable(ref reference) bool {
+	if !self.registries[ref.Registry] || len(ref.Digest) > 0 {
+		return false
+	}
+
+	_, ok := parseVersion(ref.Tag)
+	return ok
+}
+
+// Reads tags of given repositories, a few at a time. Returns tags by repository and errors of failed lookups.
+func (self *Checker) lookup(references map[string]reference) (map[string][]string, []error) {
+	result := make(map[string][]string)
+	errs := make([]error, 0)
+	var mux sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentLookups)
+	for key, ref := range references {
+		wg.Add(1)
+		go func(key string, ref reference) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			tags, err := self.tags(ref)
+			mux.Lock()
+			defer mux.Unlock()
+			if err != nil {
+				errs = append(errs, dashboardErrors.NewInternal(err.Error()))
+				return
+			}
+			result[key] = tags
+		}(key, ref)
+	}
+
+	wg.Wait()
+	return result, errs
+}
+
+// Returns update of the container image or nil if there is no newer tag.
+func getUpdate(container v1.Container, ref reference, tags []string) *ContainerUpdate {
+	latest := latestTag(ref.Tag, tags)
+	if len(latest) == 0 || len(ref.Digest) > 0 {
+		return nil
+	}
+
+	return &ContainerUpdate{
+		Container:   container.Name,
+		Image:       container.Image,
+		CurrentTag:  ref.Tag,
+		LatestTag:   latest,
+		LatestImage: ref.Name + ":" + latest,
+	}
+}
+
+// Returns deployments, stateful sets and daemon sets in given namespace, sorted by namespace and name.
+func listWorkloads(client kubernetes.Interface, namespace string) ([]workload, error) {
+	apps := client.AppsV1()
+	result := make([]workload, 0)
+	deployments, err := apps.Deployments(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		result = append(result, workload{kind: "deployment", namespace: d.Namespace, name: d.Name,
+			template: &d.Spec.Template})
+	}
+
+	statefulSets, err := apps.StatefulSets(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		result = append(result, workload{kind: "statefulset", namespace: s.Namespace, name: s.Name,
+			template: &s.Spec.Template})
+	}
+
+	daemonSets, err := apps.DaemonSets(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range daemonSets.Items {
+		d := &daemonSets.Items[i]
+		result = append(result, workload{kind: "daemonset", namespace: d.Namespace, name: d.Name,
+			template: &d.Spec.Template})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].namespace != result[j].namespace {
+			return result[i].namespace < result[j].namespace
+		}
+		return result[i].name < result[j].name
+	})
+	return result, nil
+}
+
+// getWorkload returns workload of given kind. Only controllers, that roll out changes of their pod template,
+// are supported.
+func getWorkload(client kubernetes.Interface, kind, namespace, name string) (*workload, error) {
+	w := &workload{kind: strings.ToLower(kind), namespace: namespace, name: name}
+	apps := client.AppsV1()
+	switch w.kind {
+	case "deployment":
+		d, err := apps.Deployments(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		w.template = &d.Spec.Template
+		w.patch = func(data []byte) error {
+			_, err := apps.Deployments(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, data,
+				metaV1.PatchOptions{})
+			return err
+		}
+	case "statefulset":
+		s, err := apps.StatefulSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		w.template = &s.Spec.Template
+		w.patch = func(data []byte) error {
+			_, err := apps.StatefulSets(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, data,
+				metaV1.PatchOptions{})
+			return err
+		}
+	case "daemonset":
+		d, err := apps.DaemonSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		w.template = &d.Spec.Template
+		w.patch = func(data []byte) error {
+			_, err := apps.DaemonSets(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, data,
+				metaV1.PatchOptions{})
+			return err
+		}
+	default:
+		return nil, dashboardErrors.NewInvalid(fmt.Sprintf("bumping images is not supported for kind: %s", kind))
+	}
+
+	return w, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// NewChecker creates disabled image update checker. See Configure.
+func NewChecker() *Checker {
+	return &Checker{
+		registries: make(map[string]bool),
+		cache:      make(map[string]tagCacheEntry),
+		client:     &http.Client{Timeout: requestTimeout},
+		scheme:     "https",
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageupdate
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Docker Hub is the registry of images without registry host.
+const (
+	dockerHubRegistry = "docker.io"
+	dockerHubHost     = "registry-1.docker.io"
+)
+
+// reference is a parsed image reference, i.e. ghcr.io/org/app:1.2.3.
+type reference struct {
+	// Name is the image without tag and digest as it is written in workloads.
+	Name string
+	// Registry is the normalized registry name, i.e. docker.io for images without registry host.
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// host returns host of the registry API.
+func (self reference) host() string {
+	if self.Registry == dockerHubRegistry {
+		return dockerHubHost
+	}
+
+	return self.Registry
+}
+
+// Parses image reference. Images without tag have the implicit latest tag.
+func parseReference(image string) reference {
+	result := reference{Registry: dockerHubRegistry, Tag: "latest"}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		result.Digest = name[i+1:]
+		name = name[:i]
+	}
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		result.Tag = name[i+1:]
+		name = name[:i]
+	}
+	result.Name = name
+
+	// First component is a registry host if it looks like one, otherwise the image is from Docker Hub.
+	if i := strings.Index(name, "/"); i >= 0 {
+		if host := name[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			result.Registry = host
+			name = name[i+1:]
+		}
+	}
+
+	if result.Registry == "index.docker.io" {
+		result.Registry = dockerHubRegistry
+	}
+
+	if result.Registry == dockerHubRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+
+	result.Repository = name
+	return result
+}
+
+// Tags such as 1.2, v1.2.3 or 1.19.3-alpine. Suffix after the dash is kept as a part of the tag format.
+var versionPattern = regexp.MustCompile(`^(v?)([0-9]+(?:\.[0-9]+){0,3})(-[0-9A-Za-z.-]+)?$`)
+
+// version is a semver-like tag.
+type version struct {
+	// format is a prefix, the number of components and a suffix of the tag. Only tags of the same format are
+	// compared, so i.e. 1.19-alpine is updated to 1.20-alpine, not to 1.20 or 1.20.1-alpine.
+	format     string
+	components []int
+}
+
+// Parses tag. Returns false if the tag is not semver-like.
+func parseVersion(tag string) (*version, bool) {
+	match := versionPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return nil, false
+	}
+
+	parts := strings.Split(match[2], ".")
+	result := &version{format: match[1] + strconv.Itoa(len(parts)) + match[3]}
+	for _, part := range parts {
+		component, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		result.components = append(result.components, component)
+	}
+
+	return result, true
+}
+
+// newerThan returns true if the version is newer than given version of the same format.
+func (self *version) newerThan(other *version) bool {
+	for i := range self.components {
+		if self.components[i] != other.components[i] {
+			return self.components[i] > other.components[i]
+		}
+	}
+
+	return false
+}
+
+// Returns the newest of tags newer than the current one and of the same format or empty string if there is none.
+func latestTag(current string, tags []string) string {
+	currentVersion, ok := parseVersion(current)
+	if !ok {
+		return ""
+	}
+
+	latest, latestVersion := "", currentVersion
+	for _, tag := range tags {
+		if candidate, ok := parseVersion(tag); ok && candidate.format == currentVersion.format &&
+			candidate.newerThan(latestVersion) {
+			latest, latestVersion = tag, candidate
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,215 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageupdate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseReference(t *testing.T) {
+	cases := []struct {
+		image    string
+		expected reference
+	}{
+		{"nginx", reference{Name: "nginx", Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}},
+		{"nginx:1.19-alpine", reference{Name: "nginx", Registry: "docker.io", Repository: "library/nginx",
+			Tag: "1.19-alpine"}},
+		{"bitnami/redis:6.0.9", reference{Name: "bitnami/redis", Registry: "docker.io",
+			Repository: "bitnami/redis", Tag: "6.0.9"}},
+		{"ghcr.io/org/app:v1.2.3", reference{Name: "ghcr.io/org/app", Registry: "ghcr.io", Repository: "org/app",
+			Tag: "v1.2.3"}},
+		{"localhost:5000/app@sha256:abc", reference{Name: "localhost:5000/app", Registry: "localhost:5000",
+			Repository: "app", Tag: "latest", Digest: "sha256:abc"}},
+		{"index.docker.io/nginx:1.19", reference{Name: "index.docker.io/nginx", Registry: "docker.io",
+			Repository: "library/nginx", Tag: "1.19"}},
+	}
+
+	for _, c := range cases {
+		if ref := parseReference(c.image); !reflect.DeepEqual(ref, c.expected) {
+			t.Errorf("Expected %s to be parsed as %+v, but got %+v.", c.image, c.expected, ref)
+		}
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	tags := []string{"latest", "1.9.0", "1.10.0", "1.10.1-rc1", "2.0", "v1.11.0", "1.10.0-alpine", "1.11.2-alpine"}
+	cases := []struct {
+		current  string
+		expected string
+	}{
+		{"1.9.0", "1.10.0"},
+		{"1.10.0", ""},
+		{"1.9", "2.0"},
+		{"v1.0.0", "v1.11.0"},
+		{"1.9.0-alpine", "1.11.2-alpine"},
+		{"latest", ""},
+	}
+
+	for _, c := range cases {
+		if latest := latestTag(c.current, tags); latest != c.expected {
+			t.Errorf("Expected latest tag of %s to be %q, but got %q.", c.current, c.expected, latest)
+		}
+	}
+}
+
+// Fake registry requiring a token from its authentication service and serving tags in pages of two.
+func newFakeRegistry(t *testing.T, tags map[string][]string, requests *int32) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, `{"token":"secret"}`)
+			return
+		}
+
+		atomic.AddInt32(requests, 1)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		repository := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2/"), "/tags/list")
+		repositoryTags, ok := tags[repository]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		page := repositoryTags
+		if r.URL.Query().Get("last") == "" && len(page) > 2 {
+			page = page[:2]
+			w.Header().Set("Link", fmt.Sprintf(`</v2/%s/tags/list?last=%s&n=2>; rel="next"`, repository, page[1]))
+		} else if r.URL.Query().Get("last") != "" {
+			page = page[2:]
+		}
+		fmt.Fprintf(w, `{"name":%q,"tags":["%s"]}`, repository, strings.Join(page, `","`))
+	}))
+
+	return server
+}
+
+func newTestWorkloads(host string) *fake.Clientset {
+	template := func(images ...string) v1.PodTemplateSpec {
+		spec := v1.PodTemplateSpec{}
+		for i, image := range images {
+			spec.Spec.Containers = append(spec.Spec.Containers, v1.Container{Name: fmt.Sprintf("c%d", i),
+				Image: image})
+		}
+		return spec
+	}
+
+	return fake.NewSimpleClientset(
+		&apps.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       apps.DeploymentSpec{Template: template(host+"/org/web:1.0.0", "nginx:1.0.0")},
+		},
+		&apps.StatefulSet{
+			ObjectMeta: metaV1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       apps.StatefulSetSpec{Template: template(host + "/org/db:2.0")},
+		},
+		&apps.DaemonSet{
+			ObjectMeta: metaV1.ObjectMeta{Name: "agent", Namespace: "default"},
+			Spec:       apps.DaemonSetSpec{Template: template(host + "/org/web:1.2.0")},
+		},
+	)
+}
+
+func TestCheckerList(t *testing.T) {
+	requests := int32(0)
+	server := newFakeRegistry(t, map[string][]string{"org/web": {"1.0.0", "1.1.0", "1.2.0", "latest"}}, &requests)
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	checker := NewChecker().Configure([]string{host}, time.Hour)
+	checker.client = server.Client()
+
+	result, err := checker.List(newTestWorkloads(host), "default")
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v.", err)
+	}
+
+	expected := []WorkloadUpdates{{Kind: "deployment", Namespace: "default", Name: "web",
+		Containers: []ContainerUpdate{{Container: "c0", Image: host + "/org/web:1.0.0", CurrentTag: "1.0.0",
+			LatestTag: "1.2.0", LatestImage: host + "/org/web:1.2.0"}}}}
+	if !reflect.DeepEqual(result.Workloads, expected) {
+		t.Errorf("Expected updates %+v, but got %+v.", expected, result.Workloads)
+	}
+
+	// Repository of the stateful set does not exist.
+	if len(result.Errors) != 1 {
+		t.Errorf("Expected one registry error, but got %v.", result.Errors)
+	}
+
+	cached := atomic.LoadInt32(&requests)
+	_, err = checker.List(newTestWorkloads(host), "default")
+	if more := atomic.LoadInt32(&requests) - cached; err != nil || more != 0 {
+		t.Errorf("Expected tags to be cached, but registry got %d more requests (error: %v).", more, err)
+	}
+}
+
+func TestCheckerBump(t *testing.T) {
+	requests := int32(0)
+	server := newFakeRegistry(t, map[string][]string{"org/web": {"1.0.0", "1.1.0", "1.2.0"}}, &requests)
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	checker := NewChecker().Configure([]string{host}, time.Hour)
+	checker.client = server.Client()
+	client := newTestWorkloads(host)
+
+	cases := []struct {
+		info  string
+		kind  string
+		spec  BumpSpec
+		check func(error) bool
+	}{
+		{"older tag", "deployment", BumpSpec{Container: "c0", Tag: "0.9.0"}, k8serrors.IsBadRequest},
+		{"missing tag", "deployment", BumpSpec{Container: "c0", Tag: "1.3.0"}, k8serrors.IsBadRequest},
+		{"unknown container", "deployment", BumpSpec{Container: "c9", Tag: "1.2.0"}, k8serrors.IsNotFound},
+		{"not allowed registry", "deployment", BumpSpec{Container: "c1", Tag: "1.2.0"}, k8serrors.IsInvalid},
+		{"unsupported kind", "job", BumpSpec{Container: "c0", Tag: "1.2.0"}, k8serrors.IsInvalid},
+	}
+
+	for _, c := range cases {
+		if _, err := checker.Bump(client, c.kind, "default", "web", &c.spec); !c.check(err) {
+			t.Errorf("%s: unexpected error %v.", c.info, err)
+		}
+	}
+
+	update, err := checker.Bump(client, "deployment", "default", "web", &BumpSpec{Container: "c0", Tag: "1.1.0"})
+	if err != nil || update.LatestImage != host+"/org/web:1.1.0" {
+		t.Fatalf("Expected image to be bumped to 1.1.0, but got %+v (error: %v).", update, err)
+	}
+
+	deployment, _ := client.AppsV1().Deployments("default").Get(context.TODO(), "web", metaV1.GetOptions{})
+	containers := deployment.Spec.Template.Spec.Containers
+	if len(containers) != 2 || containers[0].Image != update.LatestImage || containers[1].Image != "nginx:1.0.0" {
+		t.Errorf("Expected only image of c0 to be bumped, but got %+v.", containers)
+	}
+}
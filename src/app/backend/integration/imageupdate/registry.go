@@ -0,0 +1,202 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageupdate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// Maximum number of pages of tags read from a registry.
+	maxTagPages = 10
+	// Number of tags requested per page.
+	tagPageSize = 1000
+	// Maximum size of a registry response.
+	maxResponseSize = 4 * 1024 * 1024
+	// Failed lookups are cached for a shorter time, so registries are not asked on every request while they fail.
+	failureCacheTTL = 5 * time.Minute
+)
+
+// Tags of a repository read from its registry.
+type tagCacheEntry struct {
+	tags    []string
+	err     error
+	expires time.Time
+}
+
+// Parameters of the authentication challenge, i.e. Bearer realm="https://auth.docker.io/token",service="...".
+var challengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// Link to the next page of tags. See: https://docs.docker.com/registry/spec/api/#pagination
+var nextPagePattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// Returns tags of the repository, cached for the configured time.
+func (self *Checker) tags(ref reference) ([]string, error) {
+	key := ref.Registry + "/" + ref.Repository
+	self.mux.Lock()
+	entry, ok := self.cache[key]
+	self.mux.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.tags, entry.err
+	}
+
+	tags, err := self.fetchTags(ref)
+	entry = tagCacheEntry{tags: tags, err: err, expires: time.Now().Add(self.cacheTTL)}
+	if err != nil && self.cacheTTL > failureCacheTTL {
+		entry.expires = time.Now().Add(failureCacheTTL)
+	}
+
+	self.mux.Lock()
+	self.cache[key] = entry
+	self.mux.Unlock()
+	return tags, err
+}
+
+// Reads all tags of the repository using the registry HTTP API V2. Public repositories are read anonymously with
+// a token of the registry authentication service if the registry requires one.
+func (self *Checker) fetchTags(ref reference) ([]string, error) {
+	next := &url.URL{
+		Scheme:   self.scheme,
+		Host:     ref.host(),
+		Path:     fmt.Sprintf("/v2/%s/tags/list", ref.Repository),
+		RawQuery: fmt.Sprintf("n=%d", tagPageSize),
+	}
+
+	result := make([]string, 0)
+	token := ""
+	for page := 0; next != nil && page < maxTagPages; page++ {
+		response, err := self.get(next.String(), token)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode == http.StatusUnauthorized && len(token) == 0 {
+			challenge := response.Header.Get("WWW-Authenticate")
+			response.Body.Close()
+			if token, err = self.authenticate(challenge); err != nil {
+				return nil, err
+			}
+
+			if response, err = self.get(next.String(), token); err != nil {
+				return nil, err
+			}
+		}
+
+		tags := struct {
+			Tags []string `json:"tags"`
+		}{}
+		err = decodeResponse(response, &tags)
+		if err != nil {
+			return nil, fmt.Errorf("could not read tags of %s from %s: %s", ref.Repository, ref.Registry, err)
+		}
+
+		result = append(result, tags.Tags...)
+		next = nextPage(next, response.Header.Get("Link"))
+	}
+
+	return result, nil
+}
+
+// Gets token of the authentication service described by the challenge of the registry.
+func (self *Checker) authenticate(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication %q", challenge)
+	}
+
+	params := make(map[string]string)
+	for _, match := range challengeParamPattern.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	// Realm is given by the registry, so only HTTPS endpoints are trusted with requests of Dashboard.
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme != "https" {
+		return "", fmt.Errorf("invalid registry authentication realm %q", params["realm"])
+	}
+
+	query := realm.Query()
+	for _, param := range []string{"service", "scope"} {
+		if value, ok := params[param]; ok {
+			query.Set(param, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	response, err := self.get(realm.String(), "")
+	if err != nil {
+		return "", err
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err = decodeResponse(response, &token); err != nil {
+		return "", fmt.Errorf("could not get registry token: %s", err)
+	}
+
+	if len(token.Token) > 0 {
+		return token.Token, nil
+	}
+
+	return token.AccessToken, nil
+}
+
+func (self *Checker) get(address, token string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Accept", "application/json")
+	if len(token) > 0 {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return self.client.Do(request)
+}
+
+// Decodes JSON response and closes its body. Responses other than 200 OK are returned as errors.
+func decodeResponse(response *http.Response, result interface{}) error {
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response %s", response.Status)
+	}
+
+	return json.NewDecoder(io.LimitReader(response.Body, maxResponseSize)).Decode(result)
+}
+
+// Returns URL of the next page of tags from the Link header or nil if it is the last page. Links to other hosts
+// are ignored.
+func nextPage(current *url.URL, link string) *url.URL {
+	match := nextPagePattern.FindStringSubmatch(link)
+	if match == nil {
+		return nil
+	}
+
+	next, err := current.Parse(match[1])
+	if err != nil || next.Host != current.Host {
+		return nil
+	}
+
+	return next
+}
@@ -31,6 +31,12 @@ func (self *integrationManager) List() []api.Integration {
 
 	// Append all types of integrations
 	result = append(result, self.Metric().List()...)
+	if self.Falco().Enabled() {
+		result = append(result, self.Falco())
+	}
+	if self.ImageUpdate().Enabled() {
+		result = append(result, self.ImageUpdate())
+	}
 
 	return result
 }
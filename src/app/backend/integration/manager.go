@@ -19,6 +19,8 @@ import (
 
 	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
 	"github.com/kubernetes/dashboard/src/app/backend/integration/api"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/falco"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/imageupdate"
 	"github.com/kubernetes/dashboard/src/app/backend/integration/metric"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -31,11 +33,17 @@ type IntegrationManager interface {
 	GetState(id api.IntegrationID) (*api.IntegrationState, error)
 	// Metric returns metric manager that is responsible for management of metric integrations.
 	Metric() metric.MetricManager
+	// Falco returns store of runtime security events received from Falco.
+	Falco() *falco.EventStore
+	// ImageUpdate returns checker of available updates of images used by workloads.
+	ImageUpdate() *imageupdate.Checker
 }
 
 // Implements IntegrationManager interface
 type integrationManager struct {
-	metric metric.MetricManager
+	metric      metric.MetricManager
+	falco       *falco.EventStore
+	imageUpdate *imageupdate.Checker
 }
 
 // Metric implements integration manager interface. See IntegrationManager for more information.
@@ -43,6 +51,16 @@ func (self *integrationManager) Metric() metric.MetricManager {
 	return self.metric
 }
 
+// Falco implements integration manager interface. See IntegrationManager for more information.
+func (self *integrationManager) Falco() *falco.EventStore {
+	return self.falco
+}
+
+// ImageUpdate implements integration manager interface. See IntegrationManager for more information.
+func (self *integrationManager) ImageUpdate() *imageupdate.Checker {
+	return self.imageUpdate
+}
+
 // GetState implements integration manager interface. See IntegrationManager for more information.
 func (self *integrationManager) GetState(id api.IntegrationID) (*api.IntegrationState, error) {
 	for _, i := range self.List() {
@@ -68,6 +86,8 @@ func (self *integrationManager) getState(integration api.Integration) *api.Integ
 // NewIntegrationManager creates integration manager.
 func NewIntegrationManager(manager clientapi.ClientManager) IntegrationManager {
 	return &integrationManager{
-		metric: metric.NewMetricManager(manager),
+		metric:      metric.NewMetricManager(manager),
+		falco:       falco.NewEventStore(falco.DefaultCapacity),
+		imageUpdate: imageupdate.NewChecker(),
 	}
 }
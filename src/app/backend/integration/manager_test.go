@@ -87,6 +87,19 @@ func TestIntegrationManager_GetState(t *testing.T) {
 	}
 }
 
+func TestIntegrationManager_Falco(t *testing.T) {
+	iManager := NewIntegrationManager(nil)
+	if len(iManager.List()) != 0 {
+		t.Errorf("Expected unconfigured falco integration not to be listed, got %v", iManager.List())
+	}
+
+	iManager.Falco().Configure("token")
+	state, err := iManager.GetState(api.FalcoIntegrationID)
+	if err != nil || !state.Connected {
+		t.Errorf("Expected configured falco integration to be connected, got %v, %v", state, err)
+	}
+}
+
 func TestIntegrationManager_Metric(t *testing.T) {
 	metricManager := NewIntegrationManager(nil).Metric()
 	if metricManager == nil {
@@ -0,0 +1,101 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loglevel
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// Handler manages endpoints changing log level of applications at runtime.
+type Handler struct {
+	cManager clientapi.ClientManager
+	sManager settingsApi.SettingsManager
+}
+
+// Install creates new endpoints for log levels. Conventions of changing log level are defined by admins as
+// templates in the settings config map and applied with credentials of the user.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/loglevel/{kind}/{namespace}/{name}").
+			To(h.handleGetLogLevels).
+			Writes(LogLevelList{}))
+	ws.Route(
+		ws.PUT("/loglevel/{kind}/{namespace}/{name}").
+			To(h.handleSetLogLevel).
+			Reads(LogLevelSpec{}).
+			Writes(LogLevelResult{}))
+}
+
+// NewLogLevelHandler creates loglevel.Handler.
+func NewLogLevelHandler(cManager clientapi.ClientManager, sManager settingsApi.SettingsManager) *Handler {
+	return &Handler{cManager: cManager, sManager: sManager}
+}
+
+func (h *Handler) handleGetLogLevels(request *restful.Request, response *restful.Response) {
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	templates := h.sManager.GetLogLevelTemplates(h.cManager.InsecureClient())
+	result, err := GetLogLevels(k8sClient, templates, request.PathParameter("kind"),
+		request.PathParameter("namespace"), request.PathParameter("name"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (h *Handler) handleSetLogLevel(request *restful.Request, response *restful.Response) {
+	spec := new(LogLevelSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	cfg, err := h.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	kind, namespace, name := request.PathParameter("kind"), request.PathParameter("namespace"),
+		request.PathParameter("name")
+	templates := h.sManager.GetLogLevelTemplates(h.cManager.InsecureClient())
+	result, err := SetLogLevel(k8sClient, templates, kind, namespace, name, spec)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	log.Printf("Log level of %s %s/%s set to %s using template %s by %s", kind, namespace, name, spec.Level,
+		spec.Template, clientapi.GetIdentity(cfg))
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
@@ -0,0 +1,305 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loglevel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// LogLevel is a log level template applicable to a workload together with the level currently set.
+type LogLevel struct {
+	Template settingsApi.LogLevelTemplate `json:"template"`
+	// Level is empty if workload does not set the level yet.
+	Level string `json:"level"`
+}
+
+// LogLevelList contains log level templates applicable to a workload.
+type LogLevelList struct {
+	LogLevels []LogLevel `json:"logLevels"`
+}
+
+// LogLevelSpec is a request to change log level of a workload using given template.
+type LogLevelSpec struct {
+	Template string `json:"template"`
+	Level    string `json:"level"`
+}
+
+// LogLevelResult describes what has been done to change the log level.
+type LogLevelResult struct {
+	Template string `json:"template"`
+	Level    string `json:"level"`
+	// PatchedPods is the number of running pods, that have been annotated.
+	PatchedPods int `json:"patchedPods"`
+	// Restarted is true if pod template has been changed, so pods are replaced by a rollout.
+	Restarted bool `json:"restarted"`
+}
+
+// workload is a controller, which log level can be changed.
+type workload struct {
+	kind      string
+	namespace string
+	name      string
+	template  *v1.PodTemplateSpec
+	selector  *metaV1.LabelSelector
+	patch     func(data []byte) error
+}
+
+// getWorkload returns workload of given kind. Only controllers, that roll out changes of their pod template,
+// are supported.
+func getWorkload(client kubernetes.Interface, kind, namespace, name string) (*workload, error) {
+	w := &workload{kind: strings.ToLower(kind), namespace: namespace, name: name}
+	apps := client.AppsV1()
+	switch w.kind {
+	case "deployment":
+		d, err := apps.Deployments(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		w.template, w.selector = &d.Spec.Template, d.Spec.Selector
+		w.patch = func(data []byte) error {
+			_, err := apps.Deployments(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, data,
+				metaV1.PatchOptions{})
+			return err
+		}
+	case "statefulset":
+		s, err := apps.StatefulSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		w.template, w.selector = &s.Spec.Template, s.Spec.Selector
+		w.patch = func(data []byte) error {
+			_, err := apps.StatefulSets(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, data,
+				metaV1.PatchOptions{})
+			return err
+		}
+	case "daemonset":
+		d, err := apps.DaemonSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		w.template, w.selector = &d.Spec.Template, d.Spec.Selector
+		w.patch = func(data []byte) error {
+			_, err := apps.DaemonSets(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, data,
+				metaV1.PatchOptions{})
+			return err
+		}
+	default:
+		return nil, errors.NewInvalid(fmt.Sprintf("changing log level is not supported for kind: %s", kind))
+	}
+
+	return w, nil
+}
+
+// GetLogLevels returns templates applicable to given workload with levels it currently uses.
+func GetLogLevels(client kubernetes.Interface, templates []settingsApi.LogLevelTemplate, kind, namespace,
+	name string) (*LogLevelList, error) {
+	w, err := getWorkload(client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LogLevelList{LogLevels: []LogLevel{}}
+	for _, template := range templates {
+		if !template.Matches(w.template.Labels) {
+			continue
+		}
+
+		level, err := getCurrentLevel(client, w, template)
+		if err != nil {
+			return nil, err
+		}
+		result.LogLevels = append(result.LogLevels, LogLevel{Template: template, Level: level})
+	}
+
+	return result, nil
+}
+
+// SetLogLevel changes log level of given workload using the template named in spec.
+func SetLogLevel(client kubernetes.Interface, templates []settingsApi.LogLevelTemplate, kind, namespace,
+	name string, spec *LogLevelSpec) (*LogLevelResult, error) {
+	w, err := getWorkload(client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := findTemplate(templates, spec, w.template.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LogLevelResult{Template: template.Name, Level: spec.Level}
+	switch template.Type {
+	case settingsApi.LogLevelEnv:
+		patch, err := getEnvPatch(w.template, template, spec.Level)
+		if err != nil {
+			return nil, err
+		}
+		if err := w.patch(patch); err != nil {
+			return nil, err
+		}
+		result.Restarted = true
+	case settingsApi.LogLevelAnnotation:
+		pods, err := getPods(client, w)
+		if err != nil {
+			return nil, err
+		}
+		patch, _ := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": map[string]string{template.Key: spec.Level}},
+		})
+		for _, pod := range pods {
+			if _, err := client.CoreV1().Pods(namespace).Patch(context.TODO(), pod.Name, types.MergePatchType, patch,
+				metaV1.PatchOptions{}); err != nil {
+				return nil, err
+			}
+			result.PatchedPods++
+		}
+
+		if template.Restart {
+			if err := w.patch(getTemplateAnnotationPatch(template.Key, spec.Level)); err != nil {
+				return nil, err
+			}
+			result.Restarted = true
+		}
+	}
+
+	return result, nil
+}
+
+// findTemplate returns template requested by spec if it applies to the workload and allows requested level.
+func findTemplate(templates []settingsApi.LogLevelTemplate, spec *LogLevelSpec, labels map[string]string) (
+	*settingsApi.LogLevelTemplate, error) {
+	for i := range templates {
+		template := &templates[i]
+		if template.Name != spec.Template {
+			continue
+		}
+		if !template.Matches(labels) {
+			return nil, errors.NewBadRequest(fmt.Sprintf("log level template %s does not apply to the workload",
+				template.Name))
+		}
+		for _, level := range template.Levels {
+			if level == spec.Level {
+				return template, nil
+			}
+		}
+		return nil, errors.NewBadRequest(fmt.Sprintf("log level %s is not allowed by template %s, expected one of %s",
+			spec.Level, template.Name, strings.Join(template.Levels, ", ")))
+	}
+
+	return nil, errors.NewNotFound(fmt.Sprintf("log level template %s not found", spec.Template))
+}
+
+// getEnvPatch returns strategic merge patch setting the environment variable of template containers. Value
+// source of an existing variable is removed, as variable can not have both.
+func getEnvPatch(podTemplate *v1.PodTemplateSpec, template *settingsApi.LogLevelTemplate, level string) (
+	[]byte, error) {
+	containers := make([]map[string]interface{}, 0)
+	for _, container := range podTemplate.Spec.Containers {
+		if len(template.Container) > 0 && container.Name != template.Container {
+			continue
+		}
+		containers = append(containers, map[string]interface{}{
+			"name": container.Name,
+			"env":  []map[string]interface{}{{"name": template.Key, "value": level, "valueFrom": nil}},
+		})
+	}
+
+	if len(containers) == 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("container %s not found", template.Container))
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"template": map[string]interface{}{
+			"spec": map[string]interface{}{"containers": containers},
+		}},
+	})
+}
+
+// getTemplateAnnotationPatch returns strategic merge patch setting an annotation of the pod template.
+func getTemplateAnnotationPatch(key, level string) []byte {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": map[string]string{key: level}},
+		}},
+	})
+	return patch
+}
+
+// getCurrentLevel returns level set by the template. Annotations set only on running pods are taken from
+// the first pod, that has one.
+func getCurrentLevel(client kubernetes.Interface, w *workload, template settingsApi.LogLevelTemplate) (
+	string, error) {
+	if template.Type == settingsApi.LogLevelEnv {
+		for _, container := range w.template.Spec.Containers {
+			if len(template.Container) > 0 && container.Name != template.Container {
+				continue
+			}
+			for _, env := range container.Env {
+				if env.Name == template.Key {
+					return env.Value, nil
+				}
+			}
+		}
+		return "", nil
+	}
+
+	if level, ok := w.template.Annotations[template.Key]; ok {
+		return level, nil
+	}
+
+	pods, err := getPods(client, w)
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range pods {
+		if level, ok := pod.Annotations[template.Key]; ok {
+			return level, nil
+		}
+	}
+	return "", nil
+}
+
+// getPods returns running pods of the workload.
+func getPods(client kubernetes.Interface, w *workload) ([]v1.Pod, error) {
+	selector, err := metaV1.LabelSelectorAsSelector(w.selector)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.CoreV1().Pods(w.namespace).List(context.TODO(),
+		metaV1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]v1.Pod, 0)
+	for _, pod := range list.Items {
+		if pod.Status.Phase == v1.PodRunning || pod.Status.Phase == v1.PodPending {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
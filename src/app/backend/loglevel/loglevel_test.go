@@ -0,0 +1,127 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loglevel
+
+import (
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+var testTemplates = []settingsApi.LogLevelTemplate{
+	{
+		Name:     "go",
+		Selector: map[string]string{"lang": "go"},
+		Type:     settingsApi.LogLevelEnv,
+		Key:      "LOG_LEVEL",
+		Levels:   []string{"info", "debug"},
+	},
+	{
+		Name:   "downward",
+		Type:   settingsApi.LogLevelAnnotation,
+		Key:    "example.com/log-level",
+		Levels: []string{"info", "debug"},
+	},
+	{
+		Name:     "java",
+		Selector: map[string]string{"lang": "java"},
+		Type:     settingsApi.LogLevelEnv,
+		Key:      "LOGGING_LEVEL_ROOT",
+		Levels:   []string{"INFO"},
+	},
+}
+
+func newTestClient() *fake.Clientset {
+	labels := map[string]string{"app": "api", "lang": "go"}
+	return fake.NewSimpleClientset(
+		&apps.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "shop", Name: "api"},
+			Spec: apps.DeploymentSpec{
+				Selector: &metaV1.LabelSelector{MatchLabels: labels},
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metaV1.ObjectMeta{Labels: labels},
+					Spec: v1.PodSpec{Containers: []v1.Container{{
+						Name: "api",
+						Env:  []v1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}},
+					}}},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "shop", Name: "api-1", Labels: labels},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+		},
+	)
+}
+
+func TestGetLogLevels(t *testing.T) {
+	result, err := GetLogLevels(newTestClient(), testTemplates, "Deployment", "shop", "api")
+	if err != nil {
+		t.Fatalf("GetLogLevels() returned error: %s", err)
+	}
+
+	if len(result.LogLevels) != 2 {
+		t.Fatalf("GetLogLevels() returned %d templates, expected go and downward", len(result.LogLevels))
+	}
+	if result.LogLevels[0].Template.Name != "go" || result.LogLevels[0].Level != "info" {
+		t.Errorf("GetLogLevels() returned %#v, expected go template at info level", result.LogLevels[0])
+	}
+	if result.LogLevels[1].Level != "" {
+		t.Errorf("GetLogLevels() returned %#v, expected no level set by annotation", result.LogLevels[1])
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	cases := []struct {
+		spec     LogLevelSpec
+		expected *LogLevelResult
+		valid    bool
+	}{
+		{LogLevelSpec{Template: "go", Level: "debug"}, &LogLevelResult{Template: "go", Level: "debug",
+			Restarted: true}, true},
+		{LogLevelSpec{Template: "downward", Level: "debug"}, &LogLevelResult{Template: "downward",
+			Level: "debug", PatchedPods: 1}, true},
+		{LogLevelSpec{Template: "go", Level: "trace"}, nil, false},
+		{LogLevelSpec{Template: "java", Level: "INFO"}, nil, false},
+		{LogLevelSpec{Template: "python", Level: "info"}, nil, false},
+	}
+
+	for _, c := range cases {
+		client := newTestClient()
+		result, err := SetLogLevel(client, testTemplates, "deployment", "shop", "api", &c.spec)
+		if (err == nil) != c.valid {
+			t.Errorf("SetLogLevel(%#v) returned error %v, expected valid: %v", c.spec, err, c.valid)
+			continue
+		}
+		if !c.valid {
+			continue
+		}
+		if *result != *c.expected {
+			t.Errorf("SetLogLevel(%#v) == %#v, expected %#v", c.spec, result, c.expected)
+		}
+
+		levels, _ := GetLogLevels(client, testTemplates, "deployment", "shop", "api")
+		for _, level := range levels.LogLevels {
+			if level.Template.Name == c.spec.Template && level.Level != c.spec.Level {
+				t.Errorf("SetLogLevel(%#v) did not change the level, got %s", c.spec, level.Level)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"log"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/stream"
+)
+
+// heartbeatInterval is the interval of comments sent to keep the connection open through proxies.
+const heartbeatInterval = 25 * time.Second
+
+// Handler manages endpoints pushing notifications about finished long running operations.
+type Handler struct {
+	cManager clientapi.ClientManager
+}
+
+// Install creates new endpoints for notifications.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/notification/{kind}/{namespace}/{name}").
+			To(h.handleNotification).
+			Writes(Notification{}).
+			Metadata(stream.RouteMetadata, true))
+}
+
+// NewNotificationHandler creates notification.Handler.
+func NewNotificationHandler(cManager clientapi.ClientManager) *Handler {
+	return &Handler{cManager: cManager}
+}
+
+// handleNotification streams server-sent events. A single 'notification' event is sent once the job of given name
+// completes or the rollout of the deployment, stateful set or daemon set finishes, then the stream ends. Watch
+// failures are sent as an 'error' event.
+func (h *Handler) handleNotification(request *restful.Request, response *restful.Response) {
+	k8sClient, err := h.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	watcher, err := newResourceWatcher(k8sClient, kind, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	stream.StartEvents(response)
+
+	type outcome struct {
+		result  *Result
+		message string
+		err     error
+	}
+
+	ctx := request.Request.Context()
+	done := make(chan outcome, 1)
+	go func() {
+		result, message, err := watcher.waitFor(ctx, name)
+		done <- outcome{result, message, err}
+	}()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			stream.WriteHeartbeat(response)
+		case o := <-done:
+			if o.err != nil {
+				log.Printf("Could not watch %s %s/%s: %s", kind, namespace, name, o.err)
+				stream.WriteEvent(response, "error", o.err.Error())
+				return
+			}
+
+			stream.WriteEvent(response, "notification", Notification{
+				Kind:      kind,
+				Namespace: namespace,
+				Name:      name,
+				Result:    *o.result,
+				Message:   o.message,
+				Time:      metaV1.Now(),
+			})
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Result is the outcome of a watched operation.
+type Result string
+
+const (
+	// Succeeded means that the job completed or the rollout finished.
+	Succeeded Result = "Succeeded"
+	// Failed means that the job failed or the rollout exceeded its progress deadline.
+	Failed Result = "Failed"
+	// Deleted means that the resource was deleted before the operation finished.
+	Deleted Result = "Deleted"
+)
+
+// Notification is pushed to the client once the watched operation finishes.
+type Notification struct {
+	Kind      string      `json:"kind"`
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	Result    Result      `json:"result"`
+	Message   string      `json:"message,omitempty"`
+	Time      metaV1.Time `json:"time"`
+}
+
+// Returns result of the job or nil if it is still running.
+func getJobResult(job *batchv1.Job) (*Result, string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return toResult(Succeeded), fmt.Sprintf("Job completed with %d succeeded pods", job.Status.Succeeded)
+		case batchv1.JobFailed:
+			return toResult(Failed), condition.Message
+		}
+	}
+
+	return nil, ""
+}
+
+// Returns result of the deployment rollout or nil if it is still in progress. Logic follows
+// 'kubectl rollout status'.
+func getDeploymentResult(deployment *appsv1.Deployment) (*Result, string) {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return nil, ""
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return toResult(Failed), condition.Message
+		}
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	if deployment.Status.UpdatedReplicas < replicas || deployment.Status.Replicas > deployment.Status.UpdatedReplicas ||
+		deployment.Status.AvailableReplicas < deployment.Status.UpdatedReplicas {
+		return nil, ""
+	}
+
+	return toResult(Succeeded), fmt.Sprintf("Rollout finished with %d available replicas",
+		deployment.Status.AvailableReplicas)
+}
+
+// Returns result of the stateful set rollout or nil if it is still in progress.
+func getStatefulSetResult(statefulSet *appsv1.StatefulSet) (*Result, string) {
+	if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		return nil, ""
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	if statefulSet.Status.ReadyReplicas < replicas {
+		return nil, ""
+	}
+
+	if statefulSet.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType &&
+		statefulSet.Status.UpdateRevision != statefulSet.Status.CurrentRevision {
+		return nil, ""
+	}
+
+	return toResult(Succeeded), fmt.Sprintf("Rollout finished with %d ready replicas",
+		statefulSet.Status.ReadyReplicas)
+}
+
+// Returns result of the daemon set rollout or nil if it is still in progress.
+func getDaemonSetResult(daemonSet *appsv1.DaemonSet) (*Result, string) {
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return nil, ""
+	}
+
+	if daemonSet.Status.UpdatedNumberScheduled < daemonSet.Status.DesiredNumberScheduled ||
+		daemonSet.Status.NumberAvailable < daemonSet.Status.DesiredNumberScheduled {
+		return nil, ""
+	}
+
+	return toResult(Succeeded), fmt.Sprintf("Rollout finished with %d available pods",
+		daemonSet.Status.NumberAvailable)
+}
+
+func toResult(result Result) *Result {
+	return &result
+}
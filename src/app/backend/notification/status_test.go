@@ -0,0 +1,171 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func resultString(result *Result) string {
+	if result == nil {
+		return "<nil>"
+	}
+
+	return string(*result)
+}
+
+func TestGetJobResult(t *testing.T) {
+	cases := []struct {
+		conditions []batchv1.JobCondition
+		expected   string
+	}{
+		{nil, "<nil>"},
+		{[]batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionFalse}}, "<nil>"},
+		{[]batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}, string(Succeeded)},
+		{[]batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue}}, string(Failed)},
+	}
+
+	for _, c := range cases {
+		result, _ := getJobResult(&batchv1.Job{Status: batchv1.JobStatus{Conditions: c.conditions}})
+		if actual := resultString(result); actual != c.expected {
+			t.Errorf("getJobResult(%v) == %s, expected %s", c.conditions, actual, c.expected)
+		}
+	}
+}
+
+func TestGetDeploymentResult(t *testing.T) {
+	replicas := int32(2)
+	cases := []struct {
+		info       string
+		generation int64
+		status     appsv1.DeploymentStatus
+		expected   string
+	}{
+		{
+			"generation not observed yet", 2,
+			appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			"<nil>",
+		},
+		{
+			"old replicas still running", 1,
+			appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2},
+			"<nil>",
+		},
+		{
+			"rollout finished", 1,
+			appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			string(Succeeded),
+		},
+		{
+			"progress deadline exceeded", 1,
+			appsv1.DeploymentStatus{ObservedGeneration: 1, Conditions: []appsv1.DeploymentCondition{{
+				Type: appsv1.DeploymentProgressing, Status: v1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+			}}},
+			string(Failed),
+		},
+	}
+
+	for _, c := range cases {
+		result, _ := getDeploymentResult(&appsv1.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Generation: c.generation},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     c.status,
+		})
+		if actual := resultString(result); actual != c.expected {
+			t.Errorf("%s: expected result %s, but got %s", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestGetStatefulSetResult(t *testing.T) {
+	replicas := int32(2)
+	cases := []struct {
+		info     string
+		status   appsv1.StatefulSetStatus
+		expected string
+	}{
+		{
+			"revision not updated yet",
+			appsv1.StatefulSetStatus{ReadyReplicas: 2, CurrentRevision: "rev-1", UpdateRevision: "rev-2"},
+			"<nil>",
+		},
+		{
+			"rollout finished",
+			appsv1.StatefulSetStatus{ReadyReplicas: 2, CurrentRevision: "rev-2", UpdateRevision: "rev-2"},
+			string(Succeeded),
+		},
+	}
+
+	for _, c := range cases {
+		result, _ := getStatefulSetResult(&appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:       &replicas,
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+			},
+			Status: c.status,
+		})
+		if actual := resultString(result); actual != c.expected {
+			t.Errorf("%s: expected result %s, but got %s", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestGetDaemonSetResult(t *testing.T) {
+	cases := []struct {
+		status   appsv1.DaemonSetStatus
+		expected string
+	}{
+		{appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 2, NumberAvailable: 3}, "<nil>"},
+		{appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+			string(Succeeded)},
+	}
+
+	for _, c := range cases {
+		result, _ := getDaemonSetResult(&appsv1.DaemonSet{Status: c.status})
+		if actual := resultString(result); actual != c.expected {
+			t.Errorf("getDaemonSetResult(%v) == %s, expected %s", c.status, actual, c.expected)
+		}
+	}
+}
+
+func TestWaitForFinishedJob(t *testing.T) {
+	client := fake.NewSimpleClientset(&batchv1.Job{
+		ObjectMeta: metaV1.ObjectMeta{Name: "job-1", Namespace: "ns-1"},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: v1.ConditionTrue},
+		}},
+	})
+
+	watcher, err := newResourceWatcher(client, "job", "ns-1", "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, _, err := watcher.waitFor(context.Background(), "job-1")
+	if err != nil || resultString(result) != string(Succeeded) {
+		t.Errorf("Expected finished job to succeed, but got %s (%v)", resultString(result), err)
+	}
+
+	if _, err := newResourceWatcher(client, "pod", "ns-1", "pod-1"); err == nil {
+		t.Error("Expected error for unsupported kind")
+	}
+}
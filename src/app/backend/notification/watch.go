@@ -0,0 +1,160 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// resourceWatcher gets and watches a single resource and tells whether its operation has finished.
+type resourceWatcher struct {
+	get    func(ctx context.Context) (runtime.Object, error)
+	watch  func(ctx context.Context, options metaV1.ListOptions) (watch.Interface, error)
+	result func(obj runtime.Object) (*Result, string)
+}
+
+// Returns watcher of the resource of given kind. Only resources with long running operations are supported.
+func newResourceWatcher(client kubernetes.Interface, kind, namespace, name string) (*resourceWatcher, error) {
+	switch kind {
+	case "job":
+		jobs := client.BatchV1().Jobs(namespace)
+		return &resourceWatcher{
+			get: func(ctx context.Context) (runtime.Object, error) {
+				return jobs.Get(ctx, name, metaV1.GetOptions{})
+			},
+			watch: jobs.Watch,
+			result: func(obj runtime.Object) (*Result, string) {
+				return getJobResult(obj.(*batchv1.Job))
+			},
+		}, nil
+	case "deployment":
+		deployments := client.AppsV1().Deployments(namespace)
+		return &resourceWatcher{
+			get: func(ctx context.Context) (runtime.Object, error) {
+				return deployments.Get(ctx, name, metaV1.GetOptions{})
+			},
+			watch: deployments.Watch,
+			result: func(obj runtime.Object) (*Result, string) {
+				return getDeploymentResult(obj.(*appsv1.Deployment))
+			},
+		}, nil
+	case "statefulset":
+		statefulSets := client.AppsV1().StatefulSets(namespace)
+		return &resourceWatcher{
+			get: func(ctx context.Context) (runtime.Object, error) {
+				return statefulSets.Get(ctx, name, metaV1.GetOptions{})
+			},
+			watch: statefulSets.Watch,
+			result: func(obj runtime.Object) (*Result, string) {
+				return getStatefulSetResult(obj.(*appsv1.StatefulSet))
+			},
+		}, nil
+	case "daemonset":
+		daemonSets := client.AppsV1().DaemonSets(namespace)
+		return &resourceWatcher{
+			get: func(ctx context.Context) (runtime.Object, error) {
+				return daemonSets.Get(ctx, name, metaV1.GetOptions{})
+			},
+			watch: daemonSets.Watch,
+			result: func(obj runtime.Object) (*Result, string) {
+				return getDaemonSetResult(obj.(*appsv1.DaemonSet))
+			},
+		}, nil
+	}
+
+	return nil, errors.NewBadRequest("notifications are supported only for jobs, deployments, stateful sets " +
+		"and daemon sets")
+}
+
+// waitFor blocks until the operation of the resource finishes and returns its result. Watches closed by the
+// apiserver are restarted from the last seen resource version.
+func (self *resourceWatcher) waitFor(ctx context.Context, name string) (*Result, string, error) {
+	obj, err := self.get(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if result, message := self.result(obj); result != nil {
+		return result, message, nil
+	}
+
+	resourceVersion, err := getResourceVersion(obj)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for {
+		watcher, err := self.watch(ctx, metaV1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
+		result, message, err := self.consume(watcher, &resourceVersion)
+		watcher.Stop()
+		if result != nil || err != nil {
+			return result, message, err
+		}
+
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+	}
+}
+
+// Reads events until the operation finishes or the watch is closed.
+func (self *resourceWatcher) consume(watcher watch.Interface, resourceVersion *string) (*Result, string, error) {
+	for event := range watcher.ResultChan() {
+		switch event.Type {
+		case watch.Deleted:
+			return toResult(Deleted), "Resource was deleted", nil
+		case watch.Error:
+			return nil, "", k8serrors.FromObject(event.Object)
+		case watch.Added, watch.Modified:
+			if version, err := getResourceVersion(event.Object); err == nil {
+				*resourceVersion = version
+			}
+
+			if result, message := self.result(event.Object); result != nil {
+				return result, message, nil
+			}
+		}
+	}
+
+	return nil, "", nil
+}
+
+func getResourceVersion(obj runtime.Object) (string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
+	}
+
+	return accessor.GetResourceVersion(), nil
+}
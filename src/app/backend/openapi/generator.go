@@ -0,0 +1,356 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// modulePath is trimmed from names of schemas of Dashboard types.
+	modulePath = "github.com/kubernetes/dashboard/src/app/backend/"
+	// schemaRefPrefix is a prefix of references to schemas in components.
+	schemaRefPrefix = "#/components/schemas/"
+)
+
+// pathParameterRegexp matches parameters of route paths, i.e. "{namespace}" or "{path:*}".
+var pathParameterRegexp = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// parameterLocations maps kinds of documented parameters, that are not a part of the path nor the body, to their
+// locations.
+var parameterLocations = map[int]string{
+	restful.QueryParameterKind:  "query",
+	restful.HeaderParameterKind: "header",
+}
+
+// schemaNameRegexp matches characters not allowed in names of schemas.
+var schemaNameRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// knownSchemas are schemas of types, that are marshaled differently than their fields suggest.
+var knownSchemas = map[reflect.Type]Schema{
+	reflect.TypeOf(time.Time{}):         {Type: "string", Format: "date-time"},
+	reflect.TypeOf(metav1.Time{}):       {Type: "string", Format: "date-time"},
+	reflect.TypeOf(metav1.MicroTime{}):  {Type: "string", Format: "date-time"},
+	reflect.TypeOf(metav1.Duration{}):   {Type: "string"},
+	reflect.TypeOf(resource.Quantity{}): {Type: "string"},
+	reflect.TypeOf(json.RawMessage{}):   {},
+}
+
+// NewDocument describes all routes of web services registered in given container. Request and response schemas are
+// read from samples of the routes, errors of all routes are described by errors.APIError.
+func NewDocument(container *restful.Container, version string) *Document {
+	g := &generator{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Kubernetes Dashboard API",
+			Description: "API of the Kubernetes Dashboard backend.",
+			Version:     version,
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"jweToken": {Type: "apiKey", In: "header", Name: "jweToken",
+					Description: "Token returned by login in AuthResponse."},
+				"bearer": {Type: "http", Scheme: "bearer", Description: "Kubernetes bearer token."},
+			},
+		},
+		// Requests without credentials are allowed when the login can be skipped.
+		Security: []map[string][]string{{"jweToken": {}}, {"bearer": {}}, {}},
+	}
+
+	errorSchema := g.schemaOf(reflect.TypeOf(errors.APIError{}))
+	operationIDs := make(map[string]int)
+	for _, ws := range container.RegisteredWebServices() {
+		for _, route := range ws.Routes() {
+			path := pathParameterRegexp.ReplaceAllString(route.Path, "{$1}")
+			if doc.Paths[path] == nil {
+				doc.Paths[path] = make(PathItem)
+			}
+
+			operation := g.newOperation(route, errorSchema)
+			operationIDs[operation.OperationID]++
+			if count := operationIDs[operation.OperationID]; count > 1 {
+				operation.OperationID = fmt.Sprintf("%s%d", operation.OperationID, count)
+			}
+			doc.Paths[path][strings.ToLower(route.Method)] = operation
+		}
+	}
+
+	return doc
+}
+
+// generator keeps schemas of structs found in samples of routes.
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func (g *generator) newOperation(route restful.Route, errorSchema *Schema) *Operation {
+	operation := &Operation{
+		OperationID: getOperationID(route),
+		Summary:     route.Doc,
+		Tags:        getTags(route.Path),
+		Parameters:  getParameters(route),
+		Responses: map[string]*Response{
+			"default": {Description: "Error", Content: jsonContent(errorSchema)},
+		},
+	}
+
+	if route.ReadSample != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(g.schemaOf(reflect.TypeOf(route.ReadSample))),
+		}
+	}
+
+	success := &Response{Description: http.StatusText(http.StatusOK)}
+	if route.WriteSample != nil {
+		success.Content = jsonContent(g.schemaOf(reflect.TypeOf(route.WriteSample)))
+	}
+	operation.Responses[fmt.Sprint(http.StatusOK)] = success
+	return operation
+}
+
+// schemaOf returns schema of given type. Schemas of named structs are added to components and referenced.
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if known, ok := knownSchemas[t]; ok {
+		return &known
+	}
+
+	if t.Kind() == reflect.Struct && !t.Implements(jsonMarshalerType) &&
+		!reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		if len(t.Name()) == 0 {
+			return g.structSchema(t)
+		}
+		return g.refSchema(t)
+	}
+
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		// Custom JSON of types such as IntOrString can not be described by reflection.
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	default:
+		// Interfaces, i.e. non-critical errors of lists, can hold any value.
+		return &Schema{}
+	}
+}
+
+// refSchema adds schema of given named struct to components, unless it is there already, and references it.
+func (g *generator) refSchema(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.getSchemaName(t)
+		g.names[t] = name
+		// Placeholder stops recursion of self-referencing types.
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.structSchema(t)
+	}
+
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+// structSchema returns schema of given struct with properties named by their JSON tags. Fields of embedded structs
+// without a name are inlined, as they are by encoding/json.
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, inline, ok := getFieldName(field)
+		if !ok {
+			continue
+		}
+
+		if inline {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			for property, s := range g.structSchema(embedded).Properties {
+				schema.Properties[property] = s
+			}
+			continue
+		}
+
+		property := g.schemaOf(field.Type)
+		if field.Type.Kind() == reflect.Ptr && len(property.Ref) == 0 {
+			property.Nullable = true
+		}
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+// getSchemaName returns unique name of given named type, i.e. "auth.api.LoginSpec" or "k8s.io.api.core.v1.Pod".
+func (g *generator) getSchemaName(t reflect.Type) string {
+	pkg := strings.TrimPrefix(t.PkgPath(), modulePath)
+	name := strings.Replace(pkg, "/", ".", -1) + "." + t.Name()
+	return schemaNameRegexp.ReplaceAllString(name, "_")
+}
+
+// getFieldName returns JSON name of given field, whether its fields are inlined and whether it is marshaled at all.
+func getFieldName(field reflect.StructField) (name string, inline bool, ok bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name = strings.Split(tag, ",")[0]
+	fieldType := field.Type
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	if field.Anonymous && len(name) == 0 && fieldType.Kind() == reflect.Struct {
+		return "", true, true
+	}
+
+	if len(field.PkgPath) > 0 {
+		// Unexported field.
+		return "", false, false
+	}
+
+	if len(name) == 0 {
+		name = field.Name
+	}
+	return name, false, true
+}
+
+// getOperationID returns name of the function handling given route without the "handle" prefix, i.e.
+// "getNamespaces" for "handleGetNamespaces".
+func getOperationID(route restful.Route) string {
+	operation := route.Operation
+	if i := strings.LastIndex(operation, "."); i >= 0 {
+		operation = operation[i+1:]
+	}
+	operation = strings.TrimPrefix(strings.TrimSuffix(operation, "-fm"), "handle")
+	if len(operation) == 0 {
+		return operation
+	}
+	return strings.ToLower(operation[:1]) + operation[1:]
+}
+
+// getTags returns the first segment of given path after the version of the API, i.e. "namespace" for
+// "/api/v1/namespace/{name}".
+func getTags(path string) []string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "v") && i+1 < len(segments) && i > 0 && segments[i-1] == "api" {
+			return []string{segments[i+1]}
+		}
+	}
+	return nil
+}
+
+// getParameters returns parameters of given route. Parameters of the path come first and are always required,
+// documented query and header parameters of the route follow.
+func getParameters(route restful.Route) []Parameter {
+	documented := make(map[string]restful.ParameterData)
+	for _, p := range route.ParameterDocs {
+		documented[p.Data().Name] = p.Data()
+	}
+
+	parameters := make([]Parameter, 0)
+	for _, match := range pathParameterRegexp.FindAllStringSubmatch(route.Path, -1) {
+		parameters = append(parameters, Parameter{Name: match[1], In: "path", Required: true,
+			Description: documented[match[1]].Description, Schema: &Schema{Type: "string"}})
+	}
+
+	for _, p := range route.ParameterDocs {
+		data := p.Data()
+		in, ok := parameterLocations[data.Kind]
+		if !ok {
+			continue
+		}
+
+		parameters = append(parameters, Parameter{
+			Name:        data.Name,
+			In:          in,
+			Description: data.Description,
+			Required:    data.Required,
+			Schema:      &Schema{Type: getParameterType(data.DataType)},
+		})
+	}
+
+	if len(parameters) == 0 {
+		return nil
+	}
+	return parameters
+}
+
+func getParameterType(dataType string) string {
+	switch dataType {
+	case "integer", "int", "int32", "int64":
+		return "integer"
+	case "boolean", "bool":
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{restful.MIME_JSON: {Schema: schema}}
+}
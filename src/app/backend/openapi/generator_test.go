@@ -0,0 +1,92 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+)
+
+type testMeta struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+}
+
+type testSpec struct {
+	testMeta
+	Replicas *int32            `json:"replicas,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Data     []byte            `json:"data"`
+	Children []testSpec        `json:"children"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func testHandler(*restful.Request, *restful.Response) {}
+
+func TestNewDocument(t *testing.T) {
+	ws := new(restful.WebService).Path("/api/v1")
+	ws.Route(ws.POST("/spec/{namespace}").To(testHandler).Reads(testSpec{}).Writes(testSpec{}))
+	ws.Route(ws.GET("/spec/{namespace}/{name}").To(testHandler).
+		Param(ws.QueryParameter("filter", "Filter of names.")).Writes(testSpec{}))
+	ws.Route(ws.GET("/file/{path:*}").To(testHandler))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	doc := NewDocument(container, "v1.0.0")
+
+	create := doc.Paths["/api/v1/spec/{namespace}"]["post"]
+	if create == nil || create.RequestBody == nil || create.OperationID != "testHandler" ||
+		!reflect.DeepEqual(create.Tags, []string{"spec"}) {
+		t.Fatalf("POST /api/v1/spec/{namespace} should be documented with request body, got %+v", create)
+	}
+
+	get := doc.Paths["/api/v1/spec/{namespace}/{name}"]["get"]
+	expectedParameters := []Parameter{
+		{Name: "namespace", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+		{Name: "name", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+		{Name: "filter", In: "query", Description: "Filter of names.", Schema: &Schema{Type: "string"}},
+	}
+	if get == nil || get.OperationID != "testHandler2" || !reflect.DeepEqual(get.Parameters, expectedParameters) {
+		t.Errorf("GET /api/v1/spec/{namespace}/{name} == %+v, expected parameters %+v", get, expectedParameters)
+	}
+
+	if doc.Paths["/api/v1/file/{path}"]["get"] == nil {
+		t.Error("path parameters with patterns should be documented by name")
+	}
+
+	if _, ok := get.Responses["default"]; !ok {
+		t.Error("errors should be documented as default responses")
+	}
+	if response := get.Responses["200"]; response.Description != http.StatusText(http.StatusOK) {
+		t.Errorf("unexpected success response %+v", response)
+	}
+
+	expected := &Schema{Type: "object", Properties: map[string]*Schema{
+		"name":     {Type: "string"},
+		"created":  {Type: "string", Format: "date-time"},
+		"replicas": {Type: "integer", Format: "int32", Nullable: true},
+		"labels":   {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		"data":     {Type: "string", Format: "byte"},
+		"children": {Type: "array", Items: &Schema{Ref: schemaRefPrefix + "openapi.testSpec"}},
+	}}
+	if actual := doc.Components.Schemas["openapi.testSpec"]; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("schema of testSpec == %+v, expected %+v", actual, expected)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/emicklei/go-restful"
+)
+
+// DocumentPath is the path the OpenAPI document of the API is served at.
+const DocumentPath = "/apidocs.json"
+
+// Handler serves the OpenAPI document describing routes of a container. The document is generated by the first
+// request, when all routes are registered, and then reused.
+type Handler struct {
+	container *restful.Container
+	version   string
+	once      sync.Once
+	document  []byte
+}
+
+// NewHandler creates Handler for given container and version of the API.
+func NewHandler(container *restful.Container, version string) *Handler {
+	return &Handler{container: container, version: version}
+}
+
+// ServeHTTP implements http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.once.Do(func() {
+		h.document, _ = json.Marshal(NewDocument(h.container, h.version))
+	})
+
+	w.Header().Set("Content-Type", restful.MIME_JSON)
+	w.Write(h.document)
+}
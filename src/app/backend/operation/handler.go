@@ -0,0 +1,110 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Handler manages endpoints related to long running operations.
+type Handler struct {
+	cManager clientapi.ClientManager
+	manager  *Manager
+}
+
+// Install creates new endpoints for long running operations. Operations are started by endpoints of the features
+// they belong to, i.e. 'POST /podcleanup?async=true', which return the operation right away.
+func (self *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/operation").
+			To(self.handleList).
+			Writes(OperationList{}))
+	ws.Route(
+		ws.GET("/operation/{id}").
+			To(self.handleGet).
+			Writes(Operation{}))
+	ws.Route(
+		ws.POST("/operation/{id}/cancel").
+			To(self.handleCancel).
+			Writes(Operation{}))
+}
+
+// NewOperationHandler creates operation.Handler.
+func NewOperationHandler(cManager clientapi.ClientManager, manager *Manager) *Handler {
+	return &Handler{cManager: cManager, manager: manager}
+}
+
+func (self *Handler) handleList(request *restful.Request, response *restful.Response) {
+	owner, err := GetOwner(self.cManager, request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := self.manager.List(owner)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (self *Handler) handleGet(request *restful.Request, response *restful.Response) {
+	owner, err := GetOwner(self.cManager, request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := self.manager.Get(owner, request.PathParameter("id"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (self *Handler) handleCancel(request *restful.Request, response *restful.Response) {
+	owner, err := GetOwner(self.cManager, request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := self.manager.Cancel(owner, request.PathParameter("id"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// GetOwner returns the owner of operations started with given request, i.e. a hash of its credentials.
+func GetOwner(cManager clientapi.ClientManager, request *restful.Request) (string, error) {
+	cfg, err := cManager.Config(request)
+	if err != nil {
+		return "", err
+	}
+
+	return clientapi.GetCredentialsKey(cfg), nil
+}
@@ -0,0 +1,389 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// Finished operations are kept this long, so that their results can be read.
+	operationTTL = time.Hour
+	// Maximum number of kept operations of all users.
+	maxOperations = 100
+	// Results larger than this are not kept, they would not fit into the secret.
+	maxResultSize = 64 << 10
+	// Minimum time between saves of progress reported by a running operation.
+	defaultProgressInterval = time.Second
+	// Running operations are saved at least this often, so that abandoned ones can be recognized.
+	defaultHeartbeatInterval = 10 * time.Second
+
+	// TypePodCleanup is the type of operations that clean up finished pods.
+	TypePodCleanup = "podcleanup"
+
+	msgOperationNotFound  = "Operation not found."
+	msgOperationAbandoned = "operation was abandoned, the replica running it has stopped"
+	msgResultTooLarge     = "Result is too large to be kept."
+)
+
+// State of an operation.
+type State string
+
+const (
+	StateRunning   State = "Running"
+	StateSucceeded State = "Succeeded"
+	StateFailed    State = "Failed"
+	StateCancelled State = "Cancelled"
+)
+
+// Progress of an operation, i.e. the number of processed items out of all items.
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// Operation is a long running operation started by a user. The replica that has started it does the work, while
+// its state is kept in a secret shared by all replicas, so that any of them can report progress and cancel it.
+type Operation struct {
+	ID       string   `json:"id"`
+	Type     string   `json:"type"`
+	State    State    `json:"state"`
+	Progress Progress `json:"progress"`
+
+	// CancelRequested is set once the operation is cancelled, until the replica running it stops.
+	CancelRequested bool `json:"cancelRequested"`
+
+	// Message explains the state, i.e. why the result is missing.
+	Message string `json:"message,omitempty"`
+
+	// Result of a finished operation. Its type depends on the type of the operation.
+	Result json.RawMessage `json:"result,omitempty"`
+
+	// Error of a failed operation.
+	Error *errors.APIError `json:"error,omitempty"`
+
+	Started  metaV1.Time  `json:"started"`
+	Updated  metaV1.Time  `json:"updated"`
+	Finished *metaV1.Time `json:"finished,omitempty"`
+}
+
+// OperationList contains operations of a user, most recently started first.
+type OperationList struct {
+	Items []Operation `json:"items"`
+}
+
+// Func does the work of an operation. It should report progress to given reporter and stop once given context is
+// done. Returned result is kept with the operation even if it has failed or has been cancelled.
+type Func func(ctx context.Context, reporter *Reporter) (interface{}, error)
+
+// Manager starts operations and keeps their state in a secret shared by all replicas. Operations are owned by
+// credentials they have been started with and are visible only to requests with the same credentials. Operations
+// run by a replica that stops are reported as failed.
+type Manager struct {
+	client            kubernetes.Interface
+	progressInterval  time.Duration
+	heartbeatInterval time.Duration
+
+	mux     sync.Mutex
+	running map[string]context.CancelFunc
+}
+
+// Start starts given function as an operation of given type and returns immediately.
+func (self *Manager) Start(owner, operationType string, run Func) (*Operation, error) {
+	id, err := newOperationID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := metaV1.Now()
+	operation := Operation{ID: id, Type: operationType, State: StateRunning, Started: now, Updated: now}
+	err = updateOperations(self.client, func(operations map[string]*storedOperation) error {
+		prune(operations, now.Time)
+		if len(operations) >= maxOperations {
+			return errors.NewTooManyRequests("too many operations are running, try again later", 60)
+		}
+
+		operations[id] = &storedOperation{Operation: operation, Owner: owner}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	self.mux.Lock()
+	self.running[id] = cancel
+	self.mux.Unlock()
+
+	go self.run(ctx, cancel, id, run)
+	return &operation, nil
+}
+
+// Get returns operation with given ID. Operations of other owners are reported as not found.
+func (self *Manager) Get(owner, id string) (*Operation, error) {
+	operations, err := getOperations(self.client)
+	if err != nil {
+		return nil, err
+	}
+
+	operation, ok := operations[id]
+	if !ok || operation.Owner != owner {
+		return nil, errors.NewNotFound(msgOperationNotFound)
+	}
+
+	return self.view(operation), nil
+}
+
+// List returns operations of given owner.
+func (self *Manager) List(owner string) (*OperationList, error) {
+	operations, err := getOperations(self.client)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OperationList{Items: make([]Operation, 0)}
+	for _, operation := range operations {
+		if operation.Owner == owner {
+			result.Items = append(result.Items, *self.view(operation))
+		}
+	}
+
+	sort.Slice(result.Items, func(i, j int) bool { return result.Items[j].Started.Before(&result.Items[i].Started) })
+	return result, nil
+}
+
+// Cancel requests cancellation of a running operation with given ID. Operation is cancelled right away if it runs
+// in this replica, otherwise once the replica running it saves its progress. Finished operations are returned as
+// they are.
+func (self *Manager) Cancel(owner, id string) (*Operation, error) {
+	var result *storedOperation
+	err := updateOperations(self.client, func(operations map[string]*storedOperation) error {
+		operation, ok := operations[id]
+		if !ok || operation.Owner != owner {
+			return errors.NewNotFound(msgOperationNotFound)
+		}
+
+		if operation.State == StateRunning {
+			operation.CancelRequested = true
+		}
+
+		result = operation
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	self.mux.Lock()
+	if cancel, ok := self.running[id]; ok {
+		cancel()
+	}
+	self.mux.Unlock()
+
+	return self.view(result), nil
+}
+
+// Runs given function and saves its result. Progress is saved periodically even if it is not reported, so that
+// cancellation requested through other replicas is noticed.
+func (self *Manager) run(ctx context.Context, cancel context.CancelFunc, id string, run Func) {
+	reporter := &Reporter{manager: self, id: id, cancel: cancel}
+	stop := make(chan struct{})
+	go reporter.heartbeat(stop)
+
+	result, runErr := run(ctx, reporter)
+	cancelled := ctx.Err() != nil
+	close(stop)
+	cancel()
+
+	self.mux.Lock()
+	delete(self.running, id)
+	self.mux.Unlock()
+
+	progress := reporter.progress()
+	err := updateOperations(self.client, func(operations map[string]*storedOperation) error {
+		operation, ok := operations[id]
+		if !ok {
+			return errors.NewNotFound(msgOperationNotFound)
+		}
+
+		finish(&operation.Operation, progress, result, runErr, cancelled)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Could not save result of operation %s: %s", id, err.Error())
+	}
+}
+
+// Returns operation as it is reported to users. Running operations that have not been saved for a long time are
+// reported as failed, since the replica running them has stopped.
+func (self *Manager) view(stored *storedOperation) *Operation {
+	operation := stored.Operation
+	if operation.State == StateRunning && time.Since(operation.Updated.Time) > 3*self.heartbeatInterval {
+		operation.State = StateFailed
+		operation.Error = errors.NewAPIError(errors.NewInternal(msgOperationAbandoned))
+	}
+
+	return &operation
+}
+
+// Reporter saves progress of a running operation.
+type Reporter struct {
+	manager *Manager
+	id      string
+	cancel  context.CancelFunc
+
+	mux     sync.Mutex
+	current Progress
+	saved   time.Time
+}
+
+// Report sets the number of processed items out of all items. Progress is saved at most once per second, so it
+// can be reported after every item.
+func (self *Reporter) Report(done, total int) {
+	self.mux.Lock()
+	self.current = Progress{Done: done, Total: total}
+	due := time.Since(self.saved) >= self.manager.progressInterval
+	if due {
+		self.saved = time.Now()
+	}
+	self.mux.Unlock()
+
+	if due {
+		self.save()
+	}
+}
+
+func (self *Reporter) progress() Progress {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	return self.current
+}
+
+func (self *Reporter) heartbeat(stop <-chan struct{}) {
+	ticker := time.NewTicker(self.manager.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			self.save()
+		}
+	}
+}
+
+// Saves current progress and cancels the operation if cancellation has been requested or the operation has been
+// removed in the meantime.
+func (self *Reporter) save() {
+	progress := self.progress()
+	cancelRequested := false
+	err := updateOperations(self.manager.client, func(operations map[string]*storedOperation) error {
+		operation, ok := operations[self.id]
+		if !ok {
+			return errors.NewNotFound(msgOperationNotFound)
+		}
+
+		operation.Progress = progress
+		operation.Updated = metaV1.Now()
+		cancelRequested = operation.CancelRequested
+		return nil
+	})
+
+	if cancelRequested || errors.IsNotFoundError(err) {
+		self.cancel()
+	} else if err != nil {
+		log.Printf("Could not save progress of operation %s: %s", self.id, err.Error())
+	}
+}
+
+// Sets the final state of given operation.
+func finish(operation *Operation, progress Progress, result interface{}, err error, cancelled bool) {
+	now := metaV1.Now()
+	operation.Progress = progress
+	operation.Updated = now
+	operation.Finished = &now
+
+	switch {
+	case cancelled:
+		operation.State = StateCancelled
+	case err != nil:
+		operation.State = StateFailed
+		operation.Error = errors.NewAPIError(err)
+	default:
+		operation.State = StateSucceeded
+	}
+
+	if result == nil {
+		return
+	}
+
+	raw, marshalErr := json.Marshal(result)
+	if marshalErr != nil {
+		operation.Message = marshalErr.Error()
+	} else if len(raw) > maxResultSize {
+		operation.Message = msgResultTooLarge
+	} else {
+		operation.Result = raw
+	}
+}
+
+// Removes finished operations older than their TTL and running ones that have not been saved for that long. If
+// there are still too many operations, the oldest finished ones are removed.
+func prune(operations map[string]*storedOperation, now time.Time) {
+	finished := make([]*storedOperation, 0)
+	for id, operation := range operations {
+		if now.Sub(operation.Updated.Time) > operationTTL {
+			delete(operations, id)
+		} else if operation.State != StateRunning {
+			finished = append(finished, operation)
+		}
+	}
+
+	sort.Slice(finished, func(i, j int) bool { return finished[i].Updated.Before(&finished[j].Updated) })
+	for i := 0; len(operations) >= maxOperations && i < len(finished); i++ {
+		delete(operations, finished[i].ID)
+	}
+}
+
+func newOperationID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// NewManager creates operation manager, that keeps operations in a secret using given client.
+func NewManager(client kubernetes.Interface) *Manager {
+	return &Manager{
+		client:            client,
+		progressInterval:  defaultProgressInterval,
+		heartbeatInterval: defaultHeartbeatInterval,
+		running:           make(map[string]context.CancelFunc),
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Waits until given operation finishes.
+func waitForOperation(t *testing.T, manager *Manager, owner, id string) *Operation {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		operation, err := manager.Get(owner, id)
+		if err != nil {
+			t.Fatalf("Get(): unexpected error: %v", err)
+		}
+
+		if operation.State != StateRunning {
+			return operation
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("operation %s has not finished", id)
+	return nil
+}
+
+func TestManager_Start(t *testing.T) {
+	manager := NewManager(fake.NewSimpleClientset())
+	started, err := manager.Start("alice", TypePodCleanup, func(ctx context.Context, reporter *Reporter) (
+		interface{}, error) {
+		reporter.Report(2, 2)
+		return map[string]int{"deleted": 2}, nil
+	})
+	if err != nil {
+		t.Fatalf("Start(): unexpected error: %v", err)
+	}
+
+	if started.State != StateRunning || started.Type != TypePodCleanup {
+		t.Errorf("Start(): expected running operation, got %+v", started)
+	}
+
+	operation := waitForOperation(t, manager, "alice", started.ID)
+	if operation.State != StateSucceeded || operation.Progress != (Progress{Done: 2, Total: 2}) ||
+		string(operation.Result) != `{"deleted":2}` || operation.Finished == nil {
+		t.Errorf("expected succeeded operation with result, got %+v", operation)
+	}
+
+	if _, err := manager.Get("bob", started.ID); !errors.IsNotFoundError(err) {
+		t.Errorf("Get(): expected operation of another owner not to be found, got %v", err)
+	}
+
+	list, err := manager.List("alice")
+	if err != nil || len(list.Items) != 1 || list.Items[0].ID != started.ID {
+		t.Errorf("List(): expected operation of the owner, got %+v, %v", list, err)
+	}
+}
+
+func TestManager_StartFailed(t *testing.T) {
+	manager := NewManager(fake.NewSimpleClientset())
+	started, err := manager.Start("alice", TypePodCleanup, func(ctx context.Context, reporter *Reporter) (
+		interface{}, error) {
+		return nil, errors.NewBadRequest("olderThan is required to delete pods")
+	})
+	if err != nil {
+		t.Fatalf("Start(): unexpected error: %v", err)
+	}
+
+	operation := waitForOperation(t, manager, "alice", started.ID)
+	if operation.State != StateFailed || operation.Error == nil || operation.Error.Code != errors.CodeBadRequest {
+		t.Errorf("expected failed operation with error, got %+v", operation)
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	// Operation is cancelled through another replica, that shares the secret.
+	manager, other := NewManager(client), NewManager(client)
+	manager.progressInterval = 0
+
+	running, proceed := make(chan struct{}), make(chan struct{})
+	started, err := manager.Start("alice", TypePodCleanup, func(ctx context.Context, reporter *Reporter) (
+		interface{}, error) {
+		close(running)
+		<-proceed
+		done := 0
+		for ctx.Err() == nil && done < 1000 {
+			done++
+			reporter.Report(done, 1000)
+		}
+		return map[string]int{"deleted": done}, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Start(): unexpected error: %v", err)
+	}
+
+	<-running
+	if _, err := other.Cancel("bob", started.ID); !errors.IsNotFoundError(err) {
+		t.Errorf("Cancel(): expected operation of another owner not to be found, got %v", err)
+	}
+
+	cancelled, err := other.Cancel("alice", started.ID)
+	if err != nil || !cancelled.CancelRequested {
+		t.Fatalf("Cancel(): expected cancellation to be requested, got %+v, %v", cancelled, err)
+	}
+	close(proceed)
+
+	operation := waitForOperation(t, other, "alice", started.ID)
+	result := make(map[string]int)
+	if err := json.Unmarshal(operation.Result, &result); err != nil {
+		t.Fatalf("expected partial result to be kept: %v", err)
+	}
+
+	if operation.State != StateCancelled || result["deleted"] != 1 || operation.Progress.Done != 1 {
+		t.Errorf("expected operation to be cancelled after the first item, got %+v", operation)
+	}
+}
+
+func TestManager_GetAbandoned(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	manager := NewManager(client)
+	updated := metaV1.NewTime(time.Now().Add(-time.Minute))
+	err := updateOperations(client, func(operations map[string]*storedOperation) error {
+		operations["a"] = &storedOperation{
+			Operation: Operation{ID: "a", State: StateRunning, Started: updated, Updated: updated},
+			Owner:     "alice",
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("updateOperations(): unexpected error: %v", err)
+	}
+
+	operation, err := manager.Get("alice", "a")
+	if err != nil || operation.State != StateFailed || operation.Error == nil {
+		t.Errorf("Get(): expected operation that is not saved anymore to fail, got %+v, %v", operation, err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	old := metaV1.NewTime(now.Add(-2 * operationTTL))
+	operations := map[string]*storedOperation{
+		"finished": {Operation: Operation{ID: "finished", State: StateSucceeded, Updated: old}},
+		"running":  {Operation: Operation{ID: "running", State: StateRunning, Updated: metaV1.NewTime(now)}},
+	}
+	for i := 0; i < maxOperations; i++ {
+		id := string(rune('a' + i))
+		updated := metaV1.NewTime(now.Add(time.Duration(i) * time.Second))
+		operations[id] = &storedOperation{Operation: Operation{ID: id, State: StateSucceeded, Updated: updated}}
+	}
+
+	prune(operations, now)
+	if _, ok := operations["finished"]; ok {
+		t.Error("expected operation older than its TTL to be removed")
+	}
+
+	if _, ok := operations["running"]; !ok {
+		t.Error("expected running operation to be kept")
+	}
+
+	if _, ok := operations["a"]; ok || len(operations) >= maxOperations {
+		t.Errorf("expected the oldest finished operations to be removed, %d are kept", len(operations))
+	}
+}
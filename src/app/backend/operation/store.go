@@ -0,0 +1,116 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation
+
+import (
+	"context"
+	"encoding/json"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Number of attempts to update the secret when it is changed concurrently by another operation or replica.
+const operationRetries = 5
+
+// Operation as it is saved in the secret, together with the owner, who is allowed to see it.
+type storedOperation struct {
+	Operation
+	Owner string `json:"owner"`
+}
+
+// Returns all operations, keyed by their IDs.
+func getOperations(client kubernetes.Interface) (map[string]*storedOperation, error) {
+	secret, err := client.CoreV1().Secrets(args.Holder.GetNamespace()).Get(context.TODO(),
+		authApi.OperationsHolderName, metaV1.GetOptions{})
+	if errors.IsNotFoundError(err) {
+		return map[string]*storedOperation{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalOperations(secret.Data)
+}
+
+// Applies given change to operations, creating the secret if it does not exist yet. Change is retried if the secret
+// has been modified or created concurrently. Errors returned by the change are not retried.
+func updateOperations(client kubernetes.Interface, change func(map[string]*storedOperation) error) error {
+	secrets := client.CoreV1().Secrets(args.Holder.GetNamespace())
+	var err error
+	for i := 0; i < operationRetries; i++ {
+		secret, getErr := secrets.Get(context.TODO(), authApi.OperationsHolderName, metaV1.GetOptions{})
+		exists := getErr == nil
+		if !exists && !errors.IsNotFoundError(getErr) {
+			return getErr
+		}
+
+		if !exists {
+			secret = &v1.Secret{
+				ObjectMeta: metaV1.ObjectMeta{
+					Namespace: args.Holder.GetNamespace(),
+					Name:      authApi.OperationsHolderName,
+				},
+			}
+		}
+
+		operations, unmarshalErr := unmarshalOperations(secret.Data)
+		if unmarshalErr != nil {
+			return unmarshalErr
+		}
+
+		if err = change(operations); err != nil {
+			return err
+		}
+
+		secret.Data = make(map[string][]byte, len(operations))
+		for id, operation := range operations {
+			if secret.Data[id], err = json.Marshal(operation); err != nil {
+				return err
+			}
+		}
+
+		if exists {
+			_, err = secrets.Update(context.TODO(), secret, metaV1.UpdateOptions{})
+		} else {
+			_, err = secrets.Create(context.TODO(), secret, metaV1.CreateOptions{})
+		}
+
+		if !errors.IsConflict(err) && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func unmarshalOperations(data map[string][]byte) (map[string]*storedOperation, error) {
+	operations := make(map[string]*storedOperation, len(data))
+	for id, value := range data {
+		operation := new(storedOperation)
+		if err := json.Unmarshal(value, operation); err != nil {
+			return nil, err
+		}
+		operations[id] = operation
+	}
+
+	return operations, nil
+}
@@ -25,12 +25,14 @@ import (
 	"github.com/emicklei/go-restful"
 	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
 	"github.com/kubernetes/dashboard/src/app/backend/plugin/client/clientset/versioned"
 	fakePluginClientset "github.com/kubernetes/dashboard/src/app/backend/plugin/client/clientset/versioned/fake"
 	v1 "k8s.io/api/authorization/v1"
 	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	fakeK8sClient "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
@@ -103,6 +105,10 @@ func (cm *fakeClientManager) InsecurePluginClient() versioned.Interface {
 	return cm.pluginClient
 }
 
+func (cm *fakeClientManager) InsecureDynamicClient() dynamic.Interface {
+	panic("implement me")
+}
+
 func (cm *fakeClientManager) CanI(req *restful.Request, ssar *v1.SelfSubjectAccessReview) bool {
 	panic("implement me")
 }
@@ -130,3 +136,19 @@ func (cm *fakeClientManager) VerberClient(req *restful.Request, config *rest.Con
 func (cm *fakeClientManager) SetTokenManager(manager authApi.TokenManager) {
 	panic("implement me")
 }
+
+func (cm *fakeClientManager) SetFeatureGateManager(manager featuresApi.FeatureGateManager) {
+	panic("implement me")
+}
+
+func (cm *fakeClientManager) SetAuditLogger(logger authApi.AuditLogger) {
+	panic("implement me")
+}
+
+func (cm *fakeClientManager) SkipLoginIdentity() string {
+	panic("implement me")
+}
+
+func (cm *fakeClientManager) HeaderAuthInfo(req *restful.Request) *api.AuthInfo {
+	panic("implement me")
+}
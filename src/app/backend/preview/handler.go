@@ -0,0 +1,86 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preview
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// PreviewHandler manages all endpoints related to impersonated previews.
+type PreviewHandler struct {
+	cManager clientapi.ClientManager
+}
+
+// Install creates new endpoints for impersonated previews.
+func (self *PreviewHandler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/preview").
+			To(self.handleGetPreview).
+			Writes(Preview{}))
+}
+
+// NewPreviewHandler creates PreviewHandler.
+func NewPreviewHandler(cManager clientapi.ClientManager) *PreviewHandler {
+	return &PreviewHandler{cManager: cManager}
+}
+
+// handleGetPreview renders key list pages as seen by the subject given by 'user' and comma separated 'groups'
+// parameters in the 'namespace'. Only cluster administrators can use it and the apiserver additionally checks
+// that they are allowed to impersonate the subject, so no credentials of the subject are needed.
+func (self *PreviewHandler) handleGetPreview(request *restful.Request, response *restful.Response) {
+	if !self.cManager.CanI(request, clientapi.ToClusterAdminAccessReview()) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			"only cluster administrators can preview Dashboard as other users"))
+		return
+	}
+
+	user := request.QueryParameter("user")
+	groups := make([]string, 0)
+	for _, group := range strings.Split(request.QueryParameter("groups"), ",") {
+		if group = strings.TrimSpace(group); len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	if len(user) == 0 {
+		errors.HandleInternalError(response, errors.NewBadRequest("user to impersonate is required"))
+		return
+	}
+
+	cfg, err := self.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	impersonated := rest.CopyConfig(cfg)
+	impersonated.Impersonate = rest.ImpersonationConfig{UserName: user, Groups: groups}
+	client, err := kubernetes.NewForConfig(impersonated)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result := GetPreview(client, user, groups, request.QueryParameter("namespace"))
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
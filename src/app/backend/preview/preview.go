@@ -0,0 +1,198 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preview
+
+import (
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/configmap"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/namespace"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/node"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/secret"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/service"
+)
+
+// Preview shows what the subject sees on the key list pages of Dashboard.
+type Preview struct {
+	// User that was impersonated.
+	User string `json:"user"`
+
+	// Groups that were impersonated.
+	Groups []string `json:"groups"`
+
+	// Namespace of the namespaced lists. Empty means all namespaces.
+	Namespace string `json:"namespace"`
+
+	Lists []ListPreview `json:"lists"`
+}
+
+// ListPreview is a single list page as seen by the subject.
+type ListPreview struct {
+	Kind api.ResourceKind `json:"kind"`
+
+	// Total number of items the subject can see.
+	TotalItems int `json:"totalItems"`
+
+	// Names of the items on the first page of the list.
+	Items []string `json:"items"`
+
+	// Errors shown on the list page, i.e. because the subject is not allowed to list the resource.
+	Errors []error `json:"errors"`
+}
+
+// listFn lists a single resource kind and returns the list page as seen by the client.
+type listFn func(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ListPreview, error)
+
+// previewedLists are the key list pages rendered by GetPreview, in order.
+var previewedLists = []struct {
+	kind   api.ResourceKind
+	listFn listFn
+}{
+	{api.ResourceKindNamespace, listNamespaces},
+	{api.ResourceKindNode, listNodes},
+	{api.ResourceKindPod, listPods},
+	{api.ResourceKindDeployment, listDeployments},
+	{api.ResourceKindService, listServices},
+	{api.ResourceKindConfigMap, listConfigMaps},
+	{api.ResourceKindSecret, listSecrets},
+}
+
+// GetPreview renders key list pages using given client, that should impersonate the subject.
+func GetPreview(client kubernetes.Interface, user string, groups []string, ns string) *Preview {
+	nsQuery := common.NewNamespaceQuery([]string{ns})
+	preview := &Preview{User: user, Groups: groups, Namespace: ns, Lists: make([]ListPreview, 0)}
+
+	for _, previewed := range previewedLists {
+		list, err := previewed.listFn(client, nsQuery, dataselect.DefaultDataSelect)
+		if err != nil {
+			list = &ListPreview{Errors: []error{err}}
+		}
+
+		list.Kind = previewed.kind
+		if list.Items == nil {
+			list.Items = make([]string, 0)
+		}
+		if list.Errors == nil {
+			list.Errors = make([]error, 0)
+		}
+		preview.Lists = append(preview.Lists, *list)
+	}
+
+	return preview
+}
+
+func listNamespaces(client kubernetes.Interface, _ *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ListPreview, error) {
+	list, err := namespace.GetNamespaceList(client, dsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ListPreview{TotalItems: list.ListMeta.TotalItems, Errors: list.Errors}
+	for _, item := range list.Namespaces {
+		preview.Items = append(preview.Items, item.ObjectMeta.Name)
+	}
+	return preview, nil
+}
+
+func listNodes(client kubernetes.Interface, _ *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ListPreview, error) {
+	list, err := node.GetNodeList(client, dsQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ListPreview{TotalItems: list.ListMeta.TotalItems, Errors: list.Errors}
+	for _, item := range list.Nodes {
+		preview.Items = append(preview.Items, item.ObjectMeta.Name)
+	}
+	return preview, nil
+}
+
+func listPods(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ListPreview, error) {
+	list, err := pod.GetPodList(client, nil, nsQuery, dsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ListPreview{TotalItems: list.ListMeta.TotalItems, Errors: list.Errors}
+	for _, item := range list.Pods {
+		preview.Items = append(preview.Items, item.ObjectMeta.Name)
+	}
+	return preview, nil
+}
+
+func listDeployments(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ListPreview, error) {
+	list, err := deployment.GetDeploymentList(client, nsQuery, dsQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ListPreview{TotalItems: list.ListMeta.TotalItems, Errors: list.Errors}
+	for _, item := range list.Deployments {
+		preview.Items = append(preview.Items, item.ObjectMeta.Name)
+	}
+	return preview, nil
+}
+
+func listServices(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ListPreview, error) {
+	list, err := service.GetServiceList(client, nsQuery, dsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ListPreview{TotalItems: list.ListMeta.TotalItems, Errors: list.Errors}
+	for _, item := range list.Services {
+		preview.Items = append(preview.Items, item.ObjectMeta.Name)
+	}
+	return preview, nil
+}
+
+func listConfigMaps(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ListPreview, error) {
+	list, err := configmap.GetConfigMapList(client, nsQuery, dsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ListPreview{TotalItems: list.ListMeta.TotalItems, Errors: list.Errors}
+	for _, item := range list.Items {
+		preview.Items = append(preview.Items, item.ObjectMeta.Name)
+	}
+	return preview, nil
+}
+
+func listSecrets(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ListPreview, error) {
+	list, err := secret.GetSecretList(client, nsQuery, dsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ListPreview{TotalItems: list.ListMeta.TotalItems, Errors: list.Errors}
+	for _, item := range list.Secrets {
+		preview.Items = append(preview.Items, item.ObjectMeta.Name)
+	}
+	return preview, nil
+}
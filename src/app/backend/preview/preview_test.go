@@ -0,0 +1,59 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preview
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+func TestGetPreview(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "team-a"}},
+		&v1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "team-a"}},
+		&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "db", Namespace: "team-a"}},
+	)
+	// Typed clients return an empty list together with the error, unlike the fake when the reactor returns nil.
+	client.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &v1.SecretList{}, errors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", nil)
+	})
+
+	preview := GetPreview(client, "alice", []string{"team-a"}, "team-a")
+	if preview.User != "alice" || preview.Namespace != "team-a" {
+		t.Errorf("GetPreview(): unexpected subject %s in %s", preview.User, preview.Namespace)
+	}
+
+	lists := make(map[api.ResourceKind]ListPreview)
+	for _, list := range preview.Lists {
+		lists[list.Kind] = list
+	}
+
+	if pods := lists[api.ResourceKindPod]; pods.TotalItems != 1 || len(pods.Items) != 1 || pods.Items[0] != "web" {
+		t.Errorf("GetPreview(): expected pod web to be visible, got %+v", pods)
+	}
+
+	if secrets := lists[api.ResourceKindSecret]; secrets.TotalItems != 0 || len(secrets.Errors) != 1 {
+		t.Errorf("GetPreview(): expected secrets to be forbidden, got %+v", secrets)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publicstatus
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// cacheTTL is the time for which the status is cached, so anonymous requests can not overload the apiserver.
+const cacheTTL = 10 * time.Second
+
+// Handler manages endpoints of the public status page. Endpoints do not require authentication, so they are
+// enabled only when 'public-status-namespaces' argument is set and serve only namespaces listed there.
+type Handler struct {
+	cManager clientapi.ClientManager
+
+	mux      sync.Mutex
+	cached   *PublicStatus
+	cachedAt time.Time
+}
+
+// Install creates new endpoints of the public status page.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/publicstatus").
+			To(h.handleGetPublicStatus).
+			Writes(PublicStatus{}))
+	ws.Route(
+		ws.GET("/publicstatus/{namespace}").
+			To(h.handleGetNamespaceStatus).
+			Writes(NamespaceStatus{}))
+}
+
+// NewPublicStatusHandler creates publicstatus.Handler.
+func NewPublicStatusHandler(cManager clientapi.ClientManager) *Handler {
+	return &Handler{cManager: cManager}
+}
+
+func (h *Handler) handleGetPublicStatus(request *restful.Request, response *restful.Response) {
+	status, err := h.getStatus()
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, status)
+}
+
+func (h *Handler) handleGetNamespaceStatus(request *restful.Request, response *restful.Response) {
+	status, err := h.getStatus()
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	for _, namespaceStatus := range status.Namespaces {
+		if namespaceStatus.Name == namespace {
+			response.WriteHeaderAndEntity(http.StatusOK, namespaceStatus)
+			return
+		}
+	}
+
+	errors.HandleInternalError(response, errors.NewNotFound("namespace is not on the public status page"))
+}
+
+// getStatus returns the status of namespaces listed in 'public-status-namespaces' argument. Status is read with
+// Dashboard privileges and cached for cacheTTL. Errors are only logged, as they could reveal cluster details to
+// anonymous users.
+func (h *Handler) getStatus() (*PublicStatus, error) {
+	namespaces := args.Holder.GetPublicStatusNamespaces()
+	if len(namespaces) == 0 {
+		return nil, errors.NewNotFound("public status page is disabled")
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if h.cached != nil && time.Since(h.cachedAt) < cacheTTL {
+		return h.cached, nil
+	}
+
+	status, err := GetPublicStatus(h.cManager.InsecureClient(), namespaces)
+	if err != nil {
+		log.Printf("Cannot get public status: %s", err.Error())
+		return nil, errors.NewGenericResponse(http.StatusServiceUnavailable, "status is not available")
+	}
+
+	h.cached, h.cachedAt = status, time.Now()
+	return status, nil
+}
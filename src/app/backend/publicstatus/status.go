@@ -0,0 +1,158 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publicstatus
+
+import (
+	"context"
+	"sort"
+
+	apps "k8s.io/api/apps/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Status is a health of a workload or a rollup health of a namespace.
+type Status string
+
+const (
+	// StatusOperational means that all replicas are available.
+	StatusOperational Status = "Operational"
+	// StatusDegraded means that some, but not all replicas are available.
+	StatusDegraded Status = "Degraded"
+	// StatusDown means that none of desired replicas is available.
+	StatusDown Status = "Down"
+)
+
+// WorkloadStatus is a health of a single workload. It contains only replica counts, so no details of pods,
+// containers or their configuration are exposed.
+type WorkloadStatus struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Desired int32  `json:"desired"`
+	Ready   int32  `json:"ready"`
+	Status  Status `json:"status"`
+}
+
+// NamespaceStatus is a health of the workloads in a namespace.
+type NamespaceStatus struct {
+	Name      string           `json:"name"`
+	Status    Status           `json:"status"`
+	Workloads []WorkloadStatus `json:"workloads"`
+}
+
+// PublicStatus is a health of the workloads in namespaces exposed on the public status page.
+type PublicStatus struct {
+	Status     Status            `json:"status"`
+	Namespaces []NamespaceStatus `json:"namespaces"`
+}
+
+// GetPublicStatus returns health of the deployments, stateful sets and daemon sets in given namespaces. Client
+// should be the dashboard's insecure client, as the status is served to anonymous users.
+func GetPublicStatus(client kubernetes.Interface, namespaces []string) (*PublicStatus, error) {
+	result := &PublicStatus{Status: StatusOperational, Namespaces: make([]NamespaceStatus, 0, len(namespaces))}
+	for _, namespace := range namespaces {
+		namespaceStatus, err := getNamespaceStatus(client, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Namespaces = append(result.Namespaces, *namespaceStatus)
+		result.Status = worse(result.Status, namespaceStatus.Status)
+	}
+
+	return result, nil
+}
+
+func getNamespaceStatus(client kubernetes.Interface, namespace string) (*NamespaceStatus, error) {
+	deployments, err := client.AppsV1().Deployments(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	daemonSets, err := client.AppsV1().DaemonSets(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := make([]WorkloadStatus, 0)
+	for _, deployment := range deployments.Items {
+		workloads = append(workloads, newWorkloadStatus("Deployment", deployment.Name,
+			getDesired(deployment.Spec.Replicas), deployment.Status.AvailableReplicas))
+	}
+	for _, statefulSet := range statefulSets.Items {
+		workloads = append(workloads, newWorkloadStatus("StatefulSet", statefulSet.Name,
+			getDesired(statefulSet.Spec.Replicas), statefulSet.Status.ReadyReplicas))
+	}
+	for _, daemonSet := range daemonSets.Items {
+		workloads = append(workloads, getDaemonSetStatus(daemonSet))
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Kind != workloads[j].Kind {
+			return workloads[i].Kind < workloads[j].Kind
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+
+	result := &NamespaceStatus{Name: namespace, Status: StatusOperational, Workloads: workloads}
+	for _, workload := range workloads {
+		result.Status = worse(result.Status, workload.Status)
+	}
+
+	return result, nil
+}
+
+func getDaemonSetStatus(daemonSet apps.DaemonSet) WorkloadStatus {
+	return newWorkloadStatus("DaemonSet", daemonSet.Name, daemonSet.Status.DesiredNumberScheduled,
+		daemonSet.Status.NumberAvailable)
+}
+
+func newWorkloadStatus(kind, name string, desired, ready int32) WorkloadStatus {
+	return WorkloadStatus{Kind: kind, Name: name, Desired: desired, Ready: ready, Status: getStatus(desired, ready)}
+}
+
+// getDesired returns number of desired replicas, which defaults to 1 if not set.
+func getDesired(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func getStatus(desired, ready int32) Status {
+	if ready >= desired {
+		return StatusOperational
+	}
+	if ready == 0 {
+		return StatusDown
+	}
+	return StatusDegraded
+}
+
+// worse returns the worse of two statuses.
+func worse(a, b Status) Status {
+	if a == StatusDown || b == StatusDown {
+		return StatusDown
+	}
+	if a == StatusDegraded || b == StatusDegraded {
+		return StatusDegraded
+	}
+	return StatusOperational
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publicstatus
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestGetPublicStatus(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&apps.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       apps.DeploymentSpec{Replicas: int32Ptr(3)},
+			Status:     apps.DeploymentStatus{AvailableReplicas: 3},
+		},
+		&apps.StatefulSet{
+			ObjectMeta: metaV1.ObjectMeta{Name: "db", Namespace: "shop"},
+			Spec:       apps.StatefulSetSpec{Replicas: int32Ptr(2)},
+			Status:     apps.StatefulSetStatus{ReadyReplicas: 1},
+		},
+		&apps.DaemonSet{
+			ObjectMeta: metaV1.ObjectMeta{Name: "agent", Namespace: "monitoring"},
+			Status:     apps.DaemonSetStatus{DesiredNumberScheduled: 2, NumberAvailable: 2},
+		},
+		&apps.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "internal", Namespace: "private"},
+			Status:     apps.DeploymentStatus{AvailableReplicas: 0},
+		},
+	)
+
+	expected := &PublicStatus{
+		Status: StatusDegraded,
+		Namespaces: []NamespaceStatus{
+			{
+				Name:   "shop",
+				Status: StatusDegraded,
+				Workloads: []WorkloadStatus{
+					{Kind: "Deployment", Name: "web", Desired: 3, Ready: 3, Status: StatusOperational},
+					{Kind: "StatefulSet", Name: "db", Desired: 2, Ready: 1, Status: StatusDegraded},
+				},
+			},
+			{
+				Name:   "monitoring",
+				Status: StatusOperational,
+				Workloads: []WorkloadStatus{
+					{Kind: "DaemonSet", Name: "agent", Desired: 2, Ready: 2, Status: StatusOperational},
+				},
+			},
+		},
+	}
+
+	actual, err := GetPublicStatus(client, []string{"shop", "monitoring"})
+	if err != nil {
+		t.Fatalf("GetPublicStatus(): unexpected error %v", err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetPublicStatus() == %#v, expected %#v", actual, expected)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	cases := []struct {
+		desired, ready int32
+		expected       Status
+	}{
+		{1, 1, StatusOperational},
+		{0, 0, StatusOperational},
+		{3, 2, StatusDegraded},
+		{3, 0, StatusDown},
+	}
+
+	for _, c := range cases {
+		actual := getStatus(c.desired, c.ready)
+		if actual != c.expected {
+			t.Errorf("getStatus(%d, %d) == %s, expected %s", c.desired, c.ready, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quickaction
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// Handler manages all endpoints related to admin-defined quick actions.
+type Handler struct {
+	cManager clientapi.ClientManager
+	sManager settingsApi.SettingsManager
+}
+
+// Install creates new endpoints for quick actions. Actions are defined by admins in the settings config map
+// and executed by the backend with credentials of the user that triggered them.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/quickaction/{kind}").
+			To(h.handleGetQuickActions).
+			Writes(QuickActionList{}))
+	ws.Route(
+		ws.POST("/quickaction/{action}/{kind}/{name}").
+			To(h.handleExecuteQuickAction).
+			Reads(QuickActionSpec{}).
+			Writes(QuickActionResult{}))
+	ws.Route(
+		ws.POST("/quickaction/{action}/{kind}/{namespace}/{name}").
+			To(h.handleExecuteQuickAction).
+			Reads(QuickActionSpec{}).
+			Writes(QuickActionResult{}))
+}
+
+// NewQuickActionHandler creates quickaction.Handler.
+func NewQuickActionHandler(cManager clientapi.ClientManager, sManager settingsApi.SettingsManager) *Handler {
+	return &Handler{cManager: cManager, sManager: sManager}
+}
+
+func (h *Handler) handleGetQuickActions(request *restful.Request, response *restful.Response) {
+	actions := h.sManager.GetQuickActions(h.cManager.InsecureClient())
+	result := GetQuickActionList(actions, request.PathParameter("kind"))
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (h *Handler) handleExecuteQuickAction(request *restful.Request, response *restful.Response) {
+	spec := new(QuickActionSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace, namespaceSet := request.PathParameters()["namespace"]
+	target := Target{
+		Kind:         request.PathParameter("kind"),
+		Namespace:    namespace,
+		Name:         request.PathParameter("name"),
+		NamespaceSet: namespaceSet,
+	}
+
+	actions := h.sManager.GetQuickActions(h.cManager.InsecureClient())
+	action, err := FindQuickAction(actions, request.PathParameter("action"), target.Kind)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	cfg, err := h.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	verber, err := h.cManager.VerberClient(request, cfg)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	identity := clientapi.GetIdentity(cfg)
+	result, err := Execute(verber, cfg, action, target, spec)
+	if err != nil {
+		log.Printf("Quick action %s on %s %s/%s by %s failed: %s", action.Name, target.Kind, target.Namespace,
+			target.Name, identity, err.Error())
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	log.Printf("Quick action %s on %s %s/%s by %s succeeded: %s", action.Name, target.Kind, target.Namespace,
+		target.Name, identity, result.Message)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
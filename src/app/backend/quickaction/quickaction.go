@@ -0,0 +1,246 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quickaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/scaling"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// webhookTimeout is the maximum time that a webhook call can take.
+const webhookTimeout = 10 * time.Second
+
+// QuickActionList contains quick actions available for a resource kind.
+type QuickActionList struct {
+	Actions []settingsApi.QuickAction `json:"actions"`
+}
+
+// QuickActionSpec is a specification of a single quick action execution.
+type QuickActionSpec struct {
+	// Parameters provided by the user. They are referenced by the action definition.
+	Parameters map[string]string `json:"parameters"`
+}
+
+// Target identifies the resource that quick action is executed on.
+type Target struct {
+	Kind         string `json:"kind"`
+	Namespace    string `json:"namespace,omitempty"`
+	Name         string `json:"name"`
+	NamespaceSet bool   `json:"-"`
+}
+
+// QuickActionResult describes the outcome of quick action execution.
+type QuickActionResult struct {
+	Action string `json:"action"`
+	Target Target `json:"target"`
+	Type   string `json:"type"`
+	// Message is a short human readable description of what has been done.
+	Message string `json:"message"`
+}
+
+// webhookPayload is sent to the webhook URL of webhook quick actions.
+type webhookPayload struct {
+	Action     string            `json:"action"`
+	Target     Target            `json:"target"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// GetQuickActionList returns quick actions that can be executed on resources of given kind.
+func GetQuickActionList(actions []settingsApi.QuickAction, kind string) *QuickActionList {
+	result := &QuickActionList{Actions: make([]settingsApi.QuickAction, 0)}
+	for _, action := range actions {
+		if action.MatchesKind(kind) {
+			result.Actions = append(result.Actions, action)
+		}
+	}
+
+	return result
+}
+
+// FindQuickAction returns quick action with given name that can be executed on resources of given kind.
+func FindQuickAction(actions []settingsApi.QuickAction, name, kind string) (*settingsApi.QuickAction, error) {
+	for i := range actions {
+		if actions[i].Name == name && actions[i].MatchesKind(kind) {
+			return &actions[i], nil
+		}
+	}
+
+	return nil, errors.NewNotFound(fmt.Sprintf("quick action %s is not available for kind %s", name, kind))
+}
+
+// Execute runs quick action on the target resource. Verber and config have to carry credentials of the user
+// that executes the action, so the action can not do anything that the user would not be allowed to do.
+// Target is read with user credentials first, as webhooks are called by the backend itself and would
+// otherwise let anyone trigger them for any resource.
+func Execute(verber clientapi.ResourceVerber, cfg *rest.Config, action *settingsApi.QuickAction, target Target,
+	spec *QuickActionSpec) (*QuickActionResult, error) {
+	params, err := resolveParameters(action, target, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := verber.Get(target.Kind, target.NamespaceSet, target.Namespace, target.Name); err != nil {
+		return nil, err
+	}
+
+	expand := func(s string) string {
+		return os.Expand(s, func(key string) string { return params[key] })
+	}
+
+	result := &QuickActionResult{Action: action.Name, Target: target, Type: string(action.Type)}
+	switch action.Type {
+	case settingsApi.QuickActionAnnotate, settingsApi.QuickActionLabel:
+		key, value := expand(action.Key), expand(action.Value)
+		if err := patchMetadata(verber, action.Type, target, key, value); err != nil {
+			return nil, err
+		}
+		result.Message = fmt.Sprintf("%s %s set to %s", action.Type, key, value)
+	case settingsApi.QuickActionScale:
+		replicas := expand(action.Value)
+		if _, err := scaling.ScaleResource(cfg, target.Kind, target.Namespace, target.Name, replicas); err != nil {
+			return nil, err
+		}
+		result.Message = fmt.Sprintf("scaled to %s replicas", replicas)
+	case settingsApi.QuickActionWebhook:
+		webhookURL, err := expandURL(action.URL, params)
+		if err != nil {
+			return nil, err
+		}
+		payload := webhookPayload{Action: action.Name, Target: target, Parameters: params}
+		if err := callWebhook(webhookURL, payload); err != nil {
+			return nil, err
+		}
+		result.Message = fmt.Sprintf("webhook %s called", webhookURL)
+	default:
+		return nil, errors.NewInvalid(fmt.Sprintf("unknown quick action type: %s", action.Type))
+	}
+
+	return result, nil
+}
+
+// resolveParameters merges user provided parameters with the ones describing target resource and checks
+// that all parameters required by the action are provided.
+func resolveParameters(action *settingsApi.QuickAction, target Target, spec *QuickActionSpec) (
+	map[string]string, error) {
+	params := make(map[string]string)
+	if spec != nil {
+		for _, name := range action.Parameters {
+			if value, ok := spec.Parameters[name]; ok {
+				params[name] = value
+			}
+		}
+	}
+
+	for _, name := range action.Parameters {
+		if len(params[name]) == 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("missing quick action parameter: %s", name))
+		}
+	}
+
+	params["kind"] = target.Kind
+	params["namespace"] = target.Namespace
+	params["name"] = target.Name
+	return params, nil
+}
+
+// expandURL replaces parameter references in the webhook URL with escaped parameter values, so that user
+// provided values can neither leave the path segment or query value they are placed in, nor change the host
+// the webhook is sent to.
+func expandURL(rawURL string, params map[string]string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || len(parsed.Host) == 0 {
+		return "", errors.NewInvalid(fmt.Sprintf("invalid quick action webhook URL: %s", rawURL))
+	}
+	if strings.Contains(parsed.Scheme+parsed.Host, "$") {
+		return "", errors.NewInvalid("quick action webhook URL can not reference parameters in its host")
+	}
+
+	path, query := rawURL, ""
+	if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+		path, query = rawURL[:i], rawURL[i:]
+	}
+
+	expand := func(s string, escape func(string) string) string {
+		return os.Expand(s, func(key string) string { return escape(params[key]) })
+	}
+	return expand(path, url.PathEscape) + expand(query, url.QueryEscape), nil
+}
+
+func patchMetadata(verber clientapi.ResourceVerber, actionType settingsApi.QuickActionType, target Target,
+	key, value string) error {
+	if len(key) == 0 {
+		return errors.NewInvalid("quick action key can not be empty")
+	}
+
+	field := "annotations"
+	if actionType == settingsApi.QuickActionLabel {
+		field = "labels"
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			field: map[string]string{key: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return verber.Patch(target.Kind, target.NamespaceSet, target.Namespace, target.Name, types.MergePatchType, patch)
+}
+
+func callWebhook(webhookURL string, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.NewInvalid(fmt.Sprintf("invalid quick action webhook URL: %s", err.Error()))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.NewGenericResponse(http.StatusBadGateway,
+			fmt.Sprintf("quick action webhook returned %d status code", resp.StatusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quickaction
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// fakeVerber allows to get only the resource with given name.
+type fakeVerber struct {
+	name string
+}
+
+func (v fakeVerber) Put(string, bool, string, string, *runtime.Unknown) error { return nil }
+
+func (v fakeVerber) Delete(string, bool, string, string) error { return nil }
+
+func (v fakeVerber) Patch(string, bool, string, string, types.PatchType, []byte) error { return nil }
+
+func (v fakeVerber) Apply(string, bool, string, string, []byte, string, bool) error { return nil }
+
+func (v fakeVerber) Get(kind string, namespaceSet bool, namespace string, name string) (runtime.Object, error) {
+	if name != v.name {
+		return nil, errors.NewNotFound("not found")
+	}
+	return &runtime.Unknown{}, nil
+}
+
+func TestHandler_Install(t *testing.T) {
+	h := NewQuickActionHandler(nil, nil)
+	ws := new(restful.WebService)
+	h.Install(ws)
+
+	if len(ws.Routes()) == 0 {
+		t.Error("Failed to install routes.")
+	}
+}
+
+func TestGetQuickActionList(t *testing.T) {
+	actions := []settingsApi.QuickAction{
+		{Name: "restart", Kinds: []string{"deployment", "statefulset"}},
+		{Name: "notify", Kinds: []string{"*"}},
+		{Name: "cordon", Kinds: []string{"node"}},
+	}
+
+	cases := []struct {
+		kind     string
+		expected []string
+	}{
+		{"deployment", []string{"restart", "notify"}},
+		{"Node", []string{"notify", "cordon"}},
+		{"pod", []string{"notify"}},
+	}
+
+	for _, c := range cases {
+		actual := make([]string, 0)
+		for _, a := range GetQuickActionList(actions, c.kind).Actions {
+			actual = append(actual, a.Name)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Expected actions %v, but got %v", c.kind, c.expected, actual)
+		}
+	}
+}
+
+func TestResolveParameters(t *testing.T) {
+	action := &settingsApi.QuickAction{Name: "owner", Parameters: []string{"team"}}
+	target := Target{Kind: "deployment", Namespace: "default", Name: "app"}
+
+	if _, err := resolveParameters(action, target, &QuickActionSpec{}); !k8serrors.IsBadRequest(err) {
+		t.Errorf("Expected bad request error for missing parameter, but got %v", err)
+	}
+
+	params, err := resolveParameters(action, target, &QuickActionSpec{Parameters: map[string]string{
+		"team": "payments",
+		"name": "overridden",
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{"team": "payments", "kind": "deployment", "namespace": "default", "name": "app"}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Expected parameters %v, but got %v", expected, params)
+	}
+}
+
+func TestExecuteWebhook(t *testing.T) {
+	received := webhookPayload{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Cannot decode webhook payload: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	action := &settingsApi.QuickAction{
+		Name: "page",
+		Type: settingsApi.QuickActionWebhook,
+		URL:  server.URL + "/${namespace}/${name}",
+	}
+	target := Target{Kind: "pod", Namespace: "default", Name: "web-0"}
+
+	if _, err := Execute(fakeVerber{name: "other"}, nil, action, target, &QuickActionSpec{}); err == nil {
+		t.Error("Expected webhook not to be called for inaccessible target")
+	}
+
+	result, err := Execute(fakeVerber{name: "web-0"}, nil, action, target, &QuickActionSpec{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Message != "webhook "+server.URL+"/default/web-0 called" {
+		t.Errorf("Unexpected result message: %s", result.Message)
+	}
+
+	if !reflect.DeepEqual(received.Target, target) {
+		t.Errorf("Expected webhook to receive target %v, but got %v", target, received.Target)
+	}
+}
+
+func TestExpandURL(t *testing.T) {
+	params := map[string]string{"name": "../admin?x=1", "namespace": "default", "host": "evil.example.com"}
+	cases := []struct {
+		url      string
+		expected string
+		err      bool
+	}{
+		{"https://hooks.example.com/${namespace}/${name}?pod=${name}",
+			"https://hooks.example.com/default/..%2Fadmin%3Fx=1?pod=..%2Fadmin%3Fx%3D1", false},
+		{"https://${host}/notify", "", true},
+		{"/relative/${name}", "", true},
+	}
+
+	for _, c := range cases {
+		actual, err := expandURL(c.url, params)
+		if (err != nil) != c.err {
+			t.Errorf("Test Case: %s. Expected error: %t, but got %v", c.url, c.err, err)
+		}
+		if actual != c.expected {
+			t.Errorf("Test Case: %s. Expected %s, but got %s", c.url, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activity
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// Range is the period covered by the heatmap.
+type Range string
+
+const (
+	RangeDay  Range = "day"
+	RangeWeek Range = "week"
+
+	// BucketSize is the period aggregated into a single heatmap cell.
+	BucketSize = time.Hour
+)
+
+// Duration returns length of the range.
+func (self Range) Duration() time.Duration {
+	if self == RangeWeek {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// ParseRange parses name of the range. Empty name means RangeDay.
+func ParseRange(name string) (Range, error) {
+	switch Range(name) {
+	case "", RangeDay:
+		return RangeDay, nil
+	case RangeWeek:
+		return RangeWeek, nil
+	}
+
+	return "", errors.NewBadRequest(fmt.Sprintf("unknown range %q, should be one of day, week", name))
+}
+
+// NamespaceActivity is a single heatmap row. Counts are aligned with buckets of the heatmap.
+type NamespaceActivity struct {
+	Namespace string `json:"namespace"`
+
+	// Number of events reported in the namespace. Repeated events are counted as many times as they occurred.
+	Events []int `json:"events"`
+
+	// Number of new deployment revisions, i.e. replica sets created by rollouts.
+	DeploymentChanges []int `json:"deploymentChanges"`
+
+	// Number of pods with a container that was restarted. Only the last restart of every container is known.
+	PodRestarts []int `json:"podRestarts"`
+
+	// Sum of all counts, used to order namespaces from the busiest one.
+	Total int `json:"total"`
+}
+
+// ActivityHeatmap contains per namespace activity bucketed by hour.
+type ActivityHeatmap struct {
+	Range Range `json:"range"`
+
+	// Start times of the buckets, oldest first.
+	Buckets []metaV1.Time `json:"buckets"`
+
+	Namespaces []NamespaceActivity `json:"namespaces"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetActivityHeatmap returns activity of namespaces selected by the query within the range ending now.
+func GetActivityHeatmap(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	activityRange Range) (*ActivityHeatmap, error) {
+	log.Printf("Getting namespace activity heatmap for the last %s", activityRange)
+	channels := &common.ResourceChannels{
+		EventList:      common.GetEventListChannel(client, nsQuery, 1),
+		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
+		PodList:        common.GetPodListChannel(client, nsQuery, 1),
+	}
+
+	events := <-channels.EventList.List
+	nonCriticalErrors, criticalError := errors.HandleError(<-channels.EventList.Error)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	replicaSets := <-channels.ReplicaSetList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.ReplicaSetList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	pods := <-channels.PodList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.PodList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	heatmap := newHeatmapBuilder(time.Now(), activityRange)
+	heatmap.addEvents(events.Items)
+	heatmap.addReplicaSets(replicaSets.Items)
+	heatmap.addPods(pods.Items)
+
+	result := heatmap.build()
+	result.Errors = nonCriticalErrors
+	return result, nil
+}
+
+type heatmapBuilder struct {
+	activityRange Range
+	start         time.Time
+	buckets       int
+	namespaces    map[string]*NamespaceActivity
+}
+
+// bucket returns index of the bucket that contains given time, or false if it is outside of the range.
+func (self *heatmapBuilder) bucket(t time.Time) (int, bool) {
+	if t.Before(self.start) {
+		return 0, false
+	}
+
+	index := int(t.Sub(self.start) / BucketSize)
+	if index >= self.buckets {
+		// Times slightly in the future, i.e. because of clock skew, belong to the current bucket.
+		index = self.buckets - 1
+	}
+	return index, true
+}
+
+func (self *heatmapBuilder) row(namespace string) *NamespaceActivity {
+	row, ok := self.namespaces[namespace]
+	if !ok {
+		row = &NamespaceActivity{
+			Namespace:         namespace,
+			Events:            make([]int, self.buckets),
+			DeploymentChanges: make([]int, self.buckets),
+			PodRestarts:       make([]int, self.buckets),
+		}
+		self.namespaces[namespace] = row
+	}
+	return row
+}
+
+func (self *heatmapBuilder) addEvents(events []v1.Event) {
+	for _, event := range events {
+		index, ok := self.bucket(eventTime(event))
+		if !ok {
+			continue
+		}
+
+		count := int(event.Count)
+		if count < 1 {
+			count = 1
+		}
+		row := self.row(event.Namespace)
+		row.Events[index] += count
+		row.Total += count
+	}
+}
+
+func (self *heatmapBuilder) addReplicaSets(replicaSets []apps.ReplicaSet) {
+	for _, replicaSet := range replicaSets {
+		owner := metaV1.GetControllerOf(&replicaSet)
+		if owner == nil || owner.Kind != "Deployment" {
+			continue
+		}
+
+		if index, ok := self.bucket(replicaSet.CreationTimestamp.Time); ok {
+			row := self.row(replicaSet.Namespace)
+			row.DeploymentChanges[index]++
+			row.Total++
+		}
+	}
+}
+
+func (self *heatmapBuilder) addPods(pods []v1.Pod) {
+	for _, pod := range pods {
+		restarted := make(map[int]bool)
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			terminated := status.LastTerminationState.Terminated
+			if status.RestartCount == 0 || terminated == nil {
+				continue
+			}
+
+			if index, ok := self.bucket(terminated.FinishedAt.Time); ok {
+				restarted[index] = true
+			}
+		}
+
+		for index := range restarted {
+			row := self.row(pod.Namespace)
+			row.PodRestarts[index]++
+			row.Total++
+		}
+	}
+}
+
+func (self *heatmapBuilder) build() *ActivityHeatmap {
+	result := &ActivityHeatmap{
+		Range:      self.activityRange,
+		Buckets:    make([]metaV1.Time, self.buckets),
+		Namespaces: make([]NamespaceActivity, 0, len(self.namespaces)),
+	}
+
+	for i := range result.Buckets {
+		result.Buckets[i] = metaV1.NewTime(self.start.Add(time.Duration(i) * BucketSize))
+	}
+
+	for _, row := range self.namespaces {
+		result.Namespaces = append(result.Namespaces, *row)
+	}
+
+	sort.SliceStable(result.Namespaces, func(i, j int) bool {
+		if result.Namespaces[i].Total != result.Namespaces[j].Total {
+			return result.Namespaces[i].Total > result.Namespaces[j].Total
+		}
+		return result.Namespaces[i].Namespace < result.Namespaces[j].Namespace
+	})
+	return result
+}
+
+// newHeatmapBuilder creates builder with hourly buckets covering the range, that ends with the bucket
+// containing now.
+func newHeatmapBuilder(now time.Time, activityRange Range) *heatmapBuilder {
+	buckets := int(activityRange.Duration() / BucketSize)
+	end := now.Truncate(BucketSize).Add(BucketSize)
+	return &heatmapBuilder{
+		activityRange: activityRange,
+		start:         end.Add(-time.Duration(buckets) * BucketSize),
+		buckets:       buckets,
+		namespaces:    make(map[string]*NamespaceActivity),
+	}
+}
+
+// eventTime returns the time of the last occurrence of the event.
+func eventTime(event v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+
+	return event.CreationTimestamp.Time
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseRange(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected Range
+		valid    bool
+	}{
+		{"", RangeDay, true},
+		{"day", RangeDay, true},
+		{"week", RangeWeek, true},
+		{"month", "", false},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseRange(c.name)
+		if actual != c.expected || (err == nil) != c.valid {
+			t.Errorf("ParseRange(%q) == %q, %v, expected %q", c.name, actual, err, c.expected)
+		}
+	}
+}
+
+func TestHeatmapBuilder(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 30, 0, 0, time.UTC)
+	hoursAgo := func(hours int) metaV1.Time {
+		return metaV1.NewTime(now.Add(-time.Duration(hours) * time.Hour))
+	}
+	controller := true
+
+	builder := newHeatmapBuilder(now, RangeDay)
+	builder.addEvents([]v1.Event{
+		{ObjectMeta: metaV1.ObjectMeta{Namespace: "default"}, LastTimestamp: hoursAgo(0), Count: 3},
+		{ObjectMeta: metaV1.ObjectMeta{Namespace: "default"}, LastTimestamp: hoursAgo(2)},
+		{ObjectMeta: metaV1.ObjectMeta{Namespace: "default"}, LastTimestamp: hoursAgo(48)},
+		{ObjectMeta: metaV1.ObjectMeta{Namespace: "kube-system"}, FirstTimestamp: hoursAgo(1)},
+	})
+	builder.addReplicaSets([]apps.ReplicaSet{
+		{ObjectMeta: metaV1.ObjectMeta{Namespace: "kube-system", CreationTimestamp: hoursAgo(1),
+			OwnerReferences: []metaV1.OwnerReference{{Kind: "Deployment", Controller: &controller}}}},
+		{ObjectMeta: metaV1.ObjectMeta{Namespace: "kube-system", CreationTimestamp: hoursAgo(1)}},
+	})
+	builder.addPods([]v1.Pod{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "kube-system"},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+				{RestartCount: 2, LastTerminationState: v1.ContainerState{
+					Terminated: &v1.ContainerStateTerminated{FinishedAt: hoursAgo(1)}}},
+				{RestartCount: 1, LastTerminationState: v1.ContainerState{
+					Terminated: &v1.ContainerStateTerminated{FinishedAt: hoursAgo(1)}}},
+			}},
+		},
+	})
+
+	heatmap := builder.build()
+	if len(heatmap.Buckets) != 24 || !heatmap.Buckets[23].Time.Equal(now.Truncate(time.Hour)) {
+		t.Fatalf("Expected 24 buckets ending with the current hour, but got %v", heatmap.Buckets)
+	}
+
+	if len(heatmap.Namespaces) != 2 {
+		t.Fatalf("Expected activity of 2 namespaces, but got %v", heatmap.Namespaces)
+	}
+
+	defaultNs, system := heatmap.Namespaces[0], heatmap.Namespaces[1]
+	if defaultNs.Namespace != "default" || defaultNs.Total != 4 || defaultNs.Events[23] != 3 ||
+		defaultNs.Events[21] != 1 {
+		t.Errorf("Unexpected activity of default namespace %v", defaultNs)
+	}
+
+	expected := make([]int, 24)
+	expected[22] = 1
+	if system.Namespace != "kube-system" || system.Total != 3 || !reflect.DeepEqual(system.Events, expected) ||
+		!reflect.DeepEqual(system.DeploymentChanges, expected) || !reflect.DeepEqual(system.PodRestarts, expected) {
+		t.Errorf("Unexpected activity of kube-system namespace %v", system)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"sort"
+
+	apps "k8s.io/api/apps/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+)
+
+// Recommended labels, see https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels.
+const (
+	NameLabel      = "app.kubernetes.io/name"
+	InstanceLabel  = "app.kubernetes.io/instance"
+	VersionLabel   = "app.kubernetes.io/version"
+	ComponentLabel = "app.kubernetes.io/component"
+	PartOfLabel    = "app.kubernetes.io/part-of"
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+)
+
+// Status is a rollup status of an application or status of a single component.
+type Status string
+
+const (
+	// StatusRunning means that all replicas are ready.
+	StatusRunning Status = "Running"
+	// StatusProgressing means that some, but not all replicas are ready.
+	StatusProgressing Status = "Progressing"
+	// StatusFailed means that none of desired replicas is ready.
+	StatusFailed Status = "Failed"
+)
+
+// applicationName returns the name of the logical application the object belongs to. The higher level
+// application from part-of label is preferred over the instance and name. Empty name is returned for objects
+// without recommended labels.
+func applicationName(labels map[string]string) string {
+	for _, label := range []string{PartOfLabel, InstanceLabel, NameLabel} {
+		if name := labels[label]; len(name) > 0 {
+			return name
+		}
+	}
+	return ""
+}
+
+func newComponent(meta metaV1.ObjectMeta, kind api.ResourceKind) Component {
+	return Component{
+		ObjectMeta: api.NewObjectMeta(meta),
+		TypeMeta:   api.NewTypeMeta(kind),
+		Component:  meta.Labels[ComponentLabel],
+		Version:    meta.Labels[VersionLabel],
+		Status:     StatusRunning,
+	}
+}
+
+func newWorkloadComponent(meta metaV1.ObjectMeta, kind api.ResourceKind, replicas *int32, ready int32) Component {
+	component := newComponent(meta, kind)
+	component.Desired = 1
+	if replicas != nil {
+		component.Desired = *replicas
+	}
+	component.Ready = ready
+	component.Status = getWorkloadStatus(component.Desired, ready)
+	return component
+}
+
+func getWorkloadStatus(desired, ready int32) Status {
+	if ready >= desired {
+		return StatusRunning
+	}
+	if ready == 0 {
+		return StatusFailed
+	}
+	return StatusProgressing
+}
+
+// getRollupStatus returns the worst status of the components.
+func getRollupStatus(components []Component) Status {
+	status := StatusRunning
+	for _, component := range components {
+		if component.Status == StatusFailed {
+			return StatusFailed
+		}
+		if component.Status == StatusProgressing {
+			status = StatusProgressing
+		}
+	}
+	return status
+}
+
+func toDeploymentComponents(deployments []apps.Deployment) []Component {
+	result := make([]Component, 0)
+	for _, deployment := range deployments {
+		result = append(result, newWorkloadComponent(deployment.ObjectMeta, api.ResourceKindDeployment,
+			deployment.Spec.Replicas, deployment.Status.ReadyReplicas))
+	}
+	return result
+}
+
+func toStatefulSetComponents(statefulSets []apps.StatefulSet) []Component {
+	result := make([]Component, 0)
+	for _, statefulSet := range statefulSets {
+		result = append(result, newWorkloadComponent(statefulSet.ObjectMeta, api.ResourceKindStatefulSet,
+			statefulSet.Spec.Replicas, statefulSet.Status.ReadyReplicas))
+	}
+	return result
+}
+
+// toApplications groups components by namespace and application name. Components without the recommended
+// labels are skipped.
+func toApplications(components []Component) []Application {
+	byKey := make(map[string]*Application)
+	keys := make([]string, 0)
+	for _, component := range components {
+		name := applicationName(component.ObjectMeta.Labels)
+		if len(name) == 0 {
+			continue
+		}
+
+		key := component.ObjectMeta.Namespace + "/" + name
+		application, exists := byKey[key]
+		if !exists {
+			application = &Application{
+				ObjectMeta: api.ObjectMeta{Name: name, Namespace: component.ObjectMeta.Namespace,
+					CreationTimestamp: component.ObjectMeta.CreationTimestamp},
+				ComponentCounts: make(map[api.ResourceKind]int),
+			}
+			byKey[key] = application
+			keys = append(keys, key)
+		}
+
+		application.add(component)
+	}
+
+	sort.Strings(keys)
+	result := make([]Application, 0, len(keys))
+	for _, key := range keys {
+		application := byKey[key]
+		application.Status = getRollupStatus(application.components)
+		result = append(result, *application)
+	}
+	return result
+}
+
+func (self *Application) add(component Component) {
+	self.components = append(self.components, component)
+	self.ComponentCounts[component.TypeMeta.Kind]++
+	if component.ObjectMeta.CreationTimestamp.Before(&self.ObjectMeta.CreationTimestamp) {
+		self.ObjectMeta.CreationTimestamp = component.ObjectMeta.CreationTimestamp
+	}
+	self.Versions = appendUnique(self.Versions, component.Version)
+	self.ManagedBy = appendUnique(self.ManagedBy, component.ObjectMeta.Labels[ManagedByLabel])
+}
+
+func appendUnique(values []string, value string) []string {
+	if len(value) == 0 {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// The code below allows to perform complex data section on applications
+
+type ApplicationCell Application
+
+// GetProperty is used to get property of the application
+func (self ApplicationCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(string(self.Status))
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toCells(std []Application) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = ApplicationCell(std[i])
+	}
+	return cells
+}
+
+func fromCells(cells []dataselect.DataCell) []Application {
+	std := make([]Application, len(cells))
+	for i := range std {
+		std[i] = Application(cells[i].(ApplicationCell))
+	}
+	return std
+}
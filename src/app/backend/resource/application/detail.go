@@ -0,0 +1,57 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"fmt"
+	"log"
+
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// ApplicationDetail is an application together with all its components.
+type ApplicationDetail struct {
+	Application `json:",inline"`
+
+	Components []Component `json:"components"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetApplicationDetail returns an application from given namespace with all its components.
+func GetApplicationDetail(client client.Interface, namespace, name string) (*ApplicationDetail, error) {
+	log.Printf("Getting details of %s application in %s namespace", name, namespace)
+
+	components, nonCriticalErrors, err := getComponents(client, common.NewSameNamespaceQuery(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, application := range toApplications(components) {
+		if application.ObjectMeta.Name == name {
+			return &ApplicationDetail{
+				Application: application,
+				Components:  application.components,
+				Errors:      nonCriticalErrors,
+			}, nil
+		}
+	}
+
+	return nil, errors.NewNotFound(fmt.Sprintf("application %s not found in %s namespace", name, namespace))
+}
@@ -0,0 +1,169 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"log"
+
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+)
+
+// Component is a single resource that is a part of an application.
+type Component struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Component is the value of the component label, i.e. database.
+	Component string `json:"component,omitempty"`
+	// Version is the value of the version label.
+	Version string `json:"version,omitempty"`
+
+	// Ready and desired replicas of workloads.
+	Ready   int32 `json:"ready"`
+	Desired int32 `json:"desired"`
+
+	Status Status `json:"status"`
+}
+
+// Application is a logical application built from resources sharing app.kubernetes.io labels.
+type Application struct {
+	// ObjectMeta contains the name and namespace of the application and creation time of its oldest component.
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+
+	// Versions and tools managing the application, as found on its components.
+	Versions  []string `json:"versions"`
+	ManagedBy []string `json:"managedBy"`
+
+	// ComponentCounts contains the number of application components per kind.
+	ComponentCounts map[api.ResourceKind]int `json:"componentCounts"`
+
+	// Status is the worst status of application components.
+	Status Status `json:"status"`
+
+	components []Component
+}
+
+// ApplicationList contains a list of applications in the cluster.
+type ApplicationList struct {
+	ListMeta     api.ListMeta  `json:"listMeta"`
+	Applications []Application `json:"applications"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetApplicationList returns a list of applications built from deployments, stateful sets, services, ingresses
+// and config maps with the recommended labels.
+func GetApplicationList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ApplicationList, error) {
+	log.Print("Getting list of all applications in the cluster")
+
+	components, nonCriticalErrors, err := getComponents(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return toApplicationList(toApplications(components), nonCriticalErrors, dsQuery), nil
+}
+
+func toApplicationList(applications []Application, nonCriticalErrors []error,
+	dsQuery *dataselect.DataSelectQuery) *ApplicationList {
+	result := &ApplicationList{
+		Applications: make([]Application, 0),
+		ListMeta:     api.ListMeta{TotalItems: len(applications)},
+		Errors:       nonCriticalErrors,
+	}
+
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(applications), dsQuery)
+	result.Applications = fromCells(cells)
+	result.ListMeta.TotalItems = filteredTotal
+	return result
+}
+
+// getComponents returns all resources with the recommended labels, that can be a part of an application.
+func getComponents(client client.Interface, nsQuery *common.NamespaceQuery) ([]Component, []error, error) {
+	channels := &common.ResourceChannels{
+		DeploymentList:  common.GetDeploymentListChannel(client, nsQuery, 1),
+		StatefulSetList: common.GetStatefulSetListChannel(client, nsQuery, 1),
+		ServiceList:     common.GetServiceListChannel(client, nsQuery, 1),
+		IngressList:     common.GetIngressListChannel(client, nsQuery, 1),
+		ConfigMapList:   common.GetConfigMapListChannel(client, nsQuery, 1),
+	}
+
+	deployments := <-channels.DeploymentList.List
+	err := <-channels.DeploymentList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, nil, criticalError
+	}
+
+	statefulSets := <-channels.StatefulSetList.List
+	err = <-channels.StatefulSetList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, nil, criticalError
+	}
+
+	services := <-channels.ServiceList.List
+	err = <-channels.ServiceList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, nil, criticalError
+	}
+
+	ingresses := <-channels.IngressList.List
+	err = <-channels.IngressList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, nil, criticalError
+	}
+
+	configMaps := <-channels.ConfigMapList.List
+	err = <-channels.ConfigMapList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, nil, criticalError
+	}
+
+	components := make([]Component, 0)
+	if deployments != nil {
+		components = append(components, toDeploymentComponents(deployments.Items)...)
+	}
+	if statefulSets != nil {
+		components = append(components, toStatefulSetComponents(statefulSets.Items)...)
+	}
+	if services != nil {
+		for _, service := range services.Items {
+			components = append(components, newComponent(service.ObjectMeta, api.ResourceKindService))
+		}
+	}
+	if ingresses != nil {
+		for _, ingress := range ingresses.Items {
+			components = append(components, newComponent(ingress.ObjectMeta, api.ResourceKindIngress))
+		}
+	}
+	if configMaps != nil {
+		for _, configMap := range configMaps.Items {
+			components = append(components, newComponent(configMap.ObjectMeta, api.ResourceKindConfigMap))
+		}
+	}
+
+	return components, nonCriticalErrors, nil
+}
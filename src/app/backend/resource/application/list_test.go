@@ -0,0 +1,128 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+)
+
+func newMeta(name string, labels map[string]string) metaV1.ObjectMeta {
+	return metaV1.ObjectMeta{Name: name, Namespace: "shop", Labels: labels}
+}
+
+func newDeployment(name string, labels map[string]string, replicas, ready int32) *apps.Deployment {
+	return &apps.Deployment{
+		ObjectMeta: newMeta(name, labels),
+		Spec:       apps.DeploymentSpec{Replicas: &replicas},
+		Status:     apps.DeploymentStatus{ReadyReplicas: ready},
+	}
+}
+
+func TestGetApplicationList(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newDeployment("frontend", map[string]string{PartOfLabel: "shop", VersionLabel: "1.2", ManagedByLabel: "helm"},
+			2, 2),
+		newDeployment("checkout", map[string]string{PartOfLabel: "shop", VersionLabel: "1.3"}, 2, 1),
+		newDeployment("blog", map[string]string{NameLabel: "blog"}, 1, 1),
+		newDeployment("unlabeled", nil, 1, 0),
+		&v1.Service{ObjectMeta: newMeta("frontend", map[string]string{PartOfLabel: "shop"})},
+		&v1.ConfigMap{ObjectMeta: newMeta("blog-config", map[string]string{InstanceLabel: "blog"})},
+	)
+
+	actual, err := GetApplicationList(client, common.NewSameNamespaceQuery("shop"), dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("GetApplicationList() unexpected error: %v", err)
+	}
+
+	expected := []Application{
+		{
+			ObjectMeta:      api.ObjectMeta{Name: "blog", Namespace: "shop"},
+			ComponentCounts: map[api.ResourceKind]int{api.ResourceKindDeployment: 1, api.ResourceKindConfigMap: 1},
+			Status:          StatusRunning,
+		},
+		{
+			ObjectMeta:      api.ObjectMeta{Name: "shop", Namespace: "shop"},
+			Versions:        []string{"1.3", "1.2"},
+			ManagedBy:       []string{"helm"},
+			ComponentCounts: map[api.ResourceKind]int{api.ResourceKindDeployment: 2, api.ResourceKindService: 1},
+			Status:          StatusProgressing,
+		},
+	}
+
+	if len(actual.Applications) != len(expected) {
+		t.Fatalf("Expected %d applications, got %d", len(expected), len(actual.Applications))
+	}
+	for i := range expected {
+		a := actual.Applications[i]
+		e := expected[i]
+		if a.ObjectMeta.Name != e.ObjectMeta.Name || a.Status != e.Status ||
+			!reflect.DeepEqual(a.ComponentCounts, e.ComponentCounts) || len(a.Versions) != len(e.Versions) ||
+			!reflect.DeepEqual(a.ManagedBy, e.ManagedBy) {
+			t.Errorf("Expected application %+v, got %+v", e, a)
+		}
+	}
+}
+
+func TestGetApplicationDetail(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newDeployment("db", map[string]string{InstanceLabel: "wiki", ComponentLabel: "database"}, 1, 0),
+		newDeployment("web", map[string]string{InstanceLabel: "wiki"}, 1, 1),
+	)
+
+	detail, err := GetApplicationDetail(client, "shop", "wiki")
+	if err != nil {
+		t.Fatalf("GetApplicationDetail() unexpected error: %v", err)
+	}
+	if detail.Status != StatusFailed || len(detail.Components) != 2 {
+		t.Errorf("Unexpected application detail %+v", detail)
+	}
+	for _, component := range detail.Components {
+		if component.ObjectMeta.Name == "db" && component.Component != "database" {
+			t.Errorf("Expected database component, got %q", component.Component)
+		}
+	}
+
+	if _, err := GetApplicationDetail(client, "shop", "missing"); err == nil {
+		t.Error("Expected error for missing application")
+	}
+}
+
+func TestGetWorkloadStatus(t *testing.T) {
+	cases := []struct {
+		desired, ready int32
+		expected       Status
+	}{
+		{3, 3, StatusRunning},
+		{0, 0, StatusRunning},
+		{3, 1, StatusProgressing},
+		{3, 0, StatusFailed},
+	}
+
+	for _, c := range cases {
+		if actual := getWorkloadStatus(c.desired, c.ready); actual != c.expected {
+			t.Errorf("getWorkloadStatus(%d, %d) == %s, expected %s", c.desired, c.ready, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attention
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/restartstorm"
+)
+
+// DefaultGracePeriod is the time a workload may stay below desired number of replicas before it needs attention.
+const DefaultGracePeriod = 5 * time.Minute
+
+// Severity tells how urgently a resource needs attention.
+type Severity string
+
+const (
+	// SeverityCritical is used for resources that do not serve at all, i.e. nodes that are not ready or workloads
+	// without any available replica.
+	SeverityCritical Severity = "critical"
+	// SeverityWarning is used for degraded resources.
+	SeverityWarning Severity = "warning"
+)
+
+// severityOrder maps severities to their priority on the list.
+var severityOrder = map[Severity]int{SeverityCritical: 0, SeverityWarning: 1}
+
+// Item is a single resource that needs attention, together with reasons why.
+type Item struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+	Severity   Severity       `json:"severity"`
+	Reasons    []string       `json:"reasons"`
+
+	// Since is the time from which the resource is in its current state, if it is known.
+	Since *metaV1.Time `json:"since,omitempty"`
+}
+
+// AttentionList contains resources that need attention ordered by severity and then by the time they need it.
+type AttentionList struct {
+	ListMeta ListMeta `json:"listMeta"`
+	Items    []Item   `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ListMeta contains total number of items and number of items of each severity.
+type ListMeta struct {
+	TotalItems int `json:"totalItems"`
+	Critical   int `json:"critical"`
+	Warning    int `json:"warning"`
+}
+
+// GetAttentionList returns workloads restarting more often than allowed by restart storm thresholds, workloads
+// running below desired number of replicas for longer than the grace period and, when all namespaces are
+// queried, nodes that are not ready.
+func GetAttentionList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	gracePeriod time.Duration) (*AttentionList, error) {
+	log.Print("Getting list of resources that need attention")
+	channels := &common.ResourceChannels{
+		PodList:         common.GetPodListChannel(client, nsQuery, 1),
+		ReplicaSetList:  common.GetReplicaSetListChannel(client, nsQuery, 1),
+		DeploymentList:  common.GetDeploymentListChannel(client, nsQuery, 1),
+		StatefulSetList: common.GetStatefulSetListChannel(client, nsQuery, 1),
+		DaemonSetList:   common.GetDaemonSetListChannel(client, nsQuery, 1),
+		EventList: common.GetEventListChannelWithOptions(client, nsQuery,
+			restartstorm.StartedEventOptions, 1),
+	}
+
+	allNamespaces := nsQuery.ToRequestParam() == metaV1.NamespaceAll
+	if allNamespaces {
+		channels.NodeList = common.GetNodeListChannel(client, 1)
+	}
+
+	pods := <-channels.PodList.List
+	nonCriticalErrors, criticalError := errors.HandleError(<-channels.PodList.Error)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	replicaSets := <-channels.ReplicaSetList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.ReplicaSetList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	deployments := <-channels.DeploymentList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.DeploymentList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	statefulSets := <-channels.StatefulSetList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.StatefulSetList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	daemonSets := <-channels.DaemonSetList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.DaemonSetList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	events := <-channels.EventList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.EventList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	nodes := &v1.NodeList{}
+	if allNamespaces {
+		nodes = <-channels.NodeList.List
+		nonCriticalErrors, criticalError = errors.AppendError(<-channels.NodeList.Error, nonCriticalErrors)
+		if criticalError != nil {
+			return nil, criticalError
+		}
+	}
+
+	now := time.Now()
+	items := make([]Item, 0)
+	items = append(items, getNotReadyNodes(nodes.Items)...)
+	items = append(items, getUnavailableDeployments(deployments.Items, now, gracePeriod)...)
+	items = append(items, getUnavailableStatefulSets(statefulSets.Items, pods.Items, replicaSets.Items, now,
+		gracePeriod)...)
+	items = append(items, getUnavailableDaemonSets(daemonSets.Items, pods.Items, replicaSets.Items, now,
+		gracePeriod)...)
+	items = append(items, getRestartingWorkloads(pods.Items, replicaSets.Items, events.Items, now)...)
+	return toAttentionList(items, nonCriticalErrors), nil
+}
+
+func toAttentionList(items []Item, nonCriticalErrors []error) *AttentionList {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Severity != items[j].Severity {
+			return severityOrder[items[i].Severity] < severityOrder[items[j].Severity]
+		}
+		if items[i].Since == nil || items[j].Since == nil {
+			return items[j].Since == nil && items[i].Since != nil
+		}
+		return items[i].Since.Before(items[j].Since)
+	})
+
+	result := &AttentionList{
+		ListMeta: ListMeta{TotalItems: len(items)},
+		Items:    items,
+		Errors:   nonCriticalErrors,
+	}
+	for _, item := range items {
+		switch item.Severity {
+		case SeverityCritical:
+			result.ListMeta.Critical++
+		case SeverityWarning:
+			result.ListMeta.Warning++
+		}
+	}
+	return result
+}
+
+func getNotReadyNodes(nodes []v1.Node) []Item {
+	result := make([]Item, 0)
+	for _, node := range nodes {
+		condition := getNodeReadyCondition(node)
+		if condition != nil && condition.Status == v1.ConditionTrue {
+			continue
+		}
+
+		item := Item{
+			ObjectMeta: api.NewObjectMeta(node.ObjectMeta),
+			TypeMeta:   api.NewTypeMeta(api.ResourceKindNode),
+			Severity:   SeverityCritical,
+		}
+		if condition == nil {
+			item.Reasons = []string{"Node has not reported its readiness"}
+		} else {
+			since := condition.LastTransitionTime
+			item.Since = &since
+			item.Reasons = []string{withDetails(fmt.Sprintf("Node is not ready (%s)", condition.Status),
+				condition.Reason, condition.Message)}
+		}
+		if node.Spec.Unschedulable {
+			item.Reasons = append(item.Reasons, "Node is cordoned")
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func getNodeReadyCondition(node v1.Node) *v1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == v1.NodeReady {
+			return &node.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// getUnavailableDeployments uses deployment conditions to find out how long deployments are below desired number
+// of available replicas. Available condition turns false only when more replicas than allowed by the rollout
+// strategy are unavailable, otherwise the last progress of the rollout is used.
+func getUnavailableDeployments(deployments []apps.Deployment, now time.Time, gracePeriod time.Duration) []Item {
+	result := make([]Item, 0)
+	for _, deployment := range deployments {
+		desired := getDesiredReplicas(deployment.Spec.Replicas)
+		available := deployment.Status.AvailableReplicas
+		if available >= desired {
+			continue
+		}
+
+		var since *metaV1.Time
+		reasons := []string{fmt.Sprintf("%d of %d replicas available", available, desired)}
+		for _, condition := range deployment.Status.Conditions {
+			switch {
+			case condition.Type == apps.DeploymentAvailable && condition.Status == v1.ConditionFalse:
+				transition := condition.LastTransitionTime
+				since = &transition
+			case condition.Type == apps.DeploymentProgressing && since == nil:
+				update := condition.LastUpdateTime
+				since = &update
+				if condition.Status == v1.ConditionFalse {
+					reasons = append(reasons, withDetails("Rollout is not progressing", condition.Reason,
+						condition.Message))
+				}
+			}
+		}
+
+		if item, ok := newUnavailableItem(deployment.ObjectMeta, api.ResourceKindDeployment, available, since,
+			reasons, now, gracePeriod); ok {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func getUnavailableStatefulSets(statefulSets []apps.StatefulSet, pods []v1.Pod, replicaSets []apps.ReplicaSet,
+	now time.Time, gracePeriod time.Duration) []Item {
+	notReady := getNotReadySince(pods, replicaSets)
+	result := make([]Item, 0)
+	for _, statefulSet := range statefulSets {
+		desired := getDesiredReplicas(statefulSet.Spec.Replicas)
+		ready := statefulSet.Status.ReadyReplicas
+		if ready >= desired {
+			continue
+		}
+
+		owner := common.ResourceOwner{Kind: api.ResourceKindStatefulSet, Namespace: statefulSet.Namespace,
+			Name: statefulSet.Name}
+		reasons := []string{fmt.Sprintf("%d of %d replicas ready", ready, desired)}
+		if item, ok := newUnavailableItem(statefulSet.ObjectMeta, api.ResourceKindStatefulSet, ready,
+			getSince(notReady, owner, statefulSet.ObjectMeta), reasons, now, gracePeriod); ok {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func getUnavailableDaemonSets(daemonSets []apps.DaemonSet, pods []v1.Pod, replicaSets []apps.ReplicaSet,
+	now time.Time, gracePeriod time.Duration) []Item {
+	notReady := getNotReadySince(pods, replicaSets)
+	result := make([]Item, 0)
+	for _, daemonSet := range daemonSets {
+		desired := daemonSet.Status.DesiredNumberScheduled
+		available := daemonSet.Status.NumberAvailable
+		if available >= desired {
+			continue
+		}
+
+		owner := common.ResourceOwner{Kind: api.ResourceKindDaemonSet, Namespace: daemonSet.Namespace,
+			Name: daemonSet.Name}
+		reasons := []string{fmt.Sprintf("%d of %d pods available", available, desired)}
+		if misscheduled := daemonSet.Status.NumberMisscheduled; misscheduled > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d pods running on nodes they should not run on", misscheduled))
+		}
+		if item, ok := newUnavailableItem(daemonSet.ObjectMeta, api.ResourceKindDaemonSet, available,
+			getSince(notReady, owner, daemonSet.ObjectMeta), reasons, now, gracePeriod); ok {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// newUnavailableItem returns workload item if the workload is below desired number of replicas for longer than
+// the grace period. Workloads without any replica are critical.
+func newUnavailableItem(meta metaV1.ObjectMeta, kind api.ResourceKind, available int32, since *metaV1.Time,
+	reasons []string, now time.Time, gracePeriod time.Duration) (Item, bool) {
+	if since == nil || since.IsZero() || now.Sub(since.Time) < gracePeriod {
+		return Item{}, false
+	}
+
+	severity := SeverityWarning
+	if available == 0 {
+		severity = SeverityCritical
+	}
+
+	reasons = append(reasons, fmt.Sprintf("Below desired replicas for %s", now.Sub(since.Time).Round(time.Minute)))
+	return Item{
+		ObjectMeta: api.NewObjectMeta(meta),
+		TypeMeta:   api.NewTypeMeta(kind),
+		Severity:   severity,
+		Reasons:    reasons,
+		Since:      since,
+	}, true
+}
+
+// getNotReadySince returns the earliest time from which any pod of the workload is not ready.
+func getNotReadySince(pods []v1.Pod, replicaSets []apps.ReplicaSet) map[common.ResourceOwner]metaV1.Time {
+	result := make(map[common.ResourceOwner]metaV1.Time)
+	for _, pod := range pods {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type != v1.PodReady || condition.Status == v1.ConditionTrue {
+				continue
+			}
+
+			owner := common.GetPodOwner(pod, replicaSets)
+			if since, ok := result[owner]; !ok || condition.LastTransitionTime.Before(&since) {
+				result[owner] = condition.LastTransitionTime
+			}
+		}
+	}
+	return result
+}
+
+// getSince returns time from which the workload has a pod that is not ready. When all of its pods are ready, some
+// pods are missing, which is assumed to last since the workload was last changed or created.
+func getSince(notReady map[common.ResourceOwner]metaV1.Time, owner common.ResourceOwner,
+	meta metaV1.ObjectMeta) *metaV1.Time {
+	if since, ok := notReady[owner]; ok {
+		return &since
+	}
+
+	since := meta.CreationTimestamp
+	for _, field := range meta.ManagedFields {
+		if field.Time != nil && field.Time.After(since.Time) {
+			since = *field.Time
+		}
+	}
+	return &since
+}
+
+func getRestartingWorkloads(pods []v1.Pod, replicaSets []apps.ReplicaSet, events []v1.Event,
+	now time.Time) []Item {
+	result := make([]Item, 0)
+	for _, storm := range restartstorm.GetRestartStorms(pods, replicaSets, events, restartstorm.DefaultThresholds,
+		now) {
+		item := Item{
+			ObjectMeta: storm.ObjectMeta,
+			TypeMeta:   storm.TypeMeta,
+			Severity:   SeverityWarning,
+		}
+		for _, window := range storm.Windows {
+			if window.Exceeded {
+				item.Reasons = append(item.Reasons, fmt.Sprintf("%d restarts within %s, more than expected %d",
+					window.Restarts, window.Window, window.Threshold))
+			}
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func getDesiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func withDetails(reason string, details ...string) string {
+	for _, detail := range details {
+		if len(detail) > 0 {
+			reason = fmt.Sprintf("%s: %s", reason, detail)
+			break
+		}
+	}
+	return reason
+}
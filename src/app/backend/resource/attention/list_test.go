@@ -0,0 +1,134 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attention
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetNotReadyNodes(t *testing.T) {
+	since := metaV1.NewTime(time.Now().Add(-time.Hour))
+	nodes := []v1.Node{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "ready"},
+			Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionTrue},
+			}},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "not-ready"},
+			Spec:       v1.NodeSpec{Unschedulable: true},
+			Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionUnknown, Reason: "NodeStatusUnknown",
+					LastTransitionTime: since},
+			}},
+		},
+	}
+
+	actual := getNotReadyNodes(nodes)
+	if len(actual) != 1 {
+		t.Fatalf("Expected single node, but got %v", actual)
+	}
+
+	expected := []string{"Node is not ready (Unknown): NodeStatusUnknown", "Node is cordoned"}
+	if actual[0].ObjectMeta.Name != "not-ready" || actual[0].Severity != SeverityCritical ||
+		!reflect.DeepEqual(actual[0].Reasons, expected) || !actual[0].Since.Equal(&since) {
+		t.Errorf("Expected not ready node with reasons %v, but got %v", expected, actual[0])
+	}
+}
+
+func TestGetUnavailableDeployments(t *testing.T) {
+	now := time.Now()
+	replicas := int32(3)
+	newDeployment := func(available int32, conditions ...apps.DeploymentCondition) apps.Deployment {
+		return apps.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       apps.DeploymentSpec{Replicas: &replicas},
+			Status:     apps.DeploymentStatus{AvailableReplicas: available, Conditions: conditions},
+		}
+	}
+
+	cases := []struct {
+		info       string
+		deployment apps.Deployment
+		expected   Severity
+	}{
+		{"available", newDeployment(3), ""},
+		{
+			"unavailable for a long time",
+			newDeployment(0, apps.DeploymentCondition{Type: apps.DeploymentAvailable,
+				Status: v1.ConditionFalse, LastTransitionTime: metaV1.NewTime(now.Add(-time.Hour))}),
+			SeverityCritical,
+		},
+		{
+			"unavailable within grace period",
+			newDeployment(0, apps.DeploymentCondition{Type: apps.DeploymentAvailable,
+				Status: v1.ConditionFalse, LastTransitionTime: metaV1.NewTime(now.Add(-time.Minute))}),
+			"",
+		},
+		{
+			"degraded while rollout is stuck",
+			newDeployment(2, apps.DeploymentCondition{Type: apps.DeploymentProgressing,
+				Status: v1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+				LastUpdateTime: metaV1.NewTime(now.Add(-time.Hour))}),
+			SeverityWarning,
+		},
+	}
+
+	for _, c := range cases {
+		actual := getUnavailableDeployments([]apps.Deployment{c.deployment}, now, DefaultGracePeriod)
+		if len(c.expected) == 0 {
+			if len(actual) != 0 {
+				t.Errorf("Test Case: %s. Expected no items, but got %v", c.info, actual)
+			}
+			continue
+		}
+		if len(actual) != 1 || actual[0].Severity != c.expected {
+			t.Errorf("Test Case: %s. Expected single %s item, but got %v", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestToAttentionList(t *testing.T) {
+	now := time.Now()
+	older := metaV1.NewTime(now.Add(-time.Hour))
+	newer := metaV1.NewTime(now.Add(-time.Minute))
+	items := []Item{
+		{Reasons: []string{"restarting"}, Severity: SeverityWarning},
+		{Reasons: []string{"newer degraded"}, Severity: SeverityWarning, Since: &newer},
+		{Reasons: []string{"unavailable"}, Severity: SeverityCritical, Since: &newer},
+		{Reasons: []string{"older degraded"}, Severity: SeverityWarning, Since: &older},
+	}
+
+	actual := toAttentionList(items, nil)
+	order := make([]string, 0)
+	for _, item := range actual.Items {
+		order = append(order, item.Reasons[0])
+	}
+
+	expected := []string{"unavailable", "older degraded", "newer degraded", "restarting"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected order %v, but got %v", expected, order)
+	}
+	if actual.ListMeta != (ListMeta{TotalItems: 4, Critical: 1, Warning: 3}) {
+		t.Errorf("Expected 1 critical and 3 warning items, but got %+v", actual.ListMeta)
+	}
+}
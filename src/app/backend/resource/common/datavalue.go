@@ -0,0 +1,129 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"unicode/utf8"
+)
+
+// MaxDataValueSize is the maximum size in bytes of a single config map or secret value included in detail
+// responses and of a single slice of the value. Larger values have to be fetched in slices.
+const MaxDataValueSize = 64 * 1024
+
+// DataValueInfo describes a config map or secret value that has been truncated or left out of the detail response.
+type DataValueInfo struct {
+	// Size of the whole value in bytes.
+	Size int `json:"size"`
+	// Truncated is true when only the beginning of the value is included in the detail response.
+	Truncated bool `json:"truncated"`
+	// Binary is true when the value is not a text. Binary values are left out of the detail response.
+	Binary bool `json:"binary"`
+}
+
+// DataValueSlice is a part of a config map or secret value.
+type DataValueSlice struct {
+	Key string `json:"key"`
+	// Offset of the slice in bytes. It may differ from requested offset, as text slices start and end on
+	// character boundaries.
+	Offset int `json:"offset"`
+	// Length of the slice in bytes. Next slice starts at Offset+Length.
+	Length int `json:"length"`
+	// Size of the whole value in bytes.
+	Size   int  `json:"size"`
+	Binary bool `json:"binary"`
+	// Value contains the slice of a text value.
+	Value string `json:"value,omitempty"`
+	// BinaryValue contains the slice of a binary value.
+	BinaryValue []byte `json:"binaryValue,omitempty"`
+}
+
+// IsBinary returns true if given value is not a valid UTF-8 text or contains control characters other than
+// whitespace, i.e. for certificates in DER format or archives.
+func IsBinary(value []byte) bool {
+	if !utf8.Valid(value) {
+		return true
+	}
+
+	for _, b := range value {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TruncateDataValue returns the value cut to MaxDataValueSize on a character boundary and information about
+// the value. Binary values larger than MaxDataValueSize are left out completely. Info is nil if the value is
+// returned unchanged.
+func TruncateDataValue(value []byte) ([]byte, *DataValueInfo) {
+	if len(value) <= MaxDataValueSize {
+		return value, nil
+	}
+
+	if IsBinary(value) {
+		return []byte{}, &DataValueInfo{Size: len(value), Binary: true}
+	}
+
+	end := toRuneStart(value, MaxDataValueSize)
+	return value[:end], &DataValueInfo{Size: len(value), Truncated: true}
+}
+
+// GetDataValueSlice returns a slice of the value starting at offset with at most length bytes. Length is limited
+// to MaxDataValueSize. Slices of text values are moved to character boundaries.
+func GetDataValueSlice(key string, value []byte, offset, length int) *DataValueSlice {
+	if offset < 0 || offset > len(value) {
+		offset = len(value)
+	}
+
+	if length <= 0 || length > MaxDataValueSize {
+		length = MaxDataValueSize
+	}
+
+	end := offset + length
+	if end > len(value) {
+		end = len(value)
+	}
+
+	result := &DataValueSlice{Key: key, Size: len(value), Binary: IsBinary(value)}
+	if !result.Binary {
+		offset = toRuneStart(value, offset)
+		end = toRuneStart(value, end)
+		if end == offset && end < len(value) {
+			// Length is shorter than the character, return the whole character so the slices make progress.
+			_, size := utf8.DecodeRune(value[offset:])
+			end = offset + size
+		}
+	}
+
+	result.Offset = offset
+	result.Length = end - offset
+	if result.Binary {
+		result.BinaryValue = value[offset:end]
+	} else {
+		result.Value = string(value[offset:end])
+	}
+
+	return result
+}
+
+// Moves given index of a text value back to the start of a character.
+func toRuneStart(value []byte, index int) int {
+	for index > 0 && index < len(value) && !utf8.RuneStart(value[index]) {
+		index--
+	}
+
+	return index
+}
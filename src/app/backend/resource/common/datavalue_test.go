@@ -0,0 +1,100 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	cases := []struct {
+		value    []byte
+		expected bool
+	}{
+		{[]byte("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"), false},
+		{[]byte("zażółć\tgęślą jaźń\r\n"), false},
+		{[]byte{0x30, 0x82, 0x01, 0x0a}, true},
+		{[]byte("text\x00with nul"), true},
+	}
+
+	for _, c := range cases {
+		if actual := IsBinary(c.value); actual != c.expected {
+			t.Errorf("IsBinary(%q) == %t, expected %t", c.value, actual, c.expected)
+		}
+	}
+}
+
+func TestTruncateDataValue(t *testing.T) {
+	small := []byte("small")
+	if value, info := TruncateDataValue(small); !reflect.DeepEqual(value, small) || info != nil {
+		t.Errorf("TruncateDataValue(small) == %q, %v, expected value unchanged", value, info)
+	}
+
+	// Multi-byte character crosses the size limit, so it has to be cut out completely.
+	text := []byte(strings.Repeat("a", MaxDataValueSize-1) + "ż" + "tail")
+	value, info := TruncateDataValue(text)
+	expected := &DataValueInfo{Size: len(text), Truncated: true}
+	if len(value) != MaxDataValueSize-1 || !reflect.DeepEqual(info, expected) {
+		t.Errorf("TruncateDataValue(text) == %d bytes, %v, expected %d bytes, %v", len(value), info,
+			MaxDataValueSize-1, expected)
+	}
+
+	binary := make([]byte, MaxDataValueSize+1)
+	value, info = TruncateDataValue(binary)
+	expected = &DataValueInfo{Size: len(binary), Binary: true}
+	if len(value) != 0 || !reflect.DeepEqual(info, expected) {
+		t.Errorf("TruncateDataValue(binary) == %d bytes, %v, expected 0 bytes, %v", len(value), info, expected)
+	}
+}
+
+func TestGetDataValueSlice(t *testing.T) {
+	cases := []struct {
+		value          []byte
+		offset, length int
+		expected       *DataValueSlice
+	}{
+		{
+			[]byte("hello world"), 6, 5,
+			&DataValueSlice{Key: "key", Offset: 6, Length: 5, Size: 11, Value: "world"},
+		},
+		{
+			[]byte("hello world"), 6, 0,
+			&DataValueSlice{Key: "key", Offset: 6, Length: 5, Size: 11, Value: "world"},
+		},
+		{
+			[]byte("hello world"), 20, 5,
+			&DataValueSlice{Key: "key", Offset: 11, Length: 0, Size: 11},
+		},
+		{
+			// Slice starting and ending inside of 'ż' is moved to the start of the character.
+			[]byte("aż"), 2, 1,
+			&DataValueSlice{Key: "key", Offset: 1, Length: 2, Size: 3, Value: "ż"},
+		},
+		{
+			[]byte{0x00, 0x01, 0x02, 0x03}, 1, 2,
+			&DataValueSlice{Key: "key", Offset: 1, Length: 2, Size: 4, Binary: true, BinaryValue: []byte{0x01, 0x02}},
+		},
+	}
+
+	for _, c := range cases {
+		actual := GetDataValueSlice("key", c.value, c.offset, c.length)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetDataValueSlice(%q, %d, %d) == %#v, expected %#v", c.value, c.offset, c.length, actual,
+				c.expected)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+// GroupByQueryParameter is the name of the list query parameter containing the label used to group list items.
+const GroupByQueryParameter = "groupBy"
+
+// LabelGroup contains list items sharing the same value of a label.
+type LabelGroup struct {
+	// Value of the label. Items without the label are grouped under an empty value.
+	Value string `json:"value"`
+
+	// List of items in the group, i.e. a pod list with status and cumulative metrics of the group.
+	List interface{} `json:"list"`
+}
+
+// GroupedList contains list items grouped by value of a label.
+type GroupedList struct {
+	// ListMeta contains the number of groups.
+	ListMeta api.ListMeta `json:"listMeta"`
+
+	// GroupBy is the label used to group items.
+	GroupBy string `json:"groupBy"`
+
+	Groups []LabelGroup `json:"groups"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// NewGroupedList returns an empty list grouped by given label.
+func NewGroupedList(label string, nonCriticalErrors []error) *GroupedList {
+	return &GroupedList{
+		GroupBy: label,
+		Groups:  make([]LabelGroup, 0),
+		Errors:  nonCriticalErrors,
+	}
+}
+
+// AddGroup appends a group to the list.
+func (self *GroupedList) AddGroup(value string, list interface{}) {
+	self.Groups = append(self.Groups, LabelGroup{Value: value, List: list})
+	self.ListMeta.TotalItems = len(self.Groups)
+}
+
+// GroupByLabel groups count items by value of given label, read with labelsAt. It returns the values sorted
+// alphabetically, with an empty value for items without the label last, and indices of items for every value.
+func GroupByLabel(label string, count int, labelsAt func(i int) map[string]string) ([]string, map[string][]int) {
+	groups := make(map[string][]int)
+	values := make([]string, 0)
+	for i := 0; i < count; i++ {
+		value := labelsAt(i)[label]
+		if _, exists := groups[value]; !exists {
+			values = append(values, value)
+		}
+		groups[value] = append(groups[value], i)
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) == 0 || len(values[j]) == 0 {
+			return len(values[j]) == 0 && len(values[i]) > 0
+		}
+		return values[i] < values[j]
+	})
+	return values, groups
+}
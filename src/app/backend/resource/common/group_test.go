@@ -0,0 +1,53 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupByLabel(t *testing.T) {
+	items := []map[string]string{
+		{"app": "web"},
+		{"tier": "db"},
+		{"app": "api"},
+		{"app": "web"},
+	}
+
+	values, groups := GroupByLabel("app", len(items), func(i int) map[string]string {
+		return items[i]
+	})
+
+	expectedValues := []string{"api", "web", ""}
+	if !reflect.DeepEqual(values, expectedValues) {
+		t.Errorf("Expected values %v, got %v", expectedValues, values)
+	}
+
+	expectedGroups := map[string][]int{"api": {2}, "web": {0, 3}, "": {1}}
+	if !reflect.DeepEqual(groups, expectedGroups) {
+		t.Errorf("Expected groups %v, got %v", expectedGroups, groups)
+	}
+}
+
+func TestGroupedListAddGroup(t *testing.T) {
+	list := NewGroupedList("app", nil)
+	list.AddGroup("web", "a")
+	list.AddGroup("", "b")
+
+	if list.ListMeta.TotalItems != 2 || list.GroupBy != "app" || list.Groups[1].List != "b" {
+		t.Errorf("Unexpected grouped list %#v", list)
+	}
+}
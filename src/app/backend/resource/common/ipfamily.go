@@ -0,0 +1,58 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// GetIPFamily returns family of given IP address, IPv4 or IPv6. Empty family is returned if the address is not
+// valid, e.g. for headless services.
+func GetIPFamily(ip string) v1.IPFamily {
+	parsed := net.ParseIP(ip)
+	switch {
+	case parsed == nil:
+		return ""
+	case parsed.To4() != nil:
+		return v1.IPv4Protocol
+	default:
+		return v1.IPv6Protocol
+	}
+}
+
+// GetIPFamilies returns families of given IP addresses in order of their first occurrence. Invalid addresses are
+// skipped and nil is returned if none is valid. Dual-stack resources have both families.
+func GetIPFamilies(ips []string) []v1.IPFamily {
+	var families []v1.IPFamily
+	for _, ip := range ips {
+		family := GetIPFamily(ip)
+		if len(family) == 0 || containsIPFamily(families, family) {
+			continue
+		}
+		families = append(families, family)
+	}
+	return families
+}
+
+func containsIPFamily(families []v1.IPFamily, family v1.IPFamily) bool {
+	for _, f := range families {
+		if f == family {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetIPFamilies(t *testing.T) {
+	cases := []struct {
+		ips      []string
+		expected []v1.IPFamily
+	}{
+		{nil, nil},
+		{[]string{"None", ""}, nil},
+		{[]string{"10.0.0.1", "10.0.0.2"}, []v1.IPFamily{v1.IPv4Protocol}},
+		{[]string{"fd00::1", "10.0.0.1"}, []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol}},
+		{[]string{"::ffff:10.0.0.1"}, []v1.IPFamily{v1.IPv4Protocol}},
+	}
+
+	for _, c := range cases {
+		if actual := GetIPFamilies(c.ips); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetIPFamilies(%v) == %v, expected %v", c.ips, actual, c.expected)
+		}
+	}
+}
@@ -24,16 +24,24 @@ import api "k8s.io/api/core/v1"
 //    filtered here.
 type NamespaceQuery struct {
 	namespaces []string
+	// restricted queries without namespaces match no namespaces instead of all of them.
+	restricted bool
 }
 
 // NewSameNamespaceQuery creates new namespace query that queries single namespace.
 func NewSameNamespaceQuery(namespace string) *NamespaceQuery {
-	return &NamespaceQuery{[]string{namespace}}
+	return &NamespaceQuery{namespaces: []string{namespace}}
 }
 
 // NewNamespaceQuery creates new query for given namespaces.
 func NewNamespaceQuery(namespaces []string) *NamespaceQuery {
-	return &NamespaceQuery{namespaces}
+	return &NamespaceQuery{namespaces: namespaces}
+}
+
+// NewRestrictedNamespaceQuery creates new query that never matches other namespaces than given ones, even if
+// there are none. It is used for users that can see only a subset of namespaces.
+func NewRestrictedNamespaceQuery(namespaces []string) *NamespaceQuery {
+	return &NamespaceQuery{namespaces: namespaces, restricted: true}
 }
 
 // ToRequestParam returns K8s API namespace query for list of objects from this namespaces.
@@ -46,14 +54,15 @@ func (n *NamespaceQuery) ToRequestParam() string {
 	return api.NamespaceAll
 }
 
-// Matches returns true when the given namespace matches this query.
+// Matches returns true when the given namespace matches this query. Empty namespace of the query matches all of
+// them, the same way as in requests to K8s API.
 func (n *NamespaceQuery) Matches(namespace string) bool {
 	if len(n.namespaces) == 0 {
-		return true
+		return !n.restricted
 	}
 
 	for _, queryNamespace := range n.namespaces {
-		if namespace == queryNamespace {
+		if namespace == queryNamespace || queryNamespace == api.NamespaceAll {
 			return true
 		}
 	}
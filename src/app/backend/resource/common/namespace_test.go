@@ -69,3 +69,21 @@ func TestMatches(t *testing.T) {
 		t.Error("Expected kube-system not to match")
 	}
 }
+
+func TestRestrictedMatches(t *testing.T) {
+	nsQ := NewRestrictedNamespaceQuery(nil)
+	if nsQ.Matches("foo") {
+		t.Error("Expected foo not to match")
+	}
+	if nsQ.ToRequestParam() != "" {
+		t.Errorf("Expected %s to be ''", nsQ.ToRequestParam())
+	}
+
+	nsQ = NewRestrictedNamespaceQuery([]string{"foo", "bar"})
+	if !nsQ.Matches("foo") {
+		t.Error("Expected foo to match")
+	}
+	if nsQ.Matches("baz") {
+		t.Error("Expected baz not to match")
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// OSLinux is the operating system label value of Linux nodes.
+	OSLinux = "linux"
+	// OSWindows is the operating system label value of Windows nodes.
+	OSWindows = "windows"
+)
+
+// Well-known labels set by kubelet. Beta labels are kept for clusters older than 1.14.
+var (
+	osLabels   = []string{v1.LabelOSStable, "beta.kubernetes.io/os"}
+	archLabels = []string{v1.LabelArchStable, "beta.kubernetes.io/arch"}
+)
+
+// GetNodeOS returns operating system of the node. Labels set by kubelet are preferred over node info, so that
+// the value matches node selectors of pods.
+func GetNodeOS(node *v1.Node) string {
+	if os := getLabelValue(node.Labels, osLabels); len(os) > 0 {
+		return os
+	}
+	return node.Status.NodeInfo.OperatingSystem
+}
+
+// GetNodeArchitecture returns CPU architecture of the node.
+func GetNodeArchitecture(node *v1.Node) string {
+	if arch := getLabelValue(node.Labels, archLabels); len(arch) > 0 {
+		return arch
+	}
+	return node.Status.NodeInfo.Architecture
+}
+
+// GetPodOS returns operating system of containers of the pod. It is taken from the node selector of the pod or,
+// if the pod does not select it, from the node running the pod. Empty string is returned if it is unknown.
+func GetPodOS(client kubernetes.Interface, pod *v1.Pod) string {
+	if os := getLabelValue(pod.Spec.NodeSelector, osLabels); len(os) > 0 {
+		return os
+	}
+
+	if len(pod.Spec.NodeName) == 0 {
+		return ""
+	}
+
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), pod.Spec.NodeName, metaV1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	return GetNodeOS(node)
+}
+
+func getLabelValue(labels map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value, ok := labels[key]; ok && len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
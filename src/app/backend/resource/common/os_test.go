@@ -0,0 +1,88 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetNodeOS(t *testing.T) {
+	cases := []struct {
+		info         string
+		node         *v1.Node
+		expectedOS   string
+		expectedArch string
+	}{
+		{
+			"labels are preferred",
+			&v1.Node{
+				ObjectMeta: metaV1.ObjectMeta{Labels: map[string]string{
+					v1.LabelOSStable: OSWindows, v1.LabelArchStable: "amd64"}},
+				Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{OperatingSystem: "linux", Architecture: "arm64"}},
+			},
+			OSWindows, "amd64",
+		},
+		{
+			"beta labels",
+			&v1.Node{ObjectMeta: metaV1.ObjectMeta{Labels: map[string]string{
+				"beta.kubernetes.io/os": OSWindows, "beta.kubernetes.io/arch": "amd64"}}},
+			OSWindows, "amd64",
+		},
+		{
+			"node info without labels",
+			&v1.Node{Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{OperatingSystem: OSLinux,
+				Architecture: "arm64"}}},
+			OSLinux, "arm64",
+		},
+	}
+
+	for _, c := range cases {
+		if os := GetNodeOS(c.node); os != c.expectedOS {
+			t.Errorf("%s: GetNodeOS() == %s, expected %s", c.info, os, c.expectedOS)
+		}
+
+		if arch := GetNodeArchitecture(c.node); arch != c.expectedArch {
+			t.Errorf("%s: GetNodeArchitecture() == %s, expected %s", c.info, arch, c.expectedArch)
+		}
+	}
+}
+
+func TestGetPodOS(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: "win-1", Labels: map[string]string{v1.LabelOSStable: OSWindows}},
+	})
+
+	cases := []struct {
+		info     string
+		pod      *v1.Pod
+		expected string
+	}{
+		{"node selector", &v1.Pod{Spec: v1.PodSpec{NodeSelector: map[string]string{v1.LabelOSStable: OSLinux},
+			NodeName: "win-1"}}, OSLinux},
+		{"node of the pod", &v1.Pod{Spec: v1.PodSpec{NodeName: "win-1"}}, OSWindows},
+		{"missing node", &v1.Pod{Spec: v1.PodSpec{NodeName: "missing"}}, ""},
+		{"pending pod", &v1.Pod{}, ""},
+	}
+
+	for _, c := range cases {
+		if os := GetPodOS(client, c.pod); os != c.expected {
+			t.Errorf("%s: GetPodOS() == %s, expected %s", c.info, os, c.expected)
+		}
+	}
+}
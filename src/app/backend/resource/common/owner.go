@@ -0,0 +1,56 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"strings"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+// ResourceOwner identifies the top level controller of a resource.
+type ResourceOwner struct {
+	Kind      api.ResourceKind `json:"kind"`
+	Namespace string           `json:"namespace"`
+	Name      string           `json:"name"`
+}
+
+// GetPodOwner returns the top level controller of given pod. Pods controlled by a replica set are attributed
+// to the deployment that controls the replica set, if it is on the given list. Pods without a controller are
+// their own owner.
+func GetPodOwner(pod v1.Pod, replicaSets []apps.ReplicaSet) ResourceOwner {
+	ref := metav1.GetControllerOf(&pod)
+	if ref == nil {
+		return ResourceOwner{Kind: api.ResourceKindPod, Namespace: pod.Namespace, Name: pod.Name}
+	}
+
+	if ref.Kind == "ReplicaSet" {
+		for _, rs := range replicaSets {
+			if rs.Namespace != pod.Namespace || rs.UID != ref.UID {
+				continue
+			}
+			if rsRef := metav1.GetControllerOf(&rs); rsRef != nil && rsRef.Kind == "Deployment" {
+				return ResourceOwner{Kind: api.ResourceKindDeployment, Namespace: pod.Namespace, Name: rsRef.Name}
+			}
+		}
+	}
+
+	return ResourceOwner{Kind: api.ResourceKind(strings.ToLower(ref.Kind)), Namespace: pod.Namespace,
+		Name: ref.Name}
+}
@@ -0,0 +1,27 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+)
+
+// IsProtected returns true if object of given kind is protected by Dashboard configuration and should be hidden from
+// lists.
+func IsProtected(kind string, meta metaV1.ObjectMeta) bool {
+	return authApi.IsProtected(kind, meta.Namespace, meta.Name, meta.Labels)
+}
@@ -32,6 +32,7 @@ import (
 	client "k8s.io/client-go/kubernetes"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/compat"
 )
 
 // ResourceChannels struct holds channels to resource lists. Each list channel is paired with
@@ -174,7 +175,7 @@ func GetIngressListChannel(client client.Interface, nsQuery *NamespaceQuery,
 		Error: make(chan error, numReads),
 	}
 	go func() {
-		list, err := client.ExtensionsV1beta1().Ingresses(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
+		list, err := compat.ListIngresses(client, nsQuery.ToRequestParam(), api.ListEverything)
 		var filteredItems []extensions.Ingress
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -209,6 +210,13 @@ func GetLimitRangeListChannel(client client.Interface, nsQuery *NamespaceQuery,
 
 	go func() {
 		list, err := client.CoreV1().LimitRanges(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
+		var filteredItems []v1.LimitRange
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -328,7 +336,13 @@ func GetEndpointListChannelWithOptions(client client.Interface,
 
 	go func() {
 		list, err := client.CoreV1().Endpoints(nsQuery.ToRequestParam()).List(context.TODO(), opt)
-
+		var filteredItems []v1.Endpoints
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -364,7 +378,7 @@ func GetPodListChannelWithOptions(client client.Interface, nsQuery *NamespaceQue
 		list, err := client.CoreV1().Pods(nsQuery.ToRequestParam()).List(context.TODO(), options)
 		var filteredItems []v1.Pod
 		for _, item := range list.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) && !IsProtected(api.ResourceKindPod, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -400,7 +414,8 @@ func GetReplicationControllerListChannel(client client.Interface,
 			List(context.TODO(), api.ListEverything)
 		var filteredItems []v1.ReplicationController
 		for _, item := range list.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) &&
+				!IsProtected(api.ResourceKindReplicationController, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -435,7 +450,7 @@ func GetDeploymentListChannel(client client.Interface,
 			List(context.TODO(), api.ListEverything)
 		var filteredItems []apps.Deployment
 		for _, item := range list.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) && !IsProtected(api.ResourceKindDeployment, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -476,7 +491,7 @@ func GetReplicaSetListChannelWithOptions(client client.Interface, nsQuery *Names
 			List(context.TODO(), options)
 		var filteredItems []apps.ReplicaSet
 		for _, item := range list.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) && !IsProtected(api.ResourceKindReplicaSet, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -508,7 +523,7 @@ func GetDaemonSetListChannel(client client.Interface, nsQuery *NamespaceQuery, n
 		list, err := client.AppsV1().DaemonSets(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
 		var filteredItems []apps.DaemonSet
 		for _, item := range list.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) && !IsProtected(api.ResourceKindDaemonSet, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -540,7 +555,7 @@ func GetJobListChannel(client client.Interface,
 		list, err := client.BatchV1().Jobs(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
 		var filteredItems []batch.Job
 		for _, item := range list.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) && !IsProtected(api.ResourceKindJob, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -568,10 +583,10 @@ func GetCronJobListChannel(client client.Interface, nsQuery *NamespaceQuery, num
 	}
 
 	go func() {
-		list, err := client.BatchV1beta1().CronJobs(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
+		list, err := compat.ListCronJobs(client, nsQuery.ToRequestParam(), api.ListEverything)
 		var filteredItems []batch2.CronJob
 		for _, item := range list.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) && !IsProtected(api.ResourceKindCronJob, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -604,7 +619,7 @@ func GetStatefulSetListChannel(client client.Interface,
 		statefulSets, err := client.AppsV1().StatefulSets(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
 		var filteredItems []apps.StatefulSet
 		for _, item := range statefulSets.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) && !IsProtected(api.ResourceKindStatefulSet, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -638,7 +653,7 @@ func GetConfigMapListChannel(client client.Interface, nsQuery *NamespaceQuery,
 		list, err := client.CoreV1().ConfigMaps(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
 		var filteredItems []v1.ConfigMap
 		for _, item := range list.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) && !IsProtected(api.ResourceKindConfigMap, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -672,7 +687,7 @@ func GetSecretListChannel(client client.Interface, nsQuery *NamespaceQuery,
 		list, err := client.CoreV1().Secrets(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
 		var filteredItems []v1.Secret
 		for _, item := range list.Items {
-			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) && !IsProtected(api.ResourceKindSecret, item.ObjectMeta) {
 				filteredItems = append(filteredItems, item)
 			}
 		}
@@ -702,6 +717,13 @@ func GetRoleListChannel(client client.Interface, nsQuery *NamespaceQuery, numRea
 
 	go func() {
 		list, err := client.RbacV1().Roles(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
+		var filteredItems []rbac.Role
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -752,6 +774,13 @@ func GetRoleBindingListChannel(client client.Interface, nsQuery *NamespaceQuery,
 
 	go func() {
 		list, err := client.RbacV1().RoleBindings(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
+		var filteredItems []rbac.RoleBinding
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -831,6 +860,13 @@ func GetPersistentVolumeClaimListChannel(client client.Interface, nsQuery *Names
 
 	go func() {
 		list, err := client.CoreV1().PersistentVolumeClaims(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
+		var filteredItems []v1.PersistentVolumeClaim
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -908,6 +944,13 @@ func GetResourceQuotaListChannel(client client.Interface, nsQuery *NamespaceQuer
 
 	go func() {
 		list, err := client.CoreV1().ResourceQuotas(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
+		var filteredItems []v1.ResourceQuota
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -935,6 +978,13 @@ func GetHorizontalPodAutoscalerListChannel(client client.Interface, nsQuery *Nam
 	go func() {
 		list, err := client.AutoscalingV1().HorizontalPodAutoscalers(nsQuery.ToRequestParam()).
 			List(context.TODO(), api.ListEverything)
+		var filteredItems []autoscaling.HorizontalPodAutoscaler
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
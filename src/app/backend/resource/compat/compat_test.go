@@ -0,0 +1,142 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	extensions "k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestParseMinor(t *testing.T) {
+	cases := map[string]int{"18": 18, "21+": 21, "": 0, "x": 0}
+	for minor, expected := range cases {
+		if actual := ParseMinor(minor); actual != expected {
+			t.Errorf("ParseMinor(%q) == %d, expected %d", minor, actual, expected)
+		}
+	}
+}
+
+func TestNewAPIVersions(t *testing.T) {
+	cases := []struct {
+		minor     int
+		cronJobs  string
+		ingresses string
+	}{
+		{0, "batch/v1beta1", "extensions/v1beta1"},
+		{18, "batch/v1beta1", "extensions/v1beta1"},
+		{19, "batch/v1beta1", "networking.k8s.io/v1"},
+		{25, "batch/v1", "networking.k8s.io/v1"},
+	}
+
+	for _, c := range cases {
+		versions := NewAPIVersions(c.minor)
+		if versions.CronJobs.String() != c.cronJobs || versions.Ingresses.String() != c.ingresses {
+			t.Errorf("NewAPIVersions(%d) == %v, expected %s and %s", c.minor, versions, c.cronJobs, c.ingresses)
+		}
+	}
+}
+
+// Returns client of a server responding to given paths with given bodies. API versions of given cluster version
+// are used until returned function is called.
+func getTestClient(t *testing.T, minor string, responses map[string]string) (kubernetes.Interface, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+
+	previous := GetAPIVersions()
+	SetServerVersion(&version.Info{Major: "1", Minor: minor})
+	cleanup := func() {
+		server.Close()
+		mux.Lock()
+		current = previous
+		mux.Unlock()
+	}
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	return client, cleanup
+}
+
+func TestListCronJobs(t *testing.T) {
+	client, cleanup := getTestClient(t, "25", map[string]string{
+		"/apis/batch/v1/namespaces/default/cronjobs": `{"apiVersion":"batch/v1","kind":"CronJobList","items":[
+			{"metadata":{"name":"backup","namespace":"default"},"spec":{"schedule":"0 * * * *","suspend":true}}]}`,
+	})
+	defer cleanup()
+
+	list, err := ListCronJobs(client, "default", metaV1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListCronJobs() returned error: %s", err)
+	}
+
+	if len(list.Items) != 1 || list.Items[0].Name != "backup" || list.Items[0].Spec.Schedule != "0 * * * *" ||
+		!*list.Items[0].Spec.Suspend {
+		t.Errorf("ListCronJobs() == %#v, expected backup cron job", list.Items)
+	}
+}
+
+func TestGetIngress(t *testing.T) {
+	client, cleanup := getTestClient(t, "22", map[string]string{
+		"/apis/networking.k8s.io/v1/namespaces/default/ingresses/web": `{"apiVersion":"networking.k8s.io/v1",
+			"kind":"Ingress","metadata":{"name":"web","namespace":"default"},"spec":{"ingressClassName":"nginx",
+			"defaultBackend":{"service":{"name":"fallback","port":{"number":8080}}},
+			"rules":[{"host":"example.com","http":{"paths":[{"path":"/","pathType":"Prefix",
+			"backend":{"service":{"name":"web","port":{"name":"http"}}}}]}}]}}`,
+	})
+	defer cleanup()
+
+	ingress, err := GetIngress(client, "default", "web")
+	if err != nil {
+		t.Fatalf("GetIngress() returned error: %s", err)
+	}
+
+	prefix := extensions.PathTypePrefix
+	expected := extensions.IngressSpec{
+		IngressClassName: ingress.Spec.IngressClassName,
+		Backend:          &extensions.IngressBackend{ServiceName: "fallback", ServicePort: intstr.FromInt(8080)},
+		Rules: []extensions.IngressRule{{
+			Host: "example.com",
+			IngressRuleValue: extensions.IngressRuleValue{HTTP: &extensions.HTTPIngressRuleValue{
+				Paths: []extensions.HTTPIngressPath{{Path: "/", PathType: &prefix,
+					Backend: extensions.IngressBackend{ServiceName: "web", ServicePort: intstr.FromString("http")}}},
+			}},
+		}},
+	}
+	if ingress.Name != "web" || *ingress.Spec.IngressClassName != "nginx" || !reflect.DeepEqual(ingress.Spec, expected) {
+		t.Errorf("GetIngress() == %#v, expected %#v", ingress.Spec, expected)
+	}
+
+	if _, err := GetIngress(client, "default", "missing"); err == nil {
+		t.Error("expected error for missing ingress")
+	}
+}
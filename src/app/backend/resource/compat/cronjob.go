@@ -0,0 +1,74 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compat
+
+import (
+	"context"
+	"encoding/json"
+
+	batch2 "k8s.io/api/batch/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// CronJobRESTClient returns REST client of the API group version serving cron jobs.
+func CronJobRESTClient(client kubernetes.Interface) rest.Interface {
+	if GetAPIVersions().CronJobs == batchV1 {
+		return client.BatchV1().RESTClient()
+	}
+	return client.BatchV1beta1().RESTClient()
+}
+
+// ListCronJobs lists cron jobs in given namespace. Cron jobs of batch/v1 have the same fields as the ones of
+// batch/v1beta1, except for the newer status fields, which are not used.
+func ListCronJobs(client kubernetes.Interface, namespace string, options metaV1.ListOptions) (*batch2.CronJobList,
+	error) {
+	if GetAPIVersions().CronJobs != batchV1 {
+		return client.BatchV1beta1().CronJobs(namespace).List(context.TODO(), options)
+	}
+
+	result := &batch2.CronJobList{}
+	raw, err := client.BatchV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("cronjobs").
+		VersionedParams(&options, scheme.ParameterCodec).
+		DoRaw(context.TODO())
+	if err != nil {
+		return result, err
+	}
+
+	return result, json.Unmarshal(raw, result)
+}
+
+// GetCronJob returns cron job with given name.
+func GetCronJob(client kubernetes.Interface, namespace, name string) (*batch2.CronJob, error) {
+	if GetAPIVersions().CronJobs != batchV1 {
+		return client.BatchV1beta1().CronJobs(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	}
+
+	result := &batch2.CronJob{}
+	raw, err := client.BatchV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("cronjobs").
+		Name(name).
+		DoRaw(context.TODO())
+	if err != nil {
+		return result, err
+	}
+
+	return result, json.Unmarshal(raw, result)
+}
@@ -0,0 +1,181 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compat
+
+import (
+	"context"
+	"encoding/json"
+
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// ingressV1 holds fields of networking.k8s.io/v1 ingress, that differ from extensions/v1beta1 one. Client of the
+// supported K8s version does not have types of the newer API.
+type ingressV1 struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ingressSpecV1            `json:"spec,omitempty"`
+	Status            extensions.IngressStatus `json:"status,omitempty"`
+}
+
+type ingressListV1 struct {
+	metaV1.TypeMeta `json:",inline"`
+	metaV1.ListMeta `json:"metadata,omitempty"`
+	Items           []ingressV1 `json:"items"`
+}
+
+type ingressSpecV1 struct {
+	IngressClassName *string                 `json:"ingressClassName,omitempty"`
+	DefaultBackend   *ingressBackendV1       `json:"defaultBackend,omitempty"`
+	TLS              []extensions.IngressTLS `json:"tls,omitempty"`
+	Rules            []ingressRuleV1         `json:"rules,omitempty"`
+}
+
+type ingressRuleV1 struct {
+	Host string `json:"host,omitempty"`
+	HTTP *struct {
+		Paths []ingressPathV1 `json:"paths"`
+	} `json:"http,omitempty"`
+}
+
+type ingressPathV1 struct {
+	Path     string               `json:"path,omitempty"`
+	PathType *extensions.PathType `json:"pathType,omitempty"`
+	Backend  ingressBackendV1     `json:"backend"`
+}
+
+type ingressBackendV1 struct {
+	Service *struct {
+		Name string `json:"name"`
+		Port struct {
+			Name   string `json:"name,omitempty"`
+			Number int32  `json:"number,omitempty"`
+		} `json:"port,omitempty"`
+	} `json:"service,omitempty"`
+	Resource *v1.TypedLocalObjectReference `json:"resource,omitempty"`
+}
+
+// IngressRESTClient returns REST client of the API group version serving ingresses.
+func IngressRESTClient(client kubernetes.Interface) rest.Interface {
+	if GetAPIVersions().Ingresses == networkingV1 {
+		return client.NetworkingV1().RESTClient()
+	}
+	return client.ExtensionsV1beta1().RESTClient()
+}
+
+// ListIngresses lists ingresses in given namespace. Ingresses of networking.k8s.io/v1 are converted to
+// extensions/v1beta1 ones.
+func ListIngresses(client kubernetes.Interface, namespace string, options metaV1.ListOptions) (
+	*extensions.IngressList, error) {
+	if GetAPIVersions().Ingresses != networkingV1 {
+		return client.ExtensionsV1beta1().Ingresses(namespace).List(context.TODO(), options)
+	}
+
+	result := &extensions.IngressList{}
+	raw, err := client.NetworkingV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("ingresses").
+		VersionedParams(&options, scheme.ParameterCodec).
+		DoRaw(context.TODO())
+	if err != nil {
+		return result, err
+	}
+
+	list := &ingressListV1{}
+	if err := json.Unmarshal(raw, list); err != nil {
+		return result, err
+	}
+
+	result.ListMeta = list.ListMeta
+	for _, item := range list.Items {
+		result.Items = append(result.Items, toIngress(item))
+	}
+	return result, nil
+}
+
+// GetIngress returns ingress with given name.
+func GetIngress(client kubernetes.Interface, namespace, name string) (*extensions.Ingress, error) {
+	if GetAPIVersions().Ingresses != networkingV1 {
+		return client.ExtensionsV1beta1().Ingresses(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	}
+
+	raw, err := client.NetworkingV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("ingresses").
+		Name(name).
+		DoRaw(context.TODO())
+	if err != nil {
+		return &extensions.Ingress{}, err
+	}
+
+	ingress := ingressV1{}
+	if err := json.Unmarshal(raw, &ingress); err != nil {
+		return &extensions.Ingress{}, err
+	}
+
+	result := toIngress(ingress)
+	return &result, nil
+}
+
+func toIngress(ingress ingressV1) extensions.Ingress {
+	result := extensions.Ingress{
+		TypeMeta:   ingress.TypeMeta,
+		ObjectMeta: ingress.ObjectMeta,
+		Spec: extensions.IngressSpec{
+			IngressClassName: ingress.Spec.IngressClassName,
+			Backend:          toIngressBackend(ingress.Spec.DefaultBackend),
+			TLS:              ingress.Spec.TLS,
+		},
+		Status: ingress.Status,
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		converted := extensions.IngressRule{Host: rule.Host}
+		if rule.HTTP != nil {
+			converted.HTTP = &extensions.HTTPIngressRuleValue{Paths: make([]extensions.HTTPIngressPath, 0)}
+			for _, path := range rule.HTTP.Paths {
+				converted.HTTP.Paths = append(converted.HTTP.Paths, extensions.HTTPIngressPath{
+					Path:     path.Path,
+					PathType: path.PathType,
+					Backend:  *toIngressBackend(&path.Backend),
+				})
+			}
+		}
+		result.Spec.Rules = append(result.Spec.Rules, converted)
+	}
+	return result
+}
+
+func toIngressBackend(backend *ingressBackendV1) *extensions.IngressBackend {
+	if backend == nil {
+		return nil
+	}
+
+	result := &extensions.IngressBackend{Resource: backend.Resource}
+	if backend.Service != nil {
+		result.ServiceName = backend.Service.Name
+		result.ServicePort = intstr.FromInt(int(backend.Service.Port.Number))
+		if len(backend.Service.Port.Name) > 0 {
+			result.ServicePort = intstr.FromString(backend.Service.Port.Name)
+		}
+	}
+	return result
+}
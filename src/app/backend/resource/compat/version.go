@@ -0,0 +1,97 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compat picks versions of APIs, that are not served by all supported K8s versions, and reads objects of
+// newer versions into the types used by resource modules. This way a single build works with both older clusters,
+// that do not serve the new versions yet, and newer ones, that do not serve the old versions anymore.
+package compat
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+const (
+	// CronJobV1Minor is the first minor version of K8s serving cron jobs in batch/v1.
+	CronJobV1Minor = 21
+	// IngressV1Minor is the first minor version of K8s serving ingresses in networking.k8s.io/v1.
+	IngressV1Minor = 19
+)
+
+var (
+	batchV1           = schema.GroupVersion{Group: "batch", Version: "v1"}
+	batchV1beta1      = schema.GroupVersion{Group: "batch", Version: "v1beta1"}
+	networkingV1      = schema.GroupVersion{Group: "networking.k8s.io", Version: "v1"}
+	extensionsV1beta1 = schema.GroupVersion{Group: "extensions", Version: "v1beta1"}
+)
+
+// APIVersions are versions of APIs used for resources with different versions across supported K8s versions.
+type APIVersions struct {
+	// Minor version of the cluster. It is 0 if the version is not known.
+	Minor     int
+	CronJobs  schema.GroupVersion
+	Ingresses schema.GroupVersion
+}
+
+// NewAPIVersions returns API versions used with clusters of given minor version. The newest version served by
+// the cluster is always used, so that they keep working after the cluster is upgraded.
+func NewAPIVersions(minor int) APIVersions {
+	result := APIVersions{Minor: minor, CronJobs: batchV1beta1, Ingresses: extensionsV1beta1}
+	if minor >= CronJobV1Minor {
+		result.CronJobs = batchV1
+	}
+	if minor >= IngressV1Minor {
+		result.Ingresses = networkingV1
+	}
+	return result
+}
+
+var (
+	current = NewAPIVersions(0)
+	mux     sync.RWMutex
+)
+
+// SetServerVersion sets API versions used for the cluster of given version. Until it is set, the oldest versions
+// are used.
+func SetServerVersion(info *version.Info) {
+	versions := NewAPIVersions(ParseMinor(info.Minor))
+
+	mux.Lock()
+	defer mux.Unlock()
+	current = versions
+	log.Printf("Using %s cron jobs and %s ingresses", versions.CronJobs, versions.Ingresses)
+}
+
+// GetAPIVersions returns API versions used for the cluster.
+func GetAPIVersions() APIVersions {
+	mux.RLock()
+	defer mux.RUnlock()
+	return current
+}
+
+// ParseMinor parses minor version reported by the cluster. Some providers append a suffix to it, i.e. "21+".
+// It returns 0 if the version can not be parsed.
+func ParseMinor(minor string) int {
+	digits := strings.TrimRightFunc(minor, func(r rune) bool { return r < '0' || r > '9' })
+	result, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+	return result
+}
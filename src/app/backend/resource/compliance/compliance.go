@@ -0,0 +1,395 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Sources of compliance results.
+const (
+	// SourceKubeBenchReport means that results come from CISKubeBenchReport objects created by the Aqua operators.
+	SourceKubeBenchReport = "ciskubebenchreport"
+	// SourceClusterComplianceReport means that results come from ClusterComplianceReport objects of trivy-operator.
+	SourceClusterComplianceReport = "clustercompliancereport"
+	// SourceKubeBenchJob means that results were parsed from the JSON output of kube-bench job pods.
+	SourceKubeBenchJob = "kubebenchjob"
+)
+
+// Check statuses reported by kube-bench.
+const (
+	StatusPass = "PASS"
+	StatusFail = "FAIL"
+	StatusWarn = "WARN"
+	StatusInfo = "INFO"
+)
+
+// KubeBenchJobSelector selects pods of kube-bench jobs, which output is parsed when no reports are available.
+const KubeBenchJobSelector = "app=kube-bench"
+
+// maxJobOutputBytes limits the size of kube-bench output read from a single pod.
+const maxJobOutputBytes = 4 * 1024 * 1024
+
+var (
+	kubeBenchReportResource = schema.GroupVersionResource{Group: "aquasecurity.github.io", Version: "v1alpha1",
+		Resource: "ciskubebenchreports"}
+	clusterComplianceReportResource = schema.GroupVersionResource{Group: "aquasecurity.github.io",
+		Version: "v1alpha1", Resource: "clustercompliancereports"}
+)
+
+// CheckCounts contains the number of checks by their status.
+type CheckCounts struct {
+	Pass int `json:"pass"`
+	Fail int `json:"fail"`
+	Warn int `json:"warn"`
+	Info int `json:"info"`
+}
+
+// add adds counts of other to c.
+func (c *CheckCounts) add(other CheckCounts) {
+	c.Pass += other.Pass
+	c.Fail += other.Fail
+	c.Warn += other.Warn
+	c.Info += other.Info
+}
+
+// Score returns percentage of passed checks out of the passed and failed ones. Checks, that require manual
+// verification, are not taken into account.
+func (c CheckCounts) Score() float64 {
+	if c.Pass+c.Fail == 0 {
+		return 100
+	}
+	return float64(c.Pass) * 100 / float64(c.Pass+c.Fail)
+}
+
+// FailedCheck is a single failed benchmark check.
+type FailedCheck struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Severity    string `json:"severity,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// NodeCompliance contains benchmark results of a single node.
+type NodeCompliance struct {
+	Node         string        `json:"node"`
+	Source       string        `json:"source"`
+	Benchmark    string        `json:"benchmark,omitempty"`
+	Timestamp    metaV1.Time   `json:"timestamp"`
+	Counts       CheckCounts   `json:"counts"`
+	Score        float64       `json:"score"`
+	FailedChecks []FailedCheck `json:"failedChecks"`
+}
+
+// ClusterCompliance contains results of a cluster-wide compliance report.
+type ClusterCompliance struct {
+	Name         string        `json:"name"`
+	Source       string        `json:"source"`
+	Benchmark    string        `json:"benchmark,omitempty"`
+	Timestamp    metaV1.Time   `json:"timestamp"`
+	Counts       CheckCounts   `json:"counts"`
+	Score        float64       `json:"score"`
+	FailedChecks []FailedCheck `json:"failedChecks"`
+}
+
+// ComplianceSummary contains compliance results of all nodes and cluster-wide reports. Counts and score
+// aggregate results of all of them.
+type ComplianceSummary struct {
+	Counts  CheckCounts         `json:"counts"`
+	Score   float64             `json:"score"`
+	Nodes   []NodeCompliance    `json:"nodes"`
+	Cluster []ClusterCompliance `json:"cluster"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetComplianceSummary collects CIS benchmark results from reports of the Aqua operators. Nodes without
+// a report fall back to the output of kube-bench job pods. Sources, that are not installed, are skipped.
+func GetComplianceSummary(client kubernetes.Interface, dynamicClient dynamic.Interface) (*ComplianceSummary, error) {
+	summary := &ComplianceSummary{Nodes: []NodeCompliance{}, Cluster: []ClusterCompliance{}, Errors: []error{}}
+
+	nodes, err := getKubeBenchReports(dynamicClient)
+	summary.Errors, err = errors.AppendError(ignoreNotInstalled(err), summary.Errors)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNodes, err := getKubeBenchJobResults(client)
+	summary.Errors, err = errors.AppendError(err, summary.Errors)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range jobNodes {
+		if _, ok := nodes[node.Node]; !ok {
+			nodes[node.Node] = node
+		}
+	}
+
+	cluster, err := getClusterComplianceReports(dynamicClient)
+	summary.Errors, err = errors.AppendError(ignoreNotInstalled(err), summary.Errors)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		summary.Nodes = append(summary.Nodes, node)
+		summary.Counts.add(node.Counts)
+	}
+	sort.Slice(summary.Nodes, func(i, j int) bool { return summary.Nodes[i].Node < summary.Nodes[j].Node })
+
+	for _, report := range cluster {
+		summary.Cluster = append(summary.Cluster, report)
+		summary.Counts.add(report.Counts)
+	}
+
+	summary.Score = summary.Counts.Score()
+	return summary, nil
+}
+
+// GetNodeCompliance returns benchmark results of a single node.
+func GetNodeCompliance(client kubernetes.Interface, dynamicClient dynamic.Interface, name string) (
+	*NodeCompliance, error) {
+	summary, err := GetComplianceSummary(client, dynamicClient)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range summary.Nodes {
+		if node.Node == name {
+			return &node, nil
+		}
+	}
+
+	return nil, errors.NewNotFound("no compliance results found for node " + name)
+}
+
+// ignoreNotInstalled drops errors returned when the CRD of a report is not installed.
+func ignoreNotInstalled(err error) error {
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// getKubeBenchReports returns results of CISKubeBenchReport objects mapped by node names.
+func getKubeBenchReports(dynamicClient dynamic.Interface) (map[string]NodeCompliance, error) {
+	result := make(map[string]NodeCompliance)
+	list, err := dynamicClient.Resource(kubeBenchReportResource).List(context.TODO(), api.ListEverything)
+	if err != nil {
+		return result, err
+	}
+
+	for _, item := range list.Items {
+		node := NodeCompliance{
+			Node:         item.GetLabels()["starboard.resource.name"],
+			Source:       SourceKubeBenchReport,
+			Timestamp:    item.GetCreationTimestamp(),
+			FailedChecks: []FailedCheck{},
+		}
+		if len(node.Node) == 0 {
+			node.Node = item.GetName()
+		}
+		if updated, found, _ := unstructured.NestedString(item.Object, "report", "updateTimestamp"); found {
+			_ = node.Timestamp.UnmarshalQueryParameter(updated)
+		}
+
+		sections, _, _ := unstructured.NestedSlice(item.Object, "report", "sections")
+		for _, section := range sections {
+			s, ok := section.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			tests, _, _ := unstructured.NestedSlice(s, "tests")
+			for _, test := range tests {
+				t, ok := test.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				results, _, _ := unstructured.NestedSlice(t, "results")
+				for _, r := range results {
+					if check, ok := r.(map[string]interface{}); ok {
+						node.addCheck(nestedString(check, "test_number"), nestedString(check, "test_desc"),
+							nestedString(check, "status"), nestedString(check, "remediation"))
+					}
+				}
+			}
+		}
+
+		node.Score = node.Counts.Score()
+		result[node.Node] = node
+	}
+
+	return result, nil
+}
+
+// getClusterComplianceReports returns results of trivy-operator ClusterComplianceReport objects.
+func getClusterComplianceReports(dynamicClient dynamic.Interface) ([]ClusterCompliance, error) {
+	result := make([]ClusterCompliance, 0)
+	list, err := dynamicClient.Resource(clusterComplianceReportResource).List(context.TODO(), api.ListEverything)
+	if err != nil {
+		return result, err
+	}
+
+	for _, item := range list.Items {
+		report := ClusterCompliance{
+			Name:         item.GetName(),
+			Source:       SourceClusterComplianceReport,
+			Timestamp:    item.GetCreationTimestamp(),
+			FailedChecks: []FailedCheck{},
+		}
+		if updated, found, _ := unstructured.NestedString(item.Object, "status", "updateTimestamp"); found {
+			_ = report.Timestamp.UnmarshalQueryParameter(updated)
+		}
+		report.Benchmark, _, _ = unstructured.NestedString(item.Object, "spec", "compliance", "title")
+
+		pass, _, _ := unstructured.NestedInt64(item.Object, "status", "summary", "passCount")
+		fail, _, _ := unstructured.NestedInt64(item.Object, "status", "summary", "failCount")
+		report.Counts = CheckCounts{Pass: int(pass), Fail: int(fail)}
+
+		controls, _, _ := unstructured.NestedSlice(item.Object, "status", "summaryReport", "controlCheck")
+		for _, control := range controls {
+			c, ok := control.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if failed, _, _ := unstructured.NestedInt64(c, "totalFail"); failed > 0 {
+				report.FailedChecks = append(report.FailedChecks, FailedCheck{
+					ID:          nestedString(c, "id"),
+					Description: nestedString(c, "name"),
+					Severity:    nestedString(c, "severity"),
+				})
+			}
+		}
+
+		report.Score = report.Counts.Score()
+		result = append(result, report)
+	}
+
+	return result, nil
+}
+
+// kubeBenchOutput is the JSON output of kube-bench run with the --json flag.
+type kubeBenchOutput struct {
+	Controls []kubeBenchControls `json:"Controls"`
+}
+
+type kubeBenchControls struct {
+	Version string `json:"version"`
+	Tests   []struct {
+		Results []struct {
+			TestNumber  string `json:"test_number"`
+			TestDesc    string `json:"test_desc"`
+			Status      string `json:"status"`
+			Remediation string `json:"remediation"`
+		} `json:"results"`
+	} `json:"tests"`
+}
+
+// getKubeBenchJobResults parses output of finished kube-bench job pods mapped by names of their nodes. Only
+// the latest pod of every node is used.
+func getKubeBenchJobResults(client kubernetes.Interface) (map[string]NodeCompliance, error) {
+	result := make(map[string]NodeCompliance)
+	pods, err := client.CoreV1().Pods(v1.NamespaceAll).List(context.TODO(),
+		metaV1.ListOptions{LabelSelector: KubeBenchJobSelector})
+	if err != nil {
+		return result, err
+	}
+
+	limit := int64(maxJobOutputBytes)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodSucceeded || len(pod.Spec.NodeName) == 0 {
+			continue
+		}
+		if current, ok := result[pod.Spec.NodeName]; ok && !current.Timestamp.Before(&pod.CreationTimestamp) {
+			continue
+		}
+
+		raw, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{LimitBytes: &limit}).
+			DoRaw(context.TODO())
+		if err != nil {
+			return result, err
+		}
+
+		node, ok := parseKubeBenchOutput(raw)
+		if !ok {
+			continue
+		}
+		node.Node = pod.Spec.NodeName
+		node.Timestamp = pod.CreationTimestamp
+		result[node.Node] = *node
+	}
+
+	return result, nil
+}
+
+// parseKubeBenchOutput parses kube-bench JSON output. Older versions print the list of controls directly,
+// newer ones wrap it together with totals.
+func parseKubeBenchOutput(raw []byte) (*NodeCompliance, bool) {
+	output := kubeBenchOutput{}
+	if err := json.Unmarshal(raw, &output); err != nil || len(output.Controls) == 0 {
+		if err := json.Unmarshal(raw, &output.Controls); err != nil || len(output.Controls) == 0 {
+			return nil, false
+		}
+	}
+
+	node := &NodeCompliance{Source: SourceKubeBenchJob, FailedChecks: []FailedCheck{}}
+	for _, controls := range output.Controls {
+		node.Benchmark = controls.Version
+		for _, test := range controls.Tests {
+			for _, r := range test.Results {
+				node.addCheck(r.TestNumber, r.TestDesc, r.Status, r.Remediation)
+			}
+		}
+	}
+
+	node.Score = node.Counts.Score()
+	return node, true
+}
+
+// addCheck counts a check result and records it if it failed.
+func (n *NodeCompliance) addCheck(id, description, status, remediation string) {
+	switch strings.ToUpper(status) {
+	case StatusPass:
+		n.Counts.Pass++
+	case StatusFail:
+		n.Counts.Fail++
+		n.FailedChecks = append(n.FailedChecks, FailedCheck{ID: id, Description: description,
+			Remediation: remediation})
+	case StatusWarn:
+		n.Counts.Warn++
+	case StatusInfo:
+		n.Counts.Info++
+	}
+}
+
+func nestedString(obj map[string]interface{}, field string) string {
+	value, _, _ := unstructured.NestedString(obj, field)
+	return value
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKubeBenchOutput(t *testing.T) {
+	results := `{"version":"cis-1.6","tests":[{"section":"4.1","results":[
+		{"test_number":"4.1.1","test_desc":"Ensure that the kubelet service file permissions are set","status":"PASS"},
+		{"test_number":"4.2.1","test_desc":"Ensure that anonymous auth is disabled","status":"FAIL",
+		 "remediation":"Set --anonymous-auth=false"},
+		{"test_number":"4.2.8","test_desc":"Ensure that event qps is set","status":"WARN"}]}]}`
+
+	expected := &NodeCompliance{
+		Source:    SourceKubeBenchJob,
+		Benchmark: "cis-1.6",
+		Counts:    CheckCounts{Pass: 1, Fail: 1, Warn: 1},
+		Score:     50,
+		FailedChecks: []FailedCheck{{
+			ID:          "4.2.1",
+			Description: "Ensure that anonymous auth is disabled",
+			Remediation: "Set --anonymous-auth=false",
+		}},
+	}
+
+	cases := []struct {
+		name   string
+		output string
+		ok     bool
+	}{
+		{"wrapped", `{"Controls":[` + results + `],"Totals":{"total_pass":1}}`, true},
+		{"legacy", `[` + results + `]`, true},
+		{"not json", "[INFO] 4 Worker Node Security Configuration", false},
+	}
+
+	for _, c := range cases {
+		actual, ok := parseKubeBenchOutput([]byte(c.output))
+		if ok != c.ok {
+			t.Errorf("%s: parseKubeBenchOutput() ok == %v, expected %v", c.name, ok, c.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: parseKubeBenchOutput() == %#v, expected %#v", c.name, actual, expected)
+		}
+	}
+}
+
+func TestCheckCountsScore(t *testing.T) {
+	cases := []struct {
+		counts   CheckCounts
+		expected float64
+	}{
+		{CheckCounts{}, 100},
+		{CheckCounts{Pass: 3, Fail: 1, Warn: 10}, 75},
+		{CheckCounts{Fail: 2, Info: 1}, 0},
+	}
+
+	for _, c := range cases {
+		if actual := c.counts.Score(); actual != c.expected {
+			t.Errorf("%#v.Score() == %v, expected %v", c.counts, actual, c.expected)
+		}
+	}
+}
@@ -21,6 +21,9 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 )
 
 // ConfigMapDetail API resource provides mechanisms to inject containers with configuration data while keeping
@@ -32,6 +35,10 @@ type ConfigMapDetail struct {
 	// Data contains the configuration data.
 	// Each key must be a valid DNS_SUBDOMAIN with an optional leading dot.
 	Data map[string]string `json:"data,omitempty"`
+
+	// DataInfo describes values of Data that have been truncated and values of BinaryData, which are not
+	// included. They can be fetched in slices.
+	DataInfo map[string]common.DataValueInfo `json:"dataInfo,omitempty"`
 }
 
 // GetConfigMapDetail returns detailed information about a config map
@@ -47,9 +54,49 @@ func GetConfigMapDetail(client kubernetes.Interface, namespace, name string) (*C
 	return getConfigMapDetail(rawConfigMap), nil
 }
 
+// GetConfigMapDataSlice returns a slice of the config map value with given key. Both data and binary data are
+// searched for the key.
+func GetConfigMapDataSlice(client kubernetes.Interface, namespace, name, key string, offset,
+	length int) (*common.DataValueSlice, error) {
+	rawConfigMap, err := client.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if value, exists := rawConfigMap.Data[key]; exists {
+		return common.GetDataValueSlice(key, []byte(value), offset, length), nil
+	}
+
+	if value, exists := rawConfigMap.BinaryData[key]; exists {
+		return common.GetDataValueSlice(key, value, offset, length), nil
+	}
+
+	return nil, errors.NewNotFound("config map has no key " + key)
+}
+
 func getConfigMapDetail(rawConfigMap *v1.ConfigMap) *ConfigMapDetail {
-	return &ConfigMapDetail{
-		ConfigMap: toConfigMap(rawConfigMap.ObjectMeta),
-		Data:      rawConfigMap.Data,
+	detail := &ConfigMapDetail{ConfigMap: toConfigMap(rawConfigMap.ObjectMeta)}
+	dataInfo := make(map[string]common.DataValueInfo)
+
+	if rawConfigMap.Data != nil {
+		detail.Data = make(map[string]string, len(rawConfigMap.Data))
+	}
+
+	for key, value := range rawConfigMap.Data {
+		truncated, info := common.TruncateDataValue([]byte(value))
+		detail.Data[key] = string(truncated)
+		if info != nil {
+			dataInfo[key] = *info
+		}
 	}
+
+	for key, value := range rawConfigMap.BinaryData {
+		dataInfo[key] = common.DataValueInfo{Size: len(value), Binary: true}
+	}
+
+	if len(dataInfo) > 0 {
+		detail.DataInfo = dataInfo
+	}
+
+	return detail
 }
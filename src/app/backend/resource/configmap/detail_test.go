@@ -16,9 +16,11 @@ package configmap
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	v1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -40,6 +42,24 @@ func TestGetConfigMapDetail(t *testing.T) {
 				Data: map[string]string{"app": "my-name"},
 			},
 		},
+		{
+			&v1.ConfigMap{
+				Data:       map[string]string{"bundle": strings.Repeat("a", common.MaxDataValueSize+1)},
+				BinaryData: map[string][]byte{"archive": {0, 1, 2, 3}},
+				ObjectMeta: metaV1.ObjectMeta{Name: "foo"},
+			},
+			&ConfigMapDetail{
+				ConfigMap: ConfigMap{
+					TypeMeta:   api.TypeMeta{Kind: "configmap"},
+					ObjectMeta: api.ObjectMeta{Name: "foo"},
+				},
+				Data: map[string]string{"bundle": strings.Repeat("a", common.MaxDataValueSize)},
+				DataInfo: map[string]common.DataValueInfo{
+					"bundle":  {Size: common.MaxDataValueSize + 1, Truncated: true},
+					"archive": {Size: 4, Binary: true},
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		actual := getConfigMapDetail(c.configMaps)
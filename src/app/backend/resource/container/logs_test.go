@@ -130,6 +130,29 @@ func TestGetLogs(t *testing.T) {
 					OffsetTo:   3},
 			},
 		},
+		{
+			"remove carriage returns of windows line endings",
+			"pod-1",
+			"1 log1\r\n2 log2\r\n3 log3\r\n4 log4\r\n5 log5\r\n",
+			"test",
+			logs.AllSelection,
+			&logs.LogDetails{
+				Info: logs.LogInfo{
+					PodName:       "pod-1",
+					ContainerName: "test",
+					FromDate:      "1",
+					ToDate:        "5",
+				},
+				LogLines: logs.LogLines{log1, log2, log3, log4, log5},
+				Selection: logs.Selection{
+					ReferencePoint: logs.LogLineId{
+						LogTimestamp: "3",
+						LineNum:      -1,
+					},
+					OffsetFrom: -2,
+					OffsetTo:   3},
+			},
+		},
 		{
 			"return a slice relative to the first element",
 			"pod-1",
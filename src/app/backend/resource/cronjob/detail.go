@@ -15,11 +15,10 @@
 package cronjob
 
 import (
-	"context"
-
 	batch2 "k8s.io/api/batch/v1beta1"
-	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sClient "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/compat"
 )
 
 // CronJobDetail contains Cron Job details.
@@ -37,7 +36,7 @@ type CronJobDetail struct {
 // GetCronJobDetail gets Cron Job details.
 func GetCronJobDetail(client k8sClient.Interface, namespace, name string) (*CronJobDetail, error) {
 
-	rawObject, err := client.BatchV1beta1().CronJobs(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	rawObject, err := compat.GetCronJob(client, namespace, name)
 	if err != nil {
 		return nil, err
 	}
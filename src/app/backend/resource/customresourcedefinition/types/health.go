@@ -0,0 +1,189 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HealthStatus is a normalized health of a custom resource object.
+type HealthStatus string
+
+const (
+	HealthHealthy     HealthStatus = "Healthy"
+	HealthProgressing HealthStatus = "Progressing"
+	HealthUnhealthy   HealthStatus = "Unhealthy"
+	HealthUnknown     HealthStatus = "Unknown"
+)
+
+// Health of a custom resource object together with the reason it was derived from.
+type Health struct {
+	Status HealthStatus `json:"status"`
+	Reason string       `json:"reason,omitempty"`
+}
+
+// Condition types, that are True when the object is healthy.
+var positiveConditions = []string{"Ready", "Available", "Healthy", "Succeeded", "Synced", "Established"}
+
+// Condition types, that are True when the object is not healthy or not yet reconciled, in order of precedence.
+var negativeConditions = []struct {
+	conditionType string
+	health        HealthStatus
+}{
+	{"Failed", HealthUnhealthy},
+	{"Degraded", HealthUnhealthy},
+	{"Stalled", HealthUnhealthy},
+	{"Progressing", HealthProgressing},
+	{"Reconciling", HealthProgressing},
+}
+
+var phases = map[string]HealthStatus{
+	"running":      HealthHealthy,
+	"active":       HealthHealthy,
+	"ready":        HealthHealthy,
+	"available":    HealthHealthy,
+	"healthy":      HealthHealthy,
+	"bound":        HealthHealthy,
+	"succeeded":    HealthHealthy,
+	"completed":    HealthHealthy,
+	"deployed":     HealthHealthy,
+	"pending":      HealthProgressing,
+	"creating":     HealthProgressing,
+	"provisioning": HealthProgressing,
+	"progressing":  HealthProgressing,
+	"updating":     HealthProgressing,
+	"initializing": HealthProgressing,
+	"failed":       HealthUnhealthy,
+	"error":        HealthUnhealthy,
+	"degraded":     HealthUnhealthy,
+	"unhealthy":    HealthUnhealthy,
+}
+
+// GetHealth derives health from the status of any custom resource object. Status conditions are checked first,
+// then ready replicas and at last the phase. Unknown health is returned if none of them is present.
+func GetHealth(status map[string]interface{}) Health {
+	if health, ok := getConditionsHealth(status); ok {
+		return health
+	}
+	if health, ok := getReplicasHealth(status); ok {
+		return health
+	}
+	if health, ok := getPhaseHealth(status); ok {
+		return health
+	}
+	return Health{Status: HealthUnknown}
+}
+
+func getConditionsHealth(status map[string]interface{}) (Health, bool) {
+	list, _ := status["conditions"].([]interface{})
+	conditions := make(map[string]map[string]interface{})
+	for _, item := range list {
+		if condition, ok := item.(map[string]interface{}); ok {
+			if conditionType, ok := condition["type"].(string); ok {
+				conditions[conditionType] = condition
+			}
+		}
+	}
+
+	// Negative conditions are checked first, because i.e. Stalled object can still report Ready=True.
+	for _, negative := range negativeConditions {
+		condition, ok := conditions[negative.conditionType]
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		// Progressing condition stays True also after a successful rollout, i.e. in deployments, so it is taken
+		// into account only when its reason says that the object is still progressing.
+		if negative.conditionType == "Progressing" && condition["reason"] != "Progressing" {
+			continue
+		}
+		return Health{Status: negative.health, Reason: conditionReason(negative.conditionType, condition)}, true
+	}
+
+	for _, conditionType := range positiveConditions {
+		condition, ok := conditions[conditionType]
+		if !ok {
+			continue
+		}
+
+		health := Health{Reason: conditionReason(conditionType, condition)}
+		switch condition["status"] {
+		case "True":
+			health.Status = HealthHealthy
+		case "False":
+			health.Status = HealthUnhealthy
+		default:
+			health.Status = HealthProgressing
+		}
+		return health, true
+	}
+
+	return Health{}, false
+}
+
+func conditionReason(conditionType string, condition map[string]interface{}) string {
+	reason := fmt.Sprintf("%s=%v", conditionType, condition["status"])
+	if message, ok := condition["message"].(string); ok && len(message) > 0 {
+		reason += ": " + message
+	}
+	return reason
+}
+
+func getReplicasHealth(status map[string]interface{}) (Health, bool) {
+	replicas, ok := toInt64(status["replicas"])
+	if !ok {
+		return Health{}, false
+	}
+	ready, _ := toInt64(status["readyReplicas"])
+
+	health := Health{Reason: fmt.Sprintf("%d/%d replicas ready", ready, replicas)}
+	switch {
+	case ready >= replicas:
+		health.Status = HealthHealthy
+	case ready == 0:
+		health.Status = HealthUnhealthy
+	default:
+		health.Status = HealthProgressing
+	}
+	return health, true
+}
+
+func getPhaseHealth(status map[string]interface{}) (Health, bool) {
+	for _, key := range []string{"phase", "state"} {
+		phase, ok := status[key].(string)
+		if !ok || len(phase) == 0 {
+			continue
+		}
+
+		if health, ok := phases[strings.ToLower(phase)]; ok {
+			return Health{Status: health, Reason: fmt.Sprintf("%s %s", key, phase)}, true
+		}
+		return Health{Status: HealthUnknown, Reason: fmt.Sprintf("%s %s", key, phase)}, true
+	}
+	return Health{}, false
+}
+
+// toInt64 converts a number decoded from JSON.
+func toInt64(value interface{}) (int64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return int64(number), true
+	case int64:
+		return number, true
+	case int:
+		return int64(number), true
+	}
+	return 0, false
+}
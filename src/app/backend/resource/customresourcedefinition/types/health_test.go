@@ -0,0 +1,69 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGetHealth(t *testing.T) {
+	cases := []struct {
+		info     string
+		status   string
+		expected HealthStatus
+	}{
+		{"no status", `{}`, HealthUnknown},
+		{"ready condition", `{"conditions": [{"type": "Ready", "status": "True"}]}`, HealthHealthy},
+		{"not ready condition", `{"conditions": [{"type": "Ready", "status": "False", "message": "no pods"}]}`,
+			HealthUnhealthy},
+		{"unknown ready condition", `{"conditions": [{"type": "Ready", "status": "Unknown"}]}`, HealthProgressing},
+		{"stalled but ready", `{"conditions": [{"type": "Ready", "status": "True"},
+			{"type": "Stalled", "status": "True"}]}`, HealthUnhealthy},
+		{"rollout complete", `{"conditions": [{"type": "Available", "status": "True"},
+			{"type": "Progressing", "status": "True", "reason": "NewReplicaSetAvailable"}]}`, HealthHealthy},
+		{"reconciling", `{"conditions": [{"type": "Reconciling", "status": "True"}]}`, HealthProgressing},
+		{"all replicas ready", `{"replicas": 3, "readyReplicas": 3}`, HealthHealthy},
+		{"some replicas ready", `{"replicas": 3, "readyReplicas": 1}`, HealthProgressing},
+		{"no replicas ready", `{"replicas": 3}`, HealthUnhealthy},
+		{"running phase", `{"phase": "Running"}`, HealthHealthy},
+		{"pending phase", `{"phase": "Pending"}`, HealthProgressing},
+		{"failed state", `{"state": "failed"}`, HealthUnhealthy},
+		{"custom phase", `{"phase": "Hibernating"}`, HealthUnknown},
+	}
+
+	for _, c := range cases {
+		var status map[string]interface{}
+		if err := json.Unmarshal([]byte(c.status), &status); err != nil {
+			t.Fatalf("%s: %v", c.info, err)
+		}
+
+		if actual := GetHealth(status); actual.Status != c.expected {
+			t.Errorf("%s: expected %s, got %s (%s)", c.info, c.expected, actual.Status, actual.Reason)
+		}
+	}
+}
+
+func TestCustomResourceObjectHealth(t *testing.T) {
+	var object CustomResourceObject
+	data := `{"kind": "Foo", "metadata": {"name": "foo"}, "status": {"phase": "Failed"}}`
+	if err := json.Unmarshal([]byte(data), &object); err != nil {
+		t.Fatal(err)
+	}
+
+	if object.Health.Status != HealthUnhealthy || object.Health.Reason != "phase Failed" {
+		t.Errorf("Unexpected health %+v", object.Health)
+	}
+}
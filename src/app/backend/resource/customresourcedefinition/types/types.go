@@ -69,12 +69,16 @@ type CustomResourceDefinitionVersion struct {
 type CustomResourceObject struct {
 	TypeMeta   api.TypeMeta   `json:"typeMeta"`
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+
+	// Health normalized from the object status.
+	Health Health `json:"health"`
 }
 
 func (r *CustomResourceObject) UnmarshalJSON(data []byte) error {
 	tempStruct := &struct {
 		metav1.TypeMeta `json:",inline"`
-		ObjectMeta      metav1.ObjectMeta `json:"metadata,omitempty"`
+		ObjectMeta      metav1.ObjectMeta      `json:"metadata,omitempty"`
+		Status          map[string]interface{} `json:"status,omitempty"`
 	}{}
 
 	err := json.Unmarshal(data, &tempStruct)
@@ -84,6 +88,7 @@ func (r *CustomResourceObject) UnmarshalJSON(data []byte) error {
 
 	r.TypeMeta = api.NewTypeMeta(api.ResourceKind(tempStruct.TypeMeta.Kind))
 	r.ObjectMeta = api.NewObjectMeta(tempStruct.ObjectMeta)
+	r.Health = GetHealth(tempStruct.Status)
 	return nil
 }
 
@@ -65,6 +65,14 @@ func GetCustomResourceObjectList(client apiextensionsclientset.Interface, config
 	}
 	list.Errors = nonCriticalErrors
 
+	var items []types.CustomResourceObject
+	for _, item := range list.Items {
+		if len(item.ObjectMeta.Namespace) == 0 || namespace.Matches(item.ObjectMeta.Namespace) {
+			items = append(items, item)
+		}
+	}
+	list.Items = items
+
 	// Return only slice of data, pagination is done here.
 	crdObjectCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toObjectCells(list.Items), dsQuery)
 	list.Items = fromObjectCells(crdObjectCells)
@@ -67,6 +67,8 @@ func (self CustomResourceObjectCell) GetProperty(name dataselect.PropertyName) d
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(string(self.Health.Status))
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -0,0 +1,237 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// RevisionAnnotation is set by the deployment controller on replica sets to their revision number.
+const RevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// DefaultCanaryWindow is the default time range of warning events compared between revisions.
+const DefaultCanaryWindow = time.Hour
+
+// RevisionHealth contains health indicators of pods of a single deployment revision.
+type RevisionHealth struct {
+	Revision   string   `json:"revision"`
+	ReplicaSet string   `json:"replicaSet"`
+	New        bool     `json:"new"`
+	Images     []string `json:"images"`
+
+	DesiredReplicas int32 `json:"desiredReplicas"`
+	ReadyReplicas   int32 `json:"readyReplicas"`
+	Restarts        int32 `json:"restarts"`
+
+	// WarningEvents is the number of warning events of revision pods within the window.
+	WarningEvents int32 `json:"warningEvents"`
+	// WarningEventRate is the number of warning events per pod and hour.
+	WarningEventRate float64 `json:"warningEventRate"`
+
+	// Average usage per pod. It is present only if metrics integration is available.
+	CPUUsage    *int64 `json:"cpuUsage,omitempty"`
+	MemoryUsage *int64 `json:"memoryUsage,omitempty"`
+}
+
+// CanaryComparison compares health of the revisions of a deployment, that have running pods.
+type CanaryComparison struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// InProgress is true if more than one revision has pods, i.e. during a rollout.
+	InProgress bool             `json:"inProgress"`
+	Window     string           `json:"window"`
+	Revisions  []RevisionHealth `json:"revisions"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetCanaryComparison returns health of deployment revisions with desired replicas, the new one first.
+func GetCanaryComparison(client client.Interface, metricClient metricapi.MetricClient, namespace, name string,
+	window time.Duration) (*CanaryComparison, error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metaV1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	options := metaV1.ListOptions{LabelSelector: selector.String()}
+
+	channels := &common.ResourceChannels{
+		ReplicaSetList: common.GetReplicaSetListChannelWithOptions(client,
+			common.NewSameNamespaceQuery(namespace), options, 1),
+		PodList: common.GetPodListChannelWithOptions(client, common.NewSameNamespaceQuery(namespace), options, 1),
+		EventList: common.GetEventListChannelWithOptions(client, common.NewSameNamespaceQuery(namespace),
+			metaV1.ListOptions{FieldSelector: fields.OneTermEqualSelector("type", v1.EventTypeWarning).String()}, 1),
+	}
+
+	rsList := <-channels.ReplicaSetList.List
+	if err := <-channels.ReplicaSetList.Error; err != nil {
+		return nil, err
+	}
+
+	pods := <-channels.PodList.List
+	if err := <-channels.PodList.Error; err != nil {
+		return nil, err
+	}
+
+	events := <-channels.EventList.List
+	nonCriticalErrors, criticalError := errors.HandleError(<-channels.EventList.Error)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+	if events == nil {
+		events = &v1.EventList{}
+	}
+
+	result := &CanaryComparison{
+		ObjectMeta: api.NewObjectMeta(deployment.ObjectMeta),
+		TypeMeta:   api.NewTypeMeta(api.ResourceKindDeployment),
+		Window:     window.String(),
+		Revisions:  []RevisionHealth{},
+		Errors:     nonCriticalErrors,
+	}
+
+	newRS := FindNewReplicaSet(deployment, toReplicaSetPointers(rsList.Items))
+	since := time.Now().Add(-window)
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		if !metaV1.IsControlledBy(rs, deployment) || rs.Spec.Replicas == nil || *rs.Spec.Replicas == 0 {
+			continue
+		}
+
+		rsPods := common.FilterPodsByControllerRef(rs, pods.Items)
+		health := getRevisionHealth(rs, rsPods, events.Items, since, window)
+		health.New = newRS != nil && newRS.UID == rs.UID
+		health.CPUUsage, health.MemoryUsage = getAverageUsage(metricClient, rsPods)
+		result.Revisions = append(result.Revisions, health)
+	}
+
+	sort.SliceStable(result.Revisions, func(i, j int) bool {
+		a, _ := strconv.Atoi(result.Revisions[i].Revision)
+		b, _ := strconv.Atoi(result.Revisions[j].Revision)
+		return a > b
+	})
+	result.InProgress = len(result.Revisions) > 1
+	return result, nil
+}
+
+func toReplicaSetPointers(replicaSets []apps.ReplicaSet) []*apps.ReplicaSet {
+	result := make([]*apps.ReplicaSet, len(replicaSets))
+	for i := range replicaSets {
+		result[i] = &replicaSets[i]
+	}
+	return result
+}
+
+// getRevisionHealth computes readiness, restarts and warning events of replica set pods. Only events, that
+// were last seen after since, are counted.
+func getRevisionHealth(rs *apps.ReplicaSet, pods []v1.Pod, events []v1.Event, since time.Time,
+	window time.Duration) RevisionHealth {
+	health := RevisionHealth{
+		Revision:        rs.Annotations[RevisionAnnotation],
+		ReplicaSet:      rs.Name,
+		Images:          common.GetContainerImages(&rs.Spec.Template.Spec),
+		DesiredReplicas: *rs.Spec.Replicas,
+		ReadyReplicas:   rs.Status.ReadyReplicas,
+	}
+
+	uids := make(map[types.UID]bool)
+	for _, pod := range pods {
+		uids[pod.UID] = true
+		for _, status := range pod.Status.ContainerStatuses {
+			health.Restarts += status.RestartCount
+		}
+	}
+
+	for _, event := range events {
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+		if uids[event.InvolvedObject.UID] && lastSeen.After(since) {
+			count := event.Count
+			if count == 0 {
+				count = 1
+			}
+			health.WarningEvents += count
+		}
+	}
+
+	if len(pods) > 0 && window > 0 {
+		health.WarningEventRate = float64(health.WarningEvents) / float64(len(pods)) / window.Hours()
+	}
+
+	return health
+}
+
+// getAverageUsage returns the latest CPU and memory usage averaged over pods. Nil values are returned if
+// metrics are not available.
+func getAverageUsage(metricClient metricapi.MetricClient, pods []v1.Pod) (cpu, memory *int64) {
+	if metricClient == nil || len(pods) == 0 {
+		return nil, nil
+	}
+
+	selectors := make([]metricapi.ResourceSelector, len(pods))
+	for i, pod := range pods {
+		selectors[i] = metricapi.ResourceSelector{
+			Namespace:    pod.Namespace,
+			ResourceType: api.ResourceKindPod,
+			ResourceName: pod.Name,
+			UID:          pod.UID,
+		}
+	}
+
+	cached := &metricapi.CachedResources{Pods: pods}
+	cpuMetrics, _ := metricClient.DownloadMetric(selectors, metricapi.CpuUsage, cached).GetMetrics()
+	memoryMetrics, _ := metricClient.DownloadMetric(selectors, metricapi.MemoryUsage, cached).GetMetrics()
+	return averageLatest(cpuMetrics), averageLatest(memoryMetrics)
+}
+
+// averageLatest returns average of the latest data points of given metrics.
+func averageLatest(metrics []metricapi.Metric) *int64 {
+	var sum, count int64
+	for _, metric := range metrics {
+		if len(metric.DataPoints) > 0 {
+			sum += metric.DataPoints[len(metric.DataPoints)-1].Y
+			count++
+		}
+	}
+
+	if count == 0 {
+		return nil
+	}
+	average := sum / count
+	return &average
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+)
+
+func TestGetCanaryComparison(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	isController := true
+	newTemplate := v1.PodTemplateSpec{
+		ObjectMeta: metaV1.ObjectMeta{Labels: labels},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: "web:2"}}},
+	}
+	oldTemplate := v1.PodTemplateSpec{
+		ObjectMeta: metaV1.ObjectMeta{Labels: labels},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web", Image: "web:1"}}},
+	}
+	deployment := &apps.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: "shop", Name: "web", UID: "deployment"},
+		Spec:       apps.DeploymentSpec{Selector: &metaV1.LabelSelector{MatchLabels: labels}, Template: newTemplate},
+	}
+	owner := func(kind, name string, uid types.UID) []metaV1.OwnerReference {
+		return []metaV1.OwnerReference{{Kind: kind, Name: name, UID: uid, Controller: &isController}}
+	}
+	replicaSet := func(name, revision string, replicas int32, template v1.PodTemplateSpec) *apps.ReplicaSet {
+		return &apps.ReplicaSet{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "shop", Name: name, UID: types.UID(name), Labels: labels,
+				Annotations:     map[string]string{RevisionAnnotation: revision},
+				OwnerReferences: owner("Deployment", "web", "deployment")},
+			Spec:   apps.ReplicaSetSpec{Replicas: &replicas, Template: template},
+			Status: apps.ReplicaSetStatus{ReadyReplicas: replicas},
+		}
+	}
+	pod := func(name, rs string, restarts int32) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "shop", Name: name, UID: types.UID(name), Labels: labels,
+				OwnerReferences: owner("ReplicaSet", rs, types.UID(rs))},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{RestartCount: restarts}}},
+		}
+	}
+
+	client := fake.NewSimpleClientset(
+		deployment,
+		replicaSet("web-1", "1", 3, oldTemplate),
+		replicaSet("web-2", "2", 1, newTemplate),
+		replicaSet("web-0", "0", 0, oldTemplate),
+		pod("web-1-a", "web-1", 0),
+		pod("web-2-a", "web-2", 4),
+		&v1.Event{
+			ObjectMeta:     metaV1.ObjectMeta{Namespace: "shop", Name: "backoff"},
+			Type:           v1.EventTypeWarning,
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-2-a", UID: "web-2-a"},
+			Count:          6,
+			LastTimestamp:  metaV1.NewTime(time.Now()),
+		},
+	)
+
+	result, err := GetCanaryComparison(client, nil, "shop", "web", time.Hour)
+	if err != nil {
+		t.Fatalf("GetCanaryComparison() returned error: %s", err)
+	}
+
+	if !result.InProgress || len(result.Revisions) != 2 {
+		t.Fatalf("GetCanaryComparison() == %#v, expected rollout with two revisions", result.Revisions)
+	}
+
+	canary, stable := result.Revisions[0], result.Revisions[1]
+	if canary.Revision != "2" || !canary.New || canary.Restarts != 4 || canary.WarningEvents != 6 ||
+		canary.WarningEventRate != 6 {
+		t.Errorf("GetCanaryComparison() returned canary revision %#v", canary)
+	}
+	if stable.Revision != "1" || stable.New || stable.DesiredReplicas != 3 || stable.WarningEvents != 0 {
+		t.Errorf("GetCanaryComparison() returned stable revision %#v", stable)
+	}
+}
+
+func TestAverageLatest(t *testing.T) {
+	metrics := []metricapi.Metric{
+		{DataPoints: metricapi.DataPoints{{X: 1, Y: 100}, {X: 2, Y: 300}}},
+		{DataPoints: metricapi.DataPoints{{X: 2, Y: 100}}},
+		{},
+	}
+
+	if actual := averageLatest(metrics); actual == nil || *actual != 200 {
+		t.Errorf("averageLatest() == %v, expected 200", actual)
+	}
+	if actual := averageLatest(nil); actual != nil {
+		t.Errorf("averageLatest(nil) == %v, expected nil", *actual)
+	}
+}
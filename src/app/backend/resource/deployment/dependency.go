@@ -0,0 +1,331 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/reference"
+)
+
+// DependencyKindImage is the kind of container image dependencies.
+const DependencyKindImage = "image"
+
+// Image pull failures reported in container waiting state.
+var imagePullFailureReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// Dependency is a single object or image, that deployment pods depend on.
+type Dependency struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	// Reason explains why the dependency is not healthy.
+	Reason string `json:"reason,omitempty"`
+}
+
+// DependencyRollup is a checklist of deployment dependencies. Deployment dependencies are healthy only if
+// all of them are healthy.
+type DependencyRollup struct {
+	Healthy      bool         `json:"healthy"`
+	Dependencies []Dependency `json:"dependencies"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetDeploymentDependencies returns health of direct deployment dependencies: referenced config maps and
+// secrets, services selecting deployment pods, persistent volume claims, service account and container
+// images. Dependencies, which could not be retrieved, are left out of the rollup.
+func GetDeploymentDependencies(client client.Interface, namespace string, deploymentName string) (
+	*DependencyRollup, error) {
+	log.Printf("Getting dependencies of %s deployment in %s namespace", deploymentName, namespace)
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName,
+		metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metaV1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	nsQuery := common.NewSameNamespaceQuery(namespace)
+	channels := &common.ResourceChannels{
+		ConfigMapList:             common.GetConfigMapListChannel(client, nsQuery, 1),
+		SecretList:                common.GetSecretListChannel(client, nsQuery, 1),
+		ServiceList:               common.GetServiceListChannel(client, nsQuery, 1),
+		EndpointList:              common.GetEndpointListChannel(client, nsQuery, 1),
+		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannel(client, nsQuery, 1),
+		PodList: common.GetPodListChannelWithOptions(client, nsQuery,
+			metaV1.ListOptions{LabelSelector: selector.String()}, 1),
+	}
+
+	configMaps := <-channels.ConfigMapList.List
+	configMapErr := <-channels.ConfigMapList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(configMapErr)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	secrets := <-channels.SecretList.List
+	secretErr := <-channels.SecretList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(secretErr, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	services := <-channels.ServiceList.List
+	serviceErr := <-channels.ServiceList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(serviceErr, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	endpoints := <-channels.EndpointList.List
+	endpointErr := <-channels.EndpointList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(endpointErr, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	claims := <-channels.PersistentVolumeClaimList.List
+	claimErr := <-channels.PersistentVolumeClaimList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(claimErr, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	pods := <-channels.PodList.List
+	podErr := <-channels.PodList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(podErr, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	spec := deployment.Spec.Template.Spec
+	dependencies := make([]Dependency, 0)
+	if configMapErr != nil {
+		configMaps = nil
+	}
+	if secretErr != nil {
+		secrets = nil
+	}
+	dependencies = append(dependencies, getReferenceDependencies(spec, configMaps, secrets)...)
+	if serviceErr == nil && endpointErr == nil {
+		dependencies = append(dependencies, getServiceDependencies(deployment.Spec.Template.Labels,
+			services.Items, endpoints.Items)...)
+	}
+	if claimErr == nil {
+		dependencies = append(dependencies, getClaimDependencies(spec, claims.Items)...)
+	}
+
+	serviceAccount, err := getServiceAccountDependency(client, namespace, spec)
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+	if serviceAccount != nil {
+		dependencies = append(dependencies, *serviceAccount)
+	}
+
+	if podErr == nil {
+		dependencies = append(dependencies, getImageDependencies(spec, pods.Items)...)
+	}
+
+	return toDependencyRollup(dependencies, nonCriticalErrors), nil
+}
+
+func toDependencyRollup(dependencies []Dependency, nonCriticalErrors []error) *DependencyRollup {
+	healthy := true
+	for _, dependency := range dependencies {
+		healthy = healthy && dependency.Healthy
+	}
+	return &DependencyRollup{Healthy: healthy, Dependencies: dependencies, Errors: nonCriticalErrors}
+}
+
+// getReferenceDependencies checks that referenced config maps and secrets, and referenced keys exist.
+// Optional references are skipped, as well as references to kinds, which list is nil.
+func getReferenceDependencies(spec v1.PodSpec, configMaps *v1.ConfigMapList,
+	secrets *v1.SecretList) []Dependency {
+	keys := make(map[api.ResourceKind]map[string]map[string]bool)
+	if configMaps != nil {
+		keys[api.ResourceKindConfigMap] = make(map[string]map[string]bool)
+		for _, cm := range configMaps.Items {
+			keys[api.ResourceKindConfigMap][cm.Name] = make(map[string]bool)
+			for key := range cm.Data {
+				keys[api.ResourceKindConfigMap][cm.Name][key] = true
+			}
+			for key := range cm.BinaryData {
+				keys[api.ResourceKindConfigMap][cm.Name][key] = true
+			}
+		}
+	}
+	if secrets != nil {
+		keys[api.ResourceKindSecret] = make(map[string]map[string]bool)
+		for _, secret := range secrets.Items {
+			keys[api.ResourceKindSecret][secret.Name] = make(map[string]bool)
+			for key := range secret.Data {
+				keys[api.ResourceKindSecret][secret.Name][key] = true
+			}
+		}
+	}
+
+	result := make([]Dependency, 0)
+	index := make(map[string]int)
+	for _, ref := range reference.GetReferences(spec) {
+		if _, known := keys[ref.Kind]; ref.Optional || !known {
+			continue
+		}
+
+		id := string(ref.Kind) + "/" + ref.Name
+		i, ok := index[id]
+		if !ok {
+			i = len(result)
+			index[id] = i
+			result = append(result, Dependency{Kind: string(ref.Kind), Name: ref.Name, Healthy: true})
+		}
+
+		objectKeys, exists := keys[ref.Kind][ref.Name]
+		switch {
+		case !exists:
+			result[i].Healthy, result[i].Reason = false, "not found"
+		case len(ref.Key) > 0 && !objectKeys[ref.Key] && result[i].Healthy:
+			result[i].Healthy, result[i].Reason = false, fmt.Sprintf("key %s not found", ref.Key)
+		}
+	}
+	return result
+}
+
+// getServiceDependencies checks that services selecting deployment pods have ready endpoints.
+func getServiceDependencies(labels map[string]string, services []v1.Service,
+	endpoints []v1.Endpoints) []Dependency {
+	ready := make(map[string]bool)
+	for _, e := range endpoints {
+		for _, subset := range e.Subsets {
+			if len(subset.Addresses) > 0 {
+				ready[e.Name] = true
+			}
+		}
+	}
+
+	result := make([]Dependency, 0)
+	for _, service := range services {
+		if !api.IsSelectorMatching(service.Spec.Selector, labels) {
+			continue
+		}
+
+		dependency := Dependency{Kind: api.ResourceKindService, Name: service.Name, Healthy: ready[service.Name]}
+		if !dependency.Healthy {
+			dependency.Reason = "no ready endpoints"
+		}
+		result = append(result, dependency)
+	}
+	return result
+}
+
+// getClaimDependencies checks that persistent volume claims used by the deployment are bound.
+func getClaimDependencies(spec v1.PodSpec, claims []v1.PersistentVolumeClaim) []Dependency {
+	phases := make(map[string]v1.PersistentVolumeClaimPhase)
+	for _, claim := range claims {
+		phases[claim.Name] = claim.Status.Phase
+	}
+
+	result := make([]Dependency, 0)
+	for _, volume := range spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+
+		name := volume.PersistentVolumeClaim.ClaimName
+		dependency := Dependency{Kind: api.ResourceKindPersistentVolumeClaim, Name: name, Healthy: true}
+		if phase, ok := phases[name]; !ok {
+			dependency.Healthy, dependency.Reason = false, "not found"
+		} else if phase != v1.ClaimBound {
+			dependency.Healthy, dependency.Reason = false, fmt.Sprintf("claim is %s", phase)
+		}
+		result = append(result, dependency)
+	}
+	return result
+}
+
+// getServiceAccountDependency checks that the service account used by deployment pods exists. Nil is
+// returned, if it could not be checked.
+func getServiceAccountDependency(client client.Interface, namespace string, spec v1.PodSpec) (*Dependency,
+	error) {
+	name := spec.ServiceAccountName
+	if len(name) == 0 {
+		name = "default"
+	}
+
+	dependency := &Dependency{Kind: api.ResourceKindServiceAccount, Name: name, Healthy: true}
+	_, err := client.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		dependency.Healthy, dependency.Reason = false, "not found"
+		return dependency, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dependency, nil
+}
+
+// getImageDependencies checks that images of deployment containers can be pulled by its pods.
+func getImageDependencies(spec v1.PodSpec, pods []v1.Pod) []Dependency {
+	failures := make(map[string]string)
+	for _, pod := range pods {
+		statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...),
+			pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			waiting := status.State.Waiting
+			if waiting != nil && imagePullFailureReasons[waiting.Reason] {
+				failures[status.Image] = waiting.Reason
+			}
+		}
+	}
+
+	result := make([]Dependency, 0)
+	seen := make(map[string]bool)
+	containers := append(append([]v1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		if seen[container.Image] {
+			continue
+		}
+		seen[container.Image] = true
+
+		dependency := Dependency{Kind: DependencyKindImage, Name: container.Image, Healthy: true}
+		if reason, failed := failures[container.Image]; failed {
+			dependency.Healthy, dependency.Reason = false, reason
+		}
+		result = append(result, dependency)
+	}
+	return result
+}
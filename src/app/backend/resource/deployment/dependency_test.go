@@ -0,0 +1,92 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetDeploymentDependencies(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	deployment := &apps.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: apps.DeploymentSpec{
+			Selector: &metaV1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metaV1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					ServiceAccountName: "web",
+					Containers: []v1.Container{{
+						Name:  "web",
+						Image: "web:1.0",
+						EnvFrom: []v1.EnvFromSource{
+							{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{
+								Name: "web-config"}}},
+							{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{
+								Name: "web-secret"}}},
+						},
+					}},
+					Volumes: []v1.Volume{{
+						Name: "data",
+						VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+							ClaimName: "web-data"}},
+					}},
+				},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset(
+		deployment,
+		&v1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: "web-config", Namespace: "default"}},
+		&v1.Service{ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: v1.ServiceSpec{Selector: labels}},
+		&v1.PersistentVolumeClaim{ObjectMeta: metaV1.ObjectMeta{Name: "web-data", Namespace: "default"},
+			Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending}},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: labels},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "web", Image: "web:1.0",
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}}}},
+		},
+	)
+
+	actual, err := GetDeploymentDependencies(client, "default", "web")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := &DependencyRollup{
+		Healthy: false,
+		Dependencies: []Dependency{
+			{Kind: "configmap", Name: "web-config", Healthy: true},
+			{Kind: "secret", Name: "web-secret", Healthy: false, Reason: "not found"},
+			{Kind: "service", Name: "web", Healthy: false, Reason: "no ready endpoints"},
+			{Kind: "persistentvolumeclaim", Name: "web-data", Healthy: false, Reason: "claim is Pending"},
+			{Kind: "serviceaccount", Name: "web", Healthy: false, Reason: "not found"},
+			{Kind: "image", Name: "web:1.0", Healthy: false, Reason: "ImagePullBackOff"},
+		},
+		Errors: []error{},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected dependency rollup %#v, but got %#v", expected, actual)
+	}
+}
@@ -30,12 +30,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/yaml"
-	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	client "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	dashboardClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+	"github.com/kubernetes/dashboard/src/app/backend/validation"
 )
 
 const (
@@ -74,6 +76,12 @@ type AppDeploymentSpec struct {
 	// Whether the created service is external.
 	IsExternal bool `json:"isExternal"`
 
+	// Cluster IP of the created service. It is allocated by the cluster if empty.
+	ClusterIP string `json:"clusterIP,omitempty"`
+
+	// IP family of the created service in dual-stack clusters. Cluster IP has to belong to it, if both are set.
+	IPFamily *api.IPFamily `json:"ipFamily,omitempty"`
+
 	// Description of the deployment.
 	Description *string `json:"description"`
 
@@ -158,11 +166,87 @@ type Protocols struct {
 
 // DeployApp deploys an app based on the given configuration. The app is deployed using the given
 // client. App deployment consists of a deployment and an optional service. Both of them
-// share common labels.
-func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
+// share common labels. Optional preset provides namespace defaults for values not set in the spec.
+func DeployApp(spec *AppDeploymentSpec, preset *settingsApi.DeployPreset, client client.Interface) error {
 	log.Printf("Deploying %s application into %s namespace", spec.Name, spec.Namespace)
 
-	annotations := map[string]string{}
+	if err := validateServiceIP(spec); err != nil {
+		return err
+	}
+
+	deployment, err := NewAppDeployment(spec, preset)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.AppsV1().Deployments(spec.Namespace).Create(context.TODO(), deployment, metaV1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	if len(spec.PortMappings) > 0 {
+		service := &api.Service{
+			ObjectMeta: deployment.ObjectMeta,
+			Spec: api.ServiceSpec{
+				Selector:  deployment.Spec.Selector.MatchLabels,
+				ClusterIP: spec.ClusterIP,
+				IPFamily:  spec.IPFamily,
+			},
+		}
+
+		if spec.IsExternal {
+			service.Spec.Type = api.ServiceTypeLoadBalancer
+		} else {
+			service.Spec.Type = api.ServiceTypeClusterIP
+		}
+
+		for _, portMapping := range spec.PortMappings {
+			servicePort :=
+				api.ServicePort{
+					Protocol: portMapping.Protocol,
+					Port:     portMapping.Port,
+					Name:     generatePortMappingName(portMapping),
+					TargetPort: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: portMapping.TargetPort,
+					},
+				}
+			service.Spec.Ports = append(service.Spec.Ports, servicePort)
+		}
+
+		_, err = client.CoreV1().Services(spec.Namespace).Create(context.TODO(), service, metaV1.CreateOptions{})
+		return err
+	}
+
+	return nil
+}
+
+// validateServiceIP checks that cluster IP entered in the deploy form is a valid address of the selected family,
+// before the deployment is created.
+func validateServiceIP(spec *AppDeploymentSpec) error {
+	if len(spec.ClusterIP) == 0 || spec.ClusterIP == api.ClusterIPNone {
+		return nil
+	}
+
+	ipSpec := &validation.IPValiditySpec{IP: spec.ClusterIP}
+	if spec.IPFamily != nil {
+		ipSpec.Family = *spec.IPFamily
+	}
+
+	if validity := validation.ValidateIP(ipSpec); !validity.Valid {
+		return errors.NewBadRequest(validity.Reason)
+	}
+	return nil
+}
+
+// NewAppDeployment creates the deployment described by the deploy form, with the defaults of given preset
+// applied. It does not create it in the cluster.
+func NewAppDeployment(spec *AppDeploymentSpec, preset *settingsApi.DeployPreset) (*apps.Deployment, error) {
+	if preset == nil {
+		preset = &settingsApi.DeployPreset{}
+	}
+
+	annotations := mergeMaps(preset.Annotations, nil)
 	if spec.Description != nil {
 		annotations[DescriptionAnnotationKey] = *spec.Description
 	}
@@ -170,7 +254,7 @@ func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
 	objectMeta := metaV1.ObjectMeta{
 		Annotations: annotations,
 		Name:        spec.Name,
-		Labels:      labels,
+		Labels:      mergeMaps(preset.Labels, labels),
 	}
 
 	containerSpec := api.Container{
@@ -185,6 +269,13 @@ func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
 		Env: convertEnvVarsSpec(spec.Variables),
 	}
 
+	for name, quantity := range preset.Requests {
+		containerSpec.Resources.Requests[name] = quantity
+	}
+	if len(preset.Limits) > 0 {
+		containerSpec.Resources.Limits = preset.Limits.DeepCopy()
+	}
+
 	if spec.ContainerCommand != nil {
 		containerSpec.Command = []string{*spec.ContainerCommand}
 	}
@@ -198,12 +289,21 @@ func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
 	if spec.MemoryRequirement != nil {
 		containerSpec.Resources.Requests[api.ResourceMemory] = *spec.MemoryRequirement
 	}
+	if err := validateRequestsWithinLimits(containerSpec.Resources); err != nil {
+		return nil, err
+	}
 	podSpec := api.PodSpec{
-		Containers: []api.Container{containerSpec},
+		Containers:   []api.Container{containerSpec},
+		NodeSelector: preset.NodeSelector,
 	}
 	if spec.ImagePullSecret != nil {
 		podSpec.ImagePullSecrets = []api.LocalObjectReference{{Name: *spec.ImagePullSecret}}
 	}
+	for _, secret := range preset.ImagePullSecrets {
+		if spec.ImagePullSecret == nil || *spec.ImagePullSecret != secret {
+			podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, api.LocalObjectReference{Name: secret})
+		}
+	}
 
 	podTemplate := api.PodTemplateSpec{
 		ObjectMeta: objectMeta,
@@ -220,42 +320,17 @@ func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
 			},
 		},
 	}
-	_, err := client.AppsV1().Deployments(spec.Namespace).Create(context.TODO(), deployment, metaV1.CreateOptions{})
-
-	if err != nil {
-		return err
-	}
-
-	if len(spec.PortMappings) > 0 {
-		service := &api.Service{
-			ObjectMeta: objectMeta,
-			Spec: api.ServiceSpec{
-				Selector: labels,
-			},
-		}
-
-		if spec.IsExternal {
-			service.Spec.Type = api.ServiceTypeLoadBalancer
-		} else {
-			service.Spec.Type = api.ServiceTypeClusterIP
-		}
+	return deployment, nil
+}
 
-		for _, portMapping := range spec.PortMappings {
-			servicePort :=
-				api.ServicePort{
-					Protocol: portMapping.Protocol,
-					Port:     portMapping.Port,
-					Name:     generatePortMappingName(portMapping),
-					TargetPort: intstr.IntOrString{
-						Type:   intstr.Int,
-						IntVal: portMapping.TargetPort,
-					},
-				}
-			service.Spec.Ports = append(service.Spec.Ports, servicePort)
+// validateRequestsWithinLimits checks that requests set by the user do not exceed limits coming from the namespace
+// preset, as apiserver would reject such deployment with a less descriptive error.
+func validateRequestsWithinLimits(resources api.ResourceRequirements) error {
+	for name, limit := range resources.Limits {
+		if request, ok := resources.Requests[name]; ok && request.Cmp(limit) > 0 {
+			return errors.NewInvalid(fmt.Sprintf("%s requirement %s exceeds the limit %s set for the namespace",
+				name, request.String(), limit.String()))
 		}
-
-		_, err = client.CoreV1().Services(spec.Namespace).Create(context.TODO(), service, metaV1.CreateOptions{})
-		return err
 	}
 
 	return nil
@@ -300,6 +375,19 @@ func getLabelsMap(labels []Label) map[string]string {
 	return result
 }
 
+// mergeMaps returns a copy of defaults updated with values. Values take precedence over defaults.
+func mergeMaps(defaults, values map[string]string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range defaults {
+		result[key] = value
+	}
+	for key, value := range values {
+		result[key] = value
+	}
+
+	return result
+}
+
 // DeployAppFromFile deploys an app based on the given yaml or json file.
 func DeployAppFromFile(cfg *rest.Config, spec *AppDeploymentFromFileSpec) (bool, error) {
 	reader := strings.NewReader(spec.Content)
@@ -322,7 +410,7 @@ func DeployAppFromFile(cfg *rest.Config, spec *AppDeploymentFromFileSpec) (bool,
 			gv = schema.GroupVersion{Version: version}
 		}
 
-		discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+		discoveryClient, err := dashboardClient.NewCachedDiscoveryClient(cfg)
 		if err != nil {
 			return false, err
 		}
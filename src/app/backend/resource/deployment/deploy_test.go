@@ -21,10 +21,13 @@ import (
 
 	apps "k8s.io/api/apps/v1"
 	api "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
 )
 
 func TestDeployApp(t *testing.T) {
@@ -70,7 +73,7 @@ func TestDeployApp(t *testing.T) {
 
 	testClient := fake.NewSimpleClientset()
 
-	DeployApp(spec, testClient)
+	DeployApp(spec, nil, testClient)
 
 	createAction := testClient.Actions()[0].(core.CreateActionImpl)
 	if len(testClient.Actions()) != 1 {
@@ -88,6 +91,36 @@ func TestDeployApp(t *testing.T) {
 	}
 }
 
+func TestDeployAppServiceIP(t *testing.T) {
+	ipv6 := api.IPv6Protocol
+	spec := &AppDeploymentSpec{
+		Namespace:    "foo-namespace",
+		Name:         "foo-name",
+		PortMappings: []PortMapping{{Port: 80, TargetPort: 8080, Protocol: api.ProtocolTCP}},
+		ClusterIP:    "10.0.0.10",
+		IPFamily:     &ipv6,
+	}
+
+	testClient := fake.NewSimpleClientset()
+	if err := DeployApp(spec, nil, testClient); !apierrors.IsBadRequest(err) {
+		t.Errorf("Expected bad request for IPv4 cluster IP of IPv6 service, got %v", err)
+	}
+
+	if len(testClient.Actions()) != 0 {
+		t.Errorf("Expected no action for invalid cluster IP but got %#v", testClient.Actions())
+	}
+
+	spec.ClusterIP = "fd00::10"
+	if err := DeployApp(spec, nil, testClient); err != nil {
+		t.Fatal(err)
+	}
+
+	service := testClient.Actions()[1].(core.CreateActionImpl).GetObject().(*api.Service)
+	if service.Spec.ClusterIP != "fd00::10" || service.Spec.IPFamily == nil || *service.Spec.IPFamily != ipv6 {
+		t.Errorf("Expected IPv6 service with fd00::10 cluster IP but got %#v", service.Spec)
+	}
+}
+
 func TestDeployAppContainerCommands(t *testing.T) {
 	command := "foo-command"
 	commandArgs := "foo-command-args"
@@ -99,7 +132,7 @@ func TestDeployAppContainerCommands(t *testing.T) {
 	}
 	testClient := fake.NewSimpleClientset()
 
-	DeployApp(spec, testClient)
+	DeployApp(spec, nil, testClient)
 	createAction := testClient.Actions()[0].(core.CreateActionImpl)
 
 	rc := createAction.GetObject().(*apps.Deployment)
@@ -123,7 +156,7 @@ func TestDeployShouldPopulateEnvVars(t *testing.T) {
 	}
 	testClient := fake.NewSimpleClientset()
 
-	DeployApp(spec, testClient)
+	DeployApp(spec, nil, testClient)
 
 	createAction := testClient.Actions()[0].(core.CreateActionImpl)
 
@@ -165,7 +198,7 @@ func TestDeployWithResourceRequirements(t *testing.T) {
 	}
 	testClient := fake.NewSimpleClientset()
 
-	DeployApp(spec, testClient)
+	DeployApp(spec, nil, testClient)
 
 	createAction := testClient.Actions()[0].(core.CreateActionImpl)
 
@@ -177,6 +210,91 @@ func TestDeployWithResourceRequirements(t *testing.T) {
 	}
 }
 
+func TestDeployWithPreset(t *testing.T) {
+	cpuRequirement := resource.MustParse("200m")
+	pullSecret := "user-secret"
+	spec := &AppDeploymentSpec{
+		Namespace:       "foo-namespace",
+		Name:            "foo-name",
+		CpuRequirement:  &cpuRequirement,
+		ImagePullSecret: &pullSecret,
+		Labels:          []Label{{Key: "team", Value: "user"}},
+	}
+	preset := &settingsApi.DeployPreset{
+		Requests: api.ResourceList{
+			api.ResourceCPU:    resource.MustParse("100m"),
+			api.ResourceMemory: resource.MustParse("64Mi"),
+		},
+		Limits:           api.ResourceList{api.ResourceMemory: resource.MustParse("128Mi")},
+		Labels:           map[string]string{"team": "preset", "cost-center": "42"},
+		Annotations:      map[string]string{"owner": "platform"},
+		ImagePullSecrets: []string{"registry", "user-secret"},
+		NodeSelector:     map[string]string{"pool": "apps"},
+	}
+	testClient := fake.NewSimpleClientset()
+
+	DeployApp(spec, preset, testClient)
+
+	createAction := testClient.Actions()[0].(core.CreateActionImpl)
+	rc := createAction.GetObject().(*apps.Deployment)
+	podSpec := rc.Spec.Template.Spec
+
+	expectedResources := api.ResourceRequirements{
+		Requests: api.ResourceList{
+			api.ResourceCPU:    cpuRequirement,
+			api.ResourceMemory: resource.MustParse("64Mi"),
+		},
+		Limits: api.ResourceList{api.ResourceMemory: resource.MustParse("128Mi")},
+	}
+	if !reflect.DeepEqual(podSpec.Containers[0].Resources, expectedResources) {
+		t.Errorf("Expected resource requirements to be %#v but got %#v",
+			expectedResources, podSpec.Containers[0].Resources)
+	}
+
+	expectedLabels := map[string]string{"team": "user", "cost-center": "42"}
+	if !reflect.DeepEqual(rc.Labels, expectedLabels) {
+		t.Errorf("Expected labels to be %#v but got %#v", expectedLabels, rc.Labels)
+	}
+
+	expectedSelector := map[string]string{"team": "user"}
+	if !reflect.DeepEqual(rc.Spec.Selector.MatchLabels, expectedSelector) {
+		t.Errorf("Expected selector to be %#v but got %#v", expectedSelector, rc.Spec.Selector.MatchLabels)
+	}
+
+	if rc.Annotations["owner"] != "platform" {
+		t.Errorf("Expected preset annotations to be applied but got %#v", rc.Annotations)
+	}
+
+	expectedSecrets := []api.LocalObjectReference{{Name: "user-secret"}, {Name: "registry"}}
+	if !reflect.DeepEqual(podSpec.ImagePullSecrets, expectedSecrets) {
+		t.Errorf("Expected image pull secrets to be %#v but got %#v", expectedSecrets, podSpec.ImagePullSecrets)
+	}
+
+	if !reflect.DeepEqual(podSpec.NodeSelector, preset.NodeSelector) {
+		t.Errorf("Expected node selector to be %#v but got %#v", preset.NodeSelector, podSpec.NodeSelector)
+	}
+}
+
+func TestDeployWithPresetRequestOverLimit(t *testing.T) {
+	memoryRequirement := resource.MustParse("256Mi")
+	spec := &AppDeploymentSpec{
+		Namespace:         "foo-namespace",
+		Name:              "foo-name",
+		MemoryRequirement: &memoryRequirement,
+	}
+	preset := &settingsApi.DeployPreset{
+		Limits: api.ResourceList{api.ResourceMemory: resource.MustParse("128Mi")},
+	}
+	testClient := fake.NewSimpleClientset()
+
+	if err := DeployApp(spec, preset, testClient); err == nil {
+		t.Error("Expected error for memory requirement over the preset limit")
+	}
+	if len(testClient.Actions()) != 0 {
+		t.Errorf("Expected nothing to be created but got %d actions", len(testClient.Actions()))
+	}
+}
+
 func TestGetAvailableProtocols(t *testing.T) {
 	expected := &Protocols{Protocols: []api.Protocol{"TCP", "UDP"}}
 
@@ -0,0 +1,86 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	apps "k8s.io/api/apps/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// GetDeploymentGroupList returns deployments grouped by value of given label. Data select query is applied to
+// every group.
+func GetDeploymentGroupList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery, metricClient metricapi.MetricClient, label string) (*common.GroupedList, error) {
+	log.Printf("Getting list of all deployments in the cluster grouped by %s label", label)
+
+	channels := &common.ResourceChannels{
+		DeploymentList: common.GetDeploymentListChannel(client, nsQuery, 1),
+		PodList:        common.GetPodListChannel(client, nsQuery, 1),
+		EventList:      common.GetEventListChannel(client, nsQuery, 1),
+		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
+	}
+
+	deployments := <-channels.DeploymentList.List
+	err := <-channels.DeploymentList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	pods := <-channels.PodList.List
+	err = <-channels.PodList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	events := <-channels.EventList.List
+	err = <-channels.EventList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	rs := <-channels.ReplicaSetList.List
+	err = <-channels.ReplicaSetList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	result := common.NewGroupedList(label, nonCriticalErrors)
+	values, groups := common.GroupByLabel(label, len(deployments.Items), func(i int) map[string]string {
+		return deployments.Items[i].Labels
+	})
+	for _, value := range values {
+		group := &apps.DeploymentList{Items: make([]apps.Deployment, 0, len(groups[value]))}
+		for _, i := range groups[value] {
+			group.Items = append(group.Items, deployments.Items[i])
+		}
+
+		deploymentList := toDeploymentList(group.Items, pods.Items, events.Items, rs.Items, nil, dsQuery,
+			metricClient)
+		deploymentList.Status = getStatus(group, rs.Items, pods.Items, events.Items)
+		result.AddGroup(value, deploymentList)
+	}
+
+	return result, nil
+}
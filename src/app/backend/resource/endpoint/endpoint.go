@@ -39,6 +39,9 @@ type Endpoint struct {
 	// Status of the endpoint
 	Ready bool `json:"ready"`
 
+	// IPFamily of the endpoint address, IPv4 or IPv6.
+	IPFamily v1.IPFamily `json:"ipFamily,omitempty"`
+
 	// Array of endpoint ports
 	Ports []v1.EndpointPort `json:"ports"`
 }
@@ -92,6 +95,7 @@ func toEndpoint(address v1.EndpointAddress, ports []v1.EndpointPort, ready bool)
 		Host:     address.IP,
 		Ports:    ports,
 		Ready:    ready,
+		IPFamily: common.GetIPFamily(address.IP),
 		NodeName: address.NodeName,
 	}
 }
@@ -23,6 +23,15 @@ type EndpointList struct {
 	ListMeta api.ListMeta `json:"listMeta"`
 	// List of endpoints
 	Endpoints []Endpoint `json:"endpoints"`
+	// Readiness of endpoints per IP family. Dual-stack services have one item per family.
+	Families []EndpointFamilyStatus `json:"families,omitempty"`
+}
+
+// EndpointFamilyStatus counts ready and not ready endpoints of one IP family.
+type EndpointFamilyStatus struct {
+	Family   v1.IPFamily `json:"family"`
+	Ready    int         `json:"ready"`
+	NotReady int         `json:"notReady"`
 }
 
 // toEndpointList converts array of api events to endpoint List structure
@@ -43,5 +52,32 @@ func toEndpointList(endpoints []v1.Endpoints) *EndpointList {
 		}
 	}
 
+	endpointList.Families = getFamilyStatuses(endpointList.Endpoints)
 	return &endpointList
 }
+
+// getFamilyStatuses counts ready and not ready endpoints per IP family. Families are ordered by their first
+// occurrence. Endpoints with unknown family are skipped.
+func getFamilyStatuses(endpoints []Endpoint) []EndpointFamilyStatus {
+	var statuses []EndpointFamilyStatus
+	for _, endpoint := range endpoints {
+		if len(endpoint.IPFamily) == 0 {
+			continue
+		}
+
+		i := 0
+		for i < len(statuses) && statuses[i].Family != endpoint.IPFamily {
+			i++
+		}
+		if i == len(statuses) {
+			statuses = append(statuses, EndpointFamilyStatus{Family: endpoint.IPFamily})
+		}
+
+		if endpoint.Ready {
+			statuses[i].Ready++
+		} else {
+			statuses[i].NotReady++
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,266 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idleworkload
+
+import (
+	"log"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+)
+
+const (
+	// DefaultWindow is used when no observation window is specified.
+	DefaultWindow = time.Hour
+	// DefaultCPUThreshold is a CPU usage in millicores below which workload is considered idle.
+	DefaultCPUThreshold = 5
+)
+
+// IdleCriteria describes when a workload is considered to be idle.
+type IdleCriteria struct {
+	// Window is the period that is checked for activity.
+	Window time.Duration
+	// CPUThreshold is the highest CPU usage in millicores that any workload pod can reach during the
+	// window for the workload to be considered idle.
+	CPUThreshold int64
+}
+
+// Reclaimable contains resources requested by all workload pods that could be released.
+type Reclaimable struct {
+	// CPU in millicores.
+	CPU int64 `json:"cpu"`
+	// Memory in bytes.
+	Memory int64 `json:"memory"`
+}
+
+// IdleWorkload is a workload that did not use resources during the observation window.
+type IdleWorkload struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	Replicas int32 `json:"replicas"`
+
+	// PeakCPUUsage is the highest CPU usage in millicores observed across workload pods during the window.
+	PeakCPUUsage int64 `json:"peakCpuUsage"`
+
+	Reclaimable Reclaimable `json:"reclaimable"`
+}
+
+// IdleWorkloadList contains workloads that are candidates for scale-down or deletion.
+type IdleWorkloadList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+
+	// Window is the observation window in seconds that was used to detect idle workloads.
+	Window int64 `json:"window"`
+
+	Workloads []IdleWorkload `json:"workloads"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetIdleWorkloadList returns deployments and stateful sets, that had no CPU activity above the threshold and
+// no container restarts during the observation window. Activity is based on the metrics integration data, so
+// workloads without metrics are never reported as idle.
+func GetIdleWorkloadList(client client.Interface, metricClient metricapi.MetricClient,
+	nsQuery *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery, criteria IdleCriteria) (
+	*IdleWorkloadList, error) {
+	log.Print("Getting list of idle workloads")
+	if metricClient == nil {
+		return nil, errors.NewNotFound("metrics integration is required to detect idle workloads")
+	}
+
+	channels := &common.ResourceChannels{
+		DeploymentList:  common.GetDeploymentListChannel(client, nsQuery, 1),
+		StatefulSetList: common.GetStatefulSetListChannel(client, nsQuery, 1),
+		PodList:         common.GetPodListChannel(client, nsQuery, 1),
+	}
+
+	deployments := <-channels.DeploymentList.List
+	err := <-channels.DeploymentList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	statefulSets := <-channels.StatefulSetList.List
+	err = <-channels.StatefulSetList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	pods := <-channels.PodList.List
+	err = <-channels.PodList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	workloads := make([]workload, 0)
+	if deployments != nil {
+		for _, d := range deployments.Items {
+			workloads = append(workloads, fromDeployment(d))
+		}
+	}
+	if statefulSets != nil {
+		for _, s := range statefulSets.Items {
+			workloads = append(workloads, fromStatefulSet(s))
+		}
+	}
+
+	var podItems []v1.Pod
+	if pods != nil {
+		podItems = pods.Items
+	}
+
+	now := time.Now()
+	usage := getPeakCPUUsage(metricClient, podItems, now.Add(-criteria.Window))
+	idle := make([]IdleWorkload, 0)
+	for _, w := range workloads {
+		if result, ok := toIdleWorkload(w, podItems, usage, criteria, now); ok {
+			idle = append(idle, result)
+		}
+	}
+
+	return toIdleWorkloadList(idle, nonCriticalErrors, dsQuery, criteria), nil
+}
+
+func toIdleWorkloadList(workloads []IdleWorkload, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery,
+	criteria IdleCriteria) *IdleWorkloadList {
+	result := &IdleWorkloadList{
+		Window:    int64(criteria.Window.Seconds()),
+		Workloads: make([]IdleWorkload, 0),
+		Errors:    nonCriticalErrors,
+	}
+
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(workloads), dsQuery)
+	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	result.Workloads = fromCells(cells)
+	return result
+}
+
+// workload contains workload data required to check whether it is idle.
+type workload struct {
+	meta     metav1.ObjectMeta
+	kind     api.ResourceKind
+	selector *metav1.LabelSelector
+	replicas int32
+}
+
+func fromDeployment(d apps.Deployment) workload {
+	return workload{meta: d.ObjectMeta, kind: api.ResourceKindDeployment, selector: d.Spec.Selector,
+		replicas: d.Status.Replicas}
+}
+
+func fromStatefulSet(s apps.StatefulSet) workload {
+	return workload{meta: s.ObjectMeta, kind: api.ResourceKindStatefulSet, selector: s.Spec.Selector,
+		replicas: s.Status.Replicas}
+}
+
+func toIdleWorkload(w workload, pods []v1.Pod, usage map[string]int64, criteria IdleCriteria, now time.Time) (
+	IdleWorkload, bool) {
+	result := IdleWorkload{
+		ObjectMeta: api.NewObjectMeta(w.meta),
+		TypeMeta:   api.NewTypeMeta(w.kind),
+		Replicas:   w.replicas,
+	}
+
+	// Workloads younger than the window or scaled down to zero have nothing to report.
+	if w.replicas == 0 || w.meta.CreationTimestamp.Add(criteria.Window).After(now) {
+		return result, false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(w.selector)
+	if err != nil || selector.Empty() {
+		return result, false
+	}
+
+	matched := 0
+	for _, pod := range pods {
+		if pod.Namespace != w.meta.Namespace || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
+		peak, ok := usage[string(pod.UID)]
+		if !ok || peak > criteria.CPUThreshold || restartedSince(pod, now.Add(-criteria.Window)) {
+			return result, false
+		}
+
+		matched++
+		if peak > result.PeakCPUUsage {
+			result.PeakCPUUsage = peak
+		}
+
+		for _, container := range pod.Spec.Containers {
+			result.Reclaimable.CPU += container.Resources.Requests.Cpu().MilliValue()
+			result.Reclaimable.Memory += container.Resources.Requests.Memory().Value()
+		}
+	}
+
+	return result, matched > 0
+}
+
+// restartedSince returns true if any container of the pod was restarted after given time.
+func restartedSince(pod v1.Pod, since time.Time) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		terminated := status.LastTerminationState.Terminated
+		if terminated != nil && terminated.FinishedAt.Time.After(since) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getPeakCPUUsage returns the highest CPU usage observed after given time, mapped by pod UID. Pods without
+// metrics data points in the window are not included.
+func getPeakCPUUsage(metricClient metricapi.MetricClient, pods []v1.Pod, since time.Time) map[string]int64 {
+	result := make(map[string]int64)
+	for _, pod := range pods {
+		selector := []metricapi.ResourceSelector{{
+			Namespace:    pod.Namespace,
+			ResourceType: api.ResourceKindPod,
+			ResourceName: pod.Name,
+			UID:          pod.UID,
+		}}
+
+		metrics, _ := metricClient.DownloadMetric(selector, metricapi.CpuUsage, metricapi.NoResourceCache).
+			GetMetrics()
+		for _, metric := range metrics {
+			for _, point := range metric.DataPoints {
+				if point.X < since.Unix() {
+					continue
+				}
+
+				if peak, ok := result[string(pod.UID)]; !ok || point.Y > peak {
+					result[string(pod.UID)] = point.Y
+				}
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idleworkload
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+func TestToIdleWorkload(t *testing.T) {
+	now := time.Now()
+	criteria := IdleCriteria{Window: time.Hour, CPUThreshold: DefaultCPUThreshold}
+	w := workload{
+		meta: metav1.ObjectMeta{Name: "app", Namespace: "default",
+			CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour))},
+		kind:     api.ResourceKindDeployment,
+		selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "app"}},
+		replicas: 1,
+	}
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-1", Namespace: "default", UID: "uid-1",
+			Labels: map[string]string{"app": "app"}},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name: "app",
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("250m"),
+				v1.ResourceMemory: resource.MustParse("128Mi"),
+			}},
+		}}},
+	}
+	restarted := *pod.DeepCopy()
+	restarted.Status.ContainerStatuses = []v1.ContainerStatus{{LastTerminationState: v1.ContainerState{
+		Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-time.Minute))},
+	}}}
+
+	cases := []struct {
+		info     string
+		pods     []v1.Pod
+		usage    map[string]int64
+		expected bool
+	}{
+		{"idle pod", []v1.Pod{pod}, map[string]int64{"uid-1": 2}, true},
+		{"busy pod", []v1.Pod{pod}, map[string]int64{"uid-1": 200}, false},
+		{"pod without metrics", []v1.Pod{pod}, map[string]int64{}, false},
+		{"restarted pod", []v1.Pod{restarted}, map[string]int64{"uid-1": 0}, false},
+		{"no pods", []v1.Pod{}, map[string]int64{}, false},
+	}
+
+	for _, c := range cases {
+		actual, ok := toIdleWorkload(w, c.pods, c.usage, criteria, now)
+		if ok != c.expected {
+			t.Errorf("Test Case: %s. Expected idle to be %t, but got %t", c.info, c.expected, ok)
+		}
+
+		if ok && (actual.Reclaimable.CPU != 250 || actual.Reclaimable.Memory != 128*1024*1024) {
+			t.Errorf("Test Case: %s. Unexpected reclaimable resources: %v", c.info, actual.Reclaimable)
+		}
+	}
+}
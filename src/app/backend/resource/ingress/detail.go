@@ -18,8 +18,11 @@ import (
 	"context"
 	"log"
 
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/compat"
+
 	extensions "k8s.io/api/extensions/v1beta1"
-	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
 )
 
@@ -43,13 +46,24 @@ type IngressDetail struct {
 func GetIngressDetail(client client.Interface, namespace, name string) (*IngressDetail, error) {
 	log.Printf("Getting details of %s ingress in %s namespace", name, namespace)
 
-	rawIngress, err := client.ExtensionsV1beta1().Ingresses(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	rawIngress, err := compat.GetIngress(client, namespace, name)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return getIngressDetail(rawIngress), nil
+	detail := getIngressDetail(rawIngress)
+	serviceList, err := client.CoreV1().Services(namespace).List(context.TODO(), api.ListEverything)
+	detail.Errors, err = errors.HandleError(err)
+	if err != nil {
+		return nil, err
+	}
+
+	if serviceList != nil && len(detail.Errors) == 0 {
+		detail.Warnings = getBackendWarnings(*rawIngress, serviceList.Items)
+	}
+
+	return detail, nil
 }
 
 func getIngressDetail(i *extensions.Ingress) *IngressDetail {
@@ -20,6 +20,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/api"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/compat"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	extensions "k8s.io/api/extensions/v1beta1"
 	client "k8s.io/client-go/kubernetes"
@@ -32,6 +33,9 @@ type Ingress struct {
 
 	// External endpoints of this ingress.
 	Endpoints []common.Endpoint `json:"endpoints"`
+
+	// Warnings about backends pointing at missing services or service ports.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // IngressList - response structure for a queried ingress list.
@@ -48,14 +52,39 @@ type IngressList struct {
 // GetIngressList returns all ingresses in the given namespace.
 func GetIngressList(client client.Interface, namespace *common.NamespaceQuery,
 	dsQuery *dataselect.DataSelectQuery) (*IngressList, error) {
-	ingressList, err := client.ExtensionsV1beta1().Ingresses(namespace.ToRequestParam()).List(context.TODO(), api.ListEverything)
+	ingressList, err := compat.ListIngresses(client, namespace.ToRequestParam(), api.ListEverything)
 
 	nonCriticalErrors, criticalError := errors.HandleError(err)
 	if criticalError != nil {
 		return nil, criticalError
 	}
 
-	return toIngressList(ingressList.Items, nonCriticalErrors, dsQuery), nil
+	var ingresses []extensions.Ingress
+	for _, item := range ingressList.Items {
+		if namespace.Matches(item.Namespace) {
+			ingresses = append(ingresses, item)
+		}
+	}
+
+	result := toIngressList(ingresses, nonCriticalErrors, dsQuery)
+
+	serviceList, err := client.CoreV1().Services(namespace.ToRequestParam()).List(context.TODO(), api.ListEverything)
+	result.Errors, criticalError = errors.AppendError(err, result.Errors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	if err == nil {
+		for i := range result.Items {
+			for _, ingress := range ingressList.Items {
+				if ingress.Namespace == result.Items[i].Namespace && ingress.Name == result.Items[i].Name {
+					result.Items[i].Warnings = getBackendWarnings(ingress, serviceList.Items)
+				}
+			}
+		}
+	}
+
+	return result, nil
 }
 
 func getEndpoints(ingress *extensions.Ingress) []common.Endpoint {
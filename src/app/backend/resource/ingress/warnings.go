@@ -0,0 +1,86 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// getBackendWarnings returns warnings about ingress backends pointing at services or service ports that
+// do not exist. Services have to be listed from the ingress namespace.
+func getBackendWarnings(ingress extensions.Ingress, services []v1.Service) []string {
+	backends := make([]extensions.IngressBackend, 0)
+	if ingress.Spec.Backend != nil {
+		backends = append(backends, *ingress.Spec.Backend)
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			backends = append(backends, path.Backend)
+		}
+	}
+
+	var warnings []string
+	reported := make(map[string]bool)
+	for _, backend := range backends {
+		// Resource backends do not point at services.
+		if len(backend.ServiceName) == 0 {
+			continue
+		}
+
+		warning := ""
+		service := findService(ingress.Namespace, backend.ServiceName, services)
+		if service == nil {
+			warning = fmt.Sprintf("Backend service %s does not exist", backend.ServiceName)
+		} else if !hasPort(service, backend.ServicePort) {
+			warning = fmt.Sprintf("Backend service %s does not expose port %s", backend.ServiceName,
+				backend.ServicePort.String())
+		}
+
+		if len(warning) > 0 && !reported[warning] {
+			reported[warning] = true
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings
+}
+
+func findService(namespace, name string, services []v1.Service) *v1.Service {
+	for i := range services {
+		if services[i].Namespace == namespace && services[i].Name == name {
+			return &services[i]
+		}
+	}
+
+	return nil
+}
+
+func hasPort(service *v1.Service, port intstr.IntOrString) bool {
+	for _, p := range service.Spec.Ports {
+		if (port.Type == intstr.Int && p.Port == port.IntVal) || (port.Type == intstr.String && p.Name == port.StrVal) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestGetBackendWarnings(t *testing.T) {
+	services := []v1.Service{{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Name: "http", Port: 80}}},
+	}}
+
+	newIngress := func(backends ...extensions.IngressBackend) extensions.Ingress {
+		paths := make([]extensions.HTTPIngressPath, 0)
+		for _, backend := range backends {
+			paths = append(paths, extensions.HTTPIngressPath{Backend: backend})
+		}
+		return extensions.Ingress{
+			ObjectMeta: metaV1.ObjectMeta{Name: "ingress", Namespace: "default"},
+			Spec: extensions.IngressSpec{Rules: []extensions.IngressRule{{
+				IngressRuleValue: extensions.IngressRuleValue{HTTP: &extensions.HTTPIngressRuleValue{Paths: paths}},
+			}}},
+		}
+	}
+
+	cases := []struct {
+		info     string
+		ingress  extensions.Ingress
+		expected []string
+	}{
+		{
+			"port number",
+			newIngress(extensions.IngressBackend{ServiceName: "web", ServicePort: intstr.FromInt(80)}),
+			nil,
+		},
+		{
+			"port name",
+			newIngress(extensions.IngressBackend{ServiceName: "web", ServicePort: intstr.FromString("http")}),
+			nil,
+		},
+		{
+			"missing port",
+			newIngress(extensions.IngressBackend{ServiceName: "web", ServicePort: intstr.FromInt(8080)}),
+			[]string{"Backend service web does not expose port 8080"},
+		},
+		{
+			"missing service reported once",
+			newIngress(
+				extensions.IngressBackend{ServiceName: "api", ServicePort: intstr.FromInt(80)},
+				extensions.IngressBackend{ServiceName: "api", ServicePort: intstr.FromInt(80)},
+			),
+			[]string{"Backend service api does not exist"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := getBackendWarnings(c.ingress, services)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Expected warnings %v, but got %v", c.info, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// podSpecPaths maps kinds of workloads to the location of their pod spec.
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"PodTemplate":           {"template", "spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// PodSpecPath returns location of the pod spec in objects of given kind and false if objects of the kind do not run
+// pods.
+func PodSpecPath(kind string) ([]string, bool) {
+	path, ok := podSpecPaths[kind]
+	return path, ok
+}
+
+// runToCompletionKinds run pods which exit once their work is done, so they are not expected to have probes.
+var runToCompletionKinds = map[string]bool{
+	"Job":     true,
+	"CronJob": true,
+}
+
+// Finding is a single violation of a lint rule.
+type Finding struct {
+	Rule      settingsApi.LintRule     `json:"rule"`
+	Severity  settingsApi.LintSeverity `json:"severity"`
+	Kind      string                   `json:"kind"`
+	Name      string                   `json:"name"`
+	Container string                   `json:"container,omitempty"`
+	Message   string                   `json:"message"`
+}
+
+// String returns human readable description of the finding.
+func (f Finding) String() string {
+	if len(f.Container) > 0 {
+		return fmt.Sprintf("%s %s, container %s: %s", f.Kind, f.Name, f.Container, f.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", f.Kind, f.Name, f.Message)
+}
+
+// Findings is a list of lint rule violations.
+type Findings []Finding
+
+// Error returns an error listing findings of error severity, or nil if there are none.
+func (f Findings) Error() error {
+	messages := make([]string, 0)
+	for _, finding := range f {
+		if finding.Severity == settingsApi.LintSeverityError {
+			messages = append(messages, finding.String())
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.NewGenericResponse(http.StatusUnprocessableEntity,
+		"rejected by lint policy: "+strings.Join(messages, "; "))
+}
+
+// SetWarnings adds findings of warning severity to the response as Warning headers.
+func (f Findings) SetWarnings(header http.Header) {
+	for _, finding := range f {
+		if finding.Severity == settingsApi.LintSeverityWarning {
+			header.Add("Warning", fmt.Sprintf("299 - %q", finding.String()))
+		}
+	}
+}
+
+// CheckManifests lints every object of a YAML or JSON, possibly multi-document, manifest.
+func CheckManifests(content string, policy settingsApi.LintPolicy) (Findings, error) {
+	findings := Findings{}
+	d := yaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := d.Decode(obj); err != nil {
+			if err == io.EOF {
+				return findings, nil
+			}
+			return nil, err
+		}
+
+		objFindings, err := CheckUnstructured(obj, policy)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, objFindings...)
+	}
+}
+
+// CheckObject lints a typed object of given kind.
+func CheckObject(kind string, obj runtime.Object, policy settingsApi.LintPolicy) (Findings, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetKind(kind)
+	return CheckUnstructured(u, policy)
+}
+
+// CheckUnstructured lints an object. Objects of kinds, which do not run pods, have no findings.
+func CheckUnstructured(obj *unstructured.Unstructured, policy settingsApi.LintPolicy) (Findings, error) {
+	path, ok := podSpecPaths[obj.GetKind()]
+	if !ok {
+		return Findings{}, nil
+	}
+
+	content, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil || !found {
+		return Findings{}, err
+	}
+
+	spec := new(v1.PodSpec)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, spec); err != nil {
+		return nil, err
+	}
+
+	return CheckPodSpec(obj.GetKind(), obj.GetName(), spec, policy), nil
+}
+
+// CheckPodSpec lints pod spec of an object of given kind and name.
+func CheckPodSpec(kind, name string, spec *v1.PodSpec, policy settingsApi.LintPolicy) Findings {
+	findings := Findings{}
+	report := func(rule settingsApi.LintRule, container, message string) {
+		severity := policy.Severity(rule)
+		if severity == settingsApi.LintSeverityOff {
+			return
+		}
+		findings = append(findings, Finding{
+			Rule:      rule,
+			Severity:  severity,
+			Kind:      kind,
+			Name:      name,
+			Container: container,
+			Message:   message,
+		})
+	}
+
+	containers := append(append([]v1.Container{}, spec.InitContainers...), spec.Containers...)
+	for i, container := range containers {
+		isInit := i < len(spec.InitContainers)
+
+		if !isInit && !runToCompletionKinds[kind] && (container.LivenessProbe == nil || container.ReadinessProbe == nil) {
+			report(settingsApi.LintRuleMissingProbes, container.Name, "liveness or readiness probe is missing")
+		}
+
+		if _, ok := container.Resources.Requests[v1.ResourceCPU]; !ok {
+			report(settingsApi.LintRuleMissingRequests, container.Name, "CPU request is missing")
+		}
+		if _, ok := container.Resources.Requests[v1.ResourceMemory]; !ok {
+			report(settingsApi.LintRuleMissingRequests, container.Name, "memory request is missing")
+		}
+
+		if usesLatestTag(container.Image) {
+			report(settingsApi.LintRuleLatestTag, container.Name,
+				fmt.Sprintf("image %q does not pin a version", container.Image))
+		}
+
+		if container.SecurityContext != nil && container.SecurityContext.Privileged != nil &&
+			*container.SecurityContext.Privileged {
+			report(settingsApi.LintRulePrivileged, container.Name, "container is privileged")
+		}
+	}
+
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			report(settingsApi.LintRuleHostPath, "",
+				fmt.Sprintf("volume %s mounts host path %s", volume.Name, volume.HostPath.Path))
+		}
+	}
+
+	return findings
+}
+
+// usesLatestTag returns true if image is referenced without digest and either without tag or by the latest tag.
+func usesLatestTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+
+	// Registry host can contain a port, so only the last path segment can hold the tag.
+	segment := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(segment, ":")
+	return i < 0 || segment[i+1:] == "latest"
+}
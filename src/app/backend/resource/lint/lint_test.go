@@ -0,0 +1,166 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+func TestCheckPodSpec(t *testing.T) {
+	privileged := true
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("100m"),
+		v1.ResourceMemory: resource.MustParse("64Mi"),
+	}
+	probe := &v1.Probe{}
+	spec := &v1.PodSpec{
+		InitContainers: []v1.Container{
+			{Name: "init", Image: "busybox:1.32", Resources: v1.ResourceRequirements{Requests: requests}},
+		},
+		Containers: []v1.Container{
+			{
+				Name:           "app",
+				Image:          "registry:5000/app",
+				Resources:      v1.ResourceRequirements{Requests: requests},
+				LivenessProbe:  probe,
+				ReadinessProbe: probe,
+			},
+			{
+				Name:            "agent",
+				Image:           "agent@sha256:abc",
+				SecurityContext: &v1.SecurityContext{Privileged: &privileged},
+			},
+		},
+		Volumes: []v1.Volume{{
+			Name:         "logs",
+			VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/var/log"}},
+		}},
+	}
+
+	cases := []struct {
+		kind     string
+		policy   settingsApi.LintPolicy
+		expected map[settingsApi.LintRule]int
+	}{
+		{
+			"Deployment",
+			settingsApi.LintPolicy{},
+			map[settingsApi.LintRule]int{
+				settingsApi.LintRuleLatestTag:       1,
+				settingsApi.LintRuleMissingProbes:   1,
+				settingsApi.LintRuleMissingRequests: 2,
+				settingsApi.LintRulePrivileged:      1,
+				settingsApi.LintRuleHostPath:        1,
+			},
+		},
+		{
+			"Job",
+			settingsApi.LintPolicy{
+				settingsApi.LintRuleMissingRequests: settingsApi.LintSeverityOff,
+				settingsApi.LintRuleHostPath:        settingsApi.LintSeverityError,
+			},
+			map[settingsApi.LintRule]int{
+				settingsApi.LintRuleLatestTag:  1,
+				settingsApi.LintRulePrivileged: 1,
+				settingsApi.LintRuleHostPath:   1,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		findings := CheckPodSpec(c.kind, "foo", spec, c.policy)
+		actual := make(map[settingsApi.LintRule]int)
+		for _, finding := range findings {
+			actual[finding.Rule]++
+			if finding.Severity != c.policy.Severity(finding.Rule) {
+				t.Errorf("finding %s has severity %s, expected %s", finding, finding.Severity,
+					c.policy.Severity(finding.Rule))
+			}
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("CheckPodSpec(%s) found %v, expected %v", c.kind, actual, c.expected)
+		}
+	}
+}
+
+func TestCheckManifests(t *testing.T) {
+	content := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+            image: backup:latest
+            resources:
+              requests:
+                cpu: 100m
+                memory: 64Mi
+`
+	policy := settingsApi.LintPolicy{settingsApi.LintRuleLatestTag: settingsApi.LintSeverityError}
+
+	findings, err := CheckManifests(content, policy)
+	if err != nil {
+		t.Fatalf("CheckManifests() returned error: %s", err)
+	}
+
+	expected := Findings{{
+		Rule:      settingsApi.LintRuleLatestTag,
+		Severity:  settingsApi.LintSeverityError,
+		Kind:      "CronJob",
+		Name:      "backup",
+		Container: "backup",
+		Message:   `image "backup:latest" does not pin a version`,
+	}}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Fatalf("CheckManifests() == %v, expected %v", findings, expected)
+	}
+
+	if err := findings.Error(); err == nil {
+		t.Error("expected findings of error severity to reject the object")
+	}
+}
+
+func TestFindingsSetWarnings(t *testing.T) {
+	findings := Findings{
+		{Severity: settingsApi.LintSeverityWarning, Kind: "Pod", Name: "a", Message: "volume mounts host path"},
+		{Severity: settingsApi.LintSeverityError, Kind: "Pod", Name: "b", Message: "container is privileged"},
+	}
+
+	header := http.Header{}
+	findings.SetWarnings(header)
+
+	expected := []string{`299 - "Pod a: volume mounts host path"`}
+	if !reflect.DeepEqual(header["Warning"], expected) {
+		t.Errorf("SetWarnings() set %v, expected %v", header["Warning"], expected)
+	}
+}
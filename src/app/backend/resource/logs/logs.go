@@ -246,10 +246,12 @@ func (self LogLines) createLogLineId(lineIndex int) *LogLineId {
 }
 
 // ToLogLines converts rawLogs (string) to LogLines. Proper log lines start with a timestamp which is chopped off.
-// In error cases the server returns a message without a timestamp
+// In error cases the server returns a message without a timestamp. Carriage returns of Windows line endings are
+// removed.
 func ToLogLines(rawLogs string) LogLines {
 	logLines := LogLines{}
 	for _, line := range strings.Split(rawLogs, "\n") {
+		line = strings.TrimSuffix(line, "\r")
 		if line != "" {
 			startsWithDate := ('0' <= line[0] && line[0] <= '9') //2017-...
 			idx := strings.Index(line, " ")
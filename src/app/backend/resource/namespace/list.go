@@ -22,6 +22,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/openshift"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -45,6 +46,9 @@ type Namespace struct {
 
 	// Phase is the current lifecycle phase of the namespace.
 	Phase v1.NamespacePhase `json:"phase"`
+
+	// Usage is an overview of workloads in the namespace. It is computed only for namespace lists.
+	Usage *NamespaceUsage `json:"usage,omitempty"`
 }
 
 // GetNamespaceListFromChannels returns a list of all namespaces in the cluster.
@@ -62,15 +66,48 @@ func GetNamespaceListFromChannels(channels *common.ResourceChannels, dsQuery *da
 
 // GetNamespaceList returns a list of all namespaces in the cluster.
 func GetNamespaceList(client kubernetes.Interface, dsQuery *dataselect.DataSelectQuery) (*NamespaceList, error) {
+	return GetMatchingNamespaceList(client, common.NewNamespaceQuery(nil), dsQuery)
+}
+
+// GetMatchingNamespaceList returns a list of namespaces in the cluster, that match given namespace query. On OpenShift
+// namespaces are read from projects, so that users who are not allowed to list namespaces see their projects.
+func GetMatchingNamespaceList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*NamespaceList, error) {
 	log.Println("Getting list of namespaces")
-	namespaces, err := client.CoreV1().Namespaces().List(context.TODO(), api.ListEverything)
+	namespaces, err := listNamespaces(client)
 
 	nonCriticalErrors, criticalError := errors.HandleError(err)
 	if criticalError != nil {
 		return nil, criticalError
 	}
 
-	return toNamespaceList(namespaces.Items, nonCriticalErrors, dsQuery), nil
+	var items []v1.Namespace
+	for _, namespace := range namespaces {
+		if nsQuery.Matches(namespace.Name) {
+			items = append(items, namespace)
+		}
+	}
+
+	namespaceList := toNamespaceList(items, nonCriticalErrors, dsQuery)
+	addUsage(client, namespaceList)
+	return namespaceList, nil
+}
+
+func listNamespaces(client kubernetes.Interface) ([]v1.Namespace, error) {
+	if !openshift.GetCapabilities().Projects {
+		namespaces, err := client.CoreV1().Namespaces().List(context.TODO(), api.ListEverything)
+		if err != nil {
+			return nil, err
+		}
+		return namespaces.Items, nil
+	}
+
+	projects, err := openshift.ListProjects(client, api.ListEverything)
+	namespaces := make([]v1.Namespace, 0, len(projects.Items))
+	for _, project := range projects.Items {
+		namespaces = append(namespaces, project.ToNamespace())
+	}
+	return namespaces, err
 }
 
 func toNamespaceList(namespaces []v1.Namespace, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *NamespaceList {
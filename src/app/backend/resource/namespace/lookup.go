@@ -0,0 +1,147 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	v1 "k8s.io/api/core/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// DefaultLookupLimit is a number of namespaces returned by a lookup without a limit.
+	DefaultLookupLimit = 50
+	// MaxLookupLimit is the largest number of namespaces returned by a single lookup.
+	MaxLookupLimit = 500
+
+	// cacheResyncPeriod is a period after which namespaces in the cache are resynced.
+	cacheResyncPeriod = 10 * time.Minute
+)
+
+// NamespaceLookupQuery selects a page of namespaces whose names contain the filter. Continue is the name of the last
+// namespace of the previous page.
+type NamespaceLookupQuery struct {
+	Filter   string
+	Limit    int
+	Continue string
+}
+
+// NamespaceLookup is a page of names of namespaces that match a lookup. It is much smaller than a namespace list, so
+// that the namespace selector stays responsive on clusters with thousands of namespaces.
+type NamespaceLookup struct {
+	// ListMeta contains the number of all namespaces that match the filter.
+	ListMeta api.ListMeta `json:"listMeta"`
+
+	// Sorted names of namespaces on this page.
+	Namespaces []string `json:"namespaces"`
+
+	// Continue is set when there are more matching namespaces and should be passed to get the next page.
+	Continue string `json:"continue,omitempty"`
+}
+
+// NamespaceCache keeps names of all namespaces, read with the service account of Dashboard, in memory. The informer
+// behind it is started by the first lookup, so that small clusters never pay for it.
+type NamespaceCache struct {
+	client   kubernetes.Interface
+	informer cache.SharedIndexInformer
+	once     sync.Once
+}
+
+// NewNamespaceCache creates NamespaceCache that watches namespaces with given client.
+func NewNamespaceCache(client kubernetes.Interface) *NamespaceCache {
+	return &NamespaceCache{client: client}
+}
+
+// Names returns sorted names of all namespaces. False is returned until the cache is synced, in which case namespaces
+// have to be listed from the API server instead.
+func (c *NamespaceCache) Names() ([]string, bool) {
+	c.once.Do(c.start)
+	if !c.informer.HasSynced() {
+		return nil, false
+	}
+
+	objects := c.informer.GetStore().List()
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if namespace, ok := object.(*v1.Namespace); ok {
+			names = append(names, namespace.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, true
+}
+
+func (c *NamespaceCache) start() {
+	c.informer = coreinformers.NewNamespaceInformer(c.client, cacheResyncPeriod, cache.Indexers{})
+	go c.informer.Run(make(chan struct{}))
+}
+
+// ListNamespaceNames returns sorted names of namespaces that given client can list. On OpenShift names of projects
+// are returned.
+func ListNamespaceNames(client kubernetes.Interface) ([]string, error) {
+	namespaces, err := listNamespaces(client)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		names = append(names, namespace.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LookupNamespaces returns a page of sorted names that contain the filter of the query, ignoring case.
+func LookupNamespaces(names []string, query *NamespaceLookupQuery) *NamespaceLookup {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLookupLimit
+	}
+	if limit > MaxLookupLimit {
+		limit = MaxLookupLimit
+	}
+
+	filter := strings.ToLower(query.Filter)
+	matching := make([]string, 0)
+	for _, name := range names {
+		if strings.Contains(strings.ToLower(name), filter) {
+			matching = append(matching, name)
+		}
+	}
+	sort.Strings(matching)
+
+	start := sort.SearchStrings(matching, query.Continue)
+	if start < len(matching) && len(query.Continue) > 0 && matching[start] == query.Continue {
+		start++
+	}
+
+	end := start + limit
+	result := &NamespaceLookup{ListMeta: api.ListMeta{TotalItems: len(matching)}}
+	if end < len(matching) {
+		result.Namespaces = matching[start:end]
+		result.Continue = matching[end-1]
+	} else {
+		result.Namespaces = matching[start:]
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLookupNamespaces(t *testing.T) {
+	names := []string{"team-b", "kube-system", "Team-A", "default", "team-c"}
+	cases := []struct {
+		query    *NamespaceLookupQuery
+		expected *NamespaceLookup
+	}{
+		{
+			&NamespaceLookupQuery{},
+			&NamespaceLookup{
+				ListMeta:   api.ListMeta{TotalItems: 5},
+				Namespaces: []string{"Team-A", "default", "kube-system", "team-b", "team-c"},
+			},
+		},
+		{
+			&NamespaceLookupQuery{Filter: "team", Limit: 2},
+			&NamespaceLookup{
+				ListMeta:   api.ListMeta{TotalItems: 3},
+				Namespaces: []string{"Team-A", "team-b"},
+				Continue:   "team-b",
+			},
+		},
+		{
+			&NamespaceLookupQuery{Filter: "team", Limit: 2, Continue: "team-b"},
+			&NamespaceLookup{
+				ListMeta:   api.ListMeta{TotalItems: 3},
+				Namespaces: []string{"team-c"},
+			},
+		},
+		{
+			&NamespaceLookupQuery{Filter: "TEAM", Limit: 3},
+			&NamespaceLookup{
+				ListMeta:   api.ListMeta{TotalItems: 3},
+				Namespaces: []string{"Team-A", "team-b", "team-c"},
+			},
+		},
+		{
+			&NamespaceLookupQuery{Filter: "missing"},
+			&NamespaceLookup{
+				ListMeta:   api.ListMeta{TotalItems: 0},
+				Namespaces: []string{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := LookupNamespaces(names, c.query)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("LookupNamespaces(%#v) == \n%#v\nexpected \n%#v\n", c.query, actual, c.expected)
+		}
+	}
+}
+
+func TestNamespaceCache(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "foo"}},
+		&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "bar"}},
+	)
+	namespaceCache := NewNamespaceCache(client)
+
+	var names []string
+	synced := false
+	for i := 0; i < 100 && !synced; i++ {
+		names, synced = namespaceCache.Names()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !synced {
+		t.Fatal("namespace cache has not synced")
+	}
+	if expected := []string{"bar", "foo"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Names() == %v, expected %v", names, expected)
+	}
+}
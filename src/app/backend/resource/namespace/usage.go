@@ -0,0 +1,196 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/node"
+)
+
+// warningEventOptions lists only warning events.
+var warningEventOptions = metaV1.ListOptions{
+	FieldSelector: fields.OneTermEqualSelector("type", v1.EventTypeWarning).String(),
+}
+
+// NamespaceUsage is an overview of workloads running in a namespace.
+type NamespaceUsage struct {
+	// Pods contains the number of pods by their phase.
+	Pods map[v1.PodPhase]int `json:"pods"`
+
+	// Requests contains resources requested by pods, that did not terminate.
+	Requests v1.ResourceList `json:"requests"`
+
+	// TopWarning is the warning event, that occurred the most times, if there is any.
+	TopWarning *WarningEvent `json:"topWarning,omitempty"`
+
+	// Quotas contains utilization of resource quotas, the most utilized first.
+	Quotas []QuotaUtilization `json:"quotas"`
+}
+
+// WarningEvent is a short summary of a warning event.
+type WarningEvent struct {
+	Reason   string      `json:"reason"`
+	Message  string      `json:"message"`
+	Count    int32       `json:"count"`
+	Object   string      `json:"object"`
+	LastSeen metaV1.Time `json:"lastSeen"`
+}
+
+// QuotaUtilization is the utilization of a single resource limited by a resource quota.
+type QuotaUtilization struct {
+	Quota    string            `json:"quota"`
+	Resource v1.ResourceName   `json:"resource"`
+	Used     resource.Quantity `json:"used"`
+	Hard     resource.Quantity `json:"hard"`
+	Percent  float64           `json:"percent"`
+}
+
+// addUsage computes usage of the listed namespaces. Pods, events and quotas of all namespaces are requested
+// concurrently, so only namespaces of the current page should be passed.
+func addUsage(client kubernetes.Interface, namespaceList *NamespaceList) {
+	type namespaceChannels struct {
+		pods   common.PodListChannel
+		events common.EventListChannel
+		quotas common.ResourceQuotaListChannel
+	}
+
+	channels := make([]namespaceChannels, len(namespaceList.Namespaces))
+	for i, namespace := range namespaceList.Namespaces {
+		nsQuery := common.NewSameNamespaceQuery(namespace.ObjectMeta.Name)
+		channels[i] = namespaceChannels{
+			pods:   common.GetPodListChannel(client, nsQuery, 1),
+			events: common.GetEventListChannelWithOptions(client, nsQuery, warningEventOptions, 1),
+			quotas: common.GetResourceQuotaListChannel(client, nsQuery, 1),
+		}
+	}
+
+	for i := range namespaceList.Namespaces {
+		pods := <-channels[i].pods.List
+		podErr := <-channels[i].pods.Error
+		events := <-channels[i].events.List
+		eventErr := <-channels[i].events.Error
+		quotas := <-channels[i].quotas.List
+		quotaErr := <-channels[i].quotas.Error
+
+		for _, err := range []error{podErr, eventErr, quotaErr} {
+			namespaceList.Errors = appendUsageError(namespaceList.Errors, err)
+		}
+
+		usage := &NamespaceUsage{Pods: map[v1.PodPhase]int{}, Requests: v1.ResourceList{}, Quotas: []QuotaUtilization{}}
+		if podErr == nil {
+			usage.Pods, usage.Requests = getPodUsage(pods.Items)
+		}
+		if eventErr == nil {
+			usage.TopWarning = getTopWarning(events.Items)
+		}
+		if quotaErr == nil {
+			usage.Quotas = getQuotaUtilization(quotas.Items)
+		}
+		namespaceList.Namespaces[i].Usage = usage
+	}
+}
+
+// appendUsageError records err as a non-critical one, even if it would be critical for other lists, as usage
+// is only an addition to the namespace list.
+func appendUsageError(nonCriticalErrors []error, err error) []error {
+	nonCriticalErrors, criticalError := errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		nonCriticalErrors = append(nonCriticalErrors, criticalError)
+	}
+	return nonCriticalErrors
+}
+
+// getPodUsage counts pods by phase and sums requests of pods, that did not terminate.
+func getPodUsage(pods []v1.Pod) (map[v1.PodPhase]int, v1.ResourceList) {
+	phases := make(map[v1.PodPhase]int)
+	requests := v1.ResourceList{}
+	for i := range pods {
+		pod := &pods[i]
+		phases[pod.Status.Phase]++
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+
+		reqs, _, err := node.PodRequestsAndLimits(pod)
+		if err != nil {
+			continue
+		}
+		for name, quantity := range reqs {
+			if total, ok := requests[name]; ok {
+				total.Add(quantity)
+				requests[name] = total
+			} else {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	return phases, requests
+}
+
+// getTopWarning returns the warning event, that occurred the most times. Ties are resolved by recency.
+func getTopWarning(events []v1.Event) *WarningEvent {
+	var top *v1.Event
+	for i := range events {
+		event := &events[i]
+		if top == nil || event.Count > top.Count ||
+			(event.Count == top.Count && top.LastTimestamp.Before(&event.LastTimestamp)) {
+			top = event
+		}
+	}
+
+	if top == nil {
+		return nil
+	}
+	return &WarningEvent{
+		Reason:   top.Reason,
+		Message:  top.Message,
+		Count:    top.Count,
+		Object:   top.InvolvedObject.Kind + "/" + top.InvolvedObject.Name,
+		LastSeen: top.LastTimestamp,
+	}
+}
+
+// getQuotaUtilization returns utilization of all resources limited by given quotas, the most utilized first.
+func getQuotaUtilization(quotas []v1.ResourceQuota) []QuotaUtilization {
+	result := make([]QuotaUtilization, 0)
+	for _, quota := range quotas {
+		for name, hard := range quota.Status.Hard {
+			used := quota.Status.Used[name]
+			utilization := QuotaUtilization{Quota: quota.Name, Resource: name, Used: used, Hard: hard}
+			if hard.MilliValue() > 0 {
+				utilization.Percent = float64(used.MilliValue()) * 100 / float64(hard.MilliValue())
+			}
+			result = append(result, utilization)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Percent != result[j].Percent {
+			return result[i].Percent > result[j].Percent
+		}
+		return result[i].Quota+"/"+string(result[i].Resource) < result[j].Quota+"/"+string(result[j].Resource)
+	})
+	return result
+}
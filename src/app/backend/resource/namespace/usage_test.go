@@ -0,0 +1,92 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+)
+
+func newUsagePod(namespace, name string, phase v1.PodPhase, cpu string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}},
+		}}},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func TestGetNamespaceListUsage(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "shop"}},
+		newUsagePod("shop", "api-1", v1.PodRunning, "250m"),
+		newUsagePod("shop", "api-2", v1.PodRunning, "250m"),
+		newUsagePod("shop", "migrate", v1.PodSucceeded, "1"),
+		&v1.Event{
+			ObjectMeta:     metaV1.ObjectMeta{Namespace: "shop", Name: "a"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "BackOff",
+			Count:          12,
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "api-1"},
+			LastTimestamp:  metaV1.NewTime(now),
+		},
+		&v1.Event{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "shop", Name: "b"},
+			Type:       v1.EventTypeWarning,
+			Reason:     "FailedMount",
+			Count:      3,
+		},
+		&v1.ResourceQuota{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "shop", Name: "compute"},
+			Status: v1.ResourceQuotaStatus{
+				Hard: v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("2"),
+					v1.ResourcePods: resource.MustParse("4")},
+				Used: v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("500m"),
+					v1.ResourcePods: resource.MustParse("3")},
+			},
+		},
+	)
+
+	list, err := GetNamespaceList(client, dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("GetNamespaceList() returned error: %s", err)
+	}
+	if len(list.Namespaces) != 1 || list.Namespaces[0].Usage == nil {
+		t.Fatalf("GetNamespaceList() == %#v, expected one namespace with usage", list.Namespaces)
+	}
+
+	usage := list.Namespaces[0].Usage
+	if usage.Pods[v1.PodRunning] != 2 || usage.Pods[v1.PodSucceeded] != 1 {
+		t.Errorf("usage pods == %v, expected 2 running and 1 succeeded", usage.Pods)
+	}
+	if cpu := usage.Requests[v1.ResourceCPU]; cpu.MilliValue() != 500 {
+		t.Errorf("usage CPU requests == %s, expected 500m", cpu.String())
+	}
+	if usage.TopWarning == nil || usage.TopWarning.Reason != "BackOff" || usage.TopWarning.Object != "Pod/api-1" {
+		t.Errorf("usage top warning == %#v, expected BackOff of Pod/api-1", usage.TopWarning)
+	}
+	if len(usage.Quotas) != 2 || usage.Quotas[0].Resource != v1.ResourcePods || usage.Quotas[0].Percent != 75 {
+		t.Errorf("usage quotas == %#v, expected pods quota at 75%% first", usage.Quotas)
+	}
+}
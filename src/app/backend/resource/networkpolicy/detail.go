@@ -0,0 +1,66 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"log"
+
+	networking "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// NetworkPolicyDetail contains network policy details.
+type NetworkPolicyDetail struct {
+	// Extends list item structure.
+	NetworkPolicy `json:",inline"`
+
+	// Spec is the desired state of the network policy.
+	Spec networking.NetworkPolicySpec `json:"spec"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetNetworkPolicyDetail returns detailed information about a network policy.
+func GetNetworkPolicyDetail(client client.Interface, namespace, name string) (*NetworkPolicyDetail, error) {
+	log.Printf("Getting details of %s network policy in %s namespace", name, namespace)
+	policy, err := client.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), api.ListEverything)
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	result := &NetworkPolicyDetail{
+		NetworkPolicy: toNetworkPolicy(*policy),
+		Spec:          policy.Spec,
+		Errors:        nonCriticalErrors,
+	}
+
+	if err == nil {
+		result.Warnings = getSelectorWarnings(*policy, pods.Items)
+	}
+
+	return result, nil
+}
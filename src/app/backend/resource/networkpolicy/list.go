@@ -0,0 +1,139 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+)
+
+// NoSelectedPodsWarning is reported for network policies, whose pod selector does not match any pod.
+const NoSelectedPodsWarning = "Network policy pod selector does not match any pod"
+
+// NetworkPolicy is a presentation layer view of Kubernetes NetworkPolicy resource.
+type NetworkPolicy struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// PolicyTypes lists the types of traffic the policy applies to.
+	PolicyTypes []networking.PolicyType `json:"policyTypes"`
+
+	// Warnings about problems with the policy, i.e. pod selector that does not match any pod.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// NetworkPolicyList contains a list of network policies in the cluster.
+type NetworkPolicyList struct {
+	ListMeta api.ListMeta    `json:"listMeta"`
+	Items    []NetworkPolicy `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetNetworkPolicyList returns a list of all network policies in the given namespaces.
+func GetNetworkPolicyList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*NetworkPolicyList, error) {
+	log.Print("Getting list of network policies")
+	policies, err := client.NetworkingV1().NetworkPolicies(nsQuery.ToRequestParam()).List(context.TODO(),
+		api.ListEverything)
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	channels := &common.ResourceChannels{
+		PodList: common.GetPodListChannel(client, nsQuery, 1),
+	}
+
+	pods := <-channels.PodList.List
+	err = <-channels.PodList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	var podItems []v1.Pod
+	if err == nil && pods != nil {
+		podItems = pods.Items
+	}
+
+	var policyItems []networking.NetworkPolicy
+	for _, item := range policies.Items {
+		if nsQuery.Matches(item.Namespace) {
+			policyItems = append(policyItems, item)
+		}
+	}
+
+	return toNetworkPolicyList(policyItems, podItems, err == nil, nonCriticalErrors, dsQuery), nil
+}
+
+func toNetworkPolicyList(policies []networking.NetworkPolicy, pods []v1.Pod, podsKnown bool,
+	nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *NetworkPolicyList {
+	result := &NetworkPolicyList{
+		Items:  make([]NetworkPolicy, 0),
+		Errors: nonCriticalErrors,
+	}
+
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(policies), dsQuery)
+	policies = fromCells(cells)
+	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+
+	for _, policy := range policies {
+		item := toNetworkPolicy(policy)
+		if podsKnown {
+			item.Warnings = getSelectorWarnings(policy, pods)
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	return result
+}
+
+func toNetworkPolicy(policy networking.NetworkPolicy) NetworkPolicy {
+	return NetworkPolicy{
+		ObjectMeta:  api.NewObjectMeta(policy.ObjectMeta),
+		TypeMeta:    api.NewTypeMeta(api.ResourceKindNetworkPolicy),
+		PolicyTypes: policy.Spec.PolicyTypes,
+	}
+}
+
+// getSelectorWarnings returns warnings about network policy pod selector. Pods have to be listed from
+// the policy namespace.
+func getSelectorWarnings(policy networking.NetworkPolicy, pods []v1.Pod) []string {
+	selector, err := metaV1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	for _, pod := range pods {
+		if pod.Namespace == policy.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+			return nil
+		}
+	}
+
+	return []string{NoSelectedPodsWarning}
+}
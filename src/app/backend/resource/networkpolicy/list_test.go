@@ -0,0 +1,62 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+)
+
+func TestGetNetworkPolicyList(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&networking.NetworkPolicy{
+			ObjectMeta: metaV1.ObjectMeta{Name: "allow-web", Namespace: "default"},
+			Spec: networking.NetworkPolicySpec{
+				PodSelector: metaV1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+		},
+		&networking.NetworkPolicy{
+			ObjectMeta: metaV1.ObjectMeta{Name: "allow-db", Namespace: "default"},
+			Spec: networking.NetworkPolicySpec{
+				PodSelector: metaV1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+		},
+		&v1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default",
+			Labels: map[string]string{"app": "web"}}},
+	)
+
+	list, err := GetNetworkPolicyList(client, common.NewNamespaceQuery(nil), dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string][]string{"allow-web": nil, "allow-db": {NoSelectedPodsWarning}}
+	if len(list.Items) != len(expected) {
+		t.Fatalf("Expected %d network policies, but got %d", len(expected), len(list.Items))
+	}
+
+	for _, item := range list.Items {
+		if !reflect.DeepEqual(item.Warnings, expected[item.ObjectMeta.Name]) {
+			t.Errorf("Test Case: %s. Expected warnings %v, but got %v", item.ObjectMeta.Name,
+				expected[item.ObjectMeta.Name], item.Warnings)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/api"
 	"github.com/kubernetes/dashboard/src/app/backend/errors"
 	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 )
 
@@ -44,6 +45,11 @@ type Node struct {
 	TypeMeta           api.TypeMeta           `json:"typeMeta"`
 	Ready              v1.ConditionStatus     `json:"ready"`
 	AllocatedResources NodeAllocatedResources `json:"allocatedResources"`
+
+	// OS and Architecture of the node, e.g. windows and amd64. Pods have to tolerate taints of the node and select
+	// its operating system to run on it.
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
 }
 
 // GetNodeList returns a list of all Nodes in the cluster.
@@ -100,6 +106,8 @@ func toNode(node v1.Node, pods *v1.PodList) Node {
 		TypeMeta:           api.NewTypeMeta(api.ResourceKindNode),
 		Ready:              getNodeConditionStatus(node, v1.NodeReady),
 		AllocatedResources: allocatedResources,
+		OS:                 common.GetNodeOS(&node),
+		Architecture:       common.GetNodeArchitecture(&node),
 	}
 }
 
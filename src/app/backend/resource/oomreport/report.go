@@ -0,0 +1,336 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oomreport
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// ReasonOOMKilled is the termination reason of containers killed by the kernel OOM killer.
+const ReasonOOMKilled = "OOMKilled"
+
+// oomEventReasons are reasons of OOM kill events reported for pods, i.e. by the node problem detector.
+var oomEventReasons = []string{ReasonOOMKilled, "OOMKilling"}
+
+const (
+	// limitIncrease is the minimal suggested increase of the current memory limit.
+	limitIncrease = 1.25
+	// usageHeadroom is the headroom added on top of the observed peak usage.
+	usageHeadroom = 1.2
+	// mebibyte is the precision of suggested limits.
+	mebibyte = 1024 * 1024
+)
+
+// ContainerOOM contains OOM kills of a single container of a workload and its memory headroom.
+type ContainerOOM struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+	Container  string         `json:"container"`
+
+	// OOMKills is the number of OOM kills of the container in all workload pods.
+	OOMKills    int32        `json:"oomKills"`
+	LastOOMKill *metaV1.Time `json:"lastOOMKill,omitempty"`
+
+	MemoryLimit *resource.Quantity `json:"memoryLimit,omitempty"`
+	// PeakUsage is the container share of the highest memory usage of affected pods. Metrics are collected
+	// per pod, so usage is split between containers proportionally to their limits.
+	PeakUsage      *resource.Quantity `json:"peakUsage,omitempty"`
+	SuggestedLimit *resource.Quantity `json:"suggestedLimit,omitempty"`
+}
+
+// OOMReport contains containers killed by the OOM killer ordered by the number of kills.
+type OOMReport struct {
+	ListMeta   api.ListMeta   `json:"listMeta"`
+	Containers []ContainerOOM `json:"containers"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// containerKey identifies a container of a workload.
+type containerKey struct {
+	owner     common.ResourceOwner
+	container string
+}
+
+// GetOOMReport returns containers that were OOM killed, based on container statuses and OOM events reported
+// for pods. Metric client is optional, without it usage and usage based suggestions are not available.
+func GetOOMReport(client kubernetes.Interface, metricClient metricapi.MetricClient,
+	nsQuery *common.NamespaceQuery) (*OOMReport, error) {
+	log.Print("Getting OOM kill report")
+	channels := &common.ResourceChannels{
+		PodList:        common.GetPodListChannel(client, nsQuery, 1),
+		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
+		EventList:      common.GetEventListChannel(client, nsQuery, 1),
+	}
+
+	pods := <-channels.PodList.List
+	nonCriticalErrors, criticalError := errors.HandleError(<-channels.PodList.Error)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	replicaSets := <-channels.ReplicaSetList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.ReplicaSetList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	events := <-channels.EventList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.EventList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	containers := getContainerOOMs(pods.Items, replicaSets.Items, events.Items, metricClient)
+	return &OOMReport{
+		ListMeta:   api.ListMeta{TotalItems: len(containers)},
+		Containers: containers,
+		Errors:     nonCriticalErrors,
+	}, nil
+}
+
+func getContainerOOMs(pods []v1.Pod, replicaSets []apps.ReplicaSet, events []v1.Event,
+	metricClient metricapi.MetricClient) []ContainerOOM {
+	eventKills := getEventKills(events)
+
+	result := make(map[containerKey]*ContainerOOM)
+	keys := make([]containerKey, 0)
+	affectedPods := make([]v1.Pod, 0)
+	podKeys := make(map[string][]containerKey)
+	for _, pod := range pods {
+		kills, lastKill := getStatusKills(pod)
+		for container, count := range eventKills[string(pod.UID)] {
+			if count > kills[container] {
+				kills[container] = count
+			}
+		}
+		if len(kills) == 0 {
+			continue
+		}
+
+		owner := common.GetPodOwner(pod, replicaSets)
+		affectedPods = append(affectedPods, pod)
+		for _, container := range pod.Spec.Containers {
+			count, ok := kills[container.Name]
+			if !ok {
+				continue
+			}
+
+			key := containerKey{owner: owner, container: container.Name}
+			oom, ok := result[key]
+			if !ok {
+				oom = &ContainerOOM{
+					ObjectMeta:  api.ObjectMeta{Name: owner.Name, Namespace: owner.Namespace},
+					TypeMeta:    api.NewTypeMeta(owner.Kind),
+					Container:   container.Name,
+					MemoryLimit: getMemoryLimit(container),
+				}
+				result[key] = oom
+				keys = append(keys, key)
+			}
+
+			oom.OOMKills += count
+			if t, ok := lastKill[container.Name]; ok && (oom.LastOOMKill == nil || t.After(oom.LastOOMKill.Time)) {
+				last := t
+				oom.LastOOMKill = &last
+			}
+			podKeys[string(pod.UID)] = append(podKeys[string(pod.UID)], key)
+		}
+	}
+
+	usage := getPeakMemoryUsage(metricClient, affectedPods)
+	peaks := make(map[containerKey]int64)
+	for _, pod := range affectedPods {
+		peak, ok := usage[string(pod.UID)]
+		if !ok {
+			continue
+		}
+		for _, key := range podKeys[string(pod.UID)] {
+			if share := getContainerShare(pod.Spec.Containers, key.container, peak); share > peaks[key] {
+				peaks[key] = share
+			}
+		}
+	}
+
+	containers := make([]ContainerOOM, 0)
+	for _, key := range keys {
+		oom := result[key]
+		if peak, ok := peaks[key]; ok {
+			oom.PeakUsage = resource.NewQuantity(peak, resource.BinarySI)
+		}
+		oom.SuggestedLimit = getSuggestedLimit(oom.MemoryLimit, oom.PeakUsage)
+		containers = append(containers, *oom)
+	}
+
+	sort.SliceStable(containers, func(i, j int) bool { return containers[i].OOMKills > containers[j].OOMKills })
+	return containers
+}
+
+// getStatusKills returns number of OOM kills of pod containers visible in the current and last container
+// state, together with the time of the latest kill.
+func getStatusKills(pod v1.Pod) (map[string]int32, map[string]metaV1.Time) {
+	kills := make(map[string]int32)
+	lastKill := make(map[string]metaV1.Time)
+	for _, status := range pod.Status.ContainerStatuses {
+		for _, terminated := range []*v1.ContainerStateTerminated{status.State.Terminated,
+			status.LastTerminationState.Terminated} {
+			if terminated == nil || terminated.Reason != ReasonOOMKilled {
+				continue
+			}
+
+			kills[status.Name]++
+			if last, ok := lastKill[status.Name]; !ok || terminated.FinishedAt.After(last.Time) {
+				lastKill[status.Name] = terminated.FinishedAt
+			}
+		}
+	}
+	return kills, lastKill
+}
+
+// getEventKills returns number of OOM kills reported by events, mapped by pod UID and container name.
+func getEventKills(events []v1.Event) map[string]map[string]int32 {
+	result := make(map[string]map[string]int32)
+	for _, event := range events {
+		if event.InvolvedObject.Kind != "Pod" || !isOOMEvent(event) {
+			continue
+		}
+
+		container := getContainerName(event.InvolvedObject.FieldPath)
+		if len(container) == 0 {
+			continue
+		}
+
+		uid := string(event.InvolvedObject.UID)
+		if _, ok := result[uid]; !ok {
+			result[uid] = make(map[string]int32)
+		}
+
+		count := event.Count
+		if count <= 0 {
+			count = 1
+		}
+		result[uid][container] += count
+	}
+	return result
+}
+
+func isOOMEvent(event v1.Event) bool {
+	for _, reason := range oomEventReasons {
+		if event.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// getContainerName returns container name from event field path, i.e. "spec.containers{app}".
+func getContainerName(fieldPath string) string {
+	const prefix = "spec.containers{"
+	if !strings.HasPrefix(fieldPath, prefix) || !strings.HasSuffix(fieldPath, "}") {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(fieldPath, prefix), "}")
+}
+
+func getMemoryLimit(container v1.Container) *resource.Quantity {
+	if limit, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
+		return &limit
+	}
+	return nil
+}
+
+// getContainerShare splits pod usage between containers proportionally to their memory limits. Usage is split
+// evenly when not all containers have limits set.
+func getContainerShare(containers []v1.Container, name string, usage int64) int64 {
+	var total, own int64
+	for _, container := range containers {
+		limit := getMemoryLimit(container)
+		if limit == nil {
+			return usage / int64(len(containers))
+		}
+
+		total += limit.Value()
+		if container.Name == name {
+			own = limit.Value()
+		}
+	}
+
+	if total == 0 {
+		return usage / int64(len(containers))
+	}
+	return int64(float64(usage) * float64(own) / float64(total))
+}
+
+// getSuggestedLimit suggests memory limit, that is higher than both the current limit and the observed peak
+// usage. Nil is returned if neither of them is known.
+func getSuggestedLimit(limit, peakUsage *resource.Quantity) *resource.Quantity {
+	var suggested float64
+	if limit != nil {
+		suggested = float64(limit.Value()) * limitIncrease
+	}
+	if peakUsage != nil {
+		suggested = math.Max(suggested, float64(peakUsage.Value())*usageHeadroom)
+	}
+	if suggested == 0 {
+		return nil
+	}
+
+	rounded := int64(math.Ceil(suggested/mebibyte)) * mebibyte
+	return resource.NewQuantity(rounded, resource.BinarySI)
+}
+
+// getPeakMemoryUsage returns the highest memory usage of given pods, mapped by pod UID.
+func getPeakMemoryUsage(metricClient metricapi.MetricClient, pods []v1.Pod) map[string]int64 {
+	result := make(map[string]int64)
+	if metricClient == nil || len(pods) == 0 {
+		return result
+	}
+
+	for _, pod := range pods {
+		selector := []metricapi.ResourceSelector{{
+			Namespace:    pod.Namespace,
+			ResourceType: api.ResourceKindPod,
+			ResourceName: pod.Name,
+			UID:          pod.UID,
+		}}
+
+		metrics, _ := metricClient.DownloadMetric(selector, metricapi.MemoryUsage, metricapi.NoResourceCache).
+			GetMetrics()
+		for _, metric := range metrics {
+			for _, point := range metric.DataPoints {
+				if peak, ok := result[string(pod.UID)]; !ok || point.Y > peak {
+					result[string(pod.UID)] = point.Y
+				}
+			}
+		}
+	}
+
+	return result
+}
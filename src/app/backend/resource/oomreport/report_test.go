@@ -0,0 +1,109 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oomreport
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+func TestGetContainerOOMs(t *testing.T) {
+	controller := true
+	killed := metaV1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	newPod := func(name string, statuses ...v1.ContainerStatus) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name),
+				OwnerReferences: []metaV1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: &controller}}},
+			Spec: v1.PodSpec{Containers: []v1.Container{
+				{Name: "db", Resources: v1.ResourceRequirements{Limits: v1.ResourceList{
+					v1.ResourceMemory: resource.MustParse("256Mi")}}},
+				{Name: "exporter"},
+			}},
+			Status: v1.PodStatus{ContainerStatuses: statuses},
+		}
+	}
+
+	pods := []v1.Pod{
+		newPod("db-0", v1.ContainerStatus{Name: "db", LastTerminationState: v1.ContainerState{
+			Terminated: &v1.ContainerStateTerminated{Reason: ReasonOOMKilled, FinishedAt: killed}}}),
+		newPod("db-1", v1.ContainerStatus{Name: "db", LastTerminationState: v1.ContainerState{
+			Terminated: &v1.ContainerStateTerminated{Reason: "Error"}}}),
+		newPod("db-2"),
+	}
+	events := []v1.Event{{
+		Reason: "OOMKilling",
+		Count:  3,
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "db-2", UID: "uid-db-2",
+			FieldPath: "spec.containers{db}"},
+	}}
+
+	actual := getContainerOOMs(pods, nil, events, nil)
+	if len(actual) != 1 {
+		t.Fatalf("Expected 1 container, but got %#v", actual)
+	}
+
+	oom := actual[0]
+	if oom.TypeMeta.Kind != api.ResourceKindStatefulSet || oom.ObjectMeta.Name != "db" || oom.Container != "db" {
+		t.Errorf("Expected container db of stateful set db, but got %#v", oom)
+	}
+	if oom.OOMKills != 4 {
+		t.Errorf("Expected 4 OOM kills, but got %d", oom.OOMKills)
+	}
+	if oom.LastOOMKill == nil || !oom.LastOOMKill.Equal(&killed) {
+		t.Errorf("Expected last OOM kill at %v, but got %v", killed, oom.LastOOMKill)
+	}
+	if oom.SuggestedLimit == nil || oom.SuggestedLimit.String() != "320Mi" {
+		t.Errorf("Expected suggested limit 320Mi, but got %v", oom.SuggestedLimit)
+	}
+}
+
+func TestGetSuggestedLimit(t *testing.T) {
+	cases := []struct {
+		info            string
+		limit, usage    *resource.Quantity
+		expectedLimit   string
+		expectedMissing bool
+	}{
+		{"limit only", quantity("100Mi"), nil, "125Mi", false},
+		{"usage above limit increase", quantity("100Mi"), quantity("200Mi"), "240Mi", false},
+		{"usage only", nil, quantity("10Mi"), "12Mi", false},
+		{"unknown", nil, nil, "", true},
+	}
+
+	for _, c := range cases {
+		actual := getSuggestedLimit(c.limit, c.usage)
+		if c.expectedMissing {
+			if actual != nil {
+				t.Errorf("Test Case: %s. Expected no suggestion, but got %v", c.info, actual)
+			}
+			continue
+		}
+		if actual == nil || actual.String() != c.expectedLimit {
+			t.Errorf("Test Case: %s. Expected %s, but got %v", c.info, c.expectedLimit, actual)
+		}
+	}
+}
+
+func quantity(value string) *resource.Quantity {
+	q := resource.MustParse(value)
+	return &q
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openshift detects APIs served only by OpenShift and OKD clusters and reads their objects. Client of the
+// supported K8s version has no types of these APIs, so types declared here hold the fields used by resource modules.
+package openshift
+
+import (
+	"log"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+var (
+	routeV1   = schema.GroupVersion{Group: "route.openshift.io", Version: "v1"}
+	projectV1 = schema.GroupVersion{Group: "project.openshift.io", Version: "v1"}
+)
+
+// Capabilities tell which OpenShift APIs are served by the cluster.
+type Capabilities struct {
+	Routes   bool `json:"routes"`
+	Projects bool `json:"projects"`
+}
+
+// Detect checks which OpenShift APIs are served by the cluster. APIs, that can not be discovered, are treated as not
+// served.
+func Detect(client discovery.DiscoveryInterface) Capabilities {
+	return Capabilities{
+		Routes:   isServed(client, routeV1, "routes"),
+		Projects: isServed(client, projectV1, "projects"),
+	}
+}
+
+func isServed(client discovery.DiscoveryInterface, groupVersion schema.GroupVersion, resource string) bool {
+	list, err := client.ServerResourcesForGroupVersion(groupVersion.String())
+	if err != nil || list == nil {
+		return false
+	}
+
+	for _, item := range list.APIResources {
+		if item.Name == resource {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	current Capabilities
+	mux     sync.RWMutex
+)
+
+// SetCapabilities sets OpenShift APIs used by resource modules. Until it is set, none of them is used.
+func SetCapabilities(capabilities Capabilities) {
+	mux.Lock()
+	defer mux.Unlock()
+	current = capabilities
+	if capabilities.Routes || capabilities.Projects {
+		log.Printf("Detected OpenShift APIs, routes: %t, projects: %t", capabilities.Routes, capabilities.Projects)
+	}
+}
+
+// GetCapabilities returns OpenShift APIs served by the cluster.
+func GetCapabilities() Capabilities {
+	mux.RLock()
+	defer mux.RUnlock()
+	return current
+}
@@ -0,0 +1,150 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openshift
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		resources []*metaV1.APIResourceList
+		expected  Capabilities
+	}{
+		{nil, Capabilities{}},
+		{
+			[]*metaV1.APIResourceList{
+				{GroupVersion: "route.openshift.io/v1", APIResources: []metaV1.APIResource{{Name: "routes"}}},
+			},
+			Capabilities{Routes: true},
+		},
+		{
+			[]*metaV1.APIResourceList{
+				{GroupVersion: "route.openshift.io/v1", APIResources: []metaV1.APIResource{{Name: "routes/status"}}},
+				{GroupVersion: "project.openshift.io/v1", APIResources: []metaV1.APIResource{{Name: "projects"}}},
+			},
+			Capabilities{Projects: true},
+		},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset()
+		client.Resources = c.resources
+		if actual := Detect(client.Discovery().(*fakediscovery.FakeDiscovery)); actual != c.expected {
+			t.Errorf("Detect() == %+v, expected %+v", actual, c.expected)
+		}
+	}
+}
+
+// Returns client of a server responding to given paths with given bodies.
+func getTestClient(t *testing.T, responses map[string]string) (kubernetes.Interface, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+func TestListRoutes(t *testing.T) {
+	client, cleanup := getTestClient(t, map[string]string{
+		"/apis/route.openshift.io/v1/routes": `{"kind":"RouteList","items":[
+			{"metadata":{"name":"web","namespace":"default"},"spec":{"host":"web.example.com","to":{"kind":"Service",
+			"name":"web"},"tls":{"termination":"edge","key":"secret"}}}]}`,
+	})
+	defer cleanup()
+
+	list, err := ListRoutes(client, "", metaV1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListRoutes() returned error: %s", err)
+	}
+
+	if len(list.Items) != 1 || list.Items[0].Name != "web" || list.Items[0].Spec.To.Name != "web" ||
+		list.Items[0].Spec.TLS == nil || list.Items[0].Spec.TLS.Termination != "edge" {
+		t.Fatalf("ListRoutes() == %#v, expected web route", list.Items)
+	}
+
+	data, err := json.Marshal(list.Items[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("Route %s contains key of its TLS config", data)
+	}
+}
+
+func TestGetRoute(t *testing.T) {
+	client, cleanup := getTestClient(t, map[string]string{
+		"/apis/route.openshift.io/v1/namespaces/default/routes/web": `{"kind":"Route",
+			"metadata":{"name":"web","namespace":"default"},"spec":{"to":{"kind":"Service","name":"web"}},
+			"status":{"ingress":[{"host":"web.apps.example.com","conditions":[{"type":"Admitted","status":"True"}]}]}}`,
+	})
+	defer cleanup()
+
+	route, err := GetRoute(client, "default", "web")
+	if err != nil {
+		t.Fatalf("GetRoute() returned error: %s", err)
+	}
+
+	if len(route.Status.Ingress) != 1 || route.Status.Ingress[0].Host != "web.apps.example.com" {
+		t.Errorf("GetRoute() == %#v, expected route admitted at web.apps.example.com", route)
+	}
+
+	if _, err := GetRoute(client, "default", "missing"); err == nil {
+		t.Error("GetRoute() returned no error for missing route")
+	}
+}
+
+func TestListProjects(t *testing.T) {
+	client, cleanup := getTestClient(t, map[string]string{
+		"/apis/project.openshift.io/v1/projects": `{"kind":"ProjectList","items":[
+			{"metadata":{"name":"dev","labels":{"team":"a"}},"status":{"phase":"Active"}}]}`,
+	})
+	defer cleanup()
+
+	list, err := ListProjects(client, metaV1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListProjects() returned error: %s", err)
+	}
+
+	if len(list.Items) != 1 {
+		t.Fatalf("ListProjects() == %#v, expected dev project", list.Items)
+	}
+
+	namespace := list.Items[0].ToNamespace()
+	if namespace.Name != "dev" || namespace.Labels["team"] != "a" || namespace.Status.Phase != "Active" {
+		t.Errorf("ToNamespace() == %#v, expected active dev namespace", namespace)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openshift
+
+import (
+	"context"
+	"encoding/json"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Project is a namespace as seen by OpenShift users. Users can list projects of namespaces they have access to even
+// if they are not allowed to list namespaces.
+type Project struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+	Status            v1.NamespaceStatus `json:"status,omitempty"`
+}
+
+// ProjectList is a list of projects.
+type ProjectList struct {
+	metaV1.TypeMeta `json:",inline"`
+	metaV1.ListMeta `json:"metadata,omitempty"`
+	Items           []Project `json:"items"`
+}
+
+// ListProjects lists projects visible to the user of the client.
+func ListProjects(client kubernetes.Interface, options metaV1.ListOptions) (*ProjectList, error) {
+	result := &ProjectList{}
+	raw, err := resourceRequest(client, projectV1, "", "projects").
+		VersionedParams(&options, scheme.ParameterCodec).
+		DoRaw(context.TODO())
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal(raw, result)
+	return result, err
+}
+
+// ToNamespace returns namespace of the project.
+func (self Project) ToNamespace() v1.Namespace {
+	return v1.Namespace{
+		TypeMeta:   metaV1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
+		ObjectMeta: self.ObjectMeta,
+		Status:     self.Status,
+	}
+}
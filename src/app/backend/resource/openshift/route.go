@@ -0,0 +1,132 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openshift
+
+import (
+	"context"
+	"encoding/json"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// Route exposes a service at a host name. It holds fields of route.openshift.io/v1 route, except for certificates
+// and keys of its TLS config, so that they are never sent to the frontend.
+type Route struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RouteSpec   `json:"spec"`
+	Status            RouteStatus `json:"status,omitempty"`
+}
+
+// RouteList is a list of routes.
+type RouteList struct {
+	metaV1.TypeMeta `json:",inline"`
+	metaV1.ListMeta `json:"metadata,omitempty"`
+	Items           []Route `json:"items"`
+}
+
+// RouteSpec describes the host and the backends of a route.
+type RouteSpec struct {
+	Host              string                 `json:"host,omitempty"`
+	Subdomain         string                 `json:"subdomain,omitempty"`
+	Path              string                 `json:"path,omitempty"`
+	To                RouteTargetReference   `json:"to"`
+	AlternateBackends []RouteTargetReference `json:"alternateBackends,omitempty"`
+	Port              *RoutePort             `json:"port,omitempty"`
+	TLS               *TLSConfig             `json:"tls,omitempty"`
+	WildcardPolicy    string                 `json:"wildcardPolicy,omitempty"`
+}
+
+// RouteTargetReference points at a service receiving traffic of a route.
+type RouteTargetReference struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// RoutePort is the port of the target service receiving traffic of a route.
+type RoutePort struct {
+	TargetPort intstr.IntOrString `json:"targetPort"`
+}
+
+// TLSConfig tells how TLS connections of a route are terminated.
+type TLSConfig struct {
+	Termination                   string `json:"termination"`
+	InsecureEdgeTerminationPolicy string `json:"insecureEdgeTerminationPolicy,omitempty"`
+}
+
+// RouteStatus lists routers, that have admitted or rejected a route.
+type RouteStatus struct {
+	Ingress []RouteIngress `json:"ingress,omitempty"`
+}
+
+// RouteIngress is the state of a route in a single router.
+type RouteIngress struct {
+	Host                    string                  `json:"host,omitempty"`
+	RouterName              string                  `json:"routerName,omitempty"`
+	Conditions              []RouteIngressCondition `json:"conditions,omitempty"`
+	WildcardPolicy          string                  `json:"wildcardPolicy,omitempty"`
+	RouterCanonicalHostname string                  `json:"routerCanonicalHostname,omitempty"`
+}
+
+// RouteIngressCondition is a condition of a route in a router, i.e. whether the router has admitted it.
+type RouteIngressCondition struct {
+	Type               string       `json:"type"`
+	Status             string       `json:"status"`
+	Reason             string       `json:"reason,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastTransitionTime *metaV1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ListRoutes lists routes in given namespace.
+func ListRoutes(client kubernetes.Interface, namespace string, options metaV1.ListOptions) (*RouteList, error) {
+	result := &RouteList{}
+	raw, err := resourceRequest(client, routeV1, namespace, "routes").
+		VersionedParams(&options, scheme.ParameterCodec).
+		DoRaw(context.TODO())
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal(raw, result)
+	return result, err
+}
+
+// GetRoute returns route with given name.
+func GetRoute(client kubernetes.Interface, namespace, name string) (*Route, error) {
+	result := &Route{}
+	raw, err := resourceRequest(client, routeV1, namespace, "routes", name).DoRaw(context.TODO())
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal(raw, result)
+	return result, err
+}
+
+// Creates request for resources of an API group version, that has no client. Empty namespace means all namespaces.
+func resourceRequest(client kubernetes.Interface, groupVersion schema.GroupVersion, namespace string,
+	segments ...string) *rest.Request {
+	path := []string{"/apis", groupVersion.Group, groupVersion.Version}
+	if len(namespace) > 0 {
+		path = append(path, "namespaces", namespace)
+	}
+	return client.CoreV1().RESTClient().Get().AbsPath(append(path, segments...)...)
+}
@@ -0,0 +1,254 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// Pod states that can be cleaned up.
+const (
+	CleanupStateFailed    = "Failed"
+	CleanupStateSucceeded = "Succeeded"
+	CleanupStateEvicted   = "Evicted"
+)
+
+// evictedReason is a pod status reason set by kubelet on evicted pods.
+const evictedReason = "Evicted"
+
+// PodCleanupSpec describes which pods should be cleaned up.
+type PodCleanupSpec struct {
+	// States of pods to clean up. Supported values are Failed, Succeeded and Evicted.
+	States []string `json:"states"`
+
+	// OlderThan is a duration, i.e. "24h". Only pods that finished before that time are cleaned up. It is
+	// required when pods are deleted.
+	OlderThan string `json:"olderThan"`
+
+	// Confirm makes cleanup delete matching pods. Without it cleanup only returns pods that would be deleted.
+	Confirm bool `json:"confirm"`
+
+	// AllNamespaces has to be set to delete pods when cleanup is not limited to namespaces.
+	AllNamespaces bool `json:"allNamespaces"`
+}
+
+// PodCleanupCandidate is a pod that matches cleanup criteria.
+type PodCleanupCandidate struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+	State      string         `json:"state"`
+	FinishedAt metaV1.Time    `json:"finishedAt"`
+}
+
+// PodCleanupResult contains pods that were (or in case of a dry run would be) deleted.
+type PodCleanupResult struct {
+	DryRun bool                  `json:"dryRun"`
+	Pods   []PodCleanupCandidate `json:"pods"`
+
+	// Deleted is the number of pods that were actually deleted.
+	Deleted int `json:"deleted"`
+
+	// List of non-critical errors, that occurred during resource retrieval or deletion.
+	Errors []error `json:"errors"`
+}
+
+// CleanupPods deletes pods in the Failed, Succeeded or Evicted states that finished before the threshold
+// specified in the spec. Unless deletion is confirmed, it only returns pods that would be deleted.
+func CleanupPods(client client.Interface, nsQuery *common.NamespaceQuery, spec *PodCleanupSpec) (
+	*PodCleanupResult, error) {
+	return CleanupPodsWithProgress(context.TODO(), client, nsQuery, spec, nil)
+}
+
+// CleanupPodsWithProgress is like CleanupPods, but reports the number of processed pods to given function after
+// every deletion. Deletion stops once given context is done, pods deleted until then are returned together with
+// the error of the context.
+func CleanupPodsWithProgress(ctx context.Context, client client.Interface, nsQuery *common.NamespaceQuery,
+	spec *PodCleanupSpec, progress func(done, total int)) (*PodCleanupResult, error) {
+	if err := ValidatePodCleanupSpec(nsQuery, spec); err != nil {
+		return nil, err
+	}
+
+	states, olderThan, err := parseCleanupSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	dryRun := !spec.Confirm
+
+	channels := &common.ResourceChannels{
+		PodList: common.GetPodListChannel(client, nsQuery, 1),
+	}
+
+	pods := <-channels.PodList.List
+	err = <-channels.PodList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	result := &PodCleanupResult{DryRun: dryRun, Pods: make([]PodCleanupCandidate, 0)}
+	if pods != nil {
+		result.Pods = getCleanupCandidates(pods.Items, states, time.Now().Add(-olderThan))
+	}
+
+	if !dryRun {
+		for i, pod := range result.Pods {
+			if ctx.Err() != nil {
+				break
+			}
+
+			err := client.CoreV1().Pods(pod.ObjectMeta.Namespace).Delete(ctx, pod.ObjectMeta.Name,
+				metaV1.DeleteOptions{})
+			if err != nil && ctx.Err() != nil {
+				break
+			} else if err != nil && !errors.IsNotFoundError(err) {
+				nonCriticalErrors = append(nonCriticalErrors, err)
+			} else {
+				result.Deleted++
+			}
+
+			if progress != nil {
+				progress(i+1, len(result.Pods))
+			}
+		}
+		log.Printf("Cleaned up %d of %d pods", result.Deleted, len(result.Pods))
+	}
+
+	result.Errors = nonCriticalErrors
+	return result, ctx.Err()
+}
+
+// ValidatePodCleanupSpec returns an error if the cleanup described by given spec cannot be done, i.e. when pods of
+// all namespaces would be deleted without explicit consent.
+func ValidatePodCleanupSpec(nsQuery *common.NamespaceQuery, spec *PodCleanupSpec) error {
+	_, olderThan, err := parseCleanupSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	if spec.Confirm && olderThan == 0 {
+		return errors.NewBadRequest("olderThan is required to delete pods")
+	}
+	if spec.Confirm && nsQuery.ToRequestParam() == metaV1.NamespaceAll && !spec.AllNamespaces {
+		return errors.NewBadRequest("namespace or allNamespaces is required to delete pods")
+	}
+
+	return nil
+}
+
+func parseCleanupSpec(spec *PodCleanupSpec) (map[string]bool, time.Duration, error) {
+	states := make(map[string]bool)
+	for _, state := range spec.States {
+		switch strings.ToLower(state) {
+		case strings.ToLower(CleanupStateFailed):
+			states[CleanupStateFailed] = true
+		case strings.ToLower(CleanupStateSucceeded):
+			states[CleanupStateSucceeded] = true
+		case strings.ToLower(CleanupStateEvicted):
+			states[CleanupStateEvicted] = true
+		default:
+			return nil, 0, errors.NewBadRequest(fmt.Sprintf("unsupported pod cleanup state: %s", state))
+		}
+	}
+
+	if len(states) == 0 {
+		states[CleanupStateFailed] = true
+		states[CleanupStateEvicted] = true
+	}
+
+	var olderThan time.Duration
+	if len(spec.OlderThan) > 0 {
+		var err error
+		if olderThan, err = time.ParseDuration(spec.OlderThan); err != nil {
+			return nil, 0, errors.NewBadRequest(err.Error())
+		}
+		if olderThan < 0 {
+			return nil, 0, errors.NewBadRequest(fmt.Sprintf("olderThan can not be negative: %s", spec.OlderThan))
+		}
+	}
+
+	return states, olderThan, nil
+}
+
+func getCleanupCandidates(pods []v1.Pod, states map[string]bool, finishedBefore time.Time) []PodCleanupCandidate {
+	result := make([]PodCleanupCandidate, 0)
+	for _, pod := range pods {
+		state := getCleanupState(pod)
+		if !states[state] {
+			continue
+		}
+
+		finishedAt := getFinishTime(pod)
+		if finishedAt.Time.After(finishedBefore) {
+			continue
+		}
+
+		result = append(result, PodCleanupCandidate{
+			ObjectMeta: api.NewObjectMeta(pod.ObjectMeta),
+			TypeMeta:   api.NewTypeMeta(api.ResourceKindPod),
+			State:      state,
+			FinishedAt: finishedAt,
+		})
+	}
+
+	return result
+}
+
+// getCleanupState returns cleanup state of the pod or empty string if pod is still active.
+func getCleanupState(pod v1.Pod) string {
+	switch pod.Status.Phase {
+	case v1.PodFailed:
+		if pod.Status.Reason == evictedReason {
+			return CleanupStateEvicted
+		}
+		return CleanupStateFailed
+	case v1.PodSucceeded:
+		return CleanupStateSucceeded
+	}
+
+	return ""
+}
+
+// getFinishTime returns the time when the last container of the pod terminated. Pods evicted before their
+// containers started do not have it, so start or creation time is used instead.
+func getFinishTime(pod v1.Pod) metaV1.Time {
+	var finishedAt metaV1.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil && finishedAt.Before(&status.State.Terminated.FinishedAt) {
+			finishedAt = status.State.Terminated.FinishedAt
+		}
+	}
+
+	if finishedAt.IsZero() && pod.Status.StartTime != nil {
+		finishedAt = *pod.Status.StartTime
+	}
+
+	if finishedAt.IsZero() {
+		finishedAt = pod.CreationTimestamp
+	}
+
+	return finishedAt
+}
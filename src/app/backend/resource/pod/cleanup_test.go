@@ -0,0 +1,133 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+func newFinishedPod(name string, phase v1.PodPhase, reason string, age time.Duration) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default",
+			CreationTimestamp: metaV1.NewTime(time.Now().Add(-age))},
+		Status: v1.PodStatus{Phase: phase, Reason: reason},
+	}
+}
+
+func TestCleanupPods(t *testing.T) {
+	cases := []struct {
+		spec            *PodCleanupSpec
+		namespaces      []string
+		expectedPods    []string
+		expectedDeleted int
+		expectedLeft    int
+	}{
+		{
+			&PodCleanupSpec{OlderThan: "1h"}, nil,
+			[]string{"failed-old", "evicted-old"}, 0, 5,
+		},
+		{
+			&PodCleanupSpec{States: []string{"succeeded"}, OlderThan: "1h", Confirm: true}, []string{"default"},
+			[]string{"succeeded-old"}, 1, 4,
+		},
+		{
+			&PodCleanupSpec{States: []string{"Failed", "Evicted"}, OlderThan: "1h", Confirm: true,
+				AllNamespaces: true}, nil,
+			[]string{"failed-old", "evicted-old"}, 2, 3,
+		},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset(
+			newFinishedPod("failed-old", v1.PodFailed, "", 2*time.Hour),
+			newFinishedPod("evicted-old", v1.PodFailed, evictedReason, 2*time.Hour),
+			newFinishedPod("failed-new", v1.PodFailed, "", time.Minute),
+			newFinishedPod("succeeded-old", v1.PodSucceeded, "", 2*time.Hour),
+			newFinishedPod("running", v1.PodRunning, "", 2*time.Hour),
+		)
+
+		result, err := CleanupPods(client, common.NewNamespaceQuery(c.namespaces), c.spec)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		actual := make([]string, 0)
+		for _, pod := range result.Pods {
+			actual = append(actual, pod.ObjectMeta.Name)
+		}
+
+		if len(actual) != len(c.expectedPods) || result.Deleted != c.expectedDeleted {
+			t.Errorf("Test Case: %v. Expected pods %v and %d deleted, but got %v and %d deleted", c.spec,
+				c.expectedPods, c.expectedDeleted, actual, result.Deleted)
+		}
+
+		left, _ := client.CoreV1().Pods("default").List(context.TODO(), metaV1.ListOptions{})
+		if len(left.Items) != c.expectedLeft {
+			t.Errorf("Test Case: %v. Expected %d pods left, but got %d", c.spec, c.expectedLeft, len(left.Items))
+		}
+	}
+}
+
+func TestCleanupPodsInvalidSpec(t *testing.T) {
+	cases := []struct {
+		info       string
+		spec       *PodCleanupSpec
+		namespaces []string
+	}{
+		{"unsupported state", &PodCleanupSpec{States: []string{"Running"}}, nil},
+		{"negative age", &PodCleanupSpec{OlderThan: "-1h"}, nil},
+		{"deletion without age", &PodCleanupSpec{Confirm: true}, []string{"default"}},
+		{"deletion in all namespaces", &PodCleanupSpec{OlderThan: "1h", Confirm: true}, nil},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset(newFinishedPod("failed-old", v1.PodFailed, "", 2*time.Hour))
+		if _, err := CleanupPods(client, common.NewNamespaceQuery(c.namespaces), c.spec); err == nil {
+			t.Errorf("Test Case: %s. Expected error", c.info)
+		}
+	}
+}
+
+func TestCleanupPodsWithProgress(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newFinishedPod("failed-old", v1.PodFailed, "", 2*time.Hour),
+		newFinishedPod("evicted-old", v1.PodFailed, evictedReason, 2*time.Hour),
+	)
+	spec := &PodCleanupSpec{OlderThan: "1h", Confirm: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	reported := make([]int, 0)
+
+	// Cleanup is cancelled after the first pod.
+	result, err := CleanupPodsWithProgress(ctx, client, common.NewNamespaceQuery([]string{"default"}), spec,
+		func(done, total int) {
+			reported = append(reported, done, total)
+			cancel()
+		})
+	if err != context.Canceled {
+		t.Errorf("Expected cancellation error, but got %v", err)
+	}
+
+	if result == nil || result.Deleted != 1 || len(reported) != 2 || reported[0] != 1 || reported[1] != 2 {
+		t.Errorf("Expected one of two pods to be deleted, but got %+v and progress %v", result, reported)
+	}
+}
@@ -55,6 +55,18 @@ type PodDetail struct {
 	EventList                 common.EventList                                `json:"eventList"`
 	PersistentvolumeclaimList persistentvolumeclaim.PersistentVolumeClaimList `json:"persistentVolumeClaimList"`
 
+	// PodIPs are all addresses of the pod, one per IP family in dual-stack clusters. PodIP is the first of them.
+	PodIPs     []string      `json:"podIPs,omitempty"`
+	IPFamilies []v1.IPFamily `json:"ipFamilies,omitempty"`
+
+	// NodeSelector and Tolerations decide which nodes, e.g. of which operating system, can run the pod.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	Tolerations  []v1.Toleration   `json:"tolerations,omitempty"`
+
+	// OS of the pod containers, e.g. linux or windows. It is empty if the pod does not select it and it is not
+	// scheduled yet.
+	OS string `json:"os,omitempty"`
+
 	// List of non-critical errors, that occurred during resource retrieval.
 	Errors []error `json:"errors"`
 }
@@ -145,6 +157,7 @@ func GetPodDetail(client kubernetes.Interface, metricClient metricapi.MetricClie
 
 	podDetail := toPodDetail(pod, metrics, configMapList, secretList, controller,
 		eventList, persistentVolumeClaimList, nonCriticalErrors)
+	podDetail.OS = common.GetPodOS(client, pod)
 	return &podDetail, nil
 }
 
@@ -225,10 +238,30 @@ func toPodDetail(pod *v1.Pod, metrics []metricapi.Metric, configMaps *v1.ConfigM
 		Conditions:                getPodConditions(*pod),
 		EventList:                 *events,
 		PersistentvolumeclaimList: *persistentVolumeClaimList,
+		PodIPs:                    getPodIPs(pod),
+		IPFamilies:                common.GetIPFamilies(getPodIPs(pod)),
+		NodeSelector:              pod.Spec.NodeSelector,
+		Tolerations:               pod.Spec.Tolerations,
 		Errors:                    nonCriticalErrors,
 	}
 }
 
+// getPodIPs returns all addresses of the pod. Clusters older than 1.16 set only the primary address.
+func getPodIPs(pod *v1.Pod) []string {
+	if len(pod.Status.PodIPs) == 0 {
+		if len(pod.Status.PodIP) == 0 {
+			return nil
+		}
+		return []string{pod.Status.PodIP}
+	}
+
+	ips := make([]string, 0, len(pod.Status.PodIPs))
+	for _, podIP := range pod.Status.PodIPs {
+		ips = append(ips, podIP.IP)
+	}
+	return ips
+}
+
 func evalEnvFrom(container v1.Container, configMaps *v1.ConfigMapList, secrets *v1.SecretList) []EnvVar {
 	vars := make([]EnvVar, 0)
 	for _, envFromVar := range container.EnvFrom {
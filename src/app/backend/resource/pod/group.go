@@ -0,0 +1,69 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+)
+
+// GetPodGroupList returns pods grouped by value of given label. Data select query is applied to every group.
+func GetPodGroupList(client k8sClient.Interface, metricClient metricapi.MetricClient, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery, label string) (*common.GroupedList, error) {
+	log.Printf("Getting list of all pods in the cluster grouped by %s label", label)
+
+	channels := &common.ResourceChannels{
+		PodList:   common.GetPodListChannelWithOptions(client, nsQuery, metaV1.ListOptions{}, 1),
+		EventList: common.GetEventListChannel(client, nsQuery, 1),
+	}
+
+	pods := <-channels.PodList.List
+	err := <-channels.PodList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	eventList := <-channels.EventList.List
+	err = <-channels.EventList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	result := common.NewGroupedList(label, nonCriticalErrors)
+	values, groups := common.GroupByLabel(label, len(pods.Items), func(i int) map[string]string {
+		return pods.Items[i].Labels
+	})
+	for _, value := range values {
+		group := &v1.PodList{Items: make([]v1.Pod, 0, len(groups[value]))}
+		for _, i := range groups[value] {
+			group.Items = append(group.Items, pods.Items[i])
+		}
+
+		podList := ToPodList(group.Items, eventList.Items, nil, dsQuery, metricClient)
+		podList.Status = getStatus(group, eventList.Items)
+		result.AddGroup(value, podList)
+	}
+
+	return result, nil
+}
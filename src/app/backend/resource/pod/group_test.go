@@ -0,0 +1,81 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetPodGroupList(t *testing.T) {
+	newPod := func(name, partOf string, phase v1.PodPhase) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{}},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+		if phase == v1.PodRunning {
+			pod.Status.Conditions = []v1.PodCondition{
+				{Type: v1.PodInitialized, Status: v1.ConditionTrue},
+				{Type: v1.PodReady, Status: v1.ConditionTrue},
+			}
+		}
+		if len(partOf) > 0 {
+			pod.Labels["app.kubernetes.io/part-of"] = partOf
+		}
+		return pod
+	}
+
+	client := fake.NewSimpleClientset(
+		newPod("shop-web", "shop", v1.PodRunning),
+		newPod("shop-db", "shop", v1.PodPending),
+		newPod("blog", "blog", v1.PodRunning),
+		newPod("other", "", v1.PodSucceeded),
+	)
+
+	result, err := GetPodGroupList(client, nil, common.NewSameNamespaceQuery("default"), dataselect.NoDataSelect,
+		"app.kubernetes.io/part-of")
+	if err != nil {
+		t.Fatalf("GetPodGroupList() unexpected error: %v", err)
+	}
+
+	if result.ListMeta.TotalItems != 3 {
+		t.Fatalf("Expected 3 groups, got %d", result.ListMeta.TotalItems)
+	}
+
+	expected := []struct {
+		value   string
+		total   int
+		running int
+		pending int
+	}{
+		{"blog", 1, 1, 0},
+		{"shop", 2, 1, 1},
+		{"", 1, 0, 0},
+	}
+	for i, e := range expected {
+		group := result.Groups[i]
+		list := group.List.(PodList)
+		if group.Value != e.value || list.ListMeta.TotalItems != e.total || list.Status.Running != e.running ||
+			list.Status.Pending != e.pending {
+			t.Errorf("Group %d: expected %+v, got value %q with %d pods and status %+v", i, e, group.Value,
+				list.ListMeta.TotalItems, list.Status)
+		}
+	}
+}
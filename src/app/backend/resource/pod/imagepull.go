@@ -0,0 +1,249 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ImagePullPhase is a phase of pulling image of a single container.
+type ImagePullPhase string
+
+const (
+	// ImagePullWaiting means that kubelet has not started pulling the image yet.
+	ImagePullWaiting ImagePullPhase = "Waiting"
+	// ImagePulling means that the image is being pulled.
+	ImagePulling ImagePullPhase = "Pulling"
+	// ImagePulled means that the image is present on the node.
+	ImagePulled ImagePullPhase = "Pulled"
+	// ImagePullFailed means that the last pull attempt has failed.
+	ImagePullFailed ImagePullPhase = "Failed"
+)
+
+// Waiting reasons reported by kubelet in container status when image can not be pulled.
+var imagePullFailureReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// ImagePullStatus is a pull status of the image of a single container. Kubelet does not report progress of
+// single layers, so images are the smallest reported unit.
+type ImagePullStatus struct {
+	// Name of the container.
+	Container string `json:"container"`
+
+	// Whether the container is an init container.
+	InitContainer bool `json:"initContainer"`
+
+	// Image URI of the container.
+	Image string `json:"image"`
+
+	Phase ImagePullPhase `json:"phase"`
+
+	// Message of the latest event or status related to the pull.
+	Message string `json:"message,omitempty"`
+
+	StartedAt  *metaV1.Time `json:"startedAt,omitempty"`
+	FinishedAt *metaV1.Time `json:"finishedAt,omitempty"`
+}
+
+// ImagePullProgress shows progress of pulling images of all containers of a pod. It is meant to be polled
+// while the pod is starting.
+type ImagePullProgress struct {
+	PodPhase v1.PodPhase `json:"podPhase"`
+
+	// Images in the order in which kubelet pulls them, init containers first.
+	Images []ImagePullStatus `json:"images"`
+
+	// Number of already pulled images.
+	Pulled int `json:"pulled"`
+
+	// Total number of images.
+	Total int `json:"total"`
+
+	// Whether any of the images failed to be pulled.
+	Failed bool `json:"failed"`
+
+	// Pull related events sorted from oldest to newest.
+	Events []common.Event `json:"events"`
+}
+
+// GetImagePullProgress returns progress of pulling images of the given pod based on kubelet events and
+// container statuses.
+func GetImagePullProgress(client kubernetes.Interface, namespace, name string) (*ImagePullProgress, error) {
+	pod, err := client.CoreV1().Pods(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	channels := &common.ResourceChannels{
+		EventList: common.GetEventListChannelWithOptions(client, common.NewSameNamespaceQuery(namespace),
+			metaV1.ListOptions{
+				FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", string(pod.UID)).String(),
+			}, 1),
+	}
+
+	eventList := <-channels.EventList.List
+	if err := <-channels.EventList.Error; err != nil {
+		return nil, err
+	}
+
+	return toImagePullProgress(pod, eventList.Items), nil
+}
+
+func toImagePullProgress(pod *v1.Pod, events []v1.Event) *ImagePullProgress {
+	pullEvents := getImagePullEvents(pod, events)
+	result := &ImagePullProgress{
+		PodPhase: pod.Status.Phase,
+		Images:   make([]ImagePullStatus, 0),
+		Events:   make([]common.Event, 0, len(pullEvents)),
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		status := getContainerStatus(pod.Status.InitContainerStatuses, container.Name)
+		fieldPath := fmt.Sprintf("spec.initContainers{%s}", container.Name)
+		result.Images = append(result.Images,
+			toImagePullStatus(container, true, status, filterEventsByFieldPath(pullEvents, fieldPath)))
+	}
+
+	for _, container := range pod.Spec.Containers {
+		status := getContainerStatus(pod.Status.ContainerStatuses, container.Name)
+		fieldPath := fmt.Sprintf("spec.containers{%s}", container.Name)
+		result.Images = append(result.Images,
+			toImagePullStatus(container, false, status, filterEventsByFieldPath(pullEvents, fieldPath)))
+	}
+
+	for _, image := range result.Images {
+		switch image.Phase {
+		case ImagePulled:
+			result.Pulled++
+		case ImagePullFailed:
+			result.Failed = true
+		}
+	}
+	result.Total = len(result.Images)
+
+	for _, e := range pullEvents {
+		result.Events = append(result.Events, event.ToEvent(e))
+	}
+
+	return result
+}
+
+func toImagePullStatus(container v1.Container, init bool, status *v1.ContainerStatus,
+	events []v1.Event) ImagePullStatus {
+	result := ImagePullStatus{
+		Container:     container.Name,
+		InitContainer: init,
+		Image:         container.Image,
+		Phase:         ImagePullWaiting,
+	}
+
+	for i := range events {
+		e := events[i]
+		result.Message = e.Message
+		switch e.Reason {
+		case "Pulling":
+			result.Phase = ImagePulling
+			if result.StartedAt == nil {
+				result.StartedAt = &e.FirstTimestamp
+			}
+			result.FinishedAt = nil
+		case "Pulled":
+			result.Phase = ImagePulled
+			result.FinishedAt = &e.LastTimestamp
+		default:
+			result.Phase = ImagePullFailed
+		}
+	}
+
+	if status == nil {
+		return result
+	}
+
+	// Container status is more reliable than events, which might have been already garbage collected.
+	if len(status.ImageID) > 0 || status.State.Running != nil || status.State.Terminated != nil {
+		result.Phase = ImagePulled
+	} else if waiting := status.State.Waiting; waiting != nil && imagePullFailureReasons[waiting.Reason] {
+		result.Phase = ImagePullFailed
+		if len(waiting.Message) > 0 {
+			result.Message = waiting.Message
+		}
+	}
+
+	return result
+}
+
+// Returns image pull events of the given pod sorted from oldest to newest.
+func getImagePullEvents(pod *v1.Pod, events []v1.Event) []v1.Event {
+	result := make([]v1.Event, 0)
+	for _, e := range events {
+		if e.InvolvedObject.UID == pod.UID && isImagePullEvent(e) {
+			result = append(result, e)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LastTimestamp.Before(&result[j].LastTimestamp)
+	})
+
+	return result
+}
+
+// Kubelet reports pull failures as "Failed" and "BackOff" events, which are also used for other container
+// failures, so these are recognized by the message.
+func isImagePullEvent(e v1.Event) bool {
+	switch e.Reason {
+	case "Pulling", "Pulled":
+		return true
+	case "Failed", "BackOff", "ErrImageNeverPull", "InspectFailed":
+		return strings.Contains(strings.ToLower(e.Message), "image")
+	}
+
+	return false
+}
+
+func filterEventsByFieldPath(events []v1.Event, fieldPath string) []v1.Event {
+	result := make([]v1.Event, 0)
+	for _, e := range events {
+		if e.InvolvedObject.FieldPath == fieldPath {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+func getContainerStatus(statuses []v1.ContainerStatus, name string) *v1.ContainerStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newImagePullEvent(uid types.UID, fieldPath, reason, message string, timestamp time.Time) v1.Event {
+	return v1.Event{
+		InvolvedObject: v1.ObjectReference{UID: uid, FieldPath: fieldPath},
+		Reason:         reason,
+		Message:        message,
+		FirstTimestamp: metaV1.NewTime(timestamp),
+		LastTimestamp:  metaV1.NewTime(timestamp),
+	}
+}
+
+func TestToImagePullProgress(t *testing.T) {
+	now := time.Now()
+	pod := &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "pod-1", Namespace: "ns-1", UID: "uid-1"},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init", Image: "busybox"}},
+			Containers:     []v1.Container{{Name: "app", Image: "big-image"}, {Name: "bad", Image: "missing"}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			InitContainerStatuses: []v1.ContainerStatus{{
+				Name:    "init",
+				ImageID: "docker-pullable://busybox@sha256:1234",
+				State:   v1.ContainerState{Terminated: &v1.ContainerStateTerminated{}},
+			}},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+				{Name: "bad", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{
+					Reason: "ImagePullBackOff", Message: "Back-off pulling image \"missing\""}}},
+			},
+		},
+	}
+
+	events := []v1.Event{
+		newImagePullEvent("uid-1", "spec.containers{app}", "Pulling", "Pulling image \"big-image\"",
+			now.Add(-time.Minute)),
+		newImagePullEvent("uid-1", "spec.initContainers{init}", "Pulled", "Successfully pulled image \"busybox\"",
+			now.Add(-2*time.Minute)),
+		newImagePullEvent("uid-1", "spec.initContainers{init}", "Pulling", "Pulling image \"busybox\"",
+			now.Add(-3*time.Minute)),
+		newImagePullEvent("uid-1", "spec.containers{bad}", "Failed", "Failed to pull image \"missing\"",
+			now.Add(-time.Minute)),
+		newImagePullEvent("uid-1", "spec.containers{bad}", "BackOff", "Back-off restarting failed container",
+			now),
+		newImagePullEvent("uid-2", "spec.containers{app}", "Pulled", "Successfully pulled image \"big-image\"",
+			now),
+	}
+
+	progress := toImagePullProgress(pod, events)
+
+	if progress.Total != 3 || progress.Pulled != 1 || !progress.Failed {
+		t.Fatalf("Expected 1 of 3 images pulled with failures, but got %d of %d (failed: %t)",
+			progress.Pulled, progress.Total, progress.Failed)
+	}
+
+	expected := []struct {
+		container string
+		phase     ImagePullPhase
+	}{
+		{"init", ImagePulled},
+		{"app", ImagePulling},
+		{"bad", ImagePullFailed},
+	}
+	for i, e := range expected {
+		image := progress.Images[i]
+		if image.Container != e.container || image.Phase != e.phase {
+			t.Errorf("Expected image of %s container to be %s, but got %s container with %s phase",
+				e.container, e.phase, image.Container, image.Phase)
+		}
+	}
+
+	if progress.Images[0].StartedAt == nil || progress.Images[0].FinishedAt == nil {
+		t.Errorf("Expected pull start and finish of init container image to be set, but got %#v", progress.Images[0])
+	}
+
+	if progress.Images[2].Message != "Back-off pulling image \"missing\"" {
+		t.Errorf("Expected failure message to come from container status, but got %s", progress.Images[2].Message)
+	}
+
+	if len(progress.Events) != 4 {
+		t.Fatalf("Expected 4 image pull events, but got %d", len(progress.Events))
+	}
+
+	for i := 1; i < len(progress.Events); i++ {
+		if progress.Events[i].LastSeen.Before(&progress.Events[i-1].LastSeen) {
+			t.Errorf("Expected image pull events to be sorted from oldest to newest")
+		}
+	}
+}
@@ -0,0 +1,377 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policyreport
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// Sources of policy violations.
+const (
+	// SourcePolicyReport means that violation comes from a PolicyReport or ClusterPolicyReport, i.e. of Kyverno.
+	SourcePolicyReport = "policyreport"
+	// SourceGatekeeper means that violation comes from the status of an OPA Gatekeeper constraint.
+	SourceGatekeeper = "gatekeeper"
+)
+
+// Results of policy evaluation, that are reported as violations.
+const (
+	ResultFail  = "fail"
+	ResultWarn  = "warn"
+	ResultError = "error"
+)
+
+var (
+	policyReportResource = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2",
+		Resource: "policyreports"}
+	clusterPolicyReportResource = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2",
+		Resource: "clusterpolicyreports"}
+	constraintTemplateResource = schema.GroupVersionResource{Group: "templates.gatekeeper.sh", Version: "v1beta1",
+		Resource: "constrainttemplates"}
+	constraintGroupVersion = schema.GroupVersion{Group: "constraints.gatekeeper.sh", Version: "v1beta1"}
+
+	// pathRegexp matches the path of the violating field in messages of Kyverno validation rules.
+	pathRegexp = regexp.MustCompile(`at path (/\S*)`)
+)
+
+// Violation is a single policy failure of a resource.
+type Violation struct {
+	Source    string            `json:"source"`
+	Policy    string            `json:"policy"`
+	Rule      string            `json:"rule,omitempty"`
+	Result    string            `json:"result"`
+	Severity  string            `json:"severity,omitempty"`
+	Message   string            `json:"message"`
+	Path      string            `json:"path,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// WorkloadViolations contains policy violations of a single resource.
+type WorkloadViolations struct {
+	Namespace  string      `json:"namespace,omitempty"`
+	Kind       string      `json:"kind"`
+	Name       string      `json:"name"`
+	Violations []Violation `json:"violations"`
+}
+
+// WorkloadViolationList contains resources of a namespace with at least one policy violation.
+type WorkloadViolationList struct {
+	ListMeta  api.ListMeta         `json:"listMeta"`
+	Workloads []WorkloadViolations `json:"workloads"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// NamespaceViolations summarizes policy violations in a single namespace. Violations of cluster scoped
+// resources are reported with an empty namespace.
+type NamespaceViolations struct {
+	Namespace  string         `json:"namespace"`
+	Violations int            `json:"violations"`
+	Workloads  int            `json:"workloads"`
+	ByResult   map[string]int `json:"byResult"`
+}
+
+// PolicyViolationSummary contains policy violations per namespace together with the detected policy engines.
+type PolicyViolationSummary struct {
+	Sources    []string              `json:"sources"`
+	Namespaces []NamespaceViolations `json:"namespaces"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetPolicyViolationSummary returns number of policy violations per namespace.
+func GetPolicyViolationSummary(dynamicClient dynamic.Interface) (*PolicyViolationSummary, error) {
+	violations, sources, nonCriticalErrors, err := getViolations(dynamicClient, "")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PolicyViolationSummary{Sources: sources, Namespaces: []NamespaceViolations{},
+		Errors: nonCriticalErrors}
+	for namespace, workloads := range groupByWorkload(violations) {
+		ns := NamespaceViolations{Namespace: namespace, Workloads: len(workloads), ByResult: map[string]int{}}
+		for _, w := range workloads {
+			for _, v := range w.Violations {
+				ns.Violations++
+				ns.ByResult[v.Result]++
+			}
+		}
+		summary.Namespaces = append(summary.Namespaces, ns)
+	}
+
+	sort.Slice(summary.Namespaces, func(i, j int) bool {
+		if summary.Namespaces[i].Violations != summary.Namespaces[j].Violations {
+			return summary.Namespaces[i].Violations > summary.Namespaces[j].Violations
+		}
+		return summary.Namespaces[i].Namespace < summary.Namespaces[j].Namespace
+	})
+	return summary, nil
+}
+
+// GetWorkloadViolationList returns resources of given namespace with policy violations, the most violating
+// ones first. Kind and name optionally narrow it down to a single resource.
+func GetWorkloadViolationList(dynamicClient dynamic.Interface, namespace, kind, name string) (
+	*WorkloadViolationList, error) {
+	violations, _, nonCriticalErrors, err := getViolations(dynamicClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WorkloadViolationList{Workloads: []WorkloadViolations{}, Errors: nonCriticalErrors}
+	for _, w := range groupByWorkload(violations)[namespace] {
+		if (len(kind) == 0 || strings.EqualFold(w.Kind, kind)) && (len(name) == 0 || w.Name == name) {
+			result.Workloads = append(result.Workloads, w)
+		}
+	}
+
+	sort.Slice(result.Workloads, func(i, j int) bool {
+		a, b := result.Workloads[i], result.Workloads[j]
+		if len(a.Violations) != len(b.Violations) {
+			return len(a.Violations) > len(b.Violations)
+		}
+		return a.Kind+"/"+a.Name < b.Kind+"/"+b.Name
+	})
+	result.ListMeta.TotalItems = len(result.Workloads)
+	return result, nil
+}
+
+// groupByWorkload groups violations by namespaces and affected resources.
+func groupByWorkload(violations []Violation) map[string][]WorkloadViolations {
+	type key struct{ namespace, kind, name string }
+	index := make(map[key]int)
+	result := make(map[string][]WorkloadViolations)
+	for _, v := range violations {
+		k := key{v.Namespace, v.Kind, v.Name}
+		i, ok := index[k]
+		if !ok {
+			i = len(result[v.Namespace])
+			index[k] = i
+			result[v.Namespace] = append(result[v.Namespace], WorkloadViolations{Namespace: v.Namespace,
+				Kind: v.Kind, Name: v.Name, Violations: []Violation{}})
+		}
+		result[v.Namespace][i].Violations = append(result[v.Namespace][i].Violations, v)
+	}
+
+	return result
+}
+
+// getViolations collects violations of resources in given namespace, or in all namespaces if it is empty,
+// from all detected policy engines. Engines, that are not installed, are skipped.
+func getViolations(dynamicClient dynamic.Interface, namespace string) (
+	violations []Violation, sources []string, nonCriticalErrors []error, err error) {
+	violations, sources, nonCriticalErrors = []Violation{}, []string{}, []error{}
+
+	reports, err := getPolicyReportViolations(dynamicClient, namespace)
+	if !k8serrors.IsNotFound(err) {
+		nonCriticalErrors, err = errors.AppendError(err, nonCriticalErrors)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		sources = append(sources, SourcePolicyReport)
+		violations = append(violations, reports...)
+	}
+
+	constraints, err := getGatekeeperViolations(dynamicClient, namespace)
+	if !k8serrors.IsNotFound(err) {
+		nonCriticalErrors, err = errors.AppendError(err, nonCriticalErrors)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		sources = append(sources, SourceGatekeeper)
+		violations = append(violations, constraints...)
+	}
+
+	return violations, sources, nonCriticalErrors, nil
+}
+
+// getPolicyReportViolations returns failed results of PolicyReports of given namespace and of
+// ClusterPolicyReports.
+func getPolicyReportViolations(dynamicClient dynamic.Interface, namespace string) ([]Violation, error) {
+	reports, err := dynamicClient.Resource(policyReportResource).Namespace(namespace).
+		List(context.TODO(), api.ListEverything)
+	if err != nil {
+		return nil, err
+	}
+
+	items := reports.Items
+	clusterReports, err := dynamicClient.Resource(clusterPolicyReportResource).List(context.TODO(),
+		api.ListEverything)
+	if err == nil {
+		items = append(items, clusterReports.Items...)
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	result := make([]Violation, 0)
+	for _, report := range items {
+		for _, v := range parsePolicyReport(report) {
+			if len(namespace) == 0 || v.Namespace == namespace {
+				result = append(result, v)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// parsePolicyReport returns failed results of a PolicyReport or ClusterPolicyReport.
+func parsePolicyReport(report unstructured.Unstructured) []Violation {
+	result := make([]Violation, 0)
+	scope, _, _ := unstructured.NestedMap(report.Object, "scope")
+	results, _, _ := unstructured.NestedSlice(report.Object, "results")
+	for _, r := range results {
+		entry, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		outcome := strings.ToLower(nestedString(entry, "result"))
+		if len(outcome) == 0 {
+			// Results of the v1alpha1 version have status instead of result.
+			outcome = strings.ToLower(nestedString(entry, "status"))
+		}
+		if outcome != ResultFail && outcome != ResultWarn && outcome != ResultError {
+			continue
+		}
+
+		message := nestedString(entry, "message")
+		properties, _, _ := unstructured.NestedStringMap(entry, "properties")
+		violation := Violation{
+			Source:   SourcePolicyReport,
+			Policy:   nestedString(entry, "policy"),
+			Rule:     nestedString(entry, "rule"),
+			Result:   outcome,
+			Severity: nestedString(entry, "severity"),
+			Message:  message,
+			Path:     getPath(message),
+			Details:  properties,
+		}
+
+		resources, _, _ := unstructured.NestedSlice(entry, "resources")
+		if len(resources) == 0 && scope != nil {
+			resources = []interface{}{scope}
+		}
+		for _, res := range resources {
+			ref, ok := res.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			v := violation
+			v.Kind, v.Name, v.Namespace = nestedString(ref, "kind"), nestedString(ref, "name"),
+				nestedString(ref, "namespace")
+			if len(v.Namespace) == 0 {
+				v.Namespace = report.GetNamespace()
+			}
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// getGatekeeperViolations returns violations recorded by audit in the status of Gatekeeper constraints.
+func getGatekeeperViolations(dynamicClient dynamic.Interface, namespace string) ([]Violation, error) {
+	templates, err := dynamicClient.Resource(constraintTemplateResource).List(context.TODO(), api.ListEverything)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Violation, 0)
+	for _, template := range templates.Items {
+		kind, _, _ := unstructured.NestedString(template.Object, "spec", "crd", "spec", "names", "kind")
+		if len(kind) == 0 {
+			continue
+		}
+
+		constraints, err := dynamicClient.Resource(constraintGroupVersion.WithResource(strings.ToLower(kind))).
+			List(context.TODO(), api.ListEverything)
+		if k8serrors.IsNotFound(err) {
+			// Constraint CRD is created asynchronously after the template.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, constraint := range constraints.Items {
+			for _, v := range parseConstraint(kind, constraint) {
+				if len(namespace) == 0 || v.Namespace == namespace {
+					result = append(result, v)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// parseConstraint returns violations listed in the status of a Gatekeeper constraint.
+func parseConstraint(kind string, constraint unstructured.Unstructured) []Violation {
+	result := make([]Violation, 0)
+	violations, _, _ := unstructured.NestedSlice(constraint.Object, "status", "violations")
+	for _, v := range violations {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		outcome := ResultFail
+		if action := nestedString(entry, "enforcementAction"); action == "dryrun" || action == "warn" {
+			outcome = ResultWarn
+		}
+
+		result = append(result, Violation{
+			Source:    SourceGatekeeper,
+			Policy:    kind,
+			Rule:      constraint.GetName(),
+			Result:    outcome,
+			Message:   nestedString(entry, "message"),
+			Namespace: nestedString(entry, "namespace"),
+			Kind:      nestedString(entry, "kind"),
+			Name:      nestedString(entry, "name"),
+		})
+	}
+
+	return result
+}
+
+// getPath extracts the path of the violating field from the violation message, if it is present.
+func getPath(message string) string {
+	if match := pathRegexp.FindStringSubmatch(message); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func nestedString(obj map[string]interface{}, field string) string {
+	value, _, _ := unstructured.NestedString(obj, field)
+	return value
+}
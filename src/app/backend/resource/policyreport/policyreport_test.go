@@ -0,0 +1,113 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policyreport
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParsePolicyReport(t *testing.T) {
+	report := unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "PolicyReport",
+		"metadata": map[string]interface{}{"name": "polr-ns-default", "namespace": "default"},
+		"results": []interface{}{
+			map[string]interface{}{
+				"policy":   "disallow-latest-tag",
+				"rule":     "validate-image-tag",
+				"result":   "fail",
+				"severity": "medium",
+				"message": "validation error: Using a mutable image tag is not allowed. Rule validate-image-tag " +
+					"failed at path /spec/containers/0/image/",
+				"resources": []interface{}{
+					map[string]interface{}{"apiVersion": "v1", "kind": "Pod", "name": "web-0"},
+				},
+				"properties": map[string]interface{}{"category": "Best Practices"},
+			},
+			map[string]interface{}{
+				"policy": "require-labels",
+				"result": "pass",
+				"resources": []interface{}{
+					map[string]interface{}{"apiVersion": "v1", "kind": "Pod", "name": "web-0"},
+				},
+			},
+		},
+	}}
+
+	expected := []Violation{{
+		Source:   SourcePolicyReport,
+		Policy:   "disallow-latest-tag",
+		Rule:     "validate-image-tag",
+		Result:   ResultFail,
+		Severity: "medium",
+		Message: "validation error: Using a mutable image tag is not allowed. Rule validate-image-tag " +
+			"failed at path /spec/containers/0/image/",
+		Path:      "/spec/containers/0/image/",
+		Namespace: "default",
+		Kind:      "Pod",
+		Name:      "web-0",
+		Details:   map[string]string{"category": "Best Practices"},
+	}}
+
+	if actual := parsePolicyReport(report); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("parsePolicyReport() == %#v, expected %#v", actual, expected)
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	constraint := unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "K8sRequiredLabels",
+		"metadata": map[string]interface{}{"name": "ns-must-have-owner"},
+		"status": map[string]interface{}{
+			"violations": []interface{}{
+				map[string]interface{}{"enforcementAction": "deny", "kind": "Deployment", "name": "api",
+					"namespace": "shop", "message": "you must provide labels: {\"owner\"}"},
+				map[string]interface{}{"enforcementAction": "dryrun", "kind": "Deployment", "name": "cart",
+					"namespace": "shop", "message": "you must provide labels: {\"owner\"}"},
+			},
+		},
+	}}
+
+	actual := parseConstraint("K8sRequiredLabels", constraint)
+	if len(actual) != 2 {
+		t.Fatalf("parseConstraint() returned %d violations, expected 2", len(actual))
+	}
+	if actual[0].Result != ResultFail || actual[1].Result != ResultWarn {
+		t.Errorf("parseConstraint() results == %s, %s, expected %s, %s", actual[0].Result, actual[1].Result,
+			ResultFail, ResultWarn)
+	}
+	if actual[0].Policy != "K8sRequiredLabels" || actual[0].Rule != "ns-must-have-owner" ||
+		actual[0].Namespace != "shop" || actual[0].Name != "api" {
+		t.Errorf("parseConstraint() returned unexpected violation %#v", actual[0])
+	}
+}
+
+func TestGroupByWorkload(t *testing.T) {
+	violations := []Violation{
+		{Policy: "a", Namespace: "shop", Kind: "Deployment", Name: "api"},
+		{Policy: "b", Namespace: "default", Kind: "Pod", Name: "web-0"},
+		{Policy: "c", Namespace: "shop", Kind: "Deployment", Name: "api"},
+	}
+
+	actual := groupByWorkload(violations)
+	if len(actual["shop"]) != 1 || len(actual["shop"][0].Violations) != 2 {
+		t.Errorf("groupByWorkload() grouped shop namespace into %#v, expected one workload", actual["shop"])
+	}
+	if len(actual["default"]) != 1 || actual["default"][0].Name != "web-0" {
+		t.Errorf("groupByWorkload() grouped default namespace into %#v", actual["default"])
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// ReasonUnhealthy is the reason of events reported by kubelet when container probe fails.
+const ReasonUnhealthy = "Unhealthy"
+
+// Probe types, that failure events are reported for.
+const (
+	ProbeLiveness  = "liveness"
+	ProbeReadiness = "readiness"
+	ProbeStartup   = "startup"
+)
+
+// Default time range of probe failure analytics.
+const (
+	DefaultWindow = time.Hour
+	DefaultBucket = 5 * time.Minute
+)
+
+// unhealthyEventOptions lists only probe failure events.
+var unhealthyEventOptions = metaV1.ListOptions{
+	FieldSelector: fields.OneTermEqualSelector("reason", ReasonUnhealthy).String(),
+}
+
+// ContainerProbes is the current probe configuration of a single container.
+type ContainerProbes struct {
+	Container      string    `json:"container"`
+	LivenessProbe  *v1.Probe `json:"livenessProbe,omitempty"`
+	ReadinessProbe *v1.Probe `json:"readinessProbe,omitempty"`
+	StartupProbe   *v1.Probe `json:"startupProbe,omitempty"`
+}
+
+// ProbeFailurePoint is the number of probe failures, that happened within a single time bucket starting at
+// the timestamp.
+type ProbeFailurePoint struct {
+	Timestamp metaV1.Time `json:"timestamp"`
+	Liveness  int32       `json:"liveness"`
+	Readiness int32       `json:"readiness"`
+	Startup   int32       `json:"startup"`
+}
+
+// ProbeAnalytics contains probe configuration of a workload and probe failures of its pods over time.
+type ProbeAnalytics struct {
+	ObjectMeta api.ObjectMeta      `json:"objectMeta"`
+	TypeMeta   api.TypeMeta        `json:"typeMeta"`
+	Probes     []ContainerProbes   `json:"probes"`
+	Series     []ProbeFailurePoint `json:"series"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// workload contains workload data required to find probe failures of its pods.
+type workload struct {
+	meta     metaV1.ObjectMeta
+	template v1.PodTemplateSpec
+	selector *metaV1.LabelSelector
+}
+
+// GetProbeAnalytics returns probe failures of the workload pods within the window, grouped into buckets of
+// given size. Only failures of existing pods are taken into account, as events do not identify the
+// workload of deleted pods.
+func GetProbeAnalytics(client kubernetes.Interface, kind, namespace, name string, window,
+	bucket time.Duration) (*ProbeAnalytics, error) {
+	log.Printf("Getting probe failure analytics of %s %s in %s namespace", kind, name, namespace)
+	w, err := getWorkload(client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metaV1.LabelSelectorAsSelector(w.selector)
+	if err != nil {
+		return nil, err
+	}
+
+	nsQuery := common.NewSameNamespaceQuery(namespace)
+	channels := &common.ResourceChannels{
+		PodList: common.GetPodListChannelWithOptions(client, nsQuery,
+			metaV1.ListOptions{LabelSelector: selector.String()}, 1),
+		EventList: common.GetEventListChannelWithOptions(client, nsQuery, unhealthyEventOptions, 1),
+	}
+
+	pods := <-channels.PodList.List
+	nonCriticalErrors, criticalError := errors.HandleError(<-channels.PodList.Error)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	events := <-channels.EventList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.EventList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	uids := make(map[types.UID]bool)
+	for _, pod := range pods.Items {
+		uids[pod.UID] = true
+	}
+
+	failures := make([]v1.Event, 0)
+	for _, event := range events.Items {
+		if uids[event.InvolvedObject.UID] {
+			failures = append(failures, event)
+		}
+	}
+
+	return &ProbeAnalytics{
+		ObjectMeta: api.NewObjectMeta(w.meta),
+		TypeMeta:   api.NewTypeMeta(api.ResourceKind(strings.ToLower(kind))),
+		Probes:     getContainerProbes(w.template.Spec),
+		Series:     getFailureSeries(failures, time.Now(), window, bucket),
+		Errors:     nonCriticalErrors,
+	}, nil
+}
+
+func getWorkload(client kubernetes.Interface, kind, namespace, name string) (*workload, error) {
+	switch api.ResourceKind(strings.ToLower(kind)) {
+	case api.ResourceKindDeployment:
+		d, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{meta: d.ObjectMeta, template: d.Spec.Template, selector: d.Spec.Selector}, nil
+	case api.ResourceKindStatefulSet:
+		s, err := client.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{meta: s.ObjectMeta, template: s.Spec.Template, selector: s.Spec.Selector}, nil
+	case api.ResourceKindDaemonSet:
+		d, err := client.AppsV1().DaemonSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{meta: d.ObjectMeta, template: d.Spec.Template, selector: d.Spec.Selector}, nil
+	case api.ResourceKindReplicaSet:
+		r, err := client.AppsV1().ReplicaSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{meta: r.ObjectMeta, template: r.Spec.Template, selector: r.Spec.Selector}, nil
+	}
+
+	return nil, errors.NewInvalid(fmt.Sprintf("probe analytics is not supported for kind: %s", kind))
+}
+
+func getContainerProbes(spec v1.PodSpec) []ContainerProbes {
+	result := make([]ContainerProbes, 0)
+	for _, container := range spec.Containers {
+		if container.LivenessProbe == nil && container.ReadinessProbe == nil && container.StartupProbe == nil {
+			continue
+		}
+		result = append(result, ContainerProbes{
+			Container:      container.Name,
+			LivenessProbe:  container.LivenessProbe,
+			ReadinessProbe: container.ReadinessProbe,
+			StartupProbe:   container.StartupProbe,
+		})
+	}
+	return result
+}
+
+// getFailureSeries splits the window ending now into buckets and counts probe failures within each of them.
+func getFailureSeries(events []v1.Event, now time.Time, window, bucket time.Duration) []ProbeFailurePoint {
+	if window <= 0 {
+		return make([]ProbeFailurePoint, 0)
+	}
+	if bucket <= 0 || bucket > window {
+		bucket = window
+	}
+
+	count := int(window / bucket)
+	start := now.Add(-time.Duration(count) * bucket)
+	series := make([]ProbeFailurePoint, count)
+	for i := range series {
+		series[i].Timestamp = metaV1.NewTime(start.Add(time.Duration(i) * bucket))
+	}
+
+	for _, event := range events {
+		timestamp := getEventTime(event)
+		if timestamp.Before(start) || timestamp.After(now) {
+			continue
+		}
+
+		i := int(timestamp.Sub(start) / bucket)
+		if i >= count {
+			i = count - 1
+		}
+
+		switch getProbeType(event) {
+		case ProbeLiveness:
+			series[i].Liveness += getEventCount(event)
+		case ProbeReadiness:
+			series[i].Readiness += getEventCount(event)
+		case ProbeStartup:
+			series[i].Startup += getEventCount(event)
+		}
+	}
+
+	return series
+}
+
+// getProbeType returns type of the probe, that failed, based on the kubelet event message, i.e.
+// "Readiness probe failed: HTTP probe failed with statuscode: 503". Empty string is returned for events
+// that are not probe failures.
+func getProbeType(event v1.Event) string {
+	if event.Reason != ReasonUnhealthy {
+		return ""
+	}
+
+	message := strings.ToLower(event.Message)
+	for _, probe := range []string{ProbeLiveness, ProbeReadiness, ProbeStartup} {
+		if strings.HasPrefix(message, probe+" probe") {
+			return probe
+		}
+	}
+	return ""
+}
+
+// getEventTime returns the time of the last occurrence of the event.
+func getEventTime(event v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// getEventCount returns number of occurrences of the event. Kubelet aggregates repeated failures of the same
+// probe into a single event.
+func getEventCount(event v1.Event) int32 {
+	if event.Count > 0 {
+		return event.Count
+	}
+	return 1
+}
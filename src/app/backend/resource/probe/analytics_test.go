@@ -0,0 +1,123 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+func newProbeEvent(pod v1.Pod, message string, count int32, timestamp time.Time) v1.Event {
+	return v1.Event{
+		Reason:         ReasonUnhealthy,
+		Message:        message,
+		Count:          count,
+		LastTimestamp:  metaV1.NewTime(timestamp),
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: pod.Name, Namespace: pod.Namespace, UID: pod.UID},
+	}
+}
+
+func TestGetFailureSeries(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	pod := v1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "pod", UID: "pod-uid"}}
+	events := []v1.Event{
+		newProbeEvent(pod, "Liveness probe failed: connection refused", 2, now.Add(-50*time.Minute)),
+		newProbeEvent(pod, "Readiness probe failed: HTTP probe failed with statuscode: 503", 3,
+			now.Add(-5*time.Minute)),
+		newProbeEvent(pod, "Readiness probe errored: rpc error", 0, now.Add(-time.Minute)),
+		newProbeEvent(pod, "Liveness probe failed: too old", 1, now.Add(-2*time.Hour)),
+		{Reason: "BackOff", Message: "Back-off restarting failed container", LastTimestamp: metaV1.NewTime(now)},
+	}
+
+	cases := []struct {
+		info           string
+		window, bucket time.Duration
+		expected       []ProbeFailurePoint
+	}{
+		{
+			"two buckets",
+			time.Hour, 30 * time.Minute,
+			[]ProbeFailurePoint{
+				{Timestamp: metaV1.NewTime(now.Add(-time.Hour)), Liveness: 2},
+				{Timestamp: metaV1.NewTime(now.Add(-30 * time.Minute)), Readiness: 4},
+			},
+		},
+		{
+			"bucket larger than window",
+			10 * time.Minute, time.Hour,
+			[]ProbeFailurePoint{{Timestamp: metaV1.NewTime(now.Add(-10 * time.Minute)), Readiness: 4}},
+		},
+		{
+			"empty window",
+			0, time.Minute,
+			[]ProbeFailurePoint{},
+		},
+	}
+
+	for _, c := range cases {
+		actual := getFailureSeries(events, now, c.window, c.bucket)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Expected series %#v, but got %#v", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestGetFlappingWorkloads(t *testing.T) {
+	now := time.Now()
+	controller := true
+	newPod := func(name string, uid types.UID, ownerKind, ownerName string, ready v1.ConditionStatus) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default", UID: uid,
+				OwnerReferences: []metaV1.OwnerReference{{Kind: ownerKind, Name: ownerName,
+					UID: types.UID(ownerName), Controller: &controller}}},
+			Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}}},
+		}
+	}
+
+	replicaSets := []apps.ReplicaSet{{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "default", UID: "web-1",
+			OwnerReferences: []metaV1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &controller}}},
+	}}
+	web := newPod("web-1-a", "web-a", "ReplicaSet", "web-1", v1.ConditionTrue)
+	db := newPod("db-0", "db-0", "StatefulSet", "db", v1.ConditionFalse)
+	cache := newPod("cache-0", "cache-0", "StatefulSet", "cache", v1.ConditionTrue)
+
+	events := []v1.Event{
+		newProbeEvent(web, "Readiness probe failed: timeout", 2, now.Add(-time.Minute)),
+		newProbeEvent(web, "Readiness probe failed: timeout", 2, now.Add(-2*time.Minute)),
+		newProbeEvent(web, "Readiness probe failed: timeout", 5, now.Add(-2*time.Hour)),
+		newProbeEvent(db, "Readiness probe failed: timeout", 10, now.Add(-time.Minute)),
+		newProbeEvent(cache, "Liveness probe failed: timeout", 10, now.Add(-time.Minute)),
+	}
+
+	actual := getFlappingWorkloads([]v1.Pod{web, db, cache}, replicaSets, events, now.Add(-time.Hour), 3)
+	if len(actual) != 1 {
+		t.Fatalf("Expected 1 flapping workload, but got %#v", actual)
+	}
+
+	w := actual[0]
+	if w.TypeMeta.Kind != api.ResourceKindDeployment || w.ObjectMeta.Name != "web" ||
+		w.ReadinessFailures != 4 || w.AffectedPods != 1 || w.ReadyPods != 1 {
+		t.Errorf("Expected deployment web with 4 readiness failures, but got %#v", w)
+	}
+}
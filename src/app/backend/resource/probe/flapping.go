@@ -0,0 +1,167 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// DefaultFlappingThreshold is the default number of readiness probe failures within the window, after
+// which a workload is reported as flapping.
+const DefaultFlappingThreshold = 3
+
+// FlappingWorkload is a workload, which pods repeatedly fail readiness probe but keep becoming ready again.
+type FlappingWorkload struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// ReadinessFailures is the number of readiness probe failures within the window.
+	ReadinessFailures int32 `json:"readinessFailures"`
+	// AffectedPods is the number of pods, that failed readiness probe within the window.
+	AffectedPods int `json:"affectedPods"`
+	// ReadyPods is the number of affected pods, that are ready now.
+	ReadyPods   int         `json:"readyPods"`
+	LastFailure metaV1.Time `json:"lastFailure"`
+}
+
+// FlappingReport contains workloads with flapping readiness ordered by the number of failures.
+type FlappingReport struct {
+	ListMeta  api.ListMeta       `json:"listMeta"`
+	Workloads []FlappingWorkload `json:"workloads"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetFlappingReport returns workloads, which pods failed readiness probe at least threshold times within the
+// window, while at least one of the affected pods is ready now. Workloads that are down for good are not
+// flapping and are not reported.
+func GetFlappingReport(client kubernetes.Interface, nsQuery *common.NamespaceQuery, window time.Duration,
+	threshold int32) (*FlappingReport, error) {
+	log.Print("Getting flapping readiness report")
+	channels := &common.ResourceChannels{
+		PodList:        common.GetPodListChannel(client, nsQuery, 1),
+		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
+		EventList:      common.GetEventListChannelWithOptions(client, nsQuery, unhealthyEventOptions, 1),
+	}
+
+	pods := <-channels.PodList.List
+	nonCriticalErrors, criticalError := errors.HandleError(<-channels.PodList.Error)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	replicaSets := <-channels.ReplicaSetList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.ReplicaSetList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	events := <-channels.EventList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.EventList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	workloads := getFlappingWorkloads(pods.Items, replicaSets.Items, events.Items, time.Now().Add(-window),
+		threshold)
+	return &FlappingReport{
+		ListMeta:  api.ListMeta{TotalItems: len(workloads)},
+		Workloads: workloads,
+		Errors:    nonCriticalErrors,
+	}, nil
+}
+
+func getFlappingWorkloads(pods []v1.Pod, replicaSets []apps.ReplicaSet, events []v1.Event, since time.Time,
+	threshold int32) []FlappingWorkload {
+	podsByUID := make(map[types.UID]v1.Pod)
+	for _, pod := range pods {
+		podsByUID[pod.UID] = pod
+	}
+
+	workloads := make(map[common.ResourceOwner]*FlappingWorkload)
+	affected := make(map[types.UID]bool)
+	for _, event := range events {
+		pod, ok := podsByUID[event.InvolvedObject.UID]
+		if !ok || getProbeType(event) != ProbeReadiness {
+			continue
+		}
+
+		timestamp := getEventTime(event)
+		if timestamp.Before(since) {
+			continue
+		}
+
+		owner := common.GetPodOwner(pod, replicaSets)
+		w, ok := workloads[owner]
+		if !ok {
+			w = &FlappingWorkload{
+				ObjectMeta: api.ObjectMeta{Name: owner.Name, Namespace: owner.Namespace},
+				TypeMeta:   api.NewTypeMeta(owner.Kind),
+			}
+			workloads[owner] = w
+		}
+
+		w.ReadinessFailures += getEventCount(event)
+		if timestamp.After(w.LastFailure.Time) {
+			w.LastFailure = metaV1.NewTime(timestamp)
+		}
+		if !affected[pod.UID] {
+			affected[pod.UID] = true
+			w.AffectedPods++
+			if isReady(pod) {
+				w.ReadyPods++
+			}
+		}
+	}
+
+	result := make([]FlappingWorkload, 0)
+	for _, w := range workloads {
+		if w.ReadinessFailures >= threshold && w.ReadyPods > 0 {
+			result = append(result, *w)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].ReadinessFailures != result[j].ReadinessFailures {
+			return result[i].ReadinessFailures > result[j].ReadinessFailures
+		}
+		return result[i].ObjectMeta.Namespace+"/"+result[i].ObjectMeta.Name <
+			result[j].ObjectMeta.Namespace+"/"+result[j].ObjectMeta.Name
+	})
+
+	return result
+}
+
+func isReady(pod v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
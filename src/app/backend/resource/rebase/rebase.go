@@ -0,0 +1,238 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// RebaseSpec contains the object the user started to edit and the edited object whose update failed because of
+// a resourceVersion conflict.
+type RebaseSpec struct {
+	Original json.RawMessage `json:"original"`
+	Modified json.RawMessage `json:"modified"`
+}
+
+// Conflict is a field that was changed both by the user and on the server since the user started to edit.
+type Conflict struct {
+	// Path of the field, i.e. 'spec.template.spec.containers[name=nginx].image'.
+	Path string `json:"path"`
+
+	// Values of the field. Missing values mean that the field was removed or did not exist.
+	Original interface{} `json:"original,omitempty"`
+	Modified interface{} `json:"modified,omitempty"`
+	Current  interface{} `json:"current,omitempty"`
+}
+
+// RebaseResult is a proposal of the user's changes applied on top of the latest object.
+type RebaseResult struct {
+	// Merged object with resourceVersion of the latest object. Conflicting fields keep the current values, so
+	// it can be saved as it is only if there are no conflicts.
+	Merged map[string]interface{} `json:"merged"`
+
+	// Conflicts have to be resolved by the user before the merged object is saved.
+	Conflicts []Conflict `json:"conflicts"`
+}
+
+// Fields that are owned by the server. They always keep the current values and never conflict.
+var serverOwnedPaths = map[string]bool{
+	"metadata.resourceVersion":   true,
+	"metadata.generation":        true,
+	"metadata.managedFields":     true,
+	"metadata.uid":               true,
+	"metadata.creationTimestamp": true,
+	"metadata.selfLink":          true,
+	"status":                     true,
+}
+
+// absent marks a field that does not exist in one of the merged objects.
+type absentValue struct{}
+
+var absent = absentValue{}
+
+// Rebase computes a three-way merge of the user's changes between original and modified object into the current
+// object. Objects are merged field by field. Lists whose items all have a unique name, i.e. containers, ports or
+// environment variables, are merged item by item. Other lists are replaced as a whole.
+func Rebase(original, modified, current map[string]interface{}) *RebaseResult {
+	result := &RebaseResult{Conflicts: make([]Conflict, 0)}
+	merged := mergeValue("", original, modified, current, &result.Conflicts)
+	result.Merged, _ = merged.(map[string]interface{})
+	return result
+}
+
+// GetRebase rebases the user's changes given by spec onto the latest object in JSON.
+func GetRebase(spec *RebaseSpec, latest []byte) (*RebaseResult, error) {
+	var original, modified, current map[string]interface{}
+	if err := json.Unmarshal(spec.Original, &original); err != nil {
+		return nil, errors.NewBadRequest("original object is not valid: " + err.Error())
+	}
+
+	if err := json.Unmarshal(spec.Modified, &modified); err != nil {
+		return nil, errors.NewBadRequest("modified object is not valid: " + err.Error())
+	}
+
+	if err := json.Unmarshal(latest, &current); err != nil {
+		return nil, err
+	}
+
+	return Rebase(original, modified, current), nil
+}
+
+func mergeValue(path string, original, modified, current interface{}, conflicts *[]Conflict) interface{} {
+	switch {
+	case serverOwnedPaths[path]:
+		return current
+	case reflect.DeepEqual(modified, current):
+		return current
+	case reflect.DeepEqual(original, modified):
+		return current
+	case reflect.DeepEqual(original, current):
+		return modified
+	}
+
+	if m, ok := modified.(map[string]interface{}); ok {
+		if c, ok := current.(map[string]interface{}); ok {
+			o, _ := original.(map[string]interface{})
+			return mergeMap(path, o, m, c, conflicts)
+		}
+	}
+
+	if m, ok := toNamedItems(modified); ok {
+		if c, ok := toNamedItems(current); ok {
+			o, _ := toNamedItems(original)
+			return mergeNamedItems(path, o, m, c, modified.([]interface{}), current.([]interface{}), conflicts)
+		}
+	}
+
+	*conflicts = append(*conflicts, Conflict{
+		Path:     path,
+		Original: present(original),
+		Modified: present(modified),
+		Current:  present(current),
+	})
+	return current
+}
+
+func mergeMap(path string, original, modified, current map[string]interface{},
+	conflicts *[]Conflict) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, key := range sortedKeys(original, modified, current) {
+		merged := mergeValue(joinPath(path, key), lookup(original, key), lookup(modified, key),
+			lookup(current, key), conflicts)
+		if merged != absent {
+			result[key] = merged
+		}
+	}
+
+	return result
+}
+
+// mergeNamedItems merges lists item by item. Items keep the order of the modified list, items that were added on
+// the server since the user started to edit are appended.
+func mergeNamedItems(path string, original, modified, current map[string]interface{},
+	modifiedList, currentList []interface{}, conflicts *[]Conflict) []interface{} {
+	merged := make(map[string]interface{})
+	for _, name := range sortedKeys(original, modified, current) {
+		value := mergeValue(fmt.Sprintf("%s[name=%s]", path, name), lookup(original, name), lookup(modified, name),
+			lookup(current, name), conflicts)
+		if value != absent {
+			merged[name] = value
+		}
+	}
+
+	result := make([]interface{}, 0, len(merged))
+	for _, list := range [][]interface{}{modifiedList, currentList} {
+		for _, item := range list {
+			name := item.(map[string]interface{})["name"].(string)
+			if value, ok := merged[name]; ok {
+				result = append(result, value)
+				delete(merged, name)
+			}
+		}
+	}
+
+	return result
+}
+
+func sortedKeys(maps ...map[string]interface{}) []string {
+	keys := make(map[string]bool)
+	for _, fields := range maps {
+		for key := range fields {
+			keys[key] = true
+		}
+	}
+
+	result := make([]string, 0, len(keys))
+	for key := range keys {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// toNamedItems returns items of the list by their names, if all of them are objects with unique names.
+func toNamedItems(value interface{}) (map[string]interface{}, bool) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	result := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		name, ok := fields["name"].(string)
+		if _, duplicate := result[name]; !ok || duplicate {
+			return nil, false
+		}
+
+		result[name] = item
+	}
+
+	return result, true
+}
+
+func lookup(fields map[string]interface{}, key string) interface{} {
+	if value, ok := fields[key]; ok {
+		return value
+	}
+	return absent
+}
+
+func present(value interface{}) interface{} {
+	if value == absent {
+		return nil
+	}
+	return value
+}
+
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	return path + "." + key
+}
@@ -0,0 +1,139 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebase
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func toObject(t *testing.T, data string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestRebase(t *testing.T) {
+	original := `{
+		"metadata": {"name": "web", "resourceVersion": "1", "labels": {"app": "web"}},
+		"spec": {"replicas": 1, "containers": [
+			{"name": "nginx", "image": "nginx:1.17"},
+			{"name": "proxy", "image": "envoy"}
+		]}
+	}`
+
+	cases := []struct {
+		info              string
+		modified, current string
+		expected          string
+		expectedConflicts []Conflict
+	}{
+		{
+			"should apply changes made to different fields",
+			`{
+				"metadata": {"name": "web", "resourceVersion": "1", "labels": {"app": "web", "tier": "front"}},
+				"spec": {"replicas": 1, "containers": [
+					{"name": "nginx", "image": "nginx:1.17"},
+					{"name": "proxy", "image": "envoy"}
+				]}
+			}`,
+			`{
+				"metadata": {"name": "web", "resourceVersion": "2", "labels": {"app": "web"}},
+				"spec": {"replicas": 3, "containers": [
+					{"name": "nginx", "image": "nginx:1.17"},
+					{"name": "proxy", "image": "envoy"}
+				]},
+				"status": {"replicas": 3}
+			}`,
+			`{
+				"metadata": {"name": "web", "resourceVersion": "2", "labels": {"app": "web", "tier": "front"}},
+				"spec": {"replicas": 3, "containers": [
+					{"name": "nginx", "image": "nginx:1.17"},
+					{"name": "proxy", "image": "envoy"}
+				]},
+				"status": {"replicas": 3}
+			}`,
+			[]Conflict{},
+		},
+		{
+			"should merge named list items",
+			`{
+				"metadata": {"name": "web", "resourceVersion": "1", "labels": {"app": "web"}},
+				"spec": {"replicas": 1, "containers": [
+					{"name": "nginx", "image": "nginx:1.19"}
+				]}
+			}`,
+			`{
+				"metadata": {"name": "web", "resourceVersion": "2", "labels": {"app": "web"}},
+				"spec": {"replicas": 1, "containers": [
+					{"name": "nginx", "image": "nginx:1.17"},
+					{"name": "proxy", "image": "envoy"},
+					{"name": "sidecar", "image": "busybox"}
+				]}
+			}`,
+			`{
+				"metadata": {"name": "web", "resourceVersion": "2", "labels": {"app": "web"}},
+				"spec": {"replicas": 1, "containers": [
+					{"name": "nginx", "image": "nginx:1.19"},
+					{"name": "sidecar", "image": "busybox"}
+				]}
+			}`,
+			[]Conflict{},
+		},
+		{
+			"should report fields changed by both sides",
+			`{
+				"metadata": {"name": "web", "resourceVersion": "1", "labels": {"app": "web"}},
+				"spec": {"replicas": 2, "containers": [
+					{"name": "nginx", "image": "nginx:1.19"},
+					{"name": "proxy", "image": "envoy"}
+				]}
+			}`,
+			`{
+				"metadata": {"name": "web", "resourceVersion": "2", "labels": {"app": "web"}},
+				"spec": {"replicas": 3, "containers": [
+					{"name": "nginx", "image": "nginx:1.18"}
+				]}
+			}`,
+			`{
+				"metadata": {"name": "web", "resourceVersion": "2", "labels": {"app": "web"}},
+				"spec": {"replicas": 3, "containers": [
+					{"name": "nginx", "image": "nginx:1.18"}
+				]}
+			}`,
+			[]Conflict{
+				{Path: "spec.containers[name=nginx].image", Original: "nginx:1.17", Modified: "nginx:1.19",
+					Current: "nginx:1.18"},
+				{Path: "spec.replicas", Original: float64(1), Modified: float64(2), Current: float64(3)},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := Rebase(toObject(t, original), toObject(t, c.modified), toObject(t, c.current))
+		if !reflect.DeepEqual(actual.Merged, toObject(t, c.expected)) {
+			t.Errorf("Test Case: %s. Expected merged object %#v, but got %#v", c.info, toObject(t, c.expected),
+				actual.Merged)
+		}
+
+		if !reflect.DeepEqual(actual.Conflicts, c.expectedConflicts) {
+			t.Errorf("Test Case: %s. Expected conflicts %#v, but got %#v", c.info, c.expectedConflicts,
+				actual.Conflicts)
+		}
+	}
+}
@@ -0,0 +1,501 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+)
+
+const (
+	// SourceVPA means that recommendation comes from the Vertical Pod Autoscaler status.
+	SourceVPA = "vpa"
+	// SourceMetrics means that recommendation was computed from historical usage provided by metrics integration.
+	SourceMetrics = "metrics"
+
+	// requestHeadroom is added on top of observed usage when recommending requests.
+	requestHeadroom = 1.15
+	// limitHeadroom is added on top of peak usage when recommending limits.
+	limitHeadroom = 1.3
+	// usagePercentile is the percentile of observed CPU usage that recommended CPU request covers.
+	usagePercentile = 0.95
+
+	minCPUMilliValue   = 10
+	minMemoryValue     = 16 * 1024 * 1024
+	recommendedCPUUnit = 5
+)
+
+var vpaResource = schema.GroupVersionResource{Group: "autoscaling.k8s.io", Version: "v1",
+	Resource: "verticalpodautoscalers"}
+
+// Resources contains CPU and memory requests and limits of a single container.
+type Resources struct {
+	CPURequest    *resource.Quantity `json:"cpuRequest,omitempty"`
+	CPULimit      *resource.Quantity `json:"cpuLimit,omitempty"`
+	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
+	MemoryLimit   *resource.Quantity `json:"memoryLimit,omitempty"`
+}
+
+// Savings contains projected change of requested resources across all replicas. Positive values mean
+// that resources would be released, negative ones mean that workload is currently under-provisioned.
+type Savings struct {
+	// CPU in millicores.
+	CPU int64 `json:"cpu"`
+	// Memory in bytes.
+	Memory int64 `json:"memory"`
+}
+
+// ContainerRecommendation is a suggested resources configuration of a single container.
+type ContainerRecommendation struct {
+	Container   string    `json:"container"`
+	Current     Resources `json:"current"`
+	Recommended Resources `json:"recommended"`
+	Savings     Savings   `json:"savings"`
+}
+
+// Recommendation contains suggested resources configuration of all containers of a workload.
+type Recommendation struct {
+	TypeMeta   api.TypeMeta              `json:"typeMeta"`
+	ObjectMeta api.ObjectMeta            `json:"objectMeta"`
+	Source     string                    `json:"source"`
+	Replicas   int32                     `json:"replicas"`
+	Containers []ContainerRecommendation `json:"containers"`
+	Savings    Savings                   `json:"savings"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ApplySpec contains recommended resources that user has seen and agreed to apply.
+type ApplySpec struct {
+	Containers []ContainerRecommendation `json:"containers"`
+}
+
+// workload contains workload data required to compute recommendation.
+type workload struct {
+	meta     metav1.ObjectMeta
+	template v1.PodTemplateSpec
+	selector *metav1.LabelSelector
+	replicas int32
+}
+
+// GetRecommendation returns resources recommendation for containers of given workload. VPA recommendation
+// is preferred when VPA targeting the workload exists, otherwise historical metrics are used.
+func GetRecommendation(client kubernetes.Interface, dynamicClient dynamic.Interface,
+	metricClient metricapi.MetricClient, kind, namespace, name string) (*Recommendation, error) {
+	w, err := getWorkload(client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// VPA is optional, so missing VPA resource only means that metrics have to be used.
+	recommended, err := getVPARecommendation(dynamicClient, kind, namespace, name)
+	if errors.IsNotFoundError(err) {
+		err = nil
+	}
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	source := SourceVPA
+	if recommended == nil {
+		source = SourceMetrics
+		recommended, err = getMetricsRecommendation(client, metricClient, w)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Recommendation{
+		TypeMeta:   api.NewTypeMeta(api.ResourceKind(strings.ToLower(kind))),
+		ObjectMeta: api.NewObjectMeta(w.meta),
+		Source:     source,
+		Replicas:   w.replicas,
+		Containers: make([]ContainerRecommendation, 0),
+		Errors:     nonCriticalErrors,
+	}
+
+	for _, container := range w.template.Spec.Containers {
+		r, ok := recommended[container.Name]
+		if !ok {
+			continue
+		}
+
+		current := toResources(container.Resources)
+		savings := Savings{
+			CPU:    (milliValue(current.CPURequest) - milliValue(r.CPURequest)) * int64(w.replicas),
+			Memory: (value(current.MemoryRequest) - value(r.MemoryRequest)) * int64(w.replicas),
+		}
+		result.Savings.CPU += savings.CPU
+		result.Savings.Memory += savings.Memory
+		result.Containers = append(result.Containers, ContainerRecommendation{
+			Container:   container.Name,
+			Current:     current,
+			Recommended: r,
+			Savings:     savings,
+		})
+	}
+
+	return result, nil
+}
+
+// CheckExpected returns conflict error when recommended resources differ from the ones that user has seen
+// before applying them, i.e. because usage or VPA status has changed in the meantime.
+func CheckExpected(actual *Recommendation, expected []ContainerRecommendation) error {
+	recommended := make(map[string]Resources)
+	for _, c := range actual.Containers {
+		recommended[c.Container] = c.Recommended
+	}
+
+	if len(expected) != len(recommended) {
+		return errors.NewGenericResponse(http.StatusConflict, "recommended containers have changed")
+	}
+
+	for _, c := range expected {
+		r, ok := recommended[c.Container]
+		if !ok || !equalQuantity(r.CPURequest, c.Recommended.CPURequest) ||
+			!equalQuantity(r.CPULimit, c.Recommended.CPULimit) ||
+			!equalQuantity(r.MemoryRequest, c.Recommended.MemoryRequest) ||
+			!equalQuantity(r.MemoryLimit, c.Recommended.MemoryLimit) {
+			return errors.NewGenericResponse(http.StatusConflict,
+				fmt.Sprintf("recommendation for container %s has changed", c.Container))
+		}
+	}
+
+	return nil
+}
+
+// ApplyRecommendation patches workload pod template with recommended resources. Replica sets are refused, as
+// changes of their template do not affect pods that are already running.
+func ApplyRecommendation(verber clientapi.ResourceVerber, recommendation *Recommendation) error {
+	if recommendation.TypeMeta.Kind == api.ResourceKindReplicaSet {
+		return errors.NewBadRequest("recommendation can not be applied to replica set, apply it to its deployment")
+	}
+
+	containers := make([]map[string]interface{}, 0)
+	for _, c := range recommendation.Containers {
+		requests, limits := v1.ResourceList{}, v1.ResourceList{}
+		setQuantity(requests, v1.ResourceCPU, c.Recommended.CPURequest)
+		setQuantity(requests, v1.ResourceMemory, c.Recommended.MemoryRequest)
+		setQuantity(limits, v1.ResourceCPU, c.Recommended.CPULimit)
+		setQuantity(limits, v1.ResourceMemory, c.Recommended.MemoryLimit)
+		containers = append(containers, map[string]interface{}{
+			"name":      c.Container,
+			"resources": v1.ResourceRequirements{Requests: requests, Limits: limits},
+		})
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": containers},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return verber.Patch(string(recommendation.TypeMeta.Kind), true, recommendation.ObjectMeta.Namespace,
+		recommendation.ObjectMeta.Name, types.StrategicMergePatchType, patch)
+}
+
+func getWorkload(client kubernetes.Interface, kind, namespace, name string) (*workload, error) {
+	switch api.ResourceKind(strings.ToLower(kind)) {
+	case api.ResourceKindDeployment:
+		d, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{meta: d.ObjectMeta, template: d.Spec.Template, selector: d.Spec.Selector,
+			replicas: replicasOrDefault(d.Spec.Replicas)}, nil
+	case api.ResourceKindStatefulSet:
+		s, err := client.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{meta: s.ObjectMeta, template: s.Spec.Template, selector: s.Spec.Selector,
+			replicas: replicasOrDefault(s.Spec.Replicas)}, nil
+	case api.ResourceKindReplicaSet:
+		r, err := client.AppsV1().ReplicaSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{meta: r.ObjectMeta, template: r.Spec.Template, selector: r.Spec.Selector,
+			replicas: replicasOrDefault(r.Spec.Replicas)}, nil
+	case api.ResourceKindDaemonSet:
+		d, err := client.AppsV1().DaemonSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{meta: d.ObjectMeta, template: d.Spec.Template, selector: d.Spec.Selector,
+			replicas: d.Status.DesiredNumberScheduled}, nil
+	}
+
+	return nil, errors.NewInvalid(fmt.Sprintf("resource recommendation is not supported for kind: %s", kind))
+}
+
+// getVPARecommendation returns target recommendation of VPA that points to given workload. Nil is returned
+// if VPA is not installed or no VPA targets the workload.
+func getVPARecommendation(dynamicClient dynamic.Interface, kind, namespace, name string) (
+	map[string]Resources, error) {
+	list, err := dynamicClient.Resource(vpaResource).Namespace(namespace).List(context.TODO(), api.ListEverything)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range list.Items {
+		targetKind, _, _ := unstructured.NestedString(item.Object, "spec", "targetRef", "kind")
+		targetName, _, _ := unstructured.NestedString(item.Object, "spec", "targetRef", "name")
+		if !strings.EqualFold(targetKind, kind) || targetName != name {
+			continue
+		}
+
+		recommendations, _, _ := unstructured.NestedSlice(item.Object, "status", "recommendation",
+			"containerRecommendations")
+		result := make(map[string]Resources)
+		for _, r := range recommendations {
+			rec, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			container, _, _ := unstructured.NestedString(rec, "containerName")
+			target, _, _ := unstructured.NestedStringMap(rec, "target")
+			upperBound, _, _ := unstructured.NestedStringMap(rec, "upperBound")
+			result[container] = Resources{
+				CPURequest:    parseQuantity(target["cpu"]),
+				MemoryRequest: parseQuantity(target["memory"]),
+				CPULimit:      parseQuantity(upperBound["cpu"]),
+				MemoryLimit:   parseQuantity(upperBound["memory"]),
+			}
+		}
+
+		if len(result) > 0 {
+			return result, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getMetricsRecommendation computes recommendation based on historical usage of workload pods. Metrics are
+// collected per pod, so usage is split between containers proportionally to their current requests.
+func getMetricsRecommendation(client kubernetes.Interface, metricClient metricapi.MetricClient, w *workload) (
+	map[string]Resources, error) {
+	if metricClient == nil {
+		return nil, errors.NewNotFound("no metrics integration or VPA recommendation available")
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(w.selector)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := client.CoreV1().Pods(w.meta.Namespace).List(context.TODO(),
+		metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	selectors := make([]metricapi.ResourceSelector, len(pods.Items))
+	for i, pod := range pods.Items {
+		selectors[i] = metricapi.ResourceSelector{
+			Namespace:    pod.Namespace,
+			ResourceType: api.ResourceKindPod,
+			ResourceName: pod.Name,
+			UID:          pod.UID,
+		}
+	}
+
+	cached := &metricapi.CachedResources{Pods: pods.Items}
+	cpu, _ := metricClient.DownloadMetric(selectors, metricapi.CpuUsage, cached).GetMetrics()
+	memory, _ := metricClient.DownloadMetric(selectors, metricapi.MemoryUsage, cached).GetMetrics()
+
+	cpuUsage, memoryUsage := collectValues(cpu), collectValues(memory)
+	if len(cpuUsage) == 0 && len(memoryUsage) == 0 {
+		return nil, errors.NewNotFound("no historical metrics available for workload pods")
+	}
+
+	return splitRecommendation(w.template.Spec.Containers, cpuUsage, memoryUsage), nil
+}
+
+// splitRecommendation computes pod level recommendation from observed usage and splits it between containers.
+func splitRecommendation(containers []v1.Container, cpuUsage, memoryUsage []int64) map[string]Resources {
+	cpuRequest := int64(float64(percentile(cpuUsage, usagePercentile)) * requestHeadroom)
+	cpuLimit := int64(float64(percentile(cpuUsage, 1)) * limitHeadroom)
+	memoryRequest := int64(float64(percentile(memoryUsage, 1)) * requestHeadroom)
+	memoryLimit := int64(float64(percentile(memoryUsage, 1)) * limitHeadroom)
+
+	cpuShares, memoryShares := make([]int64, len(containers)), make([]int64, len(containers))
+	var cpuTotal, memoryTotal int64
+	for i, c := range containers {
+		cpuShares[i] = c.Resources.Requests.Cpu().MilliValue()
+		memoryShares[i] = c.Resources.Requests.Memory().Value()
+		cpuTotal += cpuShares[i]
+		memoryTotal += memoryShares[i]
+	}
+
+	result := make(map[string]Resources)
+	for i, c := range containers {
+		cpuShare := share(cpuShares[i], cpuTotal, len(containers))
+		memoryShare := share(memoryShares[i], memoryTotal, len(containers))
+		result[c.Name] = Resources{
+			CPURequest:    cpuQuantity(float64(cpuRequest) * cpuShare),
+			CPULimit:      cpuQuantity(float64(cpuLimit) * cpuShare),
+			MemoryRequest: memoryQuantity(float64(memoryRequest) * memoryShare),
+			MemoryLimit:   memoryQuantity(float64(memoryLimit) * memoryShare),
+		}
+	}
+
+	return result
+}
+
+func share(part, total int64, count int) float64 {
+	if total == 0 {
+		return 1 / float64(count)
+	}
+
+	return float64(part) / float64(total)
+}
+
+func collectValues(metrics []metricapi.Metric) []int64 {
+	result := make([]int64, 0)
+	for _, metric := range metrics {
+		for _, point := range metric.DataPoints {
+			result = append(result, point.Y)
+		}
+	}
+
+	return result
+}
+
+// percentile returns value below which given fraction of values falls. Values are sorted in place.
+func percentile(values []int64, p float64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	index := int(math.Ceil(p*float64(len(values)))) - 1
+	if index < 0 {
+		index = 0
+	}
+
+	return values[index]
+}
+
+func cpuQuantity(milliValue float64) *resource.Quantity {
+	rounded := int64(math.Ceil(milliValue/recommendedCPUUnit)) * recommendedCPUUnit
+	if rounded < minCPUMilliValue {
+		rounded = minCPUMilliValue
+	}
+
+	return resource.NewMilliQuantity(rounded, resource.DecimalSI)
+}
+
+func memoryQuantity(v float64) *resource.Quantity {
+	const mebibyte = 1024 * 1024
+	rounded := int64(math.Ceil(v/mebibyte)) * mebibyte
+	if rounded < minMemoryValue {
+		rounded = minMemoryValue
+	}
+
+	return resource.NewQuantity(rounded, resource.BinarySI)
+}
+
+func toResources(r v1.ResourceRequirements) Resources {
+	return Resources{
+		CPURequest:    getQuantity(r.Requests, v1.ResourceCPU),
+		CPULimit:      getQuantity(r.Limits, v1.ResourceCPU),
+		MemoryRequest: getQuantity(r.Requests, v1.ResourceMemory),
+		MemoryLimit:   getQuantity(r.Limits, v1.ResourceMemory),
+	}
+}
+
+func getQuantity(list v1.ResourceList, name v1.ResourceName) *resource.Quantity {
+	if q, ok := list[name]; ok {
+		return &q
+	}
+
+	return nil
+}
+
+func setQuantity(list v1.ResourceList, name v1.ResourceName, q *resource.Quantity) {
+	if q != nil {
+		list[name] = *q
+	}
+}
+
+func parseQuantity(s string) *resource.Quantity {
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return nil
+	}
+
+	return &q
+}
+
+func equalQuantity(a, b *resource.Quantity) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Cmp(*b) == 0
+}
+
+func milliValue(q *resource.Quantity) int64 {
+	if q == nil {
+		return 0
+	}
+
+	return q.MilliValue()
+}
+
+func value(q *resource.Quantity) int64 {
+	if q == nil {
+		return 0
+	}
+
+	return q.Value()
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+
+	return *replicas
+}
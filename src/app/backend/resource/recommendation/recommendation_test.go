@@ -0,0 +1,125 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommendation
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+func TestPercentile(t *testing.T) {
+	cases := []struct {
+		values   []int64
+		p        float64
+		expected int64
+	}{
+		{[]int64{}, 0.95, 0},
+		{[]int64{5}, 0.95, 5},
+		{[]int64{10, 1, 7, 3}, 1, 10},
+		{[]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100}, 0.95, 19},
+	}
+
+	for _, c := range cases {
+		actual := percentile(c.values, c.p)
+		if actual != c.expected {
+			t.Errorf("Test Case: %v. Expected percentile %d, but got %d", c.values, c.expected, actual)
+		}
+	}
+}
+
+func TestSplitRecommendation(t *testing.T) {
+	containers := []v1.Container{
+		{
+			Name: "app",
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("300m"),
+				v1.ResourceMemory: resource.MustParse("192Mi"),
+			}},
+		},
+		{
+			Name: "proxy",
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("100m"),
+				v1.ResourceMemory: resource.MustParse("64Mi"),
+			}},
+		},
+	}
+
+	mebibyte := int64(1024 * 1024)
+	result := splitRecommendation(containers, []int64{200, 100, 200}, []int64{100 * mebibyte, 200 * mebibyte})
+
+	cases := []struct {
+		container     string
+		cpuRequest    string
+		memoryRequest string
+		memoryLimit   string
+	}{
+		{"app", "175m", "173Mi", "195Mi"},
+		{"proxy", "60m", "58Mi", "65Mi"},
+	}
+
+	for _, c := range cases {
+		r := result[c.container]
+		if r.CPURequest.String() != c.cpuRequest || r.MemoryRequest.String() != c.memoryRequest ||
+			r.MemoryLimit.String() != c.memoryLimit {
+			t.Errorf("Test Case: %s. Expected %s/%s/%s, but got %s/%s/%s", c.container, c.cpuRequest,
+				c.memoryRequest, c.memoryLimit, r.CPURequest, r.MemoryRequest, r.MemoryLimit)
+		}
+	}
+}
+
+func TestCheckExpected(t *testing.T) {
+	quantity := func(value string) *resource.Quantity {
+		q := resource.MustParse(value)
+		return &q
+	}
+	actual := &Recommendation{Containers: []ContainerRecommendation{
+		{Container: "app", Recommended: Resources{CPURequest: quantity("100m"), MemoryRequest: quantity("64Mi")}},
+	}}
+
+	cases := []struct {
+		info     string
+		expected []ContainerRecommendation
+		conflict bool
+	}{
+		{"same values", []ContainerRecommendation{{Container: "app",
+			Recommended: Resources{CPURequest: quantity("0.1"), MemoryRequest: quantity("64Mi")}}}, false},
+		{"changed value", []ContainerRecommendation{{Container: "app",
+			Recommended: Resources{CPURequest: quantity("150m"), MemoryRequest: quantity("64Mi")}}}, true},
+		{"missing limit", []ContainerRecommendation{{Container: "app",
+			Recommended: Resources{CPURequest: quantity("100m"), MemoryRequest: quantity("64Mi"),
+				MemoryLimit: quantity("128Mi")}}}, true},
+		{"nothing expected", nil, true},
+	}
+
+	for _, c := range cases {
+		err := CheckExpected(actual, c.expected)
+		if c.conflict != k8serrors.IsConflict(err) || (!c.conflict && err != nil) {
+			t.Errorf("Test Case: %s. Expected conflict: %t, but got %v", c.info, c.conflict, err)
+		}
+	}
+}
+
+func TestApplyRecommendationToReplicaSet(t *testing.T) {
+	recommendation := &Recommendation{TypeMeta: api.NewTypeMeta(api.ResourceKindReplicaSet)}
+	if err := ApplyRecommendation(nil, recommendation); err == nil {
+		t.Error("Expected recommendation not to be applied to replica set")
+	}
+}
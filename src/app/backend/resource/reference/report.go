@@ -0,0 +1,358 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"log"
+	"sort"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// Places in the pod spec, where config maps and secrets can be referenced.
+const (
+	SourceEnvFrom   = "envFrom"
+	SourceValueFrom = "valueFrom"
+	SourceVolume    = "volume"
+)
+
+// Reasons of broken references.
+const (
+	ReasonMissingObject = "MissingObject"
+	ReasonMissingKey    = "MissingKey"
+)
+
+// BrokenReference is a reference to a config map or secret, or their key, that does not exist.
+type BrokenReference struct {
+	// Workload that contains the reference.
+	Workload api.ResourceKind `json:"workloadKind"`
+	// WorkloadName is the name of the workload that contains the reference.
+	WorkloadName string `json:"workloadName"`
+	// Container is empty for volume references.
+	Container string `json:"container,omitempty"`
+	// Kind of the referenced object, either config map or secret.
+	Kind   api.ResourceKind `json:"kind"`
+	Name   string           `json:"name"`
+	Key    string           `json:"key,omitempty"`
+	Source string           `json:"source"`
+	Reason string           `json:"reason"`
+}
+
+// NamespaceReferences contains broken references found in a single namespace.
+type NamespaceReferences struct {
+	Namespace  string            `json:"namespace"`
+	References []BrokenReference `json:"references"`
+}
+
+// ReferenceReport contains broken config map and secret references grouped by namespace.
+type ReferenceReport struct {
+	ListMeta   api.ListMeta          `json:"listMeta"`
+	Namespaces []NamespaceReferences `json:"namespaces"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// Reference is a single config map or secret reference found in a pod spec.
+type Reference struct {
+	Container string
+	Kind      api.ResourceKind
+	Name      string
+	Key       string
+	Source    string
+	Optional  bool
+}
+
+// workload is a pod template owner, that can reference config maps and secrets.
+type workload struct {
+	kind      api.ResourceKind
+	namespace string
+	name      string
+	spec      v1.PodSpec
+}
+
+// GetReferenceReport scans workload pod templates for references to config maps, secrets and their keys
+// that do not exist. References marked as optional are skipped. If secrets can not be listed, secret
+// references are not validated to avoid false positives.
+func GetReferenceReport(client client.Interface, nsQuery *common.NamespaceQuery) (*ReferenceReport, error) {
+	log.Print("Getting config map and secret reference report")
+	channels := &common.ResourceChannels{
+		DeploymentList:  common.GetDeploymentListChannel(client, nsQuery, 1),
+		StatefulSetList: common.GetStatefulSetListChannel(client, nsQuery, 1),
+		DaemonSetList:   common.GetDaemonSetListChannel(client, nsQuery, 1),
+		JobList:         common.GetJobListChannel(client, nsQuery, 1),
+		CronJobList:     common.GetCronJobListChannel(client, nsQuery, 1),
+		PodList:         common.GetPodListChannel(client, nsQuery, 1),
+		ConfigMapList:   common.GetConfigMapListChannel(client, nsQuery, 1),
+		SecretList:      common.GetSecretListChannel(client, nsQuery, 1),
+	}
+
+	workloads := make([]workload, 0)
+
+	deployments := <-channels.DeploymentList.List
+	nonCriticalErrors, criticalError := errors.HandleError(<-channels.DeploymentList.Error)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+	if deployments != nil {
+		for _, d := range deployments.Items {
+			workloads = append(workloads, workload{api.ResourceKindDeployment, d.Namespace, d.Name,
+				d.Spec.Template.Spec})
+		}
+	}
+
+	statefulSets := <-channels.StatefulSetList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.StatefulSetList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+	if statefulSets != nil {
+		for _, s := range statefulSets.Items {
+			workloads = append(workloads, workload{api.ResourceKindStatefulSet, s.Namespace, s.Name,
+				s.Spec.Template.Spec})
+		}
+	}
+
+	daemonSets := <-channels.DaemonSetList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.DaemonSetList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+	if daemonSets != nil {
+		for _, d := range daemonSets.Items {
+			workloads = append(workloads, workload{api.ResourceKindDaemonSet, d.Namespace, d.Name,
+				d.Spec.Template.Spec})
+		}
+	}
+
+	jobs := <-channels.JobList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.JobList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+	if jobs != nil {
+		for _, j := range jobs.Items {
+			if !isOwnedByCronJob(j) {
+				workloads = append(workloads, workload{api.ResourceKindJob, j.Namespace, j.Name,
+					j.Spec.Template.Spec})
+			}
+		}
+	}
+
+	cronJobs := <-channels.CronJobList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.CronJobList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+	if cronJobs != nil {
+		for _, c := range cronJobs.Items {
+			workloads = append(workloads, workload{api.ResourceKindCronJob, c.Namespace, c.Name,
+				c.Spec.JobTemplate.Spec.Template.Spec})
+		}
+	}
+
+	pods := <-channels.PodList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.PodList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+	if pods != nil {
+		for _, p := range pods.Items {
+			// Pods created by controllers are covered by their templates.
+			if metaV1.GetControllerOf(&p) == nil {
+				workloads = append(workloads, workload{api.ResourceKindPod, p.Namespace, p.Name, p.Spec})
+			}
+		}
+	}
+
+	configMaps := <-channels.ConfigMapList.List
+	configMapErr := <-channels.ConfigMapList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(configMapErr, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	secrets := <-channels.SecretList.List
+	secretErr := <-channels.SecretList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(secretErr, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	existing := make(map[api.ResourceKind]map[string]map[string]bool)
+	if configMapErr == nil && configMaps != nil {
+		existing[api.ResourceKindConfigMap] = make(map[string]map[string]bool)
+		for _, cm := range configMaps.Items {
+			keys := make(map[string]bool)
+			for key := range cm.Data {
+				keys[key] = true
+			}
+			for key := range cm.BinaryData {
+				keys[key] = true
+			}
+			existing[api.ResourceKindConfigMap][cm.Namespace+"/"+cm.Name] = keys
+		}
+	}
+	if secretErr == nil && secrets != nil {
+		existing[api.ResourceKindSecret] = make(map[string]map[string]bool)
+		for _, secret := range secrets.Items {
+			keys := make(map[string]bool)
+			for key := range secret.Data {
+				keys[key] = true
+			}
+			existing[api.ResourceKindSecret][secret.Namespace+"/"+secret.Name] = keys
+		}
+	}
+
+	return toReferenceReport(workloads, existing, nonCriticalErrors), nil
+}
+
+func toReferenceReport(workloads []workload, existing map[api.ResourceKind]map[string]map[string]bool,
+	nonCriticalErrors []error) *ReferenceReport {
+	byNamespace := make(map[string][]BrokenReference)
+	total := 0
+	for _, w := range workloads {
+		for _, ref := range GetReferences(w.spec) {
+			objects, known := existing[ref.Kind]
+			if ref.Optional || !known {
+				continue
+			}
+
+			reason := ""
+			if keys, ok := objects[w.namespace+"/"+ref.Name]; !ok {
+				reason = ReasonMissingObject
+			} else if len(ref.Key) > 0 && !keys[ref.Key] {
+				reason = ReasonMissingKey
+			}
+
+			if len(reason) == 0 {
+				continue
+			}
+
+			total++
+			byNamespace[w.namespace] = append(byNamespace[w.namespace], BrokenReference{
+				Workload:     w.kind,
+				WorkloadName: w.name,
+				Container:    ref.Container,
+				Kind:         ref.Kind,
+				Name:         ref.Name,
+				Key:          ref.Key,
+				Source:       ref.Source,
+				Reason:       reason,
+			})
+		}
+	}
+
+	result := &ReferenceReport{
+		ListMeta:   api.ListMeta{TotalItems: total},
+		Namespaces: make([]NamespaceReferences, 0),
+		Errors:     nonCriticalErrors,
+	}
+	for namespace, references := range byNamespace {
+		result.Namespaces = append(result.Namespaces, NamespaceReferences{Namespace: namespace, References: references})
+	}
+	sort.Slice(result.Namespaces, func(i, j int) bool {
+		return result.Namespaces[i].Namespace < result.Namespaces[j].Namespace
+	})
+
+	return result
+}
+
+// GetReferences returns all config map and secret references from the pod spec.
+func GetReferences(spec v1.PodSpec) []Reference {
+	result := make([]Reference, 0)
+	containers := append(append([]v1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		for _, envFrom := range c.EnvFrom {
+			if ref := envFrom.ConfigMapRef; ref != nil {
+				result = append(result, Reference{c.Name, api.ResourceKindConfigMap, ref.Name, "", SourceEnvFrom,
+					isOptional(ref.Optional)})
+			}
+			if ref := envFrom.SecretRef; ref != nil {
+				result = append(result, Reference{c.Name, api.ResourceKindSecret, ref.Name, "", SourceEnvFrom,
+					isOptional(ref.Optional)})
+			}
+		}
+
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				result = append(result, Reference{c.Name, api.ResourceKindConfigMap, ref.Name, ref.Key, SourceValueFrom,
+					isOptional(ref.Optional)})
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				result = append(result, Reference{c.Name, api.ResourceKindSecret, ref.Name, ref.Key, SourceValueFrom,
+					isOptional(ref.Optional)})
+			}
+		}
+	}
+
+	for _, volume := range spec.Volumes {
+		if cm := volume.ConfigMap; cm != nil {
+			result = append(result, volumeReferences(api.ResourceKindConfigMap, cm.Name, cm.Items,
+				isOptional(cm.Optional))...)
+		}
+		if secret := volume.Secret; secret != nil {
+			result = append(result, volumeReferences(api.ResourceKindSecret, secret.SecretName, secret.Items,
+				isOptional(secret.Optional))...)
+		}
+		if projected := volume.Projected; projected != nil {
+			for _, source := range projected.Sources {
+				if cm := source.ConfigMap; cm != nil {
+					result = append(result, volumeReferences(api.ResourceKindConfigMap, cm.Name, cm.Items,
+						isOptional(cm.Optional))...)
+				}
+				if secret := source.Secret; secret != nil {
+					result = append(result, volumeReferences(api.ResourceKindSecret, secret.Name, secret.Items,
+						isOptional(secret.Optional))...)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func volumeReferences(kind api.ResourceKind, name string, items []v1.KeyToPath, optional bool) []Reference {
+	if len(items) == 0 {
+		return []Reference{{Kind: kind, Name: name, Source: SourceVolume, Optional: optional}}
+	}
+
+	result := make([]Reference, 0)
+	for _, item := range items {
+		result = append(result, Reference{Kind: kind, Name: name, Key: item.Key, Source: SourceVolume,
+			Optional: optional})
+	}
+
+	return result
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+func isOwnedByCronJob(job batch.Job) bool {
+	owner := metaV1.GetControllerOf(&job)
+	return owner != nil && owner.Kind == "CronJob"
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+func TestGetReferenceReport(t *testing.T) {
+	optional := true
+	deployment := &apps.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: apps.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name: "app",
+				EnvFrom: []v1.EnvFromSource{
+					{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}},
+					{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "missing"}}},
+					{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "extra"},
+						Optional: &optional}},
+				},
+				Env: []v1.EnvVar{{Name: "MODE", ValueFrom: &v1.EnvVarSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "settings"}, Key: "mode"}}}},
+			}},
+			Volumes: []v1.Volume{{Name: "tls", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{
+				SecretName: "tls", Items: []v1.KeyToPath{{Key: "tls.crt", Path: "tls.crt"}}}}}},
+		}}},
+	}
+
+	client := fake.NewSimpleClientset(
+		deployment,
+		&v1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: "settings", Namespace: "default"},
+			Data: map[string]string{"level": "debug"}},
+		&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "tls", Namespace: "default"},
+			Data: map[string][]byte{"tls.crt": []byte("cert")}},
+	)
+
+	report, err := GetReferenceReport(client, common.NewNamespaceQuery(nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(report.Namespaces) != 1 || report.Namespaces[0].Namespace != "default" {
+		t.Fatalf("Expected broken references in default namespace, but got %v", report.Namespaces)
+	}
+
+	expected := []BrokenReference{
+		{Workload: api.ResourceKindDeployment, WorkloadName: "app", Container: "app", Kind: api.ResourceKindSecret,
+			Name: "missing", Source: SourceEnvFrom, Reason: ReasonMissingObject},
+		{Workload: api.ResourceKindDeployment, WorkloadName: "app", Container: "app", Kind: api.ResourceKindConfigMap,
+			Name: "settings", Key: "mode", Source: SourceValueFrom, Reason: ReasonMissingKey},
+	}
+
+	actual := report.Namespaces[0].References
+	if len(actual) != len(expected) || report.ListMeta.TotalItems != len(expected) {
+		t.Fatalf("Expected %d broken references, but got %v", len(expected), actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("Expected broken reference %v, but got %v", expected[i], actual[i])
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restartstorm
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// ReasonStarted is the reason of events reported by kubelet every time a container is started.
+const ReasonStarted = "Started"
+
+// StartedEventOptions lists only container start events.
+var StartedEventOptions = metaV1.ListOptions{
+	FieldSelector: fields.OneTermEqualSelector("reason", ReasonStarted).String(),
+}
+
+// RestartThreshold is the number of restarts within the window, after which workload is considered to be in
+// a restart storm.
+type RestartThreshold struct {
+	Window   time.Duration
+	Restarts int32
+}
+
+// DefaultThresholds catch both fast crash loops and slower, but steady restarts.
+var DefaultThresholds = []RestartThreshold{
+	{Window: 10 * time.Minute, Restarts: 3},
+	{Window: time.Hour, Restarts: 6},
+	{Window: 6 * time.Hour, Restarts: 12},
+}
+
+// WindowRestarts is the number of workload restarts within a single window.
+type WindowRestarts struct {
+	Window    string `json:"window"`
+	Restarts  int32  `json:"restarts"`
+	Threshold int32  `json:"threshold"`
+	Exceeded  bool   `json:"exceeded"`
+}
+
+// RestartStorm is a workload, which containers restart more often than allowed by any of the thresholds.
+type RestartStorm struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Pods is the number of existing workload pods.
+	Pods int `json:"pods"`
+	// TotalRestarts is the sum of restart counts of existing workload pods.
+	TotalRestarts int32 `json:"totalRestarts"`
+	// RestartRate is the highest number of restarts per hour observed in any of the windows.
+	RestartRate float64          `json:"restartRate"`
+	Windows     []WindowRestarts `json:"windows"`
+}
+
+// RestartStormList contains workloads in a restart storm ordered by the restart rate.
+type RestartStormList struct {
+	ListMeta  api.ListMeta   `json:"listMeta"`
+	Workloads []RestartStorm `json:"workloads"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ParseThresholds parses comma separated list of thresholds in the window:restarts form, i.e. "10m:3,1h:6".
+func ParseThresholds(value string) ([]RestartThreshold, error) {
+	result := make([]RestartThreshold, 0)
+	for _, part := range strings.Split(value, ",") {
+		pair := strings.Split(strings.TrimSpace(part), ":")
+		if len(pair) != 2 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid restart threshold: %s", part))
+		}
+
+		window, err := time.ParseDuration(pair[0])
+		if err != nil || window <= 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid restart threshold window: %s", pair[0]))
+		}
+
+		restarts, err := strconv.ParseInt(pair[1], 10, 32)
+		if err != nil || restarts <= 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid restart threshold count: %s", pair[1]))
+		}
+
+		result = append(result, RestartThreshold{Window: window, Restarts: int32(restarts)})
+	}
+	return result, nil
+}
+
+// GetRestartStormList returns workloads, which restarted more times than allowed by any of the thresholds.
+// Restarts are counted from container start events, as pod status only keeps the time of the last restart.
+func GetRestartStormList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	thresholds []RestartThreshold) (*RestartStormList, error) {
+	log.Print("Getting restart storm list")
+	channels := &common.ResourceChannels{
+		PodList:        common.GetPodListChannel(client, nsQuery, 1),
+		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
+		EventList:      common.GetEventListChannelWithOptions(client, nsQuery, StartedEventOptions, 1),
+	}
+
+	pods := <-channels.PodList.List
+	nonCriticalErrors, criticalError := errors.HandleError(<-channels.PodList.Error)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	replicaSets := <-channels.ReplicaSetList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.ReplicaSetList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	events := <-channels.EventList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.EventList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	workloads := GetRestartStorms(pods.Items, replicaSets.Items, events.Items, thresholds, time.Now())
+	return &RestartStormList{
+		ListMeta:  api.ListMeta{TotalItems: len(workloads)},
+		Workloads: workloads,
+		Errors:    nonCriticalErrors,
+	}, nil
+}
+
+// GetRestartStorms groups pods by their top level controller and returns workloads, which restarted more
+// times than allowed by any of the thresholds, ordered by the restart rate. Events are expected to be
+// container start events.
+func GetRestartStorms(pods []v1.Pod, replicaSets []apps.ReplicaSet, events []v1.Event,
+	thresholds []RestartThreshold, now time.Time) []RestartStorm {
+	eventsByPod := make(map[types.UID][]v1.Event)
+	for _, event := range events {
+		if event.Reason == ReasonStarted && strings.HasPrefix(event.InvolvedObject.FieldPath, "spec.containers") {
+			eventsByPod[event.InvolvedObject.UID] = append(eventsByPod[event.InvolvedObject.UID], event)
+		}
+	}
+
+	workloads := make(map[common.ResourceOwner]*RestartStorm)
+	owners := make([]common.ResourceOwner, 0)
+	for _, pod := range pods {
+		owner := common.GetPodOwner(pod, replicaSets)
+		storm, ok := workloads[owner]
+		if !ok {
+			storm = &RestartStorm{
+				ObjectMeta: api.ObjectMeta{Name: owner.Name, Namespace: owner.Namespace},
+				TypeMeta:   api.NewTypeMeta(owner.Kind),
+				Windows:    make([]WindowRestarts, len(thresholds)),
+			}
+			for i, threshold := range thresholds {
+				storm.Windows[i] = WindowRestarts{Window: threshold.Window.String(), Threshold: threshold.Restarts}
+			}
+			workloads[owner] = storm
+			owners = append(owners, owner)
+		}
+
+		storm.Pods++
+		for _, status := range pod.Status.ContainerStatuses {
+			storm.TotalRestarts += status.RestartCount
+		}
+		for i, threshold := range thresholds {
+			storm.Windows[i].Restarts += getRestartsSince(pod, eventsByPod[pod.UID], now.Add(-threshold.Window))
+		}
+	}
+
+	result := make([]RestartStorm, 0)
+	for _, owner := range owners {
+		storm := workloads[owner]
+		exceeded := false
+		for i, threshold := range thresholds {
+			if storm.Windows[i].Restarts >= threshold.Restarts {
+				storm.Windows[i].Exceeded = true
+				exceeded = true
+			}
+			rate := float64(storm.Windows[i].Restarts) * float64(time.Hour) / float64(threshold.Window)
+			storm.RestartRate = math.Max(storm.RestartRate, rate)
+		}
+		if exceeded {
+			result = append(result, *storm)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].RestartRate > result[j].RestartRate })
+	return result
+}
+
+// getRestartsSince estimates number of container restarts of the pod after given time. Every container start
+// is a restart, except for the first start of containers of pods created after given time.
+func getRestartsSince(pod v1.Pod, events []v1.Event, since time.Time) int32 {
+	var starts int32
+	for _, event := range events {
+		starts += getOccurrencesSince(event, since)
+	}
+
+	if !pod.CreationTimestamp.Time.Before(since) {
+		starts -= int32(len(pod.Spec.Containers))
+	}
+
+	if starts <= 0 {
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.LastTerminationState.Terminated
+			if terminated != nil && terminated.FinishedAt.Time.After(since) {
+				return 1
+			}
+		}
+		return 0
+	}
+	return starts
+}
+
+// getOccurrencesSince returns number of occurrences of the event after given time. Kubelet aggregates
+// repeated events, so when aggregated event started before given time, its occurrences are assumed to be
+// evenly distributed.
+func getOccurrencesSince(event v1.Event, since time.Time) int32 {
+	last := event.LastTimestamp.Time
+	if last.IsZero() {
+		last = event.EventTime.Time
+	}
+	if last.Before(since) {
+		return 0
+	}
+
+	count := event.Count
+	if count <= 1 {
+		return 1
+	}
+
+	first := event.FirstTimestamp.Time
+	if !first.Before(since) || !last.After(first) {
+		return count
+	}
+
+	fraction := float64(last.Sub(since)) / float64(last.Sub(first))
+	return int32(math.Round(float64(count) * fraction))
+}
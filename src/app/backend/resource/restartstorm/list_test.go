@@ -0,0 +1,129 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restartstorm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+func TestParseThresholds(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected []RestartThreshold
+		err      bool
+	}{
+		{"10m:3, 1h:6", []RestartThreshold{{10 * time.Minute, 3}, {time.Hour, 6}}, false},
+		{"10m", nil, true},
+		{"ten:3", nil, true},
+		{"10m:0", nil, true},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseThresholds(c.value)
+		if (err != nil) != c.err {
+			t.Errorf("Test Case: %s. Expected error: %t, but got %v", c.value, c.err, err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Expected %v, but got %v", c.value, c.expected, actual)
+		}
+	}
+}
+
+func TestGetOccurrencesSince(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		info        string
+		first, last time.Time
+		count       int32
+		expected    int32
+	}{
+		{"single event", now.Add(-time.Minute), now.Add(-time.Minute), 1, 1},
+		{"old event", now.Add(-2 * time.Hour), now.Add(-2 * time.Hour), 4, 0},
+		{"aggregated within window", now.Add(-30 * time.Minute), now, 4, 4},
+		{"aggregated across window", now.Add(-2 * time.Hour), now, 8, 4},
+	}
+
+	for _, c := range cases {
+		event := v1.Event{FirstTimestamp: metaV1.NewTime(c.first), LastTimestamp: metaV1.NewTime(c.last),
+			Count: c.count}
+		if actual := getOccurrencesSince(event, now.Add(-time.Hour)); actual != c.expected {
+			t.Errorf("Test Case: %s. Expected %d occurrences, but got %d", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestGetRestartStorms(t *testing.T) {
+	now := time.Now()
+	controller := true
+	replicaSets := []apps.ReplicaSet{{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "default", UID: "web-1",
+			OwnerReferences: []metaV1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &controller}}},
+	}}
+	newPod := func(name string, uid types.UID, owner string, restarts int32) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default", UID: uid,
+				CreationTimestamp: metaV1.NewTime(now.Add(-24 * time.Hour)),
+				OwnerReferences: []metaV1.OwnerReference{{Kind: "ReplicaSet", Name: owner, UID: types.UID(owner),
+					Controller: &controller}}},
+			Spec:   v1.PodSpec{Containers: []v1.Container{{Name: "app"}}},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "app", RestartCount: restarts}}},
+		}
+	}
+	newEvent := func(pod v1.Pod, fieldPath string, count int32, first time.Time) v1.Event {
+		return v1.Event{
+			Reason: ReasonStarted,
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: pod.Name, UID: pod.UID,
+				FieldPath: fieldPath},
+			Count:          count,
+			FirstTimestamp: metaV1.NewTime(first),
+			LastTimestamp:  metaV1.NewTime(now),
+		}
+	}
+
+	web := newPod("web-1-a", "web-1-a", "web-1", 20)
+	other := newPod("other-a", "other-a", "other", 1)
+	events := []v1.Event{
+		newEvent(web, "spec.containers{app}", 4, now.Add(-5*time.Minute)),
+		newEvent(web, "spec.initContainers{init}", 4, now.Add(-5*time.Minute)),
+		newEvent(other, "spec.containers{app}", 1, now),
+	}
+	thresholds := []RestartThreshold{{10 * time.Minute, 3}, {time.Hour, 6}}
+
+	actual := GetRestartStorms([]v1.Pod{web, other}, replicaSets, events, thresholds, now)
+	expected := []RestartStorm{{
+		ObjectMeta:    api.ObjectMeta{Name: "web", Namespace: "default"},
+		TypeMeta:      api.NewTypeMeta(api.ResourceKindDeployment),
+		Pods:          1,
+		TotalRestarts: 20,
+		RestartRate:   24,
+		Windows: []WindowRestarts{
+			{Window: "10m0s", Restarts: 4, Threshold: 3, Exceeded: true},
+			{Window: "1h0m0s", Restarts: 4, Threshold: 6},
+		},
+	}}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected restart storms %#v, but got %#v", expected, actual)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/openshift"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// RouteDetail is a presentation layer view of an OpenShift route.
+type RouteDetail struct {
+	// Extends list item structure.
+	Route `json:",inline"`
+
+	// Spec is the desired state of the Route.
+	Spec openshift.RouteSpec `json:"spec"`
+
+	// Status is the current state of the Route.
+	Status openshift.RouteStatus `json:"status"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetRouteDetail returns detailed information about a route.
+func GetRouteDetail(client client.Interface, namespace, name string) (*RouteDetail, error) {
+	if !openshift.GetCapabilities().Routes {
+		return nil, errors.NewNotFound(MsgRoutesNotServed)
+	}
+
+	log.Printf("Getting details of %s route in %s namespace", name, namespace)
+	route, err := openshift.GetRoute(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RouteDetail{
+		Route:  toRoute(route),
+		Spec:   route.Spec,
+		Status: route.Status,
+		Errors: []error{},
+	}, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/openshift"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// MsgRoutesNotServed is returned when routes are requested from a cluster, that is not OpenShift.
+const MsgRoutesNotServed = "routes are not served by the cluster"
+
+// Route - a single OpenShift route returned to the frontend.
+type Route struct {
+	api.ObjectMeta `json:"objectMeta"`
+	api.TypeMeta   `json:"typeMeta"`
+
+	// URL the route is exposed at. It is empty until a host is assigned to the route.
+	URL string `json:"url"`
+
+	// Name of the service receiving traffic of the route.
+	Service string `json:"service"`
+
+	// TLS termination of the route. It is empty for routes without TLS.
+	TLSTermination string `json:"tlsTermination,omitempty"`
+
+	// Admitted tells whether any router has admitted the route.
+	Admitted bool `json:"admitted"`
+}
+
+// RouteList - response structure for a queried route list.
+type RouteList struct {
+	api.ListMeta `json:"listMeta"`
+
+	// Unordered list of Routes.
+	Items []Route `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetRouteList returns all routes in the given namespace.
+func GetRouteList(client client.Interface, namespace *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*RouteList, error) {
+	if !openshift.GetCapabilities().Routes {
+		return nil, errors.NewNotFound(MsgRoutesNotServed)
+	}
+
+	log.Printf("Getting list of routes in the namespace %s", namespace.ToRequestParam())
+	routeList, err := openshift.ListRoutes(client, namespace.ToRequestParam(), api.ListEverything)
+
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	var routes []openshift.Route
+	for _, item := range routeList.Items {
+		if namespace.Matches(item.Namespace) {
+			routes = append(routes, item)
+		}
+	}
+
+	return toRouteList(routes, nonCriticalErrors, dsQuery), nil
+}
+
+func toRouteList(routes []openshift.Route, nonCriticalErrors []error,
+	dsQuery *dataselect.DataSelectQuery) *RouteList {
+	result := &RouteList{
+		Items:    make([]Route, 0),
+		ListMeta: api.ListMeta{TotalItems: len(routes)},
+		Errors:   nonCriticalErrors,
+	}
+
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(routes), dsQuery)
+	routes = fromCells(cells)
+	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+
+	for _, item := range routes {
+		result.Items = append(result.Items, toRoute(&item))
+	}
+	return result
+}
+
+func toRoute(route *openshift.Route) Route {
+	result := Route{
+		ObjectMeta: api.NewObjectMeta(route.ObjectMeta),
+		TypeMeta:   api.NewTypeMeta(api.ResourceKindRoute),
+		URL:        getURL(route),
+		Service:    route.Spec.To.Name,
+		Admitted:   isAdmitted(route),
+	}
+	if route.Spec.TLS != nil {
+		result.TLSTermination = route.Spec.TLS.Termination
+	}
+	return result
+}
+
+// Returns URL of the route. Host assigned by a router is used, when the route does not set it.
+func getURL(route *openshift.Route) string {
+	host := route.Spec.Host
+	for _, ingress := range route.Status.Ingress {
+		if len(host) > 0 {
+			break
+		}
+		host = ingress.Host
+	}
+	if len(host) == 0 {
+		return ""
+	}
+
+	scheme := "http"
+	if route.Spec.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + host + route.Spec.Path
+}
+
+func isAdmitted(route *openshift.Route) bool {
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type == "Admitted" && condition.Status == "True" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"reflect"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/openshift"
+)
+
+func TestToRoute(t *testing.T) {
+	admitted := openshift.RouteStatus{Ingress: []openshift.RouteIngress{{
+		Host:       "web-default.apps.example.com",
+		Conditions: []openshift.RouteIngressCondition{{Type: "Admitted", Status: "True"}},
+	}}}
+
+	cases := []struct {
+		route    openshift.Route
+		expected Route
+	}{
+		{
+			openshift.Route{
+				ObjectMeta: metaV1.ObjectMeta{Name: "web"},
+				Spec:       openshift.RouteSpec{To: openshift.RouteTargetReference{Name: "web-svc"}},
+			},
+			Route{ObjectMeta: api.ObjectMeta{Name: "web"}, TypeMeta: api.TypeMeta{Kind: api.ResourceKindRoute},
+				Service: "web-svc"},
+		},
+		{
+			openshift.Route{
+				ObjectMeta: metaV1.ObjectMeta{Name: "web"},
+				Spec: openshift.RouteSpec{Host: "web.example.com", Path: "/api",
+					TLS: &openshift.TLSConfig{Termination: "edge"}},
+				Status: admitted,
+			},
+			Route{ObjectMeta: api.ObjectMeta{Name: "web"}, TypeMeta: api.TypeMeta{Kind: api.ResourceKindRoute},
+				URL: "https://web.example.com/api", TLSTermination: "edge", Admitted: true},
+		},
+		{
+			openshift.Route{ObjectMeta: metaV1.ObjectMeta{Name: "web"}, Status: admitted},
+			Route{ObjectMeta: api.ObjectMeta{Name: "web"}, TypeMeta: api.TypeMeta{Kind: api.ResourceKindRoute},
+				URL: "http://web-default.apps.example.com", Admitted: true},
+		},
+	}
+
+	for _, c := range cases {
+		if actual := toRoute(&c.route); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toRoute() == %+v, expected %+v", actual, c.expected)
+		}
+	}
+}
+
+func TestGetRouteListNotServed(t *testing.T) {
+	openshift.SetCapabilities(openshift.Capabilities{})
+	if _, err := GetRouteList(nil, nil, nil); err == nil || err.Error() != MsgRoutesNotServed {
+		t.Errorf("GetRouteList() returned %v, expected %s", err, MsgRoutesNotServed)
+	}
+}
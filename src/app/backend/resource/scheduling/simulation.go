@@ -0,0 +1,279 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduling
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/node"
+)
+
+// activePodsOptions lists pods that hold resources on their nodes.
+var activePodsOptions = metaV1.ListOptions{
+	FieldSelector: fields.AndSelectors(
+		fields.OneTermNotEqualSelector("status.phase", string(v1.PodSucceeded)),
+		fields.OneTermNotEqualSelector("status.phase", string(v1.PodFailed)),
+	).String(),
+}
+
+// NodeFit tells whether the simulated pod could be scheduled to the node.
+type NodeFit struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	Fits bool `json:"fits"`
+
+	// Reasons why the pod does not fit the node. Empty if it fits.
+	Reasons []string `json:"reasons"`
+}
+
+// SimulationResult is the result of evaluating a pod against all nodes. Only node name, node selector,
+// required node affinity, taints and resource requests are evaluated, inter-pod affinity, topology spread
+// and volume constraints are not.
+type SimulationResult struct {
+	// Number of nodes the pod fits.
+	FeasibleNodes int `json:"feasibleNodes"`
+
+	// Nodes the pod fits come first.
+	Nodes []NodeFit `json:"nodes"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// SimulateScheduling evaluates given pod against current nodes without creating it.
+func SimulateScheduling(client kubernetes.Interface, pod *v1.Pod) (*SimulationResult, error) {
+	log.Printf("Simulating scheduling of pod %s", pod.Name)
+	channels := &common.ResourceChannels{
+		NodeList: common.GetNodeListChannel(client, 1),
+		PodList: common.GetPodListChannelWithOptions(client, common.NewNamespaceQuery(nil),
+			activePodsOptions, 1),
+	}
+
+	nodes := <-channels.NodeList.List
+	nonCriticalErrors, criticalError := errors.HandleError(<-channels.NodeList.Error)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	pods := <-channels.PodList.List
+	nonCriticalErrors, criticalError = errors.AppendError(<-channels.PodList.Error, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	requests, _, err := node.PodRequestsAndLimits(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	podsByNode := make(map[string][]v1.Pod)
+	for _, item := range pods.Items {
+		if len(item.Spec.NodeName) > 0 {
+			podsByNode[item.Spec.NodeName] = append(podsByNode[item.Spec.NodeName], item)
+		}
+	}
+
+	result := &SimulationResult{Nodes: make([]NodeFit, 0), Errors: nonCriticalErrors}
+	for i, item := range nodes.Items {
+		reasons := getNodeReasons(pod, &nodes.Items[i])
+		reasons = append(reasons, getResourceReasons(requests, &nodes.Items[i], podsByNode[item.Name])...)
+
+		fit := NodeFit{
+			ObjectMeta: api.NewObjectMeta(item.ObjectMeta),
+			TypeMeta:   api.NewTypeMeta(api.ResourceKindNode),
+			Fits:       len(reasons) == 0,
+			Reasons:    reasons,
+		}
+		if fit.Fits {
+			result.FeasibleNodes++
+		}
+		result.Nodes = append(result.Nodes, fit)
+	}
+
+	sort.SliceStable(result.Nodes, func(i, j int) bool {
+		if result.Nodes[i].Fits != result.Nodes[j].Fits {
+			return result.Nodes[i].Fits
+		}
+		return result.Nodes[i].ObjectMeta.Name < result.Nodes[j].ObjectMeta.Name
+	})
+	return result, nil
+}
+
+// getNodeReasons evaluates node name, cordon, node selector, required node affinity and taints.
+func getNodeReasons(pod *v1.Pod, target *v1.Node) []string {
+	reasons := make([]string, 0)
+	if len(pod.Spec.NodeName) > 0 && pod.Spec.NodeName != target.Name {
+		reasons = append(reasons, fmt.Sprintf("Pod is bound to node %s", pod.Spec.NodeName))
+	}
+
+	if target.Spec.Unschedulable && !toleratesTaint(pod.Spec.Tolerations, &v1.Taint{
+		Key: v1.TaintNodeUnschedulable, Effect: v1.TaintEffectNoSchedule}) {
+		reasons = append(reasons, "Node is cordoned")
+	}
+
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(target.Labels)) {
+		reasons = append(reasons, "Node does not match the node selector")
+	}
+
+	affinity := pod.Spec.Affinity
+	if affinity != nil && affinity.NodeAffinity != nil &&
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		matches, err := matchesNodeSelector(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, target)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("Invalid node affinity: %s", err.Error()))
+		} else if !matches {
+			reasons = append(reasons, "Node does not match the required node affinity")
+		}
+	}
+
+	for i := range target.Spec.Taints {
+		taint := &target.Spec.Taints[i]
+		if taint.Effect == v1.TaintEffectPreferNoSchedule || toleratesTaint(pod.Spec.Tolerations, taint) {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("Pod does not tolerate taint %s", taint.ToString()))
+	}
+
+	return reasons
+}
+
+// getResourceReasons compares pod requests with resources of the node that are not requested by its pods.
+func getResourceReasons(requests v1.ResourceList, target *v1.Node, pods []v1.Pod) []string {
+	reasons := make([]string, 0)
+	if podCapacity, ok := target.Status.Allocatable[v1.ResourcePods]; ok && int64(len(pods)) >= podCapacity.Value() {
+		reasons = append(reasons, fmt.Sprintf("Node already runs %d of %d pods", len(pods), podCapacity.Value()))
+	}
+
+	used := v1.ResourceList{}
+	for i := range pods {
+		podRequests, _, err := node.PodRequestsAndLimits(&pods[i])
+		if err != nil {
+			continue
+		}
+		for name, quantity := range podRequests {
+			value := used[name]
+			value.Add(quantity)
+			used[name] = value
+		}
+	}
+
+	names := make([]string, 0, len(requests))
+	for name := range requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		requested := requests[v1.ResourceName(name)]
+		if requested.IsZero() {
+			continue
+		}
+
+		allocatable, ok := target.Status.Allocatable[v1.ResourceName(name)]
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf("Node does not provide %s", name))
+			continue
+		}
+
+		free := allocatable.DeepCopy()
+		free.Sub(used[v1.ResourceName(name)])
+		if requested.Cmp(free) > 0 {
+			if free.Sign() < 0 {
+				free.Set(0)
+			}
+			reasons = append(reasons, fmt.Sprintf("Insufficient %s: requested %s, %s of %s free", name,
+				requested.String(), free.String(), allocatable.String()))
+		}
+	}
+
+	return reasons
+}
+
+// matchesNodeSelector returns true if node matches any of the selector terms.
+func matchesNodeSelector(selector *v1.NodeSelector, target *v1.Node) (bool, error) {
+	for _, term := range selector.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+
+		labelSelector, err := toSelector(term.MatchExpressions)
+		if err != nil {
+			return false, err
+		}
+
+		fieldSelector, err := toSelector(term.MatchFields)
+		if err != nil {
+			return false, err
+		}
+
+		if labelSelector.Matches(labels.Set(target.Labels)) &&
+			fieldSelector.Matches(labels.Set{"metadata.name": target.Name}) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// toSelector converts node selector requirements to a label selector.
+func toSelector(requirements []v1.NodeSelectorRequirement) (labels.Selector, error) {
+	operators := map[v1.NodeSelectorOperator]selection.Operator{
+		v1.NodeSelectorOpIn:           selection.In,
+		v1.NodeSelectorOpNotIn:        selection.NotIn,
+		v1.NodeSelectorOpExists:       selection.Exists,
+		v1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		v1.NodeSelectorOpGt:           selection.GreaterThan,
+		v1.NodeSelectorOpLt:           selection.LessThan,
+	}
+
+	selector := labels.NewSelector()
+	for _, requirement := range requirements {
+		operator, ok := operators[requirement.Operator]
+		if !ok {
+			return nil, fmt.Errorf("unknown operator %s", requirement.Operator)
+		}
+
+		parsed, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*parsed)
+	}
+
+	return selector, nil
+}
+
+// toleratesTaint returns true if any of the tolerations tolerates the taint.
+func toleratesTaint(tolerations []v1.Toleration, taint *v1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetNodeReasons(t *testing.T) {
+	target := &v1.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: "node-1", Labels: map[string]string{"disk": "ssd", "zone": "a"}},
+		Spec: v1.NodeSpec{Taints: []v1.Taint{
+			{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule},
+			{Key: "spot", Effect: v1.TaintEffectPreferNoSchedule},
+		}},
+	}
+	gpuToleration := []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "gpu",
+		Effect: v1.TaintEffectNoSchedule}}
+	affinity := func(operator v1.NodeSelectorOperator, values ...string) *v1.Affinity {
+		return &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "zone", Operator: operator, Values: values}}},
+			}},
+		}}
+	}
+
+	cases := []struct {
+		info     string
+		spec     v1.PodSpec
+		expected []string
+	}{
+		{"tolerated", v1.PodSpec{Tolerations: gpuToleration}, []string{}},
+		{"not tolerated", v1.PodSpec{}, []string{"Pod does not tolerate taint dedicated=gpu:NoSchedule"}},
+		{
+			"node selector",
+			v1.PodSpec{Tolerations: gpuToleration, NodeSelector: map[string]string{"disk": "hdd"}},
+			[]string{"Node does not match the node selector"},
+		},
+		{
+			"matching affinity",
+			v1.PodSpec{Tolerations: gpuToleration, Affinity: affinity(v1.NodeSelectorOpIn, "a")},
+			[]string{},
+		},
+		{
+			"affinity",
+			v1.PodSpec{Tolerations: gpuToleration, Affinity: affinity(v1.NodeSelectorOpNotIn, "a")},
+			[]string{"Node does not match the required node affinity"},
+		},
+		{
+			"bound",
+			v1.PodSpec{Tolerations: gpuToleration, NodeName: "node-2"},
+			[]string{"Pod is bound to node node-2"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := getNodeReasons(&v1.Pod{Spec: c.spec}, target)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: expected reasons %v, but got %v", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestGetResourceReasons(t *testing.T) {
+	target := &v1.Node{Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+		v1.ResourcePods:   resource.MustParse("10"),
+	}}}
+	running := []v1.Pod{{Spec: v1.PodSpec{Containers: []v1.Container{{Resources: v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1500m")},
+	}}}}}}
+
+	cases := []struct {
+		info     string
+		requests v1.ResourceList
+		expected []string
+	}{
+		{"fits", v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")}, []string{}},
+		{
+			"insufficient cpu",
+			v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+			[]string{"Insufficient cpu: requested 1, 500m of 2 free"},
+		},
+		{
+			"missing resource",
+			v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+			[]string{"Node does not provide nvidia.com/gpu"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := getResourceReasons(c.requests, target, running)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: expected reasons %v, but got %v", c.info, c.expected, actual)
+		}
+	}
+}
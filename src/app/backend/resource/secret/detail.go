@@ -17,12 +17,19 @@ package secret
 import (
 	"context"
 	"log"
+	"net/http"
 
 	v1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 )
 
+// MsgSecretRedacted is returned when values of a redacted secret are read without revealing them.
+const MsgSecretRedacted = "values of the secret are redacted, they have to be revealed"
+
 // SecretDetail API resource provides mechanisms to inject containers with configuration data while keeping
 // containers agnostic of Kubernetes
 type SecretDetail struct {
@@ -34,6 +41,24 @@ type SecretDetail struct {
 	// The serialized form of the secret data is a base64 encoded string,
 	// representing the arbitrary (possibly non-string) data value here.
 	Data map[string][]byte `json:"data"`
+
+	// DataInfo describes values of Data that have been truncated or left out because they are binary. They can
+	// be fetched in slices.
+	DataInfo map[string]common.DataValueInfo `json:"dataInfo,omitempty"`
+
+	// Redacted is true if values of the data have been removed. They can be revealed by users allowed to get the
+	// secret.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// SecretValues contains all values of a secret.
+type SecretValues struct {
+	Data map[string][]byte `json:"data"`
+}
+
+// RedactionPolicy decides whether values of a secret are redacted.
+type RedactionPolicy interface {
+	Redacts(namespace, secretType string, annotations map[string]string) bool
 }
 
 // GetSecretDetail returns detailed information about a secret
@@ -48,9 +73,77 @@ func GetSecretDetail(client kubernetes.Interface, namespace, name string) (*Secr
 	return getSecretDetail(rawSecret), nil
 }
 
+// GetSecretDataSlice returns a slice of the secret value with given key. Values of secrets redacted by given policy
+// can be read only with GetSecretValues.
+func GetSecretDataSlice(client kubernetes.Interface, namespace, name, key string, offset, length int,
+	policy RedactionPolicy) (*common.DataValueSlice, error) {
+	rawSecret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.Redacts(rawSecret.Namespace, string(rawSecret.Type), rawSecret.Annotations) {
+		return nil, errors.NewGenericResponse(http.StatusForbidden, MsgSecretRedacted)
+	}
+
+	value, exists := rawSecret.Data[key]
+	if !exists {
+		return nil, errors.NewNotFound("secret has no key " + key)
+	}
+
+	return common.GetDataValueSlice(key, value, offset, length), nil
+}
+
+// GetSecretValues returns all values of the secret, none of them is truncated. It is used to reveal values of
+// redacted secrets.
+func GetSecretValues(client kubernetes.Interface, namespace, name string) (*SecretValues, error) {
+	log.Printf("Revealing values of %s secret in %s namespace\n", name, namespace)
+
+	rawSecret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	data := rawSecret.Data
+	if data == nil {
+		data = map[string][]byte{}
+	}
+	return &SecretValues{Data: data}, nil
+}
+
+// Redact removes values of the secret data, keeping only the keys. Sizes of the values are removed as well.
+func (s *SecretDetail) Redact() {
+	for key := range s.Data {
+		s.Data[key] = []byte{}
+	}
+	s.DataInfo = nil
+	s.Redacted = true
+}
+
+// IsRedactedBy returns true if values of the secret are redacted by given policy.
+func (s *SecretDetail) IsRedactedBy(policy RedactionPolicy) bool {
+	return policy.Redacts(s.ObjectMeta.Namespace, string(s.Type), s.ObjectMeta.Annotations)
+}
+
 func getSecretDetail(rawSecret *v1.Secret) *SecretDetail {
-	return &SecretDetail{
-		Secret: toSecret(rawSecret),
-		Data:   rawSecret.Data,
+	detail := &SecretDetail{Secret: toSecret(rawSecret)}
+	dataInfo := make(map[string]common.DataValueInfo)
+
+	if rawSecret.Data != nil {
+		detail.Data = make(map[string][]byte, len(rawSecret.Data))
 	}
+
+	for key, value := range rawSecret.Data {
+		truncated, info := common.TruncateDataValue(value)
+		detail.Data[key] = truncated
+		if info != nil {
+			dataInfo[key] = *info
+		}
+	}
+
+	if len(dataInfo) > 0 {
+		detail.DataInfo = dataInfo
+	}
+
+	return detail
 }
@@ -16,14 +16,26 @@ package secret
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
+type fakeRedactionPolicy struct {
+	secretType string
+}
+
+func (p fakeRedactionPolicy) Redacts(namespace, secretType string, annotations map[string]string) bool {
+	return secretType == p.secretType
+}
+
 func TestGetSecretDetail(t *testing.T) {
 	cases := []struct {
 		secrets  *v1.Secret
@@ -48,6 +60,29 @@ func TestGetSecretDetail(t *testing.T) {
 				Data: map[string][]byte{"app": {0, 1, 2, 3}},
 			},
 		},
+		{
+			&v1.Secret{
+				Data: map[string][]byte{
+					"ca.crt": []byte(strings.Repeat("a", common.MaxDataValueSize+1)),
+					"ca.der": make([]byte, common.MaxDataValueSize+1),
+				},
+				ObjectMeta: metaV1.ObjectMeta{Name: "foo"},
+			},
+			&SecretDetail{
+				Secret: Secret{
+					TypeMeta:   api.TypeMeta{Kind: "secret"},
+					ObjectMeta: api.ObjectMeta{Name: "foo"},
+				},
+				Data: map[string][]byte{
+					"ca.crt": []byte(strings.Repeat("a", common.MaxDataValueSize)),
+					"ca.der": {},
+				},
+				DataInfo: map[string]common.DataValueInfo{
+					"ca.crt": {Size: common.MaxDataValueSize + 1, Truncated: true},
+					"ca.der": {Size: common.MaxDataValueSize + 1, Binary: true},
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		actual := getSecretDetail(c.secrets)
@@ -56,3 +91,61 @@ func TestGetSecretDetail(t *testing.T) {
 		}
 	}
 }
+
+func TestSecretDetailRedact(t *testing.T) {
+	detail := getSecretDetail(&v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Type:       v1.SecretTypeOpaque,
+		Data:       map[string][]byte{"password": []byte("secret")},
+	})
+
+	if !detail.IsRedactedBy(fakeRedactionPolicy{secretType: string(v1.SecretTypeOpaque)}) {
+		t.Error("opaque secret should be redacted by the policy")
+	}
+
+	if detail.IsRedactedBy(fakeRedactionPolicy{secretType: string(v1.SecretTypeTLS)}) {
+		t.Error("opaque secret should not be redacted by the policy redacting TLS secrets")
+	}
+
+	detail.Redact()
+	if !detail.Redacted || len(detail.Data["password"]) > 0 || detail.DataInfo != nil {
+		t.Errorf("redacted secret should keep only keys of the data, got %#v", detail)
+	}
+}
+
+func TestGetSecretDataSliceRedacted(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Type:       v1.SecretTypeOpaque,
+		Data:       map[string][]byte{"password": []byte("secret")},
+	})
+
+	_, err := GetSecretDataSlice(client, "bar", "foo", "password", 0, 1,
+		fakeRedactionPolicy{secretType: string(v1.SecretTypeOpaque)})
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("reading data of redacted secret should be forbidden, got %v", err)
+	}
+
+	slice, err := GetSecretDataSlice(client, "bar", "foo", "password", 0, 1,
+		fakeRedactionPolicy{secretType: string(v1.SecretTypeTLS)})
+	if err != nil || slice.Value != "s" {
+		t.Errorf("reading data of secret, which is not redacted, should succeed, got %#v, %v", slice, err)
+	}
+}
+
+func TestGetSecretValues(t *testing.T) {
+	value := []byte(strings.Repeat("a", common.MaxDataValueSize+1))
+	client := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Data:       map[string][]byte{"ca.crt": value},
+	})
+
+	values, err := GetSecretValues(client, "bar", "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(values.Data, map[string][]byte{"ca.crt": value}) {
+		t.Errorf("revealed values should not be truncated, got %d bytes", len(values.Data["ca.crt"]))
+	}
+}
@@ -85,7 +85,7 @@ type SecretList struct {
 // GetSecretList returns all secrets in the given namespace.
 func GetSecretList(client kubernetes.Interface, namespace *common.NamespaceQuery,
 	dsQuery *dataselect.DataSelectQuery) (*SecretList, error) {
-	log.Printf("Getting list of secrets in %s namespace\n", namespace)
+	log.Printf("Getting list of secrets in %s namespace\n", namespace.ToRequestParam())
 	secretList, err := client.CoreV1().Secrets(namespace.ToRequestParam()).List(context.TODO(), api.ListEverything)
 
 	nonCriticalErrors, criticalError := errors.HandleError(err)
@@ -93,7 +93,14 @@ func GetSecretList(client kubernetes.Interface, namespace *common.NamespaceQuery
 		return nil, criticalError
 	}
 
-	return ToSecretList(secretList.Items, nonCriticalErrors, dsQuery), nil
+	var secrets []v1.Secret
+	for _, item := range secretList.Items {
+		if namespace.Matches(item.Namespace) && !common.IsProtected(api.ResourceKindSecret, item.ObjectMeta) {
+			secrets = append(secrets, item)
+		}
+	}
+
+	return ToSecretList(secrets, nonCriticalErrors, dsQuery), nil
 }
 
 // CreateSecret creates a single secret using the cluster API client
@@ -19,10 +19,12 @@ import (
 	"testing"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	v1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestToSecretList(t *testing.T) {
@@ -82,3 +84,27 @@ func TestToSecretList(t *testing.T) {
 		}
 	}
 }
+
+func TestGetSecretListProtected(t *testing.T) {
+	if err := authApi.SetProtectedResources([]string{"secret:foo/db-*", "*/*@sensitive=true"}); err != nil {
+		t.Fatal(err)
+	}
+	defer authApi.SetProtectedResources(nil)
+
+	client := fake.NewSimpleClientset(
+		&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "db-password", Namespace: "foo"}},
+		&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "db-password", Namespace: "bar"}},
+		&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "api-key", Namespace: "bar",
+			Labels: map[string]string{"sensitive": "true"}}},
+	)
+
+	actual, err := GetSecretList(client, common.NewNamespaceQuery(nil), dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("GetSecretList() returned error: %v", err)
+	}
+
+	if len(actual.Secrets) != 1 || actual.Secrets[0].ObjectMeta.Namespace != "bar" ||
+		actual.Secrets[0].ObjectMeta.Name != "db-password" {
+		t.Errorf("GetSecretList() == %+v, expected only db-password secret in bar namespace", actual.Secrets)
+	}
+}
@@ -54,7 +54,14 @@ func GetServiceDetail(client k8sClient.Interface, namespace, name string) (*Serv
 		return nil, criticalError
 	}
 
+	warnings, err := getServiceWarnings(client, *serviceData)
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
 	service := toServiceDetail(serviceData, *endpointList, nonCriticalErrors)
+	service.Warnings = warnings
 	return &service, nil
 }
 
@@ -67,7 +67,7 @@ func TestGetServiceDetail(t *testing.T) {
 				},
 			},
 			namespace: "ns-2", name: "svc-2",
-			expectedActions: []string{"get", "list"},
+			expectedActions: []string{"get", "list", "list"},
 			expected: &ServiceDetail{
 				Service: Service{
 					ObjectMeta: api.ObjectMeta{
@@ -78,6 +78,7 @@ func TestGetServiceDetail(t *testing.T) {
 					TypeMeta:          api.TypeMeta{Kind: api.ResourceKindService},
 					InternalEndpoint:  common.Endpoint{Host: "svc-2.ns-2"},
 					ExternalEndpoints: []common.Endpoint{},
+					Warnings:          []string{NoMatchingPodsWarning},
 				},
 
 				EndpointList: endpoint.EndpointList{
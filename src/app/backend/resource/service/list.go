@@ -22,6 +22,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
 )
 
@@ -47,6 +48,12 @@ type Service struct {
 	// ClusterIP is usually assigned by the master. Valid values are None, empty string (""), or
 	// a valid IP address. None can be specified for headless services when proxying is not required
 	ClusterIP string `json:"clusterIP"`
+
+	// IPFamilies of the cluster IP. Headless services have the family requested in their spec, if any.
+	IPFamilies []v1.IPFamily `json:"ipFamilies,omitempty"`
+
+	// Warnings about problems with the service configuration, i.e. selector that does not match any pod.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ServiceList contains a list of services in the cluster.
@@ -67,12 +74,14 @@ func GetServiceList(client client.Interface, nsQuery *common.NamespaceQuery,
 
 	channels := &common.ResourceChannels{
 		ServiceList: common.GetServiceListChannel(client, nsQuery, 1),
+		PodList:     common.GetPodListChannel(client, nsQuery, 1),
 	}
 
 	return GetServiceListFromChannels(channels, dsQuery)
 }
 
-// GetServiceListFromChannels returns a list of all services in the cluster.
+// GetServiceListFromChannels returns a list of all services in the cluster. If pod list channel is provided,
+// services are checked for selectors that do not match any pod.
 func GetServiceListFromChannels(channels *common.ResourceChannels,
 	dsQuery *dataselect.DataSelectQuery) (*ServiceList, error) {
 	services := <-channels.ServiceList.List
@@ -82,7 +91,29 @@ func GetServiceListFromChannels(channels *common.ResourceChannels,
 		return nil, criticalError
 	}
 
-	return CreateServiceList(services.Items, nonCriticalErrors, dsQuery), nil
+	serviceList := CreateServiceList(services.Items, nonCriticalErrors, dsQuery)
+	if channels.PodList.List == nil {
+		return serviceList, nil
+	}
+
+	pods := <-channels.PodList.List
+	err = <-channels.PodList.Error
+	serviceList.Errors, criticalError = errors.AppendError(err, serviceList.Errors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	if err == nil {
+		for i := range serviceList.Services {
+			s := &serviceList.Services[i]
+			s.Warnings = getSelectorWarnings(v1.Service{
+				ObjectMeta: metaV1.ObjectMeta{Namespace: s.ObjectMeta.Namespace},
+				Spec:       v1.ServiceSpec{Selector: s.Selector, Type: s.Type},
+			}, pods.Items)
+		}
+	}
+
+	return serviceList, nil
 }
 
 func toService(service *v1.Service) Service {
@@ -93,10 +124,22 @@ func toService(service *v1.Service) Service {
 		ExternalEndpoints: common.GetExternalEndpoints(service),
 		Selector:          service.Spec.Selector,
 		ClusterIP:         service.Spec.ClusterIP,
+		IPFamilies:        getServiceIPFamilies(service),
 		Type:              service.Spec.Type,
 	}
 }
 
+func getServiceIPFamilies(service *v1.Service) []v1.IPFamily {
+	if families := common.GetIPFamilies([]string{service.Spec.ClusterIP}); len(families) > 0 {
+		return families
+	}
+
+	if service.Spec.IPFamily != nil {
+		return []v1.IPFamily{*service.Spec.IPFamily}
+	}
+	return nil
+}
+
 // CreateServiceList returns paginated service list based on given service array and pagination query.
 func CreateServiceList(services []v1.Service, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *ServiceList {
 	serviceList := &ServiceList{
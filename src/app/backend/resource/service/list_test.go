@@ -43,7 +43,7 @@ func TestGetServiceList(t *testing.T) {
 						Labels: map[string]string{},
 					}},
 				}},
-			expectedActions: []string{"list"},
+			expectedActions: []string{"list", "list"},
 			expected: &ServiceList{
 				ListMeta: api.ListMeta{TotalItems: 1},
 				Services: []Service{
@@ -0,0 +1,155 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sClient "k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// SelectorSwitchSpec is a request to switch traffic of a service to another version of a workload.
+type SelectorSwitchSpec struct {
+	// Selector contains labels set on the service selector, i.e. {"version": "green"}. Other labels of the
+	// current selector are kept.
+	Selector map[string]string `json:"selector"`
+
+	// ExpectedSelector is compared with the current selector, if it is set. It prevents switching twice when
+	// multiple users flip the service at the same time.
+	ExpectedSelector map[string]string `json:"expectedSelector,omitempty"`
+
+	// DryRun only runs the pre-flight validation.
+	DryRun bool `json:"dryRun"`
+}
+
+// SelectorSwitchResult describes the switch of a service selector.
+type SelectorSwitchResult struct {
+	PreviousSelector map[string]string `json:"previousSelector"`
+	Selector         map[string]string `json:"selector"`
+	// ReadyPods is the number of ready pods matching the new selector.
+	ReadyPods int  `json:"readyPods"`
+	DryRun    bool `json:"dryRun"`
+}
+
+// SwitchServiceSelector switches the selector of a service, but only if the new selector matches ready pods,
+// that expose all named target ports of the service. The service is updated at the version that was
+// validated, so concurrent changes make the switch fail instead of being overwritten.
+func SwitchServiceSelector(client k8sClient.Interface, namespace, name string, spec *SelectorSwitchSpec) (
+	*SelectorSwitchResult, error) {
+	if len(spec.Selector) == 0 {
+		return nil, errors.NewBadRequest("selector to switch to cannot be empty")
+	}
+
+	service, err := client.CoreV1().Services(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(service.Spec.Selector) == 0 || service.Spec.Type == v1.ServiceTypeExternalName {
+		return nil, errors.NewBadRequest(fmt.Sprintf("service %s does not select pods", name))
+	}
+	if spec.ExpectedSelector != nil && !reflect.DeepEqual(service.Spec.Selector, spec.ExpectedSelector) {
+		return nil, errors.NewGenericResponse(http.StatusConflict, fmt.Sprintf("service selector is %v, expected %v",
+			service.Spec.Selector, spec.ExpectedSelector))
+	}
+
+	selector := make(map[string]string)
+	for key, value := range service.Spec.Selector {
+		selector[key] = value
+	}
+	for key, value := range spec.Selector {
+		selector[key] = value
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metaV1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ready, err := validateSwitchTarget(service, pods.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SelectorSwitchResult{PreviousSelector: service.Spec.Selector, Selector: selector, ReadyPods: ready,
+		DryRun: spec.DryRun}
+	if spec.DryRun {
+		return result, nil
+	}
+
+	service.Spec.Selector = selector
+	if _, err := client.CoreV1().Services(namespace).Update(context.TODO(), service, metaV1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validateSwitchTarget returns the number of ready pods, that can serve the service. Error is returned if
+// there are none.
+func validateSwitchTarget(service *v1.Service, pods []v1.Pod) (int, error) {
+	ready := 0
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil && isPodReady(pod) && exposesTargetPorts(service, pod) {
+			ready++
+		}
+	}
+
+	if ready == 0 {
+		return 0, errors.NewBadRequest(fmt.Sprintf("none of %d pods matching the new selector is ready to serve "+
+			"service %s", len(pods), service.Name))
+	}
+	return ready, nil
+}
+
+func isPodReady(pod v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// exposesTargetPorts returns true if pod has container ports with all names used as target ports by service.
+func exposesTargetPorts(service *v1.Service, pod v1.Pod) bool {
+	for _, port := range service.Spec.Ports {
+		if port.TargetPort.Type != intstr.String {
+			continue
+		}
+
+		found := false
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				found = found || containerPort.Name == port.TargetPort.StrVal
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func switchTestPod(name, version string, ready v1.ConditionStatus) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default",
+			Labels: map[string]string{"app": "web", "version": version}},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "web",
+			Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}}}}},
+		Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}}},
+	}
+}
+
+func TestSwitchServiceSelector(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "web", "version": "blue"},
+			Ports:    []v1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+		},
+	}
+
+	cases := []struct {
+		info     string
+		spec     SelectorSwitchSpec
+		expected map[string]string
+		wantErr  bool
+	}{
+		{
+			"switch to ready version",
+			SelectorSwitchSpec{Selector: map[string]string{"version": "green"}},
+			map[string]string{"app": "web", "version": "green"},
+			false,
+		},
+		{
+			"dry run",
+			SelectorSwitchSpec{Selector: map[string]string{"version": "green"}, DryRun: true},
+			map[string]string{"app": "web", "version": "blue"},
+			false,
+		},
+		{
+			"no ready pods",
+			SelectorSwitchSpec{Selector: map[string]string{"version": "red"}},
+			map[string]string{"app": "web", "version": "blue"},
+			true,
+		},
+		{
+			"unexpected current selector",
+			SelectorSwitchSpec{Selector: map[string]string{"version": "green"},
+				ExpectedSelector: map[string]string{"app": "web", "version": "green"}},
+			map[string]string{"app": "web", "version": "blue"},
+			true,
+		},
+		{
+			"empty selector",
+			SelectorSwitchSpec{},
+			map[string]string{"app": "web", "version": "blue"},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset(service.DeepCopy(),
+			switchTestPod("blue", "blue", v1.ConditionTrue),
+			switchTestPod("green", "green", v1.ConditionTrue),
+			switchTestPod("red", "red", v1.ConditionFalse))
+
+		_, err := SwitchServiceSelector(client, "default", "web", &c.spec)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: unexpected error: %v", c.info, err)
+		}
+
+		actual, _ := client.CoreV1().Services("default").Get(context.TODO(), "web", metaV1.GetOptions{})
+		if !reflect.DeepEqual(actual.Spec.Selector, c.expected) {
+			t.Errorf("%s: expected selector %v, got %v", c.info, c.expected, actual.Spec.Selector)
+		}
+	}
+}
+
+func TestValidateSwitchTargetNamedPorts(t *testing.T) {
+	service := &v1.Service{Spec: v1.ServiceSpec{
+		Ports: []v1.ServicePort{{Port: 80, TargetPort: intstr.FromString("metrics")}},
+	}}
+
+	if _, err := validateSwitchTarget(service, []v1.Pod{*switchTestPod("a", "green", v1.ConditionTrue)}); err == nil {
+		t.Error("expected error for pods not exposing the named target port")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sClient "k8s.io/client-go/kubernetes"
+)
+
+// NoMatchingPodsWarning is reported for services, whose selector does not match any pod.
+const NoMatchingPodsWarning = "Service selector does not match any pod"
+
+// getSelectorWarnings returns warnings about service selector. Services without selector, i.e. the ones
+// with manually managed endpoints or of ExternalName type, are not checked.
+func getSelectorWarnings(service v1.Service, pods []v1.Pod) []string {
+	if len(service.Spec.Selector) == 0 || service.Spec.Type == v1.ServiceTypeExternalName {
+		return nil
+	}
+
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	for _, pod := range pods {
+		if pod.Namespace == service.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+			return nil
+		}
+	}
+
+	return []string{NoMatchingPodsWarning}
+}
+
+// getServiceWarnings lists pods targeted by the service and returns warnings about its selector.
+func getServiceWarnings(client k8sClient.Interface, service v1.Service) ([]string, error) {
+	if len(service.Spec.Selector) == 0 || service.Spec.Type == v1.ServiceTypeExternalName {
+		return nil, nil
+	}
+
+	pods, err := client.CoreV1().Pods(service.Namespace).List(context.TODO(), metaV1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return getSelectorWarnings(service, pods.Items), nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSelectorWarnings(t *testing.T) {
+	pods := []v1.Pod{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "db", Namespace: "data", Labels: map[string]string{"app": "db"}}},
+	}
+
+	cases := []struct {
+		info     string
+		service  v1.Service
+		expected []string
+	}{
+		{
+			"matching selector",
+			v1.Service{ObjectMeta: metaV1.ObjectMeta{Namespace: "default"},
+				Spec: v1.ServiceSpec{Selector: map[string]string{"app": "web"}}},
+			nil,
+		},
+		{
+			"pod in other namespace",
+			v1.Service{ObjectMeta: metaV1.ObjectMeta{Namespace: "default"},
+				Spec: v1.ServiceSpec{Selector: map[string]string{"app": "db"}}},
+			[]string{NoMatchingPodsWarning},
+		},
+		{
+			"no selector",
+			v1.Service{ObjectMeta: metaV1.ObjectMeta{Namespace: "default"}},
+			nil,
+		},
+		{
+			"external name",
+			v1.Service{ObjectMeta: metaV1.ObjectMeta{Namespace: "default"},
+				Spec: v1.ServiceSpec{Type: v1.ServiceTypeExternalName, Selector: map[string]string{"app": "x"}}},
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		actual := getSelectorWarnings(c.service, pods)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Expected warnings %v, but got %v", c.info, c.expected, actual)
+		}
+	}
+}
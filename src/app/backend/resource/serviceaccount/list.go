@@ -50,7 +50,14 @@ func GetServiceAccountList(client client.Interface, namespace *common.NamespaceQ
 		return nil, criticalError
 	}
 
-	return toServiceAccountList(saList.Items, nonCriticalErrors, dsQuery), nil
+	var serviceAccounts []v1.ServiceAccount
+	for _, item := range saList.Items {
+		if namespace.Matches(item.Namespace) {
+			serviceAccounts = append(serviceAccounts, item)
+		}
+	}
+
+	return toServiceAccountList(serviceAccounts, nonCriticalErrors, dsQuery), nil
 }
 
 func toServiceAccount(sa *v1.ServiceAccount) ServiceAccount {
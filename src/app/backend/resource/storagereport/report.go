@@ -0,0 +1,239 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagereport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// DefaultUnboundThreshold is the time after which pending claim is reported as unbound.
+const DefaultUnboundThreshold = 10 * time.Minute
+
+// IssueType describes the storage problem.
+type IssueType string
+
+const (
+	// IssueReleased is reported for volumes released by their claims, but not reclaimed.
+	IssueReleased IssueType = "Released"
+	// IssueFailed is reported for volumes that failed automatic reclamation.
+	IssueFailed IssueType = "Failed"
+	// IssueOrphaned is reported for volumes, whose claims no longer exist.
+	IssueOrphaned IssueType = "Orphaned"
+	// IssueUnbound is reported for claims, that were not bound for longer than the threshold.
+	IssueUnbound IssueType = "Unbound"
+)
+
+// Action is a suggested cleanup operation. It points to the dashboard API endpoint that performs it.
+type Action struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// StorageIssue is a single problem found in volumes or claims.
+type StorageIssue struct {
+	ObjectMeta    api.ObjectMeta                   `json:"objectMeta"`
+	TypeMeta      api.TypeMeta                     `json:"typeMeta"`
+	Type          IssueType                        `json:"type"`
+	Message       string                           `json:"message"`
+	Claim         string                           `json:"claim,omitempty"`
+	ReclaimPolicy v1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	Actions       []Action                         `json:"actions"`
+}
+
+// StorageReport contains storage problems found in the cluster.
+type StorageReport struct {
+	ListMeta api.ListMeta   `json:"listMeta"`
+	Issues   []StorageIssue `json:"issues"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ReclaimPolicySpec is used to change reclaim policy of a persistent volume.
+type ReclaimPolicySpec struct {
+	ReclaimPolicy v1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy"`
+}
+
+// GetStorageReport returns released, failed and orphaned persistent volumes together with claims
+// that are pending longer than the threshold.
+func GetStorageReport(client kubernetes.Interface, unboundThreshold time.Duration) (*StorageReport, error) {
+	log.Print("Getting storage report")
+	nsQuery := common.NewNamespaceQuery(nil)
+	channels := &common.ResourceChannels{
+		PersistentVolumeList:      common.GetPersistentVolumeListChannel(client, 1),
+		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannel(client, nsQuery, 1),
+	}
+
+	volumes := <-channels.PersistentVolumeList.List
+	err := <-channels.PersistentVolumeList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	claims := <-channels.PersistentVolumeClaimList.List
+	err = <-channels.PersistentVolumeClaimList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	// Claims list is empty rather than nil when it can not be retrieved. Without it every bound volume
+	// would look orphaned.
+	if err != nil {
+		claims = nil
+	}
+
+	var volumeItems []v1.PersistentVolume
+	if volumes != nil {
+		volumeItems = volumes.Items
+	}
+
+	issues := getVolumeIssues(volumeItems, claims)
+	if claims != nil {
+		issues = append(issues, getClaimIssues(claims.Items, time.Now().Add(-unboundThreshold))...)
+	}
+
+	return &StorageReport{
+		ListMeta: api.ListMeta{TotalItems: len(issues)},
+		Issues:   issues,
+		Errors:   nonCriticalErrors,
+	}, nil
+}
+
+// SetReclaimPolicy changes reclaim policy of given persistent volume.
+func SetReclaimPolicy(client kubernetes.Interface, name string, spec *ReclaimPolicySpec) error {
+	switch spec.ReclaimPolicy {
+	case v1.PersistentVolumeReclaimRetain, v1.PersistentVolumeReclaimDelete, v1.PersistentVolumeReclaimRecycle:
+	default:
+		return errors.NewBadRequest(fmt.Sprintf("unsupported reclaim policy: %s", spec.ReclaimPolicy))
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"persistentVolumeReclaimPolicy": spec.ReclaimPolicy},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CoreV1().PersistentVolumes().Patch(context.TODO(), name, types.MergePatchType, patch,
+		metaV1.PatchOptions{})
+	return err
+}
+
+// getVolumeIssues returns issues of persistent volumes. Orphans are detected only if claims could be listed.
+func getVolumeIssues(volumes []v1.PersistentVolume, claims *v1.PersistentVolumeClaimList) []StorageIssue {
+	existing := make(map[string]types.UID)
+	if claims != nil {
+		for _, claim := range claims.Items {
+			existing[claim.Namespace+"/"+claim.Name] = claim.UID
+		}
+	}
+
+	issues := make([]StorageIssue, 0)
+	for _, volume := range volumes {
+		claim := ""
+		if volume.Spec.ClaimRef != nil {
+			claim = volume.Spec.ClaimRef.Namespace + "/" + volume.Spec.ClaimRef.Name
+		}
+
+		issue := StorageIssue{
+			ObjectMeta:    api.NewObjectMeta(volume.ObjectMeta),
+			TypeMeta:      api.NewTypeMeta(api.ResourceKindPersistentVolume),
+			Claim:         claim,
+			ReclaimPolicy: volume.Spec.PersistentVolumeReclaimPolicy,
+		}
+
+		switch volume.Status.Phase {
+		case v1.VolumeReleased:
+			issue.Type = IssueReleased
+			issue.Message = fmt.Sprintf("Volume was released by claim %s and is kept due to %s reclaim policy",
+				claim, volume.Spec.PersistentVolumeReclaimPolicy)
+		case v1.VolumeFailed:
+			issue.Type = IssueFailed
+			issue.Message = fmt.Sprintf("Volume reclamation failed: %s", volume.Status.Message)
+		case v1.VolumeBound:
+			uid, ok := existing[claim]
+			if claims == nil || (ok && uid == volume.Spec.ClaimRef.UID) {
+				continue
+			}
+			issue.Type = IssueOrphaned
+			issue.Message = fmt.Sprintf("Volume is bound to claim %s that no longer exists", claim)
+		default:
+			continue
+		}
+
+		issue.Actions = getVolumeActions(volume)
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+func getClaimIssues(claims []v1.PersistentVolumeClaim, pendingBefore time.Time) []StorageIssue {
+	issues := make([]StorageIssue, 0)
+	for _, claim := range claims {
+		if claim.Status.Phase != v1.ClaimPending || claim.CreationTimestamp.Time.After(pendingBefore) {
+			continue
+		}
+
+		issues = append(issues, StorageIssue{
+			ObjectMeta: api.NewObjectMeta(claim.ObjectMeta),
+			TypeMeta:   api.NewTypeMeta(api.ResourceKindPersistentVolumeClaim),
+			Type:       IssueUnbound,
+			Message: fmt.Sprintf("Claim is pending since %s",
+				claim.CreationTimestamp.UTC().Format(time.RFC3339)),
+			Actions: []Action{{
+				Name:   "delete",
+				Method: "DELETE",
+				Path: fmt.Sprintf("/api/v1/_raw/%s/namespace/%s/name/%s",
+					api.ResourceKindPersistentVolumeClaim, claim.Namespace, claim.Name),
+			}},
+		})
+	}
+
+	return issues
+}
+
+func getVolumeActions(volume v1.PersistentVolume) []Action {
+	actions := make([]Action, 0)
+	if volume.Spec.PersistentVolumeReclaimPolicy != v1.PersistentVolumeReclaimDelete {
+		actions = append(actions, Action{
+			Name:   "setReclaimPolicy",
+			Method: "PUT",
+			Path:   fmt.Sprintf("/api/v1/storagereport/persistentvolume/%s/reclaimpolicy", volume.Name),
+		})
+	}
+
+	return append(actions, Action{
+		Name:   "delete",
+		Method: "DELETE",
+		Path:   fmt.Sprintf("/api/v1/_raw/%s/name/%s", api.ResourceKindPersistentVolume, volume.Name),
+	})
+}
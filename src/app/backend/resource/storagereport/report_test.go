@@ -0,0 +1,102 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagereport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetStorageReport(t *testing.T) {
+	old := metaV1.NewTime(time.Now().Add(-time.Hour))
+	client := fake.NewSimpleClientset(
+		&v1.PersistentVolume{
+			ObjectMeta: metaV1.ObjectMeta{Name: "released"},
+			Spec: v1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+				ClaimRef: &v1.ObjectReference{Namespace: "default", Name: "gone"}},
+			Status: v1.PersistentVolumeStatus{Phase: v1.VolumeReleased},
+		},
+		&v1.PersistentVolume{
+			ObjectMeta: metaV1.ObjectMeta{Name: "orphaned"},
+			Spec: v1.PersistentVolumeSpec{
+				ClaimRef: &v1.ObjectReference{Namespace: "default", Name: "missing", UID: "uid-missing"}},
+			Status: v1.PersistentVolumeStatus{Phase: v1.VolumeBound},
+		},
+		&v1.PersistentVolume{
+			ObjectMeta: metaV1.ObjectMeta{Name: "healthy"},
+			Spec: v1.PersistentVolumeSpec{
+				ClaimRef: &v1.ObjectReference{Namespace: "default", Name: "data", UID: "uid-data"}},
+			Status: v1.PersistentVolumeStatus{Phase: v1.VolumeBound},
+		},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{Name: "data", Namespace: "default", UID: "uid-data"},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+		},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{Name: "stuck", Namespace: "default", CreationTimestamp: old},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{Name: "fresh", Namespace: "default",
+				CreationTimestamp: metaV1.NewTime(time.Now())},
+			Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		},
+	)
+
+	report, err := GetStorageReport(client, DefaultUnboundThreshold)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]IssueType{"released": IssueReleased, "orphaned": IssueOrphaned, "stuck": IssueUnbound}
+	if len(report.Issues) != len(expected) {
+		t.Fatalf("Expected %d issues, but got %d: %v", len(expected), len(report.Issues), report.Issues)
+	}
+
+	for _, issue := range report.Issues {
+		if expected[issue.ObjectMeta.Name] != issue.Type {
+			t.Errorf("Test Case: %s. Expected issue type %s, but got %s", issue.ObjectMeta.Name,
+				expected[issue.ObjectMeta.Name], issue.Type)
+		}
+
+		if len(issue.Actions) == 0 {
+			t.Errorf("Test Case: %s. Expected cleanup actions to be suggested", issue.ObjectMeta.Name)
+		}
+	}
+}
+
+func TestSetReclaimPolicy(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.PersistentVolume{ObjectMeta: metaV1.ObjectMeta{Name: "pv"}})
+
+	if err := SetReclaimPolicy(client, "pv", &ReclaimPolicySpec{ReclaimPolicy: "Keep"}); err == nil {
+		t.Error("Expected error for unsupported reclaim policy")
+	}
+
+	err := SetReclaimPolicy(client, "pv", &ReclaimPolicySpec{ReclaimPolicy: v1.PersistentVolumeReclaimDelete})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pv, _ := client.CoreV1().PersistentVolumes().Get(context.TODO(), "pv", metaV1.GetOptions{})
+	if pv.Spec.PersistentVolumeReclaimPolicy != v1.PersistentVolumeReclaimDelete {
+		t.Errorf("Expected reclaim policy to be %s, but got %s", v1.PersistentVolumeReclaimDelete,
+			pv.Spec.PersistentVolumeReclaimPolicy)
+	}
+}
@@ -16,18 +16,23 @@ package scaling
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
 
 	apps "k8s.io/api/apps/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/scale"
+
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
 )
 
 // ReplicaCounts provide the desired and actual number of replicas.
@@ -55,10 +60,70 @@ func GetReplicaCounts(cfg *rest.Config, kind, namespace, name string) (*ReplicaC
 	}, nil
 }
 
+// ScaleSpec describes how the number of replicas is changed. Count is either absolute, i.e. "3", or relative to the
+// current number of replicas, i.e. "+2" or "-1".
+type ScaleSpec struct {
+	Replicas int32
+	Relative bool
+}
+
+// ParseScaleSpec parses count of the scale request.
+func ParseScaleSpec(count string) (*ScaleSpec, error) {
+	relative := strings.HasPrefix(count, "+") || strings.HasPrefix(count, "-")
+	replicas, err := strconv.ParseInt(count, 10, 32)
+	if err != nil || (!relative && replicas < 0) {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid replica count %q, use i.e. 3, +2 or -1", count))
+	}
+
+	return &ScaleSpec{Replicas: int32(replicas), Relative: relative}, nil
+}
+
+// Returns the desired number of replicas given the current one. Result is clamped to bounds of horizontal
+// pod autoscalers targeting the resource, as they would scale it back anyway.
+func (self ScaleSpec) replicas(current int32, hpas []horizontalpodautoscaler.HorizontalPodAutoscaler) int32 {
+	replicas := self.Replicas
+	if self.Relative {
+		replicas += current
+	}
+
+	for _, hpa := range hpas {
+		minReplicas := int32(1)
+		if hpa.MinReplicas != nil {
+			minReplicas = *hpa.MinReplicas
+		}
+
+		if replicas < minReplicas {
+			replicas = minReplicas
+		}
+
+		if replicas > hpa.MaxReplicas {
+			replicas = hpa.MaxReplicas
+		}
+	}
+
+	if replicas < 0 {
+		return 0
+	}
+
+	return replicas
+}
+
 // ScaleResource scales the provided resource using the client scale method in the case of Deployment,
 // ReplicaSet, Replication Controller. In the case of a job we are using the jobs resource update
-// method since the client scale method does not provide one for the job.
+// method since the client scale method does not provide one for the job. Count can be relative to the current
+// number of replicas. See ScaleSpec for more information.
 func ScaleResource(cfg *rest.Config, kind, namespace, name, count string) (*ReplicaCounts, error) {
+	spec, err := ParseScaleSpec(count)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scale getter changes the group version of the config, so the client is created first.
+	k8sClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	sc, err := getScaleGetter(cfg)
 	if err != nil {
 		return nil, err
@@ -70,12 +135,12 @@ func ScaleResource(cfg *rest.Config, kind, namespace, name, count string) (*Repl
 		return nil, err
 	}
 
-	c, err := strconv.Atoi(count)
+	hpas, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerListForResource(k8sClient, namespace, kind, name)
 	if err != nil {
 		return nil, err
 	}
 
-	res.Spec.Replicas = int32(c)
+	res.Spec.Replicas = spec.replicas(res.Spec.Replicas, hpas.HorizontalPodAutoscalers)
 
 	res, err = sc.Scales(namespace).Update(context.TODO(), gr, res, metaV1.UpdateOptions{})
 	if err != nil {
@@ -89,7 +154,7 @@ func ScaleResource(cfg *rest.Config, kind, namespace, name, count string) (*Repl
 }
 
 func getScaleGetter(cfg *rest.Config) (scale.ScalesGetter, error) {
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	discoveryClient, err := client.NewCachedDiscoveryClient(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -103,12 +168,9 @@ func getScaleGetter(cfg *rest.Config) (scale.ScalesGetter, error) {
 	}
 
 	resolver := scale.NewDiscoveryScaleKindResolver(discoveryClient)
-	dc := memory.NewMemCacheClient(discoveryClient)
-	drm := restmapper.NewDeferredDiscoveryRESTMapper(dc)
-
-	// Fixes "unable to get full preferred group-version-resource for <resource>: the cache has not been filled yet".
-	// See more: https://github.com/kubernetes/kubernetes/issues/68735
-	drm.Reset()
+	// Discovery results are shared with other requests, so the mapper is not reset. Cached discovery client
+	// fills missing results on the first lookup.
+	drm := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
 
 	return scale.New(restClient, drm, dynamic.LegacyAPIPathResolverFunc, resolver), nil
 }
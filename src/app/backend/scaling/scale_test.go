@@ -0,0 +1,138 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaling
+
+import (
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
+)
+
+func TestScaleSpecReplicas(t *testing.T) {
+	minReplicas := int32(2)
+	hpas := []horizontalpodautoscaler.HorizontalPodAutoscaler{{MinReplicas: &minReplicas, MaxReplicas: 5}}
+	cases := []struct {
+		count    string
+		current  int32
+		hpas     []horizontalpodautoscaler.HorizontalPodAutoscaler
+		expected int32
+	}{
+		{"3", 1, nil, 3},
+		{"+2", 3, nil, 5},
+		{"-2", 3, nil, 1},
+		{"-5", 3, nil, 0},
+		{"0", 3, nil, 0},
+		{"10", 3, hpas, 5},
+		{"-2", 3, hpas, 2},
+		{"+1", 3, hpas, 4},
+		{"0", 3, []horizontalpodautoscaler.HorizontalPodAutoscaler{{MaxReplicas: 5}}, 1},
+	}
+
+	for _, c := range cases {
+		spec, err := ParseScaleSpec(c.count)
+		if err != nil {
+			t.Fatalf("Expected %s to be valid, but got %v.", c.count, err)
+		}
+
+		if replicas := spec.replicas(c.current, c.hpas); replicas != c.expected {
+			t.Errorf("Expected %s of %d replicas with %d autoscalers to be %d, but got %d.", c.count, c.current,
+				len(c.hpas), c.expected, replicas)
+		}
+	}
+
+	for _, count := range []string{"", "-", "two", "+1.5", "-3x"} {
+		if _, err := ParseScaleSpec(count); !k8serrors.IsBadRequest(err) {
+			t.Errorf("Expected bad request error for %q, but got %v.", count, err)
+		}
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	done := make(chan string, 1)
+	scheduler := NewScheduler()
+	scheduler.get = func(cfg *rest.Config, kind, namespace, name string) (*ReplicaCounts, error) {
+		if name == "missing" {
+			return nil, k8serrors.NewNotFound(getGroupResource(kind), name)
+		}
+		return &ReplicaCounts{}, nil
+	}
+	scheduler.scale = func(cfg *rest.Config, kind, namespace, name, count string) (*ReplicaCounts, error) {
+		done <- cfg.Username + ":" + name + ":" + count
+		return &ReplicaCounts{}, nil
+	}
+
+	alice, bob := &rest.Config{Username: "alice"}, &rest.Config{Username: "bob"}
+	later := time.Now().Add(time.Hour)
+	if _, err := scheduler.Schedule(alice, "deployment", "default", "web", "+1", later); err != nil {
+		t.Fatalf("Expected no error when scheduling scale, but got %v.", err)
+	}
+
+	soon, err := scheduler.Schedule(alice, "deployment", "default", "api", "-1", time.Now().Add(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected no error when scheduling scale, but got %v.", err)
+	}
+
+	if list := scheduler.List(alice); len(list.Items) != 2 || list.Items[0].ID != soon.ID {
+		t.Errorf("Expected two scales of alice ordered by time, but got %v.", list.Items)
+	}
+
+	if list := scheduler.List(bob); len(list.Items) != 0 {
+		t.Errorf("Expected no scales of bob, but got %v.", list.Items)
+	}
+
+	select {
+	case result := <-done:
+		if result != "alice:api:-1" {
+			t.Errorf("Expected scale of api by alice, but got %s.", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected scheduled scale to be done.")
+	}
+
+	list := scheduler.List(alice)
+	if len(list.Items) != 1 {
+		t.Fatalf("Expected one pending scale after the other is done, but got %v.", list.Items)
+	}
+
+	if err = scheduler.Cancel(bob, list.Items[0].ID); !k8serrors.IsNotFound(err) {
+		t.Errorf("Expected scale of alice to be hidden from bob, but got %v.", err)
+	}
+
+	if err = scheduler.Cancel(alice, list.Items[0].ID); err != nil || len(scheduler.List(alice).Items) != 0 {
+		t.Errorf("Expected scale to be canceled, but got %v.", err)
+	}
+
+	cases := []struct {
+		info  string
+		name  string
+		count string
+		at    time.Time
+	}{
+		{"past", "web", "1", time.Now().Add(-time.Minute)},
+		{"too far", "web", "1", time.Now().Add(maxScheduleHorizon + time.Hour)},
+		{"invalid count", "web", "one", later},
+		{"missing resource", "missing", "1", later},
+	}
+
+	for _, c := range cases {
+		if _, err := scheduler.Schedule(alice, "deployment", "default", c.name, c.count, c.at); err == nil {
+			t.Errorf("%s: expected error when scheduling scale.", c.info)
+		}
+	}
+}
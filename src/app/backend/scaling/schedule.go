@@ -0,0 +1,189 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaling
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+const (
+	// How far in the future scale can be scheduled.
+	maxScheduleHorizon = 7 * 24 * time.Hour
+	// Maximum number of pending scheduled scales of all users.
+	maxScheduledScales = 100
+)
+
+// ScheduledScale is a one-shot scale of a resource done by the backend at given time.
+type ScheduledScale struct {
+	ID        string      `json:"id"`
+	Kind      string      `json:"kind"`
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	ScaleBy   string      `json:"scaleBy"`
+	At        metaV1.Time `json:"at"`
+}
+
+// ScheduledScaleList contains pending scheduled scales of a user.
+type ScheduledScaleList struct {
+	Items []ScheduledScale `json:"items"`
+}
+
+// Pending scale together with credentials it is done with.
+type scheduledEntry struct {
+	ScheduledScale
+	owner string
+	cfg   *rest.Config
+	timer *time.Timer
+}
+
+// Scheduler keeps scheduled scales in memory and does them with credentials of users who scheduled them. Scales are
+// lost when Dashboard restarts and each replica does only scales scheduled through it.
+type Scheduler struct {
+	mux       sync.Mutex
+	scheduled map[string]*scheduledEntry
+	scale     func(cfg *rest.Config, kind, namespace, name, count string) (*ReplicaCounts, error)
+	get       func(cfg *rest.Config, kind, namespace, name string) (*ReplicaCounts, error)
+}
+
+// Schedule schedules scale of given resource at given time. The resource has to be readable with given config
+// already, so typos are not found only when it is too late.
+func (self *Scheduler) Schedule(cfg *rest.Config, kind, namespace, name, count string,
+	at time.Time) (*ScheduledScale, error) {
+	if _, err := ParseScaleSpec(count); err != nil {
+		return nil, err
+	}
+
+	if delay := time.Until(at); delay <= 0 || delay > maxScheduleHorizon {
+		return nil, errors.NewBadRequest(fmt.Sprintf("scale can be scheduled at most %s ahead", maxScheduleHorizon))
+	}
+
+	if _, err := self.get(rest.CopyConfig(cfg), kind, namespace, name); err != nil {
+		return nil, err
+	}
+
+	id, err := newScheduleID()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &scheduledEntry{
+		ScheduledScale: ScheduledScale{
+			ID:        id,
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+			ScaleBy:   count,
+			At:        metaV1.NewTime(at),
+		},
+		owner: clientapi.GetCredentialsKey(cfg),
+		cfg:   rest.CopyConfig(cfg),
+	}
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	if len(self.scheduled) >= maxScheduledScales {
+		return nil, errors.NewBadRequest(fmt.Sprintf("at most %d scales can be scheduled", maxScheduledScales))
+	}
+
+	entry.timer = time.AfterFunc(time.Until(at), func() { self.run(id) })
+	self.scheduled[id] = entry
+	result := entry.ScheduledScale
+	return &result, nil
+}
+
+// List returns pending scales scheduled with the same credentials as given config, soonest first.
+func (self *Scheduler) List(cfg *rest.Config) *ScheduledScaleList {
+	owner := clientapi.GetCredentialsKey(cfg)
+	result := &ScheduledScaleList{Items: make([]ScheduledScale, 0)}
+
+	self.mux.Lock()
+	for _, entry := range self.scheduled {
+		if entry.owner == owner {
+			result.Items = append(result.Items, entry.ScheduledScale)
+		}
+	}
+	self.mux.Unlock()
+
+	sort.Slice(result.Items, func(i, j int) bool { return result.Items[i].At.Before(&result.Items[j].At) })
+	return result
+}
+
+// Cancel cancels pending scale with given ID. Scales scheduled by other users are reported as not found.
+func (self *Scheduler) Cancel(cfg *rest.Config, id string) error {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	entry, ok := self.scheduled[id]
+	if !ok || entry.owner != clientapi.GetCredentialsKey(cfg) {
+		return errors.NewNotFound("Scheduled scale not found.")
+	}
+
+	entry.timer.Stop()
+	delete(self.scheduled, id)
+	return nil
+}
+
+// Does scheduled scale. Current number of replicas and bounds of autoscalers are read at the time of the scale, not
+// when it has been scheduled. Nobody waits for the result, so it is only logged.
+func (self *Scheduler) run(id string) {
+	self.mux.Lock()
+	entry, ok := self.scheduled[id]
+	delete(self.scheduled, id)
+	self.mux.Unlock()
+
+	if !ok {
+		return
+	}
+
+	counts, err := self.scale(entry.cfg, entry.Kind, entry.Namespace, entry.Name, entry.ScaleBy)
+	if err != nil {
+		log.Printf("Scheduled scale of %s %s/%s by %s failed: %s", entry.Kind, entry.Namespace, entry.Name,
+			clientapi.GetIdentity(entry.cfg), err)
+		return
+	}
+
+	log.Printf("Scheduled scale of %s %s/%s by %s done, desired replicas: %d", entry.Kind, entry.Namespace,
+		entry.Name, clientapi.GetIdentity(entry.cfg), counts.DesiredReplicas)
+}
+
+func newScheduleID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// NewScheduler creates scheduler of one-shot scales.
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		scheduled: make(map[string]*scheduledEntry),
+		scale:     ScaleResource,
+		get:       GetReplicaCounts,
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package securityreview
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// Handler manages endpoints reviewing security of pod specs.
+type Handler struct {
+	cManager clientapi.ClientManager
+	sManager settingsApi.SettingsManager
+}
+
+// Install creates new endpoints for security review. Rules are configured by admins as a ruleset in the settings
+// config map. Patches of the findings can be applied with the raw resource patch endpoint.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.POST("/securityreview").
+			To(h.handleReviewManifest).
+			Reads(ReviewSpec{}).
+			Writes(Review{}))
+	ws.Route(
+		ws.GET("/securityreview/{kind}/{namespace}/{name}").
+			To(h.handleReviewResource).
+			Writes(Review{}))
+}
+
+// NewSecurityReviewHandler creates securityreview.Handler.
+func NewSecurityReviewHandler(cManager clientapi.ClientManager, sManager settingsApi.SettingsManager) *Handler {
+	return &Handler{cManager: cManager, sManager: sManager}
+}
+
+func (h *Handler) handleReviewManifest(request *restful.Request, response *restful.Response) {
+	spec := new(ReviewSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := ReviewManifest(spec.Content, h.sManager.GetSecurityRuleset(h.cManager.InsecureClient()))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (h *Handler) handleReviewResource(request *restful.Request, response *restful.Response) {
+	config, err := h.cManager.Config(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	verber, err := h.cManager.VerberClient(request, config)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	raw, err := verber.Get(request.PathParameter("kind"), true, request.PathParameter("namespace"),
+		request.PathParameter("name"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := ReviewObject(obj, h.sManager.GetSecurityRuleset(h.cManager.InsecureClient()))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
@@ -0,0 +1,116 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package securityreview
+
+import (
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Builds JSON patch of an object. Operations are applied to a copy of the object as they are added, so each of them
+// can rely on the previous ones, i.e. the first one adding missing security context of a container.
+type patchBuilder struct {
+	obj interface{}
+	ops []PatchOperation
+}
+
+// Sets value of given field. Missing parents are added together with the value, as JSON patch can not add members
+// to objects which do not exist.
+func (self *patchBuilder) set(value interface{}, fields ...string) {
+	for i := 1; i < len(fields); i++ {
+		if _, ok := lookup(self.obj, fields[:i]); ok {
+			continue
+		}
+
+		for j := len(fields) - 1; j >= i; j-- {
+			value = map[string]interface{}{fields[j]: value}
+		}
+		self.add(fields[:i], "", value)
+		return
+	}
+
+	self.add(fields, "", value)
+}
+
+// Appends value to the list of given field. The list is created if it does not exist.
+func (self *patchBuilder) append(value interface{}, fields ...string) {
+	if list, ok := lookup(self.obj, fields); ok {
+		if items, ok := list.([]interface{}); ok {
+			self.add(fields, "/-", value)
+			assign(self.obj, fields, append(items, runtime.DeepCopyJSONValue(value)))
+			return
+		}
+	}
+
+	self.set([]interface{}{value}, fields...)
+}
+
+func (self *patchBuilder) add(fields []string, suffix string, value interface{}) {
+	self.ops = append(self.ops, PatchOperation{Op: "add", Path: pointer(fields) + suffix, Value: value})
+	if len(suffix) == 0 {
+		assign(self.obj, fields, runtime.DeepCopyJSONValue(value))
+	}
+}
+
+// Returns value of given field of the object. Fields of lists are indices. Null values are treated as missing.
+func lookup(obj interface{}, fields []string) (interface{}, bool) {
+	for _, field := range fields {
+		switch typed := obj.(type) {
+		case map[string]interface{}:
+			obj = typed[field]
+		case []interface{}:
+			i, err := strconv.Atoi(field)
+			if err != nil || i < 0 || i >= len(typed) {
+				return nil, false
+			}
+			obj = typed[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return obj, obj != nil
+}
+
+// Sets value of given field of the object. Parent of the field has to exist.
+func assign(obj interface{}, fields []string, value interface{}) {
+	parent, _ := lookup(obj, fields[:len(fields)-1])
+	last := fields[len(fields)-1]
+	switch typed := parent.(type) {
+	case map[string]interface{}:
+		typed[last] = value
+	case []interface{}:
+		if i, err := strconv.Atoi(last); err == nil && i >= 0 && i < len(typed) {
+			typed[i] = value
+		}
+	}
+}
+
+// Returns JSON pointer of given field. See: https://tools.ietf.org/html/rfc6901
+func pointer(fields []string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	escaped := make([]string, len(fields))
+	for i, field := range fields {
+		escaped[i] = escaper.Replace(field)
+	}
+
+	return "/" + strings.Join(escaped, "/")
+}
+
+func newPatchBuilder(obj map[string]interface{}) *patchBuilder {
+	return &patchBuilder{obj: runtime.DeepCopyJSON(obj), ops: []PatchOperation{}}
+}
@@ -0,0 +1,223 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package securityreview
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/lint"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// User suggested for containers running as root. Nobody user exists in most images.
+const nonRootUser = int64(65534)
+
+// Weights of rules used to compute the score. Rules with higher severity have bigger impact.
+var severityWeights = map[settingsApi.SecuritySeverity]int{
+	settingsApi.SecuritySeverityLow:    1,
+	settingsApi.SecuritySeverityMedium: 2,
+	settingsApi.SecuritySeverityHigh:   3,
+}
+
+// Grades assigned to scores, best first.
+var grades = []struct {
+	minScore int
+	grade    string
+}{{90, "A"}, {80, "B"}, {70, "C"}, {60, "D"}, {0, "F"}}
+
+// ReviewSpec is a manifest of single object to review given as YAML or JSON.
+type ReviewSpec struct {
+	Content string `json:"content"`
+}
+
+// Review is the result of security review of a pod spec.
+type Review struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Score is the percentage of the weight of enabled rules, which the pod spec satisfies.
+	Score    int       `json:"score"`
+	Grade    string    `json:"grade"`
+	Findings []Finding `json:"findings"`
+	// Patch fixes all findings at once.
+	Patch []PatchOperation `json:"patch"`
+}
+
+// Finding is a single violation of a security rule.
+type Finding struct {
+	Rule      settingsApi.SecurityRule     `json:"rule"`
+	Severity  settingsApi.SecuritySeverity `json:"severity"`
+	Container string                       `json:"container,omitempty"`
+	Message   string                       `json:"message"`
+	// Patch fixes the finding. Like the patch of the whole review it is a JSON patch of the reviewed object, which
+	// can be applied with the raw resource patch endpoint.
+	Patch []PatchOperation `json:"patch"`
+}
+
+// PatchOperation is a single operation of JSON patch. See: https://tools.ietf.org/html/rfc6902
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// ReviewManifest reviews single object of YAML or JSON manifest.
+func ReviewManifest(content string, ruleset settingsApi.SecurityRuleset) (*Review, error) {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096).Decode(obj); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	return ReviewObject(obj, ruleset)
+}
+
+// ReviewObject reviews pod spec of given object. Objects of kinds, which do not run pods, can not be reviewed.
+func ReviewObject(obj *unstructured.Unstructured, ruleset settingsApi.SecurityRuleset) (*Review, error) {
+	path, ok := lint.PodSpecPath(obj.GetKind())
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("objects of kind %s do not run pods", obj.GetKind()))
+	}
+
+	content, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil || !found {
+		return nil, errors.NewBadRequest(fmt.Sprintf("%s %s has no pod spec", obj.GetKind(), obj.GetName()))
+	}
+
+	spec := new(v1.PodSpec)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, spec); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	review := &Review{Kind: obj.GetKind(), Name: obj.GetName(), Findings: []Finding{}}
+	fixAll := newPatchBuilder(obj.Object)
+	failed := make(map[settingsApi.SecurityRule]bool)
+	report := func(rule settingsApi.SecurityRule, container, message string, fix func(*patchBuilder)) {
+		severity := ruleset.Severity(rule)
+		if severity == settingsApi.SecuritySeverityOff {
+			return
+		}
+
+		fixOne := newPatchBuilder(obj.Object)
+		fix(fixOne)
+		fix(fixAll)
+		failed[rule] = true
+		review.Findings = append(review.Findings, Finding{
+			Rule:      rule,
+			Severity:  severity,
+			Container: container,
+			Message:   message,
+			Patch:     fixOne.ops,
+		})
+	}
+
+	if spec.HostNetwork {
+		report(settingsApi.SecurityRuleNoHostNetwork, "", "pod uses network namespace of the node",
+			func(b *patchBuilder) { b.set(false, field(path, "hostNetwork")...) })
+	}
+
+	for _, list := range []struct {
+		field      string
+		containers []v1.Container
+	}{{"initContainers", spec.InitContainers}, {"containers", spec.Containers}} {
+		for i, container := range list.containers {
+			securityContext := field(path, list.field, strconv.Itoa(i), "securityContext")
+			reviewContainer(spec.SecurityContext, container, securityContext, report)
+		}
+	}
+
+	review.Score = getScore(ruleset, failed)
+	for _, grade := range grades {
+		if review.Score >= grade.minScore {
+			review.Grade = grade.grade
+			break
+		}
+	}
+
+	review.Patch = fixAll.ops
+	return review, nil
+}
+
+func reviewContainer(podContext *v1.PodSecurityContext, container v1.Container, path []string,
+	report func(settingsApi.SecurityRule, string, string, func(*patchBuilder))) {
+	context := container.SecurityContext
+	if context == nil {
+		context = new(v1.SecurityContext)
+	}
+
+	// Container settings override the ones of the pod.
+	runAsUser, runAsNonRoot := context.RunAsUser, context.RunAsNonRoot
+	if podContext != nil && runAsUser == nil {
+		runAsUser = podContext.RunAsUser
+	}
+	if podContext != nil && runAsNonRoot == nil {
+		runAsNonRoot = podContext.RunAsNonRoot
+	}
+
+	if runAsUser != nil && *runAsUser == 0 {
+		report(settingsApi.SecurityRuleNonRoot, container.Name, "container runs as root user",
+			func(b *patchBuilder) {
+				b.set(nonRootUser, field(path, "runAsUser")...)
+				b.set(true, field(path, "runAsNonRoot")...)
+			})
+	} else if runAsUser == nil && (runAsNonRoot == nil || !*runAsNonRoot) {
+		report(settingsApi.SecurityRuleNonRoot, container.Name, "container may run as root user",
+			func(b *patchBuilder) { b.set(true, field(path, "runAsNonRoot")...) })
+	}
+
+	if context.ReadOnlyRootFilesystem == nil || !*context.ReadOnlyRootFilesystem {
+		report(settingsApi.SecurityRuleReadOnlyRootFilesystem, container.Name, "root filesystem is writable",
+			func(b *patchBuilder) { b.set(true, field(path, "readOnlyRootFilesystem")...) })
+	}
+
+	dropsAll := false
+	if context.Capabilities != nil {
+		for _, capability := range context.Capabilities.Drop {
+			dropsAll = dropsAll || strings.EqualFold(string(capability), "ALL")
+		}
+	}
+	if !dropsAll {
+		report(settingsApi.SecurityRuleDropAllCapabilities, container.Name, "capabilities are not dropped",
+			func(b *patchBuilder) { b.append("ALL", field(path, "capabilities", "drop")...) })
+	}
+}
+
+// Returns percentage of the weight of enabled rules, which are not failed.
+func getScore(ruleset settingsApi.SecurityRuleset, failed map[settingsApi.SecurityRule]bool) int {
+	total, passed := 0, 0
+	for rule := range settingsApi.SecurityRules {
+		weight := severityWeights[ruleset.Severity(rule)]
+		total += weight
+		if !failed[rule] {
+			passed += weight
+		}
+	}
+
+	if total == 0 {
+		return 100
+	}
+	return passed * 100 / total
+}
+
+// Returns path extended with given fields. Path is copied, so it can be extended multiple times.
+func field(path []string, fields ...string) []string {
+	return append(append([]string{}, path...), fields...)
+}
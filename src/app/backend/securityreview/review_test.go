@@ -0,0 +1,183 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package securityreview
+
+import (
+	"strings"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+const testDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      hostNetwork: true
+      initContainers:
+      - name: init
+        image: busybox:1.32
+        securityContext:
+          runAsNonRoot: true
+          readOnlyRootFilesystem: true
+          capabilities:
+            drop: ["ALL"]
+      containers:
+      - name: app
+        image: nginx:1.19
+      - name: sidecar
+        image: envoy:1.16
+        securityContext:
+          runAsUser: 0
+          readOnlyRootFilesystem: false
+          capabilities:
+            drop: ["NET_RAW"]
+`
+
+func parse(t *testing.T, content string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096).Decode(obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+// Applies add operations of JSON patch to a copy of the object.
+func applyPatch(obj *unstructured.Unstructured, patch []PatchOperation) *unstructured.Unstructured {
+	patched := runtime.DeepCopyJSON(obj.Object)
+	for _, op := range patch {
+		fields := strings.Split(strings.TrimPrefix(op.Path, "/"), "/")
+		for i, field := range fields {
+			fields[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(field)
+		}
+
+		value := runtime.DeepCopyJSONValue(op.Value)
+		if last := len(fields) - 1; fields[last] == "-" {
+			list, _ := lookup(patched, fields[:last])
+			assign(patched, fields[:last], append(list.([]interface{}), value))
+		} else {
+			assign(patched, fields, value)
+		}
+	}
+
+	return &unstructured.Unstructured{Object: patched}
+}
+
+func TestReviewManifest(t *testing.T) {
+	review, err := ReviewManifest(testDeployment, settingsApi.SecurityRuleset{})
+	if err != nil {
+		t.Fatalf("Review failed: %s", err.Error())
+	}
+
+	expected := map[settingsApi.SecurityRule]int{
+		settingsApi.SecurityRuleNoHostNetwork:          1,
+		settingsApi.SecurityRuleNonRoot:                2,
+		settingsApi.SecurityRuleReadOnlyRootFilesystem: 2,
+		settingsApi.SecurityRuleDropAllCapabilities:    2,
+	}
+	counts := make(map[settingsApi.SecurityRule]int)
+	for _, finding := range review.Findings {
+		counts[finding.Rule]++
+		if len(finding.Patch) == 0 {
+			t.Fatalf("Expected finding %s of container %s to have a patch", finding.Rule, finding.Container)
+		}
+	}
+	for rule, count := range expected {
+		if counts[rule] != count {
+			t.Fatalf("Expected %d findings of rule %s, got %d", count, rule, counts[rule])
+		}
+	}
+
+	if review.Score != 0 || review.Grade != "F" || review.Kind != "Deployment" || review.Name != "web" {
+		t.Fatalf("Expected failed review of Deployment web, got score %d, grade %s, object %s %s", review.Score,
+			review.Grade, review.Kind, review.Name)
+	}
+}
+
+func TestReviewManifest_Ruleset(t *testing.T) {
+	ruleset := settingsApi.SecurityRuleset{
+		settingsApi.SecurityRuleNoHostNetwork:          settingsApi.SecuritySeverityOff,
+		settingsApi.SecurityRuleReadOnlyRootFilesystem: settingsApi.SecuritySeverityOff,
+		settingsApi.SecurityRuleDropAllCapabilities:    settingsApi.SecuritySeverityLow,
+	}
+	content := strings.Replace(testDeployment, "drop: [\"NET_RAW\"]", "drop: [\"all\"]", 1)
+	content = strings.Replace(content, "        image: nginx:1.19\n",
+		"        image: nginx:1.19\n        securityContext:\n          capabilities:\n            drop: [\"ALL\"]\n", 1)
+
+	review, err := ReviewManifest(content, ruleset)
+	if err != nil {
+		t.Fatalf("Review failed: %s", err.Error())
+	}
+
+	// Only non-root rule of high severity fails, drop all capabilities rule of low severity passes.
+	for _, finding := range review.Findings {
+		if finding.Rule != settingsApi.SecurityRuleNonRoot || finding.Severity != settingsApi.SecuritySeverityHigh {
+			t.Fatalf("Unexpected finding %v", finding)
+		}
+	}
+
+	if review.Score != 25 || review.Grade != "F" {
+		t.Fatalf("Expected score 25 and grade F, got %d and %s", review.Score, review.Grade)
+	}
+}
+
+func TestReviewObject_Patch(t *testing.T) {
+	obj := parse(t, testDeployment)
+	review, err := ReviewObject(obj, settingsApi.SecurityRuleset{})
+	if err != nil {
+		t.Fatalf("Review failed: %s", err.Error())
+	}
+
+	// Every finding is fixed by its own patch.
+	for _, finding := range review.Findings {
+		patched, err := ReviewObject(applyPatch(obj, finding.Patch), settingsApi.SecurityRuleset{})
+		if err != nil {
+			t.Fatalf("Review of patched object failed: %s", err.Error())
+		}
+
+		if len(patched.Findings) != len(review.Findings)-1 {
+			t.Fatalf("Expected patch of %s finding of container %s to fix only the finding, got %v", finding.Rule,
+				finding.Container, patched.Findings)
+		}
+	}
+
+	// Patch of the review fixes all findings at once.
+	patched, err := ReviewObject(applyPatch(obj, review.Patch), settingsApi.SecurityRuleset{})
+	if err != nil {
+		t.Fatalf("Review of patched object failed: %s", err.Error())
+	}
+
+	if len(patched.Findings) != 0 || patched.Score != 100 || patched.Grade != "A" {
+		t.Fatalf("Expected patched object to pass review, got score %d and findings %v", patched.Score,
+			patched.Findings)
+	}
+}
+
+func TestReviewManifest_Invalid(t *testing.T) {
+	for _, content := range []string{"kind: ConfigMap\nmetadata:\n  name: config\n", "kind: Pod\n", "{"} {
+		if _, err := ReviewManifest(content, settingsApi.SecurityRuleset{}); !k8serrors.IsBadRequest(err) {
+			t.Fatalf("Expected bad request for %q, got %v", content, err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+	systembannerApi "github.com/kubernetes/dashboard/src/app/backend/systembanner/api"
+	"github.com/kubernetes/dashboard/src/app/backend/validation"
+)
+
+// AppBootstrap contains everything the frontend needs before the first page renders, so that it does not have to
+// make a separate request for each part of it.
+type AppBootstrap struct {
+	// LoginStatus tells whether the request carries credentials.
+	LoginStatus *validation.LoginStatus `json:"loginStatus"`
+
+	// AuthModes are modes of login enabled in Dashboard.
+	AuthModes []authApi.AuthenticationMode `json:"authModes"`
+
+	// Skippable tells whether the login can be skipped.
+	Skippable authApi.LoginSkippableResponse `json:"skippable"`
+
+	// Settings are global settings of Dashboard.
+	Settings settingsApi.Settings `json:"settings"`
+
+	// FeatureGates are states of all feature gates.
+	FeatureGates featuresApi.FeatureGateList `json:"featureGates"`
+
+	// SystemBanner is shown on top of all pages.
+	SystemBanner systembannerApi.SystemBanner `json:"systemBanner"`
+
+	// Identity is the user that made the request together with a summary of permissions.
+	Identity Identity `json:"identity"`
+}
+
+// Identity is the user that made a request. Permissions are checked only for users with credentials.
+type Identity struct {
+	// User that made the request.
+	User string `json:"user"`
+
+	// Groups of the user.
+	Groups []string `json:"groups"`
+
+	// Anonymous is true for requests without credentials.
+	Anonymous bool `json:"anonymous"`
+
+	// Permissions of the user, that change what the frontend shows.
+	Permissions Permissions `json:"permissions"`
+
+	// Session tells where the user lands after login.
+	Session *Bootstrap `json:"session"`
+}
+
+// Permissions is a summary of what the user is allowed to do.
+type Permissions struct {
+	// ClusterAdmin is true if the user can perform any action on any resource.
+	ClusterAdmin bool `json:"clusterAdmin"`
+
+	// ListNamespaces is true if the user can list all namespaces.
+	ListNamespaces bool `json:"listNamespaces"`
+
+	// EditSettings is true if the user can change global settings.
+	EditSettings bool `json:"editSettings"`
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+	"github.com/kubernetes/dashboard/src/app/backend/tenancy"
+)
+
+// Bootstrap is read by the frontend after login, so that users land in the right namespace.
+type Bootstrap struct {
+	// User that is logged in.
+	User string `json:"user"`
+
+	// Namespace that should be opened after login. Empty if no namespace is visible to the user.
+	Namespace string `json:"namespace"`
+
+	// Recently used namespaces visible to the user, the most recent first.
+	RecentNamespaces []string `json:"recentNamespaces"`
+}
+
+// GetBootstrap returns bootstrap of given subject. The most recently used namespace is preferred, then the default
+// namespace of the first group of the subject that has one and then the default namespace of the cluster. Only
+// namespaces accepted by visible are returned.
+func GetBootstrap(subject tenancy.Subject, recent []string, defaults []settingsApi.DefaultNamespace,
+	visible func(string) bool) *Bootstrap {
+	bootstrap := &Bootstrap{User: subject.User, RecentNamespaces: make([]string, 0)}
+	for _, namespace := range recent {
+		if visible(namespace) {
+			bootstrap.RecentNamespaces = append(bootstrap.RecentNamespaces, namespace)
+		}
+	}
+
+	if len(bootstrap.RecentNamespaces) > 0 {
+		bootstrap.Namespace = bootstrap.RecentNamespaces[0]
+		return bootstrap
+	}
+
+	for _, d := range defaults {
+		if containsString(subject.Groups, d.Group) && visible(d.Namespace) {
+			bootstrap.Namespace = d.Namespace
+			return bootstrap
+		}
+	}
+
+	if visible(v1.NamespaceDefault) {
+		bootstrap.Namespace = v1.NamespaceDefault
+	}
+	return bootstrap
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
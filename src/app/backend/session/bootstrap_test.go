@@ -0,0 +1,74 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"reflect"
+	"testing"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+	"github.com/kubernetes/dashboard/src/app/backend/tenancy"
+)
+
+func TestGetBootstrap(t *testing.T) {
+	all := func(string) bool { return true }
+	teamOnly := func(namespace string) bool { return namespace == "team-a" || namespace == "team-b" }
+	defaults := []settingsApi.DefaultNamespace{
+		{Group: "admins", Namespace: "kube-system"},
+		{Group: "team-a", Namespace: "team-a"},
+	}
+	alice := tenancy.Subject{User: "alice", Groups: []string{"team-a", tenancy.AuthenticatedGroup}}
+
+	cases := []struct {
+		info     string
+		subject  tenancy.Subject
+		recent   []string
+		visible  func(string) bool
+		expected *Bootstrap
+	}{
+		{
+			"most recent namespace is preferred",
+			alice, []string{"team-b", "default"}, all,
+			&Bootstrap{User: "alice", Namespace: "team-b", RecentNamespaces: []string{"team-b", "default"}},
+		},
+		{
+			"default namespace of group",
+			alice, nil, all,
+			&Bootstrap{User: "alice", Namespace: "team-a", RecentNamespaces: []string{}},
+		},
+		{
+			"default namespace of cluster",
+			tenancy.Subject{User: "bob", Groups: []string{tenancy.AuthenticatedGroup}}, nil, all,
+			&Bootstrap{User: "bob", Namespace: "default", RecentNamespaces: []string{}},
+		},
+		{
+			"invisible namespaces are skipped",
+			alice, []string{"default", "team-b"}, teamOnly,
+			&Bootstrap{User: "alice", Namespace: "team-b", RecentNamespaces: []string{"team-b"}},
+		},
+		{
+			"no visible namespace",
+			tenancy.Subject{User: "bob", Groups: []string{"admins"}}, []string{"default"}, teamOnly,
+			&Bootstrap{User: "bob", RecentNamespaces: []string{}},
+		},
+	}
+
+	for _, c := range cases {
+		actual := GetBootstrap(c.subject, c.recent, defaults, c.visible)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: GetBootstrap() == %#v, expected %#v", c.info, actual, c.expected)
+		}
+	}
+}
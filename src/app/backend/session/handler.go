@@ -0,0 +1,175 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/auth"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/clientstate"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+	"github.com/kubernetes/dashboard/src/app/backend/systembanner"
+	"github.com/kubernetes/dashboard/src/app/backend/tenancy"
+)
+
+// Handler manages endpoints related to the session of the logged in user.
+type Handler struct {
+	cManager    clientapi.ClientManager
+	sManager    settingsApi.SettingsManager
+	authManager authApi.AuthManager
+	fManager    featuresApi.FeatureGateManager
+	sbManager   *systembanner.SystemBannerManager
+	tManager    *tenancy.Manager
+}
+
+// Install creates new endpoints for sessions. Recently used namespaces are kept with the client state of the user,
+// default namespaces of groups are defined by admins in the settings config map. The bootstrap endpoint gathers
+// everything the frontend needs before the first page renders and works without login.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/bootstrap").
+			To(h.handleGetAppBootstrap).
+			Writes(AppBootstrap{}))
+	ws.Route(
+		ws.GET("/session/bootstrap").
+			To(h.handleGetBootstrap).
+			Writes(Bootstrap{}))
+	ws.Route(
+		ws.POST("/session/recentnamespace/{namespace}").
+			To(h.handleAddRecentNamespace).
+			Writes(Bootstrap{}))
+}
+
+// NewSessionHandler creates session.Handler.
+func NewSessionHandler(cManager clientapi.ClientManager, sManager settingsApi.SettingsManager,
+	authManager authApi.AuthManager, fManager featuresApi.FeatureGateManager,
+	sbManager *systembanner.SystemBannerManager) *Handler {
+	return &Handler{cManager: cManager, sManager: sManager, authManager: authManager, fManager: fManager,
+		sbManager: sbManager, tManager: tenancy.NewTenancyManager(cManager, sManager)}
+}
+
+func (h *Handler) handleGetAppBootstrap(request *restful.Request, response *restful.Response) {
+	recent, err := h.getRecentNamespaces(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	subject, err := h.tManager.Subject(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result := AppBootstrap{
+		LoginStatus:  auth.GetLoginStatus(h.cManager, request),
+		AuthModes:    h.authManager.AuthenticationModes(),
+		Skippable:    auth.GetLoginSkippable(h.authManager, h.cManager, h.fManager),
+		Settings:     h.sManager.GetGlobalSettings(h.cManager.InsecureClient()),
+		FeatureGates: h.fManager.List(),
+		SystemBanner: h.sbManager.Get(),
+		Identity: Identity{
+			User:      subject.User,
+			Groups:    subject.Groups,
+			Anonymous: subject.User == tenancy.AnonymousUser,
+			Session:   h.getBootstrap(request, subject, recent),
+		},
+	}
+
+	// Every check is a request to the API server, anonymous users are denied anyway.
+	if !result.Identity.Anonymous {
+		result.Identity.Permissions = h.getPermissions(request)
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (h *Handler) getPermissions(request *restful.Request) Permissions {
+	return Permissions{
+		ClusterAdmin: h.cManager.CanI(request, clientapi.ToClusterAdminAccessReview()),
+		ListNamespaces: h.cManager.CanI(request,
+			clientapi.ToSelfSubjectAccessReview("", "", api.ResourceKindNamespace, "list")),
+		EditSettings: settings.CanI(h.cManager, request, http.MethodPut),
+	}
+}
+
+func (h *Handler) handleGetBootstrap(request *restful.Request, response *restful.Response) {
+	recent, err := h.getRecentNamespaces(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	h.writeBootstrap(request, response, recent)
+}
+
+// getRecentNamespaces returns namespaces recently used by the user. Users who have skipped the login have no recent
+// namespaces, but still get defaults of their groups.
+func (h *Handler) getRecentNamespaces(request *restful.Request) ([]string, error) {
+	owner, err := clientstate.GetOwner(h.cManager, request)
+	if errors.IsUnauthorized(err) {
+		return make([]string, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return clientstate.GetRecentNamespaces(h.cManager.InsecureClient(), h.authManager, owner)
+}
+
+func (h *Handler) handleAddRecentNamespace(request *restful.Request, response *restful.Response) {
+	owner, err := clientstate.GetOwner(h.cManager, request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	recent, err := clientstate.AddRecentNamespace(h.cManager.InsecureClient(), h.authManager, owner,
+		request.PathParameter("namespace"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	h.writeBootstrap(request, response, recent)
+}
+
+func (h *Handler) writeBootstrap(request *restful.Request, response *restful.Response, recent []string) {
+	subject, err := h.tManager.Subject(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, h.getBootstrap(request, subject, recent))
+}
+
+func (h *Handler) getBootstrap(request *restful.Request, subject tenancy.Subject, recent []string) *Bootstrap {
+	visible := func(string) bool { return true }
+	if scope := tenancy.GetScope(request); scope != nil {
+		visible = scope.Allows
+	}
+
+	defaults := h.sManager.GetDefaultNamespaces(h.cManager.InsecureClient())
+	return GetBootstrap(subject, recent, defaults, visible)
+}
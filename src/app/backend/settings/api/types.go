@@ -15,10 +15,17 @@
 package api
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -38,6 +45,39 @@ const (
 	// PinnedResourcesKey is a settings map key which maps to current pinned resources.
 	PinnedResourcesKey = "_pinnedCRD"
 
+	// QuickActionsKey is a settings map key which maps to admin-defined quick actions.
+	QuickActionsKey = "_quickActions"
+
+	// BrandingKey is a settings map key which maps to branding of the dashboard instance.
+	BrandingKey = "_branding"
+
+	// FeatureGatesKey is a settings map key which maps to runtime overrides of feature gates.
+	FeatureGatesKey = "_featureGates"
+
+	// DeployPresetsKey is a settings map key which maps to per-namespace defaults of the deploy form.
+	DeployPresetsKey = "_deployPresets"
+
+	// LintPolicyKey is a settings map key which maps to severities of the lint rules applied on submit.
+	LintPolicyKey = "_lintPolicy"
+
+	// SecurityRulesetKey is a settings map key which maps to severities of the rules applied by security review.
+	SecurityRulesetKey = "_securityRuleset"
+
+	// LogLevelTemplatesKey is a settings map key which maps to conventions of changing log level of applications.
+	LogLevelTemplatesKey = "_logLevelTemplates"
+
+	// TenantsKey is a settings map key which maps to tenants and namespaces visible to their members.
+	TenantsKey = "_tenants"
+
+	// SecretRedactionPolicyKey is a settings map key which maps to rules selecting secrets with redacted values.
+	SecretRedactionPolicyKey = "_secretRedactionPolicy"
+
+	// DefaultNamespacesKey is a settings map key which maps to namespaces where members of groups land after login.
+	DefaultNamespacesKey = "_defaultNamespaces"
+
+	// DeployPresetWildcard is a deploy presets key used for namespaces without their own preset.
+	DeployPresetWildcard = "*"
+
 	// ConcurrentSettingsChangeError occurs during settings save if settings were modified concurrently.
 	// Keep it in sync with CONCURRENT_CHANGE_ERROR constant from the frontend.
 	ConcurrentSettingsChangeError = "settings changed since last reload"
@@ -61,6 +101,28 @@ type SettingsManager interface {
 	SavePinnedResource(client kubernetes.Interface, r *PinnedResource) error
 	// DeletePinnedResource removes a pinned resource from config map.
 	DeletePinnedResource(client kubernetes.Interface, r *PinnedResource) error
+	// GetQuickActions gets the admin-defined quick actions from config map.
+	GetQuickActions(client kubernetes.Interface) (a []QuickAction)
+	// GetBranding gets the branding from config map, or default branding if it is not set.
+	GetBranding(client kubernetes.Interface) (b Branding)
+	// SaveBranding validates provided branding and saves it in config map.
+	SaveBranding(client kubernetes.Interface, b *Branding) error
+	// GetFeatureGates gets the feature gate overrides from config map.
+	GetFeatureGates(client kubernetes.Interface) (g map[string]bool)
+	// GetDeployPresets gets the per-namespace deploy form defaults from config map.
+	GetDeployPresets(client kubernetes.Interface) (p DeployPresets)
+	// GetLintPolicy gets the severities of lint rules applied to submitted objects from config map.
+	GetLintPolicy(client kubernetes.Interface) (p LintPolicy)
+	// GetSecurityRuleset gets the severities of rules applied by security review of pod specs from config map.
+	GetSecurityRuleset(client kubernetes.Interface) (r SecurityRuleset)
+	// GetLogLevelTemplates gets the conventions of changing log level of applications from config map.
+	GetLogLevelTemplates(client kubernetes.Interface) (t []LogLevelTemplate)
+	// GetTenants gets the tenants and namespaces visible to their members from config map.
+	GetTenants(client kubernetes.Interface) (t []Tenant)
+	// GetSecretRedactionPolicy gets the rules selecting secrets, which values are redacted, from config map.
+	GetSecretRedactionPolicy(client kubernetes.Interface) (p SecretRedactionPolicy)
+	// GetDefaultNamespaces gets the namespaces where members of groups land after login from config map.
+	GetDefaultNamespaces(client kubernetes.Interface) (n []DefaultNamespace)
 }
 
 // PinnedResource represents a pinned resource.
@@ -89,6 +151,584 @@ func UnmarshalPinnedResources(data string) (*[]PinnedResource, error) {
 	return p, err
 }
 
+// QuickActionType is a type of the operation performed by a quick action.
+type QuickActionType string
+
+const (
+	// QuickActionAnnotate sets an annotation on the resource.
+	QuickActionAnnotate QuickActionType = "annotate"
+	// QuickActionLabel sets a label on the resource.
+	QuickActionLabel QuickActionType = "label"
+	// QuickActionScale sets the replica count of the resource.
+	QuickActionScale QuickActionType = "scale"
+	// QuickActionWebhook sends the resource reference to an external URL.
+	QuickActionWebhook QuickActionType = "webhook"
+)
+
+// QuickAction represents an admin-defined action that can be executed on resources of matching kinds.
+// Key, Value and URL can reference parameters using the ${param} syntax. Parameters "kind", "namespace"
+// and "name" are always available, other ones have to be listed in Parameters and provided by the user.
+type QuickAction struct {
+	Name        string          `json:"name"`
+	DisplayName string          `json:"displayName"`
+	Kinds       []string        `json:"kinds"`
+	Type        QuickActionType `json:"type"`
+	Key         string          `json:"key,omitempty"`
+	Value       string          `json:"value,omitempty"`
+	URL         string          `json:"url,omitempty"`
+	Parameters  []string        `json:"parameters,omitempty"`
+}
+
+// MatchesKind returns true if action can be executed on resources of given kind.
+func (q *QuickAction) MatchesKind(kind string) bool {
+	for _, k := range q.Kinds {
+		if k == "*" || strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarshalQuickActions marshals quick actions into JSON object.
+func MarshalQuickActions(a []QuickAction) string {
+	bytes, _ := json.Marshal(a)
+	return string(bytes)
+}
+
+// UnmarshalQuickActions unmarshal quick actions into object.
+func UnmarshalQuickActions(data string) (*[]QuickAction, error) {
+	a := new([]QuickAction)
+	err := json.Unmarshal([]byte(data), a)
+	return a, err
+}
+
+// UnmarshalFeatureGates unmarshal feature gate overrides into object.
+func UnmarshalFeatureGates(data string) (map[string]bool, error) {
+	g := make(map[string]bool)
+	err := json.Unmarshal([]byte(data), &g)
+	return g, err
+}
+
+// HelpLink is a custom link shown in the help menu and on the login page.
+type HelpLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Branding allows to white-label the dashboard instance. Logo can be either referenced by URL or embedded
+// as a base64 encoded data URI.
+type Branding struct {
+	ProductName  string     `json:"productName"`
+	LogoURL      string     `json:"logoUrl,omitempty"`
+	LogoData     string     `json:"logoData,omitempty"`
+	PrimaryColor string     `json:"primaryColor,omitempty"`
+	AccentColor  string     `json:"accentColor,omitempty"`
+	HelpLinks    []HelpLink `json:"helpLinks"`
+}
+
+// MaxLogoDataSize is the maximal size of embedded logo. Settings are stored in a config map, which size is
+// limited to 1MiB.
+const MaxLogoDataSize = 256 * 1024
+
+var (
+	colorRegexp    = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	logoDataRegexp = regexp.MustCompile(`^data:image/(png|jpeg|gif|svg\+xml);base64,`)
+)
+
+// Marshal branding into JSON object.
+func (b Branding) Marshal() string {
+	bytes, _ := json.Marshal(b)
+	return string(bytes)
+}
+
+// Validate checks that branding can be safely applied by the frontend. Colors have to be hex colors and
+// links have to use one of the http(s) schemes, so they can not be used to inject scripts or styles.
+func (b Branding) Validate() error {
+	for _, color := range []string{b.PrimaryColor, b.AccentColor} {
+		if len(color) > 0 && !colorRegexp.MatchString(color) {
+			return fmt.Errorf("invalid color %q, expected #rgb or #rrggbb", color)
+		}
+	}
+
+	if len(b.LogoURL) > 0 && !isSafeURL(b.LogoURL) {
+		return fmt.Errorf("invalid logo URL %q", b.LogoURL)
+	}
+
+	if len(b.LogoData) > 0 {
+		if len(b.LogoData) > MaxLogoDataSize {
+			return fmt.Errorf("logo data exceeds %d bytes", MaxLogoDataSize)
+		}
+		prefix := logoDataRegexp.FindString(b.LogoData)
+		if len(prefix) == 0 {
+			return fmt.Errorf("invalid logo data, expected base64 encoded image data URI")
+		}
+		if _, err := base64.StdEncoding.DecodeString(b.LogoData[len(prefix):]); err != nil {
+			return fmt.Errorf("invalid logo data: %s", err.Error())
+		}
+	}
+
+	for _, link := range b.HelpLinks {
+		if len(link.Title) == 0 || !isSafeURL(link.URL) {
+			return fmt.Errorf("invalid help link %q: %q", link.Title, link.URL)
+		}
+	}
+
+	return nil
+}
+
+// isSafeURL returns true for absolute http(s) URLs and paths relative to the dashboard.
+func isSafeURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	if len(u.Scheme) == 0 {
+		return len(u.Host) == 0 && strings.HasPrefix(u.Path, "/")
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && len(u.Host) > 0
+}
+
+// UnmarshalBranding unmarshal branding into object.
+func UnmarshalBranding(data string) (*Branding, error) {
+	b := new(Branding)
+	err := json.Unmarshal([]byte(data), b)
+	return b, err
+}
+
+// DeployPreset contains defaults applied to applications created using the deploy form. Values set
+// explicitly in the form take precedence over the preset.
+type DeployPreset struct {
+	Requests         corev1.ResourceList `json:"requests,omitempty"`
+	Limits           corev1.ResourceList `json:"limits,omitempty"`
+	Labels           map[string]string   `json:"labels,omitempty"`
+	Annotations      map[string]string   `json:"annotations,omitempty"`
+	ImagePullSecrets []string            `json:"imagePullSecrets,omitempty"`
+	NodeSelector     map[string]string   `json:"nodeSelector,omitempty"`
+}
+
+// DeployPresets maps namespace names to their deploy presets.
+type DeployPresets map[string]DeployPreset
+
+// Get returns preset of given namespace, falling back to the wildcard preset. Nil is returned
+// if neither of them is configured.
+func (p DeployPresets) Get(namespace string) *DeployPreset {
+	if preset, ok := p[namespace]; ok {
+		return &preset
+	}
+
+	if preset, ok := p[DeployPresetWildcard]; ok {
+		return &preset
+	}
+
+	return nil
+}
+
+// UnmarshalDeployPresets unmarshal deploy presets into object.
+func UnmarshalDeployPresets(data string) (DeployPresets, error) {
+	p := make(DeployPresets)
+	err := json.Unmarshal([]byte(data), &p)
+	return p, err
+}
+
+// LintRule is a name of the check applied to objects created or updated through the dashboard.
+type LintRule string
+
+const (
+	// LintRuleMissingProbes reports long-running containers without liveness or readiness probe.
+	LintRuleMissingProbes LintRule = "missingProbes"
+	// LintRuleMissingRequests reports containers without CPU or memory request.
+	LintRuleMissingRequests LintRule = "missingRequests"
+	// LintRuleLatestTag reports images without tag or using the latest tag.
+	LintRuleLatestTag LintRule = "latestTag"
+	// LintRulePrivileged reports privileged containers.
+	LintRulePrivileged LintRule = "privileged"
+	// LintRuleHostPath reports hostPath volumes.
+	LintRuleHostPath LintRule = "hostPath"
+)
+
+// LintRules contains all known lint rules.
+var LintRules = []LintRule{
+	LintRuleMissingProbes,
+	LintRuleMissingRequests,
+	LintRuleLatestTag,
+	LintRulePrivileged,
+	LintRuleHostPath,
+}
+
+// LintSeverity decides what happens with objects violating a lint rule.
+type LintSeverity string
+
+const (
+	// LintSeverityOff disables the rule.
+	LintSeverityOff LintSeverity = "off"
+	// LintSeverityWarning reports violations, but the object is still applied.
+	LintSeverityWarning LintSeverity = "warning"
+	// LintSeverityError rejects objects violating the rule.
+	LintSeverityError LintSeverity = "error"
+)
+
+// LintPolicy maps lint rules to their severities. Rules that are not listed are reported as warnings.
+type LintPolicy map[LintRule]LintSeverity
+
+// Severity returns severity of given rule.
+func (p LintPolicy) Severity(rule LintRule) LintSeverity {
+	if severity, ok := p[rule]; ok {
+		return severity
+	}
+
+	return LintSeverityWarning
+}
+
+// Validate checks that policy references only known rules and severities.
+func (p LintPolicy) Validate() error {
+	for rule, severity := range p {
+		known := false
+		for _, r := range LintRules {
+			known = known || r == rule
+		}
+		if !known {
+			return fmt.Errorf("unknown lint rule %s", rule)
+		}
+
+		switch severity {
+		case LintSeverityOff, LintSeverityWarning, LintSeverityError:
+		default:
+			return fmt.Errorf("invalid severity %s of lint rule %s", severity, rule)
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalLintPolicy unmarshal lint policy into object.
+func UnmarshalLintPolicy(data string) (LintPolicy, error) {
+	p := make(LintPolicy)
+	err := json.Unmarshal([]byte(data), &p)
+	return p, err
+}
+
+// SecurityRule is a name of the check applied by security review of pod specs.
+type SecurityRule string
+
+const (
+	// SecurityRuleNonRoot reports containers that may run as root.
+	SecurityRuleNonRoot SecurityRule = "nonRoot"
+	// SecurityRuleReadOnlyRootFilesystem reports containers with writable root filesystem.
+	SecurityRuleReadOnlyRootFilesystem SecurityRule = "readOnlyRootFilesystem"
+	// SecurityRuleDropAllCapabilities reports containers that do not drop all capabilities.
+	SecurityRuleDropAllCapabilities SecurityRule = "dropAllCapabilities"
+	// SecurityRuleNoHostNetwork reports pods using network namespace of the node.
+	SecurityRuleNoHostNetwork SecurityRule = "noHostNetwork"
+)
+
+// SecuritySeverity grades violations of a security rule. Rules with higher severity have bigger impact on the score.
+type SecuritySeverity string
+
+const (
+	// SecuritySeverityOff disables the rule.
+	SecuritySeverityOff    SecuritySeverity = "off"
+	SecuritySeverityLow    SecuritySeverity = "low"
+	SecuritySeverityMedium SecuritySeverity = "medium"
+	SecuritySeverityHigh   SecuritySeverity = "high"
+)
+
+// SecurityRules maps all known security rules to their default severities.
+var SecurityRules = map[SecurityRule]SecuritySeverity{
+	SecurityRuleNonRoot:                SecuritySeverityHigh,
+	SecurityRuleReadOnlyRootFilesystem: SecuritySeverityMedium,
+	SecurityRuleDropAllCapabilities:    SecuritySeverityMedium,
+	SecurityRuleNoHostNetwork:          SecuritySeverityHigh,
+}
+
+// SecurityRuleset maps security rules to their severities. Rules that are not listed have their default severity.
+type SecurityRuleset map[SecurityRule]SecuritySeverity
+
+// Severity returns severity of given rule.
+func (r SecurityRuleset) Severity(rule SecurityRule) SecuritySeverity {
+	if severity, ok := r[rule]; ok {
+		return severity
+	}
+
+	return SecurityRules[rule]
+}
+
+// Validate checks that ruleset references only known rules and severities.
+func (r SecurityRuleset) Validate() error {
+	for rule, severity := range r {
+		if _, ok := SecurityRules[rule]; !ok {
+			return fmt.Errorf("unknown security rule %s", rule)
+		}
+
+		switch severity {
+		case SecuritySeverityOff, SecuritySeverityLow, SecuritySeverityMedium, SecuritySeverityHigh:
+		default:
+			return fmt.Errorf("invalid severity %s of security rule %s", severity, rule)
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalSecurityRuleset unmarshal security ruleset into object.
+func UnmarshalSecurityRuleset(data string) (SecurityRuleset, error) {
+	r := make(SecurityRuleset)
+	err := json.Unmarshal([]byte(data), &r)
+	return r, err
+}
+
+// LogLevelType is a way in which application reads its log level.
+type LogLevelType string
+
+const (
+	// LogLevelEnv sets an environment variable of pod template containers. Pods are replaced by a rollout.
+	LogLevelEnv LogLevelType = "env"
+	// LogLevelAnnotation sets an annotation of running pods, that application reads through downward API
+	// volume without a restart.
+	LogLevelAnnotation LogLevelType = "annotation"
+)
+
+// LogLevelTemplate describes a convention of changing log level of applications at runtime. It applies to
+// workloads, which pod template has all labels of the selector.
+type LogLevelTemplate struct {
+	Name     string            `json:"name"`
+	Selector map[string]string `json:"selector,omitempty"`
+	Type     LogLevelType      `json:"type"`
+	// Key is a name of the environment variable or the annotation.
+	Key string `json:"key"`
+	// Container limits environment variable to a single container. All containers are updated by default.
+	Container string   `json:"container,omitempty"`
+	Levels    []string `json:"levels"`
+	// Restart sets the annotation also on the pod template, so pods are restarted with the new level and pods
+	// created later start with it.
+	Restart bool `json:"restart,omitempty"`
+}
+
+// Matches returns true if template applies to pods with given labels.
+func (t *LogLevelTemplate) Matches(labels map[string]string) bool {
+	for key, value := range t.Selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks that template can be applied.
+func (t *LogLevelTemplate) Validate() error {
+	if len(t.Name) == 0 || len(t.Key) == 0 {
+		return fmt.Errorf("log level template name and key cannot be empty")
+	}
+	if t.Type != LogLevelEnv && t.Type != LogLevelAnnotation {
+		return fmt.Errorf("invalid type %s of log level template %s", t.Type, t.Name)
+	}
+	if len(t.Levels) == 0 {
+		return fmt.Errorf("log level template %s has no levels", t.Name)
+	}
+	return nil
+}
+
+// UnmarshalLogLevelTemplates unmarshal log level templates into object.
+func UnmarshalLogLevelTemplates(data string) ([]LogLevelTemplate, error) {
+	t := make([]LogLevelTemplate, 0)
+	err := json.Unmarshal([]byte(data), &t)
+	return t, err
+}
+
+// TenantWildcard is a tenant namespace pattern matching all namespaces.
+const TenantWildcard = "*"
+
+// Tenant maps users and groups to namespaces, that are visible to them when tenancy is enabled. Namespaces are
+// shell patterns, i.e. "team-a-*".
+type Tenant struct {
+	Name       string   `json:"name"`
+	Users      []string `json:"users,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// Includes returns true if given user or any of given groups is a member of the tenant.
+func (t *Tenant) Includes(user string, groups []string) bool {
+	for _, u := range t.Users {
+		if u == user {
+			return true
+		}
+	}
+
+	for _, g := range t.Groups {
+		for _, group := range groups {
+			if g == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate checks that tenant has members and valid namespace patterns.
+func (t *Tenant) Validate() error {
+	if len(t.Name) == 0 {
+		return fmt.Errorf("tenant name cannot be empty")
+	}
+	if len(t.Users) == 0 && len(t.Groups) == 0 {
+		return fmt.Errorf("tenant %s has no users nor groups", t.Name)
+	}
+	for _, namespace := range t.Namespaces {
+		if _, err := path.Match(namespace, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %s of tenant %s", namespace, t.Name)
+		}
+	}
+	return nil
+}
+
+// UnmarshalTenants unmarshal tenants into object. Invalid tenants are rejected, so that no one gets access
+// through a typo.
+func UnmarshalTenants(data string) ([]Tenant, error) {
+	t := make([]Tenant, 0)
+	if err := json.Unmarshal([]byte(data), &t); err != nil {
+		return t, err
+	}
+
+	for i := range t {
+		if err := t[i].Validate(); err != nil {
+			return make([]Tenant, 0), err
+		}
+	}
+	return t, nil
+}
+
+// SecretRedactionRule selects secrets, which values are redacted in secret details until users reveal them. Secret
+// has to match all non-empty fields of the rule. Namespaces are shell patterns and annotations with empty value
+// match any value.
+type SecretRedactionRule struct {
+	Namespaces  []string          `json:"namespaces,omitempty"`
+	Types       []string          `json:"types,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Matches returns true if secret in given namespace, of given type and with given annotations matches the rule.
+func (r *SecretRedactionRule) Matches(namespace, secretType string, annotations map[string]string) bool {
+	if len(r.Namespaces) > 0 && !matchesAny(r.Namespaces, namespace) {
+		return false
+	}
+
+	if len(r.Types) > 0 && !containsString(r.Types, secretType) {
+		return false
+	}
+
+	for key, value := range r.Annotations {
+		actual, ok := annotations[key]
+		if !ok || (len(value) > 0 && actual != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks that namespace patterns of the rule are valid.
+func (r *SecretRedactionRule) Validate() error {
+	for _, namespace := range r.Namespaces {
+		if _, err := path.Match(namespace, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %s of secret redaction rule", namespace)
+		}
+	}
+	return nil
+}
+
+// SecretRedactionPolicy is a list of rules selecting secrets with redacted values. Values of other secrets are shown.
+type SecretRedactionPolicy []SecretRedactionRule
+
+// Redacts returns true if values of secret in given namespace, of given type and with given annotations are
+// redacted.
+func (p SecretRedactionPolicy) Redacts(namespace, secretType string, annotations map[string]string) bool {
+	for i := range p {
+		if p[i].Matches(namespace, secretType, annotations) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalSecretRedactionPolicy unmarshal secret redaction policy into object. Policy with an invalid rule redacts
+// values of all secrets, so that no values are shown through a typo.
+func UnmarshalSecretRedactionPolicy(data string) (SecretRedactionPolicy, error) {
+	p := make(SecretRedactionPolicy, 0)
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		return SecretRedactionPolicy{{}}, err
+	}
+
+	for i := range p {
+		if err := p[i].Validate(); err != nil {
+			return SecretRedactionPolicy{{}}, err
+		}
+	}
+	return p, nil
+}
+
+// DefaultNamespace is a namespace where members of the group land after login, unless they have used another one
+// recently.
+type DefaultNamespace struct {
+	Group     string `json:"group"`
+	Namespace string `json:"namespace"`
+}
+
+// Validate checks that default namespace has a group and a valid namespace name.
+func (n *DefaultNamespace) Validate() error {
+	if len(n.Group) == 0 {
+		return fmt.Errorf("group of default namespace %s cannot be empty", n.Namespace)
+	}
+	if errs := validation.IsDNS1123Label(n.Namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid default namespace %s of group %s: %s", n.Namespace, n.Group,
+			strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// UnmarshalDefaultNamespaces unmarshal default namespaces into object. All of them are rejected if any is invalid.
+func UnmarshalDefaultNamespaces(data string) ([]DefaultNamespace, error) {
+	n := make([]DefaultNamespace, 0)
+	if err := json.Unmarshal([]byte(data), &n); err != nil {
+		return n, err
+	}
+
+	for i := range n {
+		if err := n[i].Validate(); err != nil {
+			return make([]DefaultNamespace, 0), err
+		}
+	}
+	return n, nil
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultBranding is used when branding is not configured.
+var defaultBranding = Branding{
+	ProductName: "Kubernetes Dashboard",
+	HelpLinks:   []HelpLink{},
+}
+
+// GetDefaultBranding returns branding, that should be used if it is not configured.
+func GetDefaultBranding() Branding {
+	return defaultBranding
+}
+
 // Settings is a single instance of settings without context.
 type Settings struct {
 	ClusterName                      string `json:"clusterName"`
@@ -46,6 +46,21 @@ func (self *SettingsHandler) Install(ws *restful.WebService) {
 			Reads(api.Settings{}).
 			Writes(api.Settings{}))
 
+	ws.Route(
+		ws.GET("/settings/branding").
+			To(self.handleSettingsGetBranding).
+			Writes(api.Branding{}))
+	ws.Route(
+		ws.PUT("/settings/branding").
+			To(self.handleSettingsSaveBranding).
+			Reads(api.Branding{}).
+			Writes(api.Branding{}))
+
+	ws.Route(
+		ws.GET("/settings/deploypreset/{namespace}").
+			To(self.handleSettingsGetDeployPreset).
+			Writes(api.DeployPreset{}))
+
 	ws.Route(
 		ws.GET("/settings/pinner").
 			To(self.handleSettingsGetPinned))
@@ -69,18 +84,22 @@ func (self *SettingsHandler) handleSettingsGlobalCanI(request *restful.Request,
 		verb = http.MethodGet
 	}
 
-	canI := self.clientManager.CanI(request, clientapi.ToSelfSubjectAccessReview(
+	response.WriteHeaderAndEntity(http.StatusOK,
+		clientapi.CanIResponse{Allowed: CanI(self.clientManager, request, verb)})
+}
+
+// CanI tells whether the user that made given request is allowed to perform given verb on settings.
+func CanI(clientManager clientapi.ClientManager, request *restful.Request, verb string) bool {
+	if args.Holder.GetDisableSettingsAuthorizer() {
+		return true
+	}
+
+	return clientManager.CanI(request, clientapi.ToSelfSubjectAccessReview(
 		args.Holder.GetNamespace(),
 		api.SettingsConfigMapName,
 		api.ConfigMapKindName,
 		verb,
 	))
-
-	if args.Holder.GetDisableSettingsAuthorizer() {
-		canI = true
-	}
-
-	response.WriteHeaderAndEntity(http.StatusOK, clientapi.CanIResponse{Allowed: canI})
 }
 
 func (self *SettingsHandler) handleSettingsGlobalGet(request *restful.Request, response *restful.Response) {
@@ -109,6 +128,41 @@ func (self *SettingsHandler) handleSettingsGlobalSave(request *restful.Request,
 	response.WriteHeaderAndEntity(http.StatusCreated, settings)
 }
 
+func (self *SettingsHandler) handleSettingsGetBranding(request *restful.Request, response *restful.Response) {
+	client := self.clientManager.InsecureClient()
+	result := self.manager.GetBranding(client)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (self *SettingsHandler) handleSettingsSaveBranding(request *restful.Request, response *restful.Response) {
+	branding := new(api.Branding)
+	if err := request.ReadEntity(branding); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	client, err := self.clientManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if err := self.manager.SaveBranding(client, branding); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, branding)
+}
+
+func (self *SettingsHandler) handleSettingsGetDeployPreset(request *restful.Request, response *restful.Response) {
+	client := self.clientManager.InsecureClient()
+	result := self.manager.GetDeployPresets(client).Get(request.PathParameter("namespace"))
+	if result == nil {
+		result = &api.DeployPreset{}
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (self *SettingsHandler) handleSettingsGetPinned(request *restful.Request, response *restful.Response) {
 	client := self.clientManager.InsecureClient()
 	result := self.manager.GetPinnedResources(client)
@@ -34,6 +34,16 @@ import (
 type SettingsManager struct {
 	settings        map[string]api.Settings
 	pinnedResources []api.PinnedResource
+	quickActions    []api.QuickAction
+	branding        *api.Branding
+	featureGates    map[string]bool
+	deployPresets   api.DeployPresets
+	lintPolicy      api.LintPolicy
+	securityRuleset api.SecurityRuleset
+	logLevels       []api.LogLevelTemplate
+	tenants         []api.Tenant
+	redaction       api.SecretRedactionPolicy
+	defaultNs       []api.DefaultNamespace
 	rawSettings     map[string]string
 	mux             sync.Mutex
 }
@@ -43,6 +53,15 @@ func NewSettingsManager() api.SettingsManager {
 	return &SettingsManager{
 		settings:        make(map[string]api.Settings),
 		pinnedResources: []api.PinnedResource{},
+		quickActions:    []api.QuickAction{},
+		featureGates:    map[string]bool{},
+		deployPresets:   api.DeployPresets{},
+		lintPolicy:      api.LintPolicy{},
+		securityRuleset: api.SecurityRuleset{},
+		logLevels:       []api.LogLevelTemplate{},
+		tenants:         []api.Tenant{},
+		redaction:       api.SecretRedactionPolicy{},
+		defaultNs:       []api.DefaultNamespace{},
 	}
 }
 
@@ -64,6 +83,16 @@ func (sm *SettingsManager) load(client kubernetes.Interface) (configMap *v1.Conf
 		defer sm.mux.Unlock()
 		sm.rawSettings = configMap.Data
 		sm.settings = make(map[string]api.Settings)
+		sm.quickActions = []api.QuickAction{}
+		sm.branding = nil
+		sm.featureGates = map[string]bool{}
+		sm.deployPresets = api.DeployPresets{}
+		sm.lintPolicy = api.LintPolicy{}
+		sm.securityRuleset = api.SecurityRuleset{}
+		sm.logLevels = []api.LogLevelTemplate{}
+		sm.tenants = []api.Tenant{}
+		sm.redaction = api.SecretRedactionPolicy{}
+		sm.defaultNs = []api.DefaultNamespace{}
 
 		for key, value := range sm.rawSettings {
 			if key == api.PinnedResourcesKey {
@@ -73,6 +102,76 @@ func (sm *SettingsManager) load(client kubernetes.Interface) (configMap *v1.Conf
 				} else {
 					sm.pinnedResources = *p
 				}
+			} else if key == api.QuickActionsKey {
+				a, err := api.UnmarshalQuickActions(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				} else {
+					sm.quickActions = *a
+				}
+			} else if key == api.BrandingKey {
+				b, err := api.UnmarshalBranding(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				} else {
+					sm.branding = b
+				}
+			} else if key == api.FeatureGatesKey {
+				g, err := api.UnmarshalFeatureGates(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				} else {
+					sm.featureGates = g
+				}
+			} else if key == api.DeployPresetsKey {
+				p, err := api.UnmarshalDeployPresets(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				} else {
+					sm.deployPresets = p
+				}
+			} else if key == api.LintPolicyKey {
+				p, err := api.UnmarshalLintPolicy(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				} else {
+					sm.lintPolicy = p
+				}
+			} else if key == api.SecurityRulesetKey {
+				r, err := api.UnmarshalSecurityRuleset(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				} else {
+					sm.securityRuleset = r
+				}
+			} else if key == api.LogLevelTemplatesKey {
+				t, err := api.UnmarshalLogLevelTemplates(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				} else {
+					sm.logLevels = t
+				}
+			} else if key == api.TenantsKey {
+				t, err := api.UnmarshalTenants(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				} else {
+					sm.tenants = t
+				}
+			} else if key == api.SecretRedactionPolicyKey {
+				// Invalid policy is kept, because it redacts values of all secrets.
+				p, err := api.UnmarshalSecretRedactionPolicy(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				}
+				sm.redaction = p
+			} else if key == api.DefaultNamespacesKey {
+				n, err := api.UnmarshalDefaultNamespaces(value)
+				if err != nil {
+					log.Printf("Cannot unmarshal settings key %s with %s value: %s", key, value, err.Error())
+				} else {
+					sm.defaultNs = n
+				}
 			} else {
 				s, err := api.Unmarshal(value)
 				if err != nil {
@@ -199,3 +298,125 @@ func (sm *SettingsManager) DeletePinnedResource(client kubernetes.Interface, r *
 	_, err := client.CoreV1().ConfigMaps(args.Holder.GetNamespace()).Update(context.TODO(), cm, metav1.UpdateOptions{})
 	return err
 }
+
+// GetQuickActions implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetQuickActions(client kubernetes.Interface) (a []api.QuickAction) {
+	cm, _ := sm.load(client)
+	if cm == nil {
+		return
+	}
+
+	return sm.quickActions
+}
+
+// GetBranding implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetBranding(client kubernetes.Interface) api.Branding {
+	cm, _ := sm.load(client)
+	if cm == nil || sm.branding == nil {
+		return api.GetDefaultBranding()
+	}
+
+	return *sm.branding
+}
+
+// GetFeatureGates implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetFeatureGates(client kubernetes.Interface) map[string]bool {
+	cm, _ := sm.load(client)
+	if cm == nil {
+		return map[string]bool{}
+	}
+
+	return sm.featureGates
+}
+
+// GetDeployPresets implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetDeployPresets(client kubernetes.Interface) api.DeployPresets {
+	cm, _ := sm.load(client)
+	if cm == nil {
+		return api.DeployPresets{}
+	}
+
+	return sm.deployPresets
+}
+
+// GetLintPolicy implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetLintPolicy(client kubernetes.Interface) api.LintPolicy {
+	cm, _ := sm.load(client)
+	if cm == nil {
+		return api.LintPolicy{}
+	}
+
+	return sm.lintPolicy
+}
+
+// GetSecurityRuleset implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetSecurityRuleset(client kubernetes.Interface) api.SecurityRuleset {
+	cm, _ := sm.load(client)
+	if cm == nil {
+		return api.SecurityRuleset{}
+	}
+
+	return sm.securityRuleset
+}
+
+// GetLogLevelTemplates implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetLogLevelTemplates(client kubernetes.Interface) []api.LogLevelTemplate {
+	cm, _ := sm.load(client)
+	if cm == nil {
+		return []api.LogLevelTemplate{}
+	}
+
+	return sm.logLevels
+}
+
+// GetTenants implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetTenants(client kubernetes.Interface) []api.Tenant {
+	cm, _ := sm.load(client)
+	if cm == nil {
+		return []api.Tenant{}
+	}
+
+	return sm.tenants
+}
+
+// GetSecretRedactionPolicy implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetSecretRedactionPolicy(client kubernetes.Interface) api.SecretRedactionPolicy {
+	cm, _ := sm.load(client)
+	if cm == nil {
+		return api.SecretRedactionPolicy{}
+	}
+
+	return sm.redaction
+}
+
+// GetDefaultNamespaces implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) GetDefaultNamespaces(client kubernetes.Interface) []api.DefaultNamespace {
+	cm, _ := sm.load(client)
+	if cm == nil {
+		return []api.DefaultNamespace{}
+	}
+
+	return sm.defaultNs
+}
+
+// SaveBranding implements SettingsManager interface. Check it for more information.
+func (sm *SettingsManager) SaveBranding(client kubernetes.Interface, b *api.Branding) error {
+	if err := b.Validate(); err != nil {
+		return errors.NewBadRequest(err.Error())
+	}
+
+	cm, isDiff := sm.load(client)
+	if isDiff {
+		return errors.NewInvalid(api.ConcurrentSettingsChangeError)
+	}
+
+	// Data can be nil if the configMap exists but does not have any data
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+
+	defer sm.load(client)
+	cm.Data[api.BrandingKey] = b.Marshal()
+	_, err := client.CoreV1().ConfigMaps(args.Holder.GetNamespace()).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	return err
+}
@@ -63,3 +63,125 @@ func TestSettingsManager_SaveGlobalSettings(t *testing.T) {
 			err.Error())
 	}
 }
+
+func TestSettingsManager_GetQuickActions(t *testing.T) {
+	cm := api.GetDefaultSettingsConfigMap("")
+	cm.Data[api.QuickActionsKey] = `[{"name":"drain","kinds":["Deployment"],"type":"annotate","key":"drain","value":"true"}]`
+	sm := NewSettingsManager()
+	client := fake.NewSimpleClientset(cm)
+	actions := sm.GetQuickActions(client)
+
+	if len(actions) != 1 || actions[0].Name != "drain" || !actions[0].MatchesKind("deployment") {
+		t.Errorf("it should return quick actions defined in config map instead of \"%v\"", actions)
+	}
+
+	if global := sm.GetGlobalSettings(client); !reflect.DeepEqual(api.GetDefaultSettings(), global) {
+		t.Errorf("quick actions should not affect global settings \"%v\"", global)
+	}
+}
+
+func TestSettingsManager_GetBranding(t *testing.T) {
+	sm := NewSettingsManager()
+	client := fake.NewSimpleClientset(api.GetDefaultSettingsConfigMap(""))
+
+	if b := sm.GetBranding(client); !reflect.DeepEqual(api.GetDefaultBranding(), b) {
+		t.Errorf("it should return default branding \"%v\" instead of \"%v\"", api.GetDefaultBranding(), b)
+	}
+
+	cm := api.GetDefaultSettingsConfigMap("")
+	cm.Data[api.BrandingKey] = `{"productName":"Acme Console","primaryColor":"#ff6600","helpLinks":[]}`
+	client = fake.NewSimpleClientset(cm)
+	if b := sm.GetBranding(client); b.ProductName != "Acme Console" || b.PrimaryColor != "#ff6600" {
+		t.Errorf("it should return branding defined in config map instead of \"%v\"", b)
+	}
+}
+
+func TestSettingsManager_SaveBranding(t *testing.T) {
+	cases := []struct {
+		branding api.Branding
+		valid    bool
+	}{
+		{api.Branding{ProductName: "Acme", PrimaryColor: "#f60", LogoURL: "https://acme.example/logo.svg"}, true},
+		{api.Branding{ProductName: "Acme", LogoData: "data:image/png;base64,iVBORw0KGgo="}, true},
+		{api.Branding{ProductName: "Acme", HelpLinks: []api.HelpLink{{Title: "Wiki", URL: "/wiki"}}}, true},
+		{api.Branding{ProductName: "Acme", PrimaryColor: "red;background:url(x)"}, false},
+		{api.Branding{ProductName: "Acme", LogoURL: "javascript:alert(1)"}, false},
+		{api.Branding{ProductName: "Acme", LogoURL: "//evil.example/logo.png"}, false},
+		{api.Branding{ProductName: "Acme", LogoData: "data:text/html;base64,PGI+"}, false},
+		{api.Branding{ProductName: "Acme", HelpLinks: []api.HelpLink{{Title: "Wiki", URL: "ftp://wiki"}}}, false},
+	}
+
+	for _, c := range cases {
+		sm := NewSettingsManager()
+		client := fake.NewSimpleClientset(api.GetDefaultSettingsConfigMap(""))
+		sm.GetBranding(client)
+
+		err := sm.SaveBranding(client, &c.branding)
+		if (err == nil) != c.valid {
+			t.Errorf("it should return error only for invalid branding \"%v\", got \"%v\"", c.branding, err)
+		}
+	}
+}
+
+func TestSettingsManager_GetSecretRedactionPolicy(t *testing.T) {
+	cases := []struct {
+		info        string
+		policy      string
+		namespace   string
+		secretType  string
+		annotations map[string]string
+		expected    bool
+	}{
+		{"no policy shows values", "", "default", "Opaque", nil, false},
+		{"matching namespace pattern", `[{"namespaces":["kube-*"]}]`, "kube-system", "Opaque", nil, true},
+		{"other namespace", `[{"namespaces":["kube-*"]}]`, "default", "Opaque", nil, false},
+		{"namespace and type have to match", `[{"namespaces":["kube-*"],"types":["kubernetes.io/tls"]}]`,
+			"kube-system", "Opaque", nil, false},
+		{"annotation with any value", `[{"annotations":{"sensitive":""}}]`, "default", "Opaque",
+			map[string]string{"sensitive": "yes"}, true},
+		{"annotation with other value", `[{"annotations":{"sensitive":"true"}}]`, "default", "Opaque",
+			map[string]string{"sensitive": "yes"}, false},
+		{"invalid policy redacts all", `[{"namespaces":["["]}]`, "default", "Opaque", nil, true},
+		{"malformed policy redacts all", `{`, "default", "Opaque", nil, true},
+	}
+
+	for _, c := range cases {
+		cm := api.GetDefaultSettingsConfigMap("")
+		if len(c.policy) > 0 {
+			cm.Data[api.SecretRedactionPolicyKey] = c.policy
+		}
+		sm := NewSettingsManager()
+		policy := sm.GetSecretRedactionPolicy(fake.NewSimpleClientset(cm))
+
+		if actual := policy.Redacts(c.namespace, c.secretType, c.annotations); actual != c.expected {
+			t.Errorf("%s: Redacts() == %t, expected %t", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestSettingsManager_GetDefaultNamespaces(t *testing.T) {
+	cases := []struct {
+		info     string
+		value    string
+		expected []api.DefaultNamespace
+	}{
+		{"no default namespaces", "", []api.DefaultNamespace{}},
+		{"valid default namespaces", `[{"group":"team-a","namespace":"team-a"}]`,
+			[]api.DefaultNamespace{{Group: "team-a", Namespace: "team-a"}}},
+		{"invalid namespace name", `[{"group":"team-a","namespace":"Team A"}]`, []api.DefaultNamespace{}},
+		{"missing group", `[{"namespace":"team-a"}]`, []api.DefaultNamespace{}},
+	}
+
+	for _, c := range cases {
+		cm := api.GetDefaultSettingsConfigMap("")
+		if len(c.value) > 0 {
+			cm.Data[api.DefaultNamespacesKey] = c.value
+		}
+		sm := NewSettingsManager()
+		actual := sm.GetDefaultNamespaces(fake.NewSimpleClientset(cm))
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: GetDefaultNamespaces() == %v, expected %v", c.info, actual, c.expected)
+		}
+	}
+}
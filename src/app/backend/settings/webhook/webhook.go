@@ -0,0 +1,199 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/emicklei/go-restful"
+	admission "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
+	"github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// SettingsWebhookHandler serves validating admission webhook for the settings config map. Registered
+// with a ValidatingWebhookConfiguration it rejects edits that would otherwise be silently ignored by
+// all dashboard replicas.
+type SettingsWebhookHandler struct{}
+
+// Install creates new endpoint for the settings admission webhook.
+func (self *SettingsWebhookHandler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.POST("/settings").
+			To(self.handleValidate).
+			Reads(admission.AdmissionReview{}).
+			Writes(admission.AdmissionReview{}))
+}
+
+func (self *SettingsWebhookHandler) handleValidate(request *restful.Request, response *restful.Response) {
+	review := new(admission.AdmissionReview)
+	if err := request.ReadEntity(review); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	if review.Request == nil {
+		errors.HandleInternalError(response, errors.NewBadRequest("admission review does not contain a request"))
+		return
+	}
+
+	review.Response = Review(review.Request)
+	review.Request = nil
+	response.WriteHeaderAndEntity(http.StatusOK, review)
+}
+
+// Review validates the settings config map contained in the admission request. Other objects and
+// deletions are always allowed.
+func Review(request *admission.AdmissionRequest) *admission.AdmissionResponse {
+	response := &admission.AdmissionResponse{UID: request.UID, Allowed: true}
+	if request.Operation != admission.Create && request.Operation != admission.Update {
+		return response
+	}
+
+	configMap := new(v1.ConfigMap)
+	if err := json.Unmarshal(request.Object.Raw, configMap); err != nil {
+		response.Allowed = false
+		response.Result = &metav1.Status{Code: http.StatusBadRequest, Message: err.Error()}
+		return response
+	}
+
+	if configMap.Name != api.SettingsConfigMapName || configMap.Namespace != args.Holder.GetNamespace() {
+		return response
+	}
+
+	if problems := ValidateSettingsData(configMap.Data); len(problems) > 0 {
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: fmt.Sprintf("invalid dashboard settings: %v", problems),
+		}
+	}
+
+	return response
+}
+
+// ValidateSettingsData checks that every entry of the settings config map can be read by the settings
+// manager. List of problems is returned, it is empty if data is valid.
+func ValidateSettingsData(data map[string]string) []string {
+	problems := make([]string, 0)
+	for key, value := range data {
+		if err := validateEntry(key, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", key, err.Error()))
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+func validateEntry(key, value string) error {
+	switch key {
+	case api.PinnedResourcesKey:
+		_, err := api.UnmarshalPinnedResources(value)
+		return err
+	case api.QuickActionsKey:
+		actions, err := api.UnmarshalQuickActions(value)
+		if err != nil {
+			return err
+		}
+		return validateQuickActions(*actions)
+	case api.BrandingKey:
+		branding, err := api.UnmarshalBranding(value)
+		if err != nil {
+			return err
+		}
+		return branding.Validate()
+	case api.FeatureGatesKey:
+		gates, err := api.UnmarshalFeatureGates(value)
+		if err != nil {
+			return err
+		}
+		for name := range gates {
+			if _, ok := featuresApi.DefaultFeatureGates[featuresApi.Feature(name)]; !ok {
+				return fmt.Errorf("unknown feature gate %s", name)
+			}
+		}
+		return nil
+	case api.DeployPresetsKey:
+		_, err := api.UnmarshalDeployPresets(value)
+		return err
+	case api.LintPolicyKey:
+		policy, err := api.UnmarshalLintPolicy(value)
+		if err != nil {
+			return err
+		}
+		return policy.Validate()
+	case api.SecurityRulesetKey:
+		ruleset, err := api.UnmarshalSecurityRuleset(value)
+		if err != nil {
+			return err
+		}
+		return ruleset.Validate()
+	case api.LogLevelTemplatesKey:
+		templates, err := api.UnmarshalLogLevelTemplates(value)
+		if err != nil {
+			return err
+		}
+		names := make(map[string]bool)
+		for _, template := range templates {
+			if err := template.Validate(); err != nil {
+				return err
+			}
+			if names[template.Name] {
+				return fmt.Errorf("duplicated log level template %s", template.Name)
+			}
+			names[template.Name] = true
+		}
+		return nil
+	default:
+		_, err := api.Unmarshal(value)
+		return err
+	}
+}
+
+func validateQuickActions(actions []api.QuickAction) error {
+	names := make(map[string]bool)
+	for _, action := range actions {
+		if len(action.Name) == 0 {
+			return fmt.Errorf("quick action name cannot be empty")
+		}
+		if names[action.Name] {
+			return fmt.Errorf("duplicated quick action %s", action.Name)
+		}
+		names[action.Name] = true
+
+		switch action.Type {
+		case api.QuickActionAnnotate, api.QuickActionLabel, api.QuickActionScale, api.QuickActionWebhook:
+		default:
+			return fmt.Errorf("unknown type %s of quick action %s", action.Type, action.Name)
+		}
+	}
+
+	return nil
+}
+
+// NewSettingsWebhookHandler creates SettingsWebhookHandler.
+func NewSettingsWebhookHandler() SettingsWebhookHandler {
+	return SettingsWebhookHandler{}
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	admission "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+func TestValidateSettingsData(t *testing.T) {
+	cases := []struct {
+		data     map[string]string
+		problems int
+	}{
+		{map[string]string{api.GlobalSettingsKey: api.GetDefaultSettings().Marshal()}, 0},
+		{map[string]string{api.GlobalSettingsKey: "{"}, 1},
+		{map[string]string{api.BrandingKey: `{"primaryColor":"red"}`}, 1},
+		{map[string]string{api.FeatureGatesKey: `{"Exec":false}`}, 0},
+		{map[string]string{api.FeatureGatesKey: `{"Teleport":true}`}, 1},
+		{map[string]string{api.QuickActionsKey: `[{"name":"a","type":"label"},{"name":"a","type":"label"}]`}, 1},
+		{map[string]string{api.QuickActionsKey: `[{"name":"a","type":"delete"}]`}, 1},
+		{map[string]string{api.DeployPresetsKey: `{"*":{"labels":{"team":"a"}}}`, api.PinnedResourcesKey: "x"}, 1},
+		{map[string]string{api.LintPolicyKey: `{"latestTag":"error","hostPath":"off"}`}, 0},
+		{map[string]string{api.LintPolicyKey: `{"latestTag":"fatal"}`}, 1},
+		{map[string]string{api.LintPolicyKey: `{"rootUser":"error"}`}, 1},
+		{map[string]string{api.SecurityRulesetKey: `{"nonRoot":"low","noHostNetwork":"off"}`}, 0},
+		{map[string]string{api.SecurityRulesetKey: `{"nonRoot":"critical"}`}, 1},
+		{map[string]string{api.SecurityRulesetKey: `{"seccomp":"high"}`}, 1},
+		{map[string]string{api.LogLevelTemplatesKey: `[{"name":"go","type":"env","key":"LOG_LEVEL","levels":["info"]}]`}, 0},
+		{map[string]string{api.LogLevelTemplatesKey: `[{"name":"go","type":"flag","key":"v","levels":["1"]}]`}, 1},
+	}
+
+	for _, c := range cases {
+		if problems := ValidateSettingsData(c.data); len(problems) != c.problems {
+			t.Errorf("ValidateSettingsData(%v) == %v, expected %d problems", c.data, problems, c.problems)
+		}
+	}
+}
+
+func TestReview(t *testing.T) {
+	newRequest := func(name string, data map[string]string) *admission.AdmissionRequest {
+		raw, _ := json.Marshal(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data:       data,
+		})
+		return &admission.AdmissionRequest{
+			UID:       "uid",
+			Operation: admission.Update,
+			Object:    runtime.RawExtension{Raw: raw},
+		}
+	}
+
+	invalid := map[string]string{api.GlobalSettingsKey: "{"}
+	cases := []struct {
+		request *admission.AdmissionRequest
+		allowed bool
+	}{
+		{newRequest(api.SettingsConfigMapName, map[string]string{}), true},
+		{newRequest(api.SettingsConfigMapName, invalid), false},
+		{newRequest("other", invalid), true},
+		{&admission.AdmissionRequest{UID: "uid", Operation: admission.Delete}, true},
+	}
+
+	for _, c := range cases {
+		response := Review(c.request)
+		if response.Allowed != c.allowed {
+			t.Errorf("Review(%v) allowed == %t, expected %t", c.request, response.Allowed, c.allowed)
+		}
+		if response.UID != c.request.UID {
+			t.Errorf("Review(%v) UID == %s, expected %s", c.request, response.UID, c.request.UID)
+		}
+	}
+}
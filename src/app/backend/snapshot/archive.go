@@ -0,0 +1,145 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snapshot reads and writes cluster snapshots and serves them in place of a live cluster.
+//
+// Snapshot archive is a gzip compressed tar archive with one JSON serialized object per file. Files are
+// named <namespace>/<kind>/<name>.json, cluster scoped objects use '_cluster' instead of the namespace.
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	apiextensionsscheme "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientsetscheme "k8s.io/client-go/kubernetes/scheme"
+
+	pluginscheme "github.com/kubernetes/dashboard/src/app/backend/plugin/client/clientset/versioned/scheme"
+)
+
+// ClusterScope is used in place of the namespace in names of cluster scoped objects.
+const ClusterScope = "_cluster"
+
+// Scheme knows all object types that can be stored in a snapshot.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientsetscheme.AddToScheme(Scheme))
+	utilruntime.Must(apiextensionsscheme.AddToScheme(Scheme))
+	utilruntime.Must(pluginscheme.AddToScheme(Scheme))
+}
+
+// Read decodes all objects of the snapshot archive. Files with objects of unknown types are skipped.
+func Read(reader io.Reader) ([]runtime.Object, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	decoder := serializer.NewCodecFactory(Scheme).UniversalDeserializer()
+	tarReader := tar.NewReader(gzipReader)
+	objects := make([]runtime.Object, 0)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg || path.Ext(header.Name) != ".json" {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+
+		object, gvk, err := decoder.Decode(data, nil, nil)
+		if err != nil {
+			if runtime.IsNotRegisteredError(err) {
+				log.Printf("Skipping snapshot file %s: %s", header.Name, err.Error())
+				continue
+			}
+			return nil, fmt.Errorf("invalid snapshot file %s: %s", header.Name, err.Error())
+		}
+
+		object.GetObjectKind().SetGroupVersionKind(*gvk)
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
+// Writer writes objects to a snapshot archive. Objects are written as soon as they are added, so that
+// the archive can be streamed.
+type Writer struct {
+	gzipWriter *gzip.Writer
+	tarWriter  *tar.Writer
+	modTime    time.Time
+}
+
+// WriteObject adds JSON serialized object of given kind to the archive.
+func (self *Writer) WriteObject(namespace, kind, name string, data []byte) error {
+	if len(namespace) == 0 {
+		namespace = ClusterScope
+	}
+
+	return self.WriteFile(path.Join(namespace, strings.ToLower(kind), name+".json"), data)
+}
+
+// WriteFile adds a file to the archive. Files that are not objects are ignored by Read.
+func (self *Writer) WriteFile(name string, data []byte) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  self.modTime,
+		Typeflag: tar.TypeReg,
+	}
+	if err := self.tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err := self.tarWriter.Write(data)
+	return err
+}
+
+// Close finishes the archive. It does not close the underlying writer.
+func (self *Writer) Close() error {
+	if err := self.tarWriter.Close(); err != nil {
+		return err
+	}
+
+	return self.gzipWriter.Close()
+}
+
+// NewWriter creates snapshot archive writer on top of given writer.
+func NewWriter(writer io.Writer) *Writer {
+	gzipWriter := gzip.NewWriter(writer)
+	return &Writer{gzipWriter: gzipWriter, tarWriter: tar.NewWriter(gzipWriter), modTime: time.Now()}
+}
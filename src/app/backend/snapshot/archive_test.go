@@ -0,0 +1,90 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newArchive(t *testing.T, files map[string]string) *bytes.Buffer {
+	buffer := new(bytes.Buffer)
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer
+}
+
+func TestReadAndServeSnapshot(t *testing.T) {
+	archive := newArchive(t, map[string]string{
+		"default/deployment/web.json": `{"apiVersion":"apps/v1","kind":"Deployment",` +
+			`"metadata":{"name":"web","namespace":"default"}}`,
+		"_cluster/customresourcedefinition/foos.example.com.json": `{"apiVersion":"apiextensions.k8s.io/v1",` +
+			`"kind":"CustomResourceDefinition","metadata":{"name":"foos.example.com"}}`,
+		"default/foo/unknown.json": `{"apiVersion":"example.com/v1","kind":"Foo","metadata":{"name":"unknown"}}`,
+		"README":                   "not an object",
+	})
+
+	objects, err := Read(archive)
+	if err != nil {
+		t.Fatalf("Read(): unexpected error: %v", err)
+	}
+
+	if len(objects) != 2 {
+		t.Fatalf("Read(): expected 2 objects, got %d", len(objects))
+	}
+
+	manager := NewClientManager(objects)
+	deployments, err := manager.InsecureClient().AppsV1().Deployments("default").List(context.TODO(),
+		metaV1.ListOptions{})
+	if err != nil || len(deployments.Items) != 1 || deployments.Items[0].Name != "web" {
+		t.Errorf("expected deployment web to be served, got %v, %v", deployments, err)
+	}
+
+	crds, err := manager.InsecureAPIExtensionsClient().ApiextensionsV1().CustomResourceDefinitions().List(
+		context.TODO(), metaV1.ListOptions{})
+	if err != nil || len(crds.Items) != 1 {
+		t.Errorf("expected custom resource definition to be served, got %v, %v", crds, err)
+	}
+
+	verber, _ := manager.VerberClient(nil, nil)
+	if _, err := verber.Get("deployment", true, "default", "web"); err != nil {
+		t.Errorf("Get(deployment): unexpected error: %v", err)
+	}
+
+	if err := verber.Delete("deployment", true, "default", "web"); err == nil {
+		t.Error("Delete(deployment): expected snapshot to be read-only")
+	}
+}
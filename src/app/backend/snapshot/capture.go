@@ -0,0 +1,151 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+)
+
+// DefaultCaptureKinds are captured when no kinds are selected.
+var DefaultCaptureKinds = []string{
+	api.ResourceKindNamespace,
+	api.ResourceKindConfigMap,
+	api.ResourceKindCronJob,
+	api.ResourceKindDaemonSet,
+	api.ResourceKindDeployment,
+	api.ResourceKindEndpoint,
+	api.ResourceKindEvent,
+	api.ResourceKindHorizontalPodAutoscaler,
+	api.ResourceKindIngress,
+	api.ResourceKindJob,
+	api.ResourceKindPersistentVolumeClaim,
+	api.ResourceKindPod,
+	api.ResourceKindReplicaSet,
+	api.ResourceKindReplicationController,
+	api.ResourceKindResourceQuota,
+	api.ResourceKindSecret,
+	api.ResourceKindService,
+	api.ResourceKindServiceAccount,
+	api.ResourceKindStatefulSet,
+}
+
+// Client types whose resources can be captured.
+var capturedClientTypes = map[api.ClientType]bool{
+	api.ClientTypeDefault:           true,
+	api.ClientTypeExtensionClient:   true,
+	api.ClientTypeAppsClient:        true,
+	api.ClientTypeBatchClient:       true,
+	api.ClientTypeBetaBatchClient:   true,
+	api.ClientTypeAutoscalingClient: true,
+	api.ClientTypeStorageClient:     true,
+	api.ClientTypeRbacClient:        true,
+}
+
+// Annotation that holds the whole object as last applied by kubectl, including secret data.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// lister returns JSON serialized list of the resource. Namespace is empty for cluster scoped resources.
+type lister func(mapping api.APIMapping, namespace string) ([]byte, error)
+
+// ValidateCaptureKinds returns an error if any of the kinds can not be captured.
+func ValidateCaptureKinds(kinds []string) error {
+	for _, kind := range kinds {
+		mapping, ok := api.KindToAPIMapping[kind]
+		if !ok || !capturedClientTypes[mapping.ClientType] {
+			return errors.NewBadRequest(fmt.Sprintf("kind %s can not be captured", kind))
+		}
+	}
+
+	return nil
+}
+
+// capture writes sanitized objects of given kinds from given namespaces to the archive. Cluster scoped
+// kinds are listed once, namespace objects are limited to the captured namespaces. Kinds that can not
+// be listed, i.e. because user is not allowed to, are skipped and returned as problems. Returned error
+// means that the archive could not be written.
+func capture(list lister, namespaces, kinds []string, writer *Writer) ([]string, error) {
+	problems := make([]string, 0)
+	captured := make(map[string]bool)
+	for _, namespace := range namespaces {
+		captured[namespace] = true
+	}
+
+	for _, kind := range kinds {
+		mapping := api.KindToAPIMapping[kind]
+		scopes := namespaces
+		if !mapping.Namespaced {
+			scopes = []string{""}
+		}
+
+		for _, namespace := range scopes {
+			data, err := list(mapping, namespace)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s in namespace %q: %s", kind, namespace, err.Error()))
+				continue
+			}
+
+			items := new(unstructured.UnstructuredList)
+			if err := items.UnmarshalJSON(data); err != nil {
+				problems = append(problems, fmt.Sprintf("%s in namespace %q: %s", kind, namespace, err.Error()))
+				continue
+			}
+
+			for _, item := range items.Items {
+				if kind == api.ResourceKindNamespace && !captured[item.GetName()] {
+					continue
+				}
+
+				sanitize(&item)
+				data, err := item.MarshalJSON()
+				if err != nil {
+					return problems, err
+				}
+
+				if err := writer.WriteObject(item.GetNamespace(), kind, item.GetName(), data); err != nil {
+					return problems, err
+				}
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// sanitize removes data that should not leave the cluster, i.e. values of secrets.
+func sanitize(object *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(object.Object, "metadata", "managedFields")
+	if !strings.EqualFold(object.GetKind(), api.ResourceKindSecret) {
+		return
+	}
+
+	if data, found, _ := unstructured.NestedMap(object.Object, "data"); found {
+		for key := range data {
+			data[key] = ""
+		}
+		_ = unstructured.SetNestedMap(object.Object, data, "data")
+	}
+	unstructured.RemoveNestedField(object.Object, "stringData")
+
+	if annotations := object.GetAnnotations(); annotations != nil {
+		delete(annotations, lastAppliedConfigAnnotation)
+		object.SetAnnotations(annotations)
+	}
+}
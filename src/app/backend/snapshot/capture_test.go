@@ -0,0 +1,93 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+func TestCapture(t *testing.T) {
+	lists := map[string]string{
+		"namespaces": `{"apiVersion":"v1","kind":"NamespaceList","items":[` +
+			`{"metadata":{"name":"default"}},{"metadata":{"name":"kube-system"}}]}`,
+		"secrets/default": `{"apiVersion":"v1","kind":"SecretList","items":[{"metadata":{"name":"db",` +
+			`"namespace":"default","annotations":{"` + lastAppliedConfigAnnotation + `":"{}"}},` +
+			`"data":{"password":"c2VjcmV0"}}]}`,
+	}
+
+	list := func(mapping api.APIMapping, namespace string) ([]byte, error) {
+		key := mapping.Resource
+		if len(namespace) > 0 {
+			key += "/" + namespace
+		}
+
+		if data, ok := lists[key]; ok {
+			return []byte(data), nil
+		}
+		return nil, fmt.Errorf("forbidden")
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := NewWriter(buffer)
+	kinds := []string{api.ResourceKindNamespace, api.ResourceKindSecret, api.ResourceKindPod}
+	problems, err := capture(list, []string{"default"}, kinds, writer)
+	if err != nil {
+		t.Fatalf("capture(): unexpected error: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(problems) != 1 {
+		t.Errorf("capture(): expected pods to be reported as a problem, got %v", problems)
+	}
+
+	objects, err := Read(buffer)
+	if err != nil {
+		t.Fatalf("Read(): unexpected error: %v", err)
+	}
+
+	if len(objects) != 2 {
+		t.Fatalf("Read(): expected namespace default and secret, got %d objects", len(objects))
+	}
+
+	for _, object := range objects {
+		if secret, ok := object.(*corev1.Secret); ok {
+			if len(secret.Data["password"]) != 0 || len(secret.Annotations[lastAppliedConfigAnnotation]) != 0 {
+				t.Errorf("capture(): expected secret to be redacted, got %v", secret)
+			}
+		}
+
+		if namespace, ok := object.(*corev1.Namespace); ok && namespace.Name != "default" {
+			t.Errorf("capture(): expected only captured namespaces, got %s", namespace.Name)
+		}
+	}
+}
+
+func TestValidateCaptureKinds(t *testing.T) {
+	if err := ValidateCaptureKinds(DefaultCaptureKinds); err != nil {
+		t.Errorf("ValidateCaptureKinds(): unexpected error for default kinds: %v", err)
+	}
+
+	if err := ValidateCaptureKinds([]string{api.ResourceKindPlugin}); err == nil {
+		t.Error("ValidateCaptureKinds(plugin): expected error")
+	}
+}
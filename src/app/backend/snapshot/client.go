@@ -0,0 +1,229 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apiextensionsscheme "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	clientsetscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	featuresApi "github.com/kubernetes/dashboard/src/app/backend/features/api"
+	pluginclientset "github.com/kubernetes/dashboard/src/app/backend/plugin/client/clientset/versioned"
+	pluginfake "github.com/kubernetes/dashboard/src/app/backend/plugin/client/clientset/versioned/fake"
+	pluginscheme "github.com/kubernetes/dashboard/src/app/backend/plugin/client/clientset/versioned/scheme"
+)
+
+// MsgReadOnly is returned for every attempt to modify the snapshot.
+const MsgReadOnly = "Dashboard is serving a cluster snapshot and is read-only"
+
+// clientManager implements clientapi.ClientManager on top of in-memory clients loaded with objects of
+// the snapshot. All users share the same view and are allowed to see everything.
+type clientManager struct {
+	objects             []runtime.Object
+	client              kubernetes.Interface
+	apiExtensionsClient apiextensionsclientset.Interface
+	pluginClient        pluginclientset.Interface
+	dynamicClient       dynamic.Interface
+	csrfKey             string
+}
+
+// Client implements clientapi.ClientManager.
+func (self *clientManager) Client(req *restful.Request) (kubernetes.Interface, error) {
+	return self.client, nil
+}
+
+// InsecureClient implements clientapi.ClientManager.
+func (self *clientManager) InsecureClient() kubernetes.Interface {
+	return self.client
+}
+
+// APIExtensionsClient implements clientapi.ClientManager.
+func (self *clientManager) APIExtensionsClient(req *restful.Request) (apiextensionsclientset.Interface, error) {
+	return self.apiExtensionsClient, nil
+}
+
+// PluginClient implements clientapi.ClientManager.
+func (self *clientManager) PluginClient(req *restful.Request) (pluginclientset.Interface, error) {
+	return self.pluginClient, nil
+}
+
+// InsecureAPIExtensionsClient implements clientapi.ClientManager.
+func (self *clientManager) InsecureAPIExtensionsClient() apiextensionsclientset.Interface {
+	return self.apiExtensionsClient
+}
+
+// InsecurePluginClient implements clientapi.ClientManager.
+func (self *clientManager) InsecurePluginClient() pluginclientset.Interface {
+	return self.pluginClient
+}
+
+// InsecureDynamicClient implements clientapi.ClientManager. Custom resources are only served through the
+// API extensions and plugin clients, so the dynamic client serves nothing.
+func (self *clientManager) InsecureDynamicClient() dynamic.Interface {
+	return self.dynamicClient
+}
+
+// CanI implements clientapi.ClientManager. Everything stored in the snapshot can be seen by everyone.
+func (self *clientManager) CanI(req *restful.Request, ssar *authorizationv1.SelfSubjectAccessReview) bool {
+	return true
+}
+
+// Config implements clientapi.ClientManager. Returned config does not point to any cluster, it only
+// exists for handlers that pass it on to VerberClient.
+func (self *clientManager) Config(req *restful.Request) (*rest.Config, error) {
+	return &rest.Config{}, nil
+}
+
+// ClientCmdConfig implements clientapi.ClientManager. There are no credentials in snapshot mode.
+func (self *clientManager) ClientCmdConfig(req *restful.Request) (clientcmd.ClientConfig, error) {
+	return nil, errors.NewBadRequest(MsgReadOnly)
+}
+
+// CSRFKey implements clientapi.ClientManager.
+func (self *clientManager) CSRFKey() string {
+	return self.csrfKey
+}
+
+// HasAccess implements clientapi.ClientManager. Any credentials are accepted.
+func (self *clientManager) HasAccess(authInfo api.AuthInfo) error {
+	return nil
+}
+
+// VerberClient implements clientapi.ClientManager.
+func (self *clientManager) VerberClient(req *restful.Request, config *rest.Config) (clientapi.ResourceVerber, error) {
+	return &resourceVerber{objects: self.objects}, nil
+}
+
+// SetTokenManager implements clientapi.ClientManager. Tokens are not needed to access the snapshot.
+func (self *clientManager) SetTokenManager(manager authApi.TokenManager) {}
+
+// SetFeatureGateManager implements clientapi.ClientManager. Snapshot is always read without logging in.
+func (self *clientManager) SetFeatureGateManager(manager featuresApi.FeatureGateManager) {}
+
+// SetAuditLogger implements clientapi.ClientManager. Login is never skipped, so there is nothing to record.
+func (self *clientManager) SetAuditLogger(logger authApi.AuditLogger) {}
+
+// SkipLoginIdentity implements clientapi.ClientManager. Snapshot objects are served without any identity.
+func (self *clientManager) SkipLoginIdentity() string {
+	return "anonymous"
+}
+
+// HeaderAuthInfo implements clientapi.ClientManager. Snapshots are served without authentication.
+func (self *clientManager) HeaderAuthInfo(req *restful.Request) *api.AuthInfo {
+	return nil
+}
+
+// NewClientManager creates client manager serving given snapshot objects.
+func NewClientManager(objects []runtime.Object) clientapi.ClientManager {
+	var core, apiExtensions, plugin []runtime.Object
+	for _, object := range objects {
+		gvk := object.GetObjectKind().GroupVersionKind()
+		switch {
+		case clientsetscheme.Scheme.Recognizes(gvk):
+			core = append(core, object)
+		case apiextensionsscheme.Scheme.Recognizes(gvk):
+			apiExtensions = append(apiExtensions, object)
+		case pluginscheme.Scheme.Recognizes(gvk):
+			plugin = append(plugin, object)
+		}
+	}
+
+	client := fake.NewSimpleClientset(core...)
+	// Access reviews are not stored in the snapshot, they are answered the same way as CanI.
+	client.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = true
+			return true, review, nil
+		})
+
+	return &clientManager{
+		objects:             objects,
+		client:              client,
+		apiExtensionsClient: apiextensionsfake.NewSimpleClientset(apiExtensions...),
+		pluginClient:        pluginfake.NewSimpleClientset(plugin...),
+		dynamicClient:       dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		csrfKey:             clientapi.GenerateCSRFKey(),
+	}
+}
+
+// resourceVerber implements clientapi.ResourceVerber for objects of the snapshot. Only Get is supported.
+type resourceVerber struct {
+	objects []runtime.Object
+}
+
+// Get returns the snapshot object of given kind, i.e. 'deployment', with given namespace and name.
+func (self *resourceVerber) Get(kind string, namespaceSet bool, namespace string, name string) (
+	runtime.Object, error) {
+	for _, object := range self.objects {
+		if !strings.EqualFold(object.GetObjectKind().GroupVersionKind().Kind, kind) {
+			continue
+		}
+
+		accessor, err := meta.Accessor(object)
+		if err != nil {
+			return nil, err
+		}
+
+		if accessor.GetName() == name && (!namespaceSet || accessor.GetNamespace() == namespace) {
+			return object, nil
+		}
+	}
+
+	return nil, errors.NewNotFound(kind + " " + name + " not found in the snapshot")
+}
+
+// Put implements clientapi.ResourceVerber.
+func (self *resourceVerber) Put(kind string, namespaceSet bool, namespace string, name string,
+	object *runtime.Unknown) error {
+	return errors.NewGenericResponse(http.StatusForbidden, MsgReadOnly)
+}
+
+// Delete implements clientapi.ResourceVerber.
+func (self *resourceVerber) Delete(kind string, namespaceSet bool, namespace string, name string) error {
+	return errors.NewGenericResponse(http.StatusForbidden, MsgReadOnly)
+}
+
+// Patch implements clientapi.ResourceVerber.
+func (self *resourceVerber) Patch(kind string, namespaceSet bool, namespace string, name string,
+	patchType types.PatchType, data []byte) error {
+	return errors.NewGenericResponse(http.StatusForbidden, MsgReadOnly)
+}
+
+// Apply implements clientapi.ResourceVerber.
+func (self *resourceVerber) Apply(kind string, namespaceSet bool, namespace string, name string, data []byte,
+	fieldManager string, force bool) error {
+	return errors.NewGenericResponse(http.StatusForbidden, MsgReadOnly)
+}
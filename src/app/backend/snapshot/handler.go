@@ -0,0 +1,154 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/compat"
+	"github.com/kubernetes/dashboard/src/app/backend/stream"
+)
+
+// ProblemsFileName is the name of the archive file that lists everything that could not be captured.
+const ProblemsFileName = "problems.txt"
+
+// SnapshotHandler manages all endpoints related to cluster snapshots.
+type SnapshotHandler struct {
+	cManager clientapi.ClientManager
+}
+
+// Install creates new endpoints for cluster snapshots.
+func (self *SnapshotHandler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/snapshot").
+			To(self.handleCaptureSnapshot).
+			Metadata(stream.RouteMetadata, true))
+}
+
+// NewSnapshotHandler creates SnapshotHandler.
+func NewSnapshotHandler(cManager clientapi.ClientManager) *SnapshotHandler {
+	return &SnapshotHandler{cManager: cManager}
+}
+
+// handleCaptureSnapshot streams a snapshot of namespaces given by comma separated 'namespaces' parameter.
+// Optional 'kinds' parameter selects captured kinds. Objects are read with credentials of the user, who has
+// to be a cluster administrator.
+func (self *SnapshotHandler) handleCaptureSnapshot(request *restful.Request, response *restful.Response) {
+	if len(args.Holder.GetSnapshotFile()) > 0 {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden, MsgReadOnly))
+		return
+	}
+
+	if !self.cManager.CanI(request, clientapi.ToClusterAdminAccessReview()) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			"only cluster administrators can capture snapshots"))
+		return
+	}
+
+	namespaces := splitParameter(request.QueryParameter("namespaces"))
+	if len(namespaces) == 0 {
+		errors.HandleInternalError(response, errors.NewBadRequest("at least one namespace is required"))
+		return
+	}
+
+	kinds := splitParameter(request.QueryParameter("kinds"))
+	if len(kinds) == 0 {
+		kinds = DefaultCaptureKinds
+	}
+
+	if err := ValidateCaptureKinds(kinds); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	client, err := self.cManager.Client(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.AddHeader("Content-Type", "application/gzip")
+	response.AddHeader("Content-Disposition", fmt.Sprintf(`attachment; filename="snapshot-%s.tar.gz"`,
+		time.Now().UTC().Format("20060102-150405")))
+	response.WriteHeader(http.StatusOK)
+
+	// Status has been sent already, so problems can only be logged and written to the archive.
+	writer := NewWriter(response)
+	problems, err := capture(newLister(client), namespaces, kinds, writer)
+	if err != nil {
+		log.Printf("Error while writing snapshot: %s", err.Error())
+		return
+	}
+
+	if len(problems) > 0 {
+		if err := writer.WriteFile(ProblemsFileName, []byte(strings.Join(problems, "\n")+"\n")); err != nil {
+			log.Printf("Error while writing snapshot: %s", err.Error())
+			return
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("Error while writing snapshot: %s", err.Error())
+	}
+}
+
+// newLister creates lister that reads resources as JSON using REST clients of given client. Cron jobs of batch/v1
+// are read as batch/v1beta1 ones, since they have the same fields. Ingresses of networking.k8s.io/v1 are not,
+// so they are captured only from clusters serving extensions/v1beta1.
+func newLister(client kubernetes.Interface) lister {
+	clients := map[api.ClientType]rest.Interface{
+		api.ClientTypeDefault:           client.CoreV1().RESTClient(),
+		api.ClientTypeExtensionClient:   client.ExtensionsV1beta1().RESTClient(),
+		api.ClientTypeAppsClient:        client.AppsV1().RESTClient(),
+		api.ClientTypeBatchClient:       client.BatchV1().RESTClient(),
+		api.ClientTypeBetaBatchClient:   compat.CronJobRESTClient(client),
+		api.ClientTypeAutoscalingClient: client.AutoscalingV1().RESTClient(),
+		api.ClientTypeStorageClient:     client.StorageV1().RESTClient(),
+		api.ClientTypeRbacClient:        client.RbacV1().RESTClient(),
+	}
+
+	return func(mapping api.APIMapping, namespace string) ([]byte, error) {
+		return clients[mapping.ClientType].Get().
+			SetHeader("Accept", "application/json").
+			Namespace(namespace).
+			Resource(mapping.Resource).
+			Do(context.TODO()).
+			Raw()
+	}
+}
+
+func splitParameter(value string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
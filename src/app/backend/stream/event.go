@@ -0,0 +1,57 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+)
+
+// StartEvents writes headers of a server-sent events stream.
+func StartEvents(response *restful.Response) {
+	response.AddHeader("Content-Type", "text/event-stream")
+	response.AddHeader("Cache-Control", "no-cache")
+	response.WriteHeader(http.StatusOK)
+	Flush(response)
+}
+
+// WriteEvent writes a server-sent event of given type with data marshalled to JSON.
+func WriteEvent(response *restful.Response, event string, data interface{}) {
+	content, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Could not marshal %s event: %s", event, err)
+		return
+	}
+
+	fmt.Fprintf(response, "event: %s\ndata: %s\n\n", event, content)
+	Flush(response)
+}
+
+// WriteHeartbeat writes a comment that keeps the event stream open through proxies.
+func WriteHeartbeat(response *restful.Response) {
+	fmt.Fprint(response, ": heartbeat\n\n")
+	Flush(response)
+}
+
+// Flush sends buffered data to the client, if the response writer supports it.
+func Flush(response *restful.Response) {
+	if flusher, ok := response.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
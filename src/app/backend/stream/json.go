@@ -0,0 +1,251 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+)
+
+const (
+	// DefaultStreamThreshold is the minimal number of list items for which the response is streamed.
+	DefaultStreamThreshold = 1000
+	// chunkSize is the number of list items encoded by a single worker at once.
+	chunkSize = 256
+)
+
+// Unbufferer is implemented by response writers that hold the whole response in memory before sending it,
+// i.e. to replace it when the request fails. JSONEntityAccessor calls Unbuffer before it streams a large
+// list, so that the list is sent to the client as it is encoded instead of being collected in memory.
+type Unbufferer interface {
+	// Unbuffer sends everything written so far and passes further writes directly to the client.
+	Unbuffer()
+}
+
+// JSONEntityAccessor is a JSON EntityReaderWriter that streams large lists. When the written entity is
+// a struct holding a slice with at least DefaultStreamThreshold items, the items are encoded in chunks by
+// a pool of workers and written to the response in order, instead of marshalling the whole response
+// into memory first. Other entities are handled by the default go-restful accessor.
+type JSONEntityAccessor struct {
+	contentType string
+	workers     int
+	threshold   int
+	fallback    restful.EntityReaderWriter
+}
+
+// Read implements EntityReaderWriter interface.
+func (self *JSONEntityAccessor) Read(req *restful.Request, v interface{}) error {
+	return self.fallback.Read(req, v)
+}
+
+// Write implements EntityReaderWriter interface.
+func (self *JSONEntityAccessor) Write(resp *restful.Response, status int, v interface{}) error {
+	value, index, ok := self.findLargeList(v)
+	if !ok {
+		return self.fallback.Write(resp, status, v)
+	}
+
+	fields, err := marshalOtherFields(value, index)
+	if err != nil {
+		return err
+	}
+
+	if unbufferer, ok := resp.ResponseWriter.(Unbufferer); ok {
+		unbufferer.Unbuffer()
+	}
+
+	resp.Header().Set(restful.HEADER_ContentType, self.contentType)
+	resp.WriteHeader(status)
+	return self.writeObject(resp, fields, jsonName(value.Type().Field(index)), value.Field(index))
+}
+
+// findLargeList returns the struct behind v and index of its first slice field that has at least
+// threshold items.
+func (self *JSONEntityAccessor) findLargeList(v interface{}) (reflect.Value, int, bool) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return value, 0, false
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" || field.Anonymous || jsonName(field) == "-" {
+			continue
+		}
+
+		if value.Field(i).Kind() == reflect.Slice && value.Field(i).Len() >= self.threshold {
+			return value, i, true
+		}
+	}
+
+	return value, 0, false
+}
+
+// writeObject writes a JSON object made of already encoded fields and the list, which is encoded by workers.
+func (self *JSONEntityAccessor) writeObject(w io.Writer, fields map[string]json.RawMessage, listName string,
+	list reflect.Value) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buffer := bytes.NewBufferString("{")
+	for _, name := range names {
+		key, _ := json.Marshal(name)
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(fields[name])
+		buffer.WriteByte(',')
+	}
+	key, _ := json.Marshal(listName)
+	buffer.Write(key)
+	buffer.WriteString(":[")
+	if _, err := w.Write(buffer.Bytes()); err != nil {
+		return err
+	}
+
+	if err := self.writeItems(w, list); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "]}\n")
+	return err
+}
+
+type chunkResult struct {
+	data []byte
+	err  error
+}
+
+// writeItems encodes list items in chunks using a pool of workers. Chunks are written in the original
+// order. At most 'workers' encoded chunks are kept in memory at the same time.
+func (self *JSONEntityAccessor) writeItems(w io.Writer, list reflect.Value) error {
+	pending := make(chan chan chunkResult, self.workers)
+	go func() {
+		defer close(pending)
+		for start := 0; start < list.Len(); start += chunkSize {
+			end := start + chunkSize
+			if end > list.Len() {
+				end = list.Len()
+			}
+
+			result := make(chan chunkResult, 1)
+			pending <- result
+			go func(start, end int) {
+				data, err := encodeChunk(list, start, end)
+				result <- chunkResult{data: data, err: err}
+			}(start, end)
+		}
+	}()
+
+	var err error
+	first := true
+	for result := range pending {
+		chunk := <-result
+		// Keep draining remaining chunks after an error, so the producer can finish.
+		if err != nil {
+			continue
+		}
+
+		if chunk.err != nil {
+			err = chunk.err
+			continue
+		}
+
+		if !first {
+			if _, err = w.Write([]byte{','}); err != nil {
+				continue
+			}
+		}
+		first = false
+		_, err = w.Write(chunk.data)
+	}
+
+	return err
+}
+
+// encodeChunk encodes list items from start to end as comma separated JSON values.
+func encodeChunk(list reflect.Value, start, end int) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	for i := start; i < end; i++ {
+		if i > start {
+			buffer.WriteByte(',')
+		}
+
+		data, err := json.Marshal(list.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(data)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// marshalOtherFields encodes all fields of the struct except the one with given index. Returned map
+// contains encoded value of every top-level JSON key.
+func marshalOtherFields(value reflect.Value, index int) (map[string]json.RawMessage, error) {
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+	copied.Field(index).Set(reflect.Zero(value.Field(index).Type()))
+
+	data, err := json.Marshal(copied.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	delete(fields, jsonName(value.Type().Field(index)))
+	return fields, nil
+}
+
+// jsonName returns the key under which encoding/json writes given struct field.
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if len(name) == 0 {
+		return field.Name
+	}
+
+	return name
+}
+
+// NewJSONEntityAccessor creates JSONEntityAccessor that uses given number of workers to encode large lists.
+func NewJSONEntityAccessor(workers int) restful.EntityReaderWriter {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &JSONEntityAccessor{
+		contentType: restful.MIME_JSON,
+		workers:     workers,
+		threshold:   DefaultStreamThreshold,
+		fallback:    restful.NewEntityAccessorJSON(restful.MIME_JSON),
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+)
+
+type testItem struct {
+	Name  string `json:"name"`
+	Value int    `json:"value,omitempty"`
+}
+
+type testList struct {
+	ListMeta struct {
+		TotalItems int `json:"totalItems"`
+	} `json:"listMeta"`
+	Items  []testItem `json:"items"`
+	Errors []string   `json:"errors"`
+	hidden []testItem
+}
+
+func newTestList(size int) *testList {
+	list := &testList{Items: make([]testItem, size), Errors: []string{}}
+	list.ListMeta.TotalItems = size
+	for i := range list.Items {
+		list.Items[i] = testItem{Name: "item-" + strconv.Itoa(i), Value: i}
+	}
+	return list
+}
+
+func TestJSONEntityAccessor_Write(t *testing.T) {
+	for _, size := range []int{0, 10, DefaultStreamThreshold, DefaultStreamThreshold + chunkSize*3 + 7} {
+		list := newTestList(size)
+		recorder := httptest.NewRecorder()
+		response := restful.NewResponse(recorder)
+
+		if err := NewJSONEntityAccessor(3).Write(response, http.StatusOK, list); err != nil {
+			t.Fatalf("Write() returned unexpected error for %d items: %s", size, err.Error())
+		}
+
+		if recorder.Code != http.StatusOK || recorder.Header().Get(restful.HEADER_ContentType) != restful.MIME_JSON {
+			t.Errorf("unexpected status %d or content type %s", recorder.Code,
+				recorder.Header().Get(restful.HEADER_ContentType))
+		}
+
+		actual := new(testList)
+		if err := json.Unmarshal(recorder.Body.Bytes(), actual); err != nil {
+			t.Fatalf("response for %d items is not valid JSON: %s", size, err.Error())
+		}
+
+		if !reflect.DeepEqual(actual, list) {
+			t.Errorf("response for %d items does not match the written list", size)
+		}
+	}
+}
+
+type bufferingRecorder struct {
+	*httptest.ResponseRecorder
+	unbuffered bool
+}
+
+func (self *bufferingRecorder) Unbuffer() {
+	self.unbuffered = true
+}
+
+func TestJSONEntityAccessor_WriteUnbuffers(t *testing.T) {
+	cases := []struct {
+		size     int
+		expected bool
+	}{
+		{10, false},
+		{DefaultStreamThreshold, true},
+	}
+
+	for _, c := range cases {
+		recorder := &bufferingRecorder{ResponseRecorder: httptest.NewRecorder()}
+		if err := NewJSONEntityAccessor(1).Write(restful.NewResponse(recorder), http.StatusOK,
+			newTestList(c.size)); err != nil {
+			t.Fatalf("Write() returned unexpected error for %d items: %s", c.size, err.Error())
+		}
+
+		if recorder.unbuffered != c.expected {
+			t.Errorf("Unbuffer() called == %t for %d items, expected %t", recorder.unbuffered, c.size, c.expected)
+		}
+	}
+}
+
+func TestJSONEntityAccessor_findLargeList(t *testing.T) {
+	accessor := NewJSONEntityAccessor(1).(*JSONEntityAccessor)
+	cases := []struct {
+		entity   interface{}
+		expected bool
+	}{
+		{newTestList(DefaultStreamThreshold), true},
+		{*newTestList(DefaultStreamThreshold), true},
+		{newTestList(DefaultStreamThreshold - 1), false},
+		{&testList{hidden: make([]testItem, DefaultStreamThreshold)}, false},
+		{make([]testItem, DefaultStreamThreshold), false},
+		{nil, false},
+	}
+
+	for _, c := range cases {
+		if _, _, ok := accessor.findLargeList(c.entity); ok != c.expected {
+			t.Errorf("findLargeList() == %t, expected %t", ok, c.expected)
+		}
+	}
+}
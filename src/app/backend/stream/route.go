@@ -0,0 +1,30 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"github.com/emicklei/go-restful"
+)
+
+// RouteMetadata is the key of go-restful route metadata that marks long-lived streaming routes, e.g. log
+// downloads. Routes registered with this key set to true are never aborted by request timeouts and are
+// exempt from concurrency limits and response caching.
+const RouteMetadata = "streaming"
+
+// IsStreamingRoute returns true if given route was registered with RouteMetadata.
+func IsStreamingRoute(route restful.Route) bool {
+	streaming, _ := route.Metadata[RouteMetadata].(bool)
+	return streaming
+}
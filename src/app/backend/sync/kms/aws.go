@@ -0,0 +1,276 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	awsService     = "kms"
+	awsContentType = "application/x-amz-json-1.1"
+	awsSTSVersion  = "2011-06-15"
+	// Name of the session of the role assumed with web identity token.
+	awsRoleSessionName = "kubernetes-dashboard"
+)
+
+// Credentials used to sign requests to AWS.
+type awsCredentials struct {
+	AccessKeyID     string    `xml:"AccessKeyId"`
+	SecretAccessKey string    `xml:"SecretAccessKey"`
+	SessionToken    string    `xml:"SessionToken"`
+	Expiration      time.Time `xml:"Expiration"`
+}
+
+// Implements Provider interface using AWS KMS. Key is ARN, ID or alias of symmetric key. Region is taken from the
+// key ARN or AWS_REGION environment variable. Credentials are taken from the standard environment variables, either
+// static keys or the role and web identity token set up by IAM roles for service accounts.
+type awsProvider struct {
+	key         string
+	region      string
+	endpoint    string
+	credentials func() (*awsCredentials, error)
+	now         func() time.Time
+}
+
+// Name implements Provider interface. See Provider for more information.
+func (self *awsProvider) Name() string {
+	return ProviderAWS
+}
+
+// Wrap implements Provider interface. See Provider for more information.
+func (self *awsProvider) Wrap(key []byte) ([]byte, error) {
+	response := struct {
+		CiphertextBlob []byte
+	}{}
+	err := self.call("Encrypt", map[string]interface{}{"KeyId": self.key, "Plaintext": key}, &response)
+	return response.CiphertextBlob, err
+}
+
+// Unwrap implements Provider interface. See Provider for more information.
+func (self *awsProvider) Unwrap(wrapped []byte) ([]byte, error) {
+	response := struct {
+		Plaintext []byte
+	}{}
+	err := self.call("Decrypt", map[string]interface{}{"KeyId": self.key, "CiphertextBlob": wrapped}, &response)
+	return response.Plaintext, err
+}
+
+func (self *awsProvider) call(action string, body, result interface{}) error {
+	credentials, err := self.credentials()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, self.endpoint+"/", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", awsContentType)
+	request.Header.Set("X-Amz-Target", "TrentService."+action)
+	signRequest(request, data, credentials, self.region, awsService, self.now())
+	return do(request, result)
+}
+
+// Signs request with AWS Signature Version 4. All headers set on the request are signed.
+// See: https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html
+func signRequest(request *http.Request, body []byte, credentials *awsCredentials, region, service string,
+	now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	request.Header.Set("X-Amz-Date", amzDate)
+	if len(credentials.SessionToken) > 0 {
+		request.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": request.URL.Host}
+	names := []string{"host"}
+	for name, values := range request.Header {
+		name = strings.ToLower(name)
+		headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := new(strings.Builder)
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := request.URL.EscapedPath()
+	if len(path) == 0 {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		path,
+		strings.Replace(request.URL.Query().Encode(), "+", "%20", -1),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + credentials.SecretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.AccessKeyID, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))))
+}
+
+func hashHex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// Returns credentials provider based on environment variables. Credentials of the assumed role are cached until
+// shortly before they expire.
+func getAWSCredentials(region string) (func() (*awsCredentials, error), error) {
+	if accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID"); len(accessKeyID) > 0 {
+		credentials := &awsCredentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		return func() (*awsCredentials, error) { return credentials, nil }, nil
+	}
+
+	roleARN, tokenFile := os.Getenv("AWS_ROLE_ARN"), os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if len(roleARN) == 0 || len(tokenFile) == 0 {
+		return nil, fmt.Errorf("AWS credentials are not set, set AWS_ACCESS_KEY_ID or AWS_ROLE_ARN and " +
+			"AWS_WEB_IDENTITY_TOKEN_FILE environment variables")
+	}
+
+	var cached *awsCredentials
+	var mux sync.Mutex
+	return func() (*awsCredentials, error) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		if cached != nil && time.Now().Before(cached.Expiration.Add(-expiryDelta)) {
+			return cached, nil
+		}
+
+		credentials, err := assumeRoleWithWebIdentity(fmt.Sprintf("https://sts.%s.amazonaws.com/", region), roleARN,
+			tokenFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cached = credentials
+		return cached, nil
+	}, nil
+}
+
+// Exchanges web identity token for credentials of given role. Request does not have to be signed.
+func assumeRoleWithWebIdentity(endpoint, roleARN, tokenFile string) (*awsCredentials, error) {
+	token, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {awsSTSVersion},
+		"RoleArn":          {roleARN},
+		"RoleSessionName":  {awsRoleSessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+
+	response, err := httpClient.PostForm(endpoint, query)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return nil, &ResponseError{Code: response.StatusCode, Body: string(data)}
+	}
+
+	result := struct {
+		Credentials awsCredentials `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}{}
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Credentials, nil
+}
+
+func newAWSProvider(key string) (*awsProvider, error) {
+	region := os.Getenv("AWS_REGION")
+	if len(region) == 0 {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	// Key ARN has format 'arn:aws:kms:region:account:key/id'.
+	if parts := strings.Split(key, ":"); len(parts) > 3 && parts[0] == "arn" {
+		region = parts[3]
+	}
+
+	if len(region) == 0 {
+		return nil, fmt.Errorf("AWS region is not set, use key ARN or set AWS_REGION environment variable")
+	}
+
+	credentials, err := getAWSCredentials(region)
+	if err != nil {
+		return nil, err
+	}
+
+	return &awsProvider{
+		key:         key,
+		region:      region,
+		endpoint:    fmt.Sprintf("https://kms.%s.amazonaws.com", region),
+		credentials: credentials,
+		now:         time.Now,
+	}, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	azureAPIVersion = "7.3"
+	azureAlgorithm  = "RSA-OAEP-256"
+	// Token of the managed identity of the node issued by Azure Instance Metadata Service.
+	azureTokenURL = "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=" +
+		"https%3A%2F%2Fvault.azure.net"
+)
+
+// Implements Provider interface using Azure Key Vault. Key is an URL of RSA key, i.e.
+// 'https://vault-name.vault.azure.net/keys/key-name/key-version'. Managed identity given by AZURE_CLIENT_ID
+// environment variable is used if there is more than one assigned to the node.
+type azureProvider struct {
+	key    string
+	tokens *tokenSource
+}
+
+// Name implements Provider interface. See Provider for more information.
+func (self *azureProvider) Name() string {
+	return ProviderAzure
+}
+
+// Wrap implements Provider interface. See Provider for more information.
+func (self *azureProvider) Wrap(key []byte) ([]byte, error) {
+	return self.call("wrapkey", key)
+}
+
+// Unwrap implements Provider interface. See Provider for more information.
+func (self *azureProvider) Unwrap(wrapped []byte) ([]byte, error) {
+	return self.call("unwrapkey", wrapped)
+}
+
+// Key Vault expects values encoded with unpadded base64url encoding.
+func (self *azureProvider) call(operation string, value []byte) ([]byte, error) {
+	token, err := self.tokens.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(map[string]string{
+		"alg":   azureAlgorithm,
+		"value": base64.RawURLEncoding.EncodeToString(value),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost,
+		self.key+"/"+operation+"?api-version="+azureAPIVersion, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Content-Type", "application/json")
+
+	response := struct {
+		Value string `json:"value"`
+	}{}
+	if err := do(request, &response); err != nil {
+		return nil, err
+	}
+
+	return base64.RawURLEncoding.DecodeString(response.Value)
+}
+
+func newAzureProvider(key string) *azureProvider {
+	tokenURL := azureTokenURL
+	if clientID := os.Getenv("AZURE_CLIENT_ID"); len(clientID) > 0 {
+		tokenURL += "&client_id=" + url.QueryEscape(clientID)
+	}
+
+	return &azureProvider{
+		key: strings.TrimRight(key, "/"),
+		tokens: &tokenSource{fetch: func() (string, time.Duration, error) {
+			return fetchToken(tokenURL, map[string]string{"Metadata": "true"})
+		}},
+	}
+}
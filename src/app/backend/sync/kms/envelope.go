@@ -0,0 +1,228 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	syncApi "github.com/kubernetes/dashboard/src/app/backend/sync/api"
+)
+
+const (
+	// Prefix of values encrypted with data encryption key.
+	ciphertextPrefix = "kms:v1:"
+	// Entry of the secret holding data encryption key wrapped by KMS.
+	dataKeyEntry = "kms.dek"
+	// Size of data encryption key. 32 bytes select AES-256.
+	dataKeySize = 32
+)
+
+// Implements Synchronizer interface. It wraps secret synchronizer and envelope-encrypts the secret data. Every write
+// generates new data encryption key, which encrypts the values with AES-GCM and is stored in the secret wrapped by
+// KMS. Secret data can not be decrypted without access to KMS. Secrets without wrapped key are passed through, so
+// the ones created before KMS was enabled keep working until next update.
+type envelopeSynchronizer struct {
+	syncApi.Synchronizer
+
+	provider Provider
+	// Last unwrapped data encryption key, so KMS is not called every time the secret is read.
+	wrappedKey []byte
+	key        []byte
+	mux        sync.Mutex
+}
+
+// Create implements Synchronizer interface. See Synchronizer for more information.
+func (self *envelopeSynchronizer) Create(obj runtime.Object) error {
+	secret, err := self.encrypt(obj)
+	if err != nil {
+		return err
+	}
+
+	return self.Synchronizer.Create(secret)
+}
+
+// Update implements Synchronizer interface. See Synchronizer for more information.
+func (self *envelopeSynchronizer) Update(obj runtime.Object) error {
+	secret, err := self.encrypt(obj)
+	if err != nil {
+		return err
+	}
+
+	return self.Synchronizer.Update(secret)
+}
+
+// Get implements Synchronizer interface. See Synchronizer for more information. Nil is returned if the secret can
+// not be decrypted, i.e. because KMS is unavailable.
+func (self *envelopeSynchronizer) Get() runtime.Object {
+	obj := self.Synchronizer.Get()
+	if obj == nil {
+		return nil
+	}
+
+	secret, err := self.decrypt(obj)
+	if err != nil {
+		log.Printf("KMS synchronizer %s failed to decrypt secret: %s", self.Name(), err.Error())
+		return nil
+	}
+
+	return secret
+}
+
+// RegisterActionHandler implements Synchronizer interface. See Synchronizer for more information. Handlers receive
+// decrypted secret and are skipped if it can not be decrypted.
+func (self *envelopeSynchronizer) RegisterActionHandler(handler syncApi.ActionHandlerFunction,
+	events ...watch.EventType) {
+	self.Synchronizer.RegisterActionHandler(func(obj runtime.Object) {
+		secret, err := self.decrypt(obj)
+		if err != nil {
+			log.Printf("KMS synchronizer %s failed to decrypt secret: %s", self.Name(), err.Error())
+			return
+		}
+
+		handler(secret)
+	}, events...)
+}
+
+func (self *envelopeSynchronizer) encrypt(obj runtime.Object) (*v1.Secret, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := obj.(*v1.Secret).DeepCopy()
+	for entry, value := range secret.Data {
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+
+		// Entry name is authenticated, so values can not be swapped between entries.
+		sealed := aead.Seal(nonce, nonce, value, []byte(entry))
+		secret.Data[entry] = []byte(ciphertextPrefix + base64.StdEncoding.EncodeToString(sealed))
+	}
+
+	wrappedKey, err := self.provider.Wrap(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[dataKeyEntry] = wrappedKey
+	self.cacheKey(wrappedKey, key)
+	return secret, nil
+}
+
+func (self *envelopeSynchronizer) decrypt(obj runtime.Object) (*v1.Secret, error) {
+	secret, ok := obj.(*v1.Secret)
+	if !ok {
+		return nil, nil
+	}
+
+	secret = secret.DeepCopy()
+	wrappedKey, ok := secret.Data[dataKeyEntry]
+	if !ok {
+		return secret, nil
+	}
+	delete(secret.Data, dataKeyEntry)
+
+	key, err := self.unwrapKey(wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for entry, value := range secret.Data {
+		if !strings.HasPrefix(string(value), ciphertextPrefix) {
+			continue
+		}
+
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(value), ciphertextPrefix))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(sealed) < aead.NonceSize() {
+			return nil, fmt.Errorf("value of %s entry is too short", entry)
+		}
+
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		if secret.Data[entry], err = aead.Open(nil, nonce, ciphertext, []byte(entry)); err != nil {
+			return nil, err
+		}
+	}
+
+	return secret, nil
+}
+
+func (self *envelopeSynchronizer) unwrapKey(wrappedKey []byte) ([]byte, error) {
+	self.mux.Lock()
+	if bytes.Equal(self.wrappedKey, wrappedKey) {
+		defer self.mux.Unlock()
+		return self.key, nil
+	}
+	self.mux.Unlock()
+
+	key, err := self.provider.Unwrap(wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	self.cacheKey(wrappedKey, key)
+	return key, nil
+}
+
+func (self *envelopeSynchronizer) cacheKey(wrappedKey, key []byte) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.wrappedKey, self.key = wrappedKey, key
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// NewEnvelopeSynchronizer wraps given secret synchronizer, so the secret data is envelope-encrypted with key
+// managed by given KMS provider.
+func NewEnvelopeSynchronizer(provider Provider, secret syncApi.Synchronizer) syncApi.Synchronizer {
+	return &envelopeSynchronizer{Synchronizer: secret, provider: provider}
+}
@@ -0,0 +1,155 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	syncApi "github.com/kubernetes/dashboard/src/app/backend/sync/api"
+)
+
+// Fake provider wrapping keys by reversing them.
+type fakeProvider struct {
+	unwraps     int
+	unavailable bool
+}
+
+func (self *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (self *fakeProvider) Wrap(key []byte) ([]byte, error) {
+	return reverse(key), nil
+}
+
+func (self *fakeProvider) Unwrap(wrapped []byte) ([]byte, error) {
+	if self.unavailable {
+		return nil, errors.New("KMS is unavailable")
+	}
+
+	self.unwraps++
+	return reverse(wrapped), nil
+}
+
+func reverse(data []byte) []byte {
+	reversed := make([]byte, len(data))
+	for i, b := range data {
+		reversed[len(data)-1-i] = b
+	}
+	return reversed
+}
+
+// Fake synchronizer holding secret in memory.
+type fakeSecretSynchronizer struct {
+	syncApi.Synchronizer
+	secret *v1.Secret
+}
+
+func (self *fakeSecretSynchronizer) Create(obj runtime.Object) error {
+	self.secret = obj.(*v1.Secret)
+	return nil
+}
+
+func (self *fakeSecretSynchronizer) Update(obj runtime.Object) error {
+	return self.Create(obj)
+}
+
+func (self *fakeSecretSynchronizer) Get() runtime.Object {
+	if self.secret == nil {
+		return nil
+	}
+	return self.secret
+}
+
+func (self *fakeSecretSynchronizer) Name() string {
+	return "fake"
+}
+
+func newTestSecret(data map[string][]byte) *v1.Secret {
+	return &v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "key-holder", Namespace: "default"}, Data: data}
+}
+
+func TestEnvelopeSynchronizer(t *testing.T) {
+	provider := &fakeProvider{}
+	secret := &fakeSecretSynchronizer{}
+	synchronizer := NewEnvelopeSynchronizer(provider, secret)
+
+	data := map[string][]byte{"priv": []byte("private key"), "pub": []byte("public key")}
+	if err := synchronizer.Create(newTestSecret(data)); err != nil {
+		t.Fatalf("Create failed: %s", err.Error())
+	}
+
+	if _, ok := secret.secret.Data[dataKeyEntry]; !ok {
+		t.Fatalf("Expected wrapped data key to be stored in %s entry", dataKeyEntry)
+	}
+
+	for entry, value := range data {
+		if stored := secret.secret.Data[entry]; bytes.Contains(stored, value) ||
+			!strings.HasPrefix(string(stored), ciphertextPrefix) {
+			t.Fatalf("Expected %s entry to be encrypted, got %s", entry, stored)
+		}
+	}
+
+	decrypted := synchronizer.Get().(*v1.Secret)
+	if len(decrypted.Data) != len(data) || string(decrypted.Data["priv"]) != "private key" ||
+		string(decrypted.Data["pub"]) != "public key" {
+		t.Fatalf("Unexpected decrypted data: %v", decrypted.Data)
+	}
+
+	if provider.unwraps != 0 {
+		t.Fatalf("Expected data key written by this replica to be cached, got %d unwraps", provider.unwraps)
+	}
+
+	// Swapping encrypted values between entries must be detected.
+	secret.secret.Data["priv"], secret.secret.Data["pub"] = secret.secret.Data["pub"], secret.secret.Data["priv"]
+	if obj := synchronizer.Get(); obj != nil {
+		t.Fatalf("Expected no secret when entries were swapped, got %v", obj)
+	}
+}
+
+func TestEnvelopeSynchronizer_Unavailable(t *testing.T) {
+	secret := &fakeSecretSynchronizer{}
+	_ = NewEnvelopeSynchronizer(&fakeProvider{}, secret).Create(newTestSecret(map[string][]byte{"priv": []byte("key")}))
+
+	// Another replica has to unwrap the data key.
+	provider := &fakeProvider{unavailable: true}
+	synchronizer := NewEnvelopeSynchronizer(provider, secret)
+	if obj := synchronizer.Get(); obj != nil {
+		t.Fatalf("Expected no secret when KMS is unavailable, got %v", obj)
+	}
+
+	provider.unavailable = false
+	for i := 0; i < 2; i++ {
+		if value := string(synchronizer.Get().(*v1.Secret).Data["priv"]); value != "key" {
+			t.Fatalf("Expected decrypted value key, got %s", value)
+		}
+	}
+
+	if provider.unwraps != 1 {
+		t.Fatalf("Expected data key to be unwrapped once, got %d unwraps", provider.unwraps)
+	}
+
+	secret.secret = newTestSecret(map[string][]byte{"priv": []byte("plaintext")})
+	if value := string(synchronizer.Get().(*v1.Secret).Data["priv"]); value != "plaintext" {
+		t.Fatalf("Expected secret without data key to be passed through, got %s", value)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	gcpEndpoint = "https://cloudkms.googleapis.com"
+	// Token of the service account of the node or the one bound with workload identity.
+	gcpTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// Implements Provider interface using GCP Cloud KMS. Key is a resource name of symmetric key, i.e.
+// 'projects/p/locations/global/keyRings/r/cryptoKeys/k'.
+type gcpProvider struct {
+	key      string
+	endpoint string
+	tokens   *tokenSource
+}
+
+// Name implements Provider interface. See Provider for more information.
+func (self *gcpProvider) Name() string {
+	return ProviderGCP
+}
+
+// Wrap implements Provider interface. See Provider for more information.
+func (self *gcpProvider) Wrap(key []byte) ([]byte, error) {
+	response := struct {
+		Ciphertext []byte `json:"ciphertext"`
+	}{}
+	err := self.call("encrypt", map[string][]byte{"plaintext": key}, &response)
+	return response.Ciphertext, err
+}
+
+// Unwrap implements Provider interface. See Provider for more information.
+func (self *gcpProvider) Unwrap(wrapped []byte) ([]byte, error) {
+	response := struct {
+		Plaintext []byte `json:"plaintext"`
+	}{}
+	err := self.call("decrypt", map[string][]byte{"ciphertext": wrapped}, &response)
+	return response.Plaintext, err
+}
+
+func (self *gcpProvider) call(method string, body, result interface{}) error {
+	token, err := self.tokens.Token()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, self.endpoint+"/v1/"+self.key+":"+method, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Content-Type", "application/json")
+	return do(request, result)
+}
+
+func newGCPProvider(key string) *gcpProvider {
+	return &gcpProvider{
+		key:      strings.Trim(key, "/"),
+		endpoint: gcpEndpoint,
+		tokens: &tokenSource{fetch: func() (string, time.Duration, error) {
+			return fetchToken(gcpTokenURL, map[string]string{"Metadata-Flavor": "Google"})
+		}},
+	}
+}
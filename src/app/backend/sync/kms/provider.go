@@ -0,0 +1,155 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// KMS providers selectable with kms-provider argument.
+const (
+	ProviderAWS   = "aws"
+	ProviderGCP   = "gcp"
+	ProviderAzure = "azure"
+)
+
+const (
+	// Maximum time of a single request to KMS or to the metadata server issuing credentials.
+	requestTimeout = 10 * time.Second
+	// Credentials are renewed this long before they expire.
+	expiryDelta = time.Minute
+)
+
+var httpClient = &http.Client{Timeout: requestTimeout}
+
+// Provider wraps data encryption keys with a key managed by a cloud KMS. Wrapped keys can only be unwrapped by
+// calling KMS, so anything encrypted with them is useless without access to KMS.
+type Provider interface {
+	// Name returns name of the provider.
+	Name() string
+	// Wrap encrypts given data encryption key.
+	Wrap(key []byte) ([]byte, error)
+	// Unwrap decrypts given data encryption key previously encrypted by Wrap.
+	Unwrap(wrapped []byte) ([]byte, error)
+}
+
+// NewProvider creates provider of given name using given KMS key.
+func NewProvider(name, key string) (Provider, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("key of %s KMS provider is not set", name)
+	}
+
+	switch name {
+	case ProviderAWS:
+		return newAWSProvider(key)
+	case ProviderGCP:
+		return newGCPProvider(key), nil
+	case ProviderAzure:
+		return newAzureProvider(key), nil
+	}
+
+	return nil, fmt.Errorf("unsupported KMS provider %s", name)
+}
+
+// ResponseError is returned when KMS or metadata server responds with an error status.
+type ResponseError struct {
+	Code int
+	Body string
+}
+
+// Error implements error interface.
+func (self *ResponseError) Error() string {
+	return fmt.Sprintf("KMS responded with %d: %s", self.Code, self.Body)
+}
+
+// Sends given request and decodes JSON response into result.
+func do(request *http.Request, result interface{}) error {
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return &ResponseError{Code: response.StatusCode, Body: string(data)}
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+// Caches access token issued by metadata server of the cloud until shortly before it expires.
+type tokenSource struct {
+	fetch  func() (string, time.Duration, error)
+	token  string
+	expiry time.Time
+	mux    sync.Mutex
+}
+
+func (self *tokenSource) Token() (string, error) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if len(self.token) > 0 && time.Now().Before(self.expiry) {
+		return self.token, nil
+	}
+
+	token, expiresIn, err := self.fetch()
+	if err != nil {
+		return "", err
+	}
+
+	self.token, self.expiry = token, time.Now().Add(expiresIn-expiryDelta)
+	return self.token, nil
+}
+
+// Access token response of metadata servers. Azure returns expiration as a string, GCP as a number.
+type tokenResponse struct {
+	AccessToken string      `json:"access_token"`
+	ExpiresIn   json.Number `json:"expires_in"`
+}
+
+// Fetches access token from given metadata server URL with given headers.
+func fetchToken(url string, headers map[string]string) (string, time.Duration, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", 0, err
+	}
+
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	response := new(tokenResponse)
+	if err := do(request, response); err != nil {
+		return "", 0, err
+	}
+
+	expiresIn, err := response.ExpiresIn.Int64()
+	if err != nil {
+		return "", 0, err
+	}
+
+	return response.AccessToken, time.Duration(expiresIn) * time.Second, nil
+}
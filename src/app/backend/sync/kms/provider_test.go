@@ -0,0 +1,199 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignRequest(t *testing.T) {
+	// Test vector 'get-vanilla' of AWS Signature Version 4 test suite.
+	request, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	credentials := &awsCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	signRequest(request, nil, credentials, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if authorization := request.Header.Get("Authorization"); authorization != expected {
+		t.Fatalf("Expected authorization header %s, got %s", expected, authorization)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	cases := []struct {
+		name  string
+		key   string
+		valid bool
+	}{
+		{ProviderGCP, "projects/p/locations/global/keyRings/r/cryptoKeys/k", true},
+		{ProviderAzure, "https://vault.vault.azure.net/keys/k/v", true},
+		{ProviderGCP, "", false},
+		{"ibm", "key", false},
+	}
+
+	for _, c := range cases {
+		provider, err := NewProvider(c.name, c.key)
+		if (err == nil) != c.valid {
+			t.Fatalf("NewProvider(%s, %s): expected valid %t, got error %v", c.name, c.key, c.valid, err)
+		}
+
+		if c.valid && provider.Name() != c.name {
+			t.Fatalf("Expected provider %s, got %s", c.name, provider.Name())
+		}
+	}
+}
+
+func staticTokens() *tokenSource {
+	return &tokenSource{fetch: func() (string, time.Duration, error) { return "token", time.Hour, nil }}
+}
+
+func TestAWSProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") ||
+			r.Header.Get("X-Amz-Security-Token") != "session" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		body := make(map[string][]byte)
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"CiphertextBlob": append([]byte("wrapped:"),
+				body["Plaintext"]...)})
+		case "TrentService.Decrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": body["CiphertextBlob"][len("wrapped:"):]})
+		}
+	}))
+	defer server.Close()
+
+	provider := &awsProvider{
+		key:      "alias/dashboard",
+		region:   "us-east-1",
+		endpoint: server.URL,
+		credentials: func() (*awsCredentials, error) {
+			return &awsCredentials{AccessKeyID: "key", SecretAccessKey: "secret", SessionToken: "session"}, nil
+		},
+		now: time.Now,
+	}
+
+	testProviderRoundTrip(t, provider)
+}
+
+func TestGCPProvider(t *testing.T) {
+	key := "projects/p/locations/global/keyRings/r/cryptoKeys/k"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body := make(map[string][]byte)
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/v1/" + key + ":encrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"ciphertext": append([]byte("wrapped:"),
+				body["plaintext"]...)})
+		case "/v1/" + key + ":decrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"plaintext": body["ciphertext"][len("wrapped:"):]})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := newGCPProvider(key)
+	provider.endpoint = server.URL
+	provider.tokens = staticTokens()
+	testProviderRoundTrip(t, provider)
+}
+
+func TestAzureProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make(map[string]string)
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.Header.Get("Authorization") != "Bearer token" || body["alg"] != azureAlgorithm ||
+			r.URL.Query().Get("api-version") != azureAPIVersion {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		value, err := base64.RawURLEncoding.DecodeString(body["value"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/keys/k/v/wrapkey":
+			value = append([]byte("wrapped:"), value...)
+		case "/keys/k/v/unwrapkey":
+			value = value[len("wrapped:"):]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"value": base64.RawURLEncoding.EncodeToString(value)})
+	}))
+	defer server.Close()
+
+	provider := newAzureProvider(server.URL + "/keys/k/v/")
+	provider.tokens = staticTokens()
+	testProviderRoundTrip(t, provider)
+}
+
+func testProviderRoundTrip(t *testing.T, provider Provider) {
+	wrapped, err := provider.Wrap([]byte("data key"))
+	if err != nil {
+		t.Fatalf("%s: wrap failed: %s", provider.Name(), err.Error())
+	}
+
+	if string(wrapped) != "wrapped:data key" {
+		t.Fatalf("%s: unexpected wrapped key %s", provider.Name(), wrapped)
+	}
+
+	key, err := provider.Unwrap(wrapped)
+	if err != nil {
+		t.Fatalf("%s: unwrap failed: %s", provider.Name(), err.Error())
+	}
+
+	if string(key) != "data key" {
+		t.Fatalf("%s: unexpected unwrapped key %s", provider.Name(), key)
+	}
+}
+
+func TestFetchToken(t *testing.T) {
+	for _, expiresIn := range []string{`3600`, `"3600"`} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Metadata") != "true" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token": "token", "expires_in": ` + expiresIn + `}`))
+		}))
+
+		token, ttl, err := fetchToken(server.URL, map[string]string{"Metadata": "true"})
+		server.Close()
+		if err != nil || token != "token" || ttl != time.Hour {
+			t.Fatalf("Expected token valid for an hour, got %s, %s, %v", token, ttl, err)
+		}
+	}
+}
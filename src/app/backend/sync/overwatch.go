@@ -17,6 +17,8 @@ package sync
 import (
 	"fmt"
 	"log"
+	"sort"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -58,6 +60,8 @@ const (
 )
 
 type overwatch struct {
+	// mux guards maps below, as they are read by status requests while synchronizers are restarted.
+	mux          sync.RWMutex
 	syncMap      map[string]syncApi.Synchronizer
 	policyMap    map[string]RestartPolicy
 	restartCount map[string]int
@@ -68,16 +72,47 @@ type overwatch struct {
 
 // RegisterSynchronizer registers given synchronizer with given restart policy.
 func (self *overwatch) RegisterSynchronizer(synchronizer syncApi.Synchronizer, policy RestartPolicy) {
+	self.mux.Lock()
 	if _, exists := self.syncMap[synchronizer.Name()]; exists {
+		self.mux.Unlock()
 		log.Printf("Synchronizer %s is already registered. Skipping", synchronizer.Name())
 		return
 	}
 
 	self.syncMap[synchronizer.Name()] = synchronizer
 	self.policyMap[synchronizer.Name()] = policy
+	self.mux.Unlock()
 	self.broadcastRegistrationEvent(synchronizer.Name())
 }
 
+// SynchronizerStatus describes state of a synchronizer registered in overwatch.
+type SynchronizerStatus struct {
+	Name string `json:"name"`
+	// Synced is true when local copy of synchronized object is available.
+	Synced        bool          `json:"synced"`
+	RestartPolicy RestartPolicy `json:"restartPolicy"`
+	RestartCount  int           `json:"restartCount"`
+}
+
+// Status returns status of all registered synchronizers ordered by name.
+func (self *overwatch) Status() []SynchronizerStatus {
+	self.mux.RLock()
+	defer self.mux.RUnlock()
+
+	result := make([]SynchronizerStatus, 0)
+	for name, synchronizer := range self.syncMap {
+		result = append(result, SynchronizerStatus{
+			Name:          name,
+			Synced:        synchronizer.Get() != nil,
+			RestartPolicy: self.policyMap[name],
+			RestartCount:  self.restartCount[name],
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
 // Run starts overwatch.
 func (self *overwatch) Run() {
 	self.monitorRegistrationEvents()
@@ -88,12 +123,14 @@ func (self *overwatch) monitorRestartEvents() {
 	go wait.Forever(func() {
 		select {
 		case name := <-self.restartSignal:
-			if self.restartCount[name] > MaxRestartCount {
+			self.mux.RLock()
+			restartCount, synchronizer := self.restartCount[name], self.syncMap[name]
+			self.mux.RUnlock()
+			if restartCount > MaxRestartCount {
 				panic(fmt.Sprintf("synchronizer %s restart limit execeeded. Restarting pod.", name))
 			}
 
 			log.Printf("Restarting synchronizer: %s.", name)
-			synchronizer := self.syncMap[name]
 			synchronizer.Start()
 			self.monitorSynchronizerStatus(synchronizer)
 		}
@@ -104,7 +141,9 @@ func (self *overwatch) monitorRegistrationEvents() {
 	go wait.Forever(func() {
 		select {
 		case name := <-self.registrationSignal:
+			self.mux.RLock()
 			synchronizer := self.syncMap[name]
+			self.mux.RUnlock()
 			log.Printf("New synchronizer has been registered: %s. Starting", name)
 			self.monitorSynchronizerStatus(synchronizer)
 			synchronizer.Start()
@@ -119,11 +158,16 @@ func (self *overwatch) monitorSynchronizerStatus(synchronizer syncApi.Synchroniz
 		select {
 		case err := <-synchronizer.Error():
 			log.Printf("Synchronizer %s exited with error: %s", name, err.Error())
-			if self.policyMap[name] == AlwaysRestart {
+			self.mux.RLock()
+			policy := self.policyMap[name]
+			self.mux.RUnlock()
+			if policy == AlwaysRestart {
 				// Wait a sec before restarting synchronizer in case it exited with error.
 				time.Sleep(RestartDelay)
 				self.broadcastRestartEvent(name)
+				self.mux.Lock()
 				self.restartCount[name]++
+				self.mux.Unlock()
 			}
 
 			close(stopCh)
@@ -0,0 +1,184 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Maximum time of a single request to Vault.
+	requestTimeout = 10 * time.Second
+	// Service account token Dashboard logs in with when Vault token file is not set.
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	// Login path of Vault Kubernetes auth method.
+	kubernetesLoginPath = "auth/kubernetes/login"
+)
+
+// Config holds address of Vault and credentials Dashboard logs in with. Token file is used if it is set, otherwise
+// Dashboard logs in with Kubernetes auth method using the service account token and given role.
+type Config struct {
+	Address   string
+	TokenFile string
+	Role      string
+	// JWTFile is the service account token used by Kubernetes auth method. Defaults to the mounted token.
+	JWTFile string
+}
+
+// Client is a minimal client of Vault HTTP API. It logs in lazily and logs in again once the token is rejected.
+type Client struct {
+	config Config
+	http   *http.Client
+	token  string
+	mux    sync.Mutex
+}
+
+// ResponseError is returned when Vault responds with an error status.
+type ResponseError struct {
+	Code   int      `json:"-"`
+	Errors []string `json:"errors"`
+}
+
+// Error implements error interface.
+func (self *ResponseError) Error() string {
+	return fmt.Sprintf("vault responded with %d: %s", self.Code, strings.Join(self.Errors, ", "))
+}
+
+// IsNotFound returns true if Vault responded that requested path does not exist.
+func IsNotFound(err error) bool {
+	responseErr, ok := err.(*ResponseError)
+	return ok && responseErr.Code == http.StatusNotFound
+}
+
+// Do sends request with JSON body to given path of Vault API and decodes JSON response into result, if it is not
+// nil. Request is retried once with a new token if the current one is rejected, i.e. because it has expired.
+func (self *Client) Do(method, path string, body, result interface{}) error {
+	token, err := self.getToken(false)
+	if err != nil {
+		return err
+	}
+
+	err = self.do(method, path, token, body, result)
+	if responseErr, ok := err.(*ResponseError); ok && responseErr.Code == http.StatusForbidden {
+		if token, err = self.getToken(true); err != nil {
+			return err
+		}
+
+		err = self.do(method, path, token, body, result)
+	}
+
+	return err
+}
+
+func (self *Client) do(method, path, token string, body, result interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		marshalled, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(marshalled)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	request, err := http.NewRequest(method, strings.TrimRight(self.config.Address, "/")+"/v1/"+path, reader)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if len(token) > 0 {
+		request.Header.Set("X-Vault-Token", token)
+	}
+
+	response, err := self.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		responseErr := &ResponseError{Code: response.StatusCode}
+		_ = json.Unmarshal(data, responseErr)
+		return responseErr
+	}
+
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+// Returns cached token, reading or logging in for a new one if there is none or renew is set.
+func (self *Client) getToken(renew bool) (string, error) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if len(self.token) > 0 && !renew {
+		return self.token, nil
+	}
+
+	if len(self.config.TokenFile) > 0 {
+		token, err := ioutil.ReadFile(self.config.TokenFile)
+		if err != nil {
+			return "", err
+		}
+
+		self.token = strings.TrimSpace(string(token))
+		return self.token, nil
+	}
+
+	jwt, err := ioutil.ReadFile(self.config.JWTFile)
+	if err != nil {
+		return "", err
+	}
+
+	login := struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}{}
+	err = self.do(http.MethodPost, kubernetesLoginPath, "",
+		map[string]string{"role": self.config.Role, "jwt": strings.TrimSpace(string(jwt))}, &login)
+	if err != nil {
+		return "", err
+	}
+
+	self.token = login.Auth.ClientToken
+	return self.token, nil
+}
+
+// NewClient creates Vault client based on given config.
+func NewClient(config Config) *Client {
+	if len(config.JWTFile) == 0 {
+		config.JWTFile = serviceAccountTokenFile
+	}
+
+	return &Client{config: config, http: &http.Client{Timeout: requestTimeout}}
+}
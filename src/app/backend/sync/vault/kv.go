@@ -0,0 +1,288 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+
+	syncApi "github.com/kubernetes/dashboard/src/app/backend/sync/api"
+)
+
+// Time interval between which secret stored in Vault should be resynchronized. It is shorter than the one of
+// Kubernetes secrets as changes made by other replicas are not watched.
+const kvSyncPeriod = time.Minute
+
+// Resource reported in conflict errors, so they are handled the same way as conflicts of Kubernetes secrets.
+var secretResource = schema.GroupResource{Resource: "secrets"}
+
+// Data stored in Vault KV version 2 secret engine. Byte values are stored base64 encoded.
+type kvData struct {
+	Data        map[string][]byte `json:"data"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type kvReadResponse struct {
+	Data struct {
+		Data     kvData `json:"data"`
+		Metadata struct {
+			CreatedTime time.Time `json:"created_time"`
+			Version     int       `json:"version"`
+		} `json:"metadata"`
+	} `json:"data"`
+}
+
+// Implements Synchronizer interface. It stores secret in Vault KV version 2 secret engine and presents it as
+// Kubernetes secret, so existing handlers of synchronized secrets can be reused. Secret version is exposed as its
+// resource version and used for check-and-set writes, so concurrent updates result in conflicts.
+type kvSynchronizer struct {
+	namespace string
+	name      string
+	mount     string
+	path      string
+
+	secret         *v1.Secret
+	client         *Client
+	actionHandlers map[watch.EventType][]syncApi.ActionHandlerFunction
+	errChan        chan error
+
+	mux sync.Mutex
+}
+
+// Name implements Synchronizer interface. See Synchronizer for more information.
+func (self *kvSynchronizer) Name() string {
+	return fmt.Sprintf("vault-%s-%s", self.mount, strings.Replace(self.path, "/", "-", -1))
+}
+
+// Start implements Synchronizer interface. See Synchronizer for more information.
+func (self *kvSynchronizer) Start() {
+	self.errChan = make(chan error)
+
+	go func() {
+		log.Printf("Starting Vault synchronizer for %s in mount %s", self.path, self.mount)
+		ticker := time.NewTicker(kvSyncPeriod)
+		defer ticker.Stop()
+		for {
+			self.sync()
+			<-ticker.C
+		}
+	}()
+}
+
+// Error implements Synchronizer interface. See Synchronizer for more information.
+func (self *kvSynchronizer) Error() chan error {
+	return self.errChan
+}
+
+// Create implements Synchronizer interface. See Synchronizer for more information.
+func (self *kvSynchronizer) Create(obj runtime.Object) error {
+	secret := self.getSecret(obj)
+	if err := self.write(secret, new(int)); err != nil {
+		if k8serrors.IsConflict(err) {
+			return k8serrors.NewAlreadyExists(secretResource, self.name)
+		}
+
+		return err
+	}
+
+	self.Refresh()
+	return nil
+}
+
+// Get implements Synchronizer interface. See Synchronizer for more information.
+func (self *kvSynchronizer) Get() runtime.Object {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if self.secret == nil {
+		// In case secret was not yet initialized try to do it synchronously
+		secret, err := self.read()
+		if err != nil || secret == nil {
+			return nil
+		}
+
+		log.Printf("Initializing Vault synchronizer synchronously using %s from mount %s", self.path, self.mount)
+		self.secret = secret
+	}
+
+	return self.secret
+}
+
+// Update implements Synchronizer interface. See Synchronizer for more information. Secret without resource version
+// overwrites the stored one unconditionally, the same way as Kubernetes does.
+func (self *kvSynchronizer) Update(obj runtime.Object) error {
+	secret := self.getSecret(obj)
+
+	var cas *int
+	if len(secret.ResourceVersion) > 0 {
+		version, err := strconv.Atoi(secret.ResourceVersion)
+		if err != nil {
+			return k8serrors.NewBadRequest(fmt.Sprintf("invalid resource version %s", secret.ResourceVersion))
+		}
+		cas = &version
+	}
+
+	if err := self.write(secret, cas); err != nil {
+		return err
+	}
+
+	self.Refresh()
+	return nil
+}
+
+// Delete implements Synchronizer interface. See Synchronizer for more information.
+func (self *kvSynchronizer) Delete() error {
+	err := self.client.Do(http.MethodDelete, self.mount+"/metadata/"+self.path, nil, nil)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	self.mux.Lock()
+	self.secret = nil
+	self.mux.Unlock()
+	return nil
+}
+
+// RegisterActionHandler implements Synchronizer interface. See Synchronizer for more information.
+func (self *kvSynchronizer) RegisterActionHandler(handler syncApi.ActionHandlerFunction, events ...watch.EventType) {
+	for _, ev := range events {
+		self.actionHandlers[ev] = append(self.actionHandlers[ev], handler)
+	}
+}
+
+// Refresh implements Synchronizer interface. See Synchronizer for more information.
+func (self *kvSynchronizer) Refresh() {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	secret, err := self.read()
+	if err != nil {
+		log.Printf("Vault synchronizer %s failed to refresh secret: %s", self.Name(), err.Error())
+		return
+	}
+
+	self.secret = secret
+}
+
+// SetPoller implements Synchronizer interface. See Synchronizer for more information. Vault is always polled
+// directly, so custom pollers are ignored.
+func (self *kvSynchronizer) SetPoller(poller syncApi.Poller) {}
+
+func (self *kvSynchronizer) getSecret(obj runtime.Object) *v1.Secret {
+	secret, ok := obj.(*v1.Secret)
+	if !ok {
+		panic("Provided object has to be a secret. Most likely this is a programming error")
+	}
+
+	return secret
+}
+
+// Reads secret from Vault and compares it with local copy. Registered handlers are called in case it has been
+// added, modified or deleted. Vault being unavailable is not treated as deletion.
+func (self *kvSynchronizer) sync() {
+	secret, err := self.read()
+	if err != nil {
+		log.Printf("Vault synchronizer %s failed to read secret: %s", self.Name(), err.Error())
+		return
+	}
+
+	self.mux.Lock()
+	old := self.secret
+	self.secret = secret
+	self.mux.Unlock()
+
+	switch {
+	case old == nil && secret != nil:
+		self.handleEvent(watch.Added, secret)
+	case old != nil && secret == nil:
+		self.handleEvent(watch.Deleted, old)
+	case old != nil && !reflect.DeepEqual(old, secret):
+		self.handleEvent(watch.Modified, secret)
+	}
+}
+
+func (self *kvSynchronizer) handleEvent(event watch.EventType, secret *v1.Secret) {
+	for _, handler := range self.actionHandlers[event] {
+		handler(secret)
+	}
+}
+
+// Reads secret from Vault. Nil is returned if it does not exist or its latest version has been deleted.
+func (self *kvSynchronizer) read() (*v1.Secret, error) {
+	response := new(kvReadResponse)
+	err := self.client.Do(http.MethodGet, self.mount+"/data/"+self.path, nil, response)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:              self.name,
+			Namespace:         self.namespace,
+			ResourceVersion:   strconv.Itoa(response.Data.Metadata.Version),
+			CreationTimestamp: metaV1.NewTime(response.Data.Metadata.CreatedTime),
+			Annotations:       response.Data.Data.Annotations,
+		},
+		Data: response.Data.Data.Data,
+	}, nil
+}
+
+// Writes secret to Vault. If cas is set the write succeeds only if current version of the secret matches it,
+// otherwise conflict is returned. Version 0 means that the secret must not exist yet.
+func (self *kvSynchronizer) write(secret *v1.Secret, cas *int) error {
+	body := map[string]interface{}{
+		"data": kvData{Data: secret.Data, Annotations: secret.Annotations},
+	}
+	if cas != nil {
+		body["options"] = map[string]int{"cas": *cas}
+	}
+
+	err := self.client.Do(http.MethodPost, self.mount+"/data/"+self.path, body, nil)
+	if responseErr, ok := err.(*ResponseError); ok && responseErr.Code == http.StatusBadRequest &&
+		strings.Contains(strings.Join(responseErr.Errors, " "), "check-and-set") {
+		return k8serrors.NewConflict(secretResource, self.name, err)
+	}
+
+	return err
+}
+
+// NewKVSynchronizer creates synchronizer of secret stored under given path of Vault KV version 2 secret engine
+// mounted at given mount. Synchronized object is presented as secret with given name and namespace.
+func NewKVSynchronizer(client *Client, mount, path, namespace, name string) syncApi.Synchronizer {
+	return &kvSynchronizer{
+		namespace:      namespace,
+		name:           name,
+		mount:          strings.Trim(mount, "/"),
+		path:           strings.Trim(path, "/"),
+		client:         client,
+		actionHandlers: make(map[watch.EventType][]syncApi.ActionHandlerFunction),
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"encoding/base64"
+	"log"
+	"net/http"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	syncApi "github.com/kubernetes/dashboard/src/app/backend/sync/api"
+)
+
+// Prefix of ciphertexts produced by Vault transit secret engine.
+const ciphertextPrefix = "vault:"
+
+// Implements Synchronizer interface. It wraps synchronizer of Kubernetes secret and encrypts its data with Vault
+// transit secret engine, so the secret stored in the cluster is useless without access to Vault. Values that are not
+// encrypted are passed through, so secrets created before switching to Vault keep working until next update.
+type transitSynchronizer struct {
+	syncApi.Synchronizer
+
+	client *Client
+	mount  string
+	key    string
+}
+
+// Create implements Synchronizer interface. See Synchronizer for more information.
+func (self *transitSynchronizer) Create(obj runtime.Object) error {
+	secret, err := self.encrypt(obj)
+	if err != nil {
+		return err
+	}
+
+	return self.Synchronizer.Create(secret)
+}
+
+// Update implements Synchronizer interface. See Synchronizer for more information.
+func (self *transitSynchronizer) Update(obj runtime.Object) error {
+	secret, err := self.encrypt(obj)
+	if err != nil {
+		return err
+	}
+
+	return self.Synchronizer.Update(secret)
+}
+
+// Get implements Synchronizer interface. See Synchronizer for more information. Nil is returned if the secret can
+// not be decrypted, i.e. because Vault is unavailable.
+func (self *transitSynchronizer) Get() runtime.Object {
+	obj := self.Synchronizer.Get()
+	if obj == nil {
+		return nil
+	}
+
+	secret, err := self.decrypt(obj)
+	if err != nil {
+		log.Printf("Vault synchronizer %s failed to decrypt secret: %s", self.Name(), err.Error())
+		return nil
+	}
+
+	return secret
+}
+
+// RegisterActionHandler implements Synchronizer interface. See Synchronizer for more information. Handlers receive
+// decrypted secret and are skipped if it can not be decrypted.
+func (self *transitSynchronizer) RegisterActionHandler(handler syncApi.ActionHandlerFunction,
+	events ...watch.EventType) {
+	self.Synchronizer.RegisterActionHandler(func(obj runtime.Object) {
+		secret, err := self.decrypt(obj)
+		if err != nil {
+			log.Printf("Vault synchronizer %s failed to decrypt secret: %s", self.Name(), err.Error())
+			return
+		}
+
+		handler(secret)
+	}, events...)
+}
+
+func (self *transitSynchronizer) encrypt(obj runtime.Object) (*v1.Secret, error) {
+	secret := obj.(*v1.Secret).DeepCopy()
+	for key, value := range secret.Data {
+		response := struct {
+			Data struct {
+				Ciphertext string `json:"ciphertext"`
+			} `json:"data"`
+		}{}
+		err := self.client.Do(http.MethodPost, self.mount+"/encrypt/"+self.key,
+			map[string]string{"plaintext": base64.StdEncoding.EncodeToString(value)}, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		secret.Data[key] = []byte(response.Data.Ciphertext)
+	}
+
+	return secret, nil
+}
+
+func (self *transitSynchronizer) decrypt(obj runtime.Object) (*v1.Secret, error) {
+	secret, ok := obj.(*v1.Secret)
+	if !ok {
+		return nil, nil
+	}
+
+	secret = secret.DeepCopy()
+	for key, value := range secret.Data {
+		if !strings.HasPrefix(string(value), ciphertextPrefix) {
+			continue
+		}
+
+		response := struct {
+			Data struct {
+				Plaintext string `json:"plaintext"`
+			} `json:"data"`
+		}{}
+		err := self.client.Do(http.MethodPost, self.mount+"/decrypt/"+self.key,
+			map[string]string{"ciphertext": string(value)}, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		plaintext, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+		if err != nil {
+			return nil, err
+		}
+
+		secret.Data[key] = plaintext
+	}
+
+	return secret, nil
+}
+
+// NewTransitSynchronizer wraps given secret synchronizer, so the secret data is encrypted with key of given name
+// of Vault transit secret engine mounted at given mount.
+func NewTransitSynchronizer(client *Client, mount, key string, secret syncApi.Synchronizer) syncApi.Synchronizer {
+	return &transitSynchronizer{Synchronizer: secret, client: client, mount: strings.Trim(mount, "/"), key: key}
+}
@@ -0,0 +1,299 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	syncApi "github.com/kubernetes/dashboard/src/app/backend/sync/api"
+)
+
+// Fake Vault serving Kubernetes login, a single KV version 2 secret and transit encryption, which only prefixes
+// the plaintext.
+type fakeVault struct {
+	token   string
+	logins  int
+	version int
+	data    json.RawMessage
+	mux     sync.Mutex
+}
+
+func (self *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	body := make(map[string]json.RawMessage)
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	if r.URL.Path == "/v1/auth/kubernetes/login" {
+		self.logins++
+		writeJSON(w, http.StatusOK, map[string]interface{}{"auth": map[string]string{"client_token": self.token}})
+		return
+	}
+
+	if r.Header.Get("X-Vault-Token") != self.token {
+		writeJSON(w, http.StatusForbidden, map[string][]string{"errors": {"permission denied"}})
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/v1/secret/data/dashboard/key" && r.Method == http.MethodGet:
+		if self.data == nil {
+			writeJSON(w, http.StatusNotFound, map[string][]string{"errors": {}})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"data":     self.data,
+			"metadata": map[string]interface{}{"version": self.version, "created_time": time.Unix(0, 0).UTC()},
+		}})
+	case r.URL.Path == "/v1/secret/data/dashboard/key" && r.Method == http.MethodPost:
+		options := struct {
+			CAS *int `json:"cas"`
+		}{}
+		_ = json.Unmarshal(body["options"], &options)
+		if options.CAS != nil && *options.CAS != self.version {
+			writeJSON(w, http.StatusBadRequest, map[string][]string{
+				"errors": {"check-and-set parameter did not match the current version"}})
+			return
+		}
+
+		self.version++
+		self.data = body["data"]
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]int{"version": self.version}})
+	case r.URL.Path == "/v1/secret/metadata/dashboard/key" && r.Method == http.MethodDelete:
+		self.data = nil
+		self.version = 0
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Path == "/v1/transit/encrypt/dashboard":
+		var plaintext string
+		_ = json.Unmarshal(body["plaintext"], &plaintext)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]string{"ciphertext": "vault:v1:" + plaintext}})
+	case r.URL.Path == "/v1/transit/decrypt/dashboard":
+		var ciphertext string
+		_ = json.Unmarshal(body["ciphertext"], &ciphertext)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]string{"plaintext": strings.TrimPrefix(ciphertext, "vault:v1:")}})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string][]string{"errors": {}})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func newTestClient(t *testing.T, vault *fakeVault) (*Client, func()) {
+	dir, err := ioutil.TempDir("", "vault")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwtFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(jwtFile, []byte("jwt"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(vault)
+	client := NewClient(Config{Address: server.URL, Role: "kubernetes-dashboard", JWTFile: jwtFile})
+	return client, func() {
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func newTestSecret(value string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "key-holder", Namespace: "default"},
+		Data:       map[string][]byte{"priv": []byte(value)},
+	}
+}
+
+func TestClient_Relogin(t *testing.T) {
+	vault := &fakeVault{token: "first"}
+	client, cleanup := newTestClient(t, vault)
+	defer cleanup()
+
+	if err := client.Do(http.MethodGet, "secret/data/dashboard/key", nil, nil); !IsNotFound(err) {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+
+	vault.token = "second"
+	if err := client.Do(http.MethodGet, "secret/data/dashboard/key", nil, nil); !IsNotFound(err) {
+		t.Fatalf("Expected not found error after token expired, got %v", err)
+	}
+
+	if vault.logins != 2 {
+		t.Fatalf("Expected client to log in 2 times, got %d", vault.logins)
+	}
+}
+
+func TestKVSynchronizer(t *testing.T) {
+	client, cleanup := newTestClient(t, &fakeVault{token: "token"})
+	defer cleanup()
+
+	synchronizer := NewKVSynchronizer(client, "secret", "dashboard/key", "default", "key-holder")
+	if obj := synchronizer.Get(); obj != nil {
+		t.Fatalf("Expected no secret before it is created, got %v", obj)
+	}
+
+	if err := synchronizer.Create(newTestSecret("first")); err != nil {
+		t.Fatalf("Create failed: %s", err.Error())
+	}
+
+	if err := synchronizer.Create(newTestSecret("second")); !k8serrors.IsAlreadyExists(err) {
+		t.Fatalf("Expected already exists error, got %v", err)
+	}
+
+	secret := synchronizer.Get().(*v1.Secret)
+	if string(secret.Data["priv"]) != "first" || secret.Name != "key-holder" || secret.ResourceVersion != "1" {
+		t.Fatalf("Unexpected secret: %v", secret)
+	}
+
+	updated := secret.DeepCopy()
+	updated.Data["priv"] = []byte("second")
+	if err := synchronizer.Update(updated); err != nil {
+		t.Fatalf("Update failed: %s", err.Error())
+	}
+
+	stale := secret.DeepCopy()
+	stale.Data["priv"] = []byte("third")
+	if err := synchronizer.Update(stale); !k8serrors.IsConflict(err) {
+		t.Fatalf("Expected conflict when updating stale secret, got %v", err)
+	}
+
+	if err := synchronizer.Update(newTestSecret("forced")); err != nil {
+		t.Fatalf("Update without resource version failed: %s", err.Error())
+	}
+
+	if value := string(synchronizer.Get().(*v1.Secret).Data["priv"]); value != "forced" {
+		t.Fatalf("Expected forced value, got %s", value)
+	}
+
+	if err := synchronizer.Delete(); err != nil {
+		t.Fatalf("Delete failed: %s", err.Error())
+	}
+
+	if obj := synchronizer.Get(); obj != nil {
+		t.Fatalf("Expected no secret after it is deleted, got %v", obj)
+	}
+}
+
+func TestKVSynchronizer_Events(t *testing.T) {
+	client, cleanup := newTestClient(t, &fakeVault{token: "token"})
+	defer cleanup()
+
+	synchronizer := NewKVSynchronizer(client, "secret", "dashboard/key", "default", "key-holder").(*kvSynchronizer)
+	other := NewKVSynchronizer(client, "secret", "dashboard/key", "default", "key-holder")
+
+	events := make([]watch.EventType, 0)
+	for _, event := range []watch.EventType{watch.Added, watch.Modified, watch.Deleted} {
+		event := event
+		synchronizer.RegisterActionHandler(func(runtime.Object) { events = append(events, event) }, event)
+	}
+
+	_ = other.Create(newTestSecret("first"))
+	synchronizer.sync()
+	_ = other.Update(newTestSecret("second"))
+	synchronizer.sync()
+	synchronizer.sync()
+	_ = other.Delete()
+	synchronizer.sync()
+
+	expected := []watch.EventType{watch.Added, watch.Modified, watch.Deleted}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+}
+
+// Fake synchronizer holding secret in memory.
+type fakeSecretSynchronizer struct {
+	syncApi.Synchronizer
+	secret *v1.Secret
+}
+
+func (self *fakeSecretSynchronizer) Create(obj runtime.Object) error {
+	self.secret = obj.(*v1.Secret)
+	return nil
+}
+
+func (self *fakeSecretSynchronizer) Update(obj runtime.Object) error {
+	return self.Create(obj)
+}
+
+func (self *fakeSecretSynchronizer) Get() runtime.Object {
+	if self.secret == nil {
+		return nil
+	}
+	return self.secret
+}
+
+func (self *fakeSecretSynchronizer) Name() string {
+	return "fake"
+}
+
+func TestTransitSynchronizer(t *testing.T) {
+	vault := &fakeVault{token: "token"}
+	client, cleanup := newTestClient(t, vault)
+	defer cleanup()
+
+	secret := &fakeSecretSynchronizer{}
+	synchronizer := NewTransitSynchronizer(client, "transit", "dashboard", secret)
+
+	if err := synchronizer.Create(newTestSecret("key")); err != nil {
+		t.Fatalf("Create failed: %s", err.Error())
+	}
+
+	expected := "vault:v1:" + base64.StdEncoding.EncodeToString([]byte("key"))
+	if stored := string(secret.secret.Data["priv"]); stored != expected {
+		t.Fatalf("Expected stored value to be encrypted as %s, got %s", expected, stored)
+	}
+
+	if value := string(synchronizer.Get().(*v1.Secret).Data["priv"]); value != "key" {
+		t.Fatalf("Expected decrypted value key, got %s", value)
+	}
+
+	secret.secret = newTestSecret("plaintext")
+	if value := string(synchronizer.Get().(*v1.Secret).Data["priv"]); value != "plaintext" {
+		t.Fatalf("Expected plaintext value to be passed through, got %s", value)
+	}
+
+	secret.secret.Data["priv"] = []byte(expected)
+	unavailable := NewTransitSynchronizer(NewClient(Config{Address: "http://127.0.0.1:1", TokenFile: "missing"}),
+		"transit", "dashboard", secret)
+	if obj := unavailable.Get(); obj != nil {
+		t.Fatalf("Expected no secret when it can not be decrypted, got %v", obj)
+	}
+}
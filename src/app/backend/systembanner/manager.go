@@ -15,17 +15,20 @@
 package systembanner
 
 import (
+	"sync"
+
 	"github.com/kubernetes/dashboard/src/app/backend/systembanner/api"
 )
 
 // SystemBannerManager is a structure containing all system banner manager members.
 type SystemBannerManager struct {
 	systemBanner api.SystemBanner
+	mux          sync.RWMutex
 }
 
 // NewSystemBannerManager creates new settings manager.
-func NewSystemBannerManager(message, severity string) SystemBannerManager {
-	return SystemBannerManager{
+func NewSystemBannerManager(message, severity string) *SystemBannerManager {
+	return &SystemBannerManager{
 		systemBanner: api.SystemBanner{
 			Message:  message,
 			Severity: api.GetSeverity(severity),
@@ -35,5 +38,17 @@ func NewSystemBannerManager(message, severity string) SystemBannerManager {
 
 // Get implements SystemBannerManager interface. Check it for more information.
 func (sbm *SystemBannerManager) Get() api.SystemBanner {
+	sbm.mux.RLock()
+	defer sbm.mux.RUnlock()
 	return sbm.systemBanner
 }
+
+// Set replaces the system banner, i.e. when configuration is reloaded.
+func (sbm *SystemBannerManager) Set(message, severity string) {
+	sbm.mux.Lock()
+	defer sbm.mux.Unlock()
+	sbm.systemBanner = api.SystemBanner{
+		Message:  message,
+		Severity: api.GetSeverity(severity),
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemstatus
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/integration"
+)
+
+// Handler manages endpoints related to dashboard self-monitoring.
+type Handler struct {
+	cManager clientapi.ClientManager
+	iManager integration.IntegrationManager
+	sessions SessionCounter
+}
+
+// Install creates new endpoints for dashboard self-monitoring.
+func (h *Handler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/systemstatus").
+			To(h.handleGetSystemStatus).
+			Writes(SystemStatus{}))
+}
+
+// NewSystemStatusHandler creates systemstatus.Handler.
+func NewSystemStatusHandler(cManager clientapi.ClientManager, iManager integration.IntegrationManager,
+	sessions SessionCounter) *Handler {
+	return &Handler{cManager: cManager, iManager: iManager, sessions: sessions}
+}
+
+// handleGetSystemStatus returns status of Dashboard internals, that is read with Dashboard privileges. Skip login
+// would let anyone use these privileges, so only authenticated cluster administrators can see it.
+func (h *Handler) handleGetSystemStatus(request *restful.Request, response *restful.Response) {
+	if _, err := h.cManager.ClientCmdConfig(request); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if !h.cManager.CanI(request, clientapi.ToClusterAdminAccessReview()) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			"only cluster administrators can see Dashboard system status"))
+		return
+	}
+
+	result := GetSystemStatus(h.cManager.InsecureClient(), h.iManager, h.sessions)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
@@ -0,0 +1,171 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemstatus
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	"github.com/kubernetes/dashboard/src/app/backend/integration"
+	integrationapi "github.com/kubernetes/dashboard/src/app/backend/integration/api"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+	"github.com/kubernetes/dashboard/src/app/backend/sync"
+)
+
+// Sources of dashboard settings.
+const (
+	SettingsSourceConfigMap = "configmap"
+	SettingsSourceDefault   = "default"
+)
+
+// startTime is the time when dashboard process was started.
+var startTime = v1.Now()
+
+// SessionCounter returns number of open websocket sessions.
+type SessionCounter interface {
+	Count() int
+}
+
+// EncryptionKeyStatus describes key used by token manager to encrypt tokens.
+type EncryptionKeyStatus struct {
+	// Synced is true when the key is stored in the key holder secret and shared between replicas.
+	Synced bool `json:"synced"`
+	// Created is the creation time of the key holder secret.
+	Created    *v1.Time `json:"created,omitempty"`
+	AgeSeconds int64    `json:"ageSeconds"`
+}
+
+// IntegrationStatus is the state of a single integration.
+type IntegrationStatus struct {
+	ID    integrationapi.IntegrationID     `json:"id"`
+	State *integrationapi.IntegrationState `json:"state"`
+}
+
+// RuntimeStatus contains memory and goroutine stats of dashboard process.
+type RuntimeStatus struct {
+	GoVersion  string `json:"goVersion"`
+	Goroutines int    `json:"goroutines"`
+	HeapAlloc  uint64 `json:"heapAlloc"`
+	HeapSys    uint64 `json:"heapSys"`
+	Sys        uint64 `json:"sys"`
+	NumGC      uint32 `json:"numGC"`
+}
+
+// SystemStatus describes health of the dashboard itself.
+type SystemStatus struct {
+	StartTime v1.Time `json:"startTime"`
+	// Synchronizers keep local copies of objects shared between dashboard replicas.
+	Synchronizers    []sync.SynchronizerStatus `json:"synchronizers"`
+	TerminalSessions int                       `json:"terminalSessions"`
+	EncryptionKey    EncryptionKeyStatus       `json:"encryptionKey"`
+	SettingsSource   string                    `json:"settingsSource"`
+	Integrations     []IntegrationStatus       `json:"integrations"`
+	Runtime          RuntimeStatus             `json:"runtime"`
+
+	// List of non-critical errors, that occurred during status retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetSystemStatus returns status of the dashboard. Client is used to read the dashboard's own objects, so
+// it should be the dashboard's insecure client. All errors are non-critical, as the status should be
+// available even if some of its parts are not.
+func GetSystemStatus(client kubernetes.Interface, iManager integration.IntegrationManager,
+	sessions SessionCounter) *SystemStatus {
+	nonCriticalErrors := make([]error, 0)
+	keyStatus, err := getEncryptionKeyStatus(client, time.Now())
+	if err != nil {
+		nonCriticalErrors = append(nonCriticalErrors, errors.LocalizeError(err))
+	}
+
+	settingsSource, err := getSettingsSource(client)
+	if err != nil {
+		nonCriticalErrors = append(nonCriticalErrors, errors.LocalizeError(err))
+	}
+
+	return &SystemStatus{
+		StartTime:        startTime,
+		Synchronizers:    sync.Overwatch.Status(),
+		TerminalSessions: sessions.Count(),
+		EncryptionKey:    keyStatus,
+		SettingsSource:   settingsSource,
+		Integrations:     getIntegrationStatuses(iManager),
+		Runtime:          getRuntimeStatus(),
+		Errors:           nonCriticalErrors,
+	}
+}
+
+func getEncryptionKeyStatus(client kubernetes.Interface, now time.Time) (EncryptionKeyStatus, error) {
+	secret, err := client.CoreV1().Secrets(args.Holder.GetNamespace()).Get(context.TODO(),
+		authApi.EncryptionKeyHolderName, v1.GetOptions{})
+	if errors.IsNotFoundError(err) {
+		return EncryptionKeyStatus{}, nil
+	}
+	if err != nil {
+		return EncryptionKeyStatus{}, err
+	}
+
+	created := secret.CreationTimestamp
+	return EncryptionKeyStatus{
+		Synced:     true,
+		Created:    &created,
+		AgeSeconds: int64(now.Sub(created.Time) / time.Second),
+	}, nil
+}
+
+// getSettingsSource returns where settings are read from. Default settings are used when settings config
+// map does not exist.
+func getSettingsSource(client kubernetes.Interface) (string, error) {
+	_, err := client.CoreV1().ConfigMaps(args.Holder.GetNamespace()).Get(context.TODO(),
+		settingsApi.SettingsConfigMapName, v1.GetOptions{})
+	if errors.IsNotFoundError(err) {
+		return SettingsSourceDefault, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return SettingsSourceConfigMap, nil
+}
+
+func getIntegrationStatuses(iManager integration.IntegrationManager) []IntegrationStatus {
+	result := make([]IntegrationStatus, 0)
+	for _, i := range iManager.List() {
+		state, err := iManager.GetState(i.ID())
+		if err != nil {
+			continue
+		}
+		result = append(result, IntegrationStatus{ID: i.ID(), State: state})
+	}
+	return result
+}
+
+func getRuntimeStatus() RuntimeStatus {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return RuntimeStatus{
+		GoVersion:  runtime.Version(),
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  stats.HeapAlloc,
+		HeapSys:    stats.HeapSys,
+		Sys:        stats.Sys,
+		NumGC:      stats.NumGC,
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemstatus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubernetes/dashboard/src/app/backend/args"
+	authApi "github.com/kubernetes/dashboard/src/app/backend/auth/api"
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+func TestGetEncryptionKeyStatus(t *testing.T) {
+	args.GetHolderBuilder().SetNamespace("kubernetes-dashboard")
+	now := time.Now()
+	created := metaV1.NewTime(now.Add(-time.Hour))
+
+	cases := []struct {
+		info     string
+		client   *fake.Clientset
+		expected EncryptionKeyStatus
+	}{
+		{
+			"key holder secret exists",
+			fake.NewSimpleClientset(&v1.Secret{ObjectMeta: metaV1.ObjectMeta{
+				Name: authApi.EncryptionKeyHolderName, Namespace: "kubernetes-dashboard", CreationTimestamp: created}}),
+			EncryptionKeyStatus{Synced: true, Created: &created, AgeSeconds: 3600},
+		},
+		{
+			"key holder secret does not exist",
+			fake.NewSimpleClientset(),
+			EncryptionKeyStatus{},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := getEncryptionKeyStatus(c.client, now)
+		if err != nil {
+			t.Errorf("Test Case: %s. Unexpected error: %v", c.info, err)
+		}
+		if actual.Synced != c.expected.Synced || actual.AgeSeconds != c.expected.AgeSeconds {
+			t.Errorf("Test Case: %s. Expected %#v, but got %#v", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestGetSettingsSource(t *testing.T) {
+	args.GetHolderBuilder().SetNamespace("kubernetes-dashboard")
+	cases := []struct {
+		info     string
+		client   *fake.Clientset
+		expected string
+	}{
+		{
+			"settings config map exists",
+			fake.NewSimpleClientset(&v1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{
+				Name: settingsApi.SettingsConfigMapName, Namespace: "kubernetes-dashboard"}}),
+			SettingsSourceConfigMap,
+		},
+		{
+			"settings config map does not exist",
+			fake.NewSimpleClientset(),
+			SettingsSourceDefault,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := getSettingsSource(c.client)
+		if err != nil {
+			t.Errorf("Test Case: %s. Unexpected error: %v", c.info, err)
+		}
+		if actual != c.expected {
+			t.Errorf("Test Case: %s. Expected %s, but got %s", c.info, c.expected, actual)
+		}
+	}
+}
+
+func TestHandler_Install(t *testing.T) {
+	ws := new(restful.WebService)
+	NewSystemStatusHandler(nil, nil, nil).Install(ws)
+	if len(ws.Routes()) == 0 {
+		t.Error("Failed to install routes.")
+	}
+}
+
+func TestHandleGetSystemStatusUnauthenticated(t *testing.T) {
+	h := NewSystemStatusHandler(client.NewClientManager("", "http://localhost:8080"), nil, nil)
+	httpRequest, _ := http.NewRequest(http.MethodGet, "/api/v1/systemstatus", nil)
+	recorder := httptest.NewRecorder()
+
+	h.handleGetSystemStatus(restful.NewRequest(httpRequest), restful.NewResponse(recorder))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, but got %d", http.StatusUnauthorized, recorder.Code)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// MsgNamespaceNotVisible is returned for requests to namespaces outside of the scope of the user.
+const MsgNamespaceNotVisible = "namespace %s is not visible to tenants of %s"
+
+// namespacesTTL is a time for which the list of existing namespaces is remembered.
+const namespacesTTL = 30 * time.Second
+
+// Manager restricts namespaces visible to users to namespaces of their tenants. Tenants are read from settings
+// with the service account of Dashboard, so that they apply regardless of permissions of the users.
+type Manager struct {
+	cManager clientapi.ClientManager
+	sManager settingsApi.SettingsManager
+	subjects *subjectResolver
+	now      func() time.Time
+
+	namespaces        []string
+	namespacesExpires time.Time
+	mux               sync.Mutex
+}
+
+// NewTenancyManager creates tenancy.Manager.
+func NewTenancyManager(cManager clientapi.ClientManager, sManager settingsApi.SettingsManager) *Manager {
+	return &Manager{cManager: cManager, sManager: sManager, subjects: newSubjectResolver(), now: time.Now}
+}
+
+// Subject returns the user that made given request. Requests without valid credentials are anonymous.
+func (m *Manager) Subject(request *restful.Request) (Subject, error) {
+	cmdConfig, err := m.cManager.ClientCmdConfig(request)
+	if err != nil {
+		return anonymous, nil
+	}
+
+	cfg, err := cmdConfig.ClientConfig()
+	if err != nil {
+		return anonymous, nil
+	}
+
+	return m.subjects.resolve(m.cManager.InsecureClient(), cfg)
+}
+
+// Scope returns namespaces visible to the user that made given request.
+func (m *Manager) Scope(request *restful.Request) (*Scope, error) {
+	subject, err := m.Subject(request)
+	if err != nil {
+		return nil, err
+	}
+
+	client := m.cManager.InsecureClient()
+	scope := NewScope(subject, m.sManager.GetTenants(client))
+	if scope.IsUnrestricted() {
+		return scope, nil
+	}
+
+	namespaces, err := m.getNamespaces(client)
+	if err != nil {
+		return nil, err
+	}
+
+	scope.resolve(namespaces)
+	return scope, nil
+}
+
+// Filter rejects requests to namespaces outside of the scope of the user. Scope is kept in the request, so that
+// lists of all namespaces can be limited to visible ones with GetScope.
+func (m *Manager) Filter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	scope, err := m.Scope(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if scope.IsUnrestricted() {
+		chain.ProcessFilter(request, response)
+		return
+	}
+
+	for _, namespace := range getRequestedNamespaces(request) {
+		if !scope.Allows(namespace) {
+			errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+				fmt.Sprintf(MsgNamespaceNotVisible, namespace, scope.Subject.User)))
+			return
+		}
+	}
+
+	request.SetAttribute(scopeAttribute, scope)
+	chain.ProcessFilter(request, response)
+}
+
+// getNamespaces returns names of all existing namespaces.
+func (m *Manager) getNamespaces(client kubernetes.Interface) ([]string, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	now := m.now()
+	if m.namespaces != nil && now.Before(m.namespacesExpires) {
+		return m.namespaces, nil
+	}
+
+	list, err := client.CoreV1().Namespaces().List(context.TODO(), api.ListEverything)
+	if err != nil {
+		return nil, err
+	}
+
+	m.namespaces = make([]string, 0, len(list.Items))
+	for _, namespace := range list.Items {
+		m.namespaces = append(m.namespaces, namespace.Name)
+	}
+	m.namespacesExpires = now.Add(namespacesTTL)
+	return m.namespaces, nil
+}
+
+// getRequestedNamespaces returns namespaces given by path and query parameters of the request. Namespace path
+// parameter can be a comma separated list.
+func getRequestedNamespaces(request *restful.Request) []string {
+	namespaces := make([]string, 0)
+	for _, namespace := range strings.Split(request.PathParameter("namespace"), ",") {
+		if namespace = strings.TrimSpace(namespace); len(namespace) > 0 {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	if namespace := request.QueryParameter("namespace"); len(namespace) > 0 {
+		namespaces = append(namespaces, namespace)
+	}
+
+	if strings.Contains(request.SelectedRoutePath(), "/namespace/{name}") {
+		namespaces = append(namespaces, request.PathParameter("name"))
+	}
+	return namespaces
+}
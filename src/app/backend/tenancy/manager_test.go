@@ -0,0 +1,138 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	clientapi "github.com/kubernetes/dashboard/src/app/backend/client/api"
+	"github.com/kubernetes/dashboard/src/app/backend/errors"
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// fakeClientManager impersonates users given by 'Impersonate-User' header. Requests without it are anonymous.
+type fakeClientManager struct {
+	clientapi.ClientManager
+	client kubernetes.Interface
+}
+
+func (self *fakeClientManager) InsecureClient() kubernetes.Interface {
+	return self.client
+}
+
+func (self *fakeClientManager) ClientCmdConfig(req *restful.Request) (clientcmd.ClientConfig, error) {
+	user := req.HeaderParameter("Impersonate-User")
+	if len(user) == 0 {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters["cluster"] = &clientcmdapi.Cluster{Server: "https://localhost"}
+	config.AuthInfos["user"] = &clientcmdapi.AuthInfo{Token: "token", Impersonate: user}
+	config.Contexts["context"] = &clientcmdapi.Context{Cluster: "cluster", AuthInfo: "user"}
+	config.CurrentContext = "context"
+	return clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}), nil
+}
+
+type fakeSettingsManager struct {
+	settingsApi.SettingsManager
+	tenants []settingsApi.Tenant
+}
+
+func (self *fakeSettingsManager) GetTenants(client kubernetes.Interface) []settingsApi.Tenant {
+	return self.tenants
+}
+
+func TestManager_Filter(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a-dev"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+	)
+	sManager := &fakeSettingsManager{tenants: []settingsApi.Tenant{
+		{Name: "team-a", Users: []string{"alice"}, Namespaces: []string{"team-a*"}},
+		{Name: "admins", Users: []string{"admin"}, Namespaces: []string{settingsApi.TenantWildcard}},
+	}}
+	manager := NewTenancyManager(&fakeClientManager{client: client}, sManager)
+
+	visible := func(request *restful.Request, response *restful.Response) {
+		scope := GetScope(request)
+		if scope == nil {
+			response.WriteHeaderAndEntity(http.StatusOK, "all")
+			return
+		}
+		response.WriteHeaderAndEntity(http.StatusOK, strings.Join(scope.Namespaces, ","))
+	}
+
+	ws := new(restful.WebService)
+	ws.Path("/api/v1").Produces(restful.MIME_JSON)
+	ws.Filter(manager.Filter)
+	ws.Route(ws.GET("/pod").To(visible))
+	ws.Route(ws.GET("/pod/{namespace}").To(visible))
+	ws.Route(ws.GET("/namespace/{name}").To(visible))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	cases := []struct {
+		info     string
+		user     string
+		path     string
+		status   int
+		expected string
+	}{
+		{"tenant namespaces", "alice", "/api/v1/pod", http.StatusOK, `"team-a,team-a-dev"`},
+		{"visible namespace", "alice", "/api/v1/pod/team-a-dev", http.StatusOK, `"team-a,team-a-dev"`},
+		{"hidden namespace", "alice", "/api/v1/pod/team-b", http.StatusForbidden,
+			"namespace team-b is not visible to tenants of alice"},
+		{"hidden namespace in list", "alice", "/api/v1/pod/team-a,team-b", http.StatusForbidden,
+			"namespace team-b is not visible"},
+		{"hidden namespace detail", "alice", "/api/v1/namespace/team-b", http.StatusForbidden,
+			"namespace team-b is not visible"},
+		{"hidden namespace query", "alice", "/api/v1/pod?namespace=team-b", http.StatusForbidden,
+			"namespace team-b is not visible"},
+		{"wildcard", "admin", "/api/v1/pod/team-b", http.StatusOK, `"all"`},
+		{"no tenant", "bob", "/api/v1/pod", http.StatusOK, `""`},
+		{"anonymous", "", "/api/v1/pod/team-a", http.StatusForbidden,
+			"namespace team-a is not visible to tenants of system:anonymous"},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodGet, c.path, nil)
+		if len(c.user) > 0 {
+			request.Header.Set("Impersonate-User", c.user)
+		}
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, request)
+
+		if recorder.Code != c.status {
+			t.Errorf("%s: expected status %d, got %d", c.info, c.status, recorder.Code)
+		}
+
+		if !strings.Contains(recorder.Body.String(), c.expected) {
+			t.Errorf("%s: expected body to contain %q, got %q", c.info, c.expected, recorder.Body.String())
+		}
+	}
+}
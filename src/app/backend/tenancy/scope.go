@@ -0,0 +1,89 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"path"
+	"sort"
+
+	"github.com/emicklei/go-restful"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+// scopeAttribute is a request attribute keeping scope of the request subject.
+const scopeAttribute = "tenancyScope"
+
+// Scope is a set of namespaces visible to a subject.
+type Scope struct {
+	Subject Subject `json:"subject"`
+	// Tenants are names of all tenants the subject is a member of.
+	Tenants []string `json:"tenants"`
+	// Namespaces are existing namespaces visible to the subject. They are not set for unrestricted scopes.
+	Namespaces []string `json:"namespaces"`
+
+	patterns []string
+}
+
+// NewScope creates scope of given subject with namespaces of all tenants it is a member of.
+func NewScope(subject Subject, tenants []settingsApi.Tenant) *Scope {
+	scope := &Scope{Subject: subject, Tenants: make([]string, 0), Namespaces: make([]string, 0)}
+	for _, tenant := range tenants {
+		if tenant.Includes(subject.User, subject.Groups) {
+			scope.Tenants = append(scope.Tenants, tenant.Name)
+			scope.patterns = append(scope.patterns, tenant.Namespaces...)
+		}
+	}
+	return scope
+}
+
+// IsUnrestricted returns true if all namespaces are visible in the scope.
+func (s *Scope) IsUnrestricted() bool {
+	for _, pattern := range s.patterns {
+		if pattern == settingsApi.TenantWildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows returns true if given namespace is visible in the scope.
+func (s *Scope) Allows(namespace string) bool {
+	for _, pattern := range s.patterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve sets visible namespaces from given existing ones.
+func (s *Scope) resolve(namespaces []string) {
+	s.Namespaces = make([]string, 0)
+	for _, namespace := range namespaces {
+		if s.Allows(namespace) {
+			s.Namespaces = append(s.Namespaces, namespace)
+		}
+	}
+	sort.Strings(s.Namespaces)
+}
+
+// GetScope returns scope of the request subject or nil, if the request is not restricted to any namespaces.
+func GetScope(request *restful.Request) *Scope {
+	if scope, ok := request.Attribute(scopeAttribute).(*Scope); ok {
+		return scope
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"reflect"
+	"testing"
+
+	settingsApi "github.com/kubernetes/dashboard/src/app/backend/settings/api"
+)
+
+func TestNewScope(t *testing.T) {
+	tenants := []settingsApi.Tenant{
+		{Name: "team-a", Users: []string{"alice"}, Namespaces: []string{"team-a-*"}},
+		{Name: "team-b", Groups: []string{"team-b"}, Namespaces: []string{"team-b", "shared"}},
+		{Name: "admins", Groups: []string{"admins"}, Namespaces: []string{settingsApi.TenantWildcard}},
+	}
+	existing := []string{"team-a-prod", "team-a-dev", "team-b", "shared", "kube-system"}
+
+	cases := []struct {
+		info         string
+		subject      Subject
+		tenants      []string
+		unrestricted bool
+		namespaces   []string
+	}{
+		{"user member", Subject{User: "alice"}, []string{"team-a"}, false,
+			[]string{"team-a-dev", "team-a-prod"}},
+		{"group member", Subject{User: "bob", Groups: []string{"team-b"}}, []string{"team-b"}, false,
+			[]string{"shared", "team-b"}},
+		{"multiple tenants", Subject{User: "alice", Groups: []string{"team-b"}}, []string{"team-a", "team-b"},
+			false, []string{"shared", "team-a-dev", "team-a-prod", "team-b"}},
+		{"wildcard", Subject{User: "carol", Groups: []string{"admins"}}, []string{"admins"}, true, nil},
+		{"no tenant", anonymous, []string{}, false, []string{}},
+	}
+
+	for _, c := range cases {
+		scope := NewScope(c.subject, tenants)
+		if !reflect.DeepEqual(scope.Tenants, c.tenants) {
+			t.Errorf("%s: expected tenants %v, got %v", c.info, c.tenants, scope.Tenants)
+		}
+
+		if scope.IsUnrestricted() != c.unrestricted {
+			t.Errorf("%s: expected unrestricted to be %t", c.info, c.unrestricted)
+		}
+
+		if c.unrestricted {
+			continue
+		}
+
+		scope.resolve(existing)
+		if !reflect.DeepEqual(scope.Namespaces, c.namespaces) {
+			t.Errorf("%s: expected namespaces %v, got %v", c.info, c.namespaces, scope.Namespaces)
+		}
+	}
+}
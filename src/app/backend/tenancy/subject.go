@@ -0,0 +1,135 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// AnonymousUser is a subject of requests without credentials.
+	AnonymousUser = "system:anonymous"
+	// UnauthenticatedGroup is a group of requests without credentials.
+	UnauthenticatedGroup = "system:unauthenticated"
+	// AuthenticatedGroup is a group of all users with valid credentials.
+	AuthenticatedGroup = "system:authenticated"
+
+	// subjectTTL is a time for which users of reviewed tokens are remembered.
+	subjectTTL = time.Minute
+)
+
+// Subject is a user, on behalf of which a request is made.
+type Subject struct {
+	User   string   `json:"user"`
+	Groups []string `json:"groups"`
+}
+
+// anonymous is a subject of requests that could not be authenticated.
+var anonymous = Subject{User: AnonymousUser, Groups: []string{UnauthenticatedGroup}}
+
+type reviewedSubject struct {
+	subject Subject
+	expires time.Time
+}
+
+// subjectResolver finds out subjects of client configs. Tokens are resolved with a token review and results
+// are remembered for subjectTTL, so that not every request is reviewed.
+type subjectResolver struct {
+	now     func() time.Time
+	reviews map[string]reviewedSubject
+	mux     sync.Mutex
+}
+
+func newSubjectResolver() *subjectResolver {
+	return &subjectResolver{now: time.Now, reviews: make(map[string]reviewedSubject)}
+}
+
+// resolve returns subject of given config. Impersonated user is preferred over the owner of credentials. Given
+// client is used to review tokens.
+func (r *subjectResolver) resolve(client kubernetes.Interface, cfg *rest.Config) (Subject, error) {
+	switch {
+	case len(cfg.Impersonate.UserName) > 0:
+		return Subject{User: cfg.Impersonate.UserName,
+			Groups: append(append([]string{}, cfg.Impersonate.Groups...), AuthenticatedGroup)}, nil
+	case len(cfg.Username) > 0:
+		return Subject{User: cfg.Username, Groups: []string{AuthenticatedGroup}}, nil
+	case len(cfg.BearerToken) > 0:
+		return r.review(client, cfg.BearerToken)
+	case len(cfg.CertData) > 0:
+		return certificateSubject(cfg.CertData)
+	}
+
+	return anonymous, nil
+}
+
+func (r *subjectResolver) review(client kubernetes.Interface, token string) (Subject, error) {
+	key := fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+	now := r.now()
+
+	r.mux.Lock()
+	reviewed, ok := r.reviews[key]
+	r.mux.Unlock()
+	if ok && now.Before(reviewed.expires) {
+		return reviewed.subject, nil
+	}
+
+	review, err := client.AuthenticationV1().TokenReviews().Create(context.TODO(),
+		&authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}, metav1.CreateOptions{})
+	if err != nil {
+		return anonymous, err
+	}
+
+	subject := anonymous
+	if review.Status.Authenticated && len(review.Status.User.Username) > 0 {
+		subject = Subject{User: review.Status.User.Username, Groups: review.Status.User.Groups}
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for k, v := range r.reviews {
+		if !now.Before(v.expires) {
+			delete(r.reviews, k)
+		}
+	}
+	r.reviews[key] = reviewedSubject{subject: subject, expires: now.Add(subjectTTL)}
+	return subject, nil
+}
+
+// certificateSubject returns user and groups of a client certificate, the same way as API server does.
+func certificateSubject(certData []byte) (Subject, error) {
+	block, _ := pem.Decode(certData)
+	if block == nil {
+		return anonymous, fmt.Errorf("client certificate is not PEM encoded")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return anonymous, err
+	}
+
+	return Subject{User: cert.Subject.CommonName,
+		Groups: append(append([]string{}, cert.Subject.Organization...), AuthenticatedGroup)}, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func getTestCertificate(t *testing.T, commonName string, organization []string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName, Organization: organization},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestSubjectResolver_Resolve(t *testing.T) {
+	reviews := 0
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			reviews++
+			review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+			status := authenticationv1.TokenReviewStatus{}
+			if review.Spec.Token == "valid" {
+				status = authenticationv1.TokenReviewStatus{Authenticated: true,
+					User: authenticationv1.UserInfo{Username: "alice", Groups: []string{"team-a"}}}
+			}
+			return true, &authenticationv1.TokenReview{Spec: review.Spec, Status: status}, nil
+		})
+
+	cases := []struct {
+		info     string
+		cfg      *rest.Config
+		expected Subject
+	}{
+		{"impersonation", &rest.Config{BearerToken: "valid",
+			Impersonate: rest.ImpersonationConfig{UserName: "bob", Groups: []string{"team-b"}}},
+			Subject{User: "bob", Groups: []string{"team-b", AuthenticatedGroup}}},
+		{"basic", &rest.Config{Username: "carol", Password: "secret"},
+			Subject{User: "carol", Groups: []string{AuthenticatedGroup}}},
+		{"valid token", &rest.Config{BearerToken: "valid"}, Subject{User: "alice", Groups: []string{"team-a"}}},
+		{"invalid token", &rest.Config{BearerToken: "invalid"}, anonymous},
+		{"certificate", &rest.Config{TLSClientConfig: rest.TLSClientConfig{
+			CertData: getTestCertificate(t, "dave", []string{"team-d"})}},
+			Subject{User: "dave", Groups: []string{"team-d", AuthenticatedGroup}}},
+		{"no credentials", &rest.Config{}, anonymous},
+	}
+
+	resolver := newSubjectResolver()
+	for _, c := range cases {
+		subject, err := resolver.resolve(client, c.cfg)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.info, err)
+		}
+
+		if !reflect.DeepEqual(subject, c.expected) {
+			t.Errorf("%s: expected %#v, got %#v", c.info, c.expected, subject)
+		}
+	}
+
+	if reviews != 2 {
+		t.Fatalf("expected 2 token reviews, got %d", reviews)
+	}
+
+	if _, err := resolver.resolve(client, &rest.Config{BearerToken: "valid"}); err != nil || reviews != 2 {
+		t.Errorf("expected reviewed token to be remembered, got %d reviews", reviews)
+	}
+
+	resolver.now = func() time.Time { return time.Now().Add(subjectTTL) }
+	if _, err := resolver.resolve(client, &rest.Config{BearerToken: "valid"}); err != nil || reviews != 3 {
+		t.Errorf("expected expired token to be reviewed again, got %d reviews", reviews)
+	}
+}
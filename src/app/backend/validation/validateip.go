@@ -0,0 +1,58 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+	"log"
+
+	api "k8s.io/api/core/v1"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// IPValiditySpec is a specification of IP address validation request.
+type IPValiditySpec struct {
+	// IP address entered by the user.
+	IP string `json:"ip"`
+
+	// Family the address has to belong to, IPv4 or IPv6. Address of any family is valid if it is empty.
+	Family api.IPFamily `json:"family"`
+}
+
+// IPValidity describes validity of the IP address.
+type IPValidity struct {
+	// True when the address is valid and belongs to requested family.
+	Valid bool `json:"valid"`
+	// Family of the address, if it is valid.
+	Family api.IPFamily `json:"family,omitempty"`
+	// Error reason when the address is not valid.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ValidateIP validates IP address and its family.
+func ValidateIP(spec *IPValiditySpec) *IPValidity {
+	log.Printf("Validating %s IP address of %s family", spec.IP, spec.Family)
+
+	family := common.GetIPFamily(spec.IP)
+	switch {
+	case len(family) == 0:
+		return &IPValidity{Valid: false, Reason: fmt.Sprintf("%s is not a valid IP address", spec.IP)}
+	case len(spec.Family) > 0 && spec.Family != family:
+		return &IPValidity{Valid: false, Family: family,
+			Reason: fmt.Sprintf("%s is an %s address, %s address is required", spec.IP, family, spec.Family)}
+	}
+	return &IPValidity{Valid: true, Family: family}
+}
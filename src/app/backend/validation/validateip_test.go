@@ -0,0 +1,45 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"testing"
+
+	api "k8s.io/api/core/v1"
+)
+
+func TestValidateIP(t *testing.T) {
+	cases := []struct {
+		spec           *IPValiditySpec
+		expected       bool
+		expectedFamily api.IPFamily
+	}{
+		{&IPValiditySpec{IP: "10.0.0.1"}, true, api.IPv4Protocol},
+		{&IPValiditySpec{IP: "fd00::1"}, true, api.IPv6Protocol},
+		{&IPValiditySpec{IP: "10.0.0.1", Family: api.IPv4Protocol}, true, api.IPv4Protocol},
+		{&IPValiditySpec{IP: "10.0.0.1", Family: api.IPv6Protocol}, false, api.IPv4Protocol},
+		{&IPValiditySpec{IP: "fd00::1", Family: api.IPv4Protocol}, false, api.IPv6Protocol},
+		{&IPValiditySpec{IP: "10.0.0.256"}, false, ""},
+		{&IPValiditySpec{IP: ""}, false, ""},
+	}
+
+	for _, c := range cases {
+		validity := ValidateIP(c.spec)
+		if validity.Valid != c.expected || validity.Family != c.expectedFamily {
+			t.Errorf("Expected %#v validity to be %#v of %s family, but was %#v\n",
+				c.spec, c.expected, c.expectedFamily, validity)
+		}
+	}
+}